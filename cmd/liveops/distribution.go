@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// openDistributions connects to the database and returns the distribution
+// service.
+func openDistributions(configPath string) (*channelserver.DistributionService, *sqlx.DB, error) {
+	db, err := openDB(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return channelserver.NewDistributionService(channelserver.NewDistributionRepository(db), zap.NewNop()), db, nil
+}
+
+// readDistributionFile parses a distribution definition.
+func readDistributionFile(path string) (channelserver.DistributionCampaign, error) {
+	var c channelserver.DistributionCampaign
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c, fmt.Errorf("read file: %w", err)
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("parse distribution: %w", err)
+	}
+	return c, nil
+}
+
+// formatDistributionTime renders an optional window bound.
+func formatDistributionTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func runDistributions(args []string) error {
+	fs := flag.NewFlagSet("distributions", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	_ = fs.Parse(args)
+
+	svc, db, err := openDistributions(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	list, err := svc.List()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTYPE\tNAME\tSTARTS\tDEADLINE\tLIMIT\tCOURSES\tCLAIMS\tCLAIMANTS")
+	for _, c := range list {
+		courses := strings.Join(c.Courses, ",")
+		if courses == "" {
+			courses = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%d\t%s\t%d\t%d\n", c.ID, c.Type, c.EventName,
+			formatDistributionTime(c.StartsAt), formatDistributionTime(c.Deadline), c.TimesAcceptable,
+			courses, c.Claims, c.Claimants)
+	}
+	return w.Flush()
+}
+
+func runDistributionShow(args []string) error {
+	fs := flag.NewFlagSet("distribution-show", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Distribution ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openDistributions(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	c, err := svc.Get(uint32(*id))
+	if err != nil {
+		return err
+	}
+	return printJSON(c)
+}
+
+func runDistributionCreate(args []string) error {
+	fs := flag.NewFlagSet("distribution-create", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	filePath := fs.String("file", "", "Distribution JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	c, err := readDistributionFile(*filePath)
+	if err != nil {
+		return err
+	}
+	svc, db, err := openDistributions(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	created, err := svc.Create(c)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Distribution %d created\n", created.ID)
+	return nil
+}
+
+func runDistributionUpdate(args []string) error {
+	fs := flag.NewFlagSet("distribution-update", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Distribution ID")
+	filePath := fs.String("file", "", "Distribution JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	c, err := readDistributionFile(*filePath)
+	if err != nil {
+		return err
+	}
+	svc, db, err := openDistributions(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := svc.Update(uint32(*id), c); err != nil {
+		return err
+	}
+	fmt.Printf("Distribution %d updated\n", *id)
+	return nil
+}
+
+func runDistributionExpire(args []string) error {
+	fs := flag.NewFlagSet("distribution-expire", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Distribution ID")
+	atFlag := fs.String("at", "", "Expiry time in RFC 3339 (default now)")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	at := time.Now()
+	if *atFlag != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, *atFlag); err != nil {
+			return fmt.Errorf("invalid --at: %w", err)
+		}
+	}
+	svc, db, err := openDistributions(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	c, err := svc.Expire(uint32(*id), at)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Distribution %d ends at %s\n", c.ID, formatDistributionTime(c.Deadline))
+	return nil
+}
+
+func runDistributionEligible(args []string) error {
+	fs := flag.NewFlagSet("distribution-eligible", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Distribution ID")
+	limit := fs.Int("limit", 100, "Number of characters to list")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openDistributions(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	preview, err := svc.Eligible(uint32(*id), *limit)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tHR\tGR")
+	for _, c := range preview.Characters {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%d\t%d\n", c.ID, c.Name, c.HR, c.GR)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d characters can accept distribution %d\n", preview.Count, *id)
+	return nil
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// openGachas connects to the database and returns the gacha admin service.
+func openGachas(configPath string) (*channelserver.GachaAdminService, *sqlx.DB, error) {
+	db, err := openDB(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	log := zap.NewNop()
+	return channelserver.NewGachaAdminService(channelserver.NewGachaRepository(db, log), log), db, nil
+}
+
+// readJSONFile parses a JSON file into v.
+func readJSONFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse file: %w", err)
+	}
+	return nil
+}
+
+// formatRate renders a 0-1 chance as a percentage.
+func formatRate(p float64) string {
+	return fmt.Sprintf("%.3f%%", p*100)
+}
+
+func runGachas(args []string) error {
+	fs := flag.NewFlagSet("gachas", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	_ = fs.Parse(args)
+
+	svc, db, err := openGachas(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	list, err := svc.List()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTYPE\tNAME\tMIN HR\tMIN GR\tRECOMMENDED\tHIDDEN")
+	for _, g := range list {
+		_, _ = fmt.Fprintf(w, "%d\t%d\t%s\t%d\t%d\t%v\t%v\n", g.ID, g.GachaType, g.Name,
+			g.MinHR, g.MinGR, g.Recommended, g.Hidden)
+	}
+	return w.Flush()
+}
+
+func runGachaShow(args []string) error {
+	fs := flag.NewFlagSet("gacha-show", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Gacha ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openGachas(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	c, err := svc.Get(uint32(*id))
+	if err != nil {
+		return err
+	}
+	return printJSON(c)
+}
+
+func runGachaCreate(args []string) error {
+	fs := flag.NewFlagSet("gacha-create", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	filePath := fs.String("file", "", "Gacha JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	var g channelserver.Gacha
+	if err := readJSONFile(*filePath, &g); err != nil {
+		return err
+	}
+	svc, db, err := openGachas(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	created, err := svc.Create(g)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Gacha %d created\n", created.ID)
+	return nil
+}
+
+func runGachaUpdate(args []string) error {
+	fs := flag.NewFlagSet("gacha-update", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Gacha ID")
+	filePath := fs.String("file", "", "Gacha JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	var g channelserver.Gacha
+	if err := readJSONFile(*filePath, &g); err != nil {
+		return err
+	}
+	svc, db, err := openGachas(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := svc.Update(uint32(*id), g); err != nil {
+		return err
+	}
+	fmt.Printf("Gacha %d updated\n", *id)
+	return nil
+}
+
+func runGachaDelete(args []string) error {
+	fs := flag.NewFlagSet("gacha-delete", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Gacha ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openGachas(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := svc.Delete(uint32(*id)); err != nil {
+		return err
+	}
+	fmt.Printf("Gacha %d deleted\n", *id)
+	return nil
+}
+
+func runGachaEntryAdd(args []string) error {
+	fs := flag.NewFlagSet("gacha-entry-add", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Gacha ID")
+	filePath := fs.String("file", "", "Entry JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	var e channelserver.GachaCatalogueEntry
+	if err := readJSONFile(*filePath, &e); err != nil {
+		return err
+	}
+	svc, db, err := openGachas(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	created, err := svc.CreateEntry(uint32(*id), e)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Entry %d added to gacha %d\n", created.ID, *id)
+	return nil
+}
+
+func runGachaEntryUpdate(args []string) error {
+	fs := flag.NewFlagSet("gacha-entry-update", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Gacha ID")
+	entryID := fs.Uint("entry", 0, "Entry ID")
+	filePath := fs.String("file", "", "Entry JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *id == 0 || *entryID == 0 {
+		return errors.New("--id and --entry are required")
+	}
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	var e channelserver.GachaCatalogueEntry
+	if err := readJSONFile(*filePath, &e); err != nil {
+		return err
+	}
+	svc, db, err := openGachas(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := svc.UpdateEntry(uint32(*id), uint32(*entryID), e); err != nil {
+		return err
+	}
+	fmt.Printf("Entry %d of gacha %d updated\n", *entryID, *id)
+	return nil
+}
+
+func runGachaEntryDelete(args []string) error {
+	fs := flag.NewFlagSet("gacha-entry-delete", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Gacha ID")
+	entryID := fs.Uint("entry", 0, "Entry ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 || *entryID == 0 {
+		return errors.New("--id and --entry are required")
+	}
+	svc, db, err := openGachas(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := svc.DeleteEntry(uint32(*id), uint32(*entryID)); err != nil {
+		return err
+	}
+	fmt.Printf("Entry %d of gacha %d deleted\n", *entryID, *id)
+	return nil
+}
+
+func runGachaRates(args []string) error {
+	fs := flag.NewFlagSet("gacha-rates", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Gacha ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openGachas(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	rates, err := svc.Rates(uint32(*id))
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ENTRY\tRARITY\tWEIGHT\tCHANCE\tDISPLAYED\tITEMS")
+	for _, e := range rates.Entries {
+		displayed := "-"
+		if e.DisplayedRate != nil {
+			displayed = formatRate(*e.DisplayedRate)
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%d\t%g\t%s\t%s\t%d\n", e.EntryID, e.Rarity, e.Weight,
+			formatRate(e.Probability), displayed, len(e.Items))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, warning := range rates.Warnings {
+		fmt.Printf("warning: %s\n", warning)
+	}
+	return nil
+}
+
+func runGachaSimulate(args []string) error {
+	fs := flag.NewFlagSet("gacha-simulate", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Gacha ID")
+	rollType := fs.Uint("roll", 0, "Roll entry type, or the step for step-up gachas")
+	plays := fs.Int("plays", 1000, "Number of plays")
+	seed := fs.Int64("seed", 1, "Random seed")
+	asJSON := fs.Bool("json", false, "Print the full result as JSON")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openGachas(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	sim, err := svc.Simulate(uint32(*id), channelserver.GachaSimulationRequest{
+		RollType: uint8(*rollType),
+		Plays:    *plays,
+		Seed:     *seed,
+	})
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(sim)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ENTRY\tRARITY\tHITS\tFREQUENCY")
+	for _, e := range sim.Entries {
+		_, _ = fmt.Fprintf(w, "%d\t%d\t%d\t%s\n", e.EntryID, e.Rarity, e.Hits, formatRate(e.Frequency))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d plays drew %d entries (seed %d)\n", sim.Plays, sim.Draws, sim.Seed)
+	return nil
+}
+
+func runGachaHistory(args []string) error {
+	fs := flag.NewFlagSet("gacha-history", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	charID := fs.Uint("char", 0, "Character ID")
+	userID := fs.Uint("user", 0, "User ID")
+	gachaID := fs.Uint("gacha", 0, "Gacha ID")
+	sinceFlag := fs.String("since", "", "Only plays at or after this RFC 3339 time")
+	untilFlag := fs.String("until", "", "Only plays before this RFC 3339 time")
+	before := fs.Uint64("before", 0, "Only plays older than this play ID")
+	limit := fs.Int("limit", 50, "Number of plays to list")
+	asJSON := fs.Bool("json", false, "Print the plays as JSON")
+	_ = fs.Parse(args)
+
+	f := channelserver.GachaPlayFilter{
+		UserID:   uint32(*userID),
+		CharID:   uint32(*charID),
+		GachaID:  uint32(*gachaID),
+		BeforeID: *before,
+		Limit:    *limit,
+	}
+	for _, p := range []struct {
+		name, value string
+		dst         **time.Time
+	}{{"since", *sinceFlag, &f.Since}, {"until", *untilFlag, &f.Until}} {
+		if p.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, p.value)
+		if err != nil {
+			return fmt.Errorf("invalid --%s: %w", p.name, err)
+		}
+		*p.dst = &t
+	}
+	db, err := openDB(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	log := zap.NewNop()
+	svc := channelserver.NewGachaHistoryService(channelserver.NewGachaRepository(db, log), log)
+
+	plays, err := svc.History(f)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(plays)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tPLAYED\tUSER\tCHAR\tGACHA\tKIND\tROLL\tCOST\tCURRENCY\tITEMS")
+	for _, p := range plays {
+		currency := p.Currency
+		if currency == "" {
+			currency = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d %s\t%s\t%d\t%d\t%s\t%d\n", p.ID, p.PlayedAt.Format(time.RFC3339),
+			p.UserID, p.CharID, p.GachaID, p.GachaName, p.Kind, p.RollType, p.Cost, currency, len(p.Items))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// openMailBroadcasts connects to the database and returns the mail broadcast
+// service. New mail popups are relayed through the postgres registry, so
+// recipients online on servers using that backend see them straight away;
+// everyone else sees the mail next time they open their mailbox.
+func openMailBroadcasts(configPath string) (*channelserver.MailBroadcastService, *sqlx.DB, error) {
+	db, err := openDB(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	svc := channelserver.NewMailBroadcastService(channelserver.NewMailBroadcastRepository(db), zap.NewNop())
+	svc.SetNotifier(channelserver.NewPostgresChannelRegistry(nil, &channelserver.PostgresRegistryConfig{
+		Logger: zap.NewNop(),
+		DB:     db,
+	}))
+	return svc, db, nil
+}
+
+// readMailBroadcastFile parses a broadcast definition.
+func readMailBroadcastFile(path string) (channelserver.MailBroadcast, error) {
+	var b channelserver.MailBroadcast
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return b, fmt.Errorf("read file: %w", err)
+	}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return b, fmt.Errorf("parse broadcast: %w", err)
+	}
+	return b, nil
+}
+
+func runMailBroadcasts(args []string) error {
+	fs := flag.NewFlagSet("mail-broadcasts", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	limit := fs.Int("limit", 20, "Number of broadcasts to list")
+	_ = fs.Parse(args)
+
+	svc, db, err := openMailBroadcasts(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	list, err := svc.List(*limit)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tKEY\tSUBJECT\tITEMS\tSENT\tCREATED\tFINISHED")
+	for _, b := range list {
+		finished := "-"
+		if b.FinishedAt != nil {
+			finished = b.FinishedAt.Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d/%d\t%s\t%s\n", b.ID, b.IdempotencyKey, b.Subject,
+			len(b.Items), b.Sent, b.Total, b.CreatedAt.Format(time.RFC3339), finished)
+	}
+	return w.Flush()
+}
+
+func runMailBroadcastShow(args []string) error {
+	fs := flag.NewFlagSet("mail-broadcast-show", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Broadcast ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openMailBroadcasts(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	b, err := svc.Get(uint32(*id))
+	if err != nil {
+		return err
+	}
+	return printJSON(b)
+}
+
+func runMailBroadcastSend(args []string) error {
+	fs := flag.NewFlagSet("mail-broadcast-send", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	filePath := fs.String("file", "", "Broadcast JSON file (required)")
+	key := fs.String("key", "", "Idempotency key, overriding the file's")
+	_ = fs.Parse(args)
+
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	b, err := readMailBroadcastFile(*filePath)
+	if err != nil {
+		return err
+	}
+	if *key != "" {
+		b.IdempotencyKey = *key
+	}
+	svc, db, err := openMailBroadcasts(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	stored, created, err := svc.Create(b)
+	if err != nil {
+		return err
+	}
+	if !created {
+		fmt.Printf("Broadcast %d already exists for key %q\n", stored.ID, stored.IdempotencyKey)
+	}
+	return sendMailBroadcast(svc, stored.ID)
+}
+
+func runMailBroadcastResume(args []string) error {
+	fs := flag.NewFlagSet("mail-broadcast-resume", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Broadcast ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openMailBroadcasts(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	return sendMailBroadcast(svc, uint32(*id))
+}
+
+// sendMailBroadcast sends a broadcast's remaining batches and reports the
+// result.
+func sendMailBroadcast(svc *channelserver.MailBroadcastService, id uint32) error {
+	b, err := svc.Send(id)
+	if err != nil {
+		return fmt.Errorf("%w (run mail-broadcast-resume --id %d to continue)", err, id)
+	}
+	fmt.Printf("Broadcast %d finished: %d of %d characters mailed\n", b.ID, b.Sent, b.Total)
+	return nil
+}
@@ -0,0 +1,280 @@
+// liveops is an admin CLI for scheduled Erupe content. It talks to the
+// database directly through the same services as the admin API, so it works
+// while the server is down.
+//
+// Usage:
+//
+//	liveops tournaments        --config config.json
+//	liveops tournament-show    --config config.json --id 3
+//	liveops tournament-create  --config config.json --file tournament.json
+//	liveops tournament-update  --config config.json --id 3 --file tournament.json
+//	liveops tournament-delete  --config config.json --id 3
+//	liveops tournament-results --config config.json --id 3 [--status flagged]
+//	liveops tournament-review  --config config.json --id 3 --result 8 --status verified
+//	liveops mail-broadcasts       --config config.json [--limit 20]
+//	liveops mail-broadcast-show   --config config.json --id 2
+//	liveops mail-broadcast-send   --config config.json --file broadcast.json [--key maint-1017]
+//	liveops mail-broadcast-resume --config config.json --id 2
+//	liveops distributions         --config config.json
+//	liveops distribution-show     --config config.json --id 5
+//	liveops distribution-create   --config config.json --file distribution.json
+//	liveops distribution-update   --config config.json --id 5 --file distribution.json
+//	liveops distribution-expire   --config config.json --id 5 [--at 2026-11-01T00:00:00Z]
+//	liveops distribution-eligible --config config.json --id 5 [--limit 100]
+//	liveops gachas             --config config.json
+//	liveops gacha-show         --config config.json --id 4
+//	liveops gacha-create       --config config.json --file gacha.json
+//	liveops gacha-update       --config config.json --id 4 --file gacha.json
+//	liveops gacha-delete       --config config.json --id 4
+//	liveops gacha-entry-add    --config config.json --id 4 --file entry.json
+//	liveops gacha-entry-update --config config.json --id 4 --entry 12 --file entry.json
+//	liveops gacha-entry-delete --config config.json --id 4 --entry 12
+//	liveops gacha-rates        --config config.json --id 4
+//	liveops gacha-simulate     --config config.json --id 4 [--roll 0] [--plays 1000] [--seed 1] [--json]
+//	liveops gacha-history      --config config.json [--char 20] [--user 2] [--gacha 4] [--since 2026-10-01T00:00:00Z] [--limit 50]
+//	liveops shop-items          --config config.json [--type 10] [--shop 2] [--retired]
+//	liveops shop-item-show      --config config.json --id 140
+//	liveops shop-item-add       --config config.json --file item.json
+//	liveops shop-item-update    --config config.json --id 140 --file item.json
+//	liveops shop-item-retire    --config config.json --id 140
+//	liveops shop-item-restore   --config config.json --id 140
+//	liveops shop-price-schedule --config config.json --id 140 --cost 50 --at 2026-11-01T00:00:00Z
+//	liveops shop-price-cancel   --config config.json --id 140 --change 3
+//	liveops shop-top-items      --config config.json [--type 10] [--shop 2] [--limit 50]
+//	liveops shop-cap-hits       --config config.json [--type 10] [--shop 2] [--char 20] [--limit 50]
+//	liveops fpoint-items        --config config.json [--retired]
+//	liveops fpoint-item-add     --config config.json --file fpoint.json
+//	liveops fpoint-item-update  --config config.json --id 12 --file fpoint.json
+//	liveops fpoint-item-retire  --config config.json --id 12
+//	liveops fpoint-item-restore --config config.json --id 12
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// dbConfig is the minimal config subset needed to connect to PostgreSQL.
+type dbConfig struct {
+	Database struct {
+		Host     string `json:"Host"`
+		Port     int    `json:"Port"`
+		User     string `json:"User"`
+		Password string `json:"Password"`
+		Database string `json:"Database"`
+	} `json:"Database"`
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+	cmd := os.Args[1]
+	args := os.Args[2:]
+
+	var err error
+	switch cmd {
+	case "tournaments":
+		err = runTournaments(args)
+	case "tournament-show":
+		err = runTournamentShow(args)
+	case "tournament-create":
+		err = runTournamentCreate(args)
+	case "tournament-update":
+		err = runTournamentUpdate(args)
+	case "tournament-delete":
+		err = runTournamentDelete(args)
+	case "tournament-results":
+		err = runTournamentResults(args)
+	case "tournament-review":
+		err = runTournamentReview(args)
+	case "mail-broadcasts":
+		err = runMailBroadcasts(args)
+	case "mail-broadcast-show":
+		err = runMailBroadcastShow(args)
+	case "mail-broadcast-send":
+		err = runMailBroadcastSend(args)
+	case "mail-broadcast-resume":
+		err = runMailBroadcastResume(args)
+	case "distributions":
+		err = runDistributions(args)
+	case "distribution-show":
+		err = runDistributionShow(args)
+	case "distribution-create":
+		err = runDistributionCreate(args)
+	case "distribution-update":
+		err = runDistributionUpdate(args)
+	case "distribution-expire":
+		err = runDistributionExpire(args)
+	case "distribution-eligible":
+		err = runDistributionEligible(args)
+	case "gachas":
+		err = runGachas(args)
+	case "gacha-show":
+		err = runGachaShow(args)
+	case "gacha-create":
+		err = runGachaCreate(args)
+	case "gacha-update":
+		err = runGachaUpdate(args)
+	case "gacha-delete":
+		err = runGachaDelete(args)
+	case "gacha-entry-add":
+		err = runGachaEntryAdd(args)
+	case "gacha-entry-update":
+		err = runGachaEntryUpdate(args)
+	case "gacha-entry-delete":
+		err = runGachaEntryDelete(args)
+	case "gacha-rates":
+		err = runGachaRates(args)
+	case "gacha-simulate":
+		err = runGachaSimulate(args)
+	case "gacha-history":
+		err = runGachaHistory(args)
+	case "shop-items":
+		err = runShopItems(args)
+	case "shop-item-show":
+		err = runShopItemShow(args)
+	case "shop-item-add":
+		err = runShopItemAdd(args)
+	case "shop-item-update":
+		err = runShopItemUpdate(args)
+	case "shop-item-retire":
+		err = runShopItemRetire(args)
+	case "shop-item-restore":
+		err = runShopItemRestore(args)
+	case "shop-price-schedule":
+		err = runShopPriceSchedule(args)
+	case "shop-price-cancel":
+		err = runShopPriceCancel(args)
+	case "shop-top-items":
+		err = runShopTopItems(args)
+	case "shop-cap-hits":
+		err = runShopCapHits(args)
+	case "fpoint-items":
+		err = runFpointItems(args)
+	case "fpoint-item-add":
+		err = runFpointItemAdd(args)
+	case "fpoint-item-update":
+		err = runFpointItemUpdate(args)
+	case "fpoint-item-retire":
+		err = runFpointItemRetire(args)
+	case "fpoint-item-restore":
+		err = runFpointItemRestore(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n", cmd)
+		printUsage()
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, `liveops — Erupe scheduled content admin tool
+
+Commands:
+  tournaments        --config config.json
+  tournament-show    --config config.json --id N
+  tournament-create  --config config.json --file tournament.json
+  tournament-update  --config config.json --id N --file tournament.json
+  tournament-delete  --config config.json --id N
+  tournament-results --config config.json --id N [--status ok|flagged|verified|rejected]
+  tournament-review  --config config.json --id N --result N --status verified|rejected
+  mail-broadcasts       --config config.json [--limit N]
+  mail-broadcast-show   --config config.json --id N
+  mail-broadcast-send   --config config.json --file broadcast.json [--key KEY]
+  mail-broadcast-resume --config config.json --id N
+  distributions         --config config.json
+  distribution-show     --config config.json --id N
+  distribution-create   --config config.json --file distribution.json
+  distribution-update   --config config.json --id N --file distribution.json
+  distribution-expire   --config config.json --id N [--at RFC3339]
+  distribution-eligible --config config.json --id N [--limit N]
+  gachas             --config config.json
+  gacha-show         --config config.json --id N
+  gacha-create       --config config.json --file gacha.json
+  gacha-update       --config config.json --id N --file gacha.json
+  gacha-delete       --config config.json --id N
+  gacha-entry-add    --config config.json --id N --file entry.json
+  gacha-entry-update --config config.json --id N --entry N --file entry.json
+  gacha-entry-delete --config config.json --id N --entry N
+  gacha-rates        --config config.json --id N
+  gacha-simulate     --config config.json --id N [--roll N] [--plays N] [--seed N] [--json]
+  gacha-history      --config config.json [--char N] [--user N] [--gacha N] [--since RFC3339] [--until RFC3339] [--before N] [--limit N] [--json]
+  shop-items          --config config.json [--type N] [--shop N] [--retired]
+  shop-item-show      --config config.json --id N
+  shop-item-add       --config config.json --file item.json
+  shop-item-update    --config config.json --id N --file item.json
+  shop-item-retire    --config config.json --id N
+  shop-item-restore   --config config.json --id N
+  shop-price-schedule --config config.json --id N --cost N --at RFC3339
+  shop-price-cancel   --config config.json --id N --change N
+  shop-top-items      --config config.json [--type N] [--shop N] [--limit N] [--json]
+  shop-cap-hits       --config config.json [--type N] [--shop N] [--char N] [--limit N] [--json]
+  fpoint-items        --config config.json [--retired]
+  fpoint-item-add     --config config.json --file fpoint.json
+  fpoint-item-update  --config config.json --id N --file fpoint.json
+  fpoint-item-retire  --config config.json --id N
+  fpoint-item-restore --config config.json --id N
+
+Tournament files use the JSON body of POST /v2/admin/tournaments (see
+docs/openapi.yaml). Phase ends left out default to the retail lengths.
+
+Broadcast files use the JSON body of POST /v2/admin/mail-broadcasts. Sending
+again with the same idempotency key resumes the existing broadcast instead of
+mailing everyone twice.
+
+Distribution files use the JSON body of POST /v2/admin/distributions. Updating
+a distribution keeps the claims already made against it.
+
+Gacha and entry files use the JSON bodies of POST /v2/admin/gachas and POST
+/v2/admin/gachas/{id}/entries. gacha-simulate never charges or rewards
+anyone; the same catalogue and seed always give the same result.
+gacha-history lists recorded rolls newest first; pass the last ID as --before
+for the next page.
+
+Shop and Frontier Point item files use the JSON bodies of POST
+/v2/admin/shop-items and POST /v2/admin/fpoint-items. Items are checked
+against what the ClientMode in config.json is sent. Retiring an item keeps
+its purchase counts, so restoring it keeps every character's cap progress.`)
+}
+
+// openDB parses config.json and returns an open database connection.
+func openDB(configPath string) (*sqlx.DB, error) {
+	var conf dbConfig
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	if err := json.Unmarshal(data, &conf); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	dsn := fmt.Sprintf(
+		"host='%s' port='%d' user='%s' password='%s' dbname='%s' sslmode=disable",
+		conf.Database.Host, conf.Database.Port,
+		conf.Database.User, conf.Database.Password,
+		conf.Database.Database,
+	)
+	db, err := sqlx.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("ping db: %w", err)
+	}
+	return db, nil
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
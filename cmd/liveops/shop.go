@@ -0,0 +1,477 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	cfg "erupe-ce/config"
+	"erupe-ce/server/channelserver"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// openShops connects to the database and returns the shop admin service,
+// checking items against the ClientMode in config.json.
+func openShops(configPath string) (*channelserver.ShopAdminService, *sqlx.DB, error) {
+	mode, err := readClientMode(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := openDB(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return channelserver.NewShopAdminService(channelserver.NewShopRepository(db), mode, zap.NewNop()), db, nil
+}
+
+// readClientMode returns the client mode config.json configures, ZZ when it
+// is not set, like the server.
+func readClientMode(configPath string) (cfg.Mode, error) {
+	var conf struct {
+		ClientMode string `json:"ClientMode"`
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return 0, fmt.Errorf("read config: %w", err)
+	}
+	if err := json.Unmarshal(data, &conf); err != nil {
+		return 0, fmt.Errorf("parse config: %w", err)
+	}
+	if conf.ClientMode == "" {
+		return cfg.ZZ, nil
+	}
+	mode, ok := cfg.ParseMode(conf.ClientMode)
+	if !ok {
+		return 0, fmt.Errorf("unknown ClientMode %q", conf.ClientMode)
+	}
+	return mode, nil
+}
+
+// shopIDFlag converts a --shop value, where -1 means every shop, to a filter.
+func shopIDFlag(v int) *uint32 {
+	if v < 0 {
+		return nil
+	}
+	id := uint32(v)
+	return &id
+}
+
+// printShopItem prints a one-line summary of a shop item change.
+func printShopItem(action string, item *channelserver.ShopCatalogueItem) {
+	fmt.Printf("Shop item %d %s (shop %d/%d, item %d, cost %d)\n",
+		item.ID, action, item.ShopType, item.ShopID, item.ItemID, item.Cost)
+}
+
+func runShopItems(args []string) error {
+	fs := flag.NewFlagSet("shop-items", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	shopType := fs.Uint("type", 0, "Shop type (default every type)")
+	shopID := fs.Int("shop", -1, "Shop ID within the type (default every shop)")
+	retired := fs.Bool("retired", false, "Include retired items")
+	_ = fs.Parse(args)
+
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	items, err := svc.List(channelserver.ShopItemFilter{
+		ShopType:       uint8(*shopType),
+		ShopID:         shopIDFlag(*shopID),
+		IncludeRetired: *retired,
+	})
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTYPE\tSHOP\tITEM\tCOST\tQTY\tMIN HR\tMIN GR\tCAP\tRETIRED")
+	for _, i := range items {
+		retiredAt := "-"
+		if i.RetiredAt != nil {
+			retiredAt = i.RetiredAt.Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%s\n", i.ID, i.ShopType, i.ShopID,
+			i.ItemID, i.Cost, i.Quantity, i.MinHR, i.MinGR, i.MaxQuantity, retiredAt)
+	}
+	return w.Flush()
+}
+
+func runShopItemShow(args []string) error {
+	fs := flag.NewFlagSet("shop-item-show", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Shop item ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	item, err := svc.Get(uint32(*id))
+	if err != nil {
+		return err
+	}
+	return printJSON(item)
+}
+
+func runShopItemAdd(args []string) error {
+	fs := flag.NewFlagSet("shop-item-add", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	filePath := fs.String("file", "", "Shop item JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	var item channelserver.ShopCatalogueItem
+	if err := readJSONFile(*filePath, &item); err != nil {
+		return err
+	}
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	created, err := svc.Create(item)
+	if err != nil {
+		return err
+	}
+	printShopItem("created", created)
+	return nil
+}
+
+func runShopItemUpdate(args []string) error {
+	fs := flag.NewFlagSet("shop-item-update", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Shop item ID")
+	filePath := fs.String("file", "", "Shop item JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	var item channelserver.ShopCatalogueItem
+	if err := readJSONFile(*filePath, &item); err != nil {
+		return err
+	}
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	updated, err := svc.Update(uint32(*id), item)
+	if err != nil {
+		return err
+	}
+	printShopItem("updated", updated)
+	return nil
+}
+
+func runShopItemRetire(args []string) error {
+	return runShopItemRetirement("shop-item-retire", args, true)
+}
+
+func runShopItemRestore(args []string) error {
+	return runShopItemRetirement("shop-item-restore", args, false)
+}
+
+// runShopItemRetirement takes a shop item off sale or puts it back.
+func runShopItemRetirement(name string, args []string, retire bool) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Shop item ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if retire {
+		item, err := svc.Retire(uint32(*id))
+		if err != nil {
+			return err
+		}
+		printShopItem("retired", item)
+		return nil
+	}
+	item, err := svc.Restore(uint32(*id))
+	if err != nil {
+		return err
+	}
+	printShopItem("restored", item)
+	return nil
+}
+
+func runShopPriceSchedule(args []string) error {
+	fs := flag.NewFlagSet("shop-price-schedule", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Shop item ID")
+	cost := fs.Uint("cost", 0, "New cost")
+	atFlag := fs.String("at", "", "When the cost takes effect, in RFC 3339 (required)")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	if *atFlag == "" {
+		return errors.New("--at is required")
+	}
+	at, err := time.Parse(time.RFC3339, *atFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --at: %w", err)
+	}
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	item, err := svc.SchedulePrice(uint32(*id), channelserver.ShopPriceChange{
+		Cost:        uint32(*cost),
+		EffectiveAt: at,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Shop item %d costs %d from %s\n", item.ID, *cost, at.Format(time.RFC3339))
+	return nil
+}
+
+func runShopPriceCancel(args []string) error {
+	fs := flag.NewFlagSet("shop-price-cancel", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Shop item ID")
+	change := fs.Uint("change", 0, "Price change ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 || *change == 0 {
+		return errors.New("--id and --change are required")
+	}
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := svc.CancelPriceChange(uint32(*id), uint32(*change), time.Now()); err != nil {
+		return err
+	}
+	fmt.Printf("Price change %d cancelled\n", *change)
+	return nil
+}
+
+func runFpointItems(args []string) error {
+	fs := flag.NewFlagSet("fpoint-items", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	retired := fs.Bool("retired", false, "Include retired items")
+	_ = fs.Parse(args)
+
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	items, err := svc.ListFpoint(*retired)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tITEM TYPE\tITEM\tQTY\tFPOINTS\tBUYABLE\tRETIRED")
+	for _, i := range items {
+		retiredAt := "-"
+		if i.RetiredAt != nil {
+			retiredAt = i.RetiredAt.Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%v\t%s\n", i.ID, i.ItemType, i.ItemID,
+			i.Quantity, i.FPoints, i.Buyable, retiredAt)
+	}
+	return w.Flush()
+}
+
+func runFpointItemAdd(args []string) error {
+	fs := flag.NewFlagSet("fpoint-item-add", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	filePath := fs.String("file", "", "Frontier Point item JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	var item channelserver.FPointExchange
+	if err := readJSONFile(*filePath, &item); err != nil {
+		return err
+	}
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	created, err := svc.CreateFpoint(item)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Frontier Point item %d created\n", created.ID)
+	return nil
+}
+
+func runFpointItemUpdate(args []string) error {
+	fs := flag.NewFlagSet("fpoint-item-update", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Frontier Point item ID")
+	filePath := fs.String("file", "", "Frontier Point item JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	var item channelserver.FPointExchange
+	if err := readJSONFile(*filePath, &item); err != nil {
+		return err
+	}
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := svc.UpdateFpoint(uint32(*id), item); err != nil {
+		return err
+	}
+	fmt.Printf("Frontier Point item %d updated\n", *id)
+	return nil
+}
+
+func runFpointItemRetire(args []string) error {
+	return runFpointItemRetirement("fpoint-item-retire", args, true)
+}
+
+func runFpointItemRestore(args []string) error {
+	return runFpointItemRetirement("fpoint-item-restore", args, false)
+}
+
+// runFpointItemRetirement takes a Frontier Point item out of the exchange or
+// puts it back.
+func runFpointItemRetirement(name string, args []string, retire bool) error {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Frontier Point item ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if retire {
+		if _, err := svc.RetireFpoint(uint32(*id)); err != nil {
+			return err
+		}
+		fmt.Printf("Frontier Point item %d retired\n", *id)
+		return nil
+	}
+	if _, err := svc.RestoreFpoint(uint32(*id)); err != nil {
+		return err
+	}
+	fmt.Printf("Frontier Point item %d restored\n", *id)
+	return nil
+}
+
+// shopReportFlags registers the flags shared by the purchase reports.
+func shopReportFlags(fs *flag.FlagSet) (configPath *string, read func() channelserver.ShopReportFilter, asJSON *bool) {
+	configPath = fs.String("config", "config.json", "Path to config.json")
+	shopType := fs.Uint("type", 0, "Shop type (default every type)")
+	shopID := fs.Int("shop", -1, "Shop ID within the type (default every shop)")
+	limit := fs.Int("limit", 50, "Number of rows to list")
+	asJSON = fs.Bool("json", false, "Print the report as JSON")
+	read = func() channelserver.ShopReportFilter {
+		return channelserver.ShopReportFilter{ShopType: uint8(*shopType), ShopID: shopIDFlag(*shopID), Limit: *limit}
+	}
+	return configPath, read, asJSON
+}
+
+func runShopTopItems(args []string) error {
+	fs := flag.NewFlagSet("shop-top-items", flag.ExitOnError)
+	configPath, readFilter, asJSON := shopReportFlags(fs)
+	_ = fs.Parse(args)
+
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	sales, err := svc.TopItems(readFilter())
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(sales)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tTYPE\tSHOP\tITEM\tCOST\tBOUGHT\tBUYERS\tCAP\tAT CAP\tRETIRED")
+	for _, s := range sales {
+		_, _ = fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%v\n", s.ShopItemID, s.ShopType, s.ShopID,
+			s.ItemID, s.Cost, s.Bought, s.Buyers, s.MaxQuantity, s.CappedBuyers, s.Retired)
+	}
+	return w.Flush()
+}
+
+func runShopCapHits(args []string) error {
+	fs := flag.NewFlagSet("shop-cap-hits", flag.ExitOnError)
+	configPath, readFilter, asJSON := shopReportFlags(fs)
+	charID := fs.Uint("char", 0, "Character ID")
+	_ = fs.Parse(args)
+
+	svc, db, err := openShops(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	f := readFilter()
+	f.CharID = uint32(*charID)
+	hits, err := svc.CapHits(f)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(hits)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SHOP ITEM\tTYPE\tSHOP\tITEM\tCAP\tCHAR\tNAME\tBOUGHT")
+	for _, h := range hits {
+		_, _ = fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%d\t%s\t%d\n", h.ShopItemID, h.ShopType, h.ShopID,
+			h.ItemID, h.MaxQuantity, h.CharID, h.CharName, h.Bought)
+	}
+	return w.Flush()
+}
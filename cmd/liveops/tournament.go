@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// openTournaments connects to the database and returns the tournament
+// service.
+func openTournaments(configPath string) (*channelserver.TournamentService, *sqlx.DB, error) {
+	db, err := openDB(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return channelserver.NewTournamentService(channelserver.NewTournamentRepository(db), zap.NewNop()), db, nil
+}
+
+// readTournamentFile parses a tournament definition.
+func readTournamentFile(path string) (channelserver.TournamentDetail, error) {
+	var detail channelserver.TournamentDetail
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return detail, fmt.Errorf("read file: %w", err)
+	}
+	if err := json.Unmarshal(data, &detail); err != nil {
+		return detail, fmt.Errorf("parse tournament: %w", err)
+	}
+	return detail, nil
+}
+
+func formatUnix(t int64) string {
+	return time.Unix(t, 0).Format(time.RFC3339)
+}
+
+func runTournaments(args []string) error {
+	fs := flag.NewFlagSet("tournaments", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	_ = fs.Parse(args)
+
+	svc, db, err := openTournaments(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	list, err := svc.List()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tSTART\tENTRY END\tRANKING END\tREWARD END\tCYCLE\tPAID")
+	for _, t := range list {
+		paid := "-"
+		if t.PaidAt != nil {
+			paid = t.PaidAt.Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%dd\t%s\n", t.ID, t.Name,
+			formatUnix(t.StartTime), formatUnix(t.EntryEnd), formatUnix(t.RankingEnd), formatUnix(t.RewardEnd),
+			t.CycleDays, paid)
+	}
+	return w.Flush()
+}
+
+func runTournamentShow(args []string) error {
+	fs := flag.NewFlagSet("tournament-show", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Tournament ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openTournaments(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	detail, err := svc.Get(uint32(*id))
+	if err != nil {
+		return err
+	}
+	return printJSON(detail)
+}
+
+func runTournamentCreate(args []string) error {
+	fs := flag.NewFlagSet("tournament-create", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	filePath := fs.String("file", "", "Tournament JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	detail, err := readTournamentFile(*filePath)
+	if err != nil {
+		return err
+	}
+	svc, db, err := openTournaments(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	created, err := svc.Create(detail)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Tournament %d scheduled: %s to %s\n", created.ID, formatUnix(created.StartTime), formatUnix(created.RewardEnd))
+	return nil
+}
+
+func runTournamentUpdate(args []string) error {
+	fs := flag.NewFlagSet("tournament-update", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Tournament ID")
+	filePath := fs.String("file", "", "Tournament JSON file (required)")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	if *filePath == "" {
+		return errors.New("--file is required")
+	}
+	detail, err := readTournamentFile(*filePath)
+	if err != nil {
+		return err
+	}
+	svc, db, err := openTournaments(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if _, err := svc.Update(uint32(*id), detail); err != nil {
+		return err
+	}
+	fmt.Printf("Tournament %d updated\n", *id)
+	return nil
+}
+
+func runTournamentDelete(args []string) error {
+	fs := flag.NewFlagSet("tournament-delete", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Tournament ID")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openTournaments(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := svc.Delete(uint32(*id)); err != nil {
+		return err
+	}
+	fmt.Printf("Tournament %d deleted\n", *id)
+	return nil
+}
+
+func runTournamentResults(args []string) error {
+	fs := flag.NewFlagSet("tournament-results", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Tournament ID")
+	status := fs.String("status", "", "Only show results with this status")
+	_ = fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	svc, db, err := openTournaments(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	results, err := svc.Results(uint32(*id), *status)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tCHARACTER\tEVENT\tSUBMITTED\tSTATUS\tREASON")
+	for _, r := range results {
+		_, _ = fmt.Fprintf(w, "%d\t%s (%d)\t%d\t%s\t%s\t%s\n", r.ID, r.CharName, r.CharID, r.EventID,
+			r.SubmittedAt.Format(time.RFC3339), r.Status, r.FlagReason)
+	}
+	return w.Flush()
+}
+
+func runTournamentReview(args []string) error {
+	fs := flag.NewFlagSet("tournament-review", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	id := fs.Uint("id", 0, "Tournament ID")
+	resultID := fs.Uint("result", 0, "Result ID")
+	status := fs.String("status", "", "verified or rejected")
+	_ = fs.Parse(args)
+
+	if *id == 0 || *resultID == 0 {
+		return errors.New("--id and --result are required")
+	}
+	svc, db, err := openTournaments(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := svc.Review(uint32(*id), uint32(*resultID), *status); err != nil {
+		return err
+	}
+	fmt.Printf("Result %d marked %s\n", *resultID, *status)
+	return nil
+}
@@ -18,11 +18,11 @@ const (
 	MSG_SYS_UPDATE_RIGHT          uint16 = 0x0058
 	MSG_SYS_RIGHTS_RELOAD         uint16 = 0x005D
 	MSG_MHF_LOADDATA              uint16 = 0x0061
-	MSG_MHF_ENUMERATE_QUEST       uint16 = 0x009F
+	MSG_MHF_ENUMERATE_QUEST       uint16 = 0x00A0
 	MSG_MHF_GET_ACHIEVEMENT       uint16 = 0x00D4
 	MSG_MHF_ADD_ACHIEVEMENT       uint16 = 0x00D6
 	MSG_MHF_DISPLAYED_ACHIEVEMENT uint16 = 0x00D8
-	MSG_MHF_GET_WEEKLY_SCHED      uint16 = 0x00E1
+	MSG_MHF_GET_WEEKLY_SCHED      uint16 = 0x00E2
 
 	// Boost time / login boost (issue #187)
 	MSG_MHF_GET_BOOST_TIME              uint16 = 0x0126
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+
+	"erupe-ce/common/byteframe"
+	cfg "erupe-ce/config"
+	"erupe-ce/network"
+	"erupe-ce/network/clientctx"
+	"erupe-ce/network/mhfpacket"
+	"erupe-ce/network/pcap"
+)
+
+// decodePacket parses a captured packet into its typed mhfpacket struct
+// using the client mode recorded in the capture header. Packets whose
+// layout is unknown, or whose payload does not parse cleanly, return an
+// error.
+func decodePacket(rec pcap.PacketRecord, mode cfg.Mode) (pkt mhfpacket.MHFPacket, err error) {
+	if len(rec.Payload) < 2 {
+		return nil, fmt.Errorf("payload too short")
+	}
+	pkt = mhfpacket.FromOpcode(network.PacketID(rec.Opcode))
+	if pkt == nil {
+		return nil, fmt.Errorf("unknown opcode 0x%04X", rec.Opcode)
+	}
+	defer func() {
+		// Captures are untrusted input; a malformed payload must not take
+		// down the whole dump.
+		if r := recover(); r != nil {
+			pkt, err = nil, fmt.Errorf("parse panicked: %v", r)
+		}
+	}()
+	bf := byteframe.NewByteFrameFromBytes(rec.Payload[2:])
+	if err := pkt.Parse(bf, &clientctx.ClientContext{RealClientMode: mode}); err != nil {
+		return nil, err
+	}
+	if err := bf.Err(); err != nil {
+		return nil, err
+	}
+	return pkt, nil
+}
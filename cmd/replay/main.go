@@ -3,6 +3,7 @@
 // Usage:
 //
 //	replay --capture file.mhfr --mode dump     # Human-readable text output
+//	replay --capture file.mhfr --mode dump --decode  # Include typed packet fields
 //	replay --capture file.mhfr --mode json     # JSON export
 //	replay --capture file.mhfr --mode stats    # Opcode histogram, duration, counts
 //	replay --capture file.mhfr --mode replay --target 127.0.0.1:54001 --no-auth  # Replay against live server
@@ -20,6 +21,7 @@ import (
 	"time"
 
 	"erupe-ce/cmd/protbot/conn"
+	cfg "erupe-ce/config"
 	"erupe-ce/network"
 	"erupe-ce/network/pcap"
 )
@@ -33,6 +35,7 @@ func main() {
 	target := flag.String("target", "", "Target server address for replay mode (host:port)")
 	speed := flag.Float64("speed", 1.0, "Replay speed multiplier (e.g. 2.0 = 2x faster)")
 	noAuth := flag.Bool("no-auth", false, "Skip auth token patching (requires DisableTokenCheck on server)")
+	decode := flag.Bool("decode", false, "Print decoded packet fields in dump mode")
 	_ = noAuth // currently only no-auth mode is supported
 	flag.Parse()
 
@@ -44,7 +47,7 @@ func main() {
 
 	switch *mode {
 	case "dump":
-		if err := runDump(*capturePath); err != nil {
+		if err := runDump(*capturePath, *decode); err != nil {
 			fmt.Fprintf(os.Stderr, "dump failed: %v\n", err)
 			os.Exit(1)
 		}
@@ -224,7 +227,7 @@ func buildPingResponse() []byte {
 	return []byte{0x00, 0x17, 0x00, 0x10}
 }
 
-func runDump(path string) error {
+func runDump(path string, decode bool) error {
 	r, f, err := openCapture(path)
 	if err != nil {
 		return err
@@ -254,6 +257,11 @@ func runDump(path string) error {
 		opcodeName := network.PacketID(rec.Opcode).String()
 		fmt.Printf("#%04d  +%-12s  %s  0x%04X %-30s  %d bytes\n",
 			i, elapsed, rec.Direction, rec.Opcode, opcodeName, len(rec.Payload))
+		if decode {
+			if pkt, err := decodePacket(rec, cfg.Mode(r.Header.ClientMode)); err == nil {
+				fmt.Printf("       %+v\n", pkt)
+			}
+		}
 	}
 
 	fmt.Printf("\nTotal: %d packets\n", len(records))
@@ -274,13 +282,14 @@ type jsonHeader struct {
 }
 
 type jsonPacket struct {
-	Index      int    `json:"index"`
-	Timestamp  string `json:"timestamp"`
-	ElapsedNs  int64  `json:"elapsed_ns"`
-	Direction  string `json:"direction"`
-	Opcode     uint16 `json:"opcode"`
-	OpcodeName string `json:"opcode_name"`
-	PayloadLen int    `json:"payload_len"`
+	Index      int         `json:"index"`
+	Timestamp  string      `json:"timestamp"`
+	ElapsedNs  int64       `json:"elapsed_ns"`
+	Direction  string      `json:"direction"`
+	Opcode     uint16      `json:"opcode"`
+	OpcodeName string      `json:"opcode_name"`
+	PayloadLen int         `json:"payload_len"`
+	Decoded    interface{} `json:"decoded,omitempty"`
 }
 
 func runJSON(path string) error {
@@ -316,6 +325,9 @@ func runJSON(path string) error {
 			OpcodeName: network.PacketID(rec.Opcode).String(),
 			PayloadLen: len(rec.Payload),
 		}
+		if pkt, err := decodePacket(rec, cfg.Mode(r.Header.ClientMode)); err == nil {
+			out.Packets[i].Decoded = pkt
+		}
 	}
 
 	enc := json.NewEncoder(os.Stdout)
@@ -8,6 +8,11 @@ import (
 	"strings"
 	"testing"
 
+	"erupe-ce/common/byteframe"
+	cfg "erupe-ce/config"
+	"erupe-ce/network"
+	"erupe-ce/network/clientctx"
+	"erupe-ce/network/mhfpacket"
 	"erupe-ce/network/pcap"
 )
 
@@ -48,7 +53,7 @@ func TestRunDump(t *testing.T) {
 		{TimestampNs: 1000000200, Direction: pcap.DirServerToClient, Opcode: 0x0012, Payload: []byte{0x00, 0x12, 0xFF}},
 	})
 	// Just verify it doesn't error.
-	if err := runDump(path); err != nil {
+	if err := runDump(path, false); err != nil {
 		t.Fatalf("runDump: %v", err)
 	}
 }
@@ -310,3 +315,36 @@ func TestBuildPingResponse(t *testing.T) {
 		t.Errorf("opcode = 0x%04X, want 0x%04X", opcode, opcodeSysPing)
 	}
 }
+
+func TestDecodePacket(t *testing.T) {
+	bf := byteframe.NewByteFrame()
+	bf.WriteUint16(uint16(network.MSG_SYS_ENTER_STAGE))
+	if err := (&mhfpacket.MsgSysEnterStage{AckHandle: 7, StageID: "sl1Ns200p0a0u0"}).Build(bf, &clientctx.ClientContext{RealClientMode: cfg.ZZ}); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	rec := pcap.PacketRecord{Direction: pcap.DirClientToServer, Opcode: uint16(network.MSG_SYS_ENTER_STAGE), Payload: bf.Data()}
+
+	pkt, err := decodePacket(rec, cfg.ZZ)
+	if err != nil {
+		t.Fatalf("decodePacket: %v", err)
+	}
+	enter, ok := pkt.(*mhfpacket.MsgSysEnterStage)
+	if !ok {
+		t.Fatalf("decodePacket returned %T, want *MsgSysEnterStage", pkt)
+	}
+	if enter.AckHandle != 7 || enter.StageID != "sl1Ns200p0a0u0" {
+		t.Errorf("decoded = %+v", enter)
+	}
+}
+
+func TestDecodePacketErrors(t *testing.T) {
+	for _, rec := range []pcap.PacketRecord{
+		{Opcode: 0x0022, Payload: []byte{0x00}},
+		{Opcode: 0xFFFF, Payload: []byte{0xFF, 0xFF}},
+		{Opcode: uint16(network.MSG_SYS_ENTER_STAGE), Payload: []byte{0x00, 0x22, 0x00}},
+	} {
+		if _, err := decodePacket(rec, cfg.ZZ); err == nil {
+			t.Errorf("decodePacket(%x) should fail", rec.Payload)
+		}
+	}
+}
@@ -6,6 +6,11 @@
 //	saveutil export    --config config.json --char-id 42 [--output export.json]
 //	saveutil grant-import --config config.json --char-id 42 [--ttl 24h]
 //	saveutil revoke-import --config config.json --char-id 42
+//	saveutil backups   --config config.json --char-id 42
+//	saveutil diff      --config config.json --char-id 42 --from 1 [--to current]
+//	saveutil restore   --config config.json --char-id 42 --slot 1
+//	saveutil inspect   --config config.json (--char-id 42 | --file savedata.bin)
+//	saveutil edit      --config config.json --char-id 42 --set zenny=500000 [--set hr=999] [--dry-run]
 package main
 
 import (
@@ -18,17 +23,25 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
+	cfg "erupe-ce/config"
+	"erupe-ce/server/channelserver"
 	"erupe-ce/server/channelserver/compression/nullcomp"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"go.uber.org/zap"
 )
 
-// dbConfig is the minimal config subset needed to connect to PostgreSQL.
+// dbConfig is the minimal config subset needed to connect to PostgreSQL and
+// parse save data.
 type dbConfig struct {
-	Database struct {
+	ClientMode string `json:"ClientMode"`
+	Database   struct {
 		Host     string `json:"Host"`
 		Port     int    `json:"Port"`
 		User     string `json:"User"`
@@ -55,6 +68,16 @@ func main() {
 		err = runGrantImport(args)
 	case "revoke-import":
 		err = runRevokeImport(args)
+	case "backups":
+		err = runBackups(args)
+	case "diff":
+		err = runDiff(args)
+	case "restore":
+		err = runRestore(args)
+	case "inspect":
+		err = runInspect(args)
+	case "edit":
+		err = runEdit(args)
 	default:
 		fmt.Fprintf(os.Stderr, "unknown command: %s\n", cmd)
 		printUsage()
@@ -73,24 +96,43 @@ Commands:
   import       --config config.json --char-id N --file export.json
   export       --config config.json --char-id N [--output file.json]
   grant-import --config config.json --char-id N [--ttl 24h]
-  revoke-import --config config.json --char-id N`)
+  revoke-import --config config.json --char-id N
+  backups      --config config.json --char-id N
+  diff         --config config.json --char-id N --from SLOT [--to SLOT]
+  restore      --config config.json --char-id N --slot SLOT
+  inspect      --config config.json (--char-id N | --file savedata.bin)
+  edit         --config config.json --char-id N --set FIELD=VALUE... [--dry-run]
+
+SLOT is a backup slot number or "current" for the live save. Restore refuses
+to run while the character is online and keeps the replaced save in SLOT.
+Edit also refuses while the character is online and keeps the unedited save
+in a backup slot. Editable fields: `+strings.Join(channelserver.SaveFieldNames(), ", "))
 }
 
-// openDB parses config.json and returns an open database connection.
-func openDB(configPath string) (*sqlx.DB, error) {
+// loadConfig parses the subset of config.json saveutil needs.
+func loadConfig(configPath string) (dbConfig, error) {
+	var conf dbConfig
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("read config: %w", err)
+		return conf, fmt.Errorf("read config: %w", err)
+	}
+	if err := json.Unmarshal(data, &conf); err != nil {
+		return conf, fmt.Errorf("parse config: %w", err)
 	}
-	var cfg dbConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("parse config: %w", err)
+	return conf, nil
+}
+
+// openDB parses config.json and returns an open database connection.
+func openDB(configPath string) (*sqlx.DB, error) {
+	conf, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
 	}
 	dsn := fmt.Sprintf(
 		"host='%s' port='%d' user='%s' password='%s' dbname='%s' sslmode=disable",
-		cfg.Database.Host, cfg.Database.Port,
-		cfg.Database.User, cfg.Database.Password,
-		cfg.Database.Database,
+		conf.Database.Host, conf.Database.Port,
+		conf.Database.User, conf.Database.Password,
+		conf.Database.Database,
 	)
 	db, err := sqlx.Open("postgres", dsn)
 	if err != nil {
@@ -303,6 +345,287 @@ func runRevokeImport(args []string) error {
 	return nil
 }
 
+// --- backups / diff / restore ---
+
+// loadMode returns the configured ClientMode, ZZ when unset.
+func loadMode(configPath string) (cfg.Mode, error) {
+	conf, err := loadConfig(configPath)
+	if err != nil {
+		return 0, err
+	}
+	if conf.ClientMode == "" {
+		return cfg.ZZ, nil
+	}
+	mode, ok := cfg.ParseMode(conf.ClientMode)
+	if !ok {
+		return 0, fmt.Errorf("unknown ClientMode %q", conf.ClientMode)
+	}
+	return mode, nil
+}
+
+// openSaveHistory opens the database and returns a SaveHistoryService that
+// parses saves with the configured ClientMode.
+func openSaveHistory(configPath string) (*channelserver.SaveHistoryService, *sqlx.DB, error) {
+	mode, err := loadMode(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := openDB(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	svc := channelserver.NewSaveHistoryService(channelserver.NewCharacterRepository(db), mode, zap.NewNop())
+	return svc, db, nil
+}
+
+// parseSlot accepts a backup slot number or "current".
+func parseSlot(v string) (int, error) {
+	if v == "current" {
+		return channelserver.CurrentSaveSlot, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid slot %q", v)
+	}
+	return n, nil
+}
+
+func printBackupRow(w *tabwriter.Writer, b channelserver.SaveBackupInfo) {
+	slot, savedAt := strconv.Itoa(b.Slot), b.SavedAt.Format(time.RFC3339)
+	if b.Slot == channelserver.CurrentSaveSlot {
+		slot, savedAt = "current", "-"
+	}
+	if b.Summary == nil {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", slot, savedAt, b.Size, b.Error)
+		return
+	}
+	sum := b.Summary
+	_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t%d\t%d\t%d\n",
+		slot, savedAt, b.Size, sum.Name, sum.HR, sum.GR, sum.Zenny, sum.Playtime)
+}
+
+func runBackups(args []string) error {
+	fs := flag.NewFlagSet("backups", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	charID := fs.Uint("char-id", 0, "Character ID whose backups to list")
+	_ = fs.Parse(args)
+
+	if *charID == 0 {
+		return errors.New("--char-id is required")
+	}
+
+	svc, db, err := openSaveHistory(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	history, err := svc.History(uint32(*charID))
+	if err != nil {
+		return fmt.Errorf("load backups: %w", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SLOT\tSAVED AT\tSIZE\tNAME\tHR\tGR\tZENNY\tPLAYTIME")
+	printBackupRow(w, history.Current)
+	for _, b := range history.Backups {
+		printBackupRow(w, b)
+	}
+	return w.Flush()
+}
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	charID := fs.Uint("char-id", 0, "Character ID to compare saves of")
+	fromStr := fs.String("from", "", "Slot to compare from (required)")
+	toStr := fs.String("to", "current", "Slot to compare to")
+	_ = fs.Parse(args)
+
+	if *charID == 0 {
+		return errors.New("--char-id is required")
+	}
+	if *fromStr == "" {
+		return errors.New("--from is required")
+	}
+	from, err := parseSlot(*fromStr)
+	if err != nil {
+		return err
+	}
+	to, err := parseSlot(*toStr)
+	if err != nil {
+		return err
+	}
+
+	svc, db, err := openSaveHistory(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	diffs, err := svc.DiffBackups(uint32(*charID), from, to)
+	if err != nil {
+		return fmt.Errorf("diff: %w", err)
+	}
+	if len(diffs) == 0 {
+		fmt.Println("Saves are identical")
+		return nil
+	}
+	for _, d := range diffs {
+		if d.ChangedBytes > 0 {
+			fmt.Printf("%-14s %d bytes changed\n", d.Field, d.ChangedBytes)
+		} else {
+			fmt.Printf("%-14s %v -> %v\n", d.Field, d.Old, d.New)
+		}
+	}
+	return nil
+}
+
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	charID := fs.Uint("char-id", 0, "Character ID to restore")
+	slot := fs.Int("slot", -1, "Backup slot to restore (required)")
+	_ = fs.Parse(args)
+
+	if *charID == 0 {
+		return errors.New("--char-id is required")
+	}
+	if *slot < 0 {
+		return errors.New("--slot is required")
+	}
+
+	svc, db, err := openSaveHistory(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	restored, err := svc.RestoreBackup(uint32(*charID), *slot)
+	if err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+	fmt.Printf("Character %d restored from slot %d (HR %d, GR %d); the replaced save is now in slot %d\n",
+		*charID, *slot, restored.Summary.HR, restored.Summary.GR, *slot)
+	return nil
+}
+
+// --- inspect / edit ---
+
+// setFlags collects repeated --set FIELD=VALUE flags.
+type setFlags []channelserver.SaveEdit
+
+func (f *setFlags) String() string { return fmt.Sprint(*f) }
+
+func (f *setFlags) Set(v string) error {
+	field, value, ok := strings.Cut(v, "=")
+	if !ok || field == "" {
+		return fmt.Errorf("want FIELD=VALUE, got %q", v)
+	}
+	*f = append(*f, channelserver.SaveEdit{Field: field, Value: value})
+	return nil
+}
+
+func printFields(fields channelserver.SaveFields) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fields)
+}
+
+// openSaveEdit opens the database and returns a SaveEditService that parses
+// saves with the configured ClientMode.
+func openSaveEdit(configPath string) (*channelserver.SaveEditService, *sqlx.DB, error) {
+	mode, err := loadMode(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := openDB(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	svc := channelserver.NewSaveEditService(channelserver.NewCharacterRepository(db), mode, zap.NewNop())
+	return svc, db, nil
+}
+
+func runInspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	charID := fs.Uint("char-id", 0, "Character ID to inspect")
+	filePath := fs.String("file", "", "Inspect a savedata blob file instead (no database needed)")
+	_ = fs.Parse(args)
+
+	if (*charID == 0) == (*filePath == "") {
+		return errors.New("exactly one of --char-id and --file is required")
+	}
+
+	if *filePath != "" {
+		mode, err := loadMode(*configPath)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(*filePath)
+		if err != nil {
+			return fmt.Errorf("read file: %w", err)
+		}
+		save, err := channelserver.DecodeSave(0, mode, data)
+		if err != nil {
+			return err
+		}
+		return printFields(save.Fields())
+	}
+
+	svc, db, err := openSaveEdit(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	fields, err := svc.Inspect(uint32(*charID))
+	if err != nil {
+		return fmt.Errorf("inspect: %w", err)
+	}
+	return printFields(fields)
+}
+
+func runEdit(args []string) error {
+	fs := flag.NewFlagSet("edit", flag.ExitOnError)
+	configPath := fs.String("config", "config.json", "Path to config.json")
+	charID := fs.Uint("char-id", 0, "Character ID to edit")
+	dryRun := fs.Bool("dry-run", false, "Print the edited fields without writing them")
+	var edits setFlags
+	fs.Var(&edits, "set", "FIELD=VALUE to write (repeatable)")
+	_ = fs.Parse(args)
+
+	if *charID == 0 {
+		return errors.New("--char-id is required")
+	}
+	if len(edits) == 0 {
+		return errors.New("at least one --set is required")
+	}
+
+	svc, db, err := openSaveEdit(*configPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	var fields channelserver.SaveFields
+	if *dryRun {
+		fields, err = svc.Preview(uint32(*charID), edits)
+	} else {
+		fields, err = svc.Edit(uint32(*charID), edits)
+	}
+	if err != nil {
+		return fmt.Errorf("edit: %w", err)
+	}
+	if err := printFields(fields); err != nil {
+		return err
+	}
+	if !*dryRun {
+		fmt.Fprintf(os.Stderr, "Character %d saved; the unedited save was kept as a backup (see saveutil backups)\n", *charID)
+	}
+	return nil
+}
+
 // blobColumns is the ordered list of transferable save blob column names.
 var blobColumns = []string{
 	"savedata", "decomyset", "hunternavi", "otomoairou", "partner",
@@ -0,0 +1,31 @@
+package metrics
+
+import "database/sql"
+
+// DBStatser is implemented by *sql.DB and *sqlx.DB.
+type DBStatser interface {
+	Stats() sql.DBStats
+}
+
+// RegisterDBStats exposes the connection pool statistics of db, read at
+// scrape time.
+func RegisterDBStats(r *Registry, db DBStatser) {
+	gauge := func(name, help string, fn func(sql.DBStats) float64) {
+		r.NewGauge(name, help).Func(func() float64 { return fn(db.Stats()) })
+	}
+	counter := func(name, help string, fn func(sql.DBStats) float64) {
+		r.NewCounter(name, help).Func(func() float64 { return fn(db.Stats()) })
+	}
+	gauge("erupe_db_max_open_connections", "Maximum number of open connections to the database.",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })
+	gauge("erupe_db_open_connections", "Number of established connections, in use and idle.",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) })
+	gauge("erupe_db_in_use_connections", "Number of connections currently in use.",
+		func(s sql.DBStats) float64 { return float64(s.InUse) })
+	gauge("erupe_db_idle_connections", "Number of idle connections.",
+		func(s sql.DBStats) float64 { return float64(s.Idle) })
+	counter("erupe_db_wait_count_total", "Total number of connections waited for.",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) })
+	counter("erupe_db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })
+}
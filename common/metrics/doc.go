@@ -0,0 +1,5 @@
+// Package metrics provides a minimal registry of counters, gauges and
+// histograms that renders in the Prometheus text exposition format, so a
+// running server can be scraped without pulling in the Prometheus client
+// library.
+package metrics
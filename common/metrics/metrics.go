@@ -0,0 +1,291 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets are the default histogram buckets, in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// Registry holds metric families in registration order and serves them over
+// HTTP. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+	names    map[string]bool
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+type family struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	fn          func() float64
+	counts      []uint64 // per bucket, not cumulative
+	sum         float64
+	count       uint64
+}
+
+func (r *Registry) register(name, help, typ string, buckets []float64, labels []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.names[name] = true
+	f := &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	r.families = append(r.families, f)
+	return f
+}
+
+// get returns the series for labelValues, creating it if needed. The caller
+// must hold f.mu.
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if f.typ == typeHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) add(v float64, labelValues []string) {
+	f.mu.Lock()
+	f.get(labelValues).value += v
+	f.mu.Unlock()
+}
+
+func (f *family) set(v float64, labelValues []string) {
+	f.mu.Lock()
+	f.get(labelValues).value = v
+	f.mu.Unlock()
+}
+
+func (f *family) setFunc(fn func() float64, labelValues []string) {
+	f.mu.Lock()
+	f.get(labelValues).fn = fn
+	f.mu.Unlock()
+}
+
+// Counter is a family of monotonically increasing values.
+type Counter struct{ f *family }
+
+// NewCounter registers a counter family with the given label names.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{r.register(name, help, typeCounter, nil, labels)}
+}
+
+// Inc adds one to the series identified by labelValues.
+func (c *Counter) Inc(labelValues ...string) { c.f.add(1, labelValues) }
+
+// Add adds v, which must not be negative, to the series identified by
+// labelValues.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic(fmt.Sprintf("metrics: %s cannot decrease", c.f.name))
+	}
+	c.f.add(v, labelValues)
+}
+
+// Func makes the series identified by labelValues report fn's result at
+// scrape time, for counters kept elsewhere.
+func (c *Counter) Func(fn func() float64, labelValues ...string) { c.f.setFunc(fn, labelValues) }
+
+// Gauge is a family of values that can go up and down.
+type Gauge struct{ f *family }
+
+// NewGauge registers a gauge family with the given label names.
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r.register(name, help, typeGauge, nil, labels)}
+}
+
+// Set sets the series identified by labelValues to v.
+func (g *Gauge) Set(v float64, labelValues ...string) { g.f.set(v, labelValues) }
+
+// Add adds v to the series identified by labelValues.
+func (g *Gauge) Add(v float64, labelValues ...string) { g.f.add(v, labelValues) }
+
+// Func makes the series identified by labelValues report fn's result at
+// scrape time.
+func (g *Gauge) Func(fn func() float64, labelValues ...string) { g.f.setFunc(fn, labelValues) }
+
+// Histogram is a family of observation distributions.
+type Histogram struct{ f *family }
+
+// NewHistogram registers a histogram family. buckets are the inclusive upper
+// bounds and must be sorted ascending; nil selects DefBuckets.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("metrics: %s buckets are not sorted", name))
+	}
+	return &Histogram{r.register(name, help, typeHistogram, buckets, labels)}
+}
+
+// Observe records v in the series identified by labelValues.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	i := sort.SearchFloat64s(h.f.buckets, v)
+	h.f.mu.Lock()
+	s := h.f.get(labelValues)
+	if i < len(s.counts) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+	h.f.mu.Unlock()
+}
+
+// snapshot copies the family's series sorted by label values, so callbacks
+// run and output is written without holding f.mu.
+func (f *family) snapshot() []series {
+	f.mu.Lock()
+	out := make([]series, 0, len(f.series))
+	for _, s := range f.series {
+		c := *s
+		c.counts = append([]uint64(nil), s.counts...)
+		out = append(out, c)
+	}
+	f.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].labelValues, out[j].labelValues
+		for k := range a {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return false
+	})
+	return out
+}
+
+// WriteText writes every registered family in the text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]*family(nil), r.families...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		all := f.snapshot()
+		if len(all) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.typ)
+		for _, s := range all {
+			if f.typ != typeHistogram {
+				v := s.value
+				if s.fn != nil {
+					v = s.fn()
+				}
+				writeSample(bw, f.name, f.labels, s.labelValues, "", "", v)
+				continue
+			}
+			var cumulative uint64
+			for i, le := range f.buckets {
+				cumulative += s.counts[i]
+				writeSample(bw, f.name+"_bucket", f.labels, s.labelValues, "le", formatFloat(le), float64(cumulative))
+			}
+			writeSample(bw, f.name+"_bucket", f.labels, s.labelValues, "le", "+Inf", float64(s.count))
+			writeSample(bw, f.name+"_sum", f.labels, s.labelValues, "", "", s.sum)
+			writeSample(bw, f.name+"_count", f.labels, s.labelValues, "", "", float64(s.count))
+		}
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP serves the registry in the text exposition format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_ = r.WriteText(w)
+}
+
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraName, extraValue string, v float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", l, escapeLabel(values[i]))
+		}
+		if extraName != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", extraName, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func render(t *testing.T, r *Registry) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := r.WriteText(&sb); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	return sb.String()
+}
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_packets_total", "Packets seen.", "opcode")
+	c.Inc("MSG_SYS_PING")
+	c.Inc("MSG_SYS_PING")
+	c.Add(3, "MSG_SYS_ACK")
+
+	want := `# HELP test_packets_total Packets seen.
+# TYPE test_packets_total counter
+test_packets_total{opcode="MSG_SYS_ACK"} 3
+test_packets_total{opcode="MSG_SYS_PING"} 2
+`
+	if got := render(t, r); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCounterRejectsNegative(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_total", "x")
+	defer func() {
+		if recover() == nil {
+			t.Error("Add(-1) did not panic")
+		}
+	}()
+	c.Add(-1)
+}
+
+func TestGaugeFunc(t *testing.T) {
+	r := NewRegistry()
+	g := r.NewGauge("test_sessions", "Sessions.", "server_id")
+	n := 4.0
+	g.Func(func() float64 { return n }, "4112")
+	g.Set(1, "4113")
+
+	out := render(t, r)
+	if !strings.Contains(out, `test_sessions{server_id="4112"} 4`) {
+		t.Errorf("missing func series:\n%s", out)
+	}
+	n = 7
+	if out := render(t, r); !strings.Contains(out, `test_sessions{server_id="4112"} 7`) {
+		t.Errorf("func not re-evaluated:\n%s", out)
+	}
+	if !strings.Contains(out, `test_sessions{server_id="4113"} 1`) {
+		t.Errorf("missing set series:\n%s", out)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("test_seconds", "Latency.", []float64{0.1, 1})
+	h.Observe(0.05)
+	h.Observe(0.1)
+	h.Observe(0.5)
+	h.Observe(2)
+
+	want := `# HELP test_seconds Latency.
+# TYPE test_seconds histogram
+test_seconds_bucket{le="0.1"} 2
+test_seconds_bucket{le="1"} 3
+test_seconds_bucket{le="+Inf"} 4
+test_seconds_sum 2.65
+test_seconds_count 4
+`
+	if got := render(t, r); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestEmptyFamilyOmitted(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("test_total", "x", "a")
+	if got := render(t, r); got != "" {
+		t.Errorf("expected no output, got %q", got)
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	r := NewRegistry()
+	r.NewGauge("test", "Line one\nline two.", "name").Set(1, "a\"b\\c\n")
+	out := render(t, r)
+	if !strings.Contains(out, `# HELP test Line one\nline two.`) {
+		t.Errorf("help not escaped:\n%s", out)
+	}
+	if !strings.Contains(out, `test{name="a\"b\\c\n"} 1`) {
+		t.Errorf("label not escaped:\n%s", out)
+	}
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("test_total", "x")
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate registration did not panic")
+		}
+	}()
+	r.NewGauge("test_total", "x")
+}
+
+func TestWrongLabelCountPanics(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_total", "x", "a", "b")
+	defer func() {
+		if recover() == nil {
+			t.Error("wrong label count did not panic")
+		}
+	}()
+	c.Inc("only-one")
+}
+
+func TestServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("test_total", "x").Inc()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, ContentType)
+	}
+	if !strings.Contains(rec.Body.String(), "test_total 1\n") {
+		t.Errorf("unexpected body:\n%s", rec.Body.String())
+	}
+}
+
+type fakeDB struct{ stats sql.DBStats }
+
+func (f fakeDB) Stats() sql.DBStats { return f.stats }
+
+func TestRegisterDBStats(t *testing.T) {
+	r := NewRegistry()
+	RegisterDBStats(r, fakeDB{sql.DBStats{
+		MaxOpenConnections: 10,
+		OpenConnections:    3,
+		InUse:              2,
+		Idle:               1,
+		WaitCount:          5,
+		WaitDuration:       1500 * time.Millisecond,
+	}})
+	out := render(t, r)
+	for _, want := range []string{
+		"erupe_db_max_open_connections 10\n",
+		"erupe_db_open_connections 3\n",
+		"erupe_db_in_use_connections 2\n",
+		"erupe_db_idle_connections 1\n",
+		"erupe_db_wait_count_total 5\n",
+		"erupe_db_wait_duration_seconds_total 1.5\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_total", "x", "worker")
+	h := r.NewHistogram("test_seconds", "x", nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Inc("w")
+				h.Observe(0.01)
+				if j%100 == 0 {
+					_ = r.WriteText(&strings.Builder{})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	out := render(t, r)
+	if !strings.Contains(out, `test_total{worker="w"} 8000`) {
+		t.Errorf("lost increments:\n%s", out)
+	}
+	if !strings.Contains(out, "test_seconds_count 8000\n") {
+		t.Errorf("lost observations:\n%s", out)
+	}
+}
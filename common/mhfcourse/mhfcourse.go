@@ -3,6 +3,7 @@ package mhfcourse
 import (
 	"math"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -58,6 +59,18 @@ func (c Course) Value() uint32 {
 	return uint32(math.Pow(2, float64(c.ID)))
 }
 
+// CourseByName looks up a course by any of its aliases, ignoring case.
+func CourseByName(name string) (Course, bool) {
+	for id, names := range aliases {
+		for _, alias := range names {
+			if strings.EqualFold(alias, name) {
+				return Course{ID: id}, true
+			}
+		}
+	}
+	return Course{}, false
+}
+
 // CourseExists returns true if the named course exists in the given slice
 func CourseExists(ID uint16, c []Course) bool {
 	for _, course := range c {
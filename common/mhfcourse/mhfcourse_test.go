@@ -334,3 +334,24 @@ func BenchmarkCourses(b *testing.B) {
 		_ = Courses()
 	}
 }
+
+func TestCourseByName(t *testing.T) {
+	tests := []struct {
+		name   string
+		wantID uint16
+		wantOK bool
+	}{
+		{"HunterLife", 2, true},
+		{"hl", 2, true},
+		{"EXTRAA", 3, true},
+		{"NetCafe", 26, true},
+		{"Unknown", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		c, ok := CourseByName(tt.name)
+		if ok != tt.wantOK || c.ID != tt.wantID {
+			t.Errorf("CourseByName(%q) = (%d, %v), want (%d, %v)", tt.name, c.ID, ok, tt.wantID, tt.wantOK)
+		}
+	}
+}
@@ -62,6 +62,26 @@ func (m Mode) String() string {
 	return versionStrings[m]
 }
 
+// Name returns the ClientMode string of m, e.g. "ZZ", or "" for an undefined
+// mode. String is off by one (see config_mode_test.go) and panics on ZZ.
+func (m Mode) Name() string {
+	if m < S1 || m > ZZ {
+		return ""
+	}
+	return versionStrings[m-1]
+}
+
+// ParseMode returns the Mode for a ClientMode string such as "ZZ" or "G10.1",
+// ignoring case.
+func ParseMode(s string) (Mode, bool) {
+	for i := range versionStrings {
+		if strings.ToUpper(s) == versionStrings[i] {
+			return Mode(i + 1), true
+		}
+	}
+	return 0, false
+}
+
 // Config holds the global server-wide config.
 type Config struct {
 	Host                      string `mapstructure:"Host"`
@@ -83,12 +103,18 @@ type Config struct {
 	AutoCreateAccount         bool   // Automatically create accounts if they don't exist
 	LoopDelay                 int    // Delay in milliseconds between each loop iteration
 	DefaultCourses            []uint16
-	EarthStatus               int32
-	EarthID                   int32
-	EarthMonsters             []int32
+	EarthStatus               int32            // Initial Earth phase (1 Conquest, 11 Pallone, 21 Tower); 0 disables the cycle
+	EarthID                   int32            // Initial Earth event ID, incremented each three-week cycle
+	EarthMonsters             []int32          // Conquest target monster IDs, in beat level slot order
+	EarthRewards              []ConquestReward // Weekly Conquest ranking rewards, paid as distributions
 	SaveDumps                 SaveDumpOptions
 	Screenshots               ScreenshotsOptions
 	Capture                   CaptureOptions
+	LoginProtection           LoginProtectionOptions
+	Authentication            AuthenticationOptions
+	Raviente                  RavienteOptions
+	Festa                     FestaOptions
+	FortAttack                FortAttackOptions
 
 	DebugOptions    DebugOptions
 	GameplayOptions GameplayOptions
@@ -126,6 +152,107 @@ type CaptureOptions struct {
 	CaptureChannel  bool     // Capture channel server sessions
 }
 
+// LoginProtectionOptions throttles failed password logins on the sign server
+// and the API. Failures are counted per username and per IP address; once a
+// count reaches its limit the username or IP is locked out, for twice as long
+// with each further failure.
+type LoginProtectionOptions struct {
+	Enabled              bool // Enable failure counting and lockouts
+	MaxFailures          int  // Failures per username before it is locked out
+	MaxFailuresPerIP     int  // Failures per IP address before it is locked out
+	LockoutSeconds       int  // Length of the first lockout
+	MaxLockoutSeconds    int  // Upper bound on the doubled lockout length
+	ResetSeconds         int  // Seconds without a failure after which a count starts over
+	AutoCreatePerIPDaily int  // Accounts AutoCreateAccount may create per IP per day; 0 is unlimited
+}
+
+// AuthenticationOptions selects how sign server and API logins check
+// passwords. With an external backend, a local account is created the first
+// time a user the backend accepts logs in, and AutoCreateAccount is ignored.
+type AuthenticationOptions struct {
+	Backend string // "local" (users table), "webhook" or "ldap"
+	Webhook WebhookAuthOptions
+	LDAP    LDAPAuthOptions
+}
+
+// WebhookAuthOptions configures the HTTP/JSON authentication backend. The
+// username and password are POSTed as JSON; 200 accepts the login, 401 or
+// 403 rejects the password and 404 reports an unknown user.
+type WebhookAuthOptions struct {
+	URL            string // Endpoint the credentials are POSTed to
+	Secret         string // Sent as a bearer token when set
+	TimeoutSeconds int
+}
+
+// LDAPAuthOptions configures the read-only LDAP bind backend. Logins are
+// checked by a simple bind as the user's DN; nothing is searched or written.
+// A simple bind carries the password in cleartext, so plain ldap:// should
+// only be used with StartTLS or on a trusted network.
+type LDAPAuthOptions struct {
+	URL                string // ldap://host:389 or ldaps://host:636
+	BindDN             string // DN template, %s is replaced by the escaped username
+	StartTLS           bool   // Upgrade ldap:// connections with StartTLS before binding
+	InsecureSkipVerify bool   // Skip TLS certificate checks for ldaps:// and StartTLS
+	TimeoutSeconds     int
+}
+
+// RavienteOptions controls how Raviente siege state is kept and when sieges
+// may start.
+type RavienteOptions struct {
+	SyncSeconds int              // How often each channel saves its siege state and applies API start requests
+	Windows     []RavienteWindow // Scheduled siege windows; empty allows sieges at any time
+}
+
+// RavienteWindow is a recurring period, in server local time, during which
+// players may start a Raviente siege. A waiting siege is started
+// automatically when a window opens.
+type RavienteWindow struct {
+	Weekdays []string // Days the window opens on, e.g. ["Saturday", "Sunday"]; empty is every day
+	Start    string   // Opening time as "HH:MM"
+	Minutes  int      // How long the window stays open
+}
+
+// FestaOptions controls the Mezeporta Festival (Hunter's Festa) scheduler.
+type FestaOptions struct {
+	Enabled  bool // Open registration, judge the soul race, archive and close out festivals automatically
+	RestDays int  // Days between one festival's prize period ending and the next registration opening
+}
+
+// FortAttackOptions schedules Interceptor's Base fort attack events. Every
+// event has its own fort durability, which falls when a sortie on one of its
+// quests returns without a large monster kill; at zero the fort has fallen
+// and the event ends early. Participation rewards are paid as distributions
+// when an event ends.
+type FortAttackOptions struct {
+	Enabled      bool
+	Durability   int                // Fort durability each event starts with
+	SortieDamage int                // Durability lost per failed sortie; failures are only seen in ZZ kill logs
+	Events       []FortAttackEvent  // Recurring event windows
+	Rewards      []FortAttackReward // Paid to each participant when an event ends
+}
+
+// FortAttackEvent is a recurring fort attack window in server local time.
+type FortAttackEvent struct {
+	EventType    uint16   // EnumerateEvent type; type 2 also announces QuestFileIDs
+	Weekdays     []string // Days the event starts on, e.g. ["Saturday"]; empty is every day
+	Start        string   // Start time as "HH:MM"
+	Minutes      int      // How long the event lasts
+	QuestFileIDs []uint16 // Fort quests that count as sorties
+	MinHR        uint16   // Lowest HR allowed to take a fort quest; 0 for none
+	MaxSorties   int      // Sorties each character may take per event; 0 is unlimited
+}
+
+// FortAttackReward is granted to every participant of an ended fort attack
+// who took at least MinSorties sorties. HeldOnly rewards are only granted if
+// the fort did not fall. ItemType is a distribution item type (7 for items).
+type FortAttackReward struct {
+	MinSorties int
+	HeldOnly   bool
+	ItemType   uint8
+	ItemID     uint32
+	Quantity   uint32
+}
+
 // DebugOptions holds various debug/temporary options for use while developing Erupe.
 type DebugOptions struct {
 	CleanDB             bool   // Automatically wipes the DB on server reset.
@@ -203,6 +330,10 @@ type GameplayOptions struct {
 	EnableKaijiEvent               bool    // Enables the Kaiji event in the Rasta Bar
 	EnableHiganjimaEvent           bool    // Enables the Higanjima event in the Rasta Bar
 	EnableNierEvent                bool    // Enables the Nier event in the Rasta Bar
+	EnableGachaPlayHistory         bool    // Sends gacha play ledger entries in MSG_MHF_GET_GACHA_PLAY_HISTORY instead of an empty response
+	EnableDailyMissions            bool    // Sends the daily mission board and progress in MSG_MHF_GET_DAILY_MISSION_MASTER/PERSONAL instead of empty responses
+	EnableDivaMyRanking            bool    // Sends the character's Diva Defense ranks in MSG_MHF_GET_UD_MY_RANKING instead of a canned placeholder
+	EnableSeibattleResults         bool    // Sends guild scores, placings and opponents from stored results in MSG_MHF_GET_SEIBATTLE instead of placeholder rows
 	DisableRoad                    bool    // Disables the Hunting Road
 	SeasonOverride                 bool    // Overrides the Quest Season with the current Mezeporta Season
 }
@@ -228,6 +359,17 @@ type Command struct {
 	Prefix      string
 }
 
+// ConquestReward is a Conquest War ranking reward granted to every player
+// placed between PlaceFrom and PlaceTo (inclusive) on a monster's leaderboard.
+// ItemType is a distribution item type (7 for items).
+type ConquestReward struct {
+	PlaceFrom uint32
+	PlaceTo   uint32
+	ItemType  uint8
+	ItemID    uint32
+	Quantity  uint32
+}
+
 // Course represents a course within MHF
 type Course struct {
 	Name    string
@@ -286,7 +428,16 @@ type APISignLink struct {
 }
 
 type Channel struct {
-	Enabled bool
+	Enabled  bool
+	Registry ChannelRegistry
+}
+
+// ChannelRegistry selects how channels find each other for worldcasts,
+// cross-channel searches, mail notifications and disconnects.
+type ChannelRegistry struct {
+	Backend      string // "local" (single process, default) or "postgres" (several processes sharing one database)
+	InstanceID   string // Unique name for this process in the shared registry; defaults to hostname-pid
+	SyncInterval int    // Seconds between session/stage snapshot publishes (postgres backend)
 }
 
 // Entrance holds the entrance server config.
@@ -364,6 +515,7 @@ func registerDefaults() {
 	viper.RegisterAlias("DisableSoftCrash", "DisableShutdownCountdown")
 	viper.SetDefault("DefaultCourses", []uint16{1, 23, 24})
 	viper.SetDefault("EarthMonsters", []int32{0, 0, 0, 0})
+	viper.SetDefault("EarthRewards", []ConquestReward{})
 
 	// SaveDumps
 	viper.SetDefault("SaveDumps", SaveDumpOptions{
@@ -388,6 +540,41 @@ func registerDefaults() {
 		CaptureChannel:  true,
 	})
 
+	// LoginProtection
+	viper.SetDefault("LoginProtection", LoginProtectionOptions{
+		Enabled:              true,
+		MaxFailures:          5,
+		MaxFailuresPerIP:     20,
+		LockoutSeconds:       30,
+		MaxLockoutSeconds:    3600,
+		ResetSeconds:         3600,
+		AutoCreatePerIPDaily: 3,
+	})
+
+	// Authentication
+	viper.SetDefault("Authentication", AuthenticationOptions{
+		Backend: "local",
+		Webhook: WebhookAuthOptions{TimeoutSeconds: 5},
+		LDAP:    LDAPAuthOptions{TimeoutSeconds: 5},
+	})
+
+	// Raviente
+	viper.SetDefault("Raviente", RavienteOptions{
+		SyncSeconds: 10,
+	})
+
+	// Festa
+	viper.SetDefault("Festa", FestaOptions{
+		Enabled:  true,
+		RestDays: 6,
+	})
+
+	// FortAttack
+	viper.SetDefault("FortAttack", FortAttackOptions{
+		Durability:   100,
+		SortieDamage: 10,
+	})
+
 	// DebugOptions (dot-notation for per-field merge)
 	viper.SetDefault("DebugOptions.MaxHexdumpLength", 256)
 	viper.SetDefault("DebugOptions.DivaOverride", -1)
@@ -486,6 +673,8 @@ func registerDefaults() {
 
 	// Channel server
 	viper.SetDefault("Channel.Enabled", true)
+	viper.SetDefault("Channel.Registry.Backend", "local")
+	viper.SetDefault("Channel.Registry.SyncInterval", 5)
 
 	// Entrance server
 	viper.SetDefault("Entrance.Enabled", true)
@@ -559,13 +748,11 @@ func LoadConfig() (*Config, error) {
 		c.Host = ip.To4().String()
 	}
 
-	for i := range versionStrings {
-		if strings.ToUpper(c.ClientMode) == versionStrings[i] {
-			c.RealClientMode = Mode(i + 1)
-			c.ClientMode = strings.ToUpper(c.ClientMode)
-			if c.RealClientMode <= G101 {
-				c.ClientMode += " (Debug only)"
-			}
+	if mode, ok := ParseMode(c.ClientMode); ok {
+		c.RealClientMode = mode
+		c.ClientMode = strings.ToUpper(c.ClientMode)
+		if c.RealClientMode <= G101 {
+			c.ClientMode += " (Debug only)"
 		}
 	}
 	if c.RealClientMode == 0 {
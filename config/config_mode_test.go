@@ -41,3 +41,27 @@ func TestModeStringAllSafeVersions(t *testing.T) {
 		}
 	}
 }
+
+func TestModeName(t *testing.T) {
+	tests := []struct {
+		mode Mode
+		want string
+	}{
+		{S1, "S1.0"},
+		{F5, "FW.5"},
+		{G101, "G10.1"},
+		{ZZ, "ZZ"},
+		{0, ""},
+		{ZZ + 1, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.mode.Name(); got != tt.want {
+			t.Errorf("Mode(%d).Name() = %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+	for m := S1; m <= ZZ; m++ {
+		if got, ok := ParseMode(m.Name()); !ok || got != m {
+			t.Errorf("ParseMode(%q) = %d, %v, want %d", m.Name(), got, ok, m)
+		}
+	}
+}
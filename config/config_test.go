@@ -703,3 +703,24 @@ func BenchmarkGetOutboundIP4(b *testing.B) {
 		_, _ = getOutboundIP4()
 	}
 }
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   Mode
+		wantOK bool
+	}{
+		{"ZZ", ZZ, true},
+		{"zz", ZZ, true},
+		{"G10.1", G101, true},
+		{"S6.0", S6, true},
+		{"", 0, false},
+		{"Z3", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseMode(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseMode(%q) = (%v, %v), want (%v, %v)", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"erupe-ce/common/gametime"
+	"erupe-ce/common/metrics"
 	cfg "erupe-ce/config"
 	"erupe-ce/server/api"
 	"erupe-ce/server/channelserver"
@@ -311,6 +312,11 @@ func main() {
 		logger.Info("Sign: Disabled")
 	}
 
+	// Collectors shared by the API server's /metrics endpoint and every channel.
+	metricsRegistry := metrics.NewRegistry()
+	metrics.RegisterDBStats(metricsRegistry, db)
+	channelMetrics := channelserver.NewMetrics(metricsRegistry)
+
 	// New Sign server
 	var ApiServer *api.APIServer
 	if config.API.Enabled {
@@ -319,6 +325,7 @@ func main() {
 				Logger:      logger.Named("sign"),
 				ErupeConfig: config,
 				DB:          db,
+				Metrics:     metricsRegistry,
 			})
 		err = ApiServer.Start()
 		if err != nil {
@@ -330,6 +337,7 @@ func main() {
 	}
 
 	var channels []*channelserver.Server
+	var pgRegistry *channelserver.PostgresChannelRegistry
 
 	if config.Channel.Enabled {
 		channelQuery := ""
@@ -359,6 +367,7 @@ func main() {
 					ErupeConfig: config,
 					DB:          db,
 					DiscordBot:  discordBot,
+					Metrics:     channelMetrics,
 				})
 				if ee.IP == "" {
 					c.IP = config.Host
@@ -388,10 +397,34 @@ func main() {
 		// Register all servers in DB
 		_ = db.MustExec(channelQuery)
 
-		registry := channelserver.NewLocalChannelRegistry(channels)
+		var registry channelserver.ChannelRegistry
+		switch config.Channel.Registry.Backend {
+		case "postgres":
+			pgRegistry = channelserver.NewPostgresChannelRegistry(channels, &channelserver.PostgresRegistryConfig{
+				Logger:      logger.Named("registry"),
+				DB:          db,
+				ConnString:  connectString,
+				InstanceID:  config.Channel.Registry.InstanceID,
+				Interval:    time.Duration(config.Channel.Registry.SyncInterval) * time.Second,
+				ErupeConfig: config,
+			})
+			if err := pgRegistry.Start(); err != nil {
+				preventClose(config, fmt.Sprintf("Registry: Failed to start, %s", err.Error()))
+			}
+			logger.Info(fmt.Sprintf("Registry: Shared via PostgreSQL as %s", pgRegistry.InstanceID()))
+			registry = pgRegistry
+		case "", "local":
+			registry = channelserver.NewLocalChannelRegistry(channels)
+		default:
+			preventClose(config, fmt.Sprintf("Registry: Unknown backend %q", config.Channel.Registry.Backend))
+		}
 		for _, c := range channels {
 			c.Registry = registry
 		}
+		if ApiServer != nil {
+			ApiServer.SetSessionKicker(registry)
+			ApiServer.SetMailNotifier(registry)
+		}
 	}
 
 	logger.Info("Finished starting Erupe")
@@ -460,6 +493,10 @@ func main() {
 		for _, c := range channels {
 			c.ShutdownAndDrain(drainCtx)
 		}
+
+		if pgRegistry != nil {
+			pgRegistry.Close()
+		}
 	}
 
 	if config.Sign.Enabled {
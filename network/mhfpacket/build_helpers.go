@@ -0,0 +1,52 @@
+package mhfpacket
+
+import (
+	"erupe-ce/common/byteframe"
+	"erupe-ce/common/stringsupport"
+)
+
+// fixedBytes returns b truncated or zero-padded to exactly n bytes, for
+// fields the client reads with a fixed width.
+func fixedBytes(b []byte, n int) []byte {
+	out := make([]byte, n)
+	copy(out, b)
+	return out
+}
+
+// frameBytes returns the full contents of bf, or nil if bf is nil. Parse
+// stores some opaque sub-payloads as ByteFrames whose read cursor may have
+// been advanced by a handler, so Build always serializes from the start.
+func frameBytes(bf *byteframe.ByteFrame) []byte {
+	if bf == nil {
+		return nil
+	}
+	return bf.Data()
+}
+
+// writeSJISNullTerminated8 writes x as a null-terminated Shift-JIS string
+// preceded by its length (including the terminator) as a uint8, the layout
+// Parse reads with a discarded length byte followed by ReadNullTerminatedBytes.
+func writeSJISNullTerminated8(bf *byteframe.ByteFrame, x string) {
+	b := stringsupport.UTF8ToSJIS(x)
+	bf.WriteUint8(uint8(len(b) + 1))
+	bf.WriteNullTerminatedBytes(b)
+}
+
+// writeNullTerminated8 is writeSJISNullTerminated8 for ASCII identifiers
+// (stage and semaphore IDs) that Parse reads without Shift-JIS decoding.
+func writeNullTerminated8(bf *byteframe.ByteFrame, x string) {
+	bf.WriteUint8(uint8(len(x) + 1))
+	bf.WriteNullTerminatedBytes([]byte(x))
+}
+
+// weeklyStampTypeID maps the stamp card names used by the weekly stamp
+// packets back to their wire values.
+func weeklyStampTypeID(stampType string) uint8 {
+	switch stampType {
+	case "hl":
+		return 1
+	case "ex":
+		return 2
+	}
+	return 0
+}
@@ -678,12 +678,13 @@ func TestMsgSysUnlockStageRoundTrip(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := &clientctx.ClientContext{RealClientMode: cfg.ZZ}
 
-			// Build (returns NOT IMPLEMENTED)
 			original := &MsgSysUnlockStage{}
 			bf := byteframe.NewByteFrame()
-			err := original.Build(bf, ctx)
-			if err == nil {
-				t.Fatal("Build() expected NOT IMPLEMENTED error")
+			if err := original.Build(bf, ctx); err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if len(bf.Data()) != 2 {
+				t.Errorf("Build() wrote %d bytes, want 2", len(bf.Data()))
 			}
 
 			// Parse should consume a uint16 without error
@@ -691,8 +692,7 @@ func TestMsgSysUnlockStageRoundTrip(t *testing.T) {
 			bf.WriteUint16(tt.unk0)
 			_, _ = bf.Seek(0, io.SeekStart)
 			parsed := &MsgSysUnlockStage{}
-			err = parsed.Parse(bf, ctx)
-			if err != nil {
+			if err := parsed.Parse(bf, ctx); err != nil {
 				t.Fatalf("Parse() error = %v", err)
 			}
 		})
@@ -1590,8 +1590,7 @@ func TestBatchParseNotImplemented(t *testing.T) {
 		&MsgMhfReserve10F{},
 		// Empty-struct packets with NOT IMPLEMENTED Parse
 		&MsgHead{}, &MsgSysSetStatus{}, &MsgSysEcho{},
-		&MsgSysLeaveStage{}, &MsgSysAddObject{}, &MsgSysDelObject{},
-		&MsgSysDispObject{}, &MsgSysHideObject{},
+		&MsgSysLeaveStage{},
 		&MsgMhfServerCommand{}, &MsgMhfSetLoginwindow{}, &MsgMhfShutClient{},
 		&MsgMhfUpdateGuildcard{},
 	}
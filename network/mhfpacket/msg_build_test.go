@@ -175,7 +175,7 @@ func TestBuildParseCastedBinary(t *testing.T) {
 }
 
 // TestBuildParseLoadRegister verifies manual-build/Parse round-trip for MsgSysLoadRegister.
-// Build is NOT IMPLEMENTED, so we manually write the binary representation.
+// The binary representation is written by hand to pin the wire layout.
 func TestBuildParseLoadRegister(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -218,7 +218,7 @@ func TestBuildParseLoadRegister(t *testing.T) {
 }
 
 // TestBuildParseOperateRegister verifies manual-build/Parse round-trip for MsgSysOperateRegister.
-// Build is NOT IMPLEMENTED, so we manually write the binary representation.
+// The binary representation is written by hand to pin the wire layout.
 func TestBuildParseOperateRegister(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -386,7 +386,7 @@ func TestBuildParseUpdateObjectBinary(t *testing.T) {
 }
 
 // TestBuildParseArrangeGuildMember verifies manual-build/Parse round-trip for MsgMhfArrangeGuildMember.
-// Build is NOT IMPLEMENTED, so we manually write the binary representation.
+// The binary representation is written by hand to pin the wire layout.
 // Parse reads: uint32 AckHandle, uint32 GuildID, uint8 zeroed, uint8 charCount, then charCount * uint32.
 func TestBuildParseArrangeGuildMember(t *testing.T) {
 	tests := []struct {
@@ -438,7 +438,7 @@ func TestBuildParseArrangeGuildMember(t *testing.T) {
 }
 
 // TestBuildParseEnumerateGuildMember verifies manual-build/Parse round-trip for MsgMhfEnumerateGuildMember.
-// Build is NOT IMPLEMENTED, so we manually write the binary representation.
+// The binary representation is written by hand to pin the wire layout.
 // Parse reads: uint32 AckHandle, uint8 zeroed, uint8 always1, uint32 AllianceID, uint32 GuildID.
 func TestBuildParseEnumerateGuildMember(t *testing.T) {
 	tests := []struct {
@@ -482,7 +482,7 @@ func TestBuildParseEnumerateGuildMember(t *testing.T) {
 }
 
 // TestBuildParseStateCampaign verifies manual-build/Parse round-trip for MsgMhfStateCampaign.
-// Build is NOT IMPLEMENTED, so we manually write the binary representation.
+// The binary representation is written by hand to pin the wire layout.
 func TestBuildParseStateCampaign(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -523,7 +523,7 @@ func TestBuildParseStateCampaign(t *testing.T) {
 }
 
 // TestBuildParseApplyCampaign verifies manual-build/Parse round-trip for MsgMhfApplyCampaign.
-// Build is NOT IMPLEMENTED, so we manually write the binary representation.
+// The binary representation is written by hand to pin the wire layout.
 func TestBuildParseApplyCampaign(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -629,7 +629,7 @@ func TestBuildParseEnumerateEvent(t *testing.T) {
 			}
 
 			bf := byteframe.NewByteFrame()
-			// Build is NOT IMPLEMENTED; manually write the binary representation
+			// Write the binary representation by hand to pin the wire layout
 			bf.WriteUint32(original.AckHandle)
 			bf.WriteUint16(0) // Zeroed (discarded by Parse)
 			bf.WriteUint16(0) // Zeroed (discarded by Parse)
@@ -694,7 +694,7 @@ func TestBuildParseAddUdTacticsPoint(t *testing.T) {
 }
 
 // TestBuildParseApplyDistItem verifies manual-build/Parse round-trip for MsgMhfApplyDistItem.
-// Build is NOT IMPLEMENTED, so we manually write the binary representation.
+// The binary representation is written by hand to pin the wire layout.
 // Note: Unk2 and Unk3 are conditionally parsed based on RealClientMode (G8+ and G10+).
 // Default test config is ZZ, so both Unk2 and Unk3 are read.
 func TestBuildParseApplyDistItem(t *testing.T) {
@@ -770,7 +770,7 @@ func TestBuildParseEnumerateDistItem(t *testing.T) {
 			}
 
 			bf := byteframe.NewByteFrame()
-			// Build is NOT IMPLEMENTED; manually write the binary representation
+			// Write the binary representation by hand to pin the wire layout
 			bf.WriteUint32(original.AckHandle)
 			bf.WriteUint8(original.DistType)
 			bf.WriteUint8(original.Unk1)
@@ -901,7 +901,7 @@ func TestBuildParseAddKouryouPoint(t *testing.T) {
 }
 
 // TestBuildParseCheckDailyCafepoint verifies manual-build/Parse round-trip for MsgMhfCheckDailyCafepoint.
-// Build is NOT IMPLEMENTED, so we manually write the binary representation.
+// The binary representation is written by hand to pin the wire layout.
 func TestBuildParseCheckDailyCafepoint(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -1156,7 +1156,7 @@ func TestBuildParseCastedBinaryPayloadIntegrity(t *testing.T) {
 
 // TestBuildParseOperateRegisterPayloadIntegrity verifies payload integrity through
 // manual-build/Parse for MsgSysOperateRegister.
-// Build is NOT IMPLEMENTED, so we manually write the binary representation.
+// The binary representation is written by hand to pin the wire layout.
 func TestBuildParseOperateRegisterPayloadIntegrity(t *testing.T) {
 	ctx := &clientctx.ClientContext{RealClientMode: cfg.ZZ}
 
@@ -1185,7 +1185,7 @@ func TestBuildParseOperateRegisterPayloadIntegrity(t *testing.T) {
 
 // TestBuildParseArrangeGuildMemberEmptySlice ensures that an empty CharIDs slice
 // round-trips correctly (the uint8 count field should be 0).
-// Build is NOT IMPLEMENTED, so we manually write the binary representation.
+// The binary representation is written by hand to pin the wire layout.
 // Parse reads: uint32 AckHandle, uint32 GuildID, uint8 zeroed, uint8 charCount.
 func TestBuildParseArrangeGuildMemberEmptySlice(t *testing.T) {
 	ctx := &clientctx.ClientContext{RealClientMode: cfg.ZZ}
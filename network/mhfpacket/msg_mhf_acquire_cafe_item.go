@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	cfg "erupe-ce/config"
 
 	"erupe-ce/common/byteframe"
@@ -42,5 +41,15 @@ func (m *MsgMhfAcquireCafeItem) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cl
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfAcquireCafeItem) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint16(m.ItemType)
+	bf.WriteUint16(m.ItemID)
+	bf.WriteUint16(m.Quant)
+	if ctx.RealClientMode >= cfg.G6 {
+		bf.WriteUint32(m.PointCost)
+	} else {
+		bf.WriteUint16(uint16(m.PointCost))
+	}
+	bf.WriteUint16(m.Unk0)
+	return nil
 }
@@ -144,21 +144,31 @@ func TestMsgMhfAcquireCafeItemParseFieldOrder(t *testing.T) {
 	}
 }
 
-func TestMsgMhfAcquireCafeItemBuildNotImplemented(t *testing.T) {
-	pkt := &MsgMhfAcquireCafeItem{
-		AckHandle: 123,
-		ItemType:  1,
-		ItemID:    100,
-		Quant:     5,
-		PointCost: 1000,
-	}
-
-	bf := byteframe.NewByteFrame()
-	ctx := &clientctx.ClientContext{RealClientMode: cfg.ZZ}
-
-	err := pkt.Build(bf, ctx)
-	if err == nil {
-		t.Error("Build() should return error (NOT IMPLEMENTED)")
+func TestMsgMhfAcquireCafeItemBuildRoundTrip(t *testing.T) {
+	for _, mode := range []cfg.Mode{cfg.G5, cfg.ZZ} {
+		pkt := &MsgMhfAcquireCafeItem{
+			AckHandle: 123,
+			ItemType:  1,
+			ItemID:    100,
+			Quant:     5,
+			PointCost: 1000,
+			Unk0:      2,
+		}
+
+		bf := byteframe.NewByteFrame()
+		ctx := &clientctx.ClientContext{RealClientMode: mode}
+
+		if err := pkt.Build(bf, ctx); err != nil {
+			t.Fatalf("Build() error = %v", err)
+		}
+		_, _ = bf.Seek(0, io.SeekStart)
+		parsed := &MsgMhfAcquireCafeItem{}
+		if err := parsed.Parse(bf, ctx); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if *parsed != *pkt {
+			t.Errorf("mode %v: round trip = %+v, want %+v", mode, parsed, pkt)
+		}
 	}
 }
 
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -33,5 +31,10 @@ func (m *MsgMhfAcquireFesta) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clien
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfAcquireFesta) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.FestaID)
+	bf.WriteUint32(m.GuildID)
+	bf.WriteUint8(m.Unk)
+	bf.WriteUint8(0) // Zeroed
+	return nil
 }
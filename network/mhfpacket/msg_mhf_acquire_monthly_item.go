@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -35,5 +33,11 @@ func (m *MsgMhfAcquireMonthlyItem) Parse(bf *byteframe.ByteFrame, ctx *clientctx
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfAcquireMonthlyItem) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.Unk0)
+	bf.WriteUint8(m.Unk1)
+	bf.WriteUint16(m.Unk2)
+	bf.WriteUint32(m.Unk3)
+	bf.WriteUint32(0) // Zeroed
+	return nil
 }
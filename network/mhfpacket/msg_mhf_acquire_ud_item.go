@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -38,13 +36,16 @@ func (m *MsgMhfAcquireUdItem) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clie
 	m.Unk0 = bf.ReadUint8()
 	m.RewardType = bf.ReadUint8()
 	m.ItemIDCount = bf.ReadUint8()
-	for i := uint8(0); i < m.ItemIDCount; i++ {
-		bf.ReadUint32()
-	}
+	m.Unk3 = bf.ReadBytes(uint(m.ItemIDCount) * 4)
 	return nil
 }
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfAcquireUdItem) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.Unk0)
+	bf.WriteUint8(m.RewardType)
+	bf.WriteUint8(m.ItemIDCount)
+	bf.WriteBytes(fixedBytes(m.Unk3, int(m.ItemIDCount)*4))
+	return nil
 }
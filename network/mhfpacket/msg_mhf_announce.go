@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -37,5 +35,15 @@ func (m *MsgMhfAnnounce) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientCon
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfAnnounce) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.IPAddress)
+	bf.WriteUint16(m.Port)
+	bf.WriteUint8(0)
+	bf.WriteUint8(0)
+	bf.WriteUint8(0)
+	bf.WriteBytes(fixedBytes(m.StageID, 32))
+	data := frameBytes(m.Data)
+	bf.WriteUint32(uint32(len(data)))
+	bf.WriteBytes(data)
+	return nil
 }
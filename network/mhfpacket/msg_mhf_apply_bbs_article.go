@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/bfutil"
 	"erupe-ce/common/stringsupport"
 
@@ -38,5 +37,11 @@ func (m *MsgMhfApplyBbsArticle) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cl
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfApplyBbsArticle) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.Unk0)
+	bf.WriteBytes(fixedBytes(m.Unk1, 16))
+	bf.WriteBytes(stringsupport.PaddedString(m.Name, 32, true))
+	bf.WriteBytes(stringsupport.PaddedString(m.Title, 128, true))
+	bf.WriteBytes(stringsupport.PaddedString(m.Description, 256, true))
+	return nil
 }
@@ -1,9 +1,9 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/bfutil"
 	"erupe-ce/common/byteframe"
+	"erupe-ce/common/stringsupport"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
 )
@@ -31,5 +31,9 @@ func (m *MsgMhfApplyCampaign) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clie
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfApplyCampaign) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.CampaignID)
+	bf.WriteUint16(0) // Zeroed
+	bf.WriteBytes(stringsupport.PaddedString(m.Code, 16, false))
+	return nil
 }
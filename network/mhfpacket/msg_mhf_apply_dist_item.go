@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/byteframe"
 	cfg "erupe-ce/config"
 	"erupe-ce/network"
@@ -38,5 +37,14 @@ func (m *MsgMhfApplyDistItem) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clie
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfApplyDistItem) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.DistributionType)
+	bf.WriteUint32(m.DistributionID)
+	if ctx.RealClientMode >= cfg.G8 {
+		bf.WriteUint32(m.Unk2)
+	}
+	if ctx.RealClientMode >= cfg.G10 {
+		bf.WriteUint32(m.Unk3)
+	}
+	return nil
 }
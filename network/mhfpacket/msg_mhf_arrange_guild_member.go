@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -36,5 +35,12 @@ func (m *MsgMhfArrangeGuildMember) Parse(bf *byteframe.ByteFrame, ctx *clientctx
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfArrangeGuildMember) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.GuildID)
+	bf.WriteUint8(0) // Zeroed
+	bf.WriteUint8(uint8(len(m.CharIDs)))
+	for _, cid := range m.CharIDs {
+		bf.WriteUint32(cid)
+	}
+	return nil
 }
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -36,5 +34,13 @@ func (m *MsgMhfChargeFesta) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Client
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfChargeFesta) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.FestaID)
+	bf.WriteUint32(m.GuildID)
+	bf.WriteUint16(uint16(len(m.Souls)))
+	for _, soul := range m.Souls {
+		bf.WriteUint16(soul)
+	}
+	bf.WriteBool(m.Auto)
+	return nil
 }
@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -36,5 +35,9 @@ func (m *MsgMhfCheckWeeklyStamp) Parse(bf *byteframe.ByteFrame, ctx *clientctx.C
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfCheckWeeklyStamp) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(weeklyStampTypeID(m.StampType))
+	bf.WriteBool(m.Unk1)
+	bf.WriteUint16(0) // Zeroed
+	return nil
 }
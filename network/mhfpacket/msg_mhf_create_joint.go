@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/common/stringsupport"
 	"erupe-ce/network"
@@ -33,5 +31,11 @@ func (m *MsgMhfCreateJoint) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Client
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfCreateJoint) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.GuildID)
+	bf.WriteUint16(0) // Zeroed
+	name := stringsupport.UTF8ToSJIS(m.Name)
+	bf.WriteUint16(uint16(len(name) + 1))
+	bf.WriteNullTerminatedBytes(name)
+	return nil
 }
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -59,5 +57,14 @@ func (m *MsgMhfEnterTournamentQuest) Parse(bf *byteframe.ByteFrame, ctx *clientc
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfEnterTournamentQuest) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.TournamentID)
+	bf.WriteUint32(m.EntryHandle)
+	bf.WriteUint32(m.Unk2)
+	bf.WriteUint32(m.QuestSlot)
+	bf.WriteUint32(m.StageHandle)
+	bf.WriteUint32(m.Unk5)
+	bf.WriteUint8(uint8(len(m.String)))
+	bf.WriteBytes(m.String)
+	return nil
 }
@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/byteframe"
 	cfg "erupe-ce/config"
 	"erupe-ce/network"
@@ -36,5 +35,13 @@ func (m *MsgMhfEnumerateDistItem) Parse(bf *byteframe.ByteFrame, ctx *clientctx.
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfEnumerateDistItem) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.DistType)
+	bf.WriteUint8(m.Unk1)
+	bf.WriteUint16(m.MaxCount)
+	if ctx.RealClientMode >= cfg.Z1 {
+		bf.WriteUint8(uint8(len(m.Unk3)))
+		bf.WriteBytes(m.Unk3)
+	}
+	return nil
 }
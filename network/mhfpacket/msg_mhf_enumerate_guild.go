@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -59,5 +58,16 @@ func (m *MsgMhfEnumerateGuild) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cli
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfEnumerateGuild) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(uint8(m.Type))
+	bf.WriteUint8(m.Page)
+	bf.WriteBool(m.Sorting)
+	bf.WriteUint8(0) // Zeroed
+	bf.WriteBytes(fixedBytes(frameBytes(m.Data1), 4))
+	bf.WriteUint16(0) // Zeroed
+	data2 := frameBytes(m.Data2)
+	bf.WriteUint8(uint8(len(data2)))
+	bf.WriteUint8(0) // Zeroed
+	bf.WriteBytes(data2)
+	return nil
 }
@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/stringsupport"
 
 	"erupe-ce/common/byteframe"
@@ -37,5 +36,14 @@ func (m *MsgMhfEnumerateHouse) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cli
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfEnumerateHouse) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.CharID)
+	bf.WriteUint8(m.Method)
+	bf.WriteUint16(0) // Zeroed
+	if m.Name == "" {
+		bf.WriteUint8(0)
+	} else {
+		writeSJISNullTerminated8(bf, m.Name)
+	}
+	return nil
 }
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -38,5 +36,12 @@ func (m *MsgMhfEnumerateInvGuild) Parse(bf *byteframe.ByteFrame, ctx *clientctx.
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfEnumerateInvGuild) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.Unk)
+	bf.WriteUint8(m.Operation)
+	bf.WriteUint8(m.ActiveHours)
+	bf.WriteUint8(m.DaysActive)
+	bf.WriteUint8(m.PlayStyle)
+	bf.WriteUint8(m.GuildRequest)
+	return nil
 }
@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	cfg "erupe-ce/config"
 
 	"erupe-ce/common/byteframe"
@@ -41,5 +40,15 @@ func (m *MsgMhfEnumerateQuest) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cli
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfEnumerateQuest) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.Unk0)
+	bf.WriteUint8(m.World)
+	bf.WriteUint16(m.Counter)
+	if ctx.RealClientMode <= cfg.Z1 {
+		bf.WriteUint8(uint8(m.Offset))
+	} else {
+		bf.WriteUint16(m.Offset)
+	}
+	bf.WriteUint8(m.Unk4)
+	return nil
 }
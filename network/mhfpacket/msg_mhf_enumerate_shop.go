@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	cfg "erupe-ce/config"
 
 	"erupe-ce/common/byteframe"
@@ -41,5 +40,14 @@ func (m *MsgMhfEnumerateShop) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clie
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfEnumerateShop) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.ShopType)
+	bf.WriteUint32(m.ShopID)
+	bf.WriteUint16(m.Limit)
+	bf.WriteUint8(m.Unk3)
+	if ctx.RealClientMode >= cfg.G2 {
+		bf.WriteUint8(m.Unk4)
+		bf.WriteUint32(m.Unk5)
+	}
+	return nil
 }
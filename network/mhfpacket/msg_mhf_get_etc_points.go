@@ -24,5 +24,6 @@ func (m *MsgMhfGetEtcPoints) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clien
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfGetEtcPoints) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
+	bf.WriteUint32(m.AckHandle)
 	return nil
 }
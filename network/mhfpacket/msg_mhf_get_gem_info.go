@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -40,5 +38,13 @@ func (m *MsgMhfGetGemInfo) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientC
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfGetGemInfo) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.QueryType)
+	bf.WriteUint32(m.Unk1)
+	bf.WriteInt32(m.Unk2)
+	bf.WriteInt32(m.Unk3)
+	bf.WriteInt32(m.Unk4)
+	bf.WriteInt32(m.Unk5)
+	bf.WriteInt32(m.Unk6)
+	return nil
 }
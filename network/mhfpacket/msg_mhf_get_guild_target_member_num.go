@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -30,5 +28,8 @@ func (m *MsgMhfGetGuildTargetMemberNum) Parse(bf *byteframe.ByteFrame, ctx *clie
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfGetGuildTargetMemberNum) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.GuildID)
+	bf.WriteUint8(m.Unk)
+	return nil
 }
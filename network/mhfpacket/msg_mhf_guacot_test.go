@@ -1,6 +1,7 @@
 package mhfpacket
 
 import (
+	"io"
 	"testing"
 
 	"erupe-ce/common/byteframe"
@@ -221,19 +222,36 @@ func TestMsgMhfEnumerateGuacotParse(t *testing.T) {
 	}
 }
 
-func TestMsgMhfUpdateGuacotBuild_NotImplemented(t *testing.T) {
-	pkt := &MsgMhfUpdateGuacot{}
-	err := pkt.Build(byteframe.NewByteFrame(), &clientctx.ClientContext{RealClientMode: cfg.ZZ})
-	if err == nil {
-		t.Error("Build() should return error (not implemented)")
+func TestMsgMhfUpdateGuacotBuild_RoundTrip(t *testing.T) {
+	ctx := &clientctx.ClientContext{RealClientMode: cfg.ZZ}
+	pkt := &MsgMhfUpdateGuacot{
+		AckHandle:  1,
+		EntryCount: 1,
+		Goocoos: []Goocoo{{
+			Index: 2,
+			Data1: make([]int16, 22),
+			Data2: []uint32{3, 4},
+			Name:  []byte("Goo"),
+		}},
+	}
+	bf := byteframe.NewByteFrame()
+	if err := pkt.Build(bf, ctx); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	_, _ = bf.Seek(0, io.SeekStart)
+	parsed := &MsgMhfUpdateGuacot{}
+	if err := parsed.Parse(bf, ctx); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(parsed.Goocoos) != 1 || parsed.Goocoos[0].Index != 2 || string(parsed.Goocoos[0].Name) != "Goo" {
+		t.Errorf("round trip = %+v", parsed.Goocoos)
 	}
 }
 
-func TestMsgMhfEnumerateGuacotBuild_NotImplemented(t *testing.T) {
+func TestMsgMhfEnumerateGuacotBuild(t *testing.T) {
 	pkt := &MsgMhfEnumerateGuacot{}
-	err := pkt.Build(byteframe.NewByteFrame(), &clientctx.ClientContext{RealClientMode: cfg.ZZ})
-	if err == nil {
-		t.Error("Build() should return error (not implemented)")
+	if err := pkt.Build(byteframe.NewByteFrame(), &clientctx.ClientContext{RealClientMode: cfg.ZZ}); err != nil {
+		t.Errorf("Build() error = %v", err)
 	}
 }
 
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -31,5 +29,10 @@ func (m *MsgMhfInfoFesta) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientCo
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfInfoFesta) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.Unk0)
+	bf.WriteUint8(0) // Zeroed
+	bf.WriteUint8(0) // Zeroed
+	bf.WriteUint8(0) // Zeroed
+	return nil
 }
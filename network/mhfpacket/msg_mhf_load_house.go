@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/stringsupport"
 
 	"erupe-ce/common/byteframe"
@@ -38,5 +37,11 @@ func (m *MsgMhfLoadHouse) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientCo
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfLoadHouse) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.CharID)
+	bf.WriteUint8(m.Destination)
+	bf.WriteBool(m.CheckPass)
+	bf.WriteUint16(0) // Zeroed
+	writeSJISNullTerminated8(bf, m.Password)
+	return nil
 }
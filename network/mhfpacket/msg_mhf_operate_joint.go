@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -48,5 +46,13 @@ func (m *MsgMhfOperateJoint) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clien
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfOperateJoint) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.AllianceID)
+	bf.WriteUint32(m.GuildID)
+	bf.WriteUint8(uint8(m.Action))
+	data2 := frameBytes(m.Data2)
+	bf.WriteUint8(uint8(len(data2)))
+	bf.WriteBytes(fixedBytes(frameBytes(m.Data1), 4))
+	bf.WriteBytes(data2)
+	return nil
 }
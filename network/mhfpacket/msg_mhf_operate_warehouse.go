@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/stringsupport"
 
 	"erupe-ce/common/byteframe"
@@ -39,5 +38,19 @@ func (m *MsgMhfOperateWarehouse) Parse(bf *byteframe.ByteFrame, ctx *clientctx.C
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfOperateWarehouse) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.Operation)
+	bf.WriteUint8(m.BoxType)
+	bf.WriteUint8(m.BoxIndex)
+	name := stringsupport.UTF8ToSJIS(m.Name)
+	if len(name) > 0 {
+		bf.WriteUint8(uint8(len(name) + 1))
+	} else {
+		bf.WriteUint8(0)
+	}
+	bf.WriteUint16(0) // Zeroed
+	if len(name) > 0 {
+		bf.WriteNullTerminatedBytes(name)
+	}
+	return nil
 }
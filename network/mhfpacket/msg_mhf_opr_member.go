@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -36,5 +34,13 @@ func (m *MsgMhfOprMember) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientCo
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfOprMember) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteBool(m.Blacklist)
+	bf.WriteBool(m.Operation)
+	bf.WriteUint8(0)
+	bf.WriteUint8(uint8(len(m.CharIDs)))
+	for _, cid := range m.CharIDs {
+		bf.WriteUint32(cid)
+	}
+	return nil
 }
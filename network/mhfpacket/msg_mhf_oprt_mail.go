@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -51,5 +49,14 @@ func (m *MsgMhfOprtMail) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientCon
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfOprtMail) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.AccIndex)
+	bf.WriteUint8(m.Index)
+	bf.WriteUint8(uint8(m.Operation))
+	bf.WriteUint8(0) // Zeroed
+	if m.Operation == OperateMailAcquireItem {
+		bf.WriteUint16(m.Amount)
+		bf.WriteUint16(m.ItemID)
+	}
+	return nil
 }
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -40,5 +38,13 @@ func (m *MsgMhfPostGemInfo) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Client
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfPostGemInfo) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.Op)
+	bf.WriteUint32(m.Unk1)
+	bf.WriteInt32(m.Gem)
+	bf.WriteInt32(m.Quantity)
+	bf.WriteInt32(m.CID)
+	bf.WriteInt32(m.Message)
+	bf.WriteInt32(m.Unk6)
+	return nil
 }
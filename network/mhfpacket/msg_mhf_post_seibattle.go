@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -40,5 +38,13 @@ func (m *MsgMhfPostSeibattle) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clie
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfPostSeibattle) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.Unk0)
+	bf.WriteUint8(m.Unk1)
+	bf.WriteUint32(m.Unk2)
+	bf.WriteUint8(m.Unk3)
+	bf.WriteUint16(m.Unk4)
+	bf.WriteUint16(m.Unk5)
+	bf.WriteUint8(m.Unk6)
+	return nil
 }
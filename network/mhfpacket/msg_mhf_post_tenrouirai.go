@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -66,5 +64,28 @@ func (m *MsgMhfPostTenrouirai) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cli
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfPostTenrouirai) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.Unk0)
+	bf.WriteUint8(m.Op)
+	bf.WriteUint32(m.GuildID)
+	bf.WriteUint8(m.Unk1)
+
+	switch m.Op {
+	case 1:
+		bf.WriteUint16(m.Floors)
+		bf.WriteUint16(m.Antiques)
+		bf.WriteUint16(m.Chests)
+		bf.WriteUint16(m.Cats)
+		bf.WriteUint16(m.TRP)
+		bf.WriteUint16(m.Slays)
+	case 2:
+		bf.WriteUint16(m.DonatedRP)
+		bf.WriteUint16(m.PreviousRP)
+		bf.WriteUint16(m.Unk2_0)
+		bf.WriteUint16(m.Unk2_1)
+		bf.WriteUint16(m.Unk2_2)
+		bf.WriteUint16(m.Unk2_3)
+	}
+
+	return nil
 }
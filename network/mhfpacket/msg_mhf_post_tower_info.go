@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -46,5 +44,16 @@ func (m *MsgMhfPostTowerInfo) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clie
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfPostTowerInfo) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.InfoType)
+	bf.WriteUint32(m.Unk1)
+	bf.WriteInt32(m.Skill)
+	bf.WriteInt32(m.TR)
+	bf.WriteInt32(m.TRP)
+	bf.WriteInt32(m.Cost)
+	bf.WriteInt32(m.Unk6)
+	bf.WriteInt32(m.Unk7)
+	bf.WriteInt32(m.Block1)
+	bf.WriteInt64(m.Unk9)
+	return nil
 }
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -44,5 +42,16 @@ func (m *MsgMhfPresentBox) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientC
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfPresentBox) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.Unk0)
+	bf.WriteUint32(m.Unk1)
+	bf.WriteUint32(uint32(len(m.Unk7)))
+	bf.WriteUint32(m.Unk3)
+	bf.WriteUint32(m.Unk4)
+	bf.WriteUint32(m.Unk5)
+	bf.WriteUint32(m.Unk6)
+	for _, v := range m.Unk7 {
+		bf.WriteUint32(v)
+	}
+	return nil
 }
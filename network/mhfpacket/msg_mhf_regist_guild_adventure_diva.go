@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -31,5 +29,9 @@ func (m *MsgMhfRegistGuildAdventureDiva) Parse(bf *byteframe.ByteFrame, ctx *cli
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfRegistGuildAdventureDiva) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.Destination)
+	bf.WriteUint32(m.Charge)
+	bf.WriteUint32(0) // CharID
+	return nil
 }
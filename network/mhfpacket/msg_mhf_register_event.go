@@ -33,5 +33,11 @@ func (m *MsgMhfRegisterEvent) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clie
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfRegisterEvent) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint16(m.Unk0)
+	bf.WriteUint16(m.WorldID)
+	bf.WriteUint16(m.LandID)
+	bf.WriteBool(m.CheckOnly)
+	bf.WriteUint8(0) // Zeroed
 	return nil
 }
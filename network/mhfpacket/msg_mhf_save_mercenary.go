@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -35,5 +33,12 @@ func (m *MsgMhfSaveMercenary) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clie
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfSaveMercenary) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(uint32(len(m.MercData) + 16)) // lenData
+	bf.WriteUint32(m.GCP)
+	bf.WriteUint32(m.PactMercID)
+	bf.WriteUint32(uint32(len(m.MercData)))
+	bf.WriteUint32(0) // Merc index?
+	bf.WriteBytes(m.MercData)
+	return nil
 }
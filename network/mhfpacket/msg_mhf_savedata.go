@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	cfg "erupe-ce/config"
 
 	"erupe-ce/common/byteframe"
@@ -43,5 +42,22 @@ func (m *MsgMhfSavedata) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientCon
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfSavedata) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	if ctx.RealClientMode >= cfg.G1 && m.DataSize != 0 {
+		bf.WriteUint32(m.AllocMemSize)
+		bf.WriteUint8(m.SaveType)
+		bf.WriteUint32(m.Unk1)
+		bf.WriteUint32(uint32(len(m.RawDataPayload)))
+	} else {
+		// A zero DataSize (or a pre-G client, which never sends one) means
+		// the payload length is carried by AllocMemSize instead.
+		bf.WriteUint32(uint32(len(m.RawDataPayload)))
+		bf.WriteUint8(m.SaveType)
+		bf.WriteUint32(m.Unk1)
+		if ctx.RealClientMode >= cfg.G1 {
+			bf.WriteUint32(0)
+		}
+	}
+	bf.WriteBytes(m.RawDataPayload)
+	return nil
 }
@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/stringsupport"
 
 	"erupe-ce/common/byteframe"
@@ -42,5 +41,16 @@ func (m *MsgMhfSendMail) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientCon
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfSendMail) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	subject := stringsupport.UTF8ToSJIS(m.Subject)
+	body := stringsupport.UTF8ToSJIS(m.Body)
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.RecipientID)
+	bf.WriteUint16(uint16(len(subject)))
+	bf.WriteUint16(uint16(len(body)))
+	bf.WriteUint16(0) // Zeroed
+	bf.WriteUint16(m.Quantity)
+	bf.WriteUint16(m.ItemID)
+	bf.WriteNullTerminatedBytes(subject)
+	bf.WriteNullTerminatedBytes(body)
+	return nil
 }
@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	cfg "erupe-ce/config"
 
 	"erupe-ce/common/byteframe"
@@ -53,5 +52,20 @@ func (m *MsgMhfStampcardStamp) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cli
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfStampcardStamp) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint16(m.HR)
+	if ctx.RealClientMode >= cfg.G1 {
+		bf.WriteUint16(m.GR)
+	}
+	bf.WriteUint16(m.Stamps)
+	bf.WriteUint16(0) // Zeroed
+	if ctx.RealClientMode >= cfg.Z2 {
+		bf.WriteUint32(uint32(m.Reward1))
+		bf.WriteUint32(uint32(m.Reward2))
+		bf.WriteUint32(uint32(m.Item1))
+		bf.WriteUint32(uint32(m.Item2))
+		bf.WriteUint32(uint32(m.Quantity1))
+		bf.WriteUint32(uint32(m.Quantity2))
+	}
+	return nil
 }
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -38,5 +36,17 @@ func (m *MsgMhfUpdateBeatLevel) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cl
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfUpdateBeatLevel) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.Unk1)
+	bf.WriteUint32(m.Unk2)
+	for _, data := range [][]int32{m.Data1, m.Data2} {
+		for i := 0; i < 16; i++ {
+			var v int32
+			if i < len(data) {
+				v = data[i]
+			}
+			bf.WriteInt32(v)
+		}
+	}
+	return nil
 }
@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -49,5 +48,27 @@ func (m *MsgMhfUpdateGuacot) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Clien
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfUpdateGuacot) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint16(uint16(len(m.Goocoos)))
+	bf.WriteUint16(0) // Zeroed
+	for _, goocoo := range m.Goocoos {
+		bf.WriteUint32(goocoo.Index)
+		for j := 0; j < 22; j++ {
+			var v int16
+			if j < len(goocoo.Data1) {
+				v = goocoo.Data1[j]
+			}
+			bf.WriteInt16(v)
+		}
+		for j := 0; j < 2; j++ {
+			var v uint32
+			if j < len(goocoo.Data2) {
+				v = goocoo.Data2[j]
+			}
+			bf.WriteUint32(v)
+		}
+		bf.WriteUint8(uint8(len(goocoo.Name)))
+		bf.WriteBytes(goocoo.Name)
+	}
+	return nil
 }
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -63,5 +61,24 @@ func (m *MsgMhfUpdateGuildIcon) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cl
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfUpdateGuildIcon) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.GuildID)
+	bf.WriteUint16(uint16(len(m.IconParts)))
+	bf.WriteUint8(0) // Zeroed
+	bf.WriteUint8(0) // Zeroed
+
+	for _, part := range m.IconParts {
+		bf.WriteUint16(part.Index)
+		bf.WriteUint16(part.ID)
+		bf.WriteUint8(part.Page)
+		bf.WriteUint8(part.Size)
+		bf.WriteUint8(part.Rotation)
+		bf.WriteUint8(part.Red)
+		bf.WriteUint8(part.Green)
+		bf.WriteUint8(part.Blue)
+		bf.WriteUint16(part.PosX)
+		bf.WriteUint16(part.PosY)
+	}
+
+	return nil
 }
@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/stringsupport"
 
 	"erupe-ce/common/byteframe"
@@ -63,5 +62,35 @@ func (m *MsgMhfUpdateGuildMessageBoard) Parse(bf *byteframe.ByteFrame, ctx *clie
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfUpdateGuildMessageBoard) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	title := stringsupport.UTF8ToSJIS(m.Title)
+	body := stringsupport.UTF8ToSJIS(m.Body)
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.MessageOp)
+	switch m.MessageOp {
+	case 0:
+		bf.WriteUint32(m.PostType)
+		bf.WriteUint32(m.StampID)
+		bf.WriteUint32(uint32(len(title)))
+		bf.WriteUint32(uint32(len(body)))
+		bf.WriteBytes(title)
+		bf.WriteBytes(body)
+	case 1:
+		bf.WriteUint32(m.PostID)
+	case 2:
+		bf.WriteUint32(m.PostID)
+		bf.WriteBytes(make([]byte, 8))
+		bf.WriteUint32(uint32(len(title)))
+		bf.WriteUint32(uint32(len(body)))
+		bf.WriteBytes(title)
+		bf.WriteBytes(body)
+	case 3:
+		bf.WriteUint32(m.PostID)
+		bf.WriteBytes(make([]byte, 8))
+		bf.WriteUint32(m.StampID)
+	case 4:
+		bf.WriteUint32(m.PostID)
+		bf.WriteBytes(make([]byte, 8))
+		bf.WriteBool(m.LikeState)
+	}
+	return nil
 }
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	cfg "erupe-ce/config"
 	"erupe-ce/network"
@@ -38,5 +36,15 @@ func (m *MsgMhfUpdateMyhouseInfo) Parse(bf *byteframe.ByteFrame, ctx *clientctx.
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfUpdateMyhouseInfo) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	if ctx.RealClientMode >= cfg.G10 {
+		bf.WriteBytes(fixedBytes(m.Data, 362))
+	} else if ctx.RealClientMode >= cfg.GG {
+		bf.WriteBytes(fixedBytes(m.Data, 338))
+	} else if ctx.RealClientMode >= cfg.F5 {
+		bf.WriteBytes(fixedBytes(m.Data, 314))
+	} else {
+		bf.WriteBytes(fixedBytes(m.Data, 290))
+	}
+	return nil
 }
@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/byteframe"
 	"erupe-ce/common/mhfitem"
 	"erupe-ce/network"
@@ -43,5 +42,28 @@ func (m *MsgMhfUpdateWarehouse) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cl
 
 // Build builds a binary packet from the current data.
 func (m *MsgMhfUpdateWarehouse) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.BoxType)
+	bf.WriteUint8(m.BoxIndex)
+	switch m.BoxType {
+	case 0:
+		bf.WriteUint16(uint16(len(m.UpdatedItems)))
+	case 1:
+		bf.WriteUint16(uint16(len(m.UpdatedEquipment)))
+	default:
+		bf.WriteUint16(0)
+	}
+	bf.WriteUint8(0) // Zeroed
+	bf.WriteUint8(0) // Zeroed
+	switch m.BoxType {
+	case 0:
+		for _, item := range m.UpdatedItems {
+			bf.WriteBytes(item.ToBytes())
+		}
+	case 1:
+		for _, equip := range m.UpdatedEquipment {
+			bf.WriteBytes(equip.ToBytes(ctx.RealClientMode))
+		}
+	}
+	return nil
 }
@@ -1,7 +1,9 @@
 package mhfpacket
 
 import (
+	"io"
 	"testing"
+	"time"
 
 	"erupe-ce/common/byteframe"
 	"erupe-ce/common/mhfcourse"
@@ -342,16 +344,30 @@ func TestParseCoverage_EmptyPackets(t *testing.T) {
 	})
 }
 
-// TestParseCoverage_NotImplemented2 tests Parse/Build for packets that return NOT IMPLEMENTED.
-func TestParseCoverage_NotImplemented2(t *testing.T) {
+// TestParseCoverage_UpdateRight tests MsgSysUpdateRight.Parse against its Build output.
+func TestParseCoverage_UpdateRight(t *testing.T) {
 	ctx := &clientctx.ClientContext{RealClientMode: cfg.ZZ}
 
 	t.Run("MsgSysUpdateRight_Parse", func(t *testing.T) {
 		bf := byteframe.NewByteFrame()
-		pkt := &MsgSysUpdateRight{}
-		err := pkt.Parse(bf, ctx)
-		if err == nil {
-			t.Error("expected NOT IMPLEMENTED error")
+		pkt := &MsgSysUpdateRight{
+			ClientRespAckHandle: 1,
+			Bitfield:            0x0E,
+			Rights:              []mhfcourse.Course{{ID: 1}, {ID: 2, Expiry: time.Unix(1700000000, 0)}},
+		}
+		if err := pkt.Build(bf, ctx); err != nil {
+			t.Fatalf("Build() error: %v", err)
+		}
+		_, _ = bf.Seek(0, io.SeekStart)
+		parsed := &MsgSysUpdateRight{}
+		if err := parsed.Parse(bf, ctx); err != nil {
+			t.Fatalf("Parse() error: %v", err)
+		}
+		if parsed.Bitfield != 0x0E || len(parsed.Rights) != 2 {
+			t.Fatalf("parsed = %+v", parsed)
+		}
+		if !parsed.Rights[0].Expiry.IsZero() || !parsed.Rights[1].Expiry.Equal(pkt.Rights[1].Expiry) {
+			t.Errorf("Rights = %+v, want %+v", parsed.Rights, pkt.Rights)
 		}
 	})
 }
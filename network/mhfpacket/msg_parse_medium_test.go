@@ -730,37 +730,35 @@ func TestParseMediumAckHandleOnlyVerifyValues(t *testing.T) {
 	})
 }
 
-// TestParseMediumDeleteUser verifies that MsgSysDeleteUser.Parse returns
-// NOT IMPLEMENTED error (Parse is not implemented, only Build is).
+// TestParseMediumDeleteUser verifies that MsgSysDeleteUser.Parse reads the
+// character ID written by Build.
 func TestParseMediumDeleteUser(t *testing.T) {
 	bf := byteframe.NewByteFrame()
 	bf.WriteUint32(12345)
 	_, _ = bf.Seek(0, io.SeekStart)
 
 	pkt := &MsgSysDeleteUser{}
-	err := pkt.Parse(bf, &clientctx.ClientContext{RealClientMode: cfg.ZZ})
-	if err == nil {
-		t.Fatal("Parse() should return error for NOT IMPLEMENTED")
+	if err := pkt.Parse(bf, &clientctx.ClientContext{RealClientMode: cfg.ZZ}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
 	}
-	if err.Error() != "NOT IMPLEMENTED" {
-		t.Errorf("Parse() error = %q, want %q", err.Error(), "NOT IMPLEMENTED")
+	if pkt.CharID != 12345 {
+		t.Errorf("CharID = %d, want 12345", pkt.CharID)
 	}
 }
 
-// TestParseMediumInsertUser verifies that MsgSysInsertUser.Parse returns
-// NOT IMPLEMENTED error (Parse is not implemented, only Build is).
+// TestParseMediumInsertUser verifies that MsgSysInsertUser.Parse reads the
+// character ID written by Build.
 func TestParseMediumInsertUser(t *testing.T) {
 	bf := byteframe.NewByteFrame()
 	bf.WriteUint32(12345)
 	_, _ = bf.Seek(0, io.SeekStart)
 
 	pkt := &MsgSysInsertUser{}
-	err := pkt.Parse(bf, &clientctx.ClientContext{RealClientMode: cfg.ZZ})
-	if err == nil {
-		t.Fatal("Parse() should return error for NOT IMPLEMENTED")
+	if err := pkt.Parse(bf, &clientctx.ClientContext{RealClientMode: cfg.ZZ}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
 	}
-	if err.Error() != "NOT IMPLEMENTED" {
-		t.Errorf("Parse() error = %q, want %q", err.Error(), "NOT IMPLEMENTED")
+	if pkt.CharID != 12345 {
+		t.Errorf("CharID = %d, want 12345", pkt.CharID)
 	}
 }
 
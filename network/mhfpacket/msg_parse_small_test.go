@@ -36,16 +36,11 @@ func TestParseSmallNotImplemented(t *testing.T) {
 		{"MsgSysAuthData", &MsgSysAuthData{}},
 		{"MsgSysAuthQuery", &MsgSysAuthQuery{}},
 		{"MsgSysAuthTerminal", &MsgSysAuthTerminal{}},
-		{"MsgSysCloseMutex", &MsgSysCloseMutex{}},
 		{"MsgSysCollectBinary", &MsgSysCollectBinary{}},
-		{"MsgSysCreateMutex", &MsgSysCreateMutex{}},
-		{"MsgSysCreateOpenMutex", &MsgSysCreateOpenMutex{}},
-		{"MsgSysDeleteMutex", &MsgSysDeleteMutex{}},
 		{"MsgSysEnumlobby", &MsgSysEnumlobby{}},
 		{"MsgSysEnumuser", &MsgSysEnumuser{}},
 		{"MsgSysGetState", &MsgSysGetState{}},
 		{"MsgSysInfokyserver", &MsgSysInfokyserver{}},
-		{"MsgSysOpenMutex", &MsgSysOpenMutex{}},
 		{"MsgSysSerialize", &MsgSysSerialize{}},
 		{"MsgSysTransBinary", &MsgSysTransBinary{}},
 	}
@@ -238,6 +233,41 @@ func TestParseSmallRotateObject(t *testing.T) {
 	}
 }
 
+// TestParseSmallAddObject tests Parse for MsgSysAddObject (ObjID + X/Y/Z).
+func TestParseSmallAddObject(t *testing.T) {
+	ctx := &clientctx.ClientContext{RealClientMode: cfg.ZZ}
+	bf := byteframe.NewByteFrame()
+	bf.WriteUint32(0x1003)
+	bf.WriteFloat32(1.0)
+	bf.WriteFloat32(-2.0)
+	bf.WriteFloat32(3.5)
+	_, _ = bf.Seek(0, io.SeekStart)
+
+	pkt := &MsgSysAddObject{}
+	if err := pkt.Parse(bf, ctx); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if pkt.ObjID != 0x1003 || pkt.X != 1.0 || pkt.Y != -2.0 || pkt.Z != 3.5 {
+		t.Errorf("got ObjID=0x%X pos=(%v, %v, %v), want 0x1003 (1, -2, 3.5)", pkt.ObjID, pkt.X, pkt.Y, pkt.Z)
+	}
+}
+
+// TestParseSmallHideObject tests Parse for MsgSysHideObject (ObjID only).
+func TestParseSmallHideObject(t *testing.T) {
+	ctx := &clientctx.ClientContext{RealClientMode: cfg.ZZ}
+	bf := byteframe.NewByteFrame()
+	bf.WriteUint32(0x1004)
+	_, _ = bf.Seek(0, io.SeekStart)
+
+	pkt := &MsgSysHideObject{}
+	if err := pkt.Parse(bf, ctx); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if pkt.ObjID != 0x1004 {
+		t.Errorf("ObjID = 0x%X, want 0x1004", pkt.ObjID)
+	}
+}
+
 // TestParseSmallGetObjectOwner tests Parse for MsgSysGetObjectOwner (AckHandle + ObjID).
 func TestParseSmallGetObjectOwner(t *testing.T) {
 	ctx := &clientctx.ClientContext{RealClientMode: cfg.ZZ}
@@ -1,15 +1,19 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
 )
 
 // MsgSysAddObject represents the MSG_SYS_ADD_OBJECT
-type MsgSysAddObject struct{}
+//
+// Unlike MsgSysCreateObject it carries a handle the client already assigned
+// and expects no ack; the position follows the MsgSysPositionObject layout.
+type MsgSysAddObject struct {
+	ObjID   uint32
+	X, Y, Z float32
+}
 
 // Opcode returns the ID associated with this packet type.
 func (m *MsgSysAddObject) Opcode() network.PacketID {
@@ -18,10 +22,18 @@ func (m *MsgSysAddObject) Opcode() network.PacketID {
 
 // Parse parses the packet from binary
 func (m *MsgSysAddObject) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	m.ObjID = bf.ReadUint32()
+	m.X = bf.ReadFloat32()
+	m.Y = bf.ReadFloat32()
+	m.Z = bf.ReadFloat32()
+	return nil
 }
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysAddObject) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.ObjID)
+	bf.WriteFloat32(m.X)
+	bf.WriteFloat32(m.Y)
+	bf.WriteFloat32(m.Z)
+	return nil
 }
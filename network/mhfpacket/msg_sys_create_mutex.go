@@ -1,15 +1,20 @@
 package mhfpacket
 
 import (
-	"errors"
-
+	"erupe-ce/common/bfutil"
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
 )
 
+// The mutex packets share the semaphore packets' layout: an ack handle
+// followed by a uint8 length-prefixed, null-terminated name.
+
 // MsgSysCreateMutex represents the MSG_SYS_CREATE_MUTEX
-type MsgSysCreateMutex struct{}
+type MsgSysCreateMutex struct {
+	AckHandle uint32
+	MutexName string
+}
 
 // Opcode returns the ID associated with this packet type.
 func (m *MsgSysCreateMutex) Opcode() network.PacketID {
@@ -18,10 +23,15 @@ func (m *MsgSysCreateMutex) Opcode() network.PacketID {
 
 // Parse parses the packet from binary
 func (m *MsgSysCreateMutex) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	m.AckHandle = bf.ReadUint32()
+	nameLength := bf.ReadUint8()
+	m.MutexName = string(bfutil.UpToNull(bf.ReadBytes(uint(nameLength))))
+	return nil
 }
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysCreateMutex) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	writeNullTerminated8(bf, m.MutexName)
+	return nil
 }
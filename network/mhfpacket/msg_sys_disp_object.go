@@ -1,15 +1,15 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
 )
 
 // MsgSysDispObject represents the MSG_SYS_DISP_OBJECT
-type MsgSysDispObject struct{}
+type MsgSysDispObject struct {
+	ObjID uint32
+}
 
 // Opcode returns the ID associated with this packet type.
 func (m *MsgSysDispObject) Opcode() network.PacketID {
@@ -18,10 +18,12 @@ func (m *MsgSysDispObject) Opcode() network.PacketID {
 
 // Parse parses the packet from binary
 func (m *MsgSysDispObject) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	m.ObjID = bf.ReadUint32()
+	return nil
 }
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysDispObject) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.ObjID)
+	return nil
 }
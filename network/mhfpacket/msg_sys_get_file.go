@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/bfutil"
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
@@ -64,5 +62,19 @@ func (m *MsgSysGetFile) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientCont
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysGetFile) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteBool(m.IsScenario)
+	if m.Filename == "" {
+		bf.WriteUint8(0)
+	} else {
+		writeNullTerminated8(bf, m.Filename)
+	}
+
+	if m.IsScenario {
+		bf.WriteUint8(m.ScenarioIdentifer.CategoryID)
+		bf.WriteUint32(m.ScenarioIdentifer.MainID)
+		bf.WriteUint8(m.ScenarioIdentifer.ChapterID)
+		bf.WriteUint8(m.ScenarioIdentifer.Flags)
+	}
+	return nil
 }
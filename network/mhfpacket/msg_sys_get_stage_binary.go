@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"fmt"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -35,5 +33,10 @@ func (m *MsgSysGetStageBinary) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cli
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysGetStageBinary) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return fmt.Errorf("MsgSysGetStageBinary.Build: not implemented")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.BinaryType0)
+	bf.WriteUint8(m.BinaryType1)
+	bf.WriteUint32(m.Unk0)
+	writeNullTerminated8(bf, m.StageID)
+	return nil
 }
@@ -1,15 +1,15 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
 )
 
 // MsgSysHideObject represents the MSG_SYS_HIDE_OBJECT
-type MsgSysHideObject struct{}
+type MsgSysHideObject struct {
+	ObjID uint32
+}
 
 // Opcode returns the ID associated with this packet type.
 func (m *MsgSysHideObject) Opcode() network.PacketID {
@@ -18,10 +18,12 @@ func (m *MsgSysHideObject) Opcode() network.PacketID {
 
 // Parse parses the packet from binary
 func (m *MsgSysHideObject) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	m.ObjID = bf.ReadUint32()
+	return nil
 }
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysHideObject) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.ObjID)
+	return nil
 }
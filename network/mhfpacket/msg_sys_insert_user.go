@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -20,7 +18,8 @@ func (m *MsgSysInsertUser) Opcode() network.PacketID {
 
 // Parse parses the packet from binary
 func (m *MsgSysInsertUser) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	m.CharID = bf.ReadUint32()
+	return nil
 }
 
 // Build builds a binary packet from the current data.
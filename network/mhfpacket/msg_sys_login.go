@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -40,5 +38,14 @@ func (m *MsgSysLogin) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientContex
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysLogin) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.CharID0)
+	bf.WriteUint32(m.LoginTokenNumber)
+	bf.WriteUint16(m.HardcodedZero0)
+	bf.WriteUint16(m.RequestVersion)
+	bf.WriteUint32(m.CharID1)
+	bf.WriteUint16(0)  // Zeroed
+	bf.WriteUint16(11) // Always 11
+	bf.WriteNullTerminatedBytes([]byte(m.LoginTokenString))
+	return nil
 }
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"fmt"
-
 	"erupe-ce/common/bfutil"
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
@@ -33,5 +31,8 @@ func (m *MsgSysMoveStage) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientCo
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysMoveStage) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return fmt.Errorf("MsgSysMoveStage.Build: not implemented")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint8(m.UnkBool)
+	writeNullTerminated8(bf, m.StageID)
+	return nil
 }
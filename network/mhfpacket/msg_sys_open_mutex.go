@@ -1,15 +1,17 @@
 package mhfpacket
 
 import (
-	"errors"
-
+	"erupe-ce/common/bfutil"
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
 )
 
 // MsgSysOpenMutex represents the MSG_SYS_OPEN_MUTEX
-type MsgSysOpenMutex struct{}
+type MsgSysOpenMutex struct {
+	AckHandle uint32
+	MutexName string
+}
 
 // Opcode returns the ID associated with this packet type.
 func (m *MsgSysOpenMutex) Opcode() network.PacketID {
@@ -18,10 +20,15 @@ func (m *MsgSysOpenMutex) Opcode() network.PacketID {
 
 // Parse parses the packet from binary
 func (m *MsgSysOpenMutex) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	m.AckHandle = bf.ReadUint32()
+	nameLength := bf.ReadUint8()
+	m.MutexName = string(bfutil.UpToNull(bf.ReadBytes(uint(nameLength))))
+	return nil
 }
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysOpenMutex) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	writeNullTerminated8(bf, m.MutexName)
+	return nil
 }
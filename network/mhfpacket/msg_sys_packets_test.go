@@ -160,6 +160,23 @@ func TestMsgSysCloseMutexOpcode(t *testing.T) {
 	}
 }
 
+// TestMsgSysOpenMutexParse tests parsing the shared mutex packet layout
+func TestMsgSysOpenMutexParse(t *testing.T) {
+	bf := byteframe.NewByteFrame()
+	bf.WriteUint32(0x1234)
+	bf.WriteUint8(6)
+	bf.WriteBytes([]byte("event\x00"))
+	_, _ = bf.Seek(0, io.SeekStart)
+
+	pkt := &MsgSysOpenMutex{}
+	if err := pkt.Parse(bf, &clientctx.ClientContext{RealClientMode: cfg.ZZ}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if pkt.AckHandle != 0x1234 || pkt.MutexName != "event" {
+		t.Errorf("parsed = %+v, want AckHandle 0x1234, MutexName event", pkt)
+	}
+}
+
 // TestMsgSysDeleteSemaphoreOpcode tests Opcode method
 func TestMsgSysDeleteSemaphoreOpcode(t *testing.T) {
 	pkt := &MsgSysDeleteSemaphore{}
@@ -1,8 +1,6 @@
 package mhfpacket
 
 import (
-	"fmt"
-
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
@@ -34,5 +32,11 @@ func (m *MsgSysSetStageBinary) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Cli
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysSetStageBinary) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return fmt.Errorf("MsgSysSetStageBinary.Build: not implemented")
+	bf.WriteUint8(m.BinaryType0)
+	bf.WriteUint8(m.BinaryType1)
+	bf.WriteUint8(uint8(len(m.StageID) + 1))
+	bf.WriteUint16(uint16(len(m.RawDataPayload)))
+	bf.WriteNullTerminatedBytes([]byte(m.StageID))
+	bf.WriteBytes(m.RawDataPayload)
+	return nil
 }
@@ -1,7 +1,6 @@
 package mhfpacket
 
 import (
-	"errors"
 	cfg "erupe-ce/config"
 
 	"erupe-ce/common/byteframe"
@@ -62,5 +61,29 @@ func (m *MsgSysTerminalLog) Parse(bf *byteframe.ByteFrame, ctx *clientctx.Client
 
 // Build builds a binary packet from the current data.
 func (m *MsgSysTerminalLog) Build(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	bf.WriteUint32(m.AckHandle)
+	bf.WriteUint32(m.LogID)
+	bf.WriteUint16(uint16(len(m.Entries)))
+	bf.WriteUint16(0) // Zeroed
+
+	for _, e := range m.Entries {
+		bf.WriteUint32(e.Index)
+		bf.WriteUint8(e.Type1)
+		bf.WriteUint8(e.Type2)
+		bf.WriteInt16(e.Unk0)
+		bf.WriteInt32(e.Unk1)
+		bf.WriteInt32(e.Unk2)
+		bf.WriteInt32(e.Unk3)
+		if ctx.RealClientMode >= cfg.G1 {
+			for j := 0; j < 4; j++ {
+				var v int32
+				if j < len(e.Unk4) {
+					v = e.Unk4[j]
+				}
+				bf.WriteInt32(v)
+			}
+		}
+	}
+
+	return nil
 }
@@ -1,12 +1,12 @@
 package mhfpacket
 
 import (
-	"errors"
 	"erupe-ce/common/byteframe"
 	"erupe-ce/common/mhfcourse"
 	ps "erupe-ce/common/pascalstring"
 	"erupe-ce/network"
 	"erupe-ce/network/clientctx"
+	"time"
 )
 
 // MsgSysUpdateRight represents the MSG_SYS_UPDATE_RIGHT
@@ -24,7 +24,21 @@ func (m *MsgSysUpdateRight) Opcode() network.PacketID {
 
 // Parse parses the packet from binary
 func (m *MsgSysUpdateRight) Parse(bf *byteframe.ByteFrame, ctx *clientctx.ClientContext) error {
-	return errors.New("NOT IMPLEMENTED")
+	m.ClientRespAckHandle = bf.ReadUint32()
+	m.Bitfield = bf.ReadUint32()
+	rights := int(bf.ReadUint16())
+	bf.ReadUint16() // Zeroed
+	for i := 0; i < rights; i++ {
+		course := mhfcourse.Course{ID: bf.ReadUint16()}
+		bf.ReadUint16() // Zeroed
+		if expiry := bf.ReadUint32(); expiry != 0 {
+			course.Expiry = time.Unix(int64(expiry), 0)
+		}
+		m.Rights = append(m.Rights, course)
+	}
+	m.TokenLength = bf.ReadUint16()
+	bf.ReadBytes(uint(m.TokenLength)) // Login token
+	return nil
 }
 
 // Build builds a binary packet from the current data.
@@ -0,0 +1,325 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"erupe-ce/common/mhfcourse"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// Paging bounds for GET /v2/admin/users.
+const (
+	adminUsersDefaultLimit = 50
+	adminUsersMaxLimit     = 500
+)
+
+// SessionKicker force-disconnects characters from the channel servers. The
+// channel server's ChannelRegistry satisfies it; with the postgres backend the
+// disconnect reaches every process sharing the database.
+type SessionKicker interface {
+	DisconnectUser(cids []uint32)
+}
+
+// SetSessionKicker wires the channel registry used by the admin kick and ban
+// endpoints. The API server starts before the channel servers, so main calls
+// this once the registry exists.
+func (s *APIServer) SetSessionKicker(k SessionKicker) {
+	s.Lock()
+	s.kicker = k
+	s.Unlock()
+}
+
+func (s *APIServer) sessionKicker() SessionKicker {
+	s.Lock()
+	defer s.Unlock()
+	return s.kicker
+}
+
+func (s *APIServer) isOp(ctx context.Context, userID uint32) (bool, error) {
+	if s.adminRepo == nil {
+		return false, nil
+	}
+	return s.adminRepo.IsOp(ctx, userID)
+}
+
+// AdminUserResponse is the JSON representation of a user account returned by
+// the admin endpoints. Courses lists the primary name of every course bit set
+// in Rights.
+type AdminUserResponse struct {
+	ID         uint32     `json:"id"`
+	Username   string     `json:"username"`
+	Rights     uint32     `json:"rights"`
+	Courses    []string   `json:"courses"`
+	Op         bool       `json:"op"`
+	LastLogin  *time.Time `json:"lastLogin"`
+	Banned     bool       `json:"banned"`
+	BanExpires *time.Time `json:"banExpires"`
+}
+
+func newAdminUserResponse(u AdminUser) AdminUserResponse {
+	return AdminUserResponse{
+		ID:         u.ID,
+		Username:   u.Username,
+		Rights:     u.Rights,
+		Courses:    courseNames(u.Rights),
+		Op:         u.Op,
+		LastLogin:  u.LastLogin,
+		Banned:     u.Banned,
+		BanExpires: u.BanExpires,
+	}
+}
+
+// courseNames returns the primary alias of every named course in rights.
+func courseNames(rights uint32) []string {
+	names := []string{}
+	for _, c := range mhfcourse.Courses() {
+		if rights&c.Value() == 0 {
+			continue
+		}
+		if aliases := c.Aliases(); len(aliases) > 0 {
+			names = append(names, aliases[0])
+		}
+	}
+	return names
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// adminUserID parses the {id} route variable.
+func adminUserID(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid user ID")
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// loadAdminUser fetches the target user, writing a 404 or 500 on failure.
+func (s *APIServer) loadAdminUser(w http.ResponseWriter, r *http.Request, userID uint32) (AdminUser, bool) {
+	user, err := s.adminRepo.GetUser(r.Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "not_found", "User not found")
+		return user, false
+	} else if err != nil {
+		s.logger.Error("Failed to load user", zap.Error(err), zap.Uint32("userID", userID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return user, false
+	}
+	return user, true
+}
+
+// kickUser disconnects every character of userID and reports whether a
+// kicker was available.
+func (s *APIServer) kickUser(ctx context.Context, userID uint32) (bool, error) {
+	kicker := s.sessionKicker()
+	if kicker == nil {
+		return false, nil
+	}
+	cids, err := s.adminRepo.GetCharIDs(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	kicker.DisconnectUser(cids)
+	return true, nil
+}
+
+// AdminListUsers handles GET /v2/admin/users. The optional q parameter
+// matches usernames and character names; limit and offset page the result.
+func (s *APIServer) AdminListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := adminUsersDefaultLimit
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Invalid limit")
+			return
+		}
+		limit = min(n, adminUsersMaxLimit)
+	}
+	offset := 0
+	if v := query.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Invalid offset")
+			return
+		}
+		offset = n
+	}
+
+	users, err := s.adminRepo.SearchUsers(r.Context(), query.Get("q"), limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to search users", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	resp := make([]AdminUserResponse, 0, len(users))
+	for _, u := range users {
+		resp = append(resp, newAdminUserResponse(u))
+	}
+	writeJSON(w, resp)
+}
+
+// AdminGetUser handles GET /v2/admin/users/{id}.
+func (s *APIServer) AdminGetUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserID(w, r)
+	if !ok {
+		return
+	}
+	user, ok := s.loadAdminUser(w, r, userID)
+	if !ok {
+		return
+	}
+	writeJSON(w, newAdminUserResponse(user))
+}
+
+// AdminBanRequest is the body of POST /v2/admin/users/{id}/ban. A missing or
+// null expires issues a permanent ban.
+type AdminBanRequest struct {
+	Expires *time.Time `json:"expires"`
+}
+
+// AdminBanUser handles POST /v2/admin/users/{id}/ban. The user is also
+// disconnected from every channel, as the in-game ban command does.
+func (s *APIServer) AdminBanUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserID(w, r)
+	if !ok {
+		return
+	}
+	var req AdminBanRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Malformed request body")
+			return
+		}
+	}
+	if req.Expires != nil && !req.Expires.After(time.Now()) {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Ban expiry must be in the future")
+		return
+	}
+	if _, ok := s.loadAdminUser(w, r, userID); !ok {
+		return
+	}
+	if err := s.adminRepo.BanUser(r.Context(), userID, req.Expires); err != nil {
+		s.logger.Error("Failed to ban user", zap.Error(err), zap.Uint32("userID", userID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	if _, err := s.kickUser(r.Context(), userID); err != nil {
+		s.logger.Warn("Failed to disconnect banned user", zap.Error(err), zap.Uint32("userID", userID))
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("User banned via API",
+		zap.Uint32("userID", userID), zap.Uint32("adminID", admin), zap.Timep("expires", req.Expires))
+
+	user, ok := s.loadAdminUser(w, r, userID)
+	if !ok {
+		return
+	}
+	writeJSON(w, newAdminUserResponse(user))
+}
+
+// AdminUnbanUser handles DELETE /v2/admin/users/{id}/ban.
+func (s *APIServer) AdminUnbanUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserID(w, r)
+	if !ok {
+		return
+	}
+	lifted, err := s.adminRepo.UnbanUser(r.Context(), userID)
+	if err != nil {
+		s.logger.Error("Failed to unban user", zap.Error(err), zap.Uint32("userID", userID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	if !lifted {
+		writeError(w, http.StatusNotFound, "not_found", "User is not banned")
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("User unbanned via API", zap.Uint32("userID", userID), zap.Uint32("adminID", admin))
+	writeJSON(w, struct{}{})
+}
+
+// AdminRightsRequest is the body of PUT /v2/admin/users/{id}/rights. Courses
+// are course names or aliases as accepted by the in-game course command
+// (e.g. "HunterLife", "HL", "Extra"); the user's rights are replaced by the
+// union of their bits.
+type AdminRightsRequest struct {
+	Courses []string `json:"courses"`
+}
+
+// AdminSetRights handles PUT /v2/admin/users/{id}/rights.
+func (s *APIServer) AdminSetRights(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserID(w, r)
+	if !ok {
+		return
+	}
+	var req AdminRightsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Courses == nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Malformed request body")
+		return
+	}
+	var rights uint32
+	for _, name := range req.Courses {
+		course, ok := mhfcourse.CourseByName(name)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "invalid_course", fmt.Sprintf("Unknown course %q", name))
+			return
+		}
+		rights |= course.Value()
+	}
+	if _, ok := s.loadAdminUser(w, r, userID); !ok {
+		return
+	}
+	if err := s.adminRepo.SetRights(r.Context(), userID, rights); err != nil {
+		s.logger.Error("Failed to set user rights", zap.Error(err), zap.Uint32("userID", userID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("User rights set via API",
+		zap.Uint32("userID", userID), zap.Uint32("adminID", admin), zap.Uint32("rights", rights))
+
+	user, ok := s.loadAdminUser(w, r, userID)
+	if !ok {
+		return
+	}
+	writeJSON(w, newAdminUserResponse(user))
+}
+
+// AdminKickUser handles POST /v2/admin/users/{id}/kick, disconnecting every
+// session of the user's characters.
+func (s *APIServer) AdminKickUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := adminUserID(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := s.loadAdminUser(w, r, userID); !ok {
+		return
+	}
+	kicked, err := s.kickUser(r.Context(), userID)
+	if err != nil {
+		s.logger.Error("Failed to kick user", zap.Error(err), zap.Uint32("userID", userID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	if !kicked {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "No channel servers are running in this process")
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("User kicked via API", zap.Uint32("userID", userID), zap.Uint32("adminID", admin))
+	writeJSON(w, struct{}{})
+}
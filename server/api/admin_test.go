@@ -0,0 +1,290 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockKicker records DisconnectUser calls.
+type mockKicker struct {
+	mu   sync.Mutex
+	cids [][]uint32
+}
+
+func (k *mockKicker) DisconnectUser(cids []uint32) {
+	k.mu.Lock()
+	k.cids = append(k.cids, cids)
+	k.mu.Unlock()
+}
+
+// newAdminTestServer returns a server where token "valid-token" belongs to
+// user 1 (an operator) and user 2 is a regular player with two characters.
+func newAdminTestServer(t *testing.T) (*APIServer, *mockAPIAdminRepo, *mockAPISessionRepo) {
+	t.Helper()
+	repo := &mockAPIAdminRepo{
+		users: map[uint32]*AdminUser{
+			1: {ID: 1, Username: "admin", Rights: 12, Op: true},
+			2: {ID: 2, Username: "player", Rights: 12},
+		},
+		ops:     map[uint32]bool{1: true},
+		charIDs: map[uint32][]uint32{2: {20, 21}},
+	}
+	sessions := &mockAPISessionRepo{userID: 1}
+	server := &APIServer{
+		logger:      NewTestLogger(t),
+		erupeConfig: NewTestConfig(),
+		sessionRepo: sessions,
+		adminRepo:   repo,
+	}
+	return server, repo, sessions
+}
+
+func doAdminRequest(t *testing.T, server *APIServer, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Authorization", "Bearer valid-token")
+	rec := httptest.NewRecorder()
+	newTestRouter(server).ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeAdminUser(t *testing.T, rec *httptest.ResponseRecorder) AdminUserResponse {
+	t.Helper()
+	var resp AdminUserResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp
+}
+
+func TestAdminMiddleware_RejectsNonOp(t *testing.T) {
+	server, _, sessions := newAdminTestServer(t)
+	sessions.userID = 2
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/users", nil)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestAdminMiddleware_RejectsMissingToken(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	req := httptest.NewRequest("GET", "/v2/admin/users", nil)
+	rec := httptest.NewRecorder()
+	newTestRouter(server).ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rec.Code)
+	}
+}
+
+func TestAdminMiddleware_NoAdminRepo(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	server.adminRepo = nil
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/users", nil)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestAdminListUsers(t *testing.T) {
+	server, repo, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/users?q=play&limit=1000&offset=5", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if repo.lastQuery != "play" || repo.lastLimit != adminUsersMaxLimit || repo.lastOffset != 5 {
+		t.Errorf("search args = (%q, %d, %d), want (\"play\", %d, 5)",
+			repo.lastQuery, repo.lastLimit, repo.lastOffset, adminUsersMaxLimit)
+	}
+	var users []AdminUserResponse
+	if err := json.NewDecoder(rec.Body).Decode(&users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Errorf("got %d users, want 2", len(users))
+	}
+}
+
+func TestAdminListUsers_InvalidLimit(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/users?limit=abc", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminGetUser(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/users/2", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	resp := decodeAdminUser(t, rec)
+	if resp.Username != "player" {
+		t.Errorf("username = %q, want player", resp.Username)
+	}
+	if len(resp.Courses) != 2 || resp.Courses[0] != "HunterLife" || resp.Courses[1] != "Extra" {
+		t.Errorf("courses = %v, want [HunterLife Extra]", resp.Courses)
+	}
+}
+
+func TestAdminGetUser_NotFound(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/users/99", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminBanUser_Permanent(t *testing.T) {
+	server, repo, _ := newAdminTestServer(t)
+	kicker := &mockKicker{}
+	server.SetSessionKicker(kicker)
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/users/2/ban", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if !repo.users[2].Banned || repo.users[2].BanExpires != nil {
+		t.Errorf("user not permanently banned: %+v", repo.users[2])
+	}
+	if len(kicker.cids) != 1 || len(kicker.cids[0]) != 2 {
+		t.Errorf("expected the user's 2 characters to be kicked, got %v", kicker.cids)
+	}
+}
+
+func TestAdminBanUser_Temporary(t *testing.T) {
+	server, repo, _ := newAdminTestServer(t)
+	expires := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/users/2/ban", AdminBanRequest{Expires: &expires})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if got := repo.users[2].BanExpires; got == nil || !got.Equal(expires) {
+		t.Errorf("ban expiry = %v, want %v", got, expires)
+	}
+	if resp := decodeAdminUser(t, rec); !resp.Banned {
+		t.Error("response does not report the ban")
+	}
+}
+
+func TestAdminBanUser_PastExpiry(t *testing.T) {
+	server, repo, _ := newAdminTestServer(t)
+	expires := time.Now().Add(-time.Hour)
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/users/2/ban", AdminBanRequest{Expires: &expires})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+	if repo.users[2].Banned {
+		t.Error("user should not be banned")
+	}
+}
+
+func TestAdminUnbanUser(t *testing.T) {
+	server, repo, _ := newAdminTestServer(t)
+	repo.users[2].Banned = true
+
+	rec := doAdminRequest(t, server, "DELETE", "/v2/admin/users/2/ban", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if repo.users[2].Banned {
+		t.Error("user still banned")
+	}
+
+	rec = doAdminRequest(t, server, "DELETE", "/v2/admin/users/2/ban", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("second unban: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminSetRights(t *testing.T) {
+	server, repo, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "PUT", "/v2/admin/users/2/rights",
+		AdminRightsRequest{Courses: []string{"HL", "extra", "NetCafe"}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	want := uint32(1<<2 | 1<<3 | 1<<26)
+	if repo.users[2].Rights != want {
+		t.Errorf("rights = %d, want %d", repo.users[2].Rights, want)
+	}
+	resp := decodeAdminUser(t, rec)
+	if len(resp.Courses) != 3 || resp.Courses[2] != "NetCafe" {
+		t.Errorf("courses = %v", resp.Courses)
+	}
+}
+
+func TestAdminSetRights_UnknownCourse(t *testing.T) {
+	server, repo, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "PUT", "/v2/admin/users/2/rights",
+		AdminRightsRequest{Courses: []string{"HL", "Platinum"}})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+	if repo.users[2].Rights != 12 {
+		t.Errorf("rights changed to %d", repo.users[2].Rights)
+	}
+}
+
+func TestAdminSetRights_MissingCourses(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "PUT", "/v2/admin/users/2/rights", map[string]string{})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminKickUser(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	kicker := &mockKicker{}
+	server.SetSessionKicker(kicker)
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/users/2/kick", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if len(kicker.cids) != 1 || kicker.cids[0][0] != 20 || kicker.cids[0][1] != 21 {
+		t.Errorf("kicked %v, want [[20 21]]", kicker.cids)
+	}
+}
+
+func TestAdminKickUser_NoChannels(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/users/2/kick", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
+
+func TestAdminInvalidUserID(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/users/abc", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
@@ -2,7 +2,10 @@ package api
 
 import (
 	"context"
+	"erupe-ce/common/metrics"
 	cfg "erupe-ce/config"
+	"erupe-ce/server/auth"
+	"erupe-ce/server/channelserver"
 	"fmt"
 	"net/http"
 	"os"
@@ -20,21 +23,38 @@ type Config struct {
 	Logger      *zap.Logger
 	DB          *sqlx.DB
 	ErupeConfig *cfg.Config
+	Metrics     *metrics.Registry // Served at /metrics when set.
 }
 
 // APIServer is Erupes Standard API interface
 type APIServer struct {
 	sync.Mutex
-	logger         *zap.Logger
-	db             *sqlx.DB
-	erupeConfig    *cfg.Config
-	userRepo       APIUserRepo
-	charRepo       APICharacterRepo
-	sessionRepo    APISessionRepo
-	eventRepo      APIEventRepo
-	httpServer     *http.Server
-	startTime      time.Time
-	isShuttingDown bool
+	logger            *zap.Logger
+	db                *sqlx.DB
+	erupeConfig       *cfg.Config
+	userRepo          APIUserRepo
+	charRepo          APICharacterRepo
+	sessionRepo       APISessionRepo
+	eventRepo         APIEventRepo
+	adminRepo         APIAdminRepo
+	saveHistory       APISaveHistory
+	guildAdmin        APIGuildAdmin
+	raviente          APIRaviente
+	tournamentAdmin   APITournamentAdmin
+	festaAdmin        APIFestaAdmin
+	eventQuests       APIEventQuestScheduler
+	mailBroadcasts    APIMailBroadcasts
+	distributionAdmin APIDistributionAdmin
+	gachaAdmin        APIGachaAdmin
+	gachaHistory      APIGachaHistory
+	shopAdmin         APIShopAdmin
+	kicker            SessionKicker
+	loginGuard        *auth.Guard
+	authenticator     auth.Authenticator
+	metrics           *metrics.Registry
+	httpServer        *http.Server
+	startTime         time.Time
+	isShuttingDown    bool
 }
 
 // NewAPIServer creates a new Server type.
@@ -43,6 +63,7 @@ func NewAPIServer(config *Config) *APIServer {
 		logger:      config.Logger,
 		db:          config.DB,
 		erupeConfig: config.ErupeConfig,
+		metrics:     config.Metrics,
 		httpServer:  &http.Server{},
 	}
 	if config.DB != nil {
@@ -50,6 +71,34 @@ func NewAPIServer(config *Config) *APIServer {
 		s.charRepo = NewAPICharacterRepository(config.DB)
 		s.sessionRepo = NewAPISessionRepository(config.DB)
 		s.eventRepo = NewAPIEventRepository(config.DB)
+		s.adminRepo = NewAPIAdminRepository(config.DB)
+		s.raviente = channelserver.NewRavienteRepository(config.DB)
+		s.tournamentAdmin = channelserver.NewTournamentService(channelserver.NewTournamentRepository(config.DB), config.Logger)
+		s.festaAdmin = channelserver.NewFestaService(channelserver.NewFestaRepository(config.DB), config.Logger)
+		s.eventQuests = channelserver.NewEventQuestScheduler(channelserver.NewEventRepository(config.DB), config.Logger)
+		s.mailBroadcasts = channelserver.NewMailBroadcastService(channelserver.NewMailBroadcastRepository(config.DB), config.Logger)
+		s.distributionAdmin = channelserver.NewDistributionService(channelserver.NewDistributionRepository(config.DB), config.Logger)
+		gachaRepo := channelserver.NewGachaRepository(config.DB, config.Logger)
+		s.gachaAdmin = channelserver.NewGachaAdminService(gachaRepo, config.Logger)
+		s.gachaHistory = channelserver.NewGachaHistoryService(gachaRepo, config.Logger)
+		if config.ErupeConfig != nil {
+			s.loginGuard = auth.NewGuard(auth.NewLoginRepository(config.DB), config.ErupeConfig.LoginProtection, config.Logger)
+			var err error
+			s.authenticator, err = auth.New(config.ErupeConfig.Authentication, apiCredentials{s.userRepo}, auth.NewUserRepository(config.DB))
+			if err != nil {
+				config.Logger.Error("Invalid authentication config, refusing all logins", zap.Error(err))
+			}
+		}
+		if config.ErupeConfig != nil {
+			s.saveHistory = channelserver.NewSaveHistoryService(
+				channelserver.NewCharacterRepository(config.DB), config.ErupeConfig.RealClientMode, config.Logger)
+			s.shopAdmin = channelserver.NewShopAdminService(
+				channelserver.NewShopRepository(config.DB), config.ErupeConfig.RealClientMode, config.Logger)
+		}
+		guildRepo := channelserver.NewGuildRepository(config.DB)
+		s.guildAdmin = channelserver.NewGuildService(guildRepo,
+			channelserver.NewMailService(channelserver.NewMailRepository(config.DB), guildRepo, config.Logger),
+			channelserver.NewCharacterRepository(config.DB), config.Logger)
 	}
 	return s
 }
@@ -64,6 +113,9 @@ func (s *APIServer) Start() error {
 	// Dashboard routes (before catch-all)
 	r.HandleFunc("/dashboard", s.Dashboard)
 	r.HandleFunc("/api/dashboard/stats", s.DashboardStatsJSON).Methods("GET")
+	if s.metrics != nil {
+		r.Handle("/metrics", s.metrics).Methods("GET")
+	}
 
 	// Legacy routes (unchanged, no method enforcement)
 	r.HandleFunc("/launcher", s.Launcher)
@@ -87,6 +139,8 @@ func (s *APIServer) Start() error {
 	v2.HandleFunc("/health", s.Health).Methods("GET")
 	v2.HandleFunc("/server/status", s.ServerStatus).Methods("GET")
 	v2.HandleFunc("/server/info", s.ServerInfo).Methods("GET")
+	v2.HandleFunc("/raviente", s.RavienteStatus).Methods("GET")
+	v2.HandleFunc("/raviente/history", s.RavienteHistory).Methods("GET")
 
 	// V2 authenticated routes
 	v2Auth := v2.PathPrefix("").Subrouter()
@@ -96,6 +150,81 @@ func (s *APIServer) Start() error {
 	v2Auth.HandleFunc("/characters/{id}", s.DeleteCharacter).Methods("DELETE")
 	v2Auth.HandleFunc("/characters/{id}/export", s.ExportSave).Methods("GET")
 	v2Auth.HandleFunc("/characters/{id}/import", s.ImportSave).Methods("POST")
+	v2Auth.HandleFunc("/characters/{id}/backups", s.ListBackups).Methods("GET")
+	v2Auth.HandleFunc("/characters/{id}/backups/diff", s.DiffBackups).Methods("GET")
+	v2Auth.HandleFunc("/characters/{id}/backups/{slot}/restore", s.RestoreBackup).Methods("POST")
+	v2Auth.HandleFunc("/characters/{id}/gacha-history", s.GachaHistory).Methods("GET")
+
+	// V2 admin routes
+	v2Admin := v2.PathPrefix("/admin").Subrouter()
+	v2Admin.Use(s.AdminMiddleware)
+	v2Admin.HandleFunc("/users", s.AdminListUsers).Methods("GET")
+	v2Admin.HandleFunc("/users/{id}", s.AdminGetUser).Methods("GET")
+	v2Admin.HandleFunc("/users/{id}/ban", s.AdminBanUser).Methods("POST")
+	v2Admin.HandleFunc("/users/{id}/ban", s.AdminUnbanUser).Methods("DELETE")
+	v2Admin.HandleFunc("/users/{id}/rights", s.AdminSetRights).Methods("PUT")
+	v2Admin.HandleFunc("/users/{id}/kick", s.AdminKickUser).Methods("POST")
+	v2Admin.HandleFunc("/guilds/{id}/rank", s.AdminSetGuildRank).Methods("PUT")
+	v2Admin.HandleFunc("/guilds/{id}/name", s.AdminRenameGuild).Methods("PUT")
+	v2Admin.HandleFunc("/guilds/{id}/leader", s.AdminSetGuildLeader).Methods("PUT")
+	v2Admin.HandleFunc("/guilds/{id}/card", s.AdminUpdateGuildcard).Methods("PUT")
+	v2Admin.HandleFunc("/guilds/{id}/audit", s.AdminGuildAudit).Methods("GET")
+	v2Admin.HandleFunc("/raviente/{id}", s.AdminGetRaviente).Methods("GET")
+	v2Admin.HandleFunc("/raviente/{id}/start", s.AdminStartRaviente).Methods("POST")
+	v2Admin.HandleFunc("/tournaments", s.AdminListTournaments).Methods("GET")
+	v2Admin.HandleFunc("/tournaments", s.AdminCreateTournament).Methods("POST")
+	v2Admin.HandleFunc("/tournaments/{id}", s.AdminGetTournament).Methods("GET")
+	v2Admin.HandleFunc("/tournaments/{id}", s.AdminUpdateTournament).Methods("PUT")
+	v2Admin.HandleFunc("/tournaments/{id}", s.AdminDeleteTournament).Methods("DELETE")
+	v2Admin.HandleFunc("/tournaments/{id}/results", s.AdminTournamentResults).Methods("GET")
+	v2Admin.HandleFunc("/tournaments/{id}/results/{resultId}", s.AdminReviewTournamentResult).Methods("PUT")
+	v2Admin.HandleFunc("/festa", s.AdminFestaStatus).Methods("GET")
+	v2Admin.HandleFunc("/festa/history", s.AdminFestaHistory).Methods("GET")
+	v2Admin.HandleFunc("/festa/history/{id}", s.AdminFestaHistoryDetail).Methods("GET")
+	v2Admin.HandleFunc("/festa/trials", s.AdminFestaTrials).Methods("GET")
+	v2Admin.HandleFunc("/festa/trials", s.AdminAddFestaTrial).Methods("POST")
+	v2Admin.HandleFunc("/festa/trials/{id}", s.AdminRemoveFestaTrial).Methods("DELETE")
+	v2Admin.HandleFunc("/festa/prizes", s.AdminFestaPrizes).Methods("GET")
+	v2Admin.HandleFunc("/festa/prizes", s.AdminAddFestaPrize).Methods("POST")
+	v2Admin.HandleFunc("/festa/prizes/{id}", s.AdminRemoveFestaPrize).Methods("DELETE")
+	v2Admin.HandleFunc("/event-quests/preview", s.AdminEventQuestPreview).Methods("GET")
+	v2Admin.HandleFunc("/mail-broadcasts", s.AdminListMailBroadcasts).Methods("GET")
+	v2Admin.HandleFunc("/mail-broadcasts", s.AdminCreateMailBroadcast).Methods("POST")
+	v2Admin.HandleFunc("/mail-broadcasts/{id}", s.AdminGetMailBroadcast).Methods("GET")
+	v2Admin.HandleFunc("/mail-broadcasts/{id}/resume", s.AdminResumeMailBroadcast).Methods("POST")
+	v2Admin.HandleFunc("/distributions", s.AdminListDistributions).Methods("GET")
+	v2Admin.HandleFunc("/distributions", s.AdminCreateDistribution).Methods("POST")
+	v2Admin.HandleFunc("/distributions/{id}", s.AdminGetDistribution).Methods("GET")
+	v2Admin.HandleFunc("/distributions/{id}", s.AdminUpdateDistribution).Methods("PUT")
+	v2Admin.HandleFunc("/distributions/{id}/expire", s.AdminExpireDistribution).Methods("POST")
+	v2Admin.HandleFunc("/distributions/{id}/eligible", s.AdminDistributionEligible).Methods("GET")
+	v2Admin.HandleFunc("/gachas", s.AdminListGachas).Methods("GET")
+	v2Admin.HandleFunc("/gachas", s.AdminCreateGacha).Methods("POST")
+	v2Admin.HandleFunc("/gachas/{id}", s.AdminGetGacha).Methods("GET")
+	v2Admin.HandleFunc("/gachas/{id}", s.AdminUpdateGacha).Methods("PUT")
+	v2Admin.HandleFunc("/gachas/{id}", s.AdminDeleteGacha).Methods("DELETE")
+	v2Admin.HandleFunc("/gachas/{id}/entries", s.AdminCreateGachaEntry).Methods("POST")
+	v2Admin.HandleFunc("/gachas/{id}/entries/{entryId}", s.AdminUpdateGachaEntry).Methods("PUT")
+	v2Admin.HandleFunc("/gachas/{id}/entries/{entryId}", s.AdminDeleteGachaEntry).Methods("DELETE")
+	v2Admin.HandleFunc("/gachas/{id}/rates", s.AdminGachaRates).Methods("GET")
+	v2Admin.HandleFunc("/gachas/{id}/simulate", s.AdminSimulateGacha).Methods("POST")
+	v2Admin.HandleFunc("/gacha-history", s.AdminGachaHistory).Methods("GET")
+	v2Admin.HandleFunc("/shop-items", s.AdminListShopItems).Methods("GET")
+	v2Admin.HandleFunc("/shop-items", s.AdminCreateShopItem).Methods("POST")
+	v2Admin.HandleFunc("/shop-items/{id}", s.AdminGetShopItem).Methods("GET")
+	v2Admin.HandleFunc("/shop-items/{id}", s.AdminUpdateShopItem).Methods("PUT")
+	v2Admin.HandleFunc("/shop-items/{id}/retire", s.AdminRetireShopItem).Methods("POST")
+	v2Admin.HandleFunc("/shop-items/{id}/restore", s.AdminRestoreShopItem).Methods("POST")
+	v2Admin.HandleFunc("/shop-items/{id}/prices", s.AdminScheduleShopPrice).Methods("POST")
+	v2Admin.HandleFunc("/shop-items/{id}/prices/{priceId}", s.AdminCancelShopPrice).Methods("DELETE")
+	v2Admin.HandleFunc("/fpoint-items", s.AdminListFpointItems).Methods("GET")
+	v2Admin.HandleFunc("/fpoint-items", s.AdminCreateFpointItem).Methods("POST")
+	v2Admin.HandleFunc("/fpoint-items/{id}", s.AdminGetFpointItem).Methods("GET")
+	v2Admin.HandleFunc("/fpoint-items/{id}", s.AdminUpdateFpointItem).Methods("PUT")
+	v2Admin.HandleFunc("/fpoint-items/{id}/retire", s.AdminRetireFpointItem).Methods("POST")
+	v2Admin.HandleFunc("/fpoint-items/{id}/restore", s.AdminRestoreFpointItem).Methods("POST")
+	v2Admin.HandleFunc("/shop-reports/top-items", s.AdminShopTopItems).Methods("GET")
+	v2Admin.HandleFunc("/shop-reports/cap-hits", s.AdminShopCapHits).Methods("GET")
 
 	handler := handlers.CORS(
 		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
@@ -18,14 +18,15 @@ var dashboardTmpl = template.Must(template.New("dashboard").Parse(dashboardHTML)
 
 // DashboardStats is the JSON payload returned by GET /api/dashboard/stats.
 type DashboardStats struct {
-	Uptime          string        `json:"uptime"`
-	ServerVersion   string        `json:"serverVersion"`
-	ClientMode      string        `json:"clientMode"`
-	OnlinePlayers   int           `json:"onlinePlayers"`
-	TotalAccounts   int           `json:"totalAccounts"`
-	TotalCharacters int           `json:"totalCharacters"`
-	Channels        []ChannelInfo `json:"channels"`
-	DatabaseOK      bool          `json:"databaseOK"`
+	Uptime          string                  `json:"uptime"`
+	ServerVersion   string                  `json:"serverVersion"`
+	ClientMode      string                  `json:"clientMode"`
+	OnlinePlayers   int                     `json:"onlinePlayers"`
+	TotalAccounts   int                     `json:"totalAccounts"`
+	TotalCharacters int                     `json:"totalCharacters"`
+	Channels        []ChannelInfo           `json:"channels"`
+	DatabaseOK      bool                    `json:"databaseOK"`
+	Raviente        []RavienteSiegeResponse `json:"raviente"`
 }
 
 // ChannelInfo describes a single channel server entry from the servers table.
@@ -129,6 +130,13 @@ func (s *APIServer) DashboardStatsJSON(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Open Raviente sieges.
+	sieges, err := s.openSieges()
+	if err != nil {
+		s.logger.Warn("Dashboard: failed to query Raviente sieges", zap.Error(err))
+	}
+	stats.Raviente = sieges
+
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(stats); err != nil {
 		s.logger.Error("Dashboard: failed to encode stats", zap.Error(err))
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"erupe-ce/common/token"
+	"erupe-ce/server/auth"
 	"fmt"
 	"time"
 
@@ -86,3 +87,22 @@ func (s *APIServer) getReturnExpiry(uid uint32) time.Time {
 func (s *APIServer) exportSave(ctx context.Context, uid uint32, cid uint32) (map[string]interface{}, error) {
 	return s.charRepo.ExportSave(ctx, uid, cid)
 }
+
+// apiCredentials adapts APIUserRepo to auth.CredentialStore.
+type apiCredentials struct {
+	repo APIUserRepo
+}
+
+func (c apiCredentials) GetCredentials(ctx context.Context, username string) (uint32, string, error) {
+	uid, hash, _, err := c.repo.GetCredentials(ctx, username)
+	return uid, hash, err
+}
+
+// loginAuthenticator returns the configured Authenticator, defaulting to the
+// local users table.
+func (s *APIServer) loginAuthenticator() auth.Authenticator {
+	if s.authenticator == nil {
+		return auth.NewLocalAuthenticator(apiCredentials{s.userRepo})
+	}
+	return s.authenticator
+}
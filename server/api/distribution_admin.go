@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// APIDistributionAdmin creates, edits and expires item distributions.
+// *channelserver.DistributionService satisfies it.
+type APIDistributionAdmin interface {
+	List() ([]channelserver.DistributionCampaign, error)
+	Get(id uint32) (*channelserver.DistributionCampaign, error)
+	Create(c channelserver.DistributionCampaign) (*channelserver.DistributionCampaign, error)
+	Update(id uint32, c channelserver.DistributionCampaign) (*channelserver.DistributionCampaign, error)
+	Expire(id uint32, at time.Time) (*channelserver.DistributionCampaign, error)
+	Eligible(id uint32, limit int) (*channelserver.DistributionEligibility, error)
+}
+
+// AdminDistributionExpireRequest is the optional body of
+// POST /v2/admin/distributions/{id}/expire. At defaults to now.
+type AdminDistributionExpireRequest struct {
+	At *time.Time `json:"at"`
+}
+
+// adminDistribution parses the {id} route variable.
+func (s *APIServer) adminDistribution(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	if !s.requireDistributionAdmin(w) {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid distribution ID")
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// requireDistributionAdmin writes 503 when distribution administration is not
+// wired up.
+func (s *APIServer) requireDistributionAdmin(w http.ResponseWriter) bool {
+	if s.distributionAdmin == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Distribution administration is not available")
+		return false
+	}
+	return true
+}
+
+// writeDistributionAdminError maps a distribution service error.
+func (s *APIServer) writeDistributionAdminError(w http.ResponseWriter, err error, distributionID uint32) {
+	switch {
+	case errors.Is(err, channelserver.ErrDistributionNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Distribution not found")
+	case errors.Is(err, channelserver.ErrInvalidDistribution):
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+	default:
+		s.logger.Error("Distribution admin request failed", zap.Error(err), zap.Uint32("distributionID", distributionID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+	}
+}
+
+// AdminListDistributions handles GET /v2/admin/distributions.
+func (s *APIServer) AdminListDistributions(w http.ResponseWriter, r *http.Request) {
+	if !s.requireDistributionAdmin(w) {
+		return
+	}
+	list, err := s.distributionAdmin.List()
+	if err != nil {
+		s.writeDistributionAdminError(w, err, 0)
+		return
+	}
+	if list == nil {
+		list = []channelserver.DistributionCampaign{}
+	}
+	writeJSON(w, list)
+}
+
+// AdminCreateDistribution handles POST /v2/admin/distributions.
+func (s *APIServer) AdminCreateDistribution(w http.ResponseWriter, r *http.Request) {
+	if !s.requireDistributionAdmin(w) {
+		return
+	}
+	var req channelserver.DistributionCampaign
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	c, err := s.distributionAdmin.Create(req)
+	if err != nil {
+		s.writeDistributionAdminError(w, err, 0)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Distribution created via API", zap.Uint32("distributionID", c.ID), zap.Uint32("adminID", admin))
+	writeJSON(w, c)
+}
+
+// AdminGetDistribution handles GET /v2/admin/distributions/{id}.
+func (s *APIServer) AdminGetDistribution(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminDistribution(w, r)
+	if !ok {
+		return
+	}
+	c, err := s.distributionAdmin.Get(id)
+	if err != nil {
+		s.writeDistributionAdminError(w, err, id)
+		return
+	}
+	writeJSON(w, c)
+}
+
+// AdminUpdateDistribution handles PUT /v2/admin/distributions/{id}, replacing
+// its settings and items. Claims already made are kept.
+func (s *APIServer) AdminUpdateDistribution(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminDistribution(w, r)
+	if !ok {
+		return
+	}
+	var req channelserver.DistributionCampaign
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	c, err := s.distributionAdmin.Update(id, req)
+	if err != nil {
+		s.writeDistributionAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Distribution updated via API", zap.Uint32("distributionID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, c)
+}
+
+// AdminExpireDistribution handles POST /v2/admin/distributions/{id}/expire.
+func (s *APIServer) AdminExpireDistribution(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminDistribution(w, r)
+	if !ok {
+		return
+	}
+	var req AdminDistributionExpireRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	at := time.Now()
+	if req.At != nil {
+		at = *req.At
+	}
+	c, err := s.distributionAdmin.Expire(id, at)
+	if err != nil {
+		s.writeDistributionAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Distribution expired via API", zap.Uint32("distributionID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, c)
+}
+
+// AdminDistributionEligible handles GET /v2/admin/distributions/{id}/eligible,
+// previewing which characters the distribution is offered to and can still
+// accept it.
+func (s *APIServer) AdminDistributionEligible(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminDistribution(w, r)
+	if !ok {
+		return
+	}
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Invalid limit")
+			return
+		}
+		limit = n
+	}
+	preview, err := s.distributionAdmin.Eligible(id, limit)
+	if err != nil {
+		s.writeDistributionAdminError(w, err, id)
+		return
+	}
+	writeJSON(w, preview)
+}
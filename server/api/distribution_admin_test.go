@@ -0,0 +1,208 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"erupe-ce/server/channelserver"
+)
+
+// mockDistributionAdmin implements APIDistributionAdmin for testing.
+type mockDistributionAdmin struct {
+	campaign channelserver.DistributionCampaign
+	err      error
+
+	requested *channelserver.DistributionCampaign
+	expiredAt time.Time
+	limit     int
+}
+
+func (m *mockDistributionAdmin) List() ([]channelserver.DistributionCampaign, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []channelserver.DistributionCampaign{m.campaign}, nil
+}
+
+func (m *mockDistributionAdmin) Get(id uint32) (*channelserver.DistributionCampaign, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := m.campaign
+	return &out, nil
+}
+
+func (m *mockDistributionAdmin) Create(c channelserver.DistributionCampaign) (*channelserver.DistributionCampaign, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.requested = &c
+	out := m.campaign
+	return &out, nil
+}
+
+func (m *mockDistributionAdmin) Update(id uint32, c channelserver.DistributionCampaign) (*channelserver.DistributionCampaign, error) {
+	return m.Create(c)
+}
+
+func (m *mockDistributionAdmin) Expire(id uint32, at time.Time) (*channelserver.DistributionCampaign, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.expiredAt = at
+	out := m.campaign
+	out.Deadline = &at
+	return &out, nil
+}
+
+func (m *mockDistributionAdmin) Eligible(id uint32, limit int) (*channelserver.DistributionEligibility, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.limit = limit
+	return &channelserver.DistributionEligibility{
+		Count:      2,
+		Characters: []channelserver.DistributionEligibleChar{{ID: 1, Name: "Hunter", HR: 200}},
+	}, nil
+}
+
+func newMockDistributionAdmin() *mockDistributionAdmin {
+	return &mockDistributionAdmin{
+		campaign: channelserver.DistributionCampaign{ID: 3, Type: 1, EventName: "Login Bonus", Claims: 5, Claimants: 4},
+	}
+}
+
+func TestAdminCreateDistribution(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockDistributionAdmin()
+	server.distributionAdmin = mock
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/distributions", channelserver.DistributionCampaign{
+		Type:        1,
+		EventName:   "Login Bonus",
+		Description: "Thanks for playing!",
+		Courses:     []string{"HunterLife"},
+		MinHR:       100,
+		CharIDs:     []uint32{1, 2},
+		Items:       []channelserver.DistributionItem{{ItemType: 7, ItemID: 100, Quantity: 5}},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	req := mock.requested
+	if req.MinHR != 100 || len(req.CharIDs) != 2 || len(req.Courses) != 1 || len(req.Items) != 1 || req.Items[0].Quantity != 5 {
+		t.Errorf("requested = %+v", req)
+	}
+	var got channelserver.DistributionCampaign
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != 3 || got.Claims != 5 || got.Claimants != 4 {
+		t.Errorf("response = %+v", got)
+	}
+}
+
+func TestAdminCreateDistribution_Invalid(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockDistributionAdmin()
+	mock.err = fmt.Errorf("%w: at least one item is required", channelserver.ErrInvalidDistribution)
+	server.distributionAdmin = mock
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/distributions", channelserver.DistributionCampaign{EventName: "x"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+	rec = doAdminRequest(t, server, "POST", "/v2/admin/distributions", "not an object")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("malformed body: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminGetAndUpdateDistribution(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockDistributionAdmin()
+	server.distributionAdmin = mock
+
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/distributions", nil); rec.Code != http.StatusOK {
+		t.Fatalf("list: status = %d", rec.Code)
+	}
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/distributions/3", nil); rec.Code != http.StatusOK {
+		t.Fatalf("get: status = %d", rec.Code)
+	}
+	rec := doAdminRequest(t, server, "PUT", "/v2/admin/distributions/3", channelserver.DistributionCampaign{EventName: "Weekend Bonus"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: status = %d", rec.Code)
+	}
+	if mock.requested.EventName != "Weekend Bonus" {
+		t.Errorf("requested = %+v", mock.requested)
+	}
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/distributions/abc", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad ID: status = %d, want 400", rec.Code)
+	}
+
+	mock.err = fmt.Errorf("%w: 9", channelserver.ErrDistributionNotFound)
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/distributions/9", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("missing: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminExpireDistribution(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockDistributionAdmin()
+	server.distributionAdmin = mock
+
+	before := time.Now()
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/distributions/3/expire", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if mock.expiredAt.Before(before) {
+		t.Errorf("expired at %v, want now", mock.expiredAt)
+	}
+
+	at := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	rec = doAdminRequest(t, server, "POST", "/v2/admin/distributions/3/expire", AdminDistributionExpireRequest{At: &at})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if !mock.expiredAt.Equal(at) {
+		t.Errorf("expired at %v, want %v", mock.expiredAt, at)
+	}
+}
+
+func TestAdminDistributionEligible(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockDistributionAdmin()
+	server.distributionAdmin = mock
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/distributions/3/eligible?limit=50", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if mock.limit != 50 {
+		t.Errorf("limit = %d, want 50", mock.limit)
+	}
+	var got channelserver.DistributionEligibility
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Count != 2 || len(got.Characters) != 1 || got.Characters[0].Name != "Hunter" {
+		t.Errorf("response = %+v", got)
+	}
+
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/distributions/3/eligible?limit=0", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("zero limit: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminDistributions_Unavailable(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/distributions", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
@@ -3,7 +3,6 @@ package api
 import (
 	"context"
 	"crypto/sha256"
-	"database/sql"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
@@ -11,6 +10,7 @@ import (
 	"erupe-ce/common/gametime"
 	"erupe-ce/common/mhfcourse"
 	cfg "erupe-ce/config"
+	"erupe-ce/server/auth"
 	"erupe-ce/server/channelserver/compression/nullcomp"
 	"fmt"
 	"image"
@@ -26,7 +26,6 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/lib/pq"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // Notification type constants for launcher messages.
@@ -205,17 +204,38 @@ func (s *APIServer) Login(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid_request", "Malformed request body")
 		return
 	}
-	userID, password, userRights, err := s.userRepo.GetCredentials(ctx, reqData.Username)
-	if err == sql.ErrNoRows {
+	ip := auth.RemoteIP(r.RemoteAddr)
+	if lock := s.loginGuard.Check(reqData.Username, ip); lock.Locked() {
+		writeLockout(w, lock)
+		return
+	}
+	userID, err := s.loginAuthenticator().Authenticate(ctx, reqData.Username, reqData.Password)
+	switch {
+	case errors.Is(err, auth.ErrUnknownUser):
+		s.loginGuard.Failure(reqData.Username, ip)
 		writeError(w, http.StatusBadRequest, "invalid_username", "Username not found")
 		return
-	} else if err != nil {
-		s.logger.Warn("SQL query error", zap.Error(err))
-		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+	case errors.Is(err, auth.ErrBadPassword):
+		if lock := s.loginGuard.Failure(reqData.Username, ip); lock.Locked() {
+			writeLockout(w, lock)
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid_password", "Incorrect password")
+		return
+	case errors.Is(err, auth.ErrForeignAccount):
+		s.logger.Warn("External login matches an account it does not own", zap.String("username", reqData.Username))
+		writeError(w, http.StatusForbidden, "account_conflict", "Username belongs to another account")
+		return
+	case err != nil:
+		s.logger.Error("Authentication backend failed", zap.String("username", reqData.Username), zap.Error(err))
+		writeError(w, http.StatusServiceUnavailable, "auth_unavailable", "Authentication service unavailable")
 		return
 	}
-	if bcrypt.CompareHashAndPassword([]byte(password), []byte(reqData.Password)) != nil {
-		writeError(w, http.StatusBadRequest, "invalid_password", "Incorrect password")
+	s.loginGuard.Success(reqData.Username)
+	userRights, err := s.userRepo.GetRights(ctx, userID)
+	if err != nil {
+		s.logger.Warn("SQL query error", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
 		return
 	}
 
@@ -256,6 +276,12 @@ func (s *APIServer) Register(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "missing_fields", "Username and password required")
 		return
 	}
+	// External backends own their accounts and provision them on login; a
+	// local registration could otherwise claim a name first.
+	if !auth.IsLocal(s.loginAuthenticator()) {
+		writeError(w, http.StatusForbidden, "registration_disabled", "Accounts are managed by the login backend")
+		return
+	}
 	s.logger.Info("Creating account", zap.String("username", reqData.Username))
 	userID, userRights, err := s.createNewUser(ctx, reqData.Username, reqData.Password)
 	if err != nil {
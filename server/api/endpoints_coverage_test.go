@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	cfg "erupe-ce/config"
+	"erupe-ce/server/auth"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -298,6 +300,32 @@ func TestRegisterEndpoint_Success(t *testing.T) {
 	}
 }
 
+func TestRegisterEndpoint_ExternalBackendRefused(t *testing.T) {
+	server := &APIServer{
+		logger:        NewTestLogger(t),
+		erupeConfig:   NewTestConfig(),
+		userRepo:      &mockAPIUserRepo{registerID: 1},
+		sessionRepo:   &mockAPISessionRepo{createTokenID: 10},
+		charRepo:      &mockAPICharacterRepo{},
+		authenticator: &mockAuthenticator{uid: 5},
+	}
+
+	body, _ := json.Marshal(map[string]string{"username": "forumuser", "password": "password123"})
+	rec := httptest.NewRecorder()
+	server.Register(rec, httptest.NewRequest("POST", "/v2/register", bytes.NewReader(body)))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403: %s", rec.Code, rec.Body.String())
+	}
+	var errResp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&errResp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if errResp.Error != "registration_disabled" {
+		t.Errorf("error = %q, want registration_disabled", errResp.Error)
+	}
+}
+
 func TestCreateCharacterEndpoint_Success(t *testing.T) {
 	logger := NewTestLogger(t)
 	c := NewTestConfig()
@@ -443,3 +471,101 @@ func TestExportSaveEndpoint_Success(t *testing.T) {
 		t.Errorf("character name = %v, want TestHunter", resp.Character["name"])
 	}
 }
+
+func postLogin(server *APIServer, username, password string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{"username": username, "password": password})
+	req := httptest.NewRequest("POST", "/v2/login", bytes.NewReader(body))
+	req.RemoteAddr = "10.0.0.1:40000"
+	rec := httptest.NewRecorder()
+	server.Login(rec, req)
+	return rec
+}
+
+func TestLoginEndpoint_LockoutReturns429(t *testing.T) {
+	logger := NewTestLogger(t)
+	c := NewTestConfig()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correct"), bcrypt.MinCost)
+	store := newMockLoginStore()
+
+	server := &APIServer{
+		logger:      logger,
+		erupeConfig: c,
+		userRepo: &mockAPIUserRepo{
+			credentialsID:       1,
+			credentialsPassword: string(hash),
+		},
+		loginGuard: auth.NewGuard(store, cfg.LoginProtectionOptions{
+			Enabled: true, MaxFailures: 3, MaxFailuresPerIP: 20,
+			LockoutSeconds: 60, MaxLockoutSeconds: 600, ResetSeconds: 600,
+		}, logger),
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := postLogin(server, "testuser", "wrong"); rec.Code != http.StatusBadRequest {
+			t.Fatalf("failure %d status = %d, want 400", i+1, rec.Code)
+		}
+	}
+	rec := postLogin(server, "testuser", "wrong")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("locking failure status = %d, want 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "60" {
+		t.Errorf("Retry-After = %q, want 60", rec.Header().Get("Retry-After"))
+	}
+	var errResp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&errResp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if errResp.Error != "too_many_attempts" {
+		t.Errorf("error = %q, want too_many_attempts", errResp.Error)
+	}
+
+	// Even the right password is refused until the lockout ends.
+	if rec := postLogin(server, "testuser", "correct"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("login during lockout status = %d, want 429", rec.Code)
+	}
+	if store.failures["ip:10.0.0.1"] != 3 {
+		t.Errorf("IP failures = %d, want 3", store.failures["ip:10.0.0.1"])
+	}
+}
+
+func TestLoginEndpoint_ExternalAuthenticator(t *testing.T) {
+	tests := []struct {
+		name       string
+		authErr    error
+		wantStatus int
+		wantError  string
+	}{
+		{"accepted", nil, http.StatusOK, ""},
+		{"wrong password", auth.ErrBadPassword, http.StatusBadRequest, "invalid_password"},
+		{"unknown user", auth.ErrUnknownUser, http.StatusBadRequest, "invalid_username"},
+		{"account owned elsewhere", auth.ErrForeignAccount, http.StatusForbidden, "account_conflict"},
+		{"backend down", errors.New("connection refused"), http.StatusServiceUnavailable, "auth_unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &APIServer{
+				logger:        NewTestLogger(t),
+				erupeConfig:   NewTestConfig(),
+				userRepo:      &mockAPIUserRepo{credentialsRights: 30},
+				charRepo:      &mockAPICharacterRepo{},
+				sessionRepo:   &mockAPISessionRepo{createTokenID: 1},
+				authenticator: &mockAuthenticator{uid: 5, err: tt.authErr},
+			}
+			rec := postLogin(server, "forumuser", "pw")
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantError == "" {
+				return
+			}
+			var errResp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&errResp); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if errResp.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", errResp.Error, tt.wantError)
+			}
+		})
+	}
+}
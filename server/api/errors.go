@@ -2,7 +2,10 @@ package api
 
 import (
 	"encoding/json"
+	"erupe-ce/server/auth"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // ErrorResponse is the standard JSON error envelope returned by all API endpoints.
@@ -19,3 +22,10 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 		Message: message,
 	})
 }
+
+// writeLockout writes a 429 for a login refused by the login guard, with a
+// Retry-After header in seconds.
+func writeLockout(w http.ResponseWriter, lock auth.Lockout) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(lock.RetryAfter(time.Now())/time.Second)))
+	writeError(w, http.StatusTooManyRequests, "too_many_attempts", "Too many failed logins, try again later")
+}
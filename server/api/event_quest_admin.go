@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"go.uber.org/zap"
+)
+
+// APIEventQuestScheduler previews the event quest rotation.
+// *channelserver.EventQuestScheduler satisfies it.
+type APIEventQuestScheduler interface {
+	Preview(at time.Time) ([]channelserver.EventQuestLive, error)
+}
+
+// AdminEventQuestPreview handles GET /v2/admin/event-quests/preview. The
+// optional "at" query parameter is an RFC 3339 time and defaults to now.
+func (s *APIServer) AdminEventQuestPreview(w http.ResponseWriter, r *http.Request) {
+	if s.eventQuests == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Event quest scheduling is not available")
+		return
+	}
+	at := time.Now()
+	if v := r.URL.Query().Get("at"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Invalid time, expected RFC 3339")
+			return
+		}
+		at = t
+	}
+	live, err := s.eventQuests.Preview(at)
+	if err != nil {
+		s.logger.Error("Failed to preview event quests", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	writeJSON(w, live)
+}
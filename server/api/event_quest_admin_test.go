@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"erupe-ce/server/channelserver"
+)
+
+// mockEventQuestScheduler implements APIEventQuestScheduler for testing.
+type mockEventQuestScheduler struct {
+	live []channelserver.EventQuestLive
+	err  error
+	at   time.Time
+}
+
+func (m *mockEventQuestScheduler) Preview(at time.Time) ([]channelserver.EventQuestLive, error) {
+	m.at = at
+	return m.live, m.err
+}
+
+func TestAdminEventQuestPreview(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	sched := &mockEventQuestScheduler{live: []channelserver.EventQuestLive{{ID: 3, QuestID: 23527, ExclusiveGroup: "weekly"}}}
+	server.eventQuests = sched
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/event-quests/preview?at=2026-11-01T12:00:00Z", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if !sched.at.Equal(time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("previewed at %v", sched.at)
+	}
+	var live []channelserver.EventQuestLive
+	if err := json.NewDecoder(rec.Body).Decode(&live); err != nil {
+		t.Fatal(err)
+	}
+	if len(live) != 1 || live[0].QuestID != 23527 || live[0].ExclusiveGroup != "weekly" {
+		t.Errorf("live = %+v", live)
+	}
+
+	rec = doAdminRequest(t, server, "GET", "/v2/admin/event-quests/preview?at=tomorrow", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad time: status = %d, want 400", rec.Code)
+	}
+
+	sched.err = errors.New("db down")
+	rec = doAdminRequest(t, server, "GET", "/v2/admin/event-quests/preview", nil)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("error: status = %d, want 500", rec.Code)
+	}
+}
+
+func TestAdminEventQuestPreview_Unavailable(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/event-quests/preview", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
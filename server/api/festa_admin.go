@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+const festaHistoryDefaultLimit = 20
+
+// APIFestaAdmin reports on the Mezeporta Festival and edits its trials and
+// prizes. *channelserver.FestaService satisfies it.
+type APIFestaAdmin interface {
+	Status(now time.Time) (*channelserver.FestaStatus, error)
+	History(limit int) ([]channelserver.FestaResult, error)
+	HistoryDetail(id uint32) (*channelserver.FestaHistoryDetail, error)
+	Trials() ([]channelserver.FestaTrial, error)
+	AddTrial(trial channelserver.FestaTrial) (*channelserver.FestaTrial, error)
+	RemoveTrial(id uint32) error
+	Prizes() ([]channelserver.FestaPrize, error)
+	AddPrize(prize channelserver.FestaPrize) (*channelserver.FestaPrize, error)
+	RemovePrize(id uint32) error
+}
+
+// requireFestaAdmin writes 503 when festa administration is not wired up.
+func (s *APIServer) requireFestaAdmin(w http.ResponseWriter) bool {
+	if s.festaAdmin == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Festa administration is not available")
+		return false
+	}
+	return true
+}
+
+// festaAdminID parses the {id} route variable.
+func festaAdminID(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid ID")
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// writeFestaAdminError maps a festa service error.
+func (s *APIServer) writeFestaAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, channelserver.ErrFestaHistoryNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Festival not found")
+	case errors.Is(err, channelserver.ErrFestaTrialNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Trial not found")
+	case errors.Is(err, channelserver.ErrFestaPrizeNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Prize not found")
+	case errors.Is(err, channelserver.ErrInvalidFesta):
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+	default:
+		s.logger.Error("Festa admin request failed", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+	}
+}
+
+// AdminFestaStatus handles GET /v2/admin/festa.
+func (s *APIServer) AdminFestaStatus(w http.ResponseWriter, r *http.Request) {
+	if !s.requireFestaAdmin(w) {
+		return
+	}
+	status, err := s.festaAdmin.Status(time.Now())
+	if err != nil {
+		s.writeFestaAdminError(w, err)
+		return
+	}
+	writeJSON(w, status)
+}
+
+// AdminFestaHistory handles GET /v2/admin/festa/history.
+func (s *APIServer) AdminFestaHistory(w http.ResponseWriter, r *http.Request) {
+	if !s.requireFestaAdmin(w) {
+		return
+	}
+	limit := festaHistoryDefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Invalid limit")
+			return
+		}
+		limit = n
+	}
+	history, err := s.festaAdmin.History(limit)
+	if err != nil {
+		s.writeFestaAdminError(w, err)
+		return
+	}
+	if history == nil {
+		history = []channelserver.FestaResult{}
+	}
+	writeJSON(w, history)
+}
+
+// AdminFestaHistoryDetail handles GET /v2/admin/festa/history/{id}.
+func (s *APIServer) AdminFestaHistoryDetail(w http.ResponseWriter, r *http.Request) {
+	if !s.requireFestaAdmin(w) {
+		return
+	}
+	id, ok := festaAdminID(w, r)
+	if !ok {
+		return
+	}
+	detail, err := s.festaAdmin.HistoryDetail(id)
+	if err != nil {
+		s.writeFestaAdminError(w, err)
+		return
+	}
+	writeJSON(w, detail)
+}
+
+// AdminFestaTrials handles GET /v2/admin/festa/trials.
+func (s *APIServer) AdminFestaTrials(w http.ResponseWriter, r *http.Request) {
+	if !s.requireFestaAdmin(w) {
+		return
+	}
+	trials, err := s.festaAdmin.Trials()
+	if err != nil {
+		s.writeFestaAdminError(w, err)
+		return
+	}
+	if trials == nil {
+		trials = []channelserver.FestaTrial{}
+	}
+	writeJSON(w, trials)
+}
+
+// AdminAddFestaTrial handles POST /v2/admin/festa/trials.
+func (s *APIServer) AdminAddFestaTrial(w http.ResponseWriter, r *http.Request) {
+	if !s.requireFestaAdmin(w) {
+		return
+	}
+	var req channelserver.FestaTrial
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	trial, err := s.festaAdmin.AddTrial(req)
+	if err != nil {
+		s.writeFestaAdminError(w, err)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Festa trial added via API", zap.Uint32("trialID", trial.ID), zap.Uint32("adminID", admin))
+	writeJSON(w, trial)
+}
+
+// AdminRemoveFestaTrial handles DELETE /v2/admin/festa/trials/{id}.
+func (s *APIServer) AdminRemoveFestaTrial(w http.ResponseWriter, r *http.Request) {
+	if !s.requireFestaAdmin(w) {
+		return
+	}
+	id, ok := festaAdminID(w, r)
+	if !ok {
+		return
+	}
+	if err := s.festaAdmin.RemoveTrial(id); err != nil {
+		s.writeFestaAdminError(w, err)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Festa trial removed via API", zap.Uint32("trialID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, struct{}{})
+}
+
+// AdminFestaPrizes handles GET /v2/admin/festa/prizes.
+func (s *APIServer) AdminFestaPrizes(w http.ResponseWriter, r *http.Request) {
+	if !s.requireFestaAdmin(w) {
+		return
+	}
+	prizes, err := s.festaAdmin.Prizes()
+	if err != nil {
+		s.writeFestaAdminError(w, err)
+		return
+	}
+	if prizes == nil {
+		prizes = []channelserver.FestaPrize{}
+	}
+	writeJSON(w, prizes)
+}
+
+// AdminAddFestaPrize handles POST /v2/admin/festa/prizes.
+func (s *APIServer) AdminAddFestaPrize(w http.ResponseWriter, r *http.Request) {
+	if !s.requireFestaAdmin(w) {
+		return
+	}
+	var req channelserver.FestaPrize
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	prize, err := s.festaAdmin.AddPrize(req)
+	if err != nil {
+		s.writeFestaAdminError(w, err)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Festa prize added via API", zap.Uint32("prizeID", prize.ID), zap.Uint32("adminID", admin))
+	writeJSON(w, prize)
+}
+
+// AdminRemoveFestaPrize handles DELETE /v2/admin/festa/prizes/{id}.
+func (s *APIServer) AdminRemoveFestaPrize(w http.ResponseWriter, r *http.Request) {
+	if !s.requireFestaAdmin(w) {
+		return
+	}
+	id, ok := festaAdminID(w, r)
+	if !ok {
+		return
+	}
+	if err := s.festaAdmin.RemovePrize(id); err != nil {
+		s.writeFestaAdminError(w, err)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Festa prize removed via API", zap.Uint32("prizeID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, struct{}{})
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"erupe-ce/server/channelserver"
+)
+
+// mockFestaAdmin implements APIFestaAdmin for testing.
+type mockFestaAdmin struct {
+	status  channelserver.FestaStatus
+	history []channelserver.FestaResult
+	detail  *channelserver.FestaHistoryDetail
+	trials  []channelserver.FestaTrial
+	err     error
+
+	historyLimit int
+	addedTrial   *channelserver.FestaTrial
+	addedPrize   *channelserver.FestaPrize
+	removedID    uint32
+}
+
+func (m *mockFestaAdmin) Status(_ time.Time) (*channelserver.FestaStatus, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &m.status, nil
+}
+
+func (m *mockFestaAdmin) History(limit int) ([]channelserver.FestaResult, error) {
+	m.historyLimit = limit
+	return m.history, m.err
+}
+
+func (m *mockFestaAdmin) HistoryDetail(_ uint32) (*channelserver.FestaHistoryDetail, error) {
+	return m.detail, m.err
+}
+
+func (m *mockFestaAdmin) Trials() ([]channelserver.FestaTrial, error) { return m.trials, m.err }
+
+func (m *mockFestaAdmin) AddTrial(trial channelserver.FestaTrial) (*channelserver.FestaTrial, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	trial.ID = 12
+	m.addedTrial = &trial
+	return &trial, nil
+}
+
+func (m *mockFestaAdmin) RemoveTrial(id uint32) error {
+	m.removedID = id
+	return m.err
+}
+
+func (m *mockFestaAdmin) Prizes() ([]channelserver.FestaPrize, error) { return nil, m.err }
+
+func (m *mockFestaAdmin) AddPrize(prize channelserver.FestaPrize) (*channelserver.FestaPrize, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	prize.ID = 7
+	m.addedPrize = &prize
+	return &prize, nil
+}
+
+func (m *mockFestaAdmin) RemovePrize(id uint32) error {
+	m.removedID = id
+	return m.err
+}
+
+func newFestaAdminTestServer(t *testing.T) (*APIServer, *mockFestaAdmin) {
+	t.Helper()
+	server, _, _ := newAdminTestServer(t)
+	admin := &mockFestaAdmin{}
+	server.festaAdmin = admin
+	return server, admin
+}
+
+func TestAdminFestaStatus(t *testing.T) {
+	server, admin := newFestaAdminTestServer(t)
+	admin.status = channelserver.FestaStatus{EventID: 4, Phase: channelserver.FestaPhaseSoulRace, BlueSouls: 120, Leader: channelserver.FestivalColorBlue}
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/festa", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var resp channelserver.FestaStatus
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Phase != channelserver.FestaPhaseSoulRace || resp.BlueSouls != 120 || resp.Leader != channelserver.FestivalColorBlue {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestAdminFestaHistory(t *testing.T) {
+	server, admin := newFestaAdminTestServer(t)
+	admin.history = []channelserver.FestaResult{{ID: 3, EventID: 9, Winner: channelserver.FestivalColorRed}}
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/festa/history?limit=5", nil)
+	if rec.Code != http.StatusOK || admin.historyLimit != 5 {
+		t.Fatalf("status = %d, limit %d", rec.Code, admin.historyLimit)
+	}
+	var history []channelserver.FestaResult
+	if err := json.NewDecoder(rec.Body).Decode(&history); err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Winner != channelserver.FestivalColorRed {
+		t.Errorf("history = %+v", history)
+	}
+
+	rec = doAdminRequest(t, server, "GET", "/v2/admin/festa/history?limit=0", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad limit: status = %d, want 400", rec.Code)
+	}
+
+	admin.err = channelserver.ErrFestaHistoryNotFound
+	rec = doAdminRequest(t, server, "GET", "/v2/admin/festa/history/8", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("missing festival: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminAddFestaTrial(t *testing.T) {
+	server, admin := newFestaAdminTestServer(t)
+
+	body := map[string]interface{}{"objective": 1, "goalId": 5, "timesReq": 3, "reward": 10}
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/festa/trials", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if admin.addedTrial == nil || admin.addedTrial.GoalID != 5 || admin.addedTrial.TimesReq != 3 {
+		t.Errorf("added = %+v", admin.addedTrial)
+	}
+
+	admin.err = fmt.Errorf("%w: objective is required", channelserver.ErrInvalidFesta)
+	rec = doAdminRequest(t, server, "POST", "/v2/admin/festa/trials", map[string]int{})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid trial: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminRemoveFestaTrial(t *testing.T) {
+	server, admin := newFestaAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "DELETE", "/v2/admin/festa/trials/12", nil)
+	if rec.Code != http.StatusOK || admin.removedID != 12 {
+		t.Errorf("status = %d, removed %d", rec.Code, admin.removedID)
+	}
+
+	admin.err = channelserver.ErrFestaTrialNotFound
+	rec = doAdminRequest(t, server, "DELETE", "/v2/admin/festa/trials/13", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("missing trial: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminAddAndRemoveFestaPrize(t *testing.T) {
+	server, admin := newFestaAdminTestServer(t)
+
+	body := map[string]interface{}{"type": "guild", "tier": 1, "soulsReq": 100, "itemId": 7011, "numItem": 2}
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/festa/prizes", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if admin.addedPrize == nil || admin.addedPrize.Type != "guild" || admin.addedPrize.SoulsReq != 100 {
+		t.Errorf("added = %+v", admin.addedPrize)
+	}
+
+	rec = doAdminRequest(t, server, "DELETE", "/v2/admin/festa/prizes/7", nil)
+	if rec.Code != http.StatusOK || admin.removedID != 7 {
+		t.Errorf("delete: status = %d, removed %d", rec.Code, admin.removedID)
+	}
+	rec = doAdminRequest(t, server, "DELETE", "/v2/admin/festa/prizes/abc", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad id: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminFesta_Unavailable(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/festa", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
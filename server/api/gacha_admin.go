@@ -0,0 +1,263 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// APIGachaAdmin manages gacha catalogues. *channelserver.GachaAdminService
+// satisfies it.
+type APIGachaAdmin interface {
+	List() ([]channelserver.Gacha, error)
+	Get(id uint32) (*channelserver.GachaCatalogue, error)
+	Create(g channelserver.Gacha) (*channelserver.GachaCatalogue, error)
+	Update(id uint32, g channelserver.Gacha) (*channelserver.GachaCatalogue, error)
+	Delete(id uint32) error
+	CreateEntry(gachaID uint32, e channelserver.GachaCatalogueEntry) (*channelserver.GachaCatalogueEntry, error)
+	UpdateEntry(gachaID, entryID uint32, e channelserver.GachaCatalogueEntry) (*channelserver.GachaCatalogueEntry, error)
+	DeleteEntry(gachaID, entryID uint32) error
+	Rates(id uint32) (*channelserver.GachaRates, error)
+	Simulate(id uint32, req channelserver.GachaSimulationRequest) (*channelserver.GachaSimulation, error)
+}
+
+// adminGacha parses the {id} route variable.
+func (s *APIServer) adminGacha(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	if !s.requireGachaAdmin(w) {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid gacha ID")
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// adminGachaEntry parses the {id} and {entryId} route variables.
+func (s *APIServer) adminGachaEntry(w http.ResponseWriter, r *http.Request) (uint32, uint32, bool) {
+	id, ok := s.adminGacha(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	entryID, err := strconv.ParseUint(mux.Vars(r)["entryId"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid gacha entry ID")
+		return 0, 0, false
+	}
+	return id, uint32(entryID), true
+}
+
+// requireGachaAdmin writes 503 when gacha administration is not wired up.
+func (s *APIServer) requireGachaAdmin(w http.ResponseWriter) bool {
+	if s.gachaAdmin == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Gacha administration is not available")
+		return false
+	}
+	return true
+}
+
+// writeGachaAdminError maps a gacha admin service error.
+func (s *APIServer) writeGachaAdminError(w http.ResponseWriter, err error, gachaID uint32) {
+	switch {
+	case errors.Is(err, channelserver.ErrGachaNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Gacha not found")
+	case errors.Is(err, channelserver.ErrGachaEntryNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Gacha entry not found")
+	case errors.Is(err, channelserver.ErrInvalidGacha):
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+	default:
+		s.logger.Error("Gacha admin request failed", zap.Error(err), zap.Uint32("gachaID", gachaID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+	}
+}
+
+// AdminListGachas handles GET /v2/admin/gachas.
+func (s *APIServer) AdminListGachas(w http.ResponseWriter, r *http.Request) {
+	if !s.requireGachaAdmin(w) {
+		return
+	}
+	gachas, err := s.gachaAdmin.List()
+	if err != nil {
+		s.writeGachaAdminError(w, err, 0)
+		return
+	}
+	if gachas == nil {
+		gachas = []channelserver.Gacha{}
+	}
+	writeJSON(w, gachas)
+}
+
+// AdminCreateGacha handles POST /v2/admin/gachas. Entries are added
+// separately.
+func (s *APIServer) AdminCreateGacha(w http.ResponseWriter, r *http.Request) {
+	if !s.requireGachaAdmin(w) {
+		return
+	}
+	var req channelserver.Gacha
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	c, err := s.gachaAdmin.Create(req)
+	if err != nil {
+		s.writeGachaAdminError(w, err, 0)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Gacha created via API", zap.Uint32("gachaID", c.ID), zap.Uint32("adminID", admin))
+	writeJSON(w, c)
+}
+
+// AdminGetGacha handles GET /v2/admin/gachas/{id}.
+func (s *APIServer) AdminGetGacha(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminGacha(w, r)
+	if !ok {
+		return
+	}
+	c, err := s.gachaAdmin.Get(id)
+	if err != nil {
+		s.writeGachaAdminError(w, err, id)
+		return
+	}
+	writeJSON(w, c)
+}
+
+// AdminUpdateGacha handles PUT /v2/admin/gachas/{id}, replacing the shop
+// definition. Entries are left alone.
+func (s *APIServer) AdminUpdateGacha(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminGacha(w, r)
+	if !ok {
+		return
+	}
+	var req channelserver.Gacha
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	c, err := s.gachaAdmin.Update(id, req)
+	if err != nil {
+		s.writeGachaAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Gacha updated via API", zap.Uint32("gachaID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, c)
+}
+
+// AdminDeleteGacha handles DELETE /v2/admin/gachas/{id}.
+func (s *APIServer) AdminDeleteGacha(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminGacha(w, r)
+	if !ok {
+		return
+	}
+	if err := s.gachaAdmin.Delete(id); err != nil {
+		s.writeGachaAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Gacha deleted via API", zap.Uint32("gachaID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, struct{}{})
+}
+
+// AdminCreateGachaEntry handles POST /v2/admin/gachas/{id}/entries.
+func (s *APIServer) AdminCreateGachaEntry(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminGacha(w, r)
+	if !ok {
+		return
+	}
+	var req channelserver.GachaCatalogueEntry
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	e, err := s.gachaAdmin.CreateEntry(id, req)
+	if err != nil {
+		s.writeGachaAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Gacha entry created via API",
+		zap.Uint32("gachaID", id), zap.Uint32("entryID", e.ID), zap.Uint32("adminID", admin))
+	writeJSON(w, e)
+}
+
+// AdminUpdateGachaEntry handles PUT /v2/admin/gachas/{id}/entries/{entryId},
+// replacing the entry and its items.
+func (s *APIServer) AdminUpdateGachaEntry(w http.ResponseWriter, r *http.Request) {
+	id, entryID, ok := s.adminGachaEntry(w, r)
+	if !ok {
+		return
+	}
+	var req channelserver.GachaCatalogueEntry
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	e, err := s.gachaAdmin.UpdateEntry(id, entryID, req)
+	if err != nil {
+		s.writeGachaAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Gacha entry updated via API",
+		zap.Uint32("gachaID", id), zap.Uint32("entryID", entryID), zap.Uint32("adminID", admin))
+	writeJSON(w, e)
+}
+
+// AdminDeleteGachaEntry handles DELETE /v2/admin/gachas/{id}/entries/{entryId}.
+func (s *APIServer) AdminDeleteGachaEntry(w http.ResponseWriter, r *http.Request) {
+	id, entryID, ok := s.adminGachaEntry(w, r)
+	if !ok {
+		return
+	}
+	if err := s.gachaAdmin.DeleteEntry(id, entryID); err != nil {
+		s.writeGachaAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Gacha entry deleted via API",
+		zap.Uint32("gachaID", id), zap.Uint32("entryID", entryID), zap.Uint32("adminID", admin))
+	writeJSON(w, struct{}{})
+}
+
+// AdminGachaRates handles GET /v2/admin/gachas/{id}/rates, disclosing the real
+// chance of each reward and any catalogue problems.
+func (s *APIServer) AdminGachaRates(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminGacha(w, r)
+	if !ok {
+		return
+	}
+	rates, err := s.gachaAdmin.Rates(id)
+	if err != nil {
+		s.writeGachaAdminError(w, err, id)
+		return
+	}
+	writeJSON(w, rates)
+}
+
+// AdminSimulateGacha handles POST /v2/admin/gachas/{id}/simulate, running a
+// seeded series of plays without charging or rewarding anyone.
+func (s *APIServer) AdminSimulateGacha(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminGacha(w, r)
+	if !ok {
+		return
+	}
+	var req channelserver.GachaSimulationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	sim, err := s.gachaAdmin.Simulate(id, req)
+	if err != nil {
+		s.writeGachaAdminError(w, err, id)
+		return
+	}
+	writeJSON(w, sim)
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"erupe-ce/server/channelserver"
+)
+
+// mockGachaAdmin implements APIGachaAdmin for testing.
+type mockGachaAdmin struct {
+	catalogue channelserver.GachaCatalogue
+	err       error
+
+	requested      *channelserver.Gacha
+	requestedEntry *channelserver.GachaCatalogueEntry
+	entryID        uint32
+	deleted        bool
+	simulation     *channelserver.GachaSimulationRequest
+}
+
+func (m *mockGachaAdmin) List() ([]channelserver.Gacha, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return []channelserver.Gacha{m.catalogue.Gacha}, nil
+}
+
+func (m *mockGachaAdmin) Get(id uint32) (*channelserver.GachaCatalogue, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := m.catalogue
+	return &out, nil
+}
+
+func (m *mockGachaAdmin) Create(g channelserver.Gacha) (*channelserver.GachaCatalogue, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.requested = &g
+	return m.Get(m.catalogue.ID)
+}
+
+func (m *mockGachaAdmin) Update(id uint32, g channelserver.Gacha) (*channelserver.GachaCatalogue, error) {
+	return m.Create(g)
+}
+
+func (m *mockGachaAdmin) Delete(id uint32) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.deleted = true
+	return nil
+}
+
+func (m *mockGachaAdmin) CreateEntry(gachaID uint32, e channelserver.GachaCatalogueEntry) (*channelserver.GachaCatalogueEntry, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.requestedEntry = &e
+	e.ID = 9
+	return &e, nil
+}
+
+func (m *mockGachaAdmin) UpdateEntry(gachaID, entryID uint32, e channelserver.GachaCatalogueEntry) (*channelserver.GachaCatalogueEntry, error) {
+	m.entryID = entryID
+	return m.CreateEntry(gachaID, e)
+}
+
+func (m *mockGachaAdmin) DeleteEntry(gachaID, entryID uint32) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.entryID = entryID
+	return nil
+}
+
+func (m *mockGachaAdmin) Rates(id uint32) (*channelserver.GachaRates, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	shown := 0.25
+	return &channelserver.GachaRates{
+		GachaID:    id,
+		PoolWeight: 100,
+		Entries:    []channelserver.GachaEntryRate{{EntryID: 2, Weight: 50, Probability: 0.5, DisplayedRate: &shown}},
+		Warnings:   []string{"reward entry 2 is displayed at 25.000% but drawn at 50.000%"},
+	}, nil
+}
+
+func (m *mockGachaAdmin) Simulate(id uint32, req channelserver.GachaSimulationRequest) (*channelserver.GachaSimulation, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.simulation = &req
+	return &channelserver.GachaSimulation{GachaID: id, Plays: req.Plays, Seed: req.Seed, Draws: req.Plays}, nil
+}
+
+func newMockGachaAdmin() *mockGachaAdmin {
+	return &mockGachaAdmin{
+		catalogue: channelserver.GachaCatalogue{
+			Gacha:   channelserver.Gacha{ID: 5, Name: "Premium", GachaType: 1},
+			Entries: []channelserver.GachaCatalogueEntry{{GachaEntry: channelserver.GachaEntry{ID: 2, EntryType: 100, Weight: 50}}},
+		},
+	}
+}
+
+func TestAdminCreateAndGetGacha(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockGachaAdmin()
+	server.gachaAdmin = mock
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/gachas", channelserver.Gacha{Name: "Premium", GachaType: 1, MinHR: 30})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create: status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if mock.requested.Name != "Premium" || mock.requested.MinHR != 30 {
+		t.Errorf("requested = %+v", mock.requested)
+	}
+
+	rec = doAdminRequest(t, server, "GET", "/v2/admin/gachas/5", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get: status = %d", rec.Code)
+	}
+	var got map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	// The shop fields sit beside the entries rather than under a nested key.
+	if got["name"] != "Premium" || len(got["entries"].([]interface{})) != 1 {
+		t.Errorf("response = %v", got)
+	}
+
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/gachas", nil); rec.Code != http.StatusOK {
+		t.Errorf("list: status = %d", rec.Code)
+	}
+	if rec := doAdminRequest(t, server, "PUT", "/v2/admin/gachas/5", channelserver.Gacha{Name: "Renamed"}); rec.Code != http.StatusOK {
+		t.Errorf("update: status = %d", rec.Code)
+	}
+	if rec := doAdminRequest(t, server, "DELETE", "/v2/admin/gachas/5", nil); rec.Code != http.StatusOK || !mock.deleted {
+		t.Errorf("delete: status = %d, deleted = %v", rec.Code, mock.deleted)
+	}
+}
+
+func TestAdminGachaEntries(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockGachaAdmin()
+	server.gachaAdmin = mock
+
+	entry := channelserver.GachaCatalogueEntry{
+		GachaEntry: channelserver.GachaEntry{EntryType: 100, Weight: 30, Rarity: 2},
+		Items:      []channelserver.GachaItem{{ItemType: 7, ItemID: 10, Quantity: 2}},
+	}
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/gachas/5/entries", entry)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create entry: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if mock.requestedEntry.Weight != 30 || len(mock.requestedEntry.Items) != 1 || mock.requestedEntry.Items[0].ItemID != 10 {
+		t.Errorf("requested entry = %+v", mock.requestedEntry)
+	}
+
+	rec = doAdminRequest(t, server, "PUT", "/v2/admin/gachas/5/entries/9", entry)
+	if rec.Code != http.StatusOK || mock.entryID != 9 {
+		t.Errorf("update entry: status = %d, entry = %d", rec.Code, mock.entryID)
+	}
+	rec = doAdminRequest(t, server, "DELETE", "/v2/admin/gachas/5/entries/7", nil)
+	if rec.Code != http.StatusOK || mock.entryID != 7 {
+		t.Errorf("delete entry: status = %d, entry = %d", rec.Code, mock.entryID)
+	}
+	if rec := doAdminRequest(t, server, "DELETE", "/v2/admin/gachas/5/entries/x", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("bad entry ID: status = %d, want 400", rec.Code)
+	}
+
+	mock.err = fmt.Errorf("%w: 7", channelserver.ErrGachaEntryNotFound)
+	if rec := doAdminRequest(t, server, "DELETE", "/v2/admin/gachas/5/entries/7", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("missing entry: status = %d, want 404", rec.Code)
+	}
+	mock.err = fmt.Errorf("%w: weight must be a whole number", channelserver.ErrInvalidGacha)
+	if rec := doAdminRequest(t, server, "POST", "/v2/admin/gachas/5/entries", entry); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid entry: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminGachaRatesAndSimulation(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockGachaAdmin()
+	server.gachaAdmin = mock
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/gachas/5/rates", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("rates: status = %d", rec.Code)
+	}
+	var rates channelserver.GachaRates
+	if err := json.NewDecoder(rec.Body).Decode(&rates); err != nil {
+		t.Fatal(err)
+	}
+	if rates.GachaID != 5 || len(rates.Entries) != 1 || *rates.Entries[0].DisplayedRate != 0.25 || len(rates.Warnings) != 1 {
+		t.Errorf("rates = %+v", rates)
+	}
+
+	rec = doAdminRequest(t, server, "POST", "/v2/admin/gachas/5/simulate",
+		channelserver.GachaSimulationRequest{RollType: 1, Plays: 1000, Seed: 42})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("simulate: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if mock.simulation.RollType != 1 || mock.simulation.Plays != 1000 || mock.simulation.Seed != 42 {
+		t.Errorf("simulation request = %+v", mock.simulation)
+	}
+
+	mock.err = fmt.Errorf("%w: plays must be 1-100000", channelserver.ErrInvalidGacha)
+	rec = doAdminRequest(t, server, "POST", "/v2/admin/gachas/5/simulate", channelserver.GachaSimulationRequest{})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid simulation: status = %d, want 400", rec.Code)
+	}
+	mock.err = fmt.Errorf("%w: 6", channelserver.ErrGachaNotFound)
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/gachas/6/rates", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("missing gacha: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminGachas_Unavailable(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/gachas", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"go.uber.org/zap"
+)
+
+// APIGachaHistory reads the gacha play ledger.
+// *channelserver.GachaHistoryService satisfies it.
+type APIGachaHistory interface {
+	History(f channelserver.GachaPlayFilter) ([]channelserver.GachaPlay, error)
+}
+
+// requireGachaHistory writes 503 when the gacha play ledger is not wired up.
+func (s *APIServer) requireGachaHistory(w http.ResponseWriter) bool {
+	if s.gachaHistory == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Gacha history is not available")
+		return false
+	}
+	return true
+}
+
+// parseGachaPlayFilter reads the gachaId, since, until, before and limit
+// query parameters shared by the player and admin endpoints. It returns a
+// message describing the first invalid parameter.
+func parseGachaPlayFilter(q url.Values) (channelserver.GachaPlayFilter, string) {
+	var f channelserver.GachaPlayFilter
+	if v := q.Get("gachaId"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return f, "Invalid gachaId"
+		}
+		f.GachaID = uint32(n)
+	}
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{{"since", &f.Since}, {"until", &f.Until}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return f, "Invalid " + p.name + ", expected RFC 3339"
+			}
+			*p.dst = &t
+		}
+	}
+	if v := q.Get("before"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return f, "Invalid before"
+		}
+		f.BeforeID = n
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return f, "Invalid limit"
+		}
+		f.Limit = n
+	}
+	return f, ""
+}
+
+// writeGachaHistoryError maps a gacha history service error.
+func (s *APIServer) writeGachaHistoryError(w http.ResponseWriter, err error) {
+	if errors.Is(err, channelserver.ErrInvalidGachaHistory) {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	s.logger.Error("Gacha history request failed", zap.Error(err))
+	writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+}
+
+// GachaHistory handles GET /v2/characters/{id}/gacha-history, listing a
+// character's gacha plays newest first. Pass the last play's ID as "before"
+// to fetch the next page.
+func (s *APIServer) GachaHistory(w http.ResponseWriter, r *http.Request) {
+	if s.gachaHistory == nil || s.charRepo == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Gacha history is not available")
+		return
+	}
+	charID, ok := s.ownedCharacter(w, r)
+	if !ok {
+		return
+	}
+	f, msg := parseGachaPlayFilter(r.URL.Query())
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", msg)
+		return
+	}
+	f.CharID = charID
+	plays, err := s.gachaHistory.History(f)
+	if err != nil {
+		s.writeGachaHistoryError(w, err)
+		return
+	}
+	writeJSON(w, plays)
+}
+
+// AdminGachaHistory handles GET /v2/admin/gacha-history for refund
+// investigations. On top of the player filters it accepts userId and charId;
+// leaving both out searches every account.
+func (s *APIServer) AdminGachaHistory(w http.ResponseWriter, r *http.Request) {
+	if !s.requireGachaHistory(w) {
+		return
+	}
+	q := r.URL.Query()
+	f, msg := parseGachaPlayFilter(q)
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", msg)
+		return
+	}
+	for _, p := range []struct {
+		name string
+		dst  *uint32
+	}{{"userId", &f.UserID}, {"charId", &f.CharID}} {
+		if v := q.Get(p.name); v != "" {
+			n, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid_request", "Invalid "+p.name)
+				return
+			}
+			*p.dst = uint32(n)
+		}
+	}
+	plays, err := s.gachaHistory.History(f)
+	if err != nil {
+		s.writeGachaHistoryError(w, err)
+		return
+	}
+	writeJSON(w, plays)
+}
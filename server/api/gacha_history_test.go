@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"erupe-ce/server/channelserver"
+)
+
+// mockGachaHistory implements APIGachaHistory for testing.
+type mockGachaHistory struct {
+	plays []channelserver.GachaPlay
+	err   error
+
+	filter channelserver.GachaPlayFilter
+}
+
+func (m *mockGachaHistory) History(f channelserver.GachaPlayFilter) ([]channelserver.GachaPlay, error) {
+	m.filter = f
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.plays, nil
+}
+
+func newMockGachaHistory() *mockGachaHistory {
+	return &mockGachaHistory{plays: []channelserver.GachaPlay{{
+		ID: 7, UserID: 2, CharID: 20, GachaID: 3, Kind: channelserver.GachaPlayNormal,
+		CostType: 19, Cost: 5, Currency: channelserver.GachaCurrencyTrialCoins,
+		Items: []channelserver.GachaPlayItem{{ItemType: 7, ItemID: 500, Quantity: 2, Rarity: 3}},
+	}}}
+}
+
+func TestGachaHistory(t *testing.T) {
+	server, _, _ := newSaveHistoryTestServer(t)
+	history := newMockGachaHistory()
+	server.gachaHistory = history
+
+	rec := doAdminRequest(t, server, "GET", "/v2/characters/20/gacha-history?gachaId=3&before=9&limit=10", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var plays []channelserver.GachaPlay
+	if err := json.NewDecoder(rec.Body).Decode(&plays); err != nil {
+		t.Fatal(err)
+	}
+	if len(plays) != 1 || plays[0].Currency != channelserver.GachaCurrencyTrialCoins || len(plays[0].Items) != 1 {
+		t.Errorf("plays = %+v", plays)
+	}
+	want := channelserver.GachaPlayFilter{CharID: 20, GachaID: 3, BeforeID: 9, Limit: 10}
+	if history.filter != want {
+		t.Errorf("filter = %+v, want %+v", history.filter, want)
+	}
+}
+
+func TestGachaHistory_NotOwner(t *testing.T) {
+	server, _, chars := newSaveHistoryTestServer(t)
+	server.gachaHistory = newMockGachaHistory()
+	chars.isOwnerResult = false
+
+	rec := doAdminRequest(t, server, "GET", "/v2/characters/30/gacha-history", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestGachaHistory_InvalidQuery(t *testing.T) {
+	server, _, _ := newSaveHistoryTestServer(t)
+	history := newMockGachaHistory()
+	server.gachaHistory = history
+
+	for _, q := range []string{"gachaId=x", "since=yesterday", "before=-1", "limit=0"} {
+		rec := doAdminRequest(t, server, "GET", "/v2/characters/20/gacha-history?"+q, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", q, rec.Code)
+		}
+	}
+
+	history.err = fmt.Errorf("%w: since must be before until", channelserver.ErrInvalidGachaHistory)
+	rec := doAdminRequest(t, server, "GET", "/v2/characters/20/gacha-history", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("service rejection: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminGachaHistory(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	history := newMockGachaHistory()
+	server.gachaHistory = history
+
+	rec := doAdminRequest(t, server, "GET",
+		"/v2/admin/gacha-history?userId=2&charId=20&since=2026-10-01T00:00:00Z&until=2026-10-02T00:00:00Z", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	f := history.filter
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	if f.UserID != 2 || f.CharID != 20 || f.Since == nil || !f.Since.Equal(since) || f.Until == nil || !f.Until.Equal(since.Add(24*time.Hour)) {
+		t.Errorf("filter = %+v", f)
+	}
+
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/gacha-history?userId=me", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid userId: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminGachaHistory_RequiresOperator(t *testing.T) {
+	server, _, sessions := newAdminTestServer(t)
+	server.gachaHistory = newMockGachaHistory()
+	sessions.userID = 2
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/gacha-history", nil)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestGachaHistory_Unavailable(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/gacha-history", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("admin: status = %d, want 503", rec.Code)
+	}
+	if rec := doAdminRequest(t, server, "GET", "/v2/characters/20/gacha-history", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("player: status = %d, want 503", rec.Code)
+	}
+}
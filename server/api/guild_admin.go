@@ -0,0 +1,214 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	cfg "erupe-ce/config"
+	"erupe-ce/server/channelserver"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// Paging bounds for GET /v2/admin/guilds/{id}/audit.
+const (
+	guildAuditDefaultLimit = 50
+	guildAuditMaxLimit     = 500
+)
+
+// APIGuildAdmin performs operator changes to guilds and reads their audit
+// log. *channelserver.GuildService satisfies it.
+type APIGuildAdmin interface {
+	ForceRank(actor channelserver.GuildAuditActor, guildID uint32, rank uint16, mode cfg.Mode) (*channelserver.Guild, error)
+	SetRankRP(actor channelserver.GuildAuditActor, guildID, rp uint32) (*channelserver.Guild, error)
+	Rename(actor channelserver.GuildAuditActor, guildID uint32, name string) (*channelserver.Guild, error)
+	TransferLeader(actor channelserver.GuildAuditActor, guildID, charID uint32) (*channelserver.Guild, error)
+	UpdateGuildcard(actor channelserver.GuildAuditActor, guildID uint32, card channelserver.GuildCard) (*channelserver.Guild, error)
+	AuditLog(guildID uint32, limit int) ([]*channelserver.GuildAuditEntry, error)
+}
+
+// AdminGuildResponse is the guild returned by the admin guild endpoints.
+type AdminGuildResponse struct {
+	ID         uint32 `json:"id"`
+	Name       string `json:"name"`
+	LeaderID   uint32 `json:"leaderId"`
+	LeaderName string `json:"leaderName"`
+	Rank       uint16 `json:"rank"`
+	RankRP     uint32 `json:"rankRP"`
+	MainMotto  uint8  `json:"mainMotto"`
+	SubMotto   uint8  `json:"subMotto"`
+	Comment    string `json:"comment"`
+}
+
+func (s *APIServer) newAdminGuildResponse(g *channelserver.Guild) AdminGuildResponse {
+	return AdminGuildResponse{
+		ID:         g.ID,
+		Name:       g.Name,
+		LeaderID:   g.LeaderCharID,
+		LeaderName: g.LeaderName,
+		Rank:       g.Rank(s.erupeConfig.RealClientMode),
+		RankRP:     g.RankRP,
+		MainMotto:  g.MainMotto,
+		SubMotto:   g.SubMotto,
+		Comment:    g.Comment,
+	}
+}
+
+// AdminGuildRankRequest is the body of PUT /v2/admin/guilds/{id}/rank.
+// Exactly one of rank and rankRP must be set: rank moves the guild to the
+// start of that rank, rankRP sets the RP directly.
+type AdminGuildRankRequest struct {
+	Rank   *uint16 `json:"rank"`
+	RankRP *uint32 `json:"rankRP"`
+}
+
+// AdminGuildNameRequest is the body of PUT /v2/admin/guilds/{id}/name.
+type AdminGuildNameRequest struct {
+	Name string `json:"name"`
+}
+
+// AdminGuildLeaderRequest is the body of PUT /v2/admin/guilds/{id}/leader.
+type AdminGuildLeaderRequest struct {
+	CharID uint32 `json:"charId"`
+}
+
+// adminGuild parses the {id} route variable and returns the calling
+// operator as the audit actor.
+func (s *APIServer) adminGuild(w http.ResponseWriter, r *http.Request) (uint32, channelserver.GuildAuditActor, bool) {
+	if s.guildAdmin == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Guild administration is not available")
+		return 0, channelserver.GuildAuditActor{}, false
+	}
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid guild ID")
+		return 0, channelserver.GuildAuditActor{}, false
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	return uint32(id), channelserver.GuildAuditActor{Source: channelserver.GuildAuditSourceAPI, ID: admin}, true
+}
+
+// writeGuildAdminResult writes the updated guild or maps the service error.
+func (s *APIServer) writeGuildAdminResult(w http.ResponseWriter, guild *channelserver.Guild, err error, guildID uint32) {
+	switch {
+	case err == nil:
+		writeJSON(w, s.newAdminGuildResponse(guild))
+	case errors.Is(err, channelserver.ErrGuildNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Guild not found")
+	case errors.Is(err, channelserver.ErrInvalidGuildRank), errors.Is(err, channelserver.ErrInvalidGuildEdit):
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+	case errors.Is(err, channelserver.ErrNotGuildMember):
+		writeError(w, http.StatusBadRequest, "not_member", "Character is not a member of the guild")
+	default:
+		s.logger.Error("Guild admin request failed", zap.Error(err), zap.Uint32("guildID", guildID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+	}
+}
+
+// AdminSetGuildRank handles PUT /v2/admin/guilds/{id}/rank.
+func (s *APIServer) AdminSetGuildRank(w http.ResponseWriter, r *http.Request) {
+	guildID, actor, ok := s.adminGuild(w, r)
+	if !ok {
+		return
+	}
+	var req AdminGuildRankRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || (req.Rank == nil) == (req.RankRP == nil) {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Body must set exactly one of rank and rankRP")
+		return
+	}
+	var guild *channelserver.Guild
+	var err error
+	if req.Rank != nil {
+		guild, err = s.guildAdmin.ForceRank(actor, guildID, *req.Rank, s.erupeConfig.RealClientMode)
+	} else {
+		guild, err = s.guildAdmin.SetRankRP(actor, guildID, *req.RankRP)
+	}
+	if err == nil {
+		s.logger.Info("Guild rank set via API", zap.Uint32("guildID", guildID), zap.Uint32("adminID", actor.ID))
+	}
+	s.writeGuildAdminResult(w, guild, err, guildID)
+}
+
+// AdminRenameGuild handles PUT /v2/admin/guilds/{id}/name.
+func (s *APIServer) AdminRenameGuild(w http.ResponseWriter, r *http.Request) {
+	guildID, actor, ok := s.adminGuild(w, r)
+	if !ok {
+		return
+	}
+	var req AdminGuildNameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Malformed request body")
+		return
+	}
+	guild, err := s.guildAdmin.Rename(actor, guildID, req.Name)
+	if err == nil {
+		s.logger.Info("Guild renamed via API", zap.Uint32("guildID", guildID), zap.Uint32("adminID", actor.ID))
+	}
+	s.writeGuildAdminResult(w, guild, err, guildID)
+}
+
+// AdminSetGuildLeader handles PUT /v2/admin/guilds/{id}/leader.
+func (s *APIServer) AdminSetGuildLeader(w http.ResponseWriter, r *http.Request) {
+	guildID, actor, ok := s.adminGuild(w, r)
+	if !ok {
+		return
+	}
+	var req AdminGuildLeaderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.CharID == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Malformed request body")
+		return
+	}
+	guild, err := s.guildAdmin.TransferLeader(actor, guildID, req.CharID)
+	if err == nil {
+		s.logger.Info("Guild leader set via API",
+			zap.Uint32("guildID", guildID), zap.Uint32("adminID", actor.ID), zap.Uint32("charID", req.CharID))
+	}
+	s.writeGuildAdminResult(w, guild, err, guildID)
+}
+
+// AdminUpdateGuildcard handles PUT /v2/admin/guilds/{id}/card, replacing the
+// guild's mottos and comment.
+func (s *APIServer) AdminUpdateGuildcard(w http.ResponseWriter, r *http.Request) {
+	guildID, actor, ok := s.adminGuild(w, r)
+	if !ok {
+		return
+	}
+	var req channelserver.GuildCard
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Malformed request body")
+		return
+	}
+	guild, err := s.guildAdmin.UpdateGuildcard(actor, guildID, req)
+	if err == nil {
+		s.logger.Info("Guild card updated via API", zap.Uint32("guildID", guildID), zap.Uint32("adminID", actor.ID))
+	}
+	s.writeGuildAdminResult(w, guild, err, guildID)
+}
+
+// AdminGuildAudit handles GET /v2/admin/guilds/{id}/audit, newest entries
+// first. The optional limit parameter bounds the number returned.
+func (s *APIServer) AdminGuildAudit(w http.ResponseWriter, r *http.Request) {
+	guildID, _, ok := s.adminGuild(w, r)
+	if !ok {
+		return
+	}
+	limit := guildAuditDefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Invalid limit")
+			return
+		}
+		limit = min(n, guildAuditMaxLimit)
+	}
+	entries, err := s.guildAdmin.AuditLog(guildID, limit)
+	if err != nil {
+		s.logger.Error("Failed to read guild audit log", zap.Error(err), zap.Uint32("guildID", guildID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	writeJSON(w, entries)
+}
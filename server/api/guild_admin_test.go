@@ -0,0 +1,205 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	cfg "erupe-ce/config"
+	"erupe-ce/server/channelserver"
+)
+
+// mockGuildAdmin implements APIGuildAdmin for testing.
+type mockGuildAdmin struct {
+	guild   channelserver.Guild
+	entries []*channelserver.GuildAuditEntry
+	err     error
+
+	actor      channelserver.GuildAuditActor
+	rank       uint16
+	leaderID   uint32
+	auditLimit int
+}
+
+func (m *mockGuildAdmin) result(actor channelserver.GuildAuditActor) (*channelserver.Guild, error) {
+	m.actor = actor
+	if m.err != nil {
+		return nil, m.err
+	}
+	g := m.guild
+	return &g, nil
+}
+
+func (m *mockGuildAdmin) ForceRank(actor channelserver.GuildAuditActor, _ uint32, rank uint16, _ cfg.Mode) (*channelserver.Guild, error) {
+	m.rank = rank
+	return m.result(actor)
+}
+
+func (m *mockGuildAdmin) SetRankRP(actor channelserver.GuildAuditActor, _, rp uint32) (*channelserver.Guild, error) {
+	m.guild.RankRP = rp
+	return m.result(actor)
+}
+
+func (m *mockGuildAdmin) Rename(actor channelserver.GuildAuditActor, _ uint32, name string) (*channelserver.Guild, error) {
+	m.guild.Name = name
+	return m.result(actor)
+}
+
+func (m *mockGuildAdmin) TransferLeader(actor channelserver.GuildAuditActor, _, charID uint32) (*channelserver.Guild, error) {
+	m.leaderID = charID
+	return m.result(actor)
+}
+
+func (m *mockGuildAdmin) UpdateGuildcard(actor channelserver.GuildAuditActor, _ uint32, card channelserver.GuildCard) (*channelserver.Guild, error) {
+	m.guild.Comment = card.Comment
+	return m.result(actor)
+}
+
+func (m *mockGuildAdmin) AuditLog(_ uint32, limit int) ([]*channelserver.GuildAuditEntry, error) {
+	m.auditLimit = limit
+	return m.entries, m.err
+}
+
+func newGuildAdminTestServer(t *testing.T) (*APIServer, *mockGuildAdmin) {
+	t.Helper()
+	server, _, _ := newAdminTestServer(t)
+	admin := &mockGuildAdmin{guild: channelserver.Guild{ID: 10, Name: "Guild"}}
+	server.guildAdmin = admin
+	return server, admin
+}
+
+func TestAdminSetGuildRank(t *testing.T) {
+	server, admin := newGuildAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "PUT", "/v2/admin/guilds/10/rank", map[string]int{"rank": 7})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if admin.rank != 7 {
+		t.Errorf("rank = %d, want 7", admin.rank)
+	}
+	if admin.actor.Source != channelserver.GuildAuditSourceAPI || admin.actor.ID != 1 {
+		t.Errorf("actor = %+v, want api user 1", admin.actor)
+	}
+
+	rec = doAdminRequest(t, server, "PUT", "/v2/admin/guilds/10/rank", map[string]int{"rankRP": 900})
+	var resp AdminGuildResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.RankRP != 900 || resp.ID != 10 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestAdminSetGuildRank_InvalidBody(t *testing.T) {
+	server, _ := newGuildAdminTestServer(t)
+
+	for _, body := range []interface{}{map[string]int{}, map[string]int{"rank": 1, "rankRP": 2}, "rank"} {
+		rec := doAdminRequest(t, server, "PUT", "/v2/admin/guilds/10/rank", body)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%v: status = %d, want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestAdminRenameGuild(t *testing.T) {
+	server, admin := newGuildAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "PUT", "/v2/admin/guilds/10/name", AdminGuildNameRequest{Name: "Renamed"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if admin.guild.Name != "Renamed" {
+		t.Errorf("name = %q", admin.guild.Name)
+	}
+}
+
+func TestAdminSetGuildLeader(t *testing.T) {
+	server, admin := newGuildAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "PUT", "/v2/admin/guilds/10/leader", AdminGuildLeaderRequest{CharID: 21})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if admin.leaderID != 21 {
+		t.Errorf("leader = %d, want 21", admin.leaderID)
+	}
+}
+
+func TestAdminUpdateGuildcard(t *testing.T) {
+	server, admin := newGuildAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "PUT", "/v2/admin/guilds/10/card", channelserver.GuildCard{Comment: "Hi"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if admin.guild.Comment != "Hi" {
+		t.Errorf("comment = %q", admin.guild.Comment)
+	}
+}
+
+func TestAdminGuild_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{channelserver.ErrGuildNotFound, http.StatusNotFound},
+		{channelserver.ErrInvalidGuildEdit, http.StatusBadRequest},
+		{channelserver.ErrNotGuildMember, http.StatusBadRequest},
+		{errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		server, admin := newGuildAdminTestServer(t)
+		admin.err = tt.err
+
+		rec := doAdminRequest(t, server, "PUT", "/v2/admin/guilds/10/name", AdminGuildNameRequest{Name: "x"})
+		if rec.Code != tt.want {
+			t.Errorf("%v: status = %d, want %d", tt.err, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestAdminGuild_RequiresOp(t *testing.T) {
+	server, admin := newGuildAdminTestServer(t)
+	server.sessionRepo = &mockAPISessionRepo{userID: 2}
+
+	rec := doAdminRequest(t, server, "PUT", "/v2/admin/guilds/10/name", AdminGuildNameRequest{Name: "x"})
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+	if admin.guild.Name != "Guild" {
+		t.Error("non-operator renamed the guild")
+	}
+}
+
+func TestAdminGuildAudit(t *testing.T) {
+	server, admin := newGuildAdminTestServer(t)
+	admin.entries = []*channelserver.GuildAuditEntry{{ID: 1, GuildID: 10, Action: channelserver.GuildAuditName}}
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/guilds/10/audit?limit=1000", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if admin.auditLimit != guildAuditMaxLimit {
+		t.Errorf("limit = %d, want %d", admin.auditLimit, guildAuditMaxLimit)
+	}
+	var entries []channelserver.GuildAuditEntry
+	if err := json.NewDecoder(rec.Body).Decode(&entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Action != "name" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestAdminGuild_Unavailable(t *testing.T) {
+	server, _ := newGuildAdminTestServer(t)
+	server.guildAdmin = nil
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/guilds/10/audit", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// APIMailBroadcasts sends admin mail to characters matching a filter.
+// *channelserver.MailBroadcastService satisfies it.
+type APIMailBroadcasts interface {
+	Create(b channelserver.MailBroadcast) (*channelserver.MailBroadcast, bool, error)
+	Get(id uint32) (*channelserver.MailBroadcast, error)
+	List(limit int) ([]channelserver.MailBroadcast, error)
+	Send(id uint32) (*channelserver.MailBroadcast, error)
+	SetNotifier(n channelserver.MailNotifier)
+}
+
+// AdminMailBroadcastRequest is the body of POST /v2/admin/mail-broadcasts.
+// The idempotency key may also be sent in the Idempotency-Key header.
+type AdminMailBroadcastRequest struct {
+	IdempotencyKey string                            `json:"idempotencyKey"`
+	SenderID       uint32                            `json:"senderId"`
+	Subject        string                            `json:"subject"`
+	Body           string                            `json:"body"`
+	Filter         channelserver.MailBroadcastFilter `json:"filter"`
+	Items          []channelserver.MailBroadcastItem `json:"items"`
+}
+
+// SetMailNotifier wires the channel registry used to send the new mail popup
+// to broadcast recipients who are online.
+func (s *APIServer) SetMailNotifier(n channelserver.MailNotifier) {
+	if s.mailBroadcasts != nil {
+		s.mailBroadcasts.SetNotifier(n)
+	}
+}
+
+// requireMailBroadcasts writes 503 when mail broadcasts are not wired up.
+func (s *APIServer) requireMailBroadcasts(w http.ResponseWriter) bool {
+	if s.mailBroadcasts == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Mail broadcasts are not available")
+		return false
+	}
+	return true
+}
+
+// mailBroadcastID parses the {id} route variable.
+func mailBroadcastID(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid broadcast ID")
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// writeMailBroadcastError maps a mail broadcast service error.
+func (s *APIServer) writeMailBroadcastError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, channelserver.ErrMailBroadcastNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Broadcast not found")
+	case errors.Is(err, channelserver.ErrInvalidMailBroadcast):
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+	default:
+		s.logger.Error("Mail broadcast request failed", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+	}
+}
+
+// sendMailBroadcast runs a broadcast to completion in the background.
+func (s *APIServer) sendMailBroadcast(id uint32) {
+	if _, err := s.mailBroadcasts.Send(id); err != nil {
+		s.logger.Error("Mail broadcast stopped; resume it to continue", zap.Error(err), zap.Uint32("broadcastID", id))
+	}
+}
+
+// AdminListMailBroadcasts handles GET /v2/admin/mail-broadcasts.
+func (s *APIServer) AdminListMailBroadcasts(w http.ResponseWriter, r *http.Request) {
+	if !s.requireMailBroadcasts(w) {
+		return
+	}
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Invalid limit")
+			return
+		}
+		limit = n
+	}
+	list, err := s.mailBroadcasts.List(limit)
+	if err != nil {
+		s.writeMailBroadcastError(w, err)
+		return
+	}
+	if list == nil {
+		list = []channelserver.MailBroadcast{}
+	}
+	writeJSON(w, list)
+}
+
+// AdminCreateMailBroadcast handles POST /v2/admin/mail-broadcasts. A new
+// broadcast is sent in the background and answered with 202; repeating the
+// idempotency key returns the existing broadcast with 200, resuming it if it
+// had stopped.
+func (s *APIServer) AdminCreateMailBroadcast(w http.ResponseWriter, r *http.Request) {
+	if !s.requireMailBroadcasts(w) {
+		return
+	}
+	var req AdminMailBroadcastRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = r.Header.Get("Idempotency-Key")
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	b, created, err := s.mailBroadcasts.Create(channelserver.MailBroadcast{
+		IdempotencyKey: req.IdempotencyKey,
+		SenderID:       req.SenderID,
+		Subject:        req.Subject,
+		Body:           req.Body,
+		Filter:         req.Filter,
+		Items:          req.Items,
+		CreatedBy:      admin,
+	})
+	if err != nil {
+		s.writeMailBroadcastError(w, err)
+		return
+	}
+	if b.FinishedAt == nil {
+		go s.sendMailBroadcast(b.ID)
+	}
+	if created {
+		s.logger.Info("Mail broadcast started via API", zap.Uint32("broadcastID", b.ID),
+			zap.Int("recipients", b.Total), zap.Uint32("adminID", admin))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(b)
+		return
+	}
+	writeJSON(w, b)
+}
+
+// AdminGetMailBroadcast handles GET /v2/admin/mail-broadcasts/{id}.
+func (s *APIServer) AdminGetMailBroadcast(w http.ResponseWriter, r *http.Request) {
+	if !s.requireMailBroadcasts(w) {
+		return
+	}
+	id, ok := mailBroadcastID(w, r)
+	if !ok {
+		return
+	}
+	b, err := s.mailBroadcasts.Get(id)
+	if err != nil {
+		s.writeMailBroadcastError(w, err)
+		return
+	}
+	writeJSON(w, b)
+}
+
+// AdminResumeMailBroadcast handles POST /v2/admin/mail-broadcasts/{id}/resume,
+// continuing a broadcast that stopped part way from its last sent batch.
+func (s *APIServer) AdminResumeMailBroadcast(w http.ResponseWriter, r *http.Request) {
+	if !s.requireMailBroadcasts(w) {
+		return
+	}
+	id, ok := mailBroadcastID(w, r)
+	if !ok {
+		return
+	}
+	b, err := s.mailBroadcasts.Get(id)
+	if err != nil {
+		s.writeMailBroadcastError(w, err)
+		return
+	}
+	if b.FinishedAt != nil {
+		writeError(w, http.StatusConflict, "conflict", "Broadcast has already finished")
+		return
+	}
+	go s.sendMailBroadcast(b.ID)
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Mail broadcast resumed via API", zap.Uint32("broadcastID", b.ID), zap.Uint32("adminID", admin))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(b)
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"erupe-ce/server/channelserver"
+)
+
+// mockMailBroadcasts implements APIMailBroadcasts for testing.
+type mockMailBroadcasts struct {
+	broadcast channelserver.MailBroadcast
+	created   bool
+	err       error
+
+	requested *channelserver.MailBroadcast
+	sent      chan uint32
+}
+
+func (m *mockMailBroadcasts) Create(b channelserver.MailBroadcast) (*channelserver.MailBroadcast, bool, error) {
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	m.requested = &b
+	out := m.broadcast
+	return &out, m.created, nil
+}
+
+func (m *mockMailBroadcasts) Get(id uint32) (*channelserver.MailBroadcast, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := m.broadcast
+	return &out, nil
+}
+
+func (m *mockMailBroadcasts) List(_ int) ([]channelserver.MailBroadcast, error) {
+	return []channelserver.MailBroadcast{m.broadcast}, m.err
+}
+
+func (m *mockMailBroadcasts) Send(id uint32) (*channelserver.MailBroadcast, error) {
+	out := m.broadcast
+	m.sent <- id
+	return &out, nil
+}
+
+func (m *mockMailBroadcasts) SetNotifier(_ channelserver.MailNotifier) {}
+
+func newMockMailBroadcasts() *mockMailBroadcasts {
+	return &mockMailBroadcasts{
+		broadcast: channelserver.MailBroadcast{ID: 4, IdempotencyKey: "maint", Subject: "Sorry", Total: 10},
+		created:   true,
+		sent:      make(chan uint32, 1),
+	}
+}
+
+func waitMailBroadcastSent(t *testing.T, m *mockMailBroadcasts) uint32 {
+	t.Helper()
+	select {
+	case id := <-m.sent:
+		return id
+	case <-time.After(time.Second):
+		t.Fatal("broadcast was not sent")
+		return 0
+	}
+}
+
+func TestAdminCreateMailBroadcast(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockMailBroadcasts()
+	server.mailBroadcasts = mock
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/mail-broadcasts", AdminMailBroadcastRequest{
+		IdempotencyKey: "maint",
+		SenderID:       1,
+		Subject:        "Sorry",
+		Filter:         channelserver.MailBroadcastFilter{MinHR: 100},
+		Items:          []channelserver.MailBroadcastItem{{ItemID: 7, Quantity: 3}},
+	})
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", rec.Code, rec.Body.String())
+	}
+	if id := waitMailBroadcastSent(t, mock); id != 4 {
+		t.Errorf("sent broadcast %d, want 4", id)
+	}
+	if mock.requested.Filter.MinHR != 100 || len(mock.requested.Items) != 1 || mock.requested.Items[0].Quantity != 3 {
+		t.Errorf("requested = %+v", mock.requested)
+	}
+	var got channelserver.MailBroadcast
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != 4 || got.Total != 10 {
+		t.Errorf("response = %+v", got)
+	}
+}
+
+func TestAdminCreateMailBroadcast_Repeated(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockMailBroadcasts()
+	mock.created = false
+	finished := time.Now()
+	mock.broadcast.FinishedAt = &finished
+	server.mailBroadcasts = mock
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/mail-broadcasts", AdminMailBroadcastRequest{IdempotencyKey: "maint"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	select {
+	case <-mock.sent:
+		t.Error("finished broadcast was sent again")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestAdminCreateMailBroadcast_Invalid(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockMailBroadcasts()
+	mock.err = fmt.Errorf("%w: subject is required", channelserver.ErrInvalidMailBroadcast)
+	server.mailBroadcasts = mock
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/mail-broadcasts", AdminMailBroadcastRequest{IdempotencyKey: "maint"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+	rec = doAdminRequest(t, server, "POST", "/v2/admin/mail-broadcasts", "not an object")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("malformed body: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminMailBroadcastIdempotencyHeader(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockMailBroadcasts()
+	server.mailBroadcasts = mock
+
+	req := httptest.NewRequest("POST", "/v2/admin/mail-broadcasts",
+		strings.NewReader(`{"senderId":1,"subject":"Sorry","filter":{"all":true}}`))
+	req.Header.Set("Authorization", "Bearer valid-token")
+	req.Header.Set("Idempotency-Key", "from-header")
+	rec := httptest.NewRecorder()
+	newTestRouter(server).ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", rec.Code, rec.Body.String())
+	}
+	waitMailBroadcastSent(t, mock)
+	if mock.requested.IdempotencyKey != "from-header" {
+		t.Errorf("key = %q, want the header value", mock.requested.IdempotencyKey)
+	}
+}
+
+func TestAdminGetAndResumeMailBroadcast(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	mock := newMockMailBroadcasts()
+	server.mailBroadcasts = mock
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/mail-broadcasts/4", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get: status = %d", rec.Code)
+	}
+	rec = doAdminRequest(t, server, "GET", "/v2/admin/mail-broadcasts", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list: status = %d", rec.Code)
+	}
+
+	rec = doAdminRequest(t, server, "POST", "/v2/admin/mail-broadcasts/4/resume", nil)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("resume: status = %d, want 202", rec.Code)
+	}
+	waitMailBroadcastSent(t, mock)
+
+	finished := time.Now()
+	mock.broadcast.FinishedAt = &finished
+	rec = doAdminRequest(t, server, "POST", "/v2/admin/mail-broadcasts/4/resume", nil)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("resume finished: status = %d, want 409", rec.Code)
+	}
+
+	mock.err = fmt.Errorf("%w: 9", channelserver.ErrMailBroadcastNotFound)
+	rec = doAdminRequest(t, server, "GET", "/v2/admin/mail-broadcasts/9", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("missing: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminMailBroadcasts_Unavailable(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/mail-broadcasts", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"strings"
+
+	"go.uber.org/zap"
 )
 
 type contextKey string
@@ -20,6 +22,17 @@ func UserIDFromContext(ctx context.Context) (uint32, bool) {
 // AuthMiddleware extracts a Bearer token from the Authorization header,
 // validates it, and injects the user ID into the request context.
 func (s *APIServer) AuthMiddleware(next http.Handler) http.Handler {
+	return s.authenticate(next, false)
+}
+
+// AdminMiddleware is AuthMiddleware for the /v2/admin routes: the token must
+// also belong to a user with the op flag set, otherwise the request is
+// rejected with 403.
+func (s *APIServer) AdminMiddleware(next http.Handler) http.Handler {
+	return s.authenticate(next, true)
+}
+
+func (s *APIServer) authenticate(next http.Handler, requireAdmin bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		auth := r.Header.Get("Authorization")
 		if auth == "" || !strings.HasPrefix(auth, "Bearer ") {
@@ -32,6 +45,18 @@ func (s *APIServer) AuthMiddleware(next http.Handler) http.Handler {
 			writeError(w, http.StatusUnauthorized, "unauthorized", "Invalid or expired token")
 			return
 		}
+		if requireAdmin {
+			op, err := s.isOp(r.Context(), userID)
+			if err != nil {
+				s.logger.Error("Failed to check operator status", zap.Error(err), zap.Uint32("userID", userID))
+				writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+				return
+			}
+			if !op {
+				writeError(w, http.StatusForbidden, "forbidden", "Administrator rights required")
+				return
+			}
+		}
 		ctx := context.WithValue(r.Context(), userIDKey, userID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// Paging bounds for GET /v2/raviente/history.
+const (
+	ravienteHistoryDefaultLimit = 20
+	ravienteHistoryMaxLimit     = 200
+)
+
+// APIRaviente reads saved Raviente sieges and queues start requests for the
+// channels running them. *channelserver.RavienteRepository satisfies it.
+type APIRaviente interface {
+	ListOpenSieges() ([]channelserver.RavienteSiege, error)
+	ListEndedSieges(limit int) ([]channelserver.RavienteSiege, error)
+	GetSiege(id uint32) (*channelserver.RavienteSiege, error)
+	GetParticipants(id uint32) ([]channelserver.RavienteParticipant, error)
+	RequestStart(id uint32) (bool, error)
+}
+
+// RavienteSiegeResponse is a Raviente siege as returned by the API. Phase is
+// the furthest phase (1-5) with damage recorded and Started reports whether
+// the siege has begun or is still waiting for players.
+type RavienteSiegeResponse struct {
+	ID           uint32                        `json:"id"`
+	ServerID     uint16                        `json:"serverId"`
+	RaviID       uint16                        `json:"raviId"`
+	Started      bool                          `json:"started"`
+	Phase        int                           `json:"phase"`
+	Damage       uint64                        `json:"damage"`
+	Players      int                           `json:"players"`
+	Multiplier   float64                       `json:"multiplier"`
+	CreatedAt    time.Time                     `json:"createdAt"`
+	UpdatedAt    time.Time                     `json:"updatedAt"`
+	EndedAt      *time.Time                    `json:"endedAt,omitempty"`
+	Summary      *RavienteSummaryResponse      `json:"summary,omitempty"`
+	Participants []RavienteParticipantResponse `json:"participants,omitempty"`
+}
+
+// RavienteSummaryResponse is the outcome of a finished siege.
+type RavienteSummaryResponse struct {
+	PhaseReached int    `json:"phaseReached"`
+	Damage       uint64 `json:"damage"`
+	Participants int    `json:"participants"`
+}
+
+// RavienteParticipantResponse is a character that joined a siege.
+type RavienteParticipantResponse struct {
+	CharID   uint32    `json:"charId"`
+	Name     string    `json:"name"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// RavienteWindowResponse is the siege window open now or opening next.
+type RavienteWindowResponse struct {
+	Open  bool      `json:"open"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// RavienteStatusResponse is the body of GET /v2/raviente.
+type RavienteStatusResponse struct {
+	Sieges []RavienteSiegeResponse `json:"sieges"`
+	Window *RavienteWindowResponse `json:"window,omitempty"`
+}
+
+// RavienteHistoryResponse is the body of GET /v2/raviente/history.
+type RavienteHistoryResponse struct {
+	Sieges []RavienteSiegeResponse `json:"sieges"`
+}
+
+func newRavienteSiegeResponse(siege channelserver.RavienteSiege) RavienteSiegeResponse {
+	resp := RavienteSiegeResponse{
+		ID:         siege.ID,
+		ServerID:   siege.ServerID,
+		RaviID:     siege.RaviID,
+		Started:    siege.Started(),
+		Phase:      siege.Phase(),
+		Damage:     siege.Damage(),
+		Players:    siege.Players,
+		Multiplier: siege.Multiplier,
+		CreatedAt:  siege.CreatedAt,
+		UpdatedAt:  siege.UpdatedAt,
+		EndedAt:    siege.EndedAt,
+	}
+	if siege.Summary != nil {
+		resp.Summary = &RavienteSummaryResponse{
+			PhaseReached: siege.Summary.PhaseReached,
+			Damage:       siege.Summary.Damage,
+			Participants: siege.Summary.Participants,
+		}
+	}
+	return resp
+}
+
+// openSieges returns every channel's open siege, or nil when Raviente data
+// is not available.
+func (s *APIServer) openSieges() ([]RavienteSiegeResponse, error) {
+	if s.raviente == nil {
+		return nil, nil
+	}
+	sieges, err := s.raviente.ListOpenSieges()
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]RavienteSiegeResponse, len(sieges))
+	for i, siege := range sieges {
+		resp[i] = newRavienteSiegeResponse(siege)
+	}
+	return resp, nil
+}
+
+// requireRaviente writes 503 when Raviente data is not available.
+func (s *APIServer) requireRaviente(w http.ResponseWriter) bool {
+	if s.raviente == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Raviente data is not available")
+		return false
+	}
+	return true
+}
+
+// RavienteStatus handles GET /v2/raviente, listing open sieges and the
+// scheduled siege window.
+func (s *APIServer) RavienteStatus(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRaviente(w) {
+		return
+	}
+	sieges, err := s.openSieges()
+	if err != nil {
+		s.logger.Error("Failed to read Raviente sieges", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	resp := RavienteStatusResponse{Sieges: sieges}
+	now := time.Now()
+	windows := s.erupeConfig.Raviente.Windows
+	if start, end, ok := channelserver.NextRavienteWindow(windows, now); ok {
+		resp.Window = &RavienteWindowResponse{
+			Open:  channelserver.RavienteWindowOpen(windows, now),
+			Start: start,
+			End:   end,
+		}
+	}
+	writeJSON(w, resp)
+}
+
+// RavienteHistory handles GET /v2/raviente/history, listing the summaries of
+// the most recently ended sieges.
+func (s *APIServer) RavienteHistory(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRaviente(w) {
+		return
+	}
+	limit := ravienteHistoryDefaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "invalid_request", "Invalid limit")
+			return
+		}
+		limit = min(n, ravienteHistoryMaxLimit)
+	}
+	sieges, err := s.raviente.ListEndedSieges(limit)
+	if err != nil {
+		s.logger.Error("Failed to read Raviente history", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	resp := RavienteHistoryResponse{Sieges: make([]RavienteSiegeResponse, len(sieges))}
+	for i, siege := range sieges {
+		resp.Sieges[i] = newRavienteSiegeResponse(siege)
+	}
+	writeJSON(w, resp)
+}
+
+// ravienteSiegeID parses the {id} route variable.
+func ravienteSiegeID(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid siege ID")
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// AdminGetRaviente handles GET /v2/admin/raviente/{id}, returning a siege
+// with the characters that joined it.
+func (s *APIServer) AdminGetRaviente(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRaviente(w) {
+		return
+	}
+	id, ok := ravienteSiegeID(w, r)
+	if !ok {
+		return
+	}
+	siege, err := s.raviente.GetSiege(id)
+	if err != nil {
+		s.logger.Error("Failed to read Raviente siege", zap.Error(err), zap.Uint32("siegeID", id))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	if siege == nil {
+		writeError(w, http.StatusNotFound, "not_found", "Siege not found")
+		return
+	}
+	participants, err := s.raviente.GetParticipants(id)
+	if err != nil {
+		s.logger.Error("Failed to read Raviente participants", zap.Error(err), zap.Uint32("siegeID", id))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	resp := newRavienteSiegeResponse(*siege)
+	resp.Participants = make([]RavienteParticipantResponse, len(participants))
+	for i, p := range participants {
+		resp.Participants[i] = RavienteParticipantResponse{CharID: p.CharID, Name: p.Name, JoinedAt: p.JoinedAt}
+	}
+	writeJSON(w, resp)
+}
+
+// AdminStartRaviente handles POST /v2/admin/raviente/{id}/start. The channel
+// running the siege starts it at its next sync, so the response is 202.
+func (s *APIServer) AdminStartRaviente(w http.ResponseWriter, r *http.Request) {
+	if !s.requireRaviente(w) {
+		return
+	}
+	id, ok := ravienteSiegeID(w, r)
+	if !ok {
+		return
+	}
+	queued, err := s.raviente.RequestStart(id)
+	if err != nil {
+		s.logger.Error("Failed to request Raviente start", zap.Error(err), zap.Uint32("siegeID", id))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return
+	}
+	if !queued {
+		writeError(w, http.StatusNotFound, "not_found", "No open siege with that ID")
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Raviente start requested via API", zap.Uint32("siegeID", id), zap.Uint32("adminID", admin))
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cfg "erupe-ce/config"
+	"erupe-ce/server/channelserver"
+)
+
+// mockRaviente implements APIRaviente for testing.
+type mockRaviente struct {
+	open         []channelserver.RavienteSiege
+	ended        []channelserver.RavienteSiege
+	participants []channelserver.RavienteParticipant
+	err          error
+
+	historyLimit int
+	startedID    uint32
+}
+
+func (m *mockRaviente) ListOpenSieges() ([]channelserver.RavienteSiege, error) {
+	return m.open, m.err
+}
+
+func (m *mockRaviente) ListEndedSieges(limit int) ([]channelserver.RavienteSiege, error) {
+	m.historyLimit = limit
+	return m.ended, m.err
+}
+
+func (m *mockRaviente) GetSiege(id uint32) (*channelserver.RavienteSiege, error) {
+	for _, siege := range append(m.open, m.ended...) {
+		if siege.ID == id {
+			return &siege, m.err
+		}
+	}
+	return nil, m.err
+}
+
+func (m *mockRaviente) GetParticipants(uint32) ([]channelserver.RavienteParticipant, error) {
+	return m.participants, m.err
+}
+
+func (m *mockRaviente) RequestStart(id uint32) (bool, error) {
+	for _, siege := range m.open {
+		if siege.ID == id {
+			m.startedID = id
+			return true, m.err
+		}
+	}
+	return false, m.err
+}
+
+func testSiege(id uint32, started bool) channelserver.RavienteSiege {
+	siege := channelserver.RavienteSiege{
+		ID: id, ServerID: 0x1010, RaviID: 3, Players: 6, Multiplier: 4,
+		Register: make([]uint32, 30), State: make([]uint32, 30), Support: make([]uint32, 30),
+	}
+	siege.State[0], siege.State[1] = 800, 200
+	if started {
+		siege.Register[1] = 1
+	}
+	return siege
+}
+
+func newRavienteTestServer(t *testing.T) (*APIServer, *mockRaviente) {
+	t.Helper()
+	server, _, _ := newAdminTestServer(t)
+	ended := testSiege(1, true)
+	endedAt := time.Date(2026, 10, 17, 21, 0, 0, 0, time.UTC)
+	ended.EndedAt = &endedAt
+	ended.Summary = &channelserver.RavienteSummary{PhaseReached: 2, Damage: 1000, Participants: 9}
+	ravi := &mockRaviente{
+		open:  []channelserver.RavienteSiege{testSiege(2, true)},
+		ended: []channelserver.RavienteSiege{ended},
+		participants: []channelserver.RavienteParticipant{
+			{CharID: 5, Name: "Hunter"},
+		},
+	}
+	server.raviente = ravi
+	return server, ravi
+}
+
+func TestRavienteStatus(t *testing.T) {
+	server, _ := newRavienteTestServer(t)
+	server.erupeConfig.Raviente.Windows = []cfg.RavienteWindow{{Start: "20:00", Minutes: 60}}
+
+	rec := doAdminRequest(t, server, "GET", "/v2/raviente", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp RavienteStatusResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Sieges) != 1 {
+		t.Fatalf("sieges = %d, want 1", len(resp.Sieges))
+	}
+	got := resp.Sieges[0]
+	if got.ID != 2 || !got.Started || got.Phase != 2 || got.Damage != 1000 || got.Players != 6 || got.Multiplier != 4 {
+		t.Errorf("siege = %+v", got)
+	}
+	if resp.Window == nil || resp.Window.End.Sub(resp.Window.Start) != time.Hour {
+		t.Errorf("window = %+v, want a one hour window", resp.Window)
+	}
+}
+
+func TestRavienteHistory(t *testing.T) {
+	server, ravi := newRavienteTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/raviente/history?limit=5000", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp RavienteHistoryResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if ravi.historyLimit != ravienteHistoryMaxLimit {
+		t.Errorf("limit = %d, want capped at %d", ravi.historyLimit, ravienteHistoryMaxLimit)
+	}
+	if len(resp.Sieges) != 1 || resp.Sieges[0].Summary == nil || resp.Sieges[0].Summary.Participants != 9 {
+		t.Errorf("history = %+v", resp.Sieges)
+	}
+
+	if rec := doAdminRequest(t, server, "GET", "/v2/raviente/history?limit=0", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("limit=0 status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminGetRaviente(t *testing.T) {
+	server, _ := newRavienteTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/raviente/2", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp RavienteSiegeResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != 2 || len(resp.Participants) != 1 || resp.Participants[0].Name != "Hunter" {
+		t.Errorf("siege = %+v", resp)
+	}
+
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/raviente/99", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown siege status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminStartRaviente(t *testing.T) {
+	server, ravi := newRavienteTestServer(t)
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/raviente/2/start", nil)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", rec.Code, rec.Body.String())
+	}
+	if ravi.startedID != 2 {
+		t.Errorf("start requested for %d, want 2", ravi.startedID)
+	}
+
+	if rec := doAdminRequest(t, server, "POST", "/v2/admin/raviente/1/start", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("ended siege status = %d, want 404", rec.Code)
+	}
+}
+
+func TestRaviente_Unavailable(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	for _, path := range []string{"/v2/raviente", "/v2/raviente/history", "/v2/admin/raviente/1"} {
+		if rec := doAdminRequest(t, server, "GET", path, nil); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s status = %d, want 503", path, rec.Code)
+		}
+	}
+}
+
+func TestDashboardStatsJSON_Raviente(t *testing.T) {
+	server, _ := newRavienteTestServer(t)
+
+	rec := httptest.NewRecorder()
+	server.DashboardStatsJSON(rec, httptest.NewRequest(http.MethodGet, "/api/dashboard/stats", nil))
+
+	var stats DashboardStats
+	if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Raviente) != 1 || stats.Raviente[0].Phase != 2 {
+		t.Errorf("raviente = %+v, want the open siege", stats.Raviente)
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// APIAdminRepository implements APIAdminRepo with PostgreSQL.
+type APIAdminRepository struct {
+	db    *sqlx.DB
+	users *channelserver.UserRepository
+}
+
+// NewAPIAdminRepository creates a new APIAdminRepository.
+func NewAPIAdminRepository(db *sqlx.DB) *APIAdminRepository {
+	return &APIAdminRepository{db: db, users: channelserver.NewUserRepository(db)}
+}
+
+// adminUserColumns selects an AdminUser. A ban only counts while it has no
+// expiry or the expiry is still in the future, matching the sign server's
+// login check.
+const adminUserColumns = `u.id, u.username, u.rights, COALESCE(u.op, false) AS op, u.last_login,
+	(b.user_id IS NOT NULL AND (b.expires IS NULL OR b.expires > now())) AS banned,
+	b.expires AS ban_expires
+	FROM users u LEFT JOIN bans b ON b.user_id = u.id`
+
+func (r *APIAdminRepository) IsOp(ctx context.Context, userID uint32) (bool, error) {
+	var op bool
+	err := r.db.QueryRowContext(ctx, "SELECT COALESCE(op, false) FROM users WHERE id = $1", userID).Scan(&op)
+	return op, err
+}
+
+func (r *APIAdminRepository) SearchUsers(ctx context.Context, query string, limit, offset int) ([]AdminUser, error) {
+	users := []AdminUser{}
+	err := r.db.SelectContext(ctx, &users, `SELECT `+adminUserColumns+`
+		WHERE $1 = '' OR u.username ILIKE '%' || $1 || '%'
+			OR EXISTS (SELECT 1 FROM characters c WHERE c.user_id = u.id AND c.name ILIKE '%' || $1 || '%')
+		ORDER BY u.id LIMIT $2 OFFSET $3`, query, limit, offset)
+	return users, err
+}
+
+func (r *APIAdminRepository) GetUser(ctx context.Context, userID uint32) (AdminUser, error) {
+	var user AdminUser
+	err := r.db.GetContext(ctx, &user, `SELECT `+adminUserColumns+` WHERE u.id = $1`, userID)
+	return user, err
+}
+
+// BanUser goes through the channel server's UserRepository, so bans issued
+// here and through the in-game ban command are interchangeable.
+func (r *APIAdminRepository) BanUser(_ context.Context, userID uint32, expires *time.Time) error {
+	return r.users.BanUser(userID, expires)
+}
+
+func (r *APIAdminRepository) UnbanUser(ctx context.Context, userID uint32) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM bans WHERE user_id = $1", userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *APIAdminRepository) SetRights(ctx context.Context, userID uint32, rights uint32) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE users SET rights = $1 WHERE id = $2", rights, userID)
+	return err
+}
+
+func (r *APIAdminRepository) GetCharIDs(ctx context.Context, userID uint32) ([]uint32, error) {
+	var ids []uint32
+	err := r.db.SelectContext(ctx, &ids, "SELECT id FROM characters WHERE user_id = $1", userID)
+	return ids, err
+}
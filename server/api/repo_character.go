@@ -168,3 +168,11 @@ func (r *APICharacterRepository) ImportSave(ctx context.Context, charID, userID
 	}
 	return tx.Commit()
 }
+
+func (r *APICharacterRepository) IsOwner(ctx context.Context, charID, userID uint32) (bool, error) {
+	var owner bool
+	err := r.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM characters WHERE id=$1 AND user_id=$2)", charID, userID,
+	).Scan(&owner)
+	return owner, err
+}
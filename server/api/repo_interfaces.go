@@ -37,6 +37,8 @@ type APIUserRepo interface {
 	Register(ctx context.Context, username, passwordHash string, returnExpires time.Time) (id uint32, rights uint32, err error)
 	// GetCredentials returns the user's ID, password hash, and rights.
 	GetCredentials(ctx context.Context, username string) (id uint32, passwordHash string, rights uint32, err error)
+	// GetRights returns the user's rights bitmask.
+	GetRights(ctx context.Context, uid uint32) (uint32, error)
 	// GetLastLogin returns the user's last login time.
 	GetLastLogin(uid uint32) (time.Time, error)
 	// GetReturnExpiry returns the user's return expiry time.
@@ -72,6 +74,8 @@ type APICharacterRepo interface {
 	// ImportSave atomically validates+consumes the import token and writes all save blobs.
 	// Returns an error if the token is invalid, expired, or the character doesn't belong to userID.
 	ImportSave(ctx context.Context, charID, userID uint32, token string, blobs SaveBlobs) error
+	// IsOwner reports whether charID belongs to userID.
+	IsOwner(ctx context.Context, charID, userID uint32) (bool, error)
 }
 
 // APIEventRepo defines the contract for read-only event data access.
@@ -101,3 +105,33 @@ type APISessionRepo interface {
 	// GetUserIDByToken returns the user ID for a given session token.
 	GetUserIDByToken(ctx context.Context, token string) (uint32, error)
 }
+
+// AdminUser is a user account as seen by the moderation endpoints.
+type AdminUser struct {
+	ID         uint32     `db:"id"`
+	Username   string     `db:"username"`
+	Rights     uint32     `db:"rights"`
+	Op         bool       `db:"op"`
+	LastLogin  *time.Time `db:"last_login"`
+	Banned     bool       `db:"banned"`
+	BanExpires *time.Time `db:"ban_expires"`
+}
+
+// APIAdminRepo defines the contract for account moderation data access.
+type APIAdminRepo interface {
+	// IsOp returns whether the user has operator privileges.
+	IsOp(ctx context.Context, userID uint32) (bool, error)
+	// SearchUsers returns users whose username or character name contains
+	// query, ordered by ID. An empty query matches every user.
+	SearchUsers(ctx context.Context, query string, limit, offset int) ([]AdminUser, error)
+	// GetUser returns a single user by ID.
+	GetUser(ctx context.Context, userID uint32) (AdminUser, error)
+	// BanUser bans a user. A nil expires means a permanent ban.
+	BanUser(ctx context.Context, userID uint32, expires *time.Time) error
+	// UnbanUser lifts a user's ban and reports whether there was one.
+	UnbanUser(ctx context.Context, userID uint32) (bool, error)
+	// SetRights overwrites the user's rights bitmask.
+	SetRights(ctx context.Context, userID uint32, rights uint32) error
+	// GetCharIDs returns the IDs of every character owned by the user.
+	GetCharIDs(ctx context.Context, userID uint32) ([]uint32, error)
+}
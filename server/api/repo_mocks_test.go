@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
@@ -16,6 +17,8 @@ type mockAPIUserRepo struct {
 	credentialsRights   uint32
 	credentialsErr      error
 
+	rightsErr error
+
 	lastLogin    time.Time
 	lastLoginErr error
 
@@ -34,6 +37,10 @@ func (m *mockAPIUserRepo) GetCredentials(_ context.Context, _ string) (uint32, s
 	return m.credentialsID, m.credentialsPassword, m.credentialsRights, m.credentialsErr
 }
 
+func (m *mockAPIUserRepo) GetRights(_ context.Context, _ uint32) (uint32, error) {
+	return m.credentialsRights, m.rightsErr
+}
+
 func (m *mockAPIUserRepo) GetLastLogin(_ uint32) (time.Time, error) {
 	return m.lastLogin, m.lastLoginErr
 }
@@ -76,6 +83,9 @@ type mockAPICharacterRepo struct {
 	grantImportTokenErr  error
 	revokeImportTokenErr error
 	importSaveErr        error
+
+	isOwnerResult bool
+	isOwnerErr    error
 }
 
 func (m *mockAPICharacterRepo) GetNewCharacter(_ context.Context, _ uint32) (Character, error) {
@@ -122,6 +132,10 @@ func (m *mockAPICharacterRepo) ImportSave(_ context.Context, _, _ uint32, _ stri
 	return m.importSaveErr
 }
 
+func (m *mockAPICharacterRepo) IsOwner(_ context.Context, _, _ uint32) (bool, error) {
+	return m.isOwnerResult, m.isOwnerErr
+}
+
 // mockAPIEventRepo implements APIEventRepo for testing.
 type mockAPIEventRepo struct {
 	featureWeapon    *FeatureWeaponRow
@@ -155,3 +169,110 @@ func (m *mockAPISessionRepo) CreateToken(_ context.Context, _ uint32, _ string)
 func (m *mockAPISessionRepo) GetUserIDByToken(_ context.Context, _ string) (uint32, error) {
 	return m.userID, m.userIDErr
 }
+
+// mockAPIAdminRepo implements APIAdminRepo for testing. Users are keyed by ID;
+// ops lists the IDs with operator privileges.
+type mockAPIAdminRepo struct {
+	users   map[uint32]*AdminUser
+	ops     map[uint32]bool
+	charIDs map[uint32][]uint32
+
+	isOpErr   error
+	searchErr error
+	banErr    error
+
+	lastQuery  string
+	lastLimit  int
+	lastOffset int
+}
+
+func (m *mockAPIAdminRepo) IsOp(_ context.Context, userID uint32) (bool, error) {
+	return m.ops[userID], m.isOpErr
+}
+
+func (m *mockAPIAdminRepo) SearchUsers(_ context.Context, query string, limit, offset int) ([]AdminUser, error) {
+	m.lastQuery, m.lastLimit, m.lastOffset = query, limit, offset
+	if m.searchErr != nil {
+		return nil, m.searchErr
+	}
+	var users []AdminUser
+	for _, u := range m.users {
+		users = append(users, *u)
+	}
+	return users, nil
+}
+
+func (m *mockAPIAdminRepo) GetUser(_ context.Context, userID uint32) (AdminUser, error) {
+	u, ok := m.users[userID]
+	if !ok {
+		return AdminUser{}, sql.ErrNoRows
+	}
+	return *u, nil
+}
+
+func (m *mockAPIAdminRepo) BanUser(_ context.Context, userID uint32, expires *time.Time) error {
+	if m.banErr != nil {
+		return m.banErr
+	}
+	u := m.users[userID]
+	u.Banned = true
+	u.BanExpires = expires
+	return nil
+}
+
+func (m *mockAPIAdminRepo) UnbanUser(_ context.Context, userID uint32) (bool, error) {
+	u, ok := m.users[userID]
+	if !ok || !u.Banned {
+		return false, nil
+	}
+	u.Banned = false
+	u.BanExpires = nil
+	return true, nil
+}
+
+func (m *mockAPIAdminRepo) SetRights(_ context.Context, userID uint32, rights uint32) error {
+	m.users[userID].Rights = rights
+	return nil
+}
+
+func (m *mockAPIAdminRepo) GetCharIDs(_ context.Context, userID uint32) ([]uint32, error) {
+	return m.charIDs[userID], nil
+}
+
+// --- mockLoginStore ---
+
+// mockLoginStore is an in-memory auth.LoginStore.
+type mockLoginStore struct {
+	failures map[string]int
+	locked   map[string]time.Time
+}
+
+func newMockLoginStore() *mockLoginStore {
+	return &mockLoginStore{failures: map[string]int{}, locked: map[string]time.Time{}}
+}
+
+func (m *mockLoginStore) LockedUntil(key string) (time.Time, error) { return m.locked[key], nil }
+func (m *mockLoginStore) AddFailure(key string, _, _ time.Time) (int, error) {
+	m.failures[key]++
+	return m.failures[key], nil
+}
+func (m *mockLoginStore) SetLockedUntil(key string, until time.Time) error {
+	m.locked[key] = until
+	return nil
+}
+func (m *mockLoginStore) ClearFailures(key string) error {
+	delete(m.failures, key)
+	return nil
+}
+func (m *mockLoginStore) CountAccountCreations(_ string, _ time.Time) (int, error) { return 0, nil }
+func (m *mockLoginStore) AddAccountCreation(_ string, _ uint32, _ time.Time) error { return nil }
+
+// mockAuthenticator implements auth.Authenticator for testing.
+type mockAuthenticator struct {
+	uid uint32
+	err error
+}
+
+func (m *mockAuthenticator) Authenticate(context.Context, string, string) (uint32, error) {
+	return m.uid, m.err
+}
@@ -43,6 +43,12 @@ func (r *APIUserRepository) GetCredentials(ctx context.Context, username string)
 	return id, passwordHash, rights, err
 }
 
+func (r *APIUserRepository) GetRights(ctx context.Context, uid uint32) (uint32, error) {
+	var rights uint32
+	err := r.db.QueryRowContext(ctx, "SELECT rights FROM users WHERE id = $1", uid).Scan(&rights)
+	return rights, err
+}
+
 func (r *APIUserRepository) GetLastLogin(uid uint32) (time.Time, error) {
 	var lastLogin time.Time
 	err := r.db.Get(&lastLogin, "SELECT COALESCE(last_login, now()) FROM users WHERE id=$1", uid)
@@ -6,9 +6,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"erupe-ce/common/metrics"
+
 	"github.com/gorilla/mux"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -26,6 +29,9 @@ func newTestRouter(s *APIServer) *mux.Router {
 	r.HandleFunc("/character/export", s.ExportSave)
 	r.HandleFunc("/health", s.Health)
 	r.HandleFunc("/version", s.Version)
+	if s.metrics != nil {
+		r.Handle("/metrics", s.metrics).Methods("GET")
+	}
 
 	// V2 routes
 	v2 := r.PathPrefix("/v2").Subrouter()
@@ -42,9 +48,86 @@ func newTestRouter(s *APIServer) *mux.Router {
 	v2Auth.HandleFunc("/characters/{id}/delete", s.DeleteCharacter).Methods("POST")
 	v2Auth.HandleFunc("/characters/{id}", s.DeleteCharacter).Methods("DELETE")
 	v2Auth.HandleFunc("/characters/{id}/export", s.ExportSave).Methods("GET")
+	v2Auth.HandleFunc("/characters/{id}/backups", s.ListBackups).Methods("GET")
+	v2Auth.HandleFunc("/characters/{id}/backups/diff", s.DiffBackups).Methods("GET")
+	v2Auth.HandleFunc("/characters/{id}/backups/{slot}/restore", s.RestoreBackup).Methods("POST")
+	v2Auth.HandleFunc("/characters/{id}/gacha-history", s.GachaHistory).Methods("GET")
 
 	v2.HandleFunc("/server/status", s.ServerStatus).Methods("GET")
 	v2.HandleFunc("/server/info", s.ServerInfo).Methods("GET")
+	v2.HandleFunc("/raviente", s.RavienteStatus).Methods("GET")
+	v2.HandleFunc("/raviente/history", s.RavienteHistory).Methods("GET")
+
+	// V2 admin routes
+	v2Admin := v2.PathPrefix("/admin").Subrouter()
+	v2Admin.Use(s.AdminMiddleware)
+	v2Admin.HandleFunc("/users", s.AdminListUsers).Methods("GET")
+	v2Admin.HandleFunc("/users/{id}", s.AdminGetUser).Methods("GET")
+	v2Admin.HandleFunc("/users/{id}/ban", s.AdminBanUser).Methods("POST")
+	v2Admin.HandleFunc("/users/{id}/ban", s.AdminUnbanUser).Methods("DELETE")
+	v2Admin.HandleFunc("/users/{id}/rights", s.AdminSetRights).Methods("PUT")
+	v2Admin.HandleFunc("/users/{id}/kick", s.AdminKickUser).Methods("POST")
+	v2Admin.HandleFunc("/guilds/{id}/rank", s.AdminSetGuildRank).Methods("PUT")
+	v2Admin.HandleFunc("/guilds/{id}/name", s.AdminRenameGuild).Methods("PUT")
+	v2Admin.HandleFunc("/guilds/{id}/leader", s.AdminSetGuildLeader).Methods("PUT")
+	v2Admin.HandleFunc("/guilds/{id}/card", s.AdminUpdateGuildcard).Methods("PUT")
+	v2Admin.HandleFunc("/guilds/{id}/audit", s.AdminGuildAudit).Methods("GET")
+	v2Admin.HandleFunc("/raviente/{id}", s.AdminGetRaviente).Methods("GET")
+	v2Admin.HandleFunc("/raviente/{id}/start", s.AdminStartRaviente).Methods("POST")
+	v2Admin.HandleFunc("/tournaments", s.AdminListTournaments).Methods("GET")
+	v2Admin.HandleFunc("/tournaments", s.AdminCreateTournament).Methods("POST")
+	v2Admin.HandleFunc("/tournaments/{id}", s.AdminGetTournament).Methods("GET")
+	v2Admin.HandleFunc("/tournaments/{id}", s.AdminUpdateTournament).Methods("PUT")
+	v2Admin.HandleFunc("/tournaments/{id}", s.AdminDeleteTournament).Methods("DELETE")
+	v2Admin.HandleFunc("/tournaments/{id}/results", s.AdminTournamentResults).Methods("GET")
+	v2Admin.HandleFunc("/tournaments/{id}/results/{resultId}", s.AdminReviewTournamentResult).Methods("PUT")
+	v2Admin.HandleFunc("/festa", s.AdminFestaStatus).Methods("GET")
+	v2Admin.HandleFunc("/festa/history", s.AdminFestaHistory).Methods("GET")
+	v2Admin.HandleFunc("/festa/history/{id}", s.AdminFestaHistoryDetail).Methods("GET")
+	v2Admin.HandleFunc("/festa/trials", s.AdminFestaTrials).Methods("GET")
+	v2Admin.HandleFunc("/festa/trials", s.AdminAddFestaTrial).Methods("POST")
+	v2Admin.HandleFunc("/festa/trials/{id}", s.AdminRemoveFestaTrial).Methods("DELETE")
+	v2Admin.HandleFunc("/festa/prizes", s.AdminFestaPrizes).Methods("GET")
+	v2Admin.HandleFunc("/festa/prizes", s.AdminAddFestaPrize).Methods("POST")
+	v2Admin.HandleFunc("/festa/prizes/{id}", s.AdminRemoveFestaPrize).Methods("DELETE")
+	v2Admin.HandleFunc("/event-quests/preview", s.AdminEventQuestPreview).Methods("GET")
+	v2Admin.HandleFunc("/mail-broadcasts", s.AdminListMailBroadcasts).Methods("GET")
+	v2Admin.HandleFunc("/mail-broadcasts", s.AdminCreateMailBroadcast).Methods("POST")
+	v2Admin.HandleFunc("/mail-broadcasts/{id}", s.AdminGetMailBroadcast).Methods("GET")
+	v2Admin.HandleFunc("/mail-broadcasts/{id}/resume", s.AdminResumeMailBroadcast).Methods("POST")
+	v2Admin.HandleFunc("/distributions", s.AdminListDistributions).Methods("GET")
+	v2Admin.HandleFunc("/distributions", s.AdminCreateDistribution).Methods("POST")
+	v2Admin.HandleFunc("/distributions/{id}", s.AdminGetDistribution).Methods("GET")
+	v2Admin.HandleFunc("/distributions/{id}", s.AdminUpdateDistribution).Methods("PUT")
+	v2Admin.HandleFunc("/distributions/{id}/expire", s.AdminExpireDistribution).Methods("POST")
+	v2Admin.HandleFunc("/distributions/{id}/eligible", s.AdminDistributionEligible).Methods("GET")
+	v2Admin.HandleFunc("/gachas", s.AdminListGachas).Methods("GET")
+	v2Admin.HandleFunc("/gachas", s.AdminCreateGacha).Methods("POST")
+	v2Admin.HandleFunc("/gachas/{id}", s.AdminGetGacha).Methods("GET")
+	v2Admin.HandleFunc("/gachas/{id}", s.AdminUpdateGacha).Methods("PUT")
+	v2Admin.HandleFunc("/gachas/{id}", s.AdminDeleteGacha).Methods("DELETE")
+	v2Admin.HandleFunc("/gachas/{id}/entries", s.AdminCreateGachaEntry).Methods("POST")
+	v2Admin.HandleFunc("/gachas/{id}/entries/{entryId}", s.AdminUpdateGachaEntry).Methods("PUT")
+	v2Admin.HandleFunc("/gachas/{id}/entries/{entryId}", s.AdminDeleteGachaEntry).Methods("DELETE")
+	v2Admin.HandleFunc("/gachas/{id}/rates", s.AdminGachaRates).Methods("GET")
+	v2Admin.HandleFunc("/gachas/{id}/simulate", s.AdminSimulateGacha).Methods("POST")
+	v2Admin.HandleFunc("/gacha-history", s.AdminGachaHistory).Methods("GET")
+	v2Admin.HandleFunc("/shop-items", s.AdminListShopItems).Methods("GET")
+	v2Admin.HandleFunc("/shop-items", s.AdminCreateShopItem).Methods("POST")
+	v2Admin.HandleFunc("/shop-items/{id}", s.AdminGetShopItem).Methods("GET")
+	v2Admin.HandleFunc("/shop-items/{id}", s.AdminUpdateShopItem).Methods("PUT")
+	v2Admin.HandleFunc("/shop-items/{id}/retire", s.AdminRetireShopItem).Methods("POST")
+	v2Admin.HandleFunc("/shop-items/{id}/restore", s.AdminRestoreShopItem).Methods("POST")
+	v2Admin.HandleFunc("/shop-items/{id}/prices", s.AdminScheduleShopPrice).Methods("POST")
+	v2Admin.HandleFunc("/shop-items/{id}/prices/{priceId}", s.AdminCancelShopPrice).Methods("DELETE")
+	v2Admin.HandleFunc("/fpoint-items", s.AdminListFpointItems).Methods("GET")
+	v2Admin.HandleFunc("/fpoint-items", s.AdminCreateFpointItem).Methods("POST")
+	v2Admin.HandleFunc("/fpoint-items/{id}", s.AdminGetFpointItem).Methods("GET")
+	v2Admin.HandleFunc("/fpoint-items/{id}", s.AdminUpdateFpointItem).Methods("PUT")
+	v2Admin.HandleFunc("/fpoint-items/{id}/retire", s.AdminRetireFpointItem).Methods("POST")
+	v2Admin.HandleFunc("/fpoint-items/{id}/restore", s.AdminRestoreFpointItem).Methods("POST")
+	v2Admin.HandleFunc("/shop-reports/top-items", s.AdminShopTopItems).Methods("GET")
+	v2Admin.HandleFunc("/shop-reports/cap-hits", s.AdminShopCapHits).Methods("GET")
 
 	return r
 }
@@ -513,3 +596,46 @@ func TestLegacyRoutesStillWork(t *testing.T) {
 		t.Errorf("GET /version (legacy): status = %d, want 200", rec.Code)
 	}
 }
+
+func TestMetricsRoute(t *testing.T) {
+	reg := metrics.NewRegistry()
+	reg.NewGauge("erupe_test_gauge", "Test gauge.").Set(3)
+	server := &APIServer{
+		logger:      NewTestLogger(t),
+		erupeConfig: NewTestConfig(),
+		metrics:     reg,
+	}
+
+	router := newTestRouter(server)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /metrics: status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != metrics.ContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, metrics.ContentType)
+	}
+	if !strings.Contains(rec.Body.String(), "erupe_test_gauge 3\n") {
+		t.Errorf("unexpected body:\n%s", rec.Body.String())
+	}
+}
+
+func TestMetricsRoute_NotRegisteredWithoutRegistry(t *testing.T) {
+	server := &APIServer{
+		logger:      NewTestLogger(t),
+		erupeConfig: NewTestConfig(),
+	}
+
+	router := newTestRouter(server)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /metrics without registry: status = %d, want 404", rec.Code)
+	}
+}
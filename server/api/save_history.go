@@ -0,0 +1,156 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// APISaveHistory lists, compares and restores a character's savedata
+// backups. *channelserver.SaveHistoryService satisfies it.
+type APISaveHistory interface {
+	History(charID uint32) (channelserver.SaveHistory, error)
+	DiffBackups(charID uint32, from, to int) ([]channelserver.SaveFieldDiff, error)
+	RestoreBackup(charID uint32, slot int) (channelserver.SaveBackupInfo, error)
+}
+
+// SaveDiffResponse is the body returned by GET /v2/characters/{id}/backups/diff.
+type SaveDiffResponse struct {
+	From    string                        `json:"from"`
+	To      string                        `json:"to"`
+	Changes []channelserver.SaveFieldDiff `json:"changes"`
+}
+
+// backupCharacter checks that save history is available and that the caller
+// may see the {id} character.
+func (s *APIServer) backupCharacter(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	if s.saveHistory == nil || s.charRepo == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Save history is not available")
+		return 0, false
+	}
+	return s.ownedCharacter(w, r)
+}
+
+// ownedCharacter parses the {id} route variable and checks that the caller
+// owns the character or is an operator. Characters the caller may not see are
+// reported as not found.
+func (s *APIServer) ownedCharacter(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid character ID")
+		return 0, false
+	}
+	charID := uint32(id)
+	userID, _ := UserIDFromContext(r.Context())
+	owner, err := s.charRepo.IsOwner(r.Context(), charID, userID)
+	if err == nil && !owner {
+		owner, err = s.isOp(r.Context(), userID)
+	}
+	if err != nil {
+		s.logger.Error("Failed to authorize character request", zap.Error(err), zap.Uint32("charID", charID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+		return 0, false
+	}
+	if !owner {
+		writeError(w, http.StatusNotFound, "not_found", "Character not found")
+		return 0, false
+	}
+	return charID, true
+}
+
+// parseBackupSlot accepts a backup slot number or "current" for the live save.
+func parseBackupSlot(v string) (int, bool) {
+	if v == "current" {
+		return channelserver.CurrentSaveSlot, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// writeSaveHistoryError maps save history service errors to responses.
+func (s *APIServer) writeSaveHistoryError(w http.ResponseWriter, err error, charID uint32) {
+	switch {
+	case errors.Is(err, channelserver.ErrBackupNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Backup not found")
+	case errors.Is(err, channelserver.ErrSaveUnreadable):
+		writeError(w, http.StatusUnprocessableEntity, "unreadable_save", err.Error())
+	case errors.Is(err, channelserver.ErrCharacterOnline):
+		writeError(w, http.StatusConflict, "character_online", "Character must be logged out to restore a backup")
+	case errors.Is(err, channelserver.ErrBackupChanged):
+		writeError(w, http.StatusConflict, "conflict", "Backup changed while restoring, try again")
+	default:
+		s.logger.Error("Save history request failed", zap.Error(err), zap.Uint32("charID", charID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+	}
+}
+
+// ListBackups handles GET /v2/characters/{id}/backups.
+func (s *APIServer) ListBackups(w http.ResponseWriter, r *http.Request) {
+	charID, ok := s.backupCharacter(w, r)
+	if !ok {
+		return
+	}
+	history, err := s.saveHistory.History(charID)
+	if err != nil {
+		s.writeSaveHistoryError(w, err, charID)
+		return
+	}
+	writeJSON(w, history)
+}
+
+// DiffBackups handles GET /v2/characters/{id}/backups/diff?from=&to=. Both
+// parameters take a slot number or "current"; to defaults to "current".
+func (s *APIServer) DiffBackups(w http.ResponseWriter, r *http.Request) {
+	charID, ok := s.backupCharacter(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	fromStr, toStr := query.Get("from"), query.Get("to")
+	if toStr == "" {
+		toStr = "current"
+	}
+	from, okFrom := parseBackupSlot(fromStr)
+	to, okTo := parseBackupSlot(toStr)
+	if !okFrom || !okTo {
+		writeError(w, http.StatusBadRequest, "invalid_request", "from and to must be a backup slot or \"current\"")
+		return
+	}
+	changes, err := s.saveHistory.DiffBackups(charID, from, to)
+	if err != nil {
+		s.writeSaveHistoryError(w, err, charID)
+		return
+	}
+	writeJSON(w, SaveDiffResponse{From: fromStr, To: toStr, Changes: changes})
+}
+
+// RestoreBackup handles POST /v2/characters/{id}/backups/{slot}/restore. The
+// replaced save is kept in the same slot, so repeating the call undoes it.
+func (s *APIServer) RestoreBackup(w http.ResponseWriter, r *http.Request) {
+	charID, ok := s.backupCharacter(w, r)
+	if !ok {
+		return
+	}
+	slot, err := strconv.Atoi(mux.Vars(r)["slot"])
+	if err != nil || slot < 0 {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid backup slot")
+		return
+	}
+	restored, err := s.saveHistory.RestoreBackup(charID, slot)
+	if err != nil {
+		s.writeSaveHistoryError(w, err, charID)
+		return
+	}
+	userID, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Savedata restored via API",
+		zap.Uint32("charID", charID), zap.Int("slot", slot), zap.Uint32("userID", userID))
+	writeJSON(w, restored)
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"erupe-ce/server/channelserver"
+)
+
+// mockSaveHistory implements APISaveHistory for testing.
+type mockSaveHistory struct {
+	history    channelserver.SaveHistory
+	diffs      []channelserver.SaveFieldDiff
+	restoreErr error
+
+	diffFrom, diffTo int
+	restoredSlot     int
+}
+
+func (m *mockSaveHistory) History(_ uint32) (channelserver.SaveHistory, error) {
+	return m.history, nil
+}
+
+func (m *mockSaveHistory) DiffBackups(_ uint32, from, to int) ([]channelserver.SaveFieldDiff, error) {
+	m.diffFrom, m.diffTo = from, to
+	return m.diffs, nil
+}
+
+func (m *mockSaveHistory) RestoreBackup(_ uint32, slot int) (channelserver.SaveBackupInfo, error) {
+	if m.restoreErr != nil {
+		return channelserver.SaveBackupInfo{}, m.restoreErr
+	}
+	m.restoredSlot = slot
+	return channelserver.SaveBackupInfo{Slot: channelserver.CurrentSaveSlot, Summary: &channelserver.SaveSummary{HR: 7}}, nil
+}
+
+// newSaveHistoryTestServer returns a server where "valid-token" belongs to
+// user 2, a regular player who owns the requested character.
+func newSaveHistoryTestServer(t *testing.T) (*APIServer, *mockSaveHistory, *mockAPICharacterRepo) {
+	t.Helper()
+	server, _, sessions := newAdminTestServer(t)
+	sessions.userID = 2
+	history := &mockSaveHistory{
+		history: channelserver.SaveHistory{
+			Current: channelserver.SaveBackupInfo{Slot: channelserver.CurrentSaveSlot},
+			Backups: []channelserver.SaveBackupInfo{{Slot: 1, SavedAt: time.Unix(1000, 0).UTC()}},
+		},
+	}
+	chars := &mockAPICharacterRepo{isOwnerResult: true}
+	server.saveHistory = history
+	server.charRepo = chars
+	return server, history, chars
+}
+
+func TestListBackups(t *testing.T) {
+	server, _, _ := newSaveHistoryTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/characters/20/backups", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var resp channelserver.SaveHistory
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Backups) != 1 || resp.Backups[0].Slot != 1 {
+		t.Errorf("backups = %+v", resp.Backups)
+	}
+}
+
+func TestListBackups_NotOwner(t *testing.T) {
+	server, _, chars := newSaveHistoryTestServer(t)
+	chars.isOwnerResult = false
+
+	rec := doAdminRequest(t, server, "GET", "/v2/characters/20/backups", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestListBackups_OpMayViewAnyCharacter(t *testing.T) {
+	server, _, chars := newSaveHistoryTestServer(t)
+	chars.isOwnerResult = false
+	server.sessionRepo = &mockAPISessionRepo{userID: 1}
+
+	rec := doAdminRequest(t, server, "GET", "/v2/characters/20/backups", nil)
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+}
+
+func TestListBackups_Unavailable(t *testing.T) {
+	server, _, _ := newSaveHistoryTestServer(t)
+	server.saveHistory = nil
+
+	rec := doAdminRequest(t, server, "GET", "/v2/characters/20/backups", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
+
+func TestDiffBackups(t *testing.T) {
+	server, history, _ := newSaveHistoryTestServer(t)
+	history.diffs = []channelserver.SaveFieldDiff{{Field: "hr", Old: 6, New: 7}}
+
+	rec := doAdminRequest(t, server, "GET", "/v2/characters/20/backups/diff?from=2", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if history.diffFrom != 2 || history.diffTo != channelserver.CurrentSaveSlot {
+		t.Errorf("diff(%d, %d), want (2, current)", history.diffFrom, history.diffTo)
+	}
+	var resp SaveDiffResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.To != "current" || len(resp.Changes) != 1 || resp.Changes[0].Field != "hr" {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestDiffBackups_InvalidSlot(t *testing.T) {
+	server, _, _ := newSaveHistoryTestServer(t)
+
+	for _, query := range []string{"", "?from=abc", "?from=1&to=-1"} {
+		rec := doAdminRequest(t, server, "GET", "/v2/characters/20/backups/diff"+query, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%q: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	server, history, _ := newSaveHistoryTestServer(t)
+
+	rec := doAdminRequest(t, server, "POST", "/v2/characters/20/backups/1/restore", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if history.restoredSlot != 1 {
+		t.Errorf("restored slot %d, want 1", history.restoredSlot)
+	}
+}
+
+func TestRestoreBackup_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{channelserver.ErrCharacterOnline, http.StatusConflict},
+		{channelserver.ErrBackupChanged, http.StatusConflict},
+		{channelserver.ErrBackupNotFound, http.StatusNotFound},
+		{channelserver.ErrSaveUnreadable, http.StatusUnprocessableEntity},
+		{errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		server, history, _ := newSaveHistoryTestServer(t)
+		history.restoreErr = tt.err
+
+		rec := doAdminRequest(t, server, "POST", "/v2/characters/20/backups/1/restore", nil)
+		if rec.Code != tt.want {
+			t.Errorf("%v: status = %d, want %d", tt.err, rec.Code, tt.want)
+		}
+	}
+}
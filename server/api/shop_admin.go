@@ -0,0 +1,441 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// APIShopAdmin manages the shop and Frontier Point exchange catalogues and
+// reports on purchases. *channelserver.ShopAdminService satisfies it.
+type APIShopAdmin interface {
+	List(f channelserver.ShopItemFilter) ([]channelserver.ShopCatalogueItem, error)
+	Get(id uint32) (*channelserver.ShopCatalogueItem, error)
+	Create(item channelserver.ShopCatalogueItem) (*channelserver.ShopCatalogueItem, error)
+	Update(id uint32, item channelserver.ShopCatalogueItem) (*channelserver.ShopCatalogueItem, error)
+	Retire(id uint32) (*channelserver.ShopCatalogueItem, error)
+	Restore(id uint32) (*channelserver.ShopCatalogueItem, error)
+	SchedulePrice(id uint32, c channelserver.ShopPriceChange, now time.Time) (*channelserver.ShopCatalogueItem, error)
+	CancelPriceChange(id, changeID uint32, now time.Time) (*channelserver.ShopCatalogueItem, error)
+	ListFpoint(includeRetired bool) ([]channelserver.FPointExchange, error)
+	GetFpoint(id uint32) (*channelserver.FPointExchange, error)
+	CreateFpoint(item channelserver.FPointExchange) (*channelserver.FPointExchange, error)
+	UpdateFpoint(id uint32, item channelserver.FPointExchange) (*channelserver.FPointExchange, error)
+	RetireFpoint(id uint32) (*channelserver.FPointExchange, error)
+	RestoreFpoint(id uint32) (*channelserver.FPointExchange, error)
+	TopItems(f channelserver.ShopReportFilter) ([]channelserver.ShopItemSales, error)
+	CapHits(f channelserver.ShopReportFilter) ([]channelserver.ShopCapHit, error)
+}
+
+// requireShopAdmin writes 503 when shop administration is not wired up.
+func (s *APIServer) requireShopAdmin(w http.ResponseWriter) bool {
+	if s.shopAdmin == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Shop administration is not available")
+		return false
+	}
+	return true
+}
+
+// adminShopID parses an ID route variable for the shop endpoints.
+func (s *APIServer) adminShopID(w http.ResponseWriter, r *http.Request, name, label string) (uint32, bool) {
+	if !s.requireShopAdmin(w) {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(mux.Vars(r)[name], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid "+label+" ID")
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// parseShopQuery reads the shopType and shopId query parameters shared by the
+// catalogue and report endpoints. It returns a message describing the first
+// invalid parameter.
+func parseShopQuery(q url.Values) (uint8, *uint32, string) {
+	var shopType uint8
+	if v := q.Get("shopType"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 8)
+		if err != nil {
+			return 0, nil, "Invalid shopType"
+		}
+		shopType = uint8(n)
+	}
+	var shopID *uint32
+	if v := q.Get("shopId"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return 0, nil, "Invalid shopId"
+		}
+		id := uint32(n)
+		shopID = &id
+	}
+	return shopType, shopID, ""
+}
+
+// parseRetiredQuery reads the retired query parameter, which includes retired
+// items in a listing when true.
+func parseRetiredQuery(q url.Values) (bool, string) {
+	v := q.Get("retired")
+	if v == "" {
+		return false, ""
+	}
+	retired, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, "Invalid retired"
+	}
+	return retired, ""
+}
+
+// writeShopAdminError maps a shop admin service error.
+func (s *APIServer) writeShopAdminError(w http.ResponseWriter, err error, id uint32) {
+	switch {
+	case errors.Is(err, channelserver.ErrShopItemNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Shop item not found")
+	case errors.Is(err, channelserver.ErrShopPriceChangeNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Price change not found")
+	case errors.Is(err, channelserver.ErrFpointItemNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Frontier Point item not found")
+	case errors.Is(err, channelserver.ErrInvalidShopItem), errors.Is(err, channelserver.ErrInvalidShopReport):
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+	default:
+		s.logger.Error("Shop admin request failed", zap.Error(err), zap.Uint32("id", id))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+	}
+}
+
+// AdminListShopItems handles GET /v2/admin/shop-items. shopType and shopId
+// narrow the listing; retired=true includes items taken off sale.
+func (s *APIServer) AdminListShopItems(w http.ResponseWriter, r *http.Request) {
+	if !s.requireShopAdmin(w) {
+		return
+	}
+	q := r.URL.Query()
+	shopType, shopID, msg := parseShopQuery(q)
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", msg)
+		return
+	}
+	retired, msg := parseRetiredQuery(q)
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", msg)
+		return
+	}
+	items, err := s.shopAdmin.List(channelserver.ShopItemFilter{ShopType: shopType, ShopID: shopID, IncludeRetired: retired})
+	if err != nil {
+		s.writeShopAdminError(w, err, 0)
+		return
+	}
+	writeJSON(w, items)
+}
+
+// AdminCreateShopItem handles POST /v2/admin/shop-items.
+func (s *APIServer) AdminCreateShopItem(w http.ResponseWriter, r *http.Request) {
+	if !s.requireShopAdmin(w) {
+		return
+	}
+	var req channelserver.ShopCatalogueItem
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	item, err := s.shopAdmin.Create(req)
+	if err != nil {
+		s.writeShopAdminError(w, err, 0)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Shop item created via API", zap.Uint32("shopItemID", item.ID), zap.Uint32("adminID", admin))
+	writeJSON(w, item)
+}
+
+// AdminGetShopItem handles GET /v2/admin/shop-items/{id}.
+func (s *APIServer) AdminGetShopItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminShopID(w, r, "id", "shop item")
+	if !ok {
+		return
+	}
+	item, err := s.shopAdmin.Get(id)
+	if err != nil {
+		s.writeShopAdminError(w, err, id)
+		return
+	}
+	writeJSON(w, item)
+}
+
+// AdminUpdateShopItem handles PUT /v2/admin/shop-items/{id}. A new cost takes
+// effect straight away; use the prices endpoint to schedule one.
+func (s *APIServer) AdminUpdateShopItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminShopID(w, r, "id", "shop item")
+	if !ok {
+		return
+	}
+	var req channelserver.ShopCatalogueItem
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	item, err := s.shopAdmin.Update(id, req)
+	if err != nil {
+		s.writeShopAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Shop item updated via API", zap.Uint32("shopItemID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, item)
+}
+
+// AdminRetireShopItem handles POST /v2/admin/shop-items/{id}/retire, taking
+// the item off sale while keeping its purchase counts.
+func (s *APIServer) AdminRetireShopItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminShopID(w, r, "id", "shop item")
+	if !ok {
+		return
+	}
+	item, err := s.shopAdmin.Retire(id)
+	if err != nil {
+		s.writeShopAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Shop item retired via API", zap.Uint32("shopItemID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, item)
+}
+
+// AdminRestoreShopItem handles POST /v2/admin/shop-items/{id}/restore.
+func (s *APIServer) AdminRestoreShopItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminShopID(w, r, "id", "shop item")
+	if !ok {
+		return
+	}
+	item, err := s.shopAdmin.Restore(id)
+	if err != nil {
+		s.writeShopAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Shop item restored via API", zap.Uint32("shopItemID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, item)
+}
+
+// AdminScheduleShopPrice handles POST /v2/admin/shop-items/{id}/prices,
+// scheduling a new cost from a future time.
+func (s *APIServer) AdminScheduleShopPrice(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminShopID(w, r, "id", "shop item")
+	if !ok {
+		return
+	}
+	var req channelserver.ShopPriceChange
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	item, err := s.shopAdmin.SchedulePrice(id, req, time.Now())
+	if err != nil {
+		s.writeShopAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Shop price change scheduled via API", zap.Uint32("shopItemID", id),
+		zap.Uint32("cost", req.Cost), zap.Time("effectiveAt", req.EffectiveAt), zap.Uint32("adminID", admin))
+	writeJSON(w, item)
+}
+
+// AdminCancelShopPrice handles DELETE /v2/admin/shop-items/{id}/prices/{priceId}
+// for a price change that has not taken effect yet.
+func (s *APIServer) AdminCancelShopPrice(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminShopID(w, r, "id", "shop item")
+	if !ok {
+		return
+	}
+	changeID, ok := s.adminShopID(w, r, "priceId", "price change")
+	if !ok {
+		return
+	}
+	if _, err := s.shopAdmin.CancelPriceChange(id, changeID, time.Now()); err != nil {
+		s.writeShopAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Shop price change cancelled via API",
+		zap.Uint32("shopItemID", id), zap.Uint32("changeID", changeID), zap.Uint32("adminID", admin))
+	writeJSON(w, struct{}{})
+}
+
+// AdminListFpointItems handles GET /v2/admin/fpoint-items. retired=true
+// includes items taken out of the exchange.
+func (s *APIServer) AdminListFpointItems(w http.ResponseWriter, r *http.Request) {
+	if !s.requireShopAdmin(w) {
+		return
+	}
+	retired, msg := parseRetiredQuery(r.URL.Query())
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", msg)
+		return
+	}
+	items, err := s.shopAdmin.ListFpoint(retired)
+	if err != nil {
+		s.writeShopAdminError(w, err, 0)
+		return
+	}
+	writeJSON(w, items)
+}
+
+// AdminCreateFpointItem handles POST /v2/admin/fpoint-items.
+func (s *APIServer) AdminCreateFpointItem(w http.ResponseWriter, r *http.Request) {
+	if !s.requireShopAdmin(w) {
+		return
+	}
+	var req channelserver.FPointExchange
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	item, err := s.shopAdmin.CreateFpoint(req)
+	if err != nil {
+		s.writeShopAdminError(w, err, 0)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Frontier Point item created via API", zap.Uint32("fpointItemID", item.ID), zap.Uint32("adminID", admin))
+	writeJSON(w, item)
+}
+
+// AdminGetFpointItem handles GET /v2/admin/fpoint-items/{id}.
+func (s *APIServer) AdminGetFpointItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminShopID(w, r, "id", "Frontier Point item")
+	if !ok {
+		return
+	}
+	item, err := s.shopAdmin.GetFpoint(id)
+	if err != nil {
+		s.writeShopAdminError(w, err, id)
+		return
+	}
+	writeJSON(w, item)
+}
+
+// AdminUpdateFpointItem handles PUT /v2/admin/fpoint-items/{id}.
+func (s *APIServer) AdminUpdateFpointItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminShopID(w, r, "id", "Frontier Point item")
+	if !ok {
+		return
+	}
+	var req channelserver.FPointExchange
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	item, err := s.shopAdmin.UpdateFpoint(id, req)
+	if err != nil {
+		s.writeShopAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Frontier Point item updated via API", zap.Uint32("fpointItemID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, item)
+}
+
+// AdminRetireFpointItem handles POST /v2/admin/fpoint-items/{id}/retire.
+func (s *APIServer) AdminRetireFpointItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminShopID(w, r, "id", "Frontier Point item")
+	if !ok {
+		return
+	}
+	item, err := s.shopAdmin.RetireFpoint(id)
+	if err != nil {
+		s.writeShopAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Frontier Point item retired via API", zap.Uint32("fpointItemID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, item)
+}
+
+// AdminRestoreFpointItem handles POST /v2/admin/fpoint-items/{id}/restore.
+func (s *APIServer) AdminRestoreFpointItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminShopID(w, r, "id", "Frontier Point item")
+	if !ok {
+		return
+	}
+	item, err := s.shopAdmin.RestoreFpoint(id)
+	if err != nil {
+		s.writeShopAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Frontier Point item restored via API", zap.Uint32("fpointItemID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, item)
+}
+
+// parseShopReportFilter reads the query parameters of the purchase reports.
+// It returns a message describing the first invalid parameter.
+func parseShopReportFilter(q url.Values) (channelserver.ShopReportFilter, string) {
+	var f channelserver.ShopReportFilter
+	var msg string
+	f.ShopType, f.ShopID, msg = parseShopQuery(q)
+	if msg != "" {
+		return f, msg
+	}
+	if v := q.Get("charId"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return f, "Invalid charId"
+		}
+		f.CharID = uint32(n)
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return f, "Invalid limit"
+		}
+		f.Limit = n
+	}
+	return f, ""
+}
+
+// AdminShopTopItems handles GET /v2/admin/shop-reports/top-items, ranking
+// shop items by recorded purchases.
+func (s *APIServer) AdminShopTopItems(w http.ResponseWriter, r *http.Request) {
+	if !s.requireShopAdmin(w) {
+		return
+	}
+	f, msg := parseShopReportFilter(r.URL.Query())
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", msg)
+		return
+	}
+	sales, err := s.shopAdmin.TopItems(f)
+	if err != nil {
+		s.writeShopAdminError(w, err, 0)
+		return
+	}
+	writeJSON(w, sales)
+}
+
+// AdminShopCapHits handles GET /v2/admin/shop-reports/cap-hits, listing the
+// characters that have bought a capped item as many times as it allows.
+func (s *APIServer) AdminShopCapHits(w http.ResponseWriter, r *http.Request) {
+	if !s.requireShopAdmin(w) {
+		return
+	}
+	f, msg := parseShopReportFilter(r.URL.Query())
+	if msg != "" {
+		writeError(w, http.StatusBadRequest, "invalid_request", msg)
+		return
+	}
+	hits, err := s.shopAdmin.CapHits(f)
+	if err != nil {
+		s.writeShopAdminError(w, err, 0)
+		return
+	}
+	writeJSON(w, hits)
+}
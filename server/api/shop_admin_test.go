@@ -0,0 +1,289 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"erupe-ce/server/channelserver"
+)
+
+// mockShopAdmin implements APIShopAdmin for testing.
+type mockShopAdmin struct {
+	item   channelserver.ShopCatalogueItem
+	fpoint channelserver.FPointExchange
+	err    error
+
+	filter       channelserver.ShopItemFilter
+	fpointFilter bool
+	price        channelserver.ShopPriceChange
+	cancelled    uint32
+	retired      bool
+	report       channelserver.ShopReportFilter
+}
+
+func (m *mockShopAdmin) List(f channelserver.ShopItemFilter) ([]channelserver.ShopCatalogueItem, error) {
+	m.filter = f
+	return []channelserver.ShopCatalogueItem{m.item}, m.err
+}
+func (m *mockShopAdmin) lookup(id uint32) (*channelserver.ShopCatalogueItem, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if id != m.item.ID {
+		return nil, fmt.Errorf("%w: %d", channelserver.ErrShopItemNotFound, id)
+	}
+	item := m.item
+	return &item, nil
+}
+func (m *mockShopAdmin) Get(id uint32) (*channelserver.ShopCatalogueItem, error) { return m.lookup(id) }
+func (m *mockShopAdmin) Create(item channelserver.ShopCatalogueItem) (*channelserver.ShopCatalogueItem, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	item.ID = m.item.ID
+	m.item = item
+	return &item, nil
+}
+func (m *mockShopAdmin) Update(id uint32, item channelserver.ShopCatalogueItem) (*channelserver.ShopCatalogueItem, error) {
+	if _, err := m.lookup(id); err != nil {
+		return nil, err
+	}
+	item.ID = id
+	m.item = item
+	return &item, nil
+}
+func (m *mockShopAdmin) Retire(id uint32) (*channelserver.ShopCatalogueItem, error) {
+	m.retired = true
+	return m.lookup(id)
+}
+func (m *mockShopAdmin) Restore(id uint32) (*channelserver.ShopCatalogueItem, error) {
+	m.retired = false
+	return m.lookup(id)
+}
+func (m *mockShopAdmin) SchedulePrice(id uint32, c channelserver.ShopPriceChange, _ time.Time) (*channelserver.ShopCatalogueItem, error) {
+	m.price = c
+	return m.lookup(id)
+}
+func (m *mockShopAdmin) CancelPriceChange(id, changeID uint32, _ time.Time) (*channelserver.ShopCatalogueItem, error) {
+	m.cancelled = changeID
+	return m.lookup(id)
+}
+func (m *mockShopAdmin) ListFpoint(includeRetired bool) ([]channelserver.FPointExchange, error) {
+	m.fpointFilter = includeRetired
+	return []channelserver.FPointExchange{m.fpoint}, m.err
+}
+func (m *mockShopAdmin) fpointLookup(id uint32) (*channelserver.FPointExchange, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if id != m.fpoint.ID {
+		return nil, fmt.Errorf("%w: %d", channelserver.ErrFpointItemNotFound, id)
+	}
+	item := m.fpoint
+	return &item, nil
+}
+func (m *mockShopAdmin) GetFpoint(id uint32) (*channelserver.FPointExchange, error) {
+	return m.fpointLookup(id)
+}
+func (m *mockShopAdmin) CreateFpoint(item channelserver.FPointExchange) (*channelserver.FPointExchange, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	item.ID = m.fpoint.ID
+	m.fpoint = item
+	return &item, nil
+}
+func (m *mockShopAdmin) UpdateFpoint(id uint32, item channelserver.FPointExchange) (*channelserver.FPointExchange, error) {
+	if _, err := m.fpointLookup(id); err != nil {
+		return nil, err
+	}
+	item.ID = id
+	m.fpoint = item
+	return &item, nil
+}
+func (m *mockShopAdmin) RetireFpoint(id uint32) (*channelserver.FPointExchange, error) {
+	m.retired = true
+	return m.fpointLookup(id)
+}
+func (m *mockShopAdmin) RestoreFpoint(id uint32) (*channelserver.FPointExchange, error) {
+	m.retired = false
+	return m.fpointLookup(id)
+}
+func (m *mockShopAdmin) TopItems(f channelserver.ShopReportFilter) ([]channelserver.ShopItemSales, error) {
+	m.report = f
+	return []channelserver.ShopItemSales{{ShopItemID: m.item.ID, Bought: 12, Buyers: 4, CappedBuyers: 1}}, m.err
+}
+func (m *mockShopAdmin) CapHits(f channelserver.ShopReportFilter) ([]channelserver.ShopCapHit, error) {
+	m.report = f
+	return []channelserver.ShopCapHit{{CharID: 20, CharName: "Hunter", ShopItemID: m.item.ID, MaxQuantity: 3, Bought: 3}}, m.err
+}
+
+func newMockShopAdmin() *mockShopAdmin {
+	return &mockShopAdmin{
+		item:   channelserver.ShopCatalogueItem{ID: 4, ShopType: 10, ShopID: 2, ItemID: 500, Cost: 100, Quantity: 1},
+		fpoint: channelserver.FPointExchange{ID: 9, ItemType: 7, ItemID: 8895, Quantity: 1, FPoints: 500, Buyable: true},
+	}
+}
+
+func TestAdminShopItems_CRUD(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	shop := newMockShopAdmin()
+	server.shopAdmin = shop
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/shop-items?shopType=10&shopId=0&retired=true", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list: status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if f := shop.filter; f.ShopType != 10 || f.ShopID == nil || *f.ShopID != 0 || !f.IncludeRetired {
+		t.Errorf("filter = %+v", f)
+	}
+
+	rec = doAdminRequest(t, server, "POST", "/v2/admin/shop-items",
+		channelserver.ShopCatalogueItem{ShopType: 10, ShopID: 2, ItemID: 501, Cost: 50, Quantity: 3})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create: status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var created channelserver.ShopCatalogueItem
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != 4 || created.ItemID != 501 {
+		t.Errorf("created = %+v", created)
+	}
+
+	rec = doAdminRequest(t, server, "PUT", "/v2/admin/shop-items/4",
+		channelserver.ShopCatalogueItem{ShopType: 10, ShopID: 2, ItemID: 501, Cost: 40, Quantity: 3})
+	if rec.Code != http.StatusOK || shop.item.Cost != 40 {
+		t.Errorf("update: status = %d, item = %+v", rec.Code, shop.item)
+	}
+	if rec := doAdminRequest(t, server, "POST", "/v2/admin/shop-items/4/retire", nil); rec.Code != http.StatusOK || !shop.retired {
+		t.Errorf("retire: status = %d, retired = %v", rec.Code, shop.retired)
+	}
+	if rec := doAdminRequest(t, server, "POST", "/v2/admin/shop-items/4/restore", nil); rec.Code != http.StatusOK || shop.retired {
+		t.Errorf("restore: status = %d, retired = %v", rec.Code, shop.retired)
+	}
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/shop-items/7", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("missing item: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminShopItems_InvalidRequest(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	shop := newMockShopAdmin()
+	server.shopAdmin = shop
+
+	for _, q := range []string{"shopType=300", "shopId=x", "retired=maybe"} {
+		if rec := doAdminRequest(t, server, "GET", "/v2/admin/shop-items?"+q, nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", q, rec.Code)
+		}
+	}
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/shop-items/x", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid ID: status = %d, want 400", rec.Code)
+	}
+
+	shop.err = fmt.Errorf("%w: maxQuantity is not sent to G10 clients", channelserver.ErrInvalidShopItem)
+	if rec := doAdminRequest(t, server, "POST", "/v2/admin/shop-items", channelserver.ShopCatalogueItem{}); rec.Code != http.StatusBadRequest {
+		t.Errorf("service rejection: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminShopPrices(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	shop := newMockShopAdmin()
+	server.shopAdmin = shop
+
+	at := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/shop-items/4/prices", map[string]interface{}{
+		"cost": 75, "effectiveAt": at,
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("schedule: status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if shop.price.Cost != 75 || !shop.price.EffectiveAt.Equal(at) {
+		t.Errorf("price change = %+v", shop.price)
+	}
+
+	if rec := doAdminRequest(t, server, "DELETE", "/v2/admin/shop-items/4/prices/3", nil); rec.Code != http.StatusOK || shop.cancelled != 3 {
+		t.Errorf("cancel: status = %d, cancelled = %d", rec.Code, shop.cancelled)
+	}
+	shop.err = fmt.Errorf("%w: 8", channelserver.ErrShopPriceChangeNotFound)
+	if rec := doAdminRequest(t, server, "DELETE", "/v2/admin/shop-items/4/prices/8", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("missing change: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminFpointItems(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	shop := newMockShopAdmin()
+	server.shopAdmin = shop
+
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/fpoint-items?retired=1", nil); rec.Code != http.StatusOK || !shop.fpointFilter {
+		t.Errorf("list: status = %d, includeRetired = %v", rec.Code, shop.fpointFilter)
+	}
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/fpoint-items",
+		channelserver.FPointExchange{ItemType: 7, ItemID: 12524, Quantity: 1, FPoints: 300, Buyable: true})
+	if rec.Code != http.StatusOK || shop.fpoint.ItemID != 12524 {
+		t.Errorf("create: status = %d, item = %+v", rec.Code, shop.fpoint)
+	}
+	if rec := doAdminRequest(t, server, "PUT", "/v2/admin/fpoint-items/9",
+		channelserver.FPointExchange{ItemType: 7, ItemID: 12524, Quantity: 1, FPoints: 250}); rec.Code != http.StatusOK || shop.fpoint.FPoints != 250 {
+		t.Errorf("update: status = %d, item = %+v", rec.Code, shop.fpoint)
+	}
+	if rec := doAdminRequest(t, server, "POST", "/v2/admin/fpoint-items/9/retire", nil); rec.Code != http.StatusOK || !shop.retired {
+		t.Errorf("retire: status = %d, retired = %v", rec.Code, shop.retired)
+	}
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/fpoint-items/1", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("missing item: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminShopReports(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+	shop := newMockShopAdmin()
+	server.shopAdmin = shop
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/shop-reports/top-items?shopType=10&limit=5", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("top items: status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var sales []channelserver.ShopItemSales
+	if err := json.NewDecoder(rec.Body).Decode(&sales); err != nil {
+		t.Fatal(err)
+	}
+	if len(sales) != 1 || sales[0].Bought != 12 || shop.report.ShopType != 10 || shop.report.Limit != 5 {
+		t.Errorf("sales = %+v, filter = %+v", sales, shop.report)
+	}
+
+	rec = doAdminRequest(t, server, "GET", "/v2/admin/shop-reports/cap-hits?charId=20", nil)
+	if rec.Code != http.StatusOK || shop.report.CharID != 20 {
+		t.Errorf("cap hits: status = %d, filter = %+v", rec.Code, shop.report)
+	}
+	for _, q := range []string{"charId=me", "limit=0"} {
+		if rec := doAdminRequest(t, server, "GET", "/v2/admin/shop-reports/cap-hits?"+q, nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", q, rec.Code)
+		}
+	}
+}
+
+func TestAdminShop_RequiresOperator(t *testing.T) {
+	server, _, sessions := newAdminTestServer(t)
+	server.shopAdmin = newMockShopAdmin()
+	sessions.userID = 2
+
+	if rec := doAdminRequest(t, server, "GET", "/v2/admin/shop-items", nil); rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
+
+func TestAdminShop_Unavailable(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	for _, path := range []string{"/v2/admin/shop-items", "/v2/admin/fpoint-items", "/v2/admin/shop-reports/top-items"} {
+		if rec := doAdminRequest(t, server, "GET", path, nil); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: status = %d, want 503", path, rec.Code)
+		}
+	}
+}
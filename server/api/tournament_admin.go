@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"erupe-ce/server/channelserver"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// APITournamentAdmin schedules hunting tournaments and reviews their results.
+// *channelserver.TournamentService satisfies it.
+type APITournamentAdmin interface {
+	List() ([]channelserver.Tournament, error)
+	Get(id uint32) (*channelserver.TournamentDetail, error)
+	Create(detail channelserver.TournamentDetail) (*channelserver.TournamentDetail, error)
+	Update(id uint32, detail channelserver.TournamentDetail) (*channelserver.TournamentDetail, error)
+	Delete(id uint32) error
+	Results(id uint32, status string) ([]channelserver.TournamentResult, error)
+	Review(id, resultID uint32, status string) error
+}
+
+// AdminTournamentReviewRequest is the body of
+// PUT /v2/admin/tournaments/{id}/results/{resultId}.
+type AdminTournamentReviewRequest struct {
+	Status string `json:"status"`
+}
+
+// adminTournament parses the {id} route variable.
+func (s *APIServer) adminTournament(w http.ResponseWriter, r *http.Request) (uint32, bool) {
+	if s.tournamentAdmin == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Tournament administration is not available")
+		return 0, false
+	}
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid tournament ID")
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+// writeTournamentAdminError maps a tournament service error.
+func (s *APIServer) writeTournamentAdminError(w http.ResponseWriter, err error, tournamentID uint32) {
+	switch {
+	case errors.Is(err, channelserver.ErrTournamentNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Tournament not found")
+	case errors.Is(err, channelserver.ErrTournamentResultNotFound):
+		writeError(w, http.StatusNotFound, "not_found", "Result not found")
+	case errors.Is(err, channelserver.ErrInvalidTournament):
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+	default:
+		s.logger.Error("Tournament admin request failed", zap.Error(err), zap.Uint32("tournamentID", tournamentID))
+		writeError(w, http.StatusInternalServerError, "internal_error", "Internal server error")
+	}
+}
+
+// AdminListTournaments handles GET /v2/admin/tournaments.
+func (s *APIServer) AdminListTournaments(w http.ResponseWriter, r *http.Request) {
+	if s.tournamentAdmin == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Tournament administration is not available")
+		return
+	}
+	tournaments, err := s.tournamentAdmin.List()
+	if err != nil {
+		s.writeTournamentAdminError(w, err, 0)
+		return
+	}
+	if tournaments == nil {
+		tournaments = []channelserver.Tournament{}
+	}
+	writeJSON(w, tournaments)
+}
+
+// AdminCreateTournament handles POST /v2/admin/tournaments.
+func (s *APIServer) AdminCreateTournament(w http.ResponseWriter, r *http.Request) {
+	if s.tournamentAdmin == nil {
+		writeError(w, http.StatusServiceUnavailable, "unavailable", "Tournament administration is not available")
+		return
+	}
+	var req channelserver.TournamentDetail
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	detail, err := s.tournamentAdmin.Create(req)
+	if err != nil {
+		s.writeTournamentAdminError(w, err, 0)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Tournament scheduled via API", zap.Uint32("tournamentID", detail.ID), zap.Uint32("adminID", admin))
+	writeJSON(w, detail)
+}
+
+// AdminGetTournament handles GET /v2/admin/tournaments/{id}.
+func (s *APIServer) AdminGetTournament(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminTournament(w, r)
+	if !ok {
+		return
+	}
+	detail, err := s.tournamentAdmin.Get(id)
+	if err != nil {
+		s.writeTournamentAdminError(w, err, id)
+		return
+	}
+	writeJSON(w, detail)
+}
+
+// AdminUpdateTournament handles PUT /v2/admin/tournaments/{id}, replacing the
+// schedule, cups, sub-events and prizes. Sub-events keep their IDs when the
+// request repeats them.
+func (s *APIServer) AdminUpdateTournament(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminTournament(w, r)
+	if !ok {
+		return
+	}
+	var req channelserver.TournamentDetail
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	detail, err := s.tournamentAdmin.Update(id, req)
+	if err != nil {
+		s.writeTournamentAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Tournament updated via API", zap.Uint32("tournamentID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, detail)
+}
+
+// AdminDeleteTournament handles DELETE /v2/admin/tournaments/{id}.
+func (s *APIServer) AdminDeleteTournament(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminTournament(w, r)
+	if !ok {
+		return
+	}
+	if err := s.tournamentAdmin.Delete(id); err != nil {
+		s.writeTournamentAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Tournament deleted via API", zap.Uint32("tournamentID", id), zap.Uint32("adminID", admin))
+	writeJSON(w, struct{}{})
+}
+
+// AdminTournamentResults handles GET /v2/admin/tournaments/{id}/results,
+// optionally filtered with ?status=flagged and the like.
+func (s *APIServer) AdminTournamentResults(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminTournament(w, r)
+	if !ok {
+		return
+	}
+	results, err := s.tournamentAdmin.Results(id, r.URL.Query().Get("status"))
+	if err != nil {
+		s.writeTournamentAdminError(w, err, id)
+		return
+	}
+	if results == nil {
+		results = []channelserver.TournamentResult{}
+	}
+	writeJSON(w, results)
+}
+
+// AdminReviewTournamentResult handles
+// PUT /v2/admin/tournaments/{id}/results/{resultId}.
+func (s *APIServer) AdminReviewTournamentResult(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.adminTournament(w, r)
+	if !ok {
+		return
+	}
+	resultID, err := strconv.ParseUint(mux.Vars(r)["resultId"], 10, 32)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid result ID")
+		return
+	}
+	var req AdminTournamentReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	if err := s.tournamentAdmin.Review(id, uint32(resultID), req.Status); err != nil {
+		s.writeTournamentAdminError(w, err, id)
+		return
+	}
+	admin, _ := UserIDFromContext(r.Context())
+	s.logger.Info("Tournament result reviewed via API", zap.Uint32("tournamentID", id),
+		zap.Uint64("resultID", resultID), zap.String("status", req.Status), zap.Uint32("adminID", admin))
+	writeJSON(w, struct{}{})
+}
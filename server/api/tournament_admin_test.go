@@ -0,0 +1,200 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"erupe-ce/server/channelserver"
+)
+
+// mockTournamentAdmin implements APITournamentAdmin for testing.
+type mockTournamentAdmin struct {
+	detail  channelserver.TournamentDetail
+	results []channelserver.TournamentResult
+	err     error
+
+	created      *channelserver.TournamentDetail
+	updatedID    uint32
+	deletedID    uint32
+	resultStatus string
+	reviewed     [2]uint32
+	reviewStatus string
+}
+
+func (m *mockTournamentAdmin) List() ([]channelserver.Tournament, error) {
+	return []channelserver.Tournament{m.detail.Tournament}, m.err
+}
+
+func (m *mockTournamentAdmin) Get(_ uint32) (*channelserver.TournamentDetail, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	d := m.detail
+	return &d, nil
+}
+
+func (m *mockTournamentAdmin) Create(detail channelserver.TournamentDetail) (*channelserver.TournamentDetail, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	detail.ID = 5
+	m.created = &detail
+	return &detail, nil
+}
+
+func (m *mockTournamentAdmin) Update(id uint32, detail channelserver.TournamentDetail) (*channelserver.TournamentDetail, error) {
+	m.updatedID = id
+	if m.err != nil {
+		return nil, m.err
+	}
+	detail.ID = id
+	return &detail, nil
+}
+
+func (m *mockTournamentAdmin) Delete(id uint32) error {
+	m.deletedID = id
+	return m.err
+}
+
+func (m *mockTournamentAdmin) Results(_ uint32, status string) ([]channelserver.TournamentResult, error) {
+	m.resultStatus = status
+	return m.results, m.err
+}
+
+func (m *mockTournamentAdmin) Review(id, resultID uint32, status string) error {
+	m.reviewed = [2]uint32{id, resultID}
+	m.reviewStatus = status
+	return m.err
+}
+
+func newTournamentAdminTestServer(t *testing.T) (*APIServer, *mockTournamentAdmin) {
+	t.Helper()
+	server, _, _ := newAdminTestServer(t)
+	admin := &mockTournamentAdmin{detail: channelserver.TournamentDetail{
+		Tournament: channelserver.Tournament{ID: 3, Name: "Tournament #151", StartTime: 1000},
+	}}
+	server.tournamentAdmin = admin
+	return server, admin
+}
+
+func TestAdminCreateTournament(t *testing.T) {
+	server, admin := newTournamentAdminTestServer(t)
+
+	body := map[string]interface{}{
+		"name":      "Tournament #152",
+		"startTime": 2000,
+		"cycleDays": 21,
+		"cups":      []map[string]interface{}{{"cupGroup": 16, "cupType": 7, "name": "Speed Hunt"}},
+		"prizes":    []map[string]interface{}{{"cupGroup": 16, "placeFrom": 1, "placeTo": 3, "itemType": 7, "itemId": 100, "quantity": 1}},
+	}
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/tournaments", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if admin.created == nil || admin.created.Name != "Tournament #152" || admin.created.CycleDays != 21 ||
+		len(admin.created.Cups) != 1 || admin.created.Prizes[0].PlaceTo != 3 {
+		t.Errorf("created = %+v", admin.created)
+	}
+	var resp channelserver.TournamentDetail
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.ID != 5 || resp.StartTime != 2000 {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestAdminCreateTournament_Invalid(t *testing.T) {
+	server, admin := newTournamentAdminTestServer(t)
+	admin.err = fmt.Errorf("%w: startTime is required", channelserver.ErrInvalidTournament)
+
+	rec := doAdminRequest(t, server, "POST", "/v2/admin/tournaments", map[string]string{"name": "x"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+	rec = doAdminRequest(t, server, "POST", "/v2/admin/tournaments", "not an object")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad body: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminGetAndUpdateTournament(t *testing.T) {
+	server, admin := newTournamentAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/tournaments/3", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200", rec.Code)
+	}
+	rec = doAdminRequest(t, server, "PUT", "/v2/admin/tournaments/3", map[string]interface{}{"name": "Renamed", "startTime": 1000})
+	if rec.Code != http.StatusOK || admin.updatedID != 3 {
+		t.Errorf("PUT status = %d, updated %d", rec.Code, admin.updatedID)
+	}
+
+	admin.err = channelserver.ErrTournamentNotFound
+	rec = doAdminRequest(t, server, "GET", "/v2/admin/tournaments/9", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("missing: status = %d, want 404", rec.Code)
+	}
+	rec = doAdminRequest(t, server, "GET", "/v2/admin/tournaments/abc", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad id: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminDeleteTournament(t *testing.T) {
+	server, admin := newTournamentAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "DELETE", "/v2/admin/tournaments/3", nil)
+	if rec.Code != http.StatusOK || admin.deletedID != 3 {
+		t.Errorf("status = %d, deleted %d", rec.Code, admin.deletedID)
+	}
+}
+
+func TestAdminTournamentResults(t *testing.T) {
+	server, admin := newTournamentAdminTestServer(t)
+	admin.results = []channelserver.TournamentResult{{ID: 8, CharID: 2, Status: channelserver.TournamentResultFlagged, FlagReason: "submitted_too_soon"}}
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/tournaments/3/results?status=flagged", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if admin.resultStatus != "flagged" {
+		t.Errorf("status filter = %q, want flagged", admin.resultStatus)
+	}
+	var results []channelserver.TournamentResult
+	if err := json.NewDecoder(rec.Body).Decode(&results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].FlagReason != "submitted_too_soon" {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestAdminReviewTournamentResult(t *testing.T) {
+	server, admin := newTournamentAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "PUT", "/v2/admin/tournaments/3/results/8", AdminTournamentReviewRequest{Status: "rejected"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if admin.reviewed != [2]uint32{3, 8} || admin.reviewStatus != "rejected" {
+		t.Errorf("reviewed %v as %q", admin.reviewed, admin.reviewStatus)
+	}
+
+	admin.err = channelserver.ErrTournamentResultNotFound
+	rec = doAdminRequest(t, server, "PUT", "/v2/admin/tournaments/3/results/9", AdminTournamentReviewRequest{Status: "verified"})
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("missing result: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAdminTournaments_Unavailable(t *testing.T) {
+	server, _, _ := newAdminTestServer(t)
+
+	rec := doAdminRequest(t, server, "GET", "/v2/admin/tournaments", nil)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
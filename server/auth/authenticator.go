@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Rejections returned by Authenticate. Any other error means the backend
+// could not decide.
+var (
+	ErrUnknownUser = errors.New("auth: unknown user")
+	ErrBadPassword = errors.New("auth: wrong password")
+	// ErrForeignAccount means the backend accepted the login but the
+	// username belongs to an account another backend provisioned.
+	ErrForeignAccount = errors.New("auth: account belongs to another backend")
+)
+
+// Authenticator checks a username and password and returns the local user
+// ID they belong to.
+type Authenticator interface {
+	Authenticate(ctx context.Context, username, password string) (uint32, error)
+}
+
+// CredentialStore looks up a local user's ID and bcrypt password hash,
+// returning sql.ErrNoRows for an unknown username.
+type CredentialStore interface {
+	GetCredentials(ctx context.Context, username string) (uint32, string, error)
+}
+
+// LocalAuthenticator checks passwords against the users table.
+type LocalAuthenticator struct {
+	store CredentialStore
+}
+
+// NewLocalAuthenticator creates a new LocalAuthenticator.
+func NewLocalAuthenticator(store CredentialStore) *LocalAuthenticator {
+	return &LocalAuthenticator{store: store}
+}
+
+func (a *LocalAuthenticator) Authenticate(ctx context.Context, username, password string) (uint32, error) {
+	uid, hash, err := a.store.GetCredentials(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrUnknownUser
+	} else if err != nil {
+		return 0, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return 0, ErrBadPassword
+	}
+	return uid, nil
+}
+
+// Verifier checks credentials against an outside source of accounts.
+type Verifier interface {
+	Verify(ctx context.Context, username, password string) error
+}
+
+// UserProvisioner returns the local user ID for a username, creating the
+// account for backend if it does not exist yet. It returns ErrForeignAccount
+// if the account exists but was provisioned by a different backend.
+type UserProvisioner interface {
+	EnsureUser(ctx context.Context, backend, username string) (uint32, error)
+}
+
+// ExternalAuthenticator accepts whoever its Verifier accepts and maps them
+// onto a local account, creating one on first login.
+type ExternalAuthenticator struct {
+	backend  string
+	verifier Verifier
+	users    UserProvisioner
+}
+
+// NewExternalAuthenticator creates a new ExternalAuthenticator. backend names
+// the accounts it provisions, so it never adopts one it did not create.
+func NewExternalAuthenticator(backend string, v Verifier, users UserProvisioner) *ExternalAuthenticator {
+	return &ExternalAuthenticator{backend: backend, verifier: v, users: users}
+}
+
+func (a *ExternalAuthenticator) Authenticate(ctx context.Context, username, password string) (uint32, error) {
+	// An empty password is an anonymous bind to many LDAP servers, and no
+	// backend has a reason to accept one.
+	if username == "" || password == "" {
+		return 0, ErrBadPassword
+	}
+	if err := a.verifier.Verify(ctx, username, password); err != nil {
+		return 0, err
+	}
+	uid, err := a.users.EnsureUser(ctx, a.backend, username)
+	if errors.Is(err, ErrForeignAccount) {
+		return 0, err
+	} else if err != nil {
+		return 0, fmt.Errorf("auth: provision local user: %w", err)
+	}
+	return uid, nil
+}
+
+// unavailableAuthenticator fails every login; it stands in for a backend
+// whose configuration is invalid so a typo never falls back to another one.
+type unavailableAuthenticator struct {
+	err error
+}
+
+func (a unavailableAuthenticator) Authenticate(context.Context, string, string) (uint32, error) {
+	return 0, a.err
+}
+
+// New builds the Authenticator selected by opts. If the configuration is
+// invalid it returns the error together with an Authenticator that refuses
+// every login.
+func New(opts cfg.AuthenticationOptions, local CredentialStore, users UserProvisioner) (Authenticator, error) {
+	switch opts.Backend {
+	case "", "local":
+		return NewLocalAuthenticator(local), nil
+	case "webhook":
+		v, err := NewWebhookVerifier(opts.Webhook)
+		if err != nil {
+			return unavailableAuthenticator{err}, err
+		}
+		return NewExternalAuthenticator("webhook", v, users), nil
+	case "ldap":
+		v, err := NewLDAPVerifier(opts.LDAP)
+		if err != nil {
+			return unavailableAuthenticator{err}, err
+		}
+		return NewExternalAuthenticator("ldap", v, users), nil
+	default:
+		err := fmt.Errorf("auth: unknown backend %q", opts.Backend)
+		return unavailableAuthenticator{err}, err
+	}
+}
+
+// IsLocal reports whether a is the local users table, the only backend that
+// AutoCreateAccount and API registration may create accounts for.
+func IsLocal(a Authenticator) bool {
+	_, ok := a.(*LocalAuthenticator)
+	return ok
+}
+
+func timeout(seconds int) time.Duration {
+	if seconds <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(seconds) * time.Second
+}
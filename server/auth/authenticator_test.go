@@ -0,0 +1,292 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cfg "erupe-ce/config"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type memoryCredentials map[string]string
+
+func (m memoryCredentials) GetCredentials(_ context.Context, username string) (uint32, string, error) {
+	hash, ok := m[username]
+	if !ok {
+		return 0, "", sql.ErrNoRows
+	}
+	return 7, hash, nil
+}
+
+type memoryUsers struct {
+	ids      map[string]uint32
+	backends map[string]string // Owning backend per username; "local" if unset.
+}
+
+func (m *memoryUsers) EnsureUser(_ context.Context, backend, username string) (uint32, error) {
+	if id, ok := m.ids[username]; ok {
+		owner := "local"
+		if b, ok := m.backends[username]; ok {
+			owner = b
+		}
+		if owner != backend {
+			return 0, ErrForeignAccount
+		}
+		return id, nil
+	}
+	id := uint32(len(m.ids) + 100)
+	m.ids[username] = id
+	if m.backends == nil {
+		m.backends = map[string]string{}
+	}
+	m.backends[username] = backend
+	return id, nil
+}
+
+func TestLocalAuthenticator(t *testing.T) {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	a := NewLocalAuthenticator(memoryCredentials{"alice": string(hash)})
+	ctx := context.Background()
+
+	if uid, err := a.Authenticate(ctx, "alice", "hunter2"); err != nil || uid != 7 {
+		t.Errorf("good password = (%d, %v), want (7, nil)", uid, err)
+	}
+	if _, err := a.Authenticate(ctx, "alice", "wrong"); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("bad password err = %v, want ErrBadPassword", err)
+	}
+	if _, err := a.Authenticate(ctx, "bob", "hunter2"); !errors.Is(err, ErrUnknownUser) {
+		t.Errorf("unknown user err = %v, want ErrUnknownUser", err)
+	}
+}
+
+// webhookStub accepts alice/hunter2, knows alice only, and requires secret.
+func webhookStub(t *testing.T, secret string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+secret {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var req struct{ Username, Password string }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch {
+		case req.Username != "alice":
+			w.WriteHeader(http.StatusNotFound)
+		case req.Password != "hunter2":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWebhookAuthenticator(t *testing.T) {
+	srv := webhookStub(t, "s3cret")
+	users := &memoryUsers{ids: map[string]uint32{}}
+	a, err := New(cfg.AuthenticationOptions{
+		Backend: "webhook",
+		Webhook: cfg.WebhookAuthOptions{URL: srv.URL, Secret: "s3cret"},
+	}, nil, users)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if IsLocal(a) {
+		t.Error("webhook authenticator reported as local")
+	}
+	ctx := context.Background()
+
+	uid, err := a.Authenticate(ctx, "alice", "hunter2")
+	if err != nil || uid != 100 {
+		t.Fatalf("good login = (%d, %v), want (100, nil)", uid, err)
+	}
+	if again, _ := a.Authenticate(ctx, "alice", "hunter2"); again != uid {
+		t.Errorf("second login uid = %d, want the provisioned %d", again, uid)
+	}
+	if _, err := a.Authenticate(ctx, "alice", "wrong"); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("bad password err = %v, want ErrBadPassword", err)
+	}
+	if _, err := a.Authenticate(ctx, "bob", "x"); !errors.Is(err, ErrUnknownUser) {
+		t.Errorf("unknown user err = %v, want ErrUnknownUser", err)
+	}
+	if _, err := a.Authenticate(ctx, "alice", ""); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("empty password err = %v, want ErrBadPassword", err)
+	}
+	if len(users.ids) != 1 {
+		t.Errorf("provisioned %d users, want 1", len(users.ids))
+	}
+}
+
+func TestWebhookAuthenticator_RefusesLocalAccount(t *testing.T) {
+	srv := webhookStub(t, "s3cret")
+	users := &memoryUsers{ids: map[string]uint32{"alice": 7}}
+	a, _ := New(cfg.AuthenticationOptions{
+		Backend: "webhook",
+		Webhook: cfg.WebhookAuthOptions{URL: srv.URL, Secret: "s3cret"},
+	}, nil, users)
+
+	if uid, err := a.Authenticate(context.Background(), "alice", "hunter2"); !errors.Is(err, ErrForeignAccount) {
+		t.Errorf("login = (%d, %v), want ErrForeignAccount for a local account", uid, err)
+	}
+}
+
+func TestWebhookAuthenticator_BackendFailure(t *testing.T) {
+	srv := webhookStub(t, "s3cret")
+	a, _ := New(cfg.AuthenticationOptions{
+		Backend: "webhook",
+		Webhook: cfg.WebhookAuthOptions{URL: srv.URL, Secret: "wrong-secret"},
+	}, nil, &memoryUsers{ids: map[string]uint32{}})
+
+	_, err := a.Authenticate(context.Background(), "alice", "hunter2")
+	if err == nil || errors.Is(err, ErrBadPassword) || errors.Is(err, ErrUnknownUser) {
+		t.Errorf("err = %v, want a backend error", err)
+	}
+}
+
+func TestNew_InvalidConfigRefusesLogins(t *testing.T) {
+	for _, opts := range []cfg.AuthenticationOptions{
+		{Backend: "kerberos"},
+		{Backend: "webhook"},
+		{Backend: "ldap", LDAP: cfg.LDAPAuthOptions{URL: "ldap://localhost", BindDN: "uid=alice"}},
+		{Backend: "ldap", LDAP: cfg.LDAPAuthOptions{URL: "http://localhost", BindDN: "uid=%s"}},
+		{Backend: "ldap", LDAP: cfg.LDAPAuthOptions{URL: "ldaps://localhost", BindDN: "uid=%s", StartTLS: true}},
+	} {
+		a, err := New(opts, memoryCredentials{}, nil)
+		if err == nil {
+			t.Errorf("%+v: expected a config error", opts)
+			continue
+		}
+		if _, authErr := a.Authenticate(context.Background(), "alice", "hunter2"); authErr == nil {
+			t.Errorf("%+v: authenticator accepted a login", opts)
+		}
+	}
+	if a, err := New(cfg.AuthenticationOptions{}, memoryCredentials{}, nil); err != nil || !IsLocal(a) {
+		t.Errorf("empty backend = (%T, %v), want local", a, err)
+	}
+}
+
+// ldapStub answers simple binds: alice/hunter2 succeeds, a wrong password is
+// invalidCredentials and any other DN is noSuchObject. It records bind DNs.
+func ldapStub(t *testing.T) (string, chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	dns := make(chan string, 8)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer func() { _ = conn.Close() }()
+				req, err := ber.ReadPacket(conn)
+				if err != nil || len(req.Children) < 2 || len(req.Children[1].Children) < 3 {
+					return
+				}
+				bind := req.Children[1]
+				dn := bind.Children[1].Data.String()
+				password := bind.Children[2].Data.String()
+				dns <- dn
+				code := ldap.LDAPResultNoSuchObject
+				if dn == "uid=alice,ou=people" {
+					code = ldap.LDAPResultInvalidCredentials
+					if password == "hunter2" {
+						code = ldap.LDAPResultSuccess
+					}
+				}
+				_, _ = conn.Write(testBindResponse(req.Children[0].Value.(int64), code))
+			}(conn)
+		}
+	}()
+	return "ldap://" + ln.Addr().String(), dns
+}
+
+func testBindResponse(id int64, code int) []byte {
+	msg := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+	msg.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationBindResponse, nil, "")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), ""))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	res.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	msg.AppendChild(res)
+	return msg.Bytes()
+}
+
+func TestLDAPAuthenticator(t *testing.T) {
+	addr, dns := ldapStub(t)
+	a, err := New(cfg.AuthenticationOptions{
+		Backend: "ldap",
+		LDAP:    cfg.LDAPAuthOptions{URL: addr, BindDN: "uid=%s,ou=people", TimeoutSeconds: 2},
+	}, nil, &memoryUsers{ids: map[string]uint32{}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx := context.Background()
+
+	if uid, err := a.Authenticate(ctx, "alice", "hunter2"); err != nil || uid != 100 {
+		t.Errorf("good login = (%d, %v), want (100, nil)", uid, err)
+	}
+	if _, err := a.Authenticate(ctx, "alice", "wrong"); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("bad password err = %v, want ErrBadPassword", err)
+	}
+	if _, err := a.Authenticate(ctx, "bob", "x"); !errors.Is(err, ErrUnknownUser) {
+		t.Errorf("unknown user err = %v, want ErrUnknownUser", err)
+	}
+	if _, err := a.Authenticate(ctx, "x,ou=admins", "x"); !errors.Is(err, ErrUnknownUser) {
+		t.Errorf("injected DN err = %v, want ErrUnknownUser", err)
+	}
+	for _, want := range []string{"uid=alice,ou=people", "uid=alice,ou=people", "uid=bob,ou=people", `uid=x\,ou\=admins,ou=people`} {
+		if got := <-dns; got != want {
+			t.Errorf("bind DN = %q, want %q", got, want)
+		}
+	}
+	if _, err := a.Authenticate(ctx, "alice", ""); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("empty password err = %v, want ErrBadPassword", err)
+	}
+	if _, err := a.Authenticate(ctx, "alice", strings.Repeat("x", 70000)); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("oversized password err = %v, want ErrBadPassword", err)
+	}
+	if _, err := a.Authenticate(ctx, strings.Repeat("a", 70000), "hunter2"); !errors.Is(err, ErrUnknownUser) {
+		t.Errorf("oversized username err = %v, want ErrUnknownUser", err)
+	}
+	select {
+	case dn := <-dns:
+		t.Errorf("rejected login reached the directory as %q", dn)
+	default:
+	}
+}
+
+func TestEscapeDN(t *testing.T) {
+	tests := map[string]string{
+		"alice":    "alice",
+		"a,b":      `a\,b`,
+		" lead":    `\ lead`,
+		"trail ":   `trail\ `,
+		"#hash":    `\#hash`,
+		`q"<>;+=\`: `q\"\<\>\;\+\=\\`,
+		"nul\x00":  `nul\00`,
+	}
+	for in, want := range tests {
+		if got := escapeDN(in); got != want {
+			t.Errorf("escapeDN(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package auth holds the login logic shared by the sign server and the API:
+// pluggable password backends (the local users table, an HTTP/JSON webhook or
+// an LDAP bind), per-username and per-IP failure counters with doubling
+// lockouts, and a per-IP cap on automatically created accounts. Login state
+// lives in PostgreSQL so every process sharing a database sees the same
+// counters.
+package auth
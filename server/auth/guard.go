@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+// Scope says whether a lockout applies to a username or an IP address.
+type Scope string
+
+const (
+	ScopeUser Scope = "user"
+	ScopeIP   Scope = "ip"
+)
+
+// Lockout is an active lockout; the zero value means none.
+type Lockout struct {
+	Scope Scope
+	Until time.Time
+}
+
+// Locked reports whether the lockout is active.
+func (l Lockout) Locked() bool {
+	return !l.Until.IsZero()
+}
+
+// RetryAfter returns how long until the lockout ends, rounded up to a second.
+func (l Lockout) RetryAfter(now time.Time) time.Duration {
+	if !l.Locked() || !l.Until.After(now) {
+		return 0
+	}
+	return (l.Until.Sub(now) + time.Second - 1).Truncate(time.Second)
+}
+
+// Guard applies LoginProtectionOptions. Store errors are logged and fail
+// open, so a database hiccup never locks everyone out. A nil *Guard allows
+// everything, which keeps servers built without a database working.
+type Guard struct {
+	store  LoginStore
+	opts   cfg.LoginProtectionOptions
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewGuard returns a Guard, or nil if protection is disabled.
+func NewGuard(store LoginStore, opts cfg.LoginProtectionOptions, logger *zap.Logger) *Guard {
+	if !opts.Enabled || store == nil {
+		return nil
+	}
+	return &Guard{store: store, opts: opts, logger: logger, now: time.Now}
+}
+
+func userKey(username string) string { return "user:" + strings.ToLower(username) }
+func ipKey(ip string) string         { return "ip:" + ip }
+
+// RemoteIP returns the host part of a net.Conn or http.Request remote address.
+func RemoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// Check returns the active lockout of the username or the IP, if any.
+func (g *Guard) Check(username, ip string) Lockout {
+	if g == nil {
+		return Lockout{}
+	}
+	now := g.now()
+	for _, k := range []struct {
+		scope Scope
+		key   string
+	}{{ScopeIP, ipKey(ip)}, {ScopeUser, userKey(username)}} {
+		until, err := g.store.LockedUntil(k.key)
+		if err != nil {
+			g.logger.Error("Failed to read login lockout", zap.Error(err), zap.String("key", k.key))
+			continue
+		}
+		if until.After(now) {
+			return Lockout{Scope: k.scope, Until: until}
+		}
+	}
+	return Lockout{}
+}
+
+// Failure counts a failed login against the username and the IP and returns
+// the lockout it started, if any.
+func (g *Guard) Failure(username, ip string) Lockout {
+	if g == nil {
+		return Lockout{}
+	}
+	var lock Lockout
+	if until := g.fail(userKey(username), g.opts.MaxFailures); !until.IsZero() {
+		lock = Lockout{Scope: ScopeUser, Until: until}
+	}
+	if until := g.fail(ipKey(ip), g.opts.MaxFailuresPerIP); !until.IsZero() {
+		lock = Lockout{Scope: ScopeIP, Until: until}
+	}
+	if lock.Locked() {
+		g.logger.Warn("Login locked out after repeated failures",
+			zap.String("username", username), zap.String("ip", ip),
+			zap.String("scope", string(lock.Scope)), zap.Time("until", lock.Until))
+	}
+	return lock
+}
+
+// fail counts one failure for key and locks it once the count reaches max,
+// returning the end of the new lockout.
+func (g *Guard) fail(key string, max int) time.Time {
+	now := g.now()
+	failures, err := g.store.AddFailure(key, now, now.Add(-time.Duration(g.opts.ResetSeconds)*time.Second))
+	if err != nil {
+		g.logger.Error("Failed to record login failure", zap.Error(err), zap.String("key", key))
+		return time.Time{}
+	}
+	if max <= 0 || failures < max {
+		return time.Time{}
+	}
+	until := now.Add(g.lockoutLength(failures - max))
+	if err := g.store.SetLockedUntil(key, until); err != nil {
+		g.logger.Error("Failed to store login lockout", zap.Error(err), zap.String("key", key))
+		return time.Time{}
+	}
+	return until
+}
+
+// lockoutLength doubles LockoutSeconds for each failure past the limit, up
+// to MaxLockoutSeconds.
+func (g *Guard) lockoutLength(extra int) time.Duration {
+	length := time.Duration(g.opts.LockoutSeconds) * time.Second
+	limit := time.Duration(g.opts.MaxLockoutSeconds) * time.Second
+	for i := 0; i < extra && length < limit; i++ {
+		length *= 2
+	}
+	if limit > 0 && length > limit {
+		length = limit
+	}
+	return length
+}
+
+// Success clears the username's failures. The IP's count is kept, so one
+// valid account cannot be used to reset an address that is guessing others.
+func (g *Guard) Success(username string) {
+	if g == nil {
+		return
+	}
+	if err := g.store.ClearFailures(userKey(username)); err != nil {
+		g.logger.Error("Failed to clear login failures", zap.Error(err), zap.String("username", username))
+	}
+}
+
+// AllowAccountCreation reports whether the IP is still under its daily
+// automatic account creation cap.
+func (g *Guard) AllowAccountCreation(ip string) bool {
+	if g == nil || g.opts.AutoCreatePerIPDaily <= 0 {
+		return true
+	}
+	n, err := g.store.CountAccountCreations(ip, g.now().Add(-24*time.Hour))
+	if err != nil {
+		g.logger.Error("Failed to count account creations", zap.Error(err), zap.String("ip", ip))
+		return true
+	}
+	return n < g.opts.AutoCreatePerIPDaily
+}
+
+// AccountCreated records an automatically created account against the IP.
+func (g *Guard) AccountCreated(ip string, userID uint32) {
+	if g == nil {
+		return
+	}
+	if err := g.store.AddAccountCreation(ip, userID, g.now()); err != nil {
+		g.logger.Error("Failed to record account creation", zap.Error(err), zap.String("ip", ip))
+	}
+}
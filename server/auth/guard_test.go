@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+type failureRow struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// memoryLoginStore is an in-memory LoginStore.
+type memoryLoginStore struct {
+	rows      map[string]*failureRow
+	creations map[string][]time.Time
+	err       error
+}
+
+func newMemoryLoginStore() *memoryLoginStore {
+	return &memoryLoginStore{rows: map[string]*failureRow{}, creations: map[string][]time.Time{}}
+}
+
+func (m *memoryLoginStore) LockedUntil(key string) (time.Time, error) {
+	if m.err != nil {
+		return time.Time{}, m.err
+	}
+	if row, ok := m.rows[key]; ok {
+		return row.lockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+func (m *memoryLoginStore) AddFailure(key string, now, resetBefore time.Time) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	row, ok := m.rows[key]
+	if !ok {
+		row = &failureRow{}
+		m.rows[key] = row
+	}
+	if row.lastFailure.Before(resetBefore) {
+		row.failures = 0
+	}
+	row.failures++
+	row.lastFailure = now
+	return row.failures, nil
+}
+
+func (m *memoryLoginStore) SetLockedUntil(key string, until time.Time) error {
+	m.rows[key].lockedUntil = until
+	return nil
+}
+
+func (m *memoryLoginStore) ClearFailures(key string) error {
+	delete(m.rows, key)
+	return nil
+}
+
+func (m *memoryLoginStore) CountAccountCreations(ip string, since time.Time) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	n := 0
+	for _, at := range m.creations[ip] {
+		if !at.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memoryLoginStore) AddAccountCreation(ip string, _ uint32, at time.Time) error {
+	m.creations[ip] = append(m.creations[ip], at)
+	return nil
+}
+
+var testLoginOptions = cfg.LoginProtectionOptions{
+	Enabled:              true,
+	MaxFailures:          3,
+	MaxFailuresPerIP:     10,
+	LockoutSeconds:       30,
+	MaxLockoutSeconds:    100,
+	ResetSeconds:         600,
+	AutoCreatePerIPDaily: 2,
+}
+
+// newTestGuard returns a Guard on a memory store whose clock the test drives.
+func newTestGuard(opts cfg.LoginProtectionOptions) (*Guard, *memoryLoginStore, *time.Time) {
+	store := newMemoryLoginStore()
+	g := NewGuard(store, opts, zap.NewNop())
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+	return g, store, &now
+}
+
+func TestGuard_LocksUsernameAfterMaxFailures(t *testing.T) {
+	g, _, now := newTestGuard(testLoginOptions)
+
+	for i := 0; i < 2; i++ {
+		if lock := g.Failure("Hunter", "10.0.0.1"); lock.Locked() {
+			t.Fatalf("failure %d locked out early", i+1)
+		}
+	}
+	lock := g.Failure("hunter", "10.0.0.2")
+	if lock.Scope != ScopeUser || !lock.Until.Equal(now.Add(30*time.Second)) {
+		t.Fatalf("third failure = %+v, want a 30s username lockout", lock)
+	}
+	if got := g.Check("HUNTER", "10.0.0.3"); got.Scope != ScopeUser {
+		t.Errorf("Check = %+v, want the username locked from any address", got)
+	}
+	if got := g.Check("other", "10.0.0.1"); got.Locked() {
+		t.Errorf("Check for another user = %+v, want none", got)
+	}
+
+	*now = now.Add(31 * time.Second)
+	if got := g.Check("hunter", "10.0.0.1"); got.Locked() {
+		t.Errorf("Check after expiry = %+v, want none", got)
+	}
+}
+
+func TestGuard_LockoutDoublesUpToMax(t *testing.T) {
+	g, _, now := newTestGuard(testLoginOptions)
+	want := []time.Duration{0, 0, 30 * time.Second, 60 * time.Second, 100 * time.Second, 100 * time.Second}
+	for i, w := range want {
+		lock := g.Failure("hunter", "10.0.0.1")
+		var got time.Duration
+		if lock.Locked() {
+			got = lock.Until.Sub(*now)
+		}
+		if got != w {
+			t.Errorf("failure %d: lockout %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestGuard_CountResetsAfterQuietPeriod(t *testing.T) {
+	g, _, now := newTestGuard(testLoginOptions)
+	g.Failure("hunter", "10.0.0.1")
+	g.Failure("hunter", "10.0.0.1")
+
+	*now = now.Add(11 * time.Minute)
+	if lock := g.Failure("hunter", "10.0.0.1"); lock.Locked() {
+		t.Errorf("failure after the reset window locked out: %+v", lock)
+	}
+}
+
+func TestGuard_SuccessClearsUsernameOnly(t *testing.T) {
+	opts := testLoginOptions
+	opts.MaxFailuresPerIP = 3
+	g, _, _ := newTestGuard(opts)
+	g.Failure("hunter", "10.0.0.1")
+	g.Failure("hunter", "10.0.0.1")
+	g.Success("hunter")
+
+	if lock := g.Failure("hunter", "10.0.0.1"); lock.Scope != ScopeIP {
+		t.Errorf("third failure from the address = %+v, want an IP lockout", lock)
+	}
+	if got := g.Check("someone", "10.0.0.1"); got.Scope != ScopeIP {
+		t.Errorf("Check = %+v, want the address locked for every username", got)
+	}
+}
+
+func TestGuard_AccountCreationCap(t *testing.T) {
+	g, _, now := newTestGuard(testLoginOptions)
+	for i := 0; i < 2; i++ {
+		if !g.AllowAccountCreation("10.0.0.1") {
+			t.Fatalf("creation %d refused", i+1)
+		}
+		g.AccountCreated("10.0.0.1", uint32(i+1))
+	}
+	if g.AllowAccountCreation("10.0.0.1") {
+		t.Error("third creation in a day allowed")
+	}
+	if !g.AllowAccountCreation("10.0.0.2") {
+		t.Error("another address refused")
+	}
+	*now = now.Add(25 * time.Hour)
+	if !g.AllowAccountCreation("10.0.0.1") {
+		t.Error("creation refused the next day")
+	}
+}
+
+func TestGuard_StoreErrorsFailOpen(t *testing.T) {
+	g, store, _ := newTestGuard(testLoginOptions)
+	store.err = errors.New("db down")
+	for i := 0; i < 5; i++ {
+		if lock := g.Failure("hunter", "10.0.0.1"); lock.Locked() {
+			t.Fatal("locked out while the store is failing")
+		}
+	}
+	if g.Check("hunter", "10.0.0.1").Locked() || !g.AllowAccountCreation("10.0.0.1") {
+		t.Error("store errors should not block logins")
+	}
+}
+
+func TestGuard_NilAndDisabled(t *testing.T) {
+	var g *Guard
+	if g.Check("a", "b").Locked() || g.Failure("a", "b").Locked() || !g.AllowAccountCreation("b") {
+		t.Error("nil Guard should allow everything")
+	}
+	g.Success("a")
+	g.AccountCreated("b", 1)
+
+	if NewGuard(newMemoryLoginStore(), cfg.LoginProtectionOptions{}, zap.NewNop()) != nil {
+		t.Error("disabled options should give a nil Guard")
+	}
+}
+
+func TestLockoutRetryAfter(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	lock := Lockout{Scope: ScopeUser, Until: now.Add(1500 * time.Millisecond)}
+	if got := lock.RetryAfter(now); got != 2*time.Second {
+		t.Errorf("RetryAfter = %v, want 2s", got)
+	}
+	if got := (Lockout{}).RetryAfter(now); got != 0 {
+		t.Errorf("RetryAfter without a lockout = %v, want 0", got)
+	}
+}
+
+func TestRemoteIP(t *testing.T) {
+	tests := map[string]string{
+		"192.168.1.5:53312": "192.168.1.5",
+		"[::1]:8080":        "::1",
+		"10.0.0.1":          "10.0.0.1",
+	}
+	for addr, want := range tests {
+		if got := RemoteIP(addr); got != want {
+			t.Errorf("RemoteIP(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
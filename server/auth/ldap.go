@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// maxLDAPCredential caps the bind DN and password sent to the directory. No
+// real DN or password comes close, and it keeps a hostile login from making
+// the verifier send an arbitrarily large request.
+const maxLDAPCredential = 1024
+
+// LDAPVerifier checks credentials with an LDAPv3 simple bind as the user.
+// It never searches or writes, so the directory only has to let users bind
+// as themselves.
+//
+// A simple bind sends the password as it is. Over plain ldap:// without
+// StartTLS it crosses the network in cleartext.
+type LDAPVerifier struct {
+	url      string
+	host     string
+	bindDN   string
+	startTLS bool
+	insecure bool
+	timeout  time.Duration
+}
+
+// NewLDAPVerifier creates a new LDAPVerifier. BindDN is a template whose %s
+// is replaced with the escaped username, e.g. "uid=%s,ou=people,dc=example".
+func NewLDAPVerifier(opts cfg.LDAPAuthOptions) (*LDAPVerifier, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("auth: invalid Authentication.LDAP.URL %q", opts.URL)
+	}
+	if strings.Count(opts.BindDN, "%s") != 1 {
+		return nil, errors.New("auth: Authentication.LDAP.BindDN must contain exactly one %s")
+	}
+	switch u.Scheme {
+	case "ldap":
+	case "ldaps":
+		if opts.StartTLS {
+			return nil, errors.New("auth: Authentication.LDAP.StartTLS only applies to ldap:// URLs")
+		}
+	default:
+		return nil, fmt.Errorf("auth: unsupported LDAP scheme %q", u.Scheme)
+	}
+	return &LDAPVerifier{
+		url:      opts.URL,
+		host:     u.Hostname(),
+		bindDN:   opts.BindDN,
+		startTLS: opts.StartTLS,
+		insecure: opts.InsecureSkipVerify,
+		timeout:  timeout(opts.TimeoutSeconds),
+	}, nil
+}
+
+func (v *LDAPVerifier) Verify(ctx context.Context, username, password string) error {
+	// Guard here as well as in ExternalAuthenticator: an empty simple bind is
+	// an anonymous bind that most servers accept.
+	if password == "" {
+		return ErrBadPassword
+	}
+	dn := fmt.Sprintf(v.bindDN, escapeDN(username))
+	if len(dn) > maxLDAPCredential {
+		return ErrUnknownUser
+	}
+	if len(password) > maxLDAPCredential {
+		return ErrBadPassword
+	}
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	tlsConfig := &tls.Config{ServerName: v.host, InsecureSkipVerify: v.insecure}
+	conn, err := ldap.DialURL(v.url,
+		ldap.DialWithDialer(&net.Dialer{Deadline: deadline}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return fmt.Errorf("auth: ldap: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	// Closing the connection aborts a request in flight, so a cancelled
+	// login does not wait for the directory.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	conn.SetTimeout(time.Until(deadline))
+
+	if v.startTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("auth: ldap: %w", err)
+		}
+	}
+	err = conn.Bind(dn, password)
+	switch {
+	case err == nil:
+		_ = conn.Unbind()
+		return nil
+	case ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials):
+		return ErrBadPassword
+	case ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject):
+		return ErrUnknownUser
+	default:
+		return fmt.Errorf("auth: ldap: %w", err)
+	}
+}
+
+// escapeDN escapes an attribute value for use in a DN (RFC 4514 §2.4).
+func escapeDN(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == 0:
+			b.WriteString(`\00`)
+			continue
+		case strings.IndexByte(`,+"\<>;=`, c) >= 0,
+			i == 0 && (c == ' ' || c == '#'),
+			i == len(s)-1 && c == ' ':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
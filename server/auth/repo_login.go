@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// LoginStore persists failure counters and account creations.
+type LoginStore interface {
+	// LockedUntil returns the end of a key's lockout, or the zero time.
+	LockedUntil(key string) (time.Time, error)
+	// AddFailure counts a failure at now and returns the key's count,
+	// starting over if the previous failure was before resetBefore.
+	AddFailure(key string, now, resetBefore time.Time) (int, error)
+	SetLockedUntil(key string, until time.Time) error
+	ClearFailures(key string) error
+	CountAccountCreations(ip string, since time.Time) (int, error)
+	AddAccountCreation(ip string, userID uint32, at time.Time) error
+}
+
+// LoginRepository implements LoginStore with PostgreSQL.
+type LoginRepository struct {
+	db *sqlx.DB
+}
+
+// NewLoginRepository creates a new LoginRepository.
+func NewLoginRepository(db *sqlx.DB) *LoginRepository {
+	return &LoginRepository{db: db}
+}
+
+func (r *LoginRepository) LockedUntil(key string) (time.Time, error) {
+	var until sql.NullTime
+	err := r.db.QueryRow(`SELECT locked_until FROM login_failures WHERE key = $1`, key).Scan(&until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	return until.Time, err
+}
+
+func (r *LoginRepository) AddFailure(key string, now, resetBefore time.Time) (int, error) {
+	var failures int
+	err := r.db.QueryRow(`
+		INSERT INTO login_failures (key, failures, last_failure) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_failures.last_failure < $3 THEN 1 ELSE login_failures.failures + 1 END,
+			last_failure = EXCLUDED.last_failure
+		RETURNING failures
+	`, key, now, resetBefore).Scan(&failures)
+	return failures, err
+}
+
+func (r *LoginRepository) SetLockedUntil(key string, until time.Time) error {
+	_, err := r.db.Exec(`UPDATE login_failures SET locked_until = $2 WHERE key = $1`, key, until)
+	return err
+}
+
+func (r *LoginRepository) ClearFailures(key string) error {
+	_, err := r.db.Exec(`DELETE FROM login_failures WHERE key = $1`, key)
+	return err
+}
+
+func (r *LoginRepository) CountAccountCreations(ip string, since time.Time) (int, error) {
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM account_creations WHERE ip = $1 AND created_at >= $2`, ip, since).Scan(&n)
+	return n, err
+}
+
+func (r *LoginRepository) AddAccountCreation(ip string, userID uint32, at time.Time) error {
+	_, err := r.db.Exec(`INSERT INTO account_creations (ip, user_id, created_at) VALUES ($1, $2, $3)`, ip, userID, at)
+	return err
+}
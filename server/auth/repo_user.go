@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserRepository implements UserProvisioner with PostgreSQL.
+type UserRepository struct {
+	db *sqlx.DB
+}
+
+// NewUserRepository creates a new UserRepository.
+func NewUserRepository(db *sqlx.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// EnsureUser returns the ID of username's account, creating it for backend
+// if needed. An existing account is only adopted if backend provisioned it,
+// so an external login can never take over a local account of the same name.
+// Accounts created here get a random password hash: their password lives in
+// the external backend and cannot be used against the local table.
+func (r *UserRepository) EnsureUser(ctx context.Context, backend, username string) (uint32, error) {
+	var uid uint32
+	var owner string
+	err := r.db.QueryRowContext(ctx, `SELECT id, auth_backend FROM users WHERE username = $1`, username).Scan(&uid, &owner)
+	if err == nil {
+		return ownedUser(uid, owner, backend)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return 0, err
+	}
+	hash, err := bcrypt.GenerateFromPassword(secret, bcrypt.DefaultCost)
+	if err != nil {
+		return 0, err
+	}
+	// DO UPDATE rather than DO NOTHING so RETURNING yields the ID when a
+	// concurrent login created the row first.
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO users (username, password, return_expires, auth_backend) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (username) DO UPDATE SET username = EXCLUDED.username
+		RETURNING id, auth_backend
+	`, username, string(hash), time.Now().Add(30*24*time.Hour), backend).Scan(&uid, &owner)
+	if err != nil {
+		return 0, err
+	}
+	return ownedUser(uid, owner, backend)
+}
+
+func ownedUser(uid uint32, owner, backend string) (uint32, error) {
+	if owner != backend {
+		return 0, ErrForeignAccount
+	}
+	return uid, nil
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	cfg "erupe-ce/config"
+)
+
+// WebhookVerifier checks credentials by POSTing them as JSON to a URL,
+// typically a small endpoint on a community's forum.
+//
+//	POST {"username": "...", "password": "..."}
+//	200 accepted, 401/403 wrong password, 404 unknown user
+type WebhookVerifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookVerifier creates a new WebhookVerifier.
+func NewWebhookVerifier(opts cfg.WebhookAuthOptions) (*WebhookVerifier, error) {
+	if opts.URL == "" {
+		return nil, errors.New("auth: webhook backend needs Authentication.Webhook.URL")
+	}
+	return &WebhookVerifier{
+		url:    opts.URL,
+		secret: opts.Secret,
+		client: &http.Client{Timeout: timeout(opts.TimeoutSeconds)},
+	}, nil
+}
+
+func (v *WebhookVerifier) Verify(ctx context.Context, username, password string) error {
+	body, err := json.Marshal(map[string]string{"username": username, "password": password})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if v.secret != "" {
+		req.Header.Set("Authorization", "Bearer "+v.secret)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("auth: webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrBadPassword
+	case http.StatusNotFound:
+		return ErrUnknownUser
+	default:
+		return fmt.Errorf("auth: webhook returned %s", resp.Status)
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 // ChannelRegistry abstracts cross-channel operations behind an interface.
 // The default LocalChannelRegistry wraps the in-process []*Server slice.
-// Future implementations may use DB/Redis/NATS for multi-process deployments.
+// PostgresChannelRegistry extends it across processes sharing one database.
 type ChannelRegistry interface {
 	// Worldcast broadcasts a packet to all sessions across all channels.
 	Worldcast(pkt mhfpacket.MHFPacket, ignoredSession *Session, ignoredChannel *Server)
@@ -46,6 +46,7 @@ type SessionSnapshot struct {
 
 // StageSnapshot is an immutable copy of stage data taken under lock.
 type StageSnapshot struct {
+	GlobalID      string // Owning channel's GlobalID
 	ServerIP      net.IP
 	ServerPort    uint16
 	StageID       string
@@ -122,6 +122,7 @@ func (r *LocalChannelRegistry) SearchStages(stagePrefix string, max int) []Stage
 
 		cIP := net.ParseIP(c.IP).To4()
 		cPort := c.Port
+		cGID := c.GlobalID
 		c.stages.Range(func(_ string, stage *Stage) bool {
 			if len(results) >= max {
 				return false
@@ -148,6 +149,7 @@ func (r *LocalChannelRegistry) SearchStages(stagePrefix string, max int) []Stage
 			}
 
 			results = append(results, StageSnapshot{
+				GlobalID:      cGID,
 				ServerIP:      cIP,
 				ServerPort:    cPort,
 				StageID:       stage.id,
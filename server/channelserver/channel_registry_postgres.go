@@ -0,0 +1,453 @@
+package channelserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"erupe-ce/common/byteframe"
+	cfg "erupe-ce/config"
+	"erupe-ce/network/clientctx"
+	"erupe-ce/network/mhfpacket"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// registryNotifyChannel is the LISTEN/NOTIFY channel shared by every process.
+const registryNotifyChannel = "erupe_registry"
+
+// registryMaxPayload is the largest NOTIFY payload PostgreSQL accepts
+// (8000 bytes) minus headroom for the JSON envelope.
+const registryMaxPayload = 7900
+
+// Registry message kinds relayed between processes.
+const (
+	registryMsgWorldcast  = "worldcast"
+	registryMsgMail       = "mail"
+	registryMsgDisconnect = "disconnect"
+)
+
+// registryMessage is the JSON envelope sent through NOTIFY.
+type registryMessage struct {
+	Origin     string   `json:"o"`
+	Kind       string   `json:"k"`
+	Data       []byte   `json:"d,omitempty"` // Worldcast: opcode-prefixed packet bytes
+	CharIDs    []uint32 `json:"c,omitempty"` // Mail recipient or disconnect targets
+	SenderID   uint32   `json:"s,omitempty"`
+	SenderName string   `json:"n,omitempty"`
+}
+
+// PostgresRegistryConfig configures a PostgresChannelRegistry.
+type PostgresRegistryConfig struct {
+	Logger      *zap.Logger
+	DB          *sqlx.DB
+	ConnString  string        // Connection string for the dedicated LISTEN connection
+	InstanceID  string        // Unique name for this process; defaults to hostname-pid
+	Interval    time.Duration // How often snapshots are published
+	ErupeConfig *cfg.Config
+}
+
+// PostgresChannelRegistry is a ChannelRegistry for deployments that run
+// several Erupe processes against one database. Channels owned by this
+// process are served by a LocalChannelRegistry. Session and stage snapshots
+// are published to the registry_* tables on a fixed interval so other
+// processes can search them, and worldcasts, mail notifications and
+// disconnects are relayed through LISTEN/NOTIFY.
+//
+// FindSessionByCharID only returns sessions owned by this process, since a
+// remote *Session cannot be represented; remote search results may lag by up
+// to one publish interval.
+type PostgresChannelRegistry struct {
+	local      *LocalChannelRegistry
+	db         *sqlx.DB
+	logger     *zap.Logger
+	connString string
+	instanceID string
+	interval   time.Duration
+	mode       cfg.Mode
+
+	listener *pq.Listener
+	notify   func(payload string) error // Overridable for tests
+	done     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewPostgresChannelRegistry creates a PostgresChannelRegistry for the given
+// local channels. Call Start to begin publishing and listening.
+func NewPostgresChannelRegistry(channels []*Server, config *PostgresRegistryConfig) *PostgresChannelRegistry {
+	r := &PostgresChannelRegistry{
+		local:      NewLocalChannelRegistry(channels),
+		db:         config.DB,
+		logger:     config.Logger,
+		connString: config.ConnString,
+		instanceID: config.InstanceID,
+		interval:   config.Interval,
+		done:       make(chan struct{}),
+	}
+	if config.ErupeConfig != nil {
+		r.mode = config.ErupeConfig.RealClientMode
+	}
+	if r.instanceID == "" {
+		host, _ := os.Hostname()
+		r.instanceID = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if r.interval <= 0 {
+		r.interval = 5 * time.Second
+	}
+	r.notify = r.pgNotify
+	return r
+}
+
+// InstanceID returns the name this process publishes its snapshots under.
+func (r *PostgresChannelRegistry) InstanceID() string {
+	return r.instanceID
+}
+
+// Start opens the LISTEN connection, publishes an initial snapshot and
+// launches the background publish and receive loops.
+func (r *PostgresChannelRegistry) Start() error {
+	r.listener = pq.NewListener(r.connString, 10*time.Second, time.Minute,
+		func(ev pq.ListenerEventType, err error) {
+			if err != nil {
+				r.logger.Warn("Registry listener event", zap.Int("event", int(ev)), zap.Error(err))
+			}
+		})
+	if err := r.listener.Listen(registryNotifyChannel); err != nil {
+		_ = r.listener.Close()
+		return fmt.Errorf("listening on %s: %w", registryNotifyChannel, err)
+	}
+	if err := r.Publish(); err != nil {
+		r.logger.Warn("Initial registry publish failed", zap.Error(err))
+	}
+
+	r.wg.Add(2)
+	go r.publishLoop()
+	go r.listenLoop()
+	return nil
+}
+
+// Close stops the background loops and removes this instance's snapshots.
+func (r *PostgresChannelRegistry) Close() {
+	r.stopOnce.Do(func() {
+		close(r.done)
+		if r.listener != nil {
+			_ = r.listener.Close()
+		}
+		r.wg.Wait()
+		if _, err := r.db.Exec(`DELETE FROM registry_sessions WHERE instance_id=$1`, r.instanceID); err != nil {
+			r.logger.Warn("Failed to clear registry sessions", zap.Error(err))
+		}
+		if _, err := r.db.Exec(`DELETE FROM registry_stages WHERE instance_id=$1`, r.instanceID); err != nil {
+			r.logger.Warn("Failed to clear registry stages", zap.Error(err))
+		}
+		if _, err := r.db.Exec(`DELETE FROM registry_instances WHERE instance_id=$1`, r.instanceID); err != nil {
+			r.logger.Warn("Failed to clear registry instance", zap.Error(err))
+		}
+	})
+}
+
+func (r *PostgresChannelRegistry) publishLoop() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+			if err := r.Publish(); err != nil {
+				r.logger.Warn("Registry publish failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (r *PostgresChannelRegistry) listenLoop() {
+	defer r.wg.Done()
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case n := <-r.listener.Notify:
+			// A nil notification signals a reconnect; anything sent in
+			// between is lost, which is acceptable for these best-effort
+			// relays.
+			if n != nil {
+				r.handleNotification(n.Extra)
+			}
+		case <-ping.C:
+			go func() { _ = r.listener.Ping() }()
+		}
+	}
+}
+
+// staleSeconds is how long an instance may go without a heartbeat before
+// its snapshots are ignored. Queries compare it against the database clock,
+// e.g. heartbeat >= now() - $n::float8 * interval '1 second', so instances
+// whose local clocks disagree still agree on who is stale.
+func (r *PostgresChannelRegistry) staleSeconds() float64 {
+	return (3 * r.interval).Seconds()
+}
+
+// Publish replaces this instance's session and stage snapshots and refreshes
+// its heartbeat, all in one transaction. Snapshots left behind by instances
+// that stopped heartbeating are reaped at the same time.
+func (r *PostgresChannelRegistry) Publish() error {
+	sessions := r.local.SearchSessions(func(SessionSnapshot) bool { return true }, math.MaxInt)
+	stages := r.local.SearchStages("", math.MaxInt)
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`INSERT INTO registry_instances (instance_id, heartbeat) VALUES ($1, now())
+		ON CONFLICT (instance_id) DO UPDATE SET heartbeat=now()`, r.instanceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM registry_instances
+		WHERE heartbeat < now() - $1::float8 * interval '1 second'`, r.staleSeconds()); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM registry_sessions WHERE instance_id=$1
+		OR instance_id NOT IN (SELECT instance_id FROM registry_instances)`, r.instanceID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM registry_stages WHERE instance_id=$1
+		OR instance_id NOT IN (SELECT instance_id FROM registry_instances)`, r.instanceID); err != nil {
+		return err
+	}
+	for _, snap := range sessions {
+		if snap.CharID == 0 {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO registry_sessions
+			(instance_id, char_id, name, stage_id, server_ip, server_port, user_binary3)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT DO NOTHING`,
+			r.instanceID, snap.CharID, snap.Name, snap.StageID, snap.ServerIP.String(), snap.ServerPort, snap.UserBinary3); err != nil {
+			return err
+		}
+	}
+	for _, snap := range stages {
+		if _, err := tx.Exec(`INSERT INTO registry_stages
+			(instance_id, global_id, stage_id, server_ip, server_port, client_count, reserved, quest_reserved, max_players, bin_data0, bin_data1, bin_data3)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) ON CONFLICT DO NOTHING`,
+			r.instanceID, snap.GlobalID, snap.StageID, snap.ServerIP.String(), snap.ServerPort, snap.ClientCount,
+			snap.Reserved, snap.QuestReserved, snap.MaxPlayers, snap.RawBinData0, snap.RawBinData1, snap.RawBinData3); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresChannelRegistry) pgNotify(payload string) error {
+	_, err := r.db.Exec(`SELECT pg_notify($1, $2)`, registryNotifyChannel, payload)
+	return err
+}
+
+func (r *PostgresChannelRegistry) send(msg registryMessage) {
+	msg.Origin = r.instanceID
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		r.logger.Error("Failed to encode registry message", zap.Error(err))
+		return
+	}
+	if len(payload) > registryMaxPayload {
+		r.logger.Warn("Registry message too large to relay",
+			zap.String("kind", msg.Kind), zap.Int("bytes", len(payload)))
+		return
+	}
+	if err := r.notify(string(payload)); err != nil {
+		r.logger.Warn("Failed to relay registry message", zap.String("kind", msg.Kind), zap.Error(err))
+	}
+}
+
+// handleNotification applies a message relayed by another process.
+// Messages sent by this instance are ignored since they were already
+// handled locally.
+func (r *PostgresChannelRegistry) handleNotification(payload string) {
+	var msg registryMessage
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		r.logger.Warn("Malformed registry message", zap.Error(err))
+		return
+	}
+	if msg.Origin == r.instanceID {
+		return
+	}
+	switch msg.Kind {
+	case registryMsgWorldcast:
+		if len(msg.Data) < 2 {
+			return
+		}
+		for _, c := range r.local.channels {
+			c.Lock()
+			for _, session := range c.sessions {
+				data := make([]byte, len(msg.Data))
+				copy(data, msg.Data)
+				session.QueueSendNonBlocking(data)
+			}
+			c.Unlock()
+		}
+	case registryMsgMail:
+		for _, cid := range msg.CharIDs {
+			if session := r.local.FindSessionByCharID(cid); session != nil {
+				queueMailNotify(session, msg.SenderID, msg.SenderName)
+			}
+		}
+	case registryMsgDisconnect:
+		r.local.DisconnectUser(msg.CharIDs)
+	default:
+		r.logger.Warn("Unknown registry message kind", zap.String("kind", msg.Kind))
+	}
+}
+
+func (r *PostgresChannelRegistry) Worldcast(pkt mhfpacket.MHFPacket, ignoredSession *Session, ignoredChannel *Server) {
+	r.local.Worldcast(pkt, ignoredSession, ignoredChannel)
+
+	bf := byteframe.NewByteFrame()
+	bf.WriteUint16(uint16(pkt.Opcode()))
+	if err := pkt.Build(bf, &clientctx.ClientContext{RealClientMode: r.mode}); err != nil {
+		r.logger.Warn("Failed to build worldcast packet", zap.Error(err))
+		return
+	}
+	r.send(registryMessage{Kind: registryMsgWorldcast, Data: bf.Data()})
+}
+
+func (r *PostgresChannelRegistry) FindSessionByCharID(charID uint32) *Session {
+	return r.local.FindSessionByCharID(charID)
+}
+
+func (r *PostgresChannelRegistry) DisconnectUser(cids []uint32) {
+	r.local.DisconnectUser(cids)
+	if len(cids) > 0 {
+		r.send(registryMessage{Kind: registryMsgDisconnect, CharIDs: cids})
+	}
+}
+
+func (r *PostgresChannelRegistry) FindChannelForStage(stageSuffix string) string {
+	if gid := r.local.FindChannelForStage(stageSuffix); gid != "" {
+		return gid
+	}
+	var gid string
+	err := r.db.Get(&gid, `SELECT s.global_id FROM registry_stages s
+		JOIN registry_instances i ON i.instance_id = s.instance_id
+		WHERE s.instance_id <> $1 AND i.heartbeat >= now() - $2::float8 * interval '1 second' AND right(s.stage_id, length($3::text)) = $3::text
+		LIMIT 1`, r.instanceID, r.staleSeconds(), stageSuffix)
+	if err != nil {
+		return ""
+	}
+	return gid
+}
+
+// registrySessionRow is a row of registry_sessions.
+type registrySessionRow struct {
+	CharID      uint32 `db:"char_id"`
+	Name        string `db:"name"`
+	StageID     string `db:"stage_id"`
+	ServerIP    string `db:"server_ip"`
+	ServerPort  uint16 `db:"server_port"`
+	UserBinary3 []byte `db:"user_binary3"`
+}
+
+func (r *PostgresChannelRegistry) SearchSessions(predicate func(SessionSnapshot) bool, max int) []SessionSnapshot {
+	results := r.local.SearchSessions(predicate, max)
+	if len(results) >= max {
+		return results
+	}
+	var rows []registrySessionRow
+	err := r.db.Select(&rows, `SELECT s.char_id, s.name, s.stage_id, s.server_ip, s.server_port, s.user_binary3
+		FROM registry_sessions s JOIN registry_instances i ON i.instance_id = s.instance_id
+		WHERE s.instance_id <> $1 AND i.heartbeat >= now() - $2::float8 * interval '1 second'`, r.instanceID, r.staleSeconds())
+	if err != nil {
+		r.logger.Warn("Failed to search remote sessions", zap.Error(err))
+		return results
+	}
+	for _, row := range rows {
+		if len(results) >= max {
+			break
+		}
+		snap := SessionSnapshot{
+			CharID:      row.CharID,
+			Name:        row.Name,
+			StageID:     row.StageID,
+			ServerIP:    net.ParseIP(row.ServerIP).To4(),
+			ServerPort:  row.ServerPort,
+			UserBinary3: row.UserBinary3,
+		}
+		if predicate(snap) {
+			results = append(results, snap)
+		}
+	}
+	return results
+}
+
+// registryStageRow is a row of registry_stages.
+type registryStageRow struct {
+	GlobalID      string `db:"global_id"`
+	StageID       string `db:"stage_id"`
+	ServerIP      string `db:"server_ip"`
+	ServerPort    uint16 `db:"server_port"`
+	ClientCount   int    `db:"client_count"`
+	Reserved      int    `db:"reserved"`
+	QuestReserved int    `db:"quest_reserved"`
+	MaxPlayers    uint16 `db:"max_players"`
+	BinData0      []byte `db:"bin_data0"`
+	BinData1      []byte `db:"bin_data1"`
+	BinData3      []byte `db:"bin_data3"`
+}
+
+func (r *PostgresChannelRegistry) SearchStages(stagePrefix string, max int) []StageSnapshot {
+	results := r.local.SearchStages(stagePrefix, max)
+	if len(results) >= max {
+		return results
+	}
+	var rows []registryStageRow
+	err := r.db.Select(&rows, `SELECT s.global_id, s.stage_id, s.server_ip, s.server_port, s.client_count,
+		s.reserved, s.quest_reserved, s.max_players, s.bin_data0, s.bin_data1, s.bin_data3
+		FROM registry_stages s JOIN registry_instances i ON i.instance_id = s.instance_id
+		WHERE s.instance_id <> $1 AND i.heartbeat >= now() - $2::float8 * interval '1 second' AND left(s.stage_id, length($3::text)) = $3::text
+		LIMIT $4`, r.instanceID, r.staleSeconds(), stagePrefix, max-len(results))
+	if err != nil {
+		r.logger.Warn("Failed to search remote stages", zap.Error(err))
+		return results
+	}
+	for _, row := range rows {
+		results = append(results, StageSnapshot{
+			GlobalID:      row.GlobalID,
+			ServerIP:      net.ParseIP(row.ServerIP).To4(),
+			ServerPort:    row.ServerPort,
+			StageID:       row.StageID,
+			ClientCount:   row.ClientCount,
+			Reserved:      row.Reserved,
+			QuestReserved: row.QuestReserved,
+			MaxPlayers:    row.MaxPlayers,
+			RawBinData0:   row.BinData0,
+			RawBinData1:   row.BinData1,
+			RawBinData3:   row.BinData3,
+		})
+	}
+	return results
+}
+
+func (r *PostgresChannelRegistry) NotifyMailToCharID(charID uint32, sender *Session, mail *Mail) {
+	if session := r.local.FindSessionByCharID(charID); session != nil {
+		SendMailNotification(sender, mail, session)
+		return
+	}
+	r.send(registryMessage{
+		Kind:       registryMsgMail,
+		CharIDs:    []uint32{charID},
+		SenderID:   mail.SenderID,
+		SenderName: mailSenderName(sender, mail),
+	})
+}
@@ -0,0 +1,257 @@
+package channelserver
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"erupe-ce/network"
+	"erupe-ce/network/mhfpacket"
+
+	"go.uber.org/zap"
+)
+
+func newTestPostgresRegistry(channels []*Server, instanceID string) (*PostgresChannelRegistry, *[]string) {
+	reg := NewPostgresChannelRegistry(channels, &PostgresRegistryConfig{
+		Logger:     zap.NewNop(),
+		InstanceID: instanceID,
+	})
+	var sent []string
+	reg.notify = func(payload string) error {
+		sent = append(sent, payload)
+		return nil
+	}
+	return reg, &sent
+}
+
+func addRegistryTestSession(ch *Server, charID uint32, name string) (*Session, *mockConn) {
+	conn := &mockConn{}
+	sess := createTestSessionForServer(ch, conn, charID, name)
+	ch.Lock()
+	ch.sessions[conn] = sess
+	ch.Unlock()
+	return sess, conn
+}
+
+func TestPostgresRegistryDefaults(t *testing.T) {
+	reg, _ := newTestPostgresRegistry(nil, "")
+	if reg.InstanceID() == "" {
+		t.Error("InstanceID should default to hostname-pid")
+	}
+	if reg.interval != 5*time.Second {
+		t.Errorf("interval = %v, want 5s", reg.interval)
+	}
+}
+
+func TestPostgresRegistryWorldcastRelays(t *testing.T) {
+	channels := createTestChannels(1)
+	reg, sent := newTestPostgresRegistry(channels, "a")
+	sess, _ := addRegistryTestSession(channels[0], 1, "Local")
+
+	reg.Worldcast(&mhfpacket.MsgSysCastedBinary{MessageType: BinaryMessageTypeChat, RawDataPayload: []byte{1, 2, 3}}, nil, nil)
+
+	if len(sess.sendPackets) != 1 {
+		t.Errorf("local session got %d packets, want 1", len(sess.sendPackets))
+	}
+	if len(*sent) != 1 {
+		t.Fatalf("relayed %d messages, want 1", len(*sent))
+	}
+	var msg registryMessage
+	if err := json.Unmarshal([]byte((*sent)[0]), &msg); err != nil {
+		t.Fatalf("relayed payload is not JSON: %v", err)
+	}
+	if msg.Origin != "a" || msg.Kind != registryMsgWorldcast {
+		t.Errorf("message = %+v, want origin a, kind worldcast", msg)
+	}
+	if op := binary.BigEndian.Uint16(msg.Data); op != uint16(network.MSG_SYS_CASTED_BINARY) {
+		t.Errorf("relayed opcode = %#x, want MSG_SYS_CASTED_BINARY", op)
+	}
+}
+
+func TestPostgresRegistryWorldcastTooLarge(t *testing.T) {
+	reg, sent := newTestPostgresRegistry(createTestChannels(1), "a")
+
+	reg.Worldcast(&mhfpacket.MsgSysCastedBinary{RawDataPayload: make([]byte, registryMaxPayload)}, nil, nil)
+
+	if len(*sent) != 0 {
+		t.Errorf("oversized worldcast should not be relayed, got %d messages", len(*sent))
+	}
+}
+
+func TestPostgresRegistryHandleWorldcast(t *testing.T) {
+	channels := createTestChannels(2)
+	reg, _ := newTestPostgresRegistry(channels, "b")
+	s1, _ := addRegistryTestSession(channels[0], 1, "One")
+	s2, _ := addRegistryTestSession(channels[1], 2, "Two")
+
+	payload, _ := json.Marshal(registryMessage{Origin: "a", Kind: registryMsgWorldcast, Data: []byte{0x00, 0x01, 0xAA}})
+	reg.handleNotification(string(payload))
+
+	for _, s := range []*Session{s1, s2} {
+		if len(s.sendPackets) != 1 {
+			t.Fatalf("session %d got %d packets, want 1", s.charID, len(s.sendPackets))
+		}
+		p := <-s.sendPackets
+		if string(p.data) != string([]byte{0x00, 0x01, 0xAA}) {
+			t.Errorf("session %d got %x", s.charID, p.data)
+		}
+	}
+}
+
+func TestPostgresRegistryIgnoresOwnMessages(t *testing.T) {
+	channels := createTestChannels(1)
+	reg, _ := newTestPostgresRegistry(channels, "a")
+	sess, conn := addRegistryTestSession(channels[0], 1, "Self")
+
+	for _, msg := range []registryMessage{
+		{Origin: "a", Kind: registryMsgWorldcast, Data: []byte{0, 1}},
+		{Origin: "a", Kind: registryMsgDisconnect, CharIDs: []uint32{1}},
+	} {
+		payload, _ := json.Marshal(msg)
+		reg.handleNotification(string(payload))
+	}
+
+	if len(sess.sendPackets) != 0 {
+		t.Error("own worldcast should not be delivered twice")
+	}
+	if conn.WasClosed() {
+		t.Error("own disconnect should not be applied twice")
+	}
+}
+
+func TestPostgresRegistryHandleDisconnect(t *testing.T) {
+	channels := createTestChannels(1)
+	reg, _ := newTestPostgresRegistry(channels, "b")
+	_, target := addRegistryTestSession(channels[0], 7, "Target")
+	_, other := addRegistryTestSession(channels[0], 8, "Other")
+
+	payload, _ := json.Marshal(registryMessage{Origin: "a", Kind: registryMsgDisconnect, CharIDs: []uint32{7}})
+	reg.handleNotification(string(payload))
+
+	if !target.WasClosed() {
+		t.Error("relayed disconnect should close the target connection")
+	}
+	if other.WasClosed() {
+		t.Error("relayed disconnect should not close other connections")
+	}
+}
+
+func TestPostgresRegistryDisconnectRelays(t *testing.T) {
+	channels := createTestChannels(1)
+	reg, sent := newTestPostgresRegistry(channels, "a")
+	_, conn := addRegistryTestSession(channels[0], 3, "Local")
+
+	reg.DisconnectUser([]uint32{3, 4})
+
+	if !conn.WasClosed() {
+		t.Error("local session should be closed")
+	}
+	if len(*sent) != 1 || !strings.Contains((*sent)[0], `"k":"disconnect"`) {
+		t.Errorf("sent = %v, want one disconnect message", *sent)
+	}
+
+	reg.DisconnectUser(nil)
+	if len(*sent) != 1 {
+		t.Error("empty disconnect should not be relayed")
+	}
+}
+
+func TestPostgresRegistryHandleMail(t *testing.T) {
+	channels := createTestChannels(1)
+	reg, _ := newTestPostgresRegistry(channels, "b")
+	sess, _ := addRegistryTestSession(channels[0], 5, "Recipient")
+
+	payload, _ := json.Marshal(registryMessage{Origin: "a", Kind: registryMsgMail, CharIDs: []uint32{5, 6}, SenderID: 9, SenderName: "Sender"})
+	reg.handleNotification(string(payload))
+
+	if len(sess.sendPackets) != 1 {
+		t.Fatalf("recipient got %d packets, want 1", len(sess.sendPackets))
+	}
+	p := <-sess.sendPackets
+	if op := binary.BigEndian.Uint16(p.data); op != uint16(network.MSG_SYS_CASTED_BINARY) {
+		t.Errorf("opcode = %#x, want MSG_SYS_CASTED_BINARY", op)
+	}
+}
+
+func TestPostgresRegistryNotifyMailWithoutSession(t *testing.T) {
+	channels := createTestChannels(1)
+	reg, sent := newTestPostgresRegistry(channels, "a")
+	sess, _ := addRegistryTestSession(channels[0], 5, "Recipient")
+	mail := &Mail{SenderID: 9, SenderName: "Admin"}
+
+	// Broadcasts notify without a sender session; the name comes from the mail.
+	reg.NotifyMailToCharID(5, nil, mail)
+	reg.NotifyMailToCharID(6, nil, mail)
+
+	if len(sess.sendPackets) != 1 {
+		t.Errorf("local recipient got %d packets, want 1", len(sess.sendPackets))
+	}
+	if len(*sent) != 1 || !strings.Contains((*sent)[0], `"n":"Admin"`) {
+		t.Errorf("sent = %v, want one mail message naming the sender", *sent)
+	}
+}
+
+func TestPostgresRegistryMalformedMessage(t *testing.T) {
+	reg, _ := newTestPostgresRegistry(createTestChannels(1), "b")
+	// Must not panic.
+	reg.handleNotification("not json")
+	reg.handleNotification(`{"o":"a","k":"unknown"}`)
+	reg.handleNotification(`{"o":"a","k":"worldcast"}`)
+}
+
+func TestPostgresRegistrySharedSnapshots(t *testing.T) {
+	db := SetupTestDB(t)
+	defer TeardownTestDB(t, db)
+
+	chA := createTestChannels(1)
+	chA[0].GlobalID = "0101"
+	chB := createTestChannels(1)
+	chB[0].GlobalID = "0201"
+	chB[0].Port = 54010
+
+	regA, _ := newTestPostgresRegistry(chA, "host-a")
+	regA.db = db
+	regB, _ := newTestPostgresRegistry(chB, "host-b")
+	regB.db = db
+
+	addRegistryTestSession(chA[0], 100, "Alice")
+	sessB, _ := addRegistryTestSession(chB[0], 200, "Bob")
+	sessB.stage = NewStage("sl2Ls210p0a0u200")
+	chB[0].stages.Store("sl2Ls210p0a0u200", sessB.stage)
+
+	if err := regA.Publish(); err != nil {
+		t.Fatalf("Publish A: %v", err)
+	}
+	if err := regB.Publish(); err != nil {
+		t.Fatalf("Publish B: %v", err)
+	}
+
+	results := regA.SearchSessions(func(s SessionSnapshot) bool { return true }, 10)
+	if len(results) != 2 {
+		t.Fatalf("SearchSessions from A returned %d results, want 2", len(results))
+	}
+	found := false
+	for _, r := range results {
+		if r.CharID == 200 && r.Name == "Bob" && r.ServerPort == 54010 && r.StageID == "sl2Ls210p0a0u200" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("remote session not found in %+v", results)
+	}
+
+	stages := regA.SearchStages("sl2Ls210", 10)
+	if len(stages) != 1 || stages[0].GlobalID != "0201" {
+		t.Errorf("SearchStages from A = %+v, want remote stage on 0201", stages)
+	}
+	if gid := regA.FindChannelForStage("u200"); gid != "0201" {
+		t.Errorf("FindChannelForStage(u200) = %q, want 0201", gid)
+	}
+
+	regB.Close()
+	if results := regA.SearchSessions(func(s SessionSnapshot) bool { return true }, 10); len(results) != 1 {
+		t.Errorf("after B closed, SearchSessions returned %d results, want 1", len(results))
+	}
+}
@@ -27,6 +27,7 @@ func isDivaDefenseQuestType(questType uint8) bool {
 
 // Event quest binary frame offsets
 const (
+	eventQuestUnlockedOffset = 11 // Special tool quests: whether the campaign unlocked it
 	questFrameTimeFlagOffset = 25
 	questFrameVariant3Offset = 175
 )
@@ -7,6 +7,9 @@ const (
 	raviRegisterGeneral = uint32(0x60000)
 )
 
+// raviRegisterSize is the number of values in each Raviente register.
+const raviRegisterSize = 30
+
 // Raviente semaphore constants
 const (
 	raviSemaphoreStride = 0x10000     // ID spacing between hs_l0* semaphores
@@ -0,0 +1,80 @@
+package channelserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+// dailyMissionsFile is the name of the daily mission master list in BinPath.
+const dailyMissionsFile = "daily_missions.json"
+
+// DailyMission is one entry of the daily mission board. Type and Target are
+// passed through to the client unchanged; the server only interprets
+// Quantity (the progress needed to complete the mission) and the reward.
+//
+//	{
+//	  "missions": [
+//	    {"id": 1, "type": 1, "target": 11, "quantity": 3, "reward_item": 1, "reward_quantity": 5}
+//	  ]
+//	}
+type DailyMission struct {
+	ID             uint32 `json:"id"`
+	Type           uint8  `json:"type"`
+	Target         uint32 `json:"target"`
+	Quantity       uint32 `json:"quantity"`
+	RewardItem     uint16 `json:"reward_item"`
+	RewardQuantity uint16 `json:"reward_quantity"`
+}
+
+// DailyMissionConfig is the top-level JSON structure for daily_missions.json.
+type DailyMissionConfig struct {
+	Missions []DailyMission `json:"missions"`
+}
+
+// validateDailyMissions checks that mission IDs are non-zero and unique and
+// that every mission can be completed.
+func validateDailyMissions(missions []DailyMission) error {
+	seen := make(map[uint32]bool, len(missions))
+	for i, m := range missions {
+		if m.ID == 0 {
+			return fmt.Errorf("mission %d: id must be non-zero", i)
+		}
+		if seen[m.ID] {
+			return fmt.Errorf("mission %d: duplicate id %d", i, m.ID)
+		}
+		seen[m.ID] = true
+		if m.Quantity == 0 {
+			return fmt.Errorf("mission %d (id %d): quantity must be non-zero", i, m.ID)
+		}
+	}
+	return nil
+}
+
+// loadDailyMissions reads daily_missions.json from binPath. A missing or
+// invalid file leaves the daily mission board empty.
+func loadDailyMissions(binPath string, logger *zap.Logger) []DailyMission {
+	path := filepath.Join(binPath, dailyMissionsFile)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil // file absent — not an error
+	}
+
+	var cfg DailyMissionConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		logger.Error("daily_missions.json: JSON parse error",
+			zap.String("path", path), zap.Error(err))
+		return nil
+	}
+	if err := validateDailyMissions(cfg.Missions); err != nil {
+		logger.Error("daily_missions.json: validation failed",
+			zap.String("path", path), zap.Error(err))
+		return nil
+	}
+
+	logger.Info("Loaded daily missions", zap.Int("missions", len(cfg.Missions)))
+	return cfg.Missions
+}
@@ -0,0 +1,54 @@
+package channelserver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestLoadDailyMissions(t *testing.T) {
+	dir := t.TempDir()
+	json := `{"missions": [
+		{"id": 1, "type": 1, "target": 11, "quantity": 3, "reward_item": 7, "reward_quantity": 2},
+		{"id": 2, "type": 2, "quantity": 1}
+	]}`
+	if err := os.WriteFile(filepath.Join(dir, dailyMissionsFile), []byte(json), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	missions := loadDailyMissions(dir, zap.NewNop())
+	if len(missions) != 2 {
+		t.Fatalf("loaded %d missions, want 2", len(missions))
+	}
+	if m := missions[0]; m.ID != 1 || m.RewardItem != 7 || m.RewardQuantity != 2 {
+		t.Errorf("mission 1 = %+v, want reward 7x2", m)
+	}
+}
+
+func TestLoadDailyMissions_Missing(t *testing.T) {
+	if missions := loadDailyMissions(t.TempDir(), zap.NewNop()); missions != nil {
+		t.Errorf("missing file should load no missions, got %d", len(missions))
+	}
+}
+
+func TestValidateDailyMissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		missions []DailyMission
+		wantErr  bool
+	}{
+		{"valid", []DailyMission{{ID: 1, Quantity: 1}, {ID: 2, Quantity: 5}}, false},
+		{"zero id", []DailyMission{{ID: 0, Quantity: 1}}, true},
+		{"duplicate id", []DailyMission{{ID: 1, Quantity: 1}, {ID: 1, Quantity: 2}}, true},
+		{"zero quantity", []DailyMission{{ID: 1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateDailyMissions(tt.missions); (err != nil) != tt.wantErr {
+				t.Errorf("validateDailyMissions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -68,6 +68,20 @@ type GuildLeader struct {
 	LeaderName   string `db:"leader_name"`
 }
 
+// GuildAuditEntry is one administrative change to a guild, as recorded in
+// the guild_audit table. OldValue and NewValue hold the changed setting
+// formatted as text.
+type GuildAuditEntry struct {
+	ID          uint32    `db:"id" json:"id"`
+	GuildID     uint32    `db:"guild_id" json:"guildId"`
+	ActorSource string    `db:"actor_source" json:"actorSource"`
+	ActorID     uint32    `db:"actor_id" json:"actorId"`
+	Action      string    `db:"action" json:"action"`
+	OldValue    string    `db:"old_value" json:"oldValue"`
+	NewValue    string    `db:"new_value" json:"newValue"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+}
+
 // GuildIconPart represents one graphical part of a guild icon.
 type GuildIconPart struct {
 	Index    uint16
@@ -112,35 +126,51 @@ func (gi *GuildIcon) Value() (valuer driver.Value, err error) {
 	return json.Marshal(gi)
 }
 
-func (g *Guild) Rank(mode cfg.Mode) uint16 {
-	rpMap := []uint32{
-		24, 48, 96, 144, 192, 240, 288, 360, 432,
-		504, 600, 696, 792, 888, 984, 1080, 1200,
-	}
+// guildRankThresholds returns the rank RP needed to reach each rank from 1
+// upwards under mode.
+func guildRankThresholds(mode cfg.Mode) []uint32 {
 	if mode <= cfg.Z2 {
-		rpMap = []uint32{
+		return []uint32{
 			3500, 6000, 8500, 11000, 13500, 16000, 20000, 24000, 28000,
 			33000, 38000, 43000, 48000, 55000, 70000, 90000, 120000,
 		}
 	}
-	for i, u := range rpMap {
-		if g.RankRP < u {
-			if mode <= cfg.S6 && i >= 12 {
-				return 12
-			} else if mode <= cfg.F5 && i >= 13 {
-				return 13
-			} else if mode <= cfg.G32 && i >= 14 {
-				return 14
-			}
-			return uint16(i)
-		}
+	return []uint32{
+		24, 48, 96, 144, 192, 240, 288, 360, 432,
+		504, 600, 696, 792, 888, 984, 1080, 1200,
 	}
-	if mode <= cfg.S6 {
+}
+
+// maxGuildRank returns the highest guild rank the client for mode displays.
+func maxGuildRank(mode cfg.Mode) uint16 {
+	switch {
+	case mode <= cfg.S6:
 		return 12
-	} else if mode <= cfg.F5 {
+	case mode <= cfg.F5:
 		return 13
-	} else if mode <= cfg.G32 {
+	case mode <= cfg.G32:
 		return 14
 	}
 	return 17
 }
+
+// GuildRankRP returns the minimum rank RP of the given rank under mode, and
+// false if mode's client has no such rank.
+func GuildRankRP(rank uint16, mode cfg.Mode) (uint32, bool) {
+	if rank > maxGuildRank(mode) {
+		return 0, false
+	}
+	if rank == 0 {
+		return 0, true
+	}
+	return guildRankThresholds(mode)[rank-1], true
+}
+
+func (g *Guild) Rank(mode cfg.Mode) uint16 {
+	for i, u := range guildRankThresholds(mode) {
+		if g.RankRP < u {
+			return min(uint16(i), maxGuildRank(mode))
+		}
+	}
+	return maxGuildRank(mode)
+}
@@ -181,27 +181,10 @@ func (save *CharacterSaveData) Save(s *Session) error {
 		save.compSave = save.decompSave
 	}
 
-	// Compute integrity hash over the decompressed save.
-	hash := sha256.Sum256(save.decompSave)
-
-	// Build the atomic save params — character data, house data, hash, and
-	// optionally a backup snapshot, all in one transaction.
-	params := SaveAtomicParams{
-		CharID:        save.CharID,
-		CompSave:      save.compSave,
-		Hash:          hash[:],
-		HR:            save.HR,
-		GR:            save.GR,
-		IsFemale:      save.Gender,
-		WeaponType:    save.WeaponType,
-		WeaponID:      save.WeaponID,
-		HouseTier:     save.HouseTier,
-		HouseData:     save.HouseData,
-		BookshelfData: save.BookshelfData,
-		GalleryData:   save.GalleryData,
-		ToreData:      save.ToreData,
-		GardenData:    save.GardenData,
-	}
+	// Build the atomic save params — character data, house data, integrity
+	// hash over the decompressed save, and optionally a backup snapshot, all
+	// in one transaction.
+	params := save.atomicParams()
 
 	// Time-gated rotating backup: include the previous compressed savedata
 	// in the transaction if enough time has elapsed since the last backup.
@@ -352,8 +352,14 @@ func parseChatCommand(s *Session, command string) {
 				if s.server.getRaviSemaphore() != nil {
 					switch args[1] {
 					case "start":
-						if s.server.raviente.register[1] == 0 {
-							s.server.raviente.register[1] = s.server.raviente.register[3]
+						if !s.isOp() && !RavienteWindowOpen(s.server.erupeConfig.Raviente.Windows, time.Now()) {
+							sendServerChatMessage(s, s.I18n().commands.ravi.start.closed)
+							break
+						}
+						s.server.raviente.Lock()
+						started := s.server.raviente.startLocked()
+						s.server.raviente.Unlock()
+						if started {
 							sendServerChatMessage(s, s.I18n().commands.ravi.start.success)
 							s.notifyRavi()
 						} else {
@@ -28,6 +28,10 @@ const (
 func handleMsgMhfSavedata(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfSavedata)
 
+	start := time.Now()
+	saved := false
+	defer func() { s.server.metrics.saveDone(s.server, time.Since(start), saved) }()
+
 	// Serialize saves for the same character to prevent concurrent operations
 	// from racing and defeating corruption detection.
 	unlock := s.server.charSaveLocks.Lock(s.charID)
@@ -140,6 +144,7 @@ func handleMsgMhfSavedata(s *Session, p mhfpacket.MHFPacket) {
 	if err := s.server.charRepo.SaveString(s.charID, "name", characterSaveData.Name); err != nil {
 		s.logger.Error("Failed to update character name in db", zap.Error(err))
 	}
+	saved = true
 	doAckSimpleSucceed(s, pkt.AckHandle, make([]byte, 4))
 }
 
@@ -534,6 +534,8 @@ func TestHandleMsgMhfGetGachaPlayHistory(t *testing.T) {
 
 func TestHandleMsgMhfPlayFreeGacha(t *testing.T) {
 	server := createMockServer()
+	server.gachaRepo = &mockGachaRepo{}
+	ensureGachaService(server)
 	session := createMockSession(1, server)
 
 	handleMsgMhfPlayFreeGacha(session, &mhfpacket.MsgMhfPlayFreeGacha{
@@ -101,10 +101,10 @@ func handleMsgMhfEnumerateDistItem(s *Session, p mhfpacket.MHFPacket) {
 
 // DistributionItem represents a single item in a distribution.
 type DistributionItem struct {
-	ItemType uint8  `db:"item_type"`
-	ID       uint32 `db:"id"`
-	ItemID   uint32 `db:"item_id"`
-	Quantity uint32 `db:"quantity"`
+	ItemType uint8  `db:"item_type" json:"itemType"`
+	ID       uint32 `db:"id" json:"id,omitempty"`
+	ItemID   uint32 `db:"item_id" json:"itemId"`
+	Quantity uint32 `db:"quantity" json:"quantity"`
 }
 
 func handleMsgMhfApplyDistItem(s *Session, p mhfpacket.MHFPacket) {
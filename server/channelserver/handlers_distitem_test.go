@@ -22,6 +22,8 @@ type mockDistRepo struct {
 	recordedDist  uint32
 	recordedChar  uint32
 	recordErr     error
+	campaigns     []DistributionCampaign
+	eligible      []DistributionEligibleChar
 }
 
 func (m *mockDistRepo) List(_ uint32, _ uint8) ([]Distribution, error) {
@@ -48,6 +50,56 @@ func (m *mockDistRepo) GetDescription(_ uint32) (string, error) {
 	return m.description, m.descErr
 }
 
+func (m *mockDistRepo) ListCampaigns() ([]DistributionCampaign, error) {
+	return m.campaigns, nil
+}
+
+func (m *mockDistRepo) GetCampaign(id uint32) (*DistributionCampaign, error) {
+	for i := range m.campaigns {
+		if m.campaigns[i].ID == id {
+			c := m.campaigns[i]
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDistRepo) CreateCampaign(c DistributionCampaign) (uint32, error) {
+	c.ID = uint32(len(m.campaigns) + 1)
+	m.campaigns = append(m.campaigns, c)
+	return c.ID, nil
+}
+
+func (m *mockDistRepo) UpdateCampaign(c DistributionCampaign) (bool, error) {
+	for i := range m.campaigns {
+		if m.campaigns[i].ID == c.ID {
+			m.campaigns[i] = c
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockDistRepo) ExpireCampaign(id uint32, at time.Time) (bool, error) {
+	for i := range m.campaigns {
+		if m.campaigns[i].ID == id {
+			if d := m.campaigns[i].Deadline; d == nil || d.After(at) {
+				m.campaigns[i].Deadline = &at
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockDistRepo) Eligible(id uint32, limit int) (int, []DistributionEligibleChar, error) {
+	chars := m.eligible
+	if len(chars) > limit {
+		chars = chars[:limit]
+	}
+	return len(m.eligible), chars, nil
+}
+
 func TestHandleMsgMhfEnumerateDistItem_Empty(t *testing.T) {
 	server := createMockServer()
 	server.erupeConfig.RealClientMode = cfg.S6
@@ -192,9 +192,8 @@ func handleMsgMhfAddUdPoint(s *Session, p mhfpacket.MHFPacket) {
 	// Find the current diva event to associate points with.
 	eventID := uint32(0)
 	if s.server.divaRepo != nil {
-		events, err := s.server.divaRepo.GetEvents()
-		if err == nil && len(events) > 0 {
-			eventID = events[len(events)-1].ID
+		if event, ok := currentDivaEvent(s); ok {
+			eventID = event.ID
 		}
 	}
 
@@ -444,14 +443,65 @@ func handleMsgMhfGetUdMonsterPoint(s *Session, p mhfpacket.MHFPacket) {
 	doAckBufSucceed(s, pkt.AckHandle, resp.Data())
 }
 
+// Diva Defense present lists and the AcquireUdItem reward types backed by them.
+const (
+	divaPresentsDaily = "daily"
+	divaPresentsNorma = "norma"
+
+	udRewardDaily    = 0
+	udRewardPersonal = 1
+
+	// divaPresentDistributionType is the distribution type claimed presents
+	// are delivered as, the same gift box the other event rewards use.
+	divaPresentDistributionType = 1
+	divaPresentEventName        = "Diva Defense Present"
+)
+
+// divaRankingSize is the number of entries sent by GetUdRanking.
+const divaRankingSize = 100
+
+// currentDivaEvent returns the most recent diva event, or false if none
+// has been scheduled.
+func currentDivaEvent(s *Session) (DivaEvent, bool) {
+	events, err := s.server.divaRepo.GetEvents()
+	if err != nil {
+		s.logger.Error("Failed to query diva schedule", zap.Error(err))
+		return DivaEvent{}, false
+	}
+	if len(events) == 0 {
+		return DivaEvent{}, false
+	}
+	return events[len(events)-1], true
+}
+
+// writeDivaPresent writes the 14 bytes shared by daily and norma entries.
+// The padding at +6 and +10 is not read by the client.
+func writeDivaPresent(bf *byteframe.ByteFrame, p DivaPresent) {
+	bf.WriteUint8(p.RankType)
+	bf.WriteUint16(p.RankFrom)
+	bf.WriteUint16(p.RankTo)
+	bf.WriteUint8(p.ItemType)
+	bf.WriteUint16(0)
+	bf.WriteUint16(p.ItemID)
+	bf.WriteUint16(0)
+	bf.WriteUint16(p.Quantity)
+}
+
 func handleMsgMhfGetUdDailyPresentList(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfGetUdDailyPresentList)
 	// DailyPresentList: u16 count + count × 15-byte entries.
 	// Entry: u8 rank_type, u16 rank_from, u16 rank_to, u8 item_type,
 	//        u16 _pad0(skip), u16 item_id, u16 _pad1(skip), u16 quantity, u8 unk.
-	// Padding at +6 and +10 is NOT read by the client.
+	presents, err := s.server.divaRepo.GetPresents(divaPresentsDaily)
+	if err != nil {
+		s.logger.Error("Failed to get diva daily presents", zap.Error(err))
+	}
 	bf := byteframe.NewByteFrame()
-	bf.WriteUint16(0) // count = 0 (no entries configured)
+	bf.WriteUint16(uint16(len(presents)))
+	for _, present := range presents {
+		writeDivaPresent(bf, present)
+		bf.WriteUint8(0) // unk
+	}
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
@@ -461,25 +511,180 @@ func handleMsgMhfGetUdNormaPresentList(s *Session, p mhfpacket.MHFPacket) {
 	// Same layout as DailyPresent (+0x00..+0x0D), plus:
 	//   +0x0E u32 points_required (norma threshold)
 	//   +0x12 u8  bead_type (BeadType that unlocks this tier)
-	// Padding at +6 and +10 NOT read.
+	presents, err := s.server.divaRepo.GetPresents(divaPresentsNorma)
+	if err != nil {
+		s.logger.Error("Failed to get diva norma presents", zap.Error(err))
+	}
 	bf := byteframe.NewByteFrame()
-	bf.WriteUint16(0) // count = 0 (no entries configured)
+	bf.WriteUint16(uint16(len(presents)))
+	for _, present := range presents {
+		writeDivaPresent(bf, present)
+		bf.WriteUint32(present.PointsReq)
+		bf.WriteUint8(present.BeadType)
+	}
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
+// divaPresentEligible reports whether a present's rank bracket and point
+// threshold admit the character. Rank type 0 checks the personal rank and
+// any other value the guild rank; a bracket starting at 0 admits everyone.
+func divaPresentEligible(present DivaPresent, personal, guild DivaRankEntry) bool {
+	if personal.Points < uint64(present.PointsReq) {
+		return false
+	}
+	if present.RankFrom == 0 {
+		return true
+	}
+	rank := personal.Rank
+	if present.RankType != 0 {
+		rank = guild.Rank
+	}
+	return rank != 0 && rank >= uint32(present.RankFrom) && rank <= uint32(present.RankTo)
+}
+
+// divaRanks returns the character's personal and guild places in an event.
+func divaRanks(s *Session, eventID uint32) (DivaRankEntry, DivaRankEntry, error) {
+	personal, err := s.server.divaRepo.GetCharacterRank(s.charID, eventID)
+	if err != nil {
+		return DivaRankEntry{}, DivaRankEntry{}, err
+	}
+	var guild DivaRankEntry
+	member, err := s.server.guildRepo.GetCharacterMembership(s.charID)
+	if err != nil {
+		return DivaRankEntry{}, DivaRankEntry{}, err
+	}
+	if member != nil && !member.IsApplicant {
+		if guild, err = s.server.divaRepo.GetGuildRank(member.GuildID, eventID); err != nil {
+			return DivaRankEntry{}, DivaRankEntry{}, err
+		}
+	}
+	return personal, guild, nil
+}
+
 func handleMsgMhfAcquireUdItem(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfAcquireUdItem)
+
+	// Only daily and personal (norma) presents have tables; the ranking and
+	// achievement reward types are acknowledged without being tracked.
+	var list string
+	switch pkt.RewardType {
+	case udRewardDaily:
+		list = divaPresentsDaily
+	case udRewardPersonal:
+		list = divaPresentsNorma
+	default:
+		doAckSimpleSucceed(s, pkt.AckHandle, []byte{0x00, 0x00, 0x00, 0x00})
+		return
+	}
+
+	event, ok := currentDivaEvent(s)
+	if !ok {
+		doAckSimpleFail(s, pkt.AckHandle, []byte{0x00, 0x00, 0x00, 0x00})
+		return
+	}
+	presents, err := s.server.divaRepo.GetPresents(list)
+	if err != nil {
+		s.logger.Error("Failed to get diva presents", zap.Error(err))
+		doAckSimpleFail(s, pkt.AckHandle, []byte{0x00, 0x00, 0x00, 0x00})
+		return
+	}
+	personal, guild, err := divaRanks(s, event.ID)
+	if err != nil {
+		s.logger.Error("Failed to get diva ranks", zap.Error(err))
+		doAckSimpleFail(s, pkt.AckHandle, []byte{0x00, 0x00, 0x00, 0x00})
+		return
+	}
+
+	// The request lists the item IDs being acquired; every eligible present
+	// of the list carrying one of them is claimed.
+	bf := byteframe.NewByteFrameFromBytes(pkt.Unk3)
+	requested := make(map[uint16]bool, pkt.ItemIDCount)
+	for i := 0; i < int(pkt.ItemIDCount); i++ {
+		requested[uint16(bf.ReadUint32())] = true
+	}
+	var eligible []DivaPresent
+	for _, present := range presents {
+		if requested[present.ItemID] && divaPresentEligible(present, personal, guild) {
+			eligible = append(eligible, present)
+		}
+	}
+
+	// Daily presents can be claimed again each day, norma presents once per event.
+	period := TimeMidnight()
+	if list == divaPresentsNorma {
+		period = time.Unix(int64(event.StartTime), 0)
+	}
+	var claimed []uint32
+	if len(eligible) > 0 {
+		if claimed, err = s.server.divaRepo.ClaimPresents(s.charID, eligible, period, divaPresentEventName); err != nil {
+			s.logger.Error("Failed to claim diva presents", zap.Error(err))
+		}
+	}
+	if len(claimed) == 0 {
+		doAckSimpleFail(s, pkt.AckHandle, []byte{0x00, 0x00, 0x00, 0x00})
+		return
+	}
+	s.logger.Info("Diva presents acquired",
+		zap.Uint32("charID", s.charID), zap.String("list", list), zap.Int("count", len(claimed)))
 	doAckSimpleSucceed(s, pkt.AckHandle, []byte{0x00, 0x00, 0x00, 0x00})
 }
 
+// writeDivaRankName writes the 25-byte name field used by the ranking responses.
+func writeDivaRankName(bf *byteframe.ByteFrame, name string) {
+	bf.WriteBytes(stringsupport.PaddedString(name, 25, true))
+}
+
 func handleMsgMhfGetUdRanking(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfGetUdRanking)
-	doAckSimpleSucceed(s, pkt.AckHandle, []byte{0x00, 0x00, 0x00, 0x00})
+	// Response: u32 count + count × (u32 rank, u32 points, char[25] name).
+	// Unk0 0 selects the personal ranking, anything else the guild ranking.
+	var entries []DivaRankEntry
+	if event, ok := currentDivaEvent(s); ok {
+		var err error
+		if pkt.Unk0 == 0 {
+			entries, err = s.server.divaRepo.GetPersonalRanking(event.ID, divaRankingSize)
+		} else {
+			entries, err = s.server.divaRepo.GetGuildRanking(event.ID, divaRankingSize)
+		}
+		if err != nil {
+			s.logger.Error("Failed to get diva ranking", zap.Error(err))
+		}
+	}
+	bf := byteframe.NewByteFrame()
+	bf.WriteUint32(uint32(len(entries)))
+	for _, e := range entries {
+		bf.WriteUint32(e.Rank)
+		bf.WriteUint32(uint32(e.Points))
+		writeDivaRankName(bf, e.Name)
+	}
+	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
 func handleMsgMhfGetUdMyRanking(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfGetUdMyRanking)
-	// Temporary canned response
-	data, _ := hex.DecodeString("00000515000005150000CEB4000003CE000003CE0000CEB44D49444E494748542D414E47454C0000000000000000000000")
-	doAckBufSucceed(s, pkt.AckHandle, data)
+	if !s.server.erupeConfig.GameplayOptions.EnableDivaMyRanking {
+		// Temporary canned response
+		data, _ := hex.DecodeString("00000515000005150000CEB4000003CE000003CE0000CEB44D49444E494748542D414E47454C0000000000000000000000")
+		doAckBufSucceed(s, pkt.AckHandle, data)
+		return
+	}
+	// 49 bytes; the layout is a guess that no capture has confirmed:
+	//   u32 personal rank, u32 personal rank, u32 personal points,
+	//   u32 guild rank, u32 guild rank, u32 guild points, char[25] guild name.
+	var personal, guild DivaRankEntry
+	if event, ok := currentDivaEvent(s); ok {
+		var err error
+		if personal, guild, err = divaRanks(s, event.ID); err != nil {
+			s.logger.Error("Failed to get diva ranks", zap.Error(err))
+		}
+	}
+	bf := byteframe.NewByteFrame()
+	bf.WriteUint32(personal.Rank)
+	bf.WriteUint32(personal.Rank)
+	bf.WriteUint32(uint32(personal.Points))
+	bf.WriteUint32(guild.Rank)
+	bf.WriteUint32(guild.Rank)
+	bf.WriteUint32(uint32(guild.Points))
+	writeDivaRankName(bf, guild.Name)
+	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
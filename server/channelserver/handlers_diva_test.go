@@ -3,6 +3,7 @@ package channelserver
 import (
 	"testing"
 
+	"erupe-ce/common/byteframe"
 	cfg "erupe-ce/config"
 	"erupe-ce/network/mhfpacket"
 	"time"
@@ -498,3 +499,224 @@ func TestHandleMsgMhfGetUdSchedule_NoEvents(t *testing.T) {
 	handleMsgMhfGetUdSchedule(s, pkt)
 	<-s.sendPackets
 }
+
+func newDivaPresentSession(repo *mockDivaRepo) *Session {
+	srv := createMockServer()
+	srv.divaRepo = repo
+	srv.guildRepo = &mockGuildRepo{}
+	s := createMockSession(1, srv)
+	s.charID = 100
+	return s
+}
+
+func TestHandleMsgMhfGetUdDailyPresentList_Entries(t *testing.T) {
+	s := newDivaPresentSession(&mockDivaRepo{presents: map[string][]DivaPresent{
+		divaPresentsDaily: {
+			{ID: 1, RankFrom: 1, RankTo: 10, ItemType: 26, ItemID: 1, Quantity: 3},
+			{ID: 2, RankType: 1, RankFrom: 11, RankTo: 100, ItemType: 7, ItemID: 500, Quantity: 2},
+		},
+	}})
+
+	handleMsgMhfGetUdDailyPresentList(s, &mhfpacket.MsgMhfGetUdDailyPresentList{AckHandle: 1})
+
+	ack := readAck(t, s)
+	if len(ack.Payload) != 2+2*15 {
+		t.Fatalf("payload = %d bytes, want %d", len(ack.Payload), 2+2*15)
+	}
+	bf := byteframe.NewByteFrameFromBytes(ack.Payload)
+	if n := bf.ReadUint16(); n != 2 {
+		t.Fatalf("count = %d, want 2", n)
+	}
+	_ = bf.ReadBytes(15)
+	rankType, from, to, itemType := bf.ReadUint8(), bf.ReadUint16(), bf.ReadUint16(), bf.ReadUint8()
+	_ = bf.ReadUint16()
+	itemID := bf.ReadUint16()
+	_ = bf.ReadUint16()
+	quantity := bf.ReadUint16()
+	if rankType != 1 || from != 11 || to != 100 || itemType != 7 || itemID != 500 || quantity != 2 {
+		t.Errorf("entry = type %d ranks %d-%d item %d/%d x%d", rankType, from, to, itemType, itemID, quantity)
+	}
+}
+
+func TestHandleMsgMhfGetUdNormaPresentList_Entries(t *testing.T) {
+	s := newDivaPresentSession(&mockDivaRepo{presents: map[string][]DivaPresent{
+		divaPresentsNorma: {{ID: 5, ItemType: 26, ItemID: 1, Quantity: 2, PointsReq: 50000, BeadType: 3}},
+	}})
+
+	handleMsgMhfGetUdNormaPresentList(s, &mhfpacket.MsgMhfGetUdNormaPresentList{AckHandle: 1})
+
+	ack := readAck(t, s)
+	if len(ack.Payload) != 2+19 {
+		t.Fatalf("payload = %d bytes, want %d", len(ack.Payload), 2+19)
+	}
+	bf := byteframe.NewByteFrameFromBytes(ack.Payload[2+14:])
+	if points, bead := bf.ReadUint32(), bf.ReadUint8(); points != 50000 || bead != 3 {
+		t.Errorf("norma threshold = %d bead %d, want 50000 bead 3", points, bead)
+	}
+}
+
+func udItemIDs(ids ...uint32) []byte {
+	bf := byteframe.NewByteFrame()
+	for _, id := range ids {
+		bf.WriteUint32(id)
+	}
+	return bf.Data()
+}
+
+func TestHandleMsgMhfAcquireUdItem_DailyClaimedOnce(t *testing.T) {
+	repo := &mockDivaRepo{
+		events:   []DivaEvent{{ID: 7, StartTime: uint32(time.Now().Unix())}},
+		charRank: DivaRankEntry{Rank: 5, Points: 1000},
+		presents: map[string][]DivaPresent{divaPresentsDaily: {
+			{ID: 1, RankFrom: 1, RankTo: 10, ItemType: 26, ItemID: 1, Quantity: 3},
+			{ID: 2, RankFrom: 11, RankTo: 100, ItemType: 26, ItemID: 1, Quantity: 2},
+		}},
+	}
+	s := newDivaPresentSession(repo)
+	pkt := &mhfpacket.MsgMhfAcquireUdItem{AckHandle: 1, RewardType: udRewardDaily, ItemIDCount: 1, Unk3: udItemIDs(1)}
+
+	handleMsgMhfAcquireUdItem(s, pkt)
+	if ack := readAck(t, s); ack.ErrorCode != 0 {
+		t.Fatalf("first claim error code = %d, want success", ack.ErrorCode)
+	}
+	if !repo.claimed[1] || repo.claimed[2] {
+		t.Errorf("claimed = %v, want only the rank 1-10 present", repo.claimed)
+	}
+	if len(repo.delivered) != 1 || repo.delivered[0].Quantity != 3 {
+		t.Errorf("delivered = %+v, want the rank 1-10 present's items", repo.delivered)
+	}
+	if !repo.claimPeriod.Equal(TimeMidnight()) {
+		t.Errorf("claim period = %v, want today's midnight", repo.claimPeriod)
+	}
+
+	handleMsgMhfAcquireUdItem(s, pkt)
+	if ack := readAck(t, s); ack.ErrorCode == 0 {
+		t.Error("second claim succeeded, want failure")
+	}
+}
+
+func TestHandleMsgMhfAcquireUdItem_NormaThreshold(t *testing.T) {
+	start := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	repo := &mockDivaRepo{
+		events:   []DivaEvent{{ID: 7, StartTime: uint32(start.Unix())}},
+		charRank: DivaRankEntry{Rank: 40, Points: 60000},
+		presents: map[string][]DivaPresent{divaPresentsNorma: {
+			{ID: 3, ItemType: 26, ItemID: 1, Quantity: 1, PointsReq: 10000},
+			{ID: 4, ItemType: 26, ItemID: 1, Quantity: 2, PointsReq: 50000},
+			{ID: 5, ItemType: 26, ItemID: 1, Quantity: 3, PointsReq: 100000},
+		}},
+	}
+	s := newDivaPresentSession(repo)
+
+	handleMsgMhfAcquireUdItem(s, &mhfpacket.MsgMhfAcquireUdItem{
+		AckHandle: 1, RewardType: udRewardPersonal, ItemIDCount: 1, Unk3: udItemIDs(1),
+	})
+
+	if ack := readAck(t, s); ack.ErrorCode != 0 {
+		t.Fatalf("error code = %d, want success", ack.ErrorCode)
+	}
+	if !repo.claimed[3] || !repo.claimed[4] || repo.claimed[5] {
+		t.Errorf("claimed = %v, want the 10000 and 50000 tiers", repo.claimed)
+	}
+	if !repo.claimPeriod.Equal(start) {
+		t.Errorf("claim period = %v, want the event start %v", repo.claimPeriod, start)
+	}
+}
+
+func TestHandleMsgMhfAcquireUdItem_NoEvent(t *testing.T) {
+	repo := &mockDivaRepo{presents: map[string][]DivaPresent{divaPresentsDaily: {{ID: 1, ItemID: 1}}}}
+	s := newDivaPresentSession(repo)
+
+	handleMsgMhfAcquireUdItem(s, &mhfpacket.MsgMhfAcquireUdItem{
+		AckHandle: 1, RewardType: udRewardDaily, ItemIDCount: 1, Unk3: udItemIDs(1),
+	})
+
+	if ack := readAck(t, s); ack.ErrorCode == 0 {
+		t.Error("claim without an event succeeded")
+	}
+	if len(repo.claimed) != 0 {
+		t.Errorf("claimed = %v, want nothing", repo.claimed)
+	}
+}
+
+func TestDivaPresentEligible_GuildRank(t *testing.T) {
+	present := DivaPresent{RankType: 1, RankFrom: 1, RankTo: 3}
+	personal := DivaRankEntry{Rank: 50}
+	if !divaPresentEligible(present, personal, DivaRankEntry{Rank: 2}) {
+		t.Error("guild rank 2 should qualify for a guild 1-3 bracket")
+	}
+	if divaPresentEligible(present, personal, DivaRankEntry{}) {
+		t.Error("unranked guild should not qualify")
+	}
+}
+
+func TestHandleMsgMhfGetUdRanking_Entries(t *testing.T) {
+	repo := &mockDivaRepo{
+		events: []DivaEvent{{ID: 7}},
+		personalRanking: []DivaRankEntry{
+			{Rank: 1, ID: 100, Name: "Hunter", Points: 9000},
+			{Rank: 2, ID: 101, Name: "Other", Points: 500},
+		},
+		guildRanking: []DivaRankEntry{{Rank: 1, ID: 3, Name: "Guild", Points: 9500}},
+	}
+	s := newDivaPresentSession(repo)
+
+	handleMsgMhfGetUdRanking(s, &mhfpacket.MsgMhfGetUdRanking{AckHandle: 1})
+	ack := readAck(t, s)
+	if len(ack.Payload) != 4+2*33 {
+		t.Fatalf("payload = %d bytes, want %d", len(ack.Payload), 4+2*33)
+	}
+	bf := byteframe.NewByteFrameFromBytes(ack.Payload)
+	if n, rank, points := bf.ReadUint32(), bf.ReadUint32(), bf.ReadUint32(); n != 2 || rank != 1 || points != 9000 {
+		t.Errorf("personal ranking = count %d rank %d points %d", n, rank, points)
+	}
+
+	handleMsgMhfGetUdRanking(s, &mhfpacket.MsgMhfGetUdRanking{AckHandle: 2, Unk0: 1})
+	if ack := readAck(t, s); len(ack.Payload) != 4+33 {
+		t.Errorf("guild ranking payload = %d bytes, want %d", len(ack.Payload), 4+33)
+	}
+}
+
+func TestHandleMsgMhfGetUdMyRanking_Disabled(t *testing.T) {
+	repo := &mockDivaRepo{
+		events:   []DivaEvent{{ID: 7}},
+		charRank: DivaRankEntry{Rank: 4, Points: 1200},
+	}
+	s := newDivaPresentSession(repo)
+
+	handleMsgMhfGetUdMyRanking(s, &mhfpacket.MsgMhfGetUdMyRanking{AckHandle: 1})
+
+	ack := readAck(t, s)
+	if len(ack.Payload) != 49 {
+		t.Fatalf("payload = %d bytes, want 49", len(ack.Payload))
+	}
+	if rank := byteframe.NewByteFrameFromBytes(ack.Payload).ReadUint32(); rank != 0x515 {
+		t.Errorf("rank = %d, want the canned placeholder", rank)
+	}
+}
+
+func TestHandleMsgMhfGetUdMyRanking_WithGuild(t *testing.T) {
+	repo := &mockDivaRepo{
+		events:    []DivaEvent{{ID: 7}},
+		charRank:  DivaRankEntry{Rank: 4, Points: 1200},
+		guildRank: DivaRankEntry{Rank: 2, Name: "Guild", Points: 8000},
+	}
+	s := newDivaPresentSession(repo)
+	s.server.erupeConfig.GameplayOptions.EnableDivaMyRanking = true
+	s.server.guildRepo = &mockGuildRepo{membership: &GuildMember{GuildID: 3, CharID: 100}}
+
+	handleMsgMhfGetUdMyRanking(s, &mhfpacket.MsgMhfGetUdMyRanking{AckHandle: 1})
+
+	ack := readAck(t, s)
+	if len(ack.Payload) != 49 {
+		t.Fatalf("payload = %d bytes, want 49", len(ack.Payload))
+	}
+	bf := byteframe.NewByteFrameFromBytes(ack.Payload)
+	got := [6]uint32{bf.ReadUint32(), bf.ReadUint32(), bf.ReadUint32(), bf.ReadUint32(), bf.ReadUint32(), bf.ReadUint32()}
+	if got != [6]uint32{4, 4, 1200, 2, 2, 8000} {
+		t.Errorf("ranks = %v", got)
+	}
+	if name := string(ack.Payload[24:29]); name != "Guild" {
+		t.Errorf("guild name = %q", name)
+	}
+}
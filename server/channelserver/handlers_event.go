@@ -18,16 +18,56 @@ type Event struct {
 	Unk2         uint16
 	Unk3         uint16
 	Unk4         uint16
-	Unk5         uint32
-	Unk6         uint32
+	StartTime    uint32
+	EndTime      uint32
 	QuestFileIDs []uint16
 }
 
+// fortAttackEvents returns the scheduled fort attacks to list in
+// EnumerateEvent, or none when the scheduler is disabled.
+func fortAttackEvents(s *Session) []Event {
+	if !s.server.erupeConfig.FortAttack.Enabled {
+		return []Event{}
+	}
+	announced, err := s.server.fortAttackService.Announced(TimeAdjusted())
+	if err != nil {
+		s.logger.Error("Failed to get fort attacks", zap.Error(err))
+		return []Event{}
+	}
+	events := make([]Event, 0, len(announced))
+	for _, fa := range announced {
+		event := Event{
+			EventType: fa.EventType,
+			StartTime: uint32(fa.StartsAt.Unix()),
+			EndTime:   uint32(fa.EndsAt.Unix()),
+		}
+		for _, id := range fa.QuestFileIDs {
+			if len(event.QuestFileIDs) == math.MaxUint8 {
+				break
+			}
+			event.QuestFileIDs = append(event.QuestFileIDs, uint16(id))
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// runFortAttacks schedules and ends fort attack events until the server
+// shuts down.
+func (s *Server) runFortAttacks() {
+	s.runPeriodic(fortAttackTickInterval, func() {
+		s.fortAttackService.Tick(TimeAdjusted(), s.erupeConfig.FortAttack)
+	})
+}
+
 func handleMsgMhfEnumerateEvent(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfEnumerateEvent)
 	bf := byteframe.NewByteFrame()
 
-	events := []Event{}
+	events := fortAttackEvents(s)
+	if len(events) > math.MaxUint8 {
+		events = events[:math.MaxUint8]
+	}
 
 	bf.WriteUint8(uint8(len(events)))
 	for _, event := range events {
@@ -36,8 +76,8 @@ func handleMsgMhfEnumerateEvent(s *Session, p mhfpacket.MHFPacket) {
 		bf.WriteUint16(event.Unk2)
 		bf.WriteUint16(event.Unk3)
 		bf.WriteUint16(event.Unk4)
-		bf.WriteUint32(event.Unk5)
-		bf.WriteUint32(event.Unk6)
+		bf.WriteUint32(event.StartTime)
+		bf.WriteUint32(event.EndTime)
 		if event.EventType == 2 {
 			bf.WriteUint8(uint8(len(event.QuestFileIDs)))
 			for _, qf := range event.QuestFileIDs {
@@ -217,6 +257,9 @@ func handleMsgMhfUseKeepLoginBoost(s *Session, p mhfpacket.MHFPacket) {
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
+// handleMsgMhfGetRestrictionEvent is a stub: the packet's layout is unknown.
+// Fort attack restrictions are enforced when the player enters the quest
+// stage instead (see handleMsgSysEnterStage).
 func handleMsgMhfGetRestrictionEvent(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
 
 func handleMsgMhfSetRestrictionEvent(s *Session, p mhfpacket.MHFPacket) {
@@ -2,8 +2,11 @@ package channelserver
 
 import (
 	"math/bits"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"erupe-ce/common/byteframe"
 	cfg "erupe-ce/config"
 	"erupe-ce/network/mhfpacket"
 	"time"
@@ -296,6 +299,121 @@ func TestHandleMsgMhfGetRestrictionEvent_Empty(t *testing.T) {
 	handleMsgMhfGetRestrictionEvent(session, nil)
 }
 
+func TestHandleMsgMhfEnumerateEvent_FortAttack(t *testing.T) {
+	server := createMockServer()
+	server.erupeConfig.FortAttack.Enabled = true
+	now := TimeAdjusted()
+	server.fortAttackRepo = &mockFortAttackRepo{events: []FortAttack{
+		{ID: 1, EventType: 2, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), QuestFileIDs: []int64{20001, 20004}},
+		{ID: 2, EventType: 1, StartsAt: now.Add(2 * time.Hour), EndsAt: now.Add(3 * time.Hour)},
+		{ID: 3, EventType: 1, StartsAt: now.Add(48 * time.Hour), EndsAt: now.Add(49 * time.Hour)},
+	}}
+	ensureFortAttackService(server)
+	session := createMockSession(1, server)
+
+	handleMsgMhfEnumerateEvent(session, &mhfpacket.MsgMhfEnumerateEvent{AckHandle: 1})
+
+	bf := byteframe.NewByteFrameFromBytes(extractAckData(t, session))
+	if n := bf.ReadUint8(); n != 2 {
+		t.Fatalf("event count = %d, want 2 (the third is beyond the lookahead)", n)
+	}
+	if eventType := bf.ReadUint16(); eventType != 2 {
+		t.Errorf("EventType = %d, want 2", eventType)
+	}
+	bf.ReadBytes(8) // Unk1-Unk4
+	if start := bf.ReadUint32(); start != uint32(now.Add(-time.Hour).Unix()) {
+		t.Errorf("StartTime = %d", start)
+	}
+	bf.ReadUint32() // EndTime
+	if n := bf.ReadUint8(); n != 2 {
+		t.Fatalf("quest count = %d, want 2", n)
+	}
+	if id := bf.ReadUint16(); id != 20001 {
+		t.Errorf("quest = %d, want 20001", id)
+	}
+	bf.ReadUint16()
+	if eventType := bf.ReadUint16(); eventType != 1 {
+		t.Errorf("second EventType = %d, want 1", eventType)
+	}
+}
+
+func TestFortAttackSortie(t *testing.T) {
+	server := createMockServer()
+	server.erupeConfig.FortAttack.Enabled = true
+	server.erupeConfig.FortAttack.SortieDamage = 25
+	now := TimeAdjusted()
+	repo := &mockFortAttackRepo{events: []FortAttack{
+		{ID: 4, EventType: 2, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour),
+			QuestFileIDs: []int64{20001}, MinHR: 100, MaxSorties: 1},
+	}}
+	server.fortAttackRepo = repo
+	ensureFortAttackService(server)
+	charRepo := newMockCharacterRepo()
+	charRepo.ints["hr"] = 50
+	server.charRepo = charRepo
+	session := createMockSession(1, server)
+
+	if fortAttackSortie(session, "20001d0") {
+		t.Error("sortie below MinHR was allowed")
+	}
+	if !fortAttackSortie(session, "23527d0") {
+		t.Error("non-fort quest was refused")
+	}
+
+	charRepo.ints["hr"] = 200
+	if !fortAttackSortie(session, "20001d0") || session.fortSortie != 4 {
+		t.Fatalf("sortie refused, fortSortie = %d", session.fortSortie)
+	}
+	if !fortAttackSortie(session, "20001n0") {
+		t.Error("second request for the same sortie was refused")
+	}
+
+	session.stage = NewStage("test_stage")
+	server.erupeConfig.RealClientMode = cfg.ZZ
+	server.guildRepo = &mockGuildRepo{}
+	handleMsgSysRecordLog(session, &mhfpacket.MsgSysRecordLog{AckHandle: 1, Data: make([]byte, 256)})
+	if len(repo.damaged) != 1 || repo.damaged[0] != 25 || session.fortSortie != 0 {
+		t.Errorf("damaged = %v, fortSortie = %d", repo.damaged, session.fortSortie)
+	}
+
+	if fortAttackSortie(session, "20001d0") {
+		t.Error("sortie beyond MaxSorties was allowed")
+	}
+}
+
+func TestFortAttackSortie_CountedOnQuestEntry(t *testing.T) {
+	server := createMockServer()
+	server.erupeConfig.FortAttack.Enabled = true
+	server.erupeConfig.BinPath = t.TempDir()
+	now := TimeAdjusted()
+	repo := &mockFortAttackRepo{events: []FortAttack{
+		{ID: 4, EventType: 2, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour),
+			QuestFileIDs: []int64{20001}, MaxSorties: 1},
+	}}
+	server.fortAttackRepo = repo
+	ensureFortAttackService(server)
+	server.charRepo = newMockCharacterRepo()
+	session := createMockSession(1, server)
+
+	questDir := filepath.Join(server.erupeConfig.BinPath, "quests")
+	if err := os.MkdirAll(questDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(questDir, "20001d0.bin"), []byte{0x01}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	handleMsgSysGetFile(session, &mhfpacket.MsgSysGetFile{AckHandle: 1, Filename: "20001d0"})
+	<-session.sendPackets
+	if repo.sorties[1] != 0 || session.fortSortie != 0 {
+		t.Fatalf("loading the quest file counted a sortie: sorties = %v", repo.sorties)
+	}
+
+	handleMsgSysEnterStage(session, &mhfpacket.MsgSysEnterStage{AckHandle: 2, StageID: "sl1Qs001p0a0u0"})
+	if repo.sorties[1] != 1 || session.fortSortie != 4 {
+		t.Errorf("sorties = %v, fortSortie = %d after entering the quest", repo.sorties, session.fortSortie)
+	}
+}
+
 // TestHandleMsgMhfRegisterEvent_DifferentValues tests with various Unk2/Unk4 values.
 func TestHandleMsgMhfRegisterEvent_DifferentValues(t *testing.T) {
 	server := createMockServer()
@@ -60,29 +60,38 @@ func generateFestaTimestamps(s *Session, start uint32, debug bool) []uint32 {
 		}
 		return timestamps
 	}
-	var err error
-	start, err = s.server.festaService.EnsureActiveEvent(start, TimeAdjusted(), midnight.Add(24*time.Hour))
-	if err != nil {
-		s.logger.Error("Failed to ensure active festa event", zap.Error(err))
-	}
-	timestamps[0] = start
-	timestamps[1] = timestamps[0] + secsPerWeek
-	timestamps[2] = timestamps[1] + secsPerWeek
-	timestamps[3] = timestamps[2] + festaVotingDuration
-	timestamps[4] = timestamps[3] + festaRewardDuration
-	return timestamps
+	// With the scheduler running it owns the festa event; otherwise the event
+	// is replaced here once it expires.
+	if !s.server.erupeConfig.Festa.Enabled {
+		var err error
+		start, err = s.server.festaService.EnsureActiveEvent(start, TimeAdjusted(), midnight.Add(24*time.Hour))
+		if err != nil {
+			s.logger.Error("Failed to ensure active festa event", zap.Error(err))
+		}
+	}
+	return festaTimestamps(start)
+}
+
+// runFesta drives the festival cycle until the server shuts down. It is
+// idle while DebugOptions.FestaOverride pins the festival to a phase.
+func (s *Server) runFesta() {
+	s.runPeriodic(festaTickInterval, func() {
+		if s.erupeConfig.DebugOptions.FestaOverride < 0 {
+			s.festaService.Tick(TimeAdjusted(), s.erupeConfig.Festa.RestDays)
+		}
+	})
 }
 
 // FestaTrial represents a festa trial/challenge entry.
 type FestaTrial struct {
-	ID        uint32        `db:"id"`
-	Objective uint16        `db:"objective"`
-	GoalID    uint32        `db:"goal_id"`
-	TimesReq  uint16        `db:"times_req"`
-	Locale    uint16        `db:"locale_req"`
-	Reward    uint16        `db:"reward"`
-	Monopoly  FestivalColor `db:"monopoly"`
-	Unk       uint16
+	ID        uint32        `db:"id" json:"id"`
+	Objective uint16        `db:"objective" json:"objective"`
+	GoalID    uint32        `db:"goal_id" json:"goalId"`
+	TimesReq  uint16        `db:"times_req" json:"timesReq"`
+	Locale    uint16        `db:"locale_req" json:"localeReq"`
+	Reward    uint16        `db:"reward" json:"reward"`
+	Monopoly  FestivalColor `db:"monopoly" json:"monopoly"`
+	Unk       uint16        `json:"-"`
 }
 
 // FestaReward represents a festa reward entry.
@@ -124,7 +133,7 @@ func handleMsgMhfInfoFesta(s *Session, p mhfpacket.MHFPacket) {
 		timestamps = generateFestaTimestamps(s, start, false)
 	}
 
-	if timestamps[0] > uint32(TimeAdjusted().Unix()) {
+	if timestamps[0] == 0 || timestamps[0] > uint32(TimeAdjusted().Unix()) {
 		doAckBufSucceed(s, pkt.AckHandle, make([]byte, 4))
 		return
 	}
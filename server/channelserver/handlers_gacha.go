@@ -9,45 +9,83 @@ import (
 
 // Gacha represents a gacha lottery definition.
 type Gacha struct {
-	ID           uint32 `db:"id"`
-	MinGR        uint32 `db:"min_gr"`
-	MinHR        uint32 `db:"min_hr"`
-	Name         string `db:"name"`
-	URLBanner    string `db:"url_banner"`
-	URLFeature   string `db:"url_feature"`
-	URLThumbnail string `db:"url_thumbnail"`
-	Wide         bool   `db:"wide"`
-	Recommended  bool   `db:"recommended"`
-	GachaType    uint8  `db:"gacha_type"`
-	Hidden       bool   `db:"hidden"`
+	ID           uint32 `db:"id" json:"id"`
+	MinGR        uint32 `db:"min_gr" json:"minGr"`
+	MinHR        uint32 `db:"min_hr" json:"minHr"`
+	Name         string `db:"name" json:"name"`
+	URLBanner    string `db:"url_banner" json:"urlBanner"`
+	URLFeature   string `db:"url_feature" json:"urlFeature"`
+	URLThumbnail string `db:"url_thumbnail" json:"urlThumbnail"`
+	Wide         bool   `db:"wide" json:"wide"`
+	Recommended  bool   `db:"recommended" json:"recommended"`
+	GachaType    uint8  `db:"gacha_type" json:"gachaType"`
+	Hidden       bool   `db:"hidden" json:"hidden"`
 }
 
 // GachaEntry represents a gacha entry (step/box).
 type GachaEntry struct {
-	EntryType      uint8   `db:"entry_type"`
-	ID             uint32  `db:"id"`
-	ItemType       uint8   `db:"item_type"`
-	ItemNumber     uint32  `db:"item_number"`
-	ItemQuantity   uint16  `db:"item_quantity"`
-	Weight         float64 `db:"weight"`
-	Rarity         uint8   `db:"rarity"`
-	Rolls          uint8   `db:"rolls"`
-	FrontierPoints uint16  `db:"frontier_points"`
-	DailyLimit     uint8   `db:"daily_limit"`
-	Name           string  `db:"name"`
+	EntryType      uint8   `db:"entry_type" json:"entryType"`
+	ID             uint32  `db:"id" json:"id"`
+	ItemType       uint8   `db:"item_type" json:"itemType"`
+	ItemNumber     uint32  `db:"item_number" json:"itemNumber"`
+	ItemQuantity   uint16  `db:"item_quantity" json:"itemQuantity"`
+	Weight         float64 `db:"weight" json:"weight"`
+	Rarity         uint8   `db:"rarity" json:"rarity"`
+	Rolls          uint8   `db:"rolls" json:"rolls"`
+	FrontierPoints uint16  `db:"frontier_points" json:"frontierPoints"`
+	DailyLimit     uint8   `db:"daily_limit" json:"dailyLimit"`
+	Name           string  `db:"name" json:"name"`
 }
 
 // GachaItem represents a single item in a gacha pool.
 type GachaItem struct {
-	ItemType uint8  `db:"item_type"`
-	ItemID   uint16 `db:"item_id"`
-	Quantity uint16 `db:"quantity"`
+	ItemType uint8  `db:"item_type" json:"itemType"`
+	ItemID   uint16 `db:"item_id" json:"itemId"`
+	Quantity uint16 `db:"quantity" json:"quantity"`
 }
 
+// gachaPlayHistorySize is the number of plays sent in a play history
+// response.
+//
+// The response keeps its one-byte placeholder unless
+// GameplayOptions.EnableGachaPlayHistory is set, as the layout below has not
+// been confirmed against a client:
+//
+//	u8 count, then per play of the requested gacha, newest first:
+//	  u32 played at (Unix), u8 roll type, u8 item count,
+//	  items as in the play responses: u8 type, u16 ID, u16 quantity, u8 rarity
+const gachaPlayHistorySize = 20
+
 func handleMsgMhfGetGachaPlayHistory(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfGetGachaPlayHistory)
 	bf := byteframe.NewByteFrame()
-	bf.WriteUint8(1)
+	if !s.server.erupeConfig.GameplayOptions.EnableGachaPlayHistory {
+		bf.WriteUint8(1)
+		doAckBufSucceed(s, pkt.AckHandle, bf.Data())
+		return
+	}
+	plays, err := s.server.gachaRepo.ListPlays(GachaPlayFilter{
+		CharID:  s.charID,
+		GachaID: pkt.GachaID,
+		Limit:   gachaPlayHistorySize,
+	})
+	if err != nil {
+		s.logger.Error("Failed to get gacha play history", zap.Error(err))
+		plays = nil
+	}
+	bf.WriteUint8(uint8(len(plays)))
+	for _, play := range plays {
+		bf.WriteUint32(uint32(play.PlayedAt.Unix()))
+		bf.WriteUint8(play.RollType)
+		items := play.Items[:min(len(play.Items), 255)]
+		bf.WriteUint8(uint8(len(items)))
+		for _, item := range items {
+			bf.WriteUint8(item.ItemType)
+			bf.WriteUint16(item.ItemID)
+			bf.WriteUint16(item.Quantity)
+			bf.WriteUint8(item.Rarity)
+		}
+	}
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
@@ -227,6 +265,7 @@ func handleMsgMhfResetBoxGachaInfo(s *Session, p mhfpacket.MHFPacket) {
 
 func handleMsgMhfPlayFreeGacha(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfPlayFreeGacha)
+	s.server.gachaService.PlayFreeGacha(s.userID, s.charID, pkt.GachaID, pkt.GachaType)
 	bf := byteframe.NewByteFrame()
 	bf.WriteUint32(1)
 	doAckSimpleSucceed(s, pkt.AckHandle, bf.Data())
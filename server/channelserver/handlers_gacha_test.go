@@ -27,6 +27,40 @@ func TestHandleMsgMhfGetGachaPlayHistory_StubResponse(t *testing.T) {
 	}
 }
 
+func TestHandleMsgMhfGetGachaPlayHistory_Ledger(t *testing.T) {
+	server := createMockServer()
+	server.erupeConfig.GameplayOptions.EnableGachaPlayHistory = true
+	gachaRepo := &mockGachaRepo{}
+	_ = gachaRepo.InsertPlay(GachaPlay{CharID: 1, GachaID: 1, RollType: 2, Items: []GachaPlayItem{
+		{ItemType: 7, ItemID: 500, Quantity: 3, Rarity: 4},
+	}})
+	_ = gachaRepo.InsertPlay(GachaPlay{CharID: 1, GachaID: 2})
+	_ = gachaRepo.InsertPlay(GachaPlay{CharID: 9, GachaID: 1})
+	server.gachaRepo = gachaRepo
+	session := createMockSession(1, server)
+
+	handleMsgMhfGetGachaPlayHistory(session, &mhfpacket.MsgMhfGetGachaPlayHistory{AckHandle: 100, GachaID: 1})
+
+	select {
+	case p := <-session.sendPackets:
+		bf := byteframe.NewByteFrameFromBytes(p.data[10:]) // Skip full ACK header
+		if n := bf.ReadUint8(); n != 1 {
+			t.Fatalf("count = %d, want only character 1's play of gacha 1", n)
+		}
+		if at := bf.ReadUint32(); at != uint32(gachaRepo.plays[0].PlayedAt.Unix()) {
+			t.Errorf("played at = %d", at)
+		}
+		if roll, items := bf.ReadUint8(), bf.ReadUint8(); roll != 2 || items != 1 {
+			t.Errorf("roll = %d, items = %d", roll, items)
+		}
+		if typ, id, qty, rarity := bf.ReadUint8(), bf.ReadUint16(), bf.ReadUint16(), bf.ReadUint8(); typ != 7 || id != 500 || qty != 3 || rarity != 4 {
+			t.Errorf("item = %d %d %d %d", typ, id, qty, rarity)
+		}
+	default:
+		t.Error("No response packet queued")
+	}
+}
+
 func TestHandleMsgMhfGetGachaPoint(t *testing.T) {
 	server := createMockServer()
 	userRepo := &mockUserRepoGacha{
@@ -560,6 +594,9 @@ func TestHandleMsgMhfResetBoxGachaInfo(t *testing.T) {
 
 func TestHandleMsgMhfPlayFreeGacha_StubACK(t *testing.T) {
 	server := createMockServer()
+	gachaRepo := &mockGachaRepo{}
+	server.gachaRepo = gachaRepo
+	ensureGachaService(server)
 	session := createMockSession(1, server)
 
 	pkt := &mhfpacket.MsgMhfPlayFreeGacha{AckHandle: 100, GachaID: 1}
@@ -571,6 +608,9 @@ func TestHandleMsgMhfPlayFreeGacha_StubACK(t *testing.T) {
 	default:
 		t.Error("No response packet queued")
 	}
+	if len(gachaRepo.plays) != 1 || gachaRepo.plays[0].Kind != GachaPlayFree || gachaRepo.plays[0].CharID != 1 {
+		t.Errorf("plays = %+v, want one free play by character 1", gachaRepo.plays)
+	}
 }
 
 func TestGetRandomEntries_NonBox(t *testing.T) {
@@ -1060,31 +1060,6 @@ func TestHandleMsgMhfAcquireMonthlyItem_Coverage2(t *testing.T) {
 	}
 }
 
-func TestEmptyGuildHandlers(t *testing.T) {
-	server := createMockServer()
-	session := createMockSession(1, server)
-
-	tests := []struct {
-		name    string
-		handler func(s *Session, p mhfpacket.MHFPacket)
-	}{
-		{"handleMsgMhfUpdateForceGuildRank", handleMsgMhfUpdateForceGuildRank},
-		{"handleMsgMhfUpdateGuild", handleMsgMhfUpdateGuild},
-		{"handleMsgMhfUpdateGuildcard", handleMsgMhfUpdateGuildcard},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					t.Errorf("%s panicked: %v", tt.name, r)
-				}
-			}()
-			tt.handler(session, nil)
-		})
-	}
-}
-
 func TestAcquireMonthlyItem_MarksAsClaimed(t *testing.T) {
 	server := createMockServer()
 	stampMock := &mockStampRepoForItems{}
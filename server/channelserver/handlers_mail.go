@@ -29,28 +29,44 @@ type Mail struct {
 	SenderName           string    `db:"sender_name"`
 }
 
-// SendMailNotification sends a new mail notification to a player.
+// SendMailNotification sends a new mail notification to a player. The sender
+// name is looked up through s unless m already carries it, so s may be nil
+// for mail sent outside a session.
 func SendMailNotification(s *Session, m *Mail, recipient *Session) {
+	queueMailNotify(recipient, m.SenderID, mailSenderName(s, m))
+}
+
+// queueMailNotify sends the "new mail" popup to recipient. It only needs the
+// sender's ID and name so it can also serve notifications relayed from other
+// processes by PostgresChannelRegistry.
+func queueMailNotify(recipient *Session, senderID uint32, senderName string) {
 	bf := byteframe.NewByteFrame()
 
 	notification := &binpacket.MsgBinMailNotify{
-		SenderName: getCharacterName(s, m.SenderID),
+		SenderName: senderName,
 	}
 
 	_ = notification.Build(bf)
 
 	castedBinary := &mhfpacket.MsgSysCastedBinary{
-		CharID:         m.SenderID,
+		CharID:         senderID,
 		BroadcastType:  0x00,
 		MessageType:    BinaryMessageTypeMailNotify,
 		RawDataPayload: bf.Data(),
 	}
 
-	_ = castedBinary.Build(bf, s.clientContext)
-
 	recipient.QueueSendMHFNonBlocking(castedBinary)
 }
 
+// mailSenderName returns m's sender name, looking it up through s if m does
+// not carry it.
+func mailSenderName(s *Session, m *Mail) string {
+	if m.SenderName != "" || s == nil {
+		return m.SenderName
+	}
+	return getCharacterName(s, m.SenderID)
+}
+
 func getCharacterName(s *Session, charID uint32) string {
 	name, err := s.server.charRepo.GetName(charID)
 	if err != nil {
@@ -70,11 +70,16 @@ func handleMsgMhfKickExportForce(s *Session, p mhfpacket.MHFPacket) {} // stub:
 
 func handleMsgMhfGetEarthStatus(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfGetEarthStatus)
+	phase := earthPhase(s)
+	status := phase.Status
+	if !phase.Enabled {
+		status = s.server.erupeConfig.EarthStatus
+	}
 	bf := byteframe.NewByteFrame()
-	bf.WriteUint32(uint32(TimeWeekStart().Unix())) // Start
-	bf.WriteUint32(uint32(TimeWeekNext().Unix()))  // End
-	bf.WriteInt32(s.server.erupeConfig.EarthStatus)
-	bf.WriteInt32(s.server.erupeConfig.EarthID)
+	bf.WriteUint32(uint32(phase.Start.Unix())) // Start
+	bf.WriteUint32(uint32(phase.End.Unix()))   // End
+	bf.WriteInt32(status)
+	bf.WriteInt32(phase.EarthID)
 	for i, m := range s.server.erupeConfig.EarthMonsters {
 		if s.server.erupeConfig.RealClientMode <= cfg.G9 {
 			if i == 3 {
@@ -147,32 +152,75 @@ func handleMsgMhfGetSenyuDailyCount(s *Session, p mhfpacket.MHFPacket) {
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
-// handleMsgMhfGetDailyMissionMaster returns an empty daily mission master list.
-// The full response format is not yet reverse-engineered; count=0 is safe.
+// handleMsgMhfGetDailyMissionMaster returns the daily mission board loaded
+// from daily_missions.json. The board is only sent when
+// GameplayOptions.EnableDailyMissions is set, as the entry layout is a guess
+// that no capture has confirmed:
+//
+//	uint32 mission ID
+//	uint8  type
+//	uint32 target
+//	uint32 quantity
+//	uint16 reward item ID
+//	uint16 reward quantity
 func handleMsgMhfGetDailyMissionMaster(s *Session, p mhfpacket.MHFPacket) {
 	if p == nil {
 		return
 	}
 	pkt := p.(*mhfpacket.MsgMhfGetDailyMissionMaster)
 	bf := byteframe.NewByteFrame()
-	bf.WriteUint32(0) // entry count = 0
+	if !s.server.erupeConfig.GameplayOptions.EnableDailyMissions {
+		bf.WriteUint32(0) // entry count = 0
+		doAckBufSucceed(s, pkt.AckHandle, bf.Data())
+		return
+	}
+	bf.WriteUint32(uint32(len(s.server.dailyMissions)))
+	for _, m := range s.server.dailyMissions {
+		bf.WriteUint32(m.ID)
+		bf.WriteUint8(m.Type)
+		bf.WriteUint32(m.Target)
+		bf.WriteUint32(m.Quantity)
+		bf.WriteUint16(m.RewardItem)
+		bf.WriteUint16(m.RewardQuantity)
+	}
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
-// handleMsgMhfGetDailyMissionPersonal returns an empty personal daily mission progress list.
-// The full response format is not yet reverse-engineered; count=0 is safe.
+// handleMsgMhfGetDailyMissionPersonal returns the character's progress for
+// today. Progress resets at gametime.Midnight(). Like the board, it is only
+// sent when GameplayOptions.EnableDailyMissions is set; the entry layout is
+// a guess that no capture has confirmed:
+//
+//	uint32 mission ID
+//	uint32 progress
+//	bool   reward claimed
 func handleMsgMhfGetDailyMissionPersonal(s *Session, p mhfpacket.MHFPacket) {
 	if p == nil {
 		return
 	}
 	pkt := p.(*mhfpacket.MsgMhfGetDailyMissionPersonal)
 	bf := byteframe.NewByteFrame()
-	bf.WriteUint32(0) // entry count = 0
+	if !s.server.erupeConfig.GameplayOptions.EnableDailyMissions {
+		bf.WriteUint32(0) // entry count = 0
+		doAckBufSucceed(s, pkt.AckHandle, bf.Data())
+		return
+	}
+	progress, err := s.server.dailyMissionRepo.GetProgress(s.charID, TimeMidnight())
+	if err != nil {
+		s.logger.Error("Failed to load daily mission progress", zap.Error(err))
+	}
+	bf.WriteUint32(uint32(len(progress)))
+	for _, mp := range progress {
+		bf.WriteUint32(mp.MissionID)
+		bf.WriteUint32(mp.Progress)
+		bf.WriteBool(mp.Claimed)
+	}
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
-// handleMsgMhfSetDailyMissionPersonal acknowledges a personal daily mission progress write.
-// The full request/response format is not yet reverse-engineered.
+// handleMsgMhfSetDailyMissionPersonal acknowledges a personal daily mission
+// progress write. The request body beyond the AckHandle has not been
+// reverse-engineered, so nothing is recorded.
 func handleMsgMhfSetDailyMissionPersonal(s *Session, p mhfpacket.MHFPacket) {
 	if p == nil {
 		return
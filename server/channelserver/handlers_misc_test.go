@@ -1,8 +1,10 @@
 package channelserver
 
 import (
+	"bytes"
 	"testing"
 
+	"erupe-ce/common/byteframe"
 	cfg "erupe-ce/config"
 	"erupe-ce/network/mhfpacket"
 )
@@ -502,6 +504,66 @@ func TestHandleMsgMhfSetDailyMissionPersonal(t *testing.T) {
 	handleMsgMhfSetDailyMissionPersonal(session, nil)
 }
 
+var testDailyMissions = []DailyMission{
+	{ID: 1, Type: 1, Target: 11, Quantity: 3, RewardItem: 7, RewardQuantity: 2},
+	{ID: 2, Type: 2, Target: 0, Quantity: 1},
+}
+
+func TestHandleMsgMhfGetDailyMissionMaster_Disabled(t *testing.T) {
+	server := createMockServer()
+	server.dailyMissions = testDailyMissions
+	server.dailyMissionRepo = &mockDailyMissionRepo{progress: []DailyMissionProgress{{MissionID: 1, Progress: 2}}}
+	session := createMockSession(1, server)
+
+	handleMsgMhfGetDailyMissionMaster(session, &mhfpacket.MsgMhfGetDailyMissionMaster{AckHandle: 1})
+	handleMsgMhfGetDailyMissionPersonal(session, &mhfpacket.MsgMhfGetDailyMissionPersonal{AckHandle: 2})
+
+	for _, name := range []string{"master", "personal"} {
+		if payload := readAck(t, session).Payload; !bytes.Equal(payload, make([]byte, 4)) {
+			t.Errorf("%s payload = %x, want an empty list", name, payload)
+		}
+	}
+}
+
+func TestHandleMsgMhfGetDailyMissionMaster_Entries(t *testing.T) {
+	server := createMockServer()
+	server.erupeConfig.GameplayOptions.EnableDailyMissions = true
+	server.dailyMissions = testDailyMissions
+	session := createMockSession(1, server)
+
+	handleMsgMhfGetDailyMissionMaster(session, &mhfpacket.MsgMhfGetDailyMissionMaster{AckHandle: 1})
+
+	ack := readAck(t, session)
+	bf := byteframe.NewByteFrameFromBytes(ack.Payload)
+	if n := bf.ReadUint32(); n != 2 {
+		t.Fatalf("count = %d, want 2", n)
+	}
+	if id, typ, target, qty := bf.ReadUint32(), bf.ReadUint8(), bf.ReadUint32(), bf.ReadUint32(); id != 1 || typ != 1 || target != 11 || qty != 3 {
+		t.Errorf("entry 0 = (%d, %d, %d, %d), want (1, 1, 11, 3)", id, typ, target, qty)
+	}
+	if item, qty := bf.ReadUint16(), bf.ReadUint16(); item != 7 || qty != 2 {
+		t.Errorf("entry 0 reward = %dx%d, want 7x2", item, qty)
+	}
+}
+
+func TestHandleMsgMhfGetDailyMissionPersonal_Entries(t *testing.T) {
+	server := createMockServer()
+	server.erupeConfig.GameplayOptions.EnableDailyMissions = true
+	server.dailyMissionRepo = &mockDailyMissionRepo{progress: []DailyMissionProgress{{MissionID: 1, Progress: 2}, {MissionID: 2, Progress: 1, Claimed: true}}}
+	session := createMockSession(1, server)
+
+	handleMsgMhfGetDailyMissionPersonal(session, &mhfpacket.MsgMhfGetDailyMissionPersonal{AckHandle: 1})
+
+	bf := byteframe.NewByteFrameFromBytes(readAck(t, session).Payload)
+	if n := bf.ReadUint32(); n != 2 {
+		t.Fatalf("count = %d, want 2", n)
+	}
+	bf.ReadBytes(9)
+	if id, progress, claimed := bf.ReadUint32(), bf.ReadUint32(), bf.ReadBool(); id != 2 || progress != 1 || !claimed {
+		t.Errorf("entry 1 = (%d, %d, %v), want (2, 1, true)", id, progress, claimed)
+	}
+}
+
 func TestHandleMsgMhfGetUdShopCoin(t *testing.T) {
 	server := createMockServer()
 	session := createMockSession(1, server)
@@ -1,13 +1,81 @@
 package channelserver
 
-import "erupe-ce/network/mhfpacket"
+import (
+	"erupe-ce/common/byteframe"
+	"erupe-ce/network/mhfpacket"
+)
 
-func handleMsgSysCreateMutex(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+// Mutex acks carry the uint32 mutex ID on success and four zero bytes on
+// failure, mirroring the semaphore handlers.
 
-func handleMsgSysCreateOpenMutex(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+func doAckMutex(s *Session, ackHandle uint32, m *Mutex) {
+	bf := byteframe.NewByteFrame()
+	bf.WriteUint32(m.id)
+	doAckSimpleSucceed(s, ackHandle, bf.Data())
+}
 
-func handleMsgSysDeleteMutex(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+func handleMsgSysCreateMutex(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysCreateMutex)
+	s.server.mutexLock.Lock()
+	m := s.server.createMutex(s, pkt.MutexName)
+	s.server.mutexLock.Unlock()
+	doAckMutex(s, pkt.AckHandle, m)
+}
 
-func handleMsgSysOpenMutex(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+func handleMsgSysCreateOpenMutex(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysCreateOpenMutex)
+	s.server.mutexLock.Lock()
+	m := s.server.createMutex(s, pkt.MutexName)
+	owned := m.open(s, pkt.AckHandle)
+	s.server.mutexLock.Unlock()
+	if owned {
+		doAckMutex(s, pkt.AckHandle, m)
+	}
+}
 
-func handleMsgSysCloseMutex(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+func handleMsgSysOpenMutex(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysOpenMutex)
+	s.server.mutexLock.Lock()
+	m, exists := s.server.mutexes[pkt.MutexName]
+	owned := exists && m.open(s, pkt.AckHandle)
+	s.server.mutexLock.Unlock()
+	switch {
+	case !exists:
+		doAckSimpleFail(s, pkt.AckHandle, make([]byte, 4))
+	case owned:
+		doAckMutex(s, pkt.AckHandle, m)
+	}
+}
+
+func handleMsgSysCloseMutex(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysCloseMutex)
+	acks := []mutexAck{{session: s, ackHandle: pkt.AckHandle}}
+	s.server.mutexLock.Lock()
+	if m, exists := s.server.mutexes[pkt.MutexName]; exists {
+		if m.owner == s {
+			acks[0].id = m.id
+			acks = append(acks, m.release()...)
+		} else if handle, queued := m.removeWaiter(s); queued {
+			// Cancelling a queued open fails its pending ack.
+			acks[0].id = m.id
+			acks = append(acks, mutexAck{session: s, ackHandle: handle})
+		}
+	}
+	s.server.mutexLock.Unlock()
+	sendMutexAcks(s, acks)
+}
+
+func handleMsgSysDeleteMutex(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysDeleteMutex)
+	acks := []mutexAck{{session: s, ackHandle: pkt.AckHandle}}
+	s.server.mutexLock.Lock()
+	if m, exists := s.server.mutexes[pkt.MutexName]; exists && (m.owner == nil || m.owner == s) {
+		acks[0].id = m.id
+		for _, w := range m.waiters {
+			acks = append(acks, mutexAck{session: w.session, ackHandle: w.ackHandle})
+		}
+		delete(s.server.mutexes, pkt.MutexName)
+	}
+	s.server.mutexLock.Unlock()
+	sendMutexAcks(s, acks)
+}
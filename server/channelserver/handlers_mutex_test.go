@@ -1,77 +1,173 @@
 package channelserver
 
 import (
+	"encoding/binary"
 	"testing"
+
+	"erupe-ce/network"
+	"erupe-ce/network/mhfpacket"
 )
 
-// Test that all mutex handlers don't panic (they are empty implementations)
+// expectMutexAck reads one ack from session and checks its handle and
+// whether it reports success.
+func expectMutexAck(t *testing.T, session *Session, ackHandle uint32, ok bool) uint32 {
+	t.Helper()
+	ack := readAck(t, session)
+	if ack.AckHandle != ackHandle {
+		t.Fatalf("ack handle = %d, want %d", ack.AckHandle, ackHandle)
+	}
+	if (ack.ErrorCode == 0) != ok {
+		t.Fatalf("ack error code = %d, want success=%v", ack.ErrorCode, ok)
+	}
+	return binary.BigEndian.Uint32(ack.Payload)
+}
+
+func expectNoPacket(t *testing.T, session *Session) {
+	t.Helper()
+	select {
+	case p := <-session.sendPackets:
+		t.Fatalf("unexpected packet queued: %x", p.data)
+	default:
+	}
+}
 
 func TestHandleMsgSysCreateMutex(t *testing.T) {
 	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysCreateMutex panicked: %v", r)
-		}
-	}()
-
-	handleMsgSysCreateMutex(session, nil)
+	a := createMockSession(1, server)
+	b := createMockSession(2, server)
+
+	handleMsgSysCreateMutex(a, &mhfpacket.MsgSysCreateMutex{AckHandle: 1, MutexName: "guild"})
+	id := expectMutexAck(t, a, 1, true)
+	if id == 0 {
+		t.Fatal("mutex ID should be non-zero")
+	}
+
+	// Creating an existing mutex joins it rather than allocating a new one.
+	handleMsgSysCreateMutex(b, &mhfpacket.MsgSysCreateMutex{AckHandle: 2, MutexName: "guild"})
+	if got := expectMutexAck(t, b, 2, true); got != id {
+		t.Errorf("second create ID = %d, want %d", got, id)
+	}
+	if m := server.mutexes["guild"]; len(m.members) != 2 || m.owner != nil {
+		t.Errorf("members = %d, owner = %v; want 2 members and no owner", len(m.members), m.owner)
+	}
 }
 
 func TestHandleMsgSysCreateOpenMutex(t *testing.T) {
 	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysCreateOpenMutex panicked: %v", r)
-		}
-	}()
-
-	handleMsgSysCreateOpenMutex(session, nil)
+	a := createMockSession(1, server)
+	b := createMockSession(2, server)
+
+	handleMsgSysCreateOpenMutex(a, &mhfpacket.MsgSysCreateOpenMutex{AckHandle: 1, MutexName: "house"})
+	expectMutexAck(t, a, 1, true)
+	if server.mutexes["house"].owner != a {
+		t.Fatal("creator should own the mutex")
+	}
+
+	// A second opener is queued and not acked until ownership passes.
+	handleMsgSysCreateOpenMutex(b, &mhfpacket.MsgSysCreateOpenMutex{AckHandle: 2, MutexName: "house"})
+	expectNoPacket(t, b)
+	if len(server.mutexes["house"].waiters) != 1 {
+		t.Fatalf("waiters = %d, want 1", len(server.mutexes["house"].waiters))
+	}
 }
 
-func TestHandleMsgSysDeleteMutex(t *testing.T) {
+func TestHandleMsgSysOpenMutex(t *testing.T) {
 	server := createMockServer()
-	session := createMockSession(1, server)
+	a := createMockSession(1, server)
 
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysDeleteMutex panicked: %v", r)
-		}
-	}()
+	handleMsgSysOpenMutex(a, &mhfpacket.MsgSysOpenMutex{AckHandle: 1, MutexName: "missing"})
+	expectMutexAck(t, a, 1, false)
+
+	handleMsgSysCreateMutex(a, &mhfpacket.MsgSysCreateMutex{AckHandle: 2, MutexName: "m"})
+	id := expectMutexAck(t, a, 2, true)
+	handleMsgSysOpenMutex(a, &mhfpacket.MsgSysOpenMutex{AckHandle: 3, MutexName: "m"})
+	if got := expectMutexAck(t, a, 3, true); got != id {
+		t.Errorf("open ID = %d, want %d", got, id)
+	}
 
-	handleMsgSysDeleteMutex(session, nil)
+	// Re-opening a mutex the session already owns succeeds immediately.
+	handleMsgSysOpenMutex(a, &mhfpacket.MsgSysOpenMutex{AckHandle: 4, MutexName: "m"})
+	expectMutexAck(t, a, 4, true)
 }
 
-func TestHandleMsgSysOpenMutex(t *testing.T) {
+func TestHandleMsgSysCloseMutex(t *testing.T) {
 	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysOpenMutex panicked: %v", r)
+	a := createMockSession(1, server)
+	b := createMockSession(2, server)
+	c := createMockSession(3, server)
+
+	handleMsgSysCreateOpenMutex(a, &mhfpacket.MsgSysCreateOpenMutex{AckHandle: 1, MutexName: "m"})
+	id := expectMutexAck(t, a, 1, true)
+	handleMsgSysCreateMutex(c, &mhfpacket.MsgSysCreateMutex{AckHandle: 1, MutexName: "m"})
+	expectMutexAck(t, c, 1, true)
+	handleMsgSysOpenMutex(b, &mhfpacket.MsgSysOpenMutex{AckHandle: 7, MutexName: "m"})
+	expectNoPacket(t, b)
+
+	// Only the owner may close.
+	handleMsgSysCloseMutex(c, &mhfpacket.MsgSysCloseMutex{AckHandle: 2, MutexName: "m"})
+	expectMutexAck(t, c, 2, false)
+
+	handleMsgSysCloseMutex(a, &mhfpacket.MsgSysCloseMutex{AckHandle: 3, MutexName: "m"})
+	if got := expectMutexAck(t, b, 7, true); got != id {
+		t.Errorf("deferred open ID = %d, want %d", got, id)
+	}
+	if server.mutexes["m"].owner != b {
+		t.Fatal("ownership should pass to the queued waiter")
+	}
+
+	// The other members are told about the new owner before the close is
+	// acked.
+	for _, session := range []*Session{a, c} {
+		select {
+		case p := <-session.sendPackets:
+			if op := network.PacketID(binary.BigEndian.Uint16(p.data)); op != network.MSG_SYS_OPEN_MUTEX {
+				t.Errorf("charID %d notified with %s, want MSG_SYS_OPEN_MUTEX", session.charID, op)
+			}
+		default:
+			t.Errorf("charID %d was not notified of the ownership change", session.charID)
 		}
-	}()
-
-	handleMsgSysOpenMutex(session, nil)
+	}
+	expectMutexAck(t, a, 3, true)
+	expectNoPacket(t, b)
 }
 
-func TestHandleMsgSysCloseMutex(t *testing.T) {
+func TestHandleMsgSysCloseMutex_CancelsQueuedOpen(t *testing.T) {
 	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysCloseMutex panicked: %v", r)
-		}
-	}()
+	a := createMockSession(1, server)
+	b := createMockSession(2, server)
+
+	handleMsgSysCreateOpenMutex(a, &mhfpacket.MsgSysCreateOpenMutex{AckHandle: 1, MutexName: "m"})
+	expectMutexAck(t, a, 1, true)
+	handleMsgSysOpenMutex(b, &mhfpacket.MsgSysOpenMutex{AckHandle: 5, MutexName: "m"})
+
+	handleMsgSysCloseMutex(b, &mhfpacket.MsgSysCloseMutex{AckHandle: 6, MutexName: "m"})
+	expectMutexAck(t, b, 6, true)
+	expectMutexAck(t, b, 5, false)
+	if m := server.mutexes["m"]; m.owner != a || len(m.waiters) != 0 {
+		t.Errorf("owner = %v, waiters = %d; want a and 0", m.owner, len(m.waiters))
+	}
+}
 
-	handleMsgSysCloseMutex(session, nil)
+func TestHandleMsgSysDeleteMutex(t *testing.T) {
+	server := createMockServer()
+	a := createMockSession(1, server)
+	b := createMockSession(2, server)
+
+	handleMsgSysDeleteMutex(a, &mhfpacket.MsgSysDeleteMutex{AckHandle: 1, MutexName: "missing"})
+	expectMutexAck(t, a, 1, false)
+
+	handleMsgSysCreateOpenMutex(a, &mhfpacket.MsgSysCreateOpenMutex{AckHandle: 2, MutexName: "m"})
+	expectMutexAck(t, a, 2, true)
+	handleMsgSysOpenMutex(b, &mhfpacket.MsgSysOpenMutex{AckHandle: 3, MutexName: "m"})
+
+	// A mutex held by someone else cannot be deleted.
+	handleMsgSysDeleteMutex(b, &mhfpacket.MsgSysDeleteMutex{AckHandle: 4, MutexName: "m"})
+	expectMutexAck(t, b, 4, false)
+
+	handleMsgSysDeleteMutex(a, &mhfpacket.MsgSysDeleteMutex{AckHandle: 5, MutexName: "m"})
+	expectMutexAck(t, a, 5, true)
+	expectMutexAck(t, b, 3, false)
+	if _, exists := server.mutexes["m"]; exists {
+		t.Error("mutex should be removed from the registry")
+	}
 }
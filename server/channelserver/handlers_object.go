@@ -18,7 +18,7 @@ func handleMsgSysCreateObject(s *Session, p mhfpacket.MHFPacket) {
 		y:           pkt.Y,
 		z:           pkt.Z,
 	}
-	s.stage.objects[s.charID] = newObj
+	s.stage.objects[newObj.id] = newObj
 	s.stage.Unlock()
 
 	// Response to our requesting client.
@@ -38,27 +38,28 @@ func handleMsgSysCreateObject(s *Session, p mhfpacket.MHFPacket) {
 	s.stage.BroadcastMHF(dupObjUpdate, s)
 }
 
-// handleMsgSysDeleteObject removes the sender's own synced stage object and
-// relays the deletion to the rest of the stage, mirroring the same
+// handleMsgSysDeleteObject removes one of the sender's synced stage objects
+// and relays the deletion to the rest of the stage, mirroring the same
 // remove-then-broadcast pattern already used server-side when a client
-// leaves a stage (see removeSessionFromStage). A client may only delete the
-// object it owns -- the requested ObjID must match the object on record for
-// the sender's charID, or the request is dropped.
+// leaves a stage (see removeSessionFromStage). A client may only delete
+// objects it owns; requests for anyone else's ObjID are dropped.
 func handleMsgSysDeleteObject(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgSysDeleteObject)
-
-	s.stage.Lock()
-	object, ok := s.stage.objects[s.charID]
-	if ok && object.id == pkt.ObjID {
-		delete(s.stage.objects, s.charID)
-	} else {
-		ok = false
+	if deleteOwnedObject(s, pkt.ObjID) {
+		s.stage.BroadcastMHF(pkt, s)
 	}
-	s.stage.Unlock()
+}
 
-	if ok {
-		s.stage.BroadcastMHF(pkt, s)
+// deleteOwnedObject removes objID from the sender's stage if the sender owns
+// it, and reports whether it did.
+func deleteOwnedObject(s *Session, objID uint32) bool {
+	s.stage.Lock()
+	defer s.stage.Unlock()
+	if s.stage.ownedObject(s.charID, objID) == nil {
+		return false
 	}
+	delete(s.stage.objects, objID)
+	return true
 }
 
 func handleMsgSysPositionObject(s *Session, p mhfpacket.MHFPacket) {
@@ -73,8 +74,7 @@ func handleMsgSysPositionObject(s *Session, p mhfpacket.MHFPacket) {
 		)
 	}
 	s.stage.Lock()
-	object, ok := s.stage.objects[s.charID]
-	if ok {
+	if object := s.stage.ownedObject(s.charID, pkt.ObjID); object != nil {
 		object.x = pkt.X
 		object.y = pkt.Y
 		object.z = pkt.Z
@@ -91,8 +91,7 @@ func handleMsgSysRotateObject(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgSysRotateObject)
 
 	s.stage.Lock()
-	object, ok := s.stage.objects[s.charID]
-	if ok {
+	if object := s.stage.ownedObject(s.charID, pkt.ObjID); object != nil {
 		object.rotation = pkt.Rotation
 	}
 	s.stage.Unlock()
@@ -100,34 +99,79 @@ func handleMsgSysRotateObject(s *Session, p mhfpacket.MHFPacket) {
 	s.stage.BroadcastMHF(pkt, s)
 }
 
-func handleMsgSysDuplicateObject(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+// handleMsgSysDuplicateObject copies one of the stage's objects to a new
+// position under a fresh handle owned by the sender. There is no ack, so the
+// resulting MsgSysDuplicateObject is broadcast to the whole stage, sender
+// included, which is how the requesting client learns the new handle.
+func handleMsgSysDuplicateObject(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysDuplicateObject)
+
+	s.stage.Lock()
+	source, ok := s.stage.objects[pkt.ObjID]
+	if !ok {
+		s.stage.Unlock()
+		return
+	}
+	newObj := &Object{
+		id:          s.getObjectId(),
+		ownerCharID: s.charID,
+		x:           pkt.X,
+		y:           pkt.Y,
+		z:           pkt.Z,
+		rotation:    source.rotation,
+		hidden:      source.hidden,
+		binary:      source.binary,
+	}
+	s.stage.objects[newObj.id] = newObj
+	s.stage.Unlock()
 
+	s.stage.BroadcastMHF(&mhfpacket.MsgSysDuplicateObject{
+		ObjID:       newObj.id,
+		X:           newObj.x,
+		Y:           newObj.y,
+		Z:           newObj.z,
+		Unk0:        pkt.Unk0,
+		OwnerCharID: newObj.ownerCharID,
+	}, nil)
+}
+
+// handleMsgSysSetObjectBinary stores the binary state of one of the sender's
+// objects and tells the rest of the stage it changed, so they can fetch it
+// with MSG_SYS_GET_OBJECT_BINARY. The payload is kept verbatim: it is
+// produced and consumed by the same client build, so the PS3's byte order
+// never needs to be interpreted here.
 func handleMsgSysSetObjectBinary(s *Session, p mhfpacket.MHFPacket) {
-	_ = p.(*mhfpacket.MsgSysSetObjectBinary)
-	/* This causes issues with PS3 as this actually sends with endiness!
-	for _, session := range s.server.sessions {
-		if session.charID == s.charID {
-			s.server.userBinary.Set(s.charID, 3, pkt.RawDataPayload)
-			msg := &mhfpacket.MsgSysNotifyUserBinary{
-				CharID:     s.charID,
-				BinaryType: 3,
-			}
-			s.server.BroadcastMHF(msg, s)
-		}
+	pkt := p.(*mhfpacket.MsgSysSetObjectBinary)
+
+	s.stage.Lock()
+	object := s.stage.ownedObject(s.charID, pkt.ObjID)
+	if object != nil {
+		object.binary = pkt.RawDataPayload
+	}
+	s.stage.Unlock()
+
+	if object != nil {
+		s.stage.BroadcastMHF(&mhfpacket.MsgSysUpdateObjectBinary{ObjectHandleID: pkt.ObjID}, s)
 	}
-	*/
 }
 
 // handleMsgSysGetObjectBinary answers a request for another stage object's
-// synced binary state. Erupe doesn't persist per-object binary payloads yet
-// (see handleMsgSysSetObjectBinary's PS3-endianness caveat above), so this
-// always acks a zero-length result -- the same "not found" shape the PC
+// synced binary state. Objects that never had a binary set, or that no
+// longer exist, ack a zero-length result -- the same "not found" shape the PC
 // client itself falls back to when its local lookup misses (decompiled
 // pkt_handler_MSG_SYS_GET_OBJECT_BINARY replies with a zero-length payload
 // rather than an error ack), so a real client handles this gracefully.
 func handleMsgSysGetObjectBinary(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgSysGetObjectBinary)
-	doAckBufSucceed(s, pkt.AckHandle, []byte{})
+
+	data := []byte{}
+	s.stage.RLock()
+	if object, ok := s.stage.objects[pkt.ObjID]; ok && object.binary != nil {
+		data = object.binary
+	}
+	s.stage.RUnlock()
+
+	doAckBufSucceed(s, pkt.AckHandle, data)
 }
 
 // handleMsgSysGetObjectOwner answers a request for the owning character of a
@@ -138,11 +182,8 @@ func handleMsgSysGetObjectOwner(s *Session, p mhfpacket.MHFPacket) {
 
 	var ownerCharID uint32
 	s.stage.RLock()
-	for _, object := range s.stage.objects {
-		if object.id == pkt.ObjID {
-			ownerCharID = object.ownerCharID
-			break
-		}
+	if object, ok := s.stage.objects[pkt.ObjID]; ok {
+		ownerCharID = object.ownerCharID
 	}
 	s.stage.RUnlock()
 
@@ -151,14 +192,95 @@ func handleMsgSysGetObjectOwner(s *Session, p mhfpacket.MHFPacket) {
 	doAckBufSucceed(s, pkt.AckHandle, resp.Data())
 }
 
-func handleMsgSysUpdateObjectBinary(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+// handleMsgSysUpdateObjectBinary relays an owner's "binary changed" notice
+// for one of its objects to the rest of the stage.
+func handleMsgSysUpdateObjectBinary(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysUpdateObjectBinary)
+
+	s.stage.RLock()
+	owned := s.stage.ownedObject(s.charID, pkt.ObjectHandleID) != nil
+	s.stage.RUnlock()
+
+	if owned {
+		s.stage.BroadcastMHF(pkt, s)
+	}
+}
+
+// handleMsgSysCleanupObject removes every object the sender owns in its
+// current stage, e.g. when a client resets its scene without leaving.
+func handleMsgSysCleanupObject(s *Session, p mhfpacket.MHFPacket) {
+	s.stage.Lock()
+	removed := s.stage.removeObjectsOwnedBy(s.charID)
+	s.stage.Unlock()
+
+	for _, object := range removed {
+		s.stage.BroadcastMHF(&mhfpacket.MsgSysDeleteObject{ObjID: object.id}, s)
+	}
+}
+
+// handleMsgSysAddObject registers an object under a handle the client
+// assigned itself. Handles already in use on the stage are rejected so a
+// client cannot take over someone else's object.
+func handleMsgSysAddObject(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysAddObject)
+
+	s.stage.Lock()
+	_, exists := s.stage.objects[pkt.ObjID]
+	if !exists {
+		s.stage.objects[pkt.ObjID] = &Object{
+			id:          pkt.ObjID,
+			ownerCharID: s.charID,
+			x:           pkt.X,
+			y:           pkt.Y,
+			z:           pkt.Z,
+		}
+	}
+	s.stage.Unlock()
 
-func handleMsgSysCleanupObject(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+	if !exists {
+		s.stage.BroadcastMHF(&mhfpacket.MsgSysDuplicateObject{
+			ObjID:       pkt.ObjID,
+			X:           pkt.X,
+			Y:           pkt.Y,
+			Z:           pkt.Z,
+			OwnerCharID: s.charID,
+		}, s)
+	}
+}
 
-func handleMsgSysAddObject(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+// handleMsgSysDelObject is the no-ack counterpart of handleMsgSysAddObject
+// and shares handleMsgSysDeleteObject's ownership rule.
+func handleMsgSysDelObject(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysDelObject)
+	if deleteOwnedObject(s, pkt.ObjID) {
+		s.stage.BroadcastMHF(&mhfpacket.MsgSysDeleteObject{ObjID: pkt.ObjID}, s)
+	}
+}
 
-func handleMsgSysDelObject(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+func handleMsgSysDispObject(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysDispObject)
+	if setObjectHidden(s, pkt.ObjID, false) {
+		s.stage.BroadcastMHF(pkt, s)
+	}
+}
 
-func handleMsgSysDispObject(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+func handleMsgSysHideObject(s *Session, p mhfpacket.MHFPacket) {
+	pkt := p.(*mhfpacket.MsgSysHideObject)
+	if setObjectHidden(s, pkt.ObjID, true) {
+		s.stage.BroadcastMHF(pkt, s)
+	}
+}
 
-func handleMsgSysHideObject(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
+// setObjectHidden updates the visibility of one of the sender's objects and
+// reports whether the sender owns it. Players who enter the stage later
+// receive a MsgSysHideObject after the object's duplicate.
+func setObjectHidden(s *Session, objID uint32, hidden bool) bool {
+	s.stage.Lock()
+	defer s.stage.Unlock()
+	object := s.stage.ownedObject(s.charID, objID)
+	if object == nil {
+		return false
+	}
+	object.hidden = hidden
+	return true
+}
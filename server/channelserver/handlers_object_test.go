@@ -3,6 +3,8 @@ package channelserver
 import (
 	"testing"
 
+	"erupe-ce/common/byteframe"
+	"erupe-ce/network"
 	"erupe-ce/network/mhfpacket"
 )
 
@@ -49,6 +51,7 @@ func TestHandleMsgSysCreateObject_MultipleObjects(t *testing.T) {
 
 	for i := 0; i < 3; i++ {
 		sessions[i] = createMockSession(uint32(i+1), server)
+		sessions[i].objectID = uint16(i + 1) // Normally assigned by NewSession.
 		sessions[i].stage = stage
 
 		pkt := &mhfpacket.MsgSysCreateObject{
@@ -88,7 +91,7 @@ func TestHandleMsgSysPositionObject(t *testing.T) {
 	stage.clients[session2] = session2.charID
 
 	// Create an object
-	stage.objects[session.charID] = &Object{
+	stage.objects[1] = &Object{
 		id:          1,
 		ownerCharID: session.charID,
 		x:           0,
@@ -106,7 +109,7 @@ func TestHandleMsgSysPositionObject(t *testing.T) {
 	handleMsgSysPositionObject(session, pkt)
 
 	// Verify object position was updated
-	obj := stage.objects[session.charID]
+	obj := stage.objects[1]
 	if obj.x != 100.0 || obj.y != 200.0 || obj.z != 300.0 {
 		t.Errorf("Object position not updated: got (%f, %f, %f), want (100, 200, 300)",
 			obj.x, obj.y, obj.z)
@@ -159,14 +162,14 @@ func TestHandleMsgSysDeleteObject(t *testing.T) {
 	stage.clients[session] = session.charID
 	stage.clients[session2] = session2.charID
 
-	stage.objects[session.charID] = &Object{
+	stage.objects[1] = &Object{
 		id:          1,
 		ownerCharID: session.charID,
 	}
 
 	handleMsgSysDeleteObject(session, &mhfpacket.MsgSysDeleteObject{ObjID: 1})
 
-	if _, ok := stage.objects[session.charID]; ok {
+	if _, ok := stage.objects[1]; ok {
 		t.Error("object should have been removed from the stage")
 	}
 
@@ -186,14 +189,14 @@ func TestHandleMsgSysDeleteObject_WrongObjID(t *testing.T) {
 
 	stage := NewStage("test_stage")
 	session.stage = stage
-	stage.objects[session.charID] = &Object{
+	stage.objects[1] = &Object{
 		id:          1,
 		ownerCharID: session.charID,
 	}
 
 	handleMsgSysDeleteObject(session, &mhfpacket.MsgSysDeleteObject{ObjID: 999})
 
-	if _, ok := stage.objects[session.charID]; !ok {
+	if _, ok := stage.objects[1]; !ok {
 		t.Error("object should not have been removed for a mismatched ObjID")
 	}
 }
@@ -210,7 +213,7 @@ func TestHandleMsgSysRotateObject(t *testing.T) {
 	stage.clients[session] = session.charID
 	stage.clients[session2] = session2.charID
 
-	stage.objects[session.charID] = &Object{
+	stage.objects[1] = &Object{
 		id:          1,
 		ownerCharID: session.charID,
 	}
@@ -218,7 +221,7 @@ func TestHandleMsgSysRotateObject(t *testing.T) {
 	pkt := &mhfpacket.MsgSysRotateObject{ObjID: 1, Rotation: 1.5707963}
 	handleMsgSysRotateObject(session, pkt)
 
-	obj := stage.objects[session.charID]
+	obj := stage.objects[1]
 	if obj.rotation != 1.5707963 {
 		t.Errorf("Object rotation not updated: got %f, want 1.5707963", obj.rotation)
 	}
@@ -248,20 +251,6 @@ func TestHandleMsgSysRotateObject_NoObject(t *testing.T) {
 	handleMsgSysRotateObject(session, &mhfpacket.MsgSysRotateObject{ObjID: 999, Rotation: 0})
 }
 
-func TestHandleMsgSysDuplicateObject(t *testing.T) {
-	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysDuplicateObject panicked: %v", r)
-		}
-	}()
-
-	handleMsgSysDuplicateObject(session, nil)
-}
-
 func TestHandleMsgSysGetObjectBinary(t *testing.T) {
 	server := createMockServer()
 	session := createMockSession(1, server)
@@ -286,7 +275,7 @@ func TestHandleMsgSysGetObjectOwner(t *testing.T) {
 
 	stage := NewStage("test_stage")
 	session.stage = stage
-	stage.objects[session.charID] = &Object{
+	stage.objects[1] = &Object{
 		id:          1,
 		ownerCharID: session.charID,
 	}
@@ -319,90 +308,6 @@ func TestHandleMsgSysGetObjectOwner_NotFound(t *testing.T) {
 	handleMsgSysGetObjectOwner(session, &mhfpacket.MsgSysGetObjectOwner{AckHandle: 42, ObjID: 999})
 }
 
-func TestHandleMsgSysUpdateObjectBinary(t *testing.T) {
-	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysUpdateObjectBinary panicked: %v", r)
-		}
-	}()
-
-	handleMsgSysUpdateObjectBinary(session, nil)
-}
-
-func TestHandleMsgSysCleanupObject(t *testing.T) {
-	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysCleanupObject panicked: %v", r)
-		}
-	}()
-
-	handleMsgSysCleanupObject(session, nil)
-}
-
-func TestHandleMsgSysAddObject(t *testing.T) {
-	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysAddObject panicked: %v", r)
-		}
-	}()
-
-	handleMsgSysAddObject(session, nil)
-}
-
-func TestHandleMsgSysDelObject(t *testing.T) {
-	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysDelObject panicked: %v", r)
-		}
-	}()
-
-	handleMsgSysDelObject(session, nil)
-}
-
-func TestHandleMsgSysDispObject(t *testing.T) {
-	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysDispObject panicked: %v", r)
-		}
-	}()
-
-	handleMsgSysDispObject(session, nil)
-}
-
-func TestHandleMsgSysHideObject(t *testing.T) {
-	server := createMockServer()
-	session := createMockSession(1, server)
-
-	// Should not panic (empty handler)
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("handleMsgSysHideObject panicked: %v", r)
-		}
-	}()
-
-	handleMsgSysHideObject(session, nil)
-}
-
 func TestObjectHandlers_SequentialCreateObject(t *testing.T) {
 	server := createMockServer()
 	stage := NewStage("test_stage")
@@ -411,6 +316,7 @@ func TestObjectHandlers_SequentialCreateObject(t *testing.T) {
 	// Test sequential object creation across multiple sessions
 	for i := 0; i < 10; i++ {
 		session := createMockSession(uint32(i), server)
+		session.objectID = uint16(i + 1)
 		session.stage = stage
 
 		pkt := &mhfpacket.MsgSysCreateObject{
@@ -444,7 +350,7 @@ func TestObjectHandlers_SequentialPositionUpdate(t *testing.T) {
 	stage.clients[session] = session.charID
 
 	// Create an object
-	stage.objects[session.charID] = &Object{
+	stage.objects[1] = &Object{
 		id:          1,
 		ownerCharID: session.charID,
 		x:           0,
@@ -465,62 +371,215 @@ func TestObjectHandlers_SequentialPositionUpdate(t *testing.T) {
 	}
 
 	// Verify final position
-	obj := stage.objects[session.charID]
+	obj := stage.objects[1]
 	if obj.x != 9 || obj.y != 18 || obj.z != 27 {
 		t.Errorf("Object position not as expected: got (%f, %f, %f), want (9, 18, 27)",
 			obj.x, obj.y, obj.z)
 	}
 }
 
-// Tests consolidated from handlers_coverage3_test.go
+// newObjectTestStage returns a stage holding an owner session and one
+// observer, with object 1 owned by the owner.
+func newObjectTestStage(server *Server) (stage *Stage, owner, observer *Session) {
+	stage = NewStage("test_stage")
+	owner = createMockSession(1, server)
+	observer = createMockSession(2, server)
+	for i, session := range []*Session{owner, observer} {
+		session.objectID = uint16(i + 1) // Normally assigned by NewSession.
+		session.stage = stage
+		stage.clients[session] = session.charID
+	}
+	stage.objects[1] = &Object{id: 1, ownerCharID: owner.charID}
+	return stage, owner, observer
+}
+
+// expectBroadcast checks that session was sent exactly one packet with the
+// given opcode.
+func expectBroadcast(t *testing.T, session *Session, op network.PacketID) *byteframe.ByteFrame {
+	t.Helper()
+	select {
+	case p := <-session.sendPackets:
+		bf := byteframe.NewByteFrameFromBytes(p.data)
+		if got := network.PacketID(bf.ReadUint16()); got != op {
+			t.Fatalf("charID %d received %s, want %s", session.charID, got, op)
+		}
+		return bf
+	default:
+		t.Fatalf("charID %d received nothing, want %s", session.charID, op)
+		return nil
+	}
+}
 
-func TestEmptyHandlers_ObjectGo(t *testing.T) {
+func TestHandleMsgSysCreateObject_SameOwner(t *testing.T) {
 	server := createMockServer()
 	session := createMockSession(1, server)
+	stage := NewStage("test_stage")
+	session.stage = stage
 
-	tests := []struct {
-		name string
-		fn   func()
-	}{
-		{"handleMsgSysDuplicateObject", func() { handleMsgSysDuplicateObject(session, nil) }},
-		{"handleMsgSysUpdateObjectBinary", func() { handleMsgSysUpdateObjectBinary(session, nil) }},
-		{"handleMsgSysCleanupObject", func() { handleMsgSysCleanupObject(session, nil) }},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					t.Errorf("%s panicked: %v", tt.name, r)
-				}
-			}()
-			tt.fn()
-		})
+	handleMsgSysCreateObject(session, &mhfpacket.MsgSysCreateObject{AckHandle: 1})
+	handleMsgSysCreateObject(session, &mhfpacket.MsgSysCreateObject{AckHandle: 2})
+
+	if len(stage.objects) != 2 {
+		t.Errorf("Stage should keep both objects of one owner, got %d", len(stage.objects))
 	}
 }
 
-func TestEmptyHandlers_MiscFiles_Object(t *testing.T) {
+func TestHandleMsgSysDuplicateObject(t *testing.T) {
 	server := createMockServer()
-	session := createMockSession(1, server)
+	stage, owner, observer := newObjectTestStage(server)
+	stage.objects[1].rotation = 2
+	stage.objects[1].binary = []byte{0xAA}
+
+	handleMsgSysDuplicateObject(observer, &mhfpacket.MsgSysDuplicateObject{ObjID: 1, X: 5, Y: 6, Z: 7})
+
+	if len(stage.objects) != 2 {
+		t.Fatalf("Stage should have 2 objects, got %d", len(stage.objects))
+	}
+	var dup *Object
+	for id, obj := range stage.objects {
+		if id != 1 {
+			dup = obj
+		}
+	}
+	if dup.ownerCharID != observer.charID || dup.x != 5 || dup.rotation != 2 || len(dup.binary) != 1 {
+		t.Errorf("duplicate = %+v, want owner %d at x=5 with the source's rotation and binary", dup, observer.charID)
+	}
+
+	// The duplicator learns the new handle from the broadcast too.
+	for _, session := range []*Session{owner, observer} {
+		bf := expectBroadcast(t, session, network.MSG_SYS_DUPLICATE_OBJECT)
+		if id := bf.ReadUint32(); id != dup.id {
+			t.Errorf("charID %d told about object %d, want %d", session.charID, id, dup.id)
+		}
+	}
+}
+
+func TestHandleMsgSysDuplicateObject_UnknownSource(t *testing.T) {
+	server := createMockServer()
+	stage, owner, _ := newObjectTestStage(server)
+
+	handleMsgSysDuplicateObject(owner, &mhfpacket.MsgSysDuplicateObject{ObjID: 999})
+
+	if len(stage.objects) != 1 {
+		t.Errorf("Stage should still have 1 object, got %d", len(stage.objects))
+	}
+}
+
+func TestHandleMsgSysObjectBinary_SetUpdateGet(t *testing.T) {
+	server := createMockServer()
+	stage, owner, observer := newObjectTestStage(server)
+
+	handleMsgSysSetObjectBinary(owner, &mhfpacket.MsgSysSetObjectBinary{ObjID: 1, RawDataPayload: []byte{1, 2, 3}})
+	if got := stage.objects[1].binary; len(got) != 3 {
+		t.Fatalf("binary = %x, want 010203", got)
+	}
+	expectBroadcast(t, observer, network.MSG_SYS_UPDATE_OBJECT_BINARY)
+
+	handleMsgSysUpdateObjectBinary(owner, &mhfpacket.MsgSysUpdateObjectBinary{ObjectHandleID: 1})
+	expectBroadcast(t, observer, network.MSG_SYS_UPDATE_OBJECT_BINARY)
+
+	handleMsgSysGetObjectBinary(observer, &mhfpacket.MsgSysGetObjectBinary{AckHandle: 9, ObjID: 1})
+	if ack := readAck(t, observer); ack.AckHandle != 9 || string(ack.Payload) != "\x01\x02\x03" {
+		t.Errorf("ack = %+v, want handle 9 with payload 010203", ack)
+	}
+}
+
+func TestHandleMsgSysObjectBinary_NotOwner(t *testing.T) {
+	server := createMockServer()
+	stage, owner, observer := newObjectTestStage(server)
+
+	handleMsgSysSetObjectBinary(observer, &mhfpacket.MsgSysSetObjectBinary{ObjID: 1, RawDataPayload: []byte{1}})
+	handleMsgSysUpdateObjectBinary(observer, &mhfpacket.MsgSysUpdateObjectBinary{ObjectHandleID: 1})
+
+	if stage.objects[1].binary != nil {
+		t.Error("a non-owner should not be able to set an object's binary")
+	}
+	expectNoPacket(t, owner)
+}
+
+func TestHandleMsgSysCleanupObject(t *testing.T) {
+	server := createMockServer()
+	stage, owner, observer := newObjectTestStage(server)
+	stage.objects[2] = &Object{id: 2, ownerCharID: owner.charID}
+	stage.objects[3] = &Object{id: 3, ownerCharID: observer.charID}
+
+	handleMsgSysCleanupObject(owner, &mhfpacket.MsgSysCleanupObject{})
+
+	if len(stage.objects) != 1 || stage.objects[3] == nil {
+		t.Errorf("only the observer's object should remain, got %d objects", len(stage.objects))
+	}
+	expectBroadcast(t, observer, network.MSG_SYS_DELETE_OBJECT)
+	expectBroadcast(t, observer, network.MSG_SYS_DELETE_OBJECT)
+	expectNoPacket(t, owner)
+}
+
+func TestHandleMsgSysAddObject(t *testing.T) {
+	server := createMockServer()
+	stage, owner, observer := newObjectTestStage(server)
+
+	handleMsgSysAddObject(observer, &mhfpacket.MsgSysAddObject{ObjID: 50, X: 1, Y: 2, Z: 3})
+	if obj := stage.objects[50]; obj == nil || obj.ownerCharID != observer.charID || obj.z != 3 {
+		t.Fatalf("object 50 = %+v, want owned by %d at z=3", obj, observer.charID)
+	}
+	expectBroadcast(t, owner, network.MSG_SYS_DUPLICATE_OBJECT)
+
+	// An existing handle cannot be claimed.
+	handleMsgSysAddObject(observer, &mhfpacket.MsgSysAddObject{ObjID: 1})
+	if stage.objects[1].ownerCharID != owner.charID {
+		t.Error("adding an existing handle should not change its owner")
+	}
+	expectNoPacket(t, owner)
+}
+
+func TestHandleMsgSysDelObject(t *testing.T) {
+	server := createMockServer()
+	stage, owner, observer := newObjectTestStage(server)
+
+	handleMsgSysDelObject(observer, &mhfpacket.MsgSysDelObject{ObjID: 1})
+	if stage.objects[1] == nil {
+		t.Fatal("a non-owner should not be able to delete the object")
+	}
+
+	handleMsgSysDelObject(owner, &mhfpacket.MsgSysDelObject{ObjID: 1})
+	if stage.objects[1] != nil {
+		t.Error("object should have been removed from the stage")
+	}
+	expectBroadcast(t, observer, network.MSG_SYS_DELETE_OBJECT)
+}
+
+func TestHandleMsgSysDispHideObject(t *testing.T) {
+	server := createMockServer()
+	stage, owner, observer := newObjectTestStage(server)
+
+	handleMsgSysHideObject(owner, &mhfpacket.MsgSysHideObject{ObjID: 1})
+	if !stage.objects[1].hidden {
+		t.Fatal("object should be hidden")
+	}
+	expectBroadcast(t, observer, network.MSG_SYS_HIDE_OBJECT)
+
+	handleMsgSysDispObject(observer, &mhfpacket.MsgSysDispObject{ObjID: 1})
+	if !stage.objects[1].hidden {
+		t.Fatal("a non-owner should not be able to show the object")
+	}
+	expectNoPacket(t, owner)
+
+	handleMsgSysDispObject(owner, &mhfpacket.MsgSysDispObject{ObjID: 1})
+	if stage.objects[1].hidden {
+		t.Error("object should be visible again")
+	}
+	expectBroadcast(t, observer, network.MSG_SYS_DISP_OBJECT)
+}
+
+func TestRemoveSessionFromStage_RemovesAllOwnedObjects(t *testing.T) {
+	server := createMockServer()
+	stage, owner, observer := newObjectTestStage(server)
+	stage.objects[2] = &Object{id: 2, ownerCharID: owner.charID}
+
+	removeSessionFromStage(owner)
 
-	tests := []struct {
-		name string
-		fn   func()
-	}{
-		{"handleMsgSysAddObject", func() { handleMsgSysAddObject(session, nil) }},
-		{"handleMsgSysDelObject", func() { handleMsgSysDelObject(session, nil) }},
-		{"handleMsgSysDispObject", func() { handleMsgSysDispObject(session, nil) }},
-		{"handleMsgSysHideObject", func() { handleMsgSysHideObject(session, nil) }},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			defer func() {
-				if r := recover(); r != nil {
-					t.Errorf("%s panicked: %v", tt.name, r)
-				}
-			}()
-			tt.fn()
-		})
+	if len(stage.objects) != 0 {
+		t.Errorf("owner's objects should be cleaned up, %d left", len(stage.objects))
 	}
+	expectBroadcast(t, observer, network.MSG_SYS_DELETE_OBJECT)
+	expectBroadcast(t, observer, network.MSG_SYS_DELETE_OBJECT)
 }
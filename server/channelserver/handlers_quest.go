@@ -14,6 +14,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"go.uber.org/zap"
@@ -151,10 +152,51 @@ func handleMsgSysGetFile(s *Session, p mhfpacket.MHFPacket) {
 		if s.server.erupeConfig.RealClientMode <= cfg.Z1 && s.server.erupeConfig.DebugOptions.AutoQuestBackport {
 			data = BackportQuest(decryption.UnpackSimple(data), s.server.erupeConfig.RealClientMode)
 		}
+		s.Lock()
+		s.questFile = pkt.Filename
+		s.Unlock()
 		doAckBufSucceed(s, pkt.AckHandle, data)
 	}
 }
 
+// fortAttackSortie counts a sortie when the quest being entered belongs to
+// a running fort attack, reporting false if the event's restrictions refuse
+// the character. No packet names the quest on entry, so callers pass the
+// quest file the session last loaded.
+func fortAttackSortie(s *Session, filename string) bool {
+	if !s.server.erupeConfig.FortAttack.Enabled || len(filename) < 5 {
+		return true
+	}
+	// Quest filenames are formatted as [5-digit ID][d/n][season].
+	questID, err := strconv.ParseUint(filename[:5], 10, 16)
+	if err != nil {
+		return true
+	}
+	event, err := s.server.fortAttackService.ActiveFor(TimeAdjusted(), uint16(questID))
+	if err != nil {
+		s.logger.Error("Failed to get fort attack", zap.Error(err))
+		return true
+	}
+	if event == nil || s.fortSortie == event.ID {
+		return true
+	}
+	hr, err := s.server.charRepo.ReadInt(s.charID, "hr")
+	if err != nil {
+		s.logger.Error("Failed to read HR for fort attack", zap.Error(err))
+		return true
+	}
+	if err := s.server.fortAttackService.Sortie(event, s.charID, hr); err != nil {
+		if errors.Is(err, ErrFortAttackRestricted) {
+			s.logger.Info("Fort attack sortie refused", zap.Uint32("charID", s.charID), zap.Error(err))
+			return false
+		}
+		s.logger.Error("Failed to record fort attack sortie", zap.Error(err))
+		return true
+	}
+	s.fortSortie = event.ID
+	return true
+}
+
 func questFileExists(s *Session, filename string) bool {
 	base := filepath.Join(s.server.erupeConfig.BinPath, "quests", filename)
 	if _, err := os.Stat(base + ".bin"); err == nil {
@@ -365,38 +407,7 @@ func makeEventQuest(s *Session, eq EventQuest) ([]byte, error) {
 		bf.WriteUint8(eq.MaxPlayers)
 	}
 	bf.WriteUint8(eq.QuestType)
-	if eq.QuestType == QuestTypeSpecialTool {
-		var stamps, required int
-		var deadline time.Time
-		err := s.server.db.QueryRow(`SELECT COUNT(*) FROM campaign_state WHERE campaign_id = (
-			SELECT campaign_id
-			FROM campaign_rewards
-			WHERE item_type = 9
-			AND item_id = $1
-			LIMIT 1
-		) AND character_id = $2`, eq.QuestID, s.charID).Scan(&stamps)
-		if err != nil {
-			bf.WriteBool(false)
-		} else {
-			err = s.server.db.QueryRow(`SELECT stamps, end_time
-			FROM campaigns
-			WHERE id = (
-				SELECT campaign_id
-				FROM campaign_rewards
-				WHERE item_type = 9
-				AND item_id = $1
-				LIMIT 1
-			)`, eq.QuestID).Scan(&required, &deadline)
-			required = campaignRequiredStamps(required)
-			if err == nil && stamps >= required && deadline.After(time.Now()) {
-				bf.WriteBool(true)
-			} else {
-				bf.WriteBool(false)
-			}
-		}
-	} else {
-		bf.WriteBool(true)
-	}
+	bf.WriteBool(eventQuestUnlocked(s, eq))
 	bf.WriteUint16(0) // Unk
 	if s.server.erupeConfig.RealClientMode >= cfg.G2 {
 		bf.WriteUint32(eq.Mark)
@@ -443,65 +454,61 @@ func makeEventQuest(s *Session, eq EventQuest) ([]byte, error) {
 	return bf.Data(), nil
 }
 
+// eventQuestUnlocked reports whether the character may take eq. Special tool
+// quests are unlocked by collecting their campaign's stamps; every other
+// quest is always available.
+func eventQuestUnlocked(s *Session, eq EventQuest) bool {
+	if eq.QuestType != QuestTypeSpecialTool {
+		return true
+	}
+	var stamps, required int
+	var deadline time.Time
+	err := s.server.db.QueryRow(`SELECT COUNT(*) FROM campaign_state WHERE campaign_id = (
+		SELECT campaign_id
+		FROM campaign_rewards
+		WHERE item_type = 9
+		AND item_id = $1
+		LIMIT 1
+	) AND character_id = $2`, eq.QuestID, s.charID).Scan(&stamps)
+	if err != nil {
+		return false
+	}
+	err = s.server.db.QueryRow(`SELECT stamps, end_time
+	FROM campaigns
+	WHERE id = (
+		SELECT campaign_id
+		FROM campaign_rewards
+		WHERE item_type = 9
+		AND item_id = $1
+		LIMIT 1
+	)`, eq.QuestID).Scan(&required, &deadline)
+	required = campaignRequiredStamps(required)
+	return err == nil && stamps >= required && deadline.After(time.Now())
+}
+
+// runEventQuests keeps the live event quest set up to date until the server
+// shuts down, so rotations are stored even while nobody opens the quest list.
+func (s *Server) runEventQuests() {
+	s.runPeriodic(eventQuestTickInterval, func() {
+		s.eventQuestScheduler.Tick(time.Now())
+	})
+}
+
 func handleMsgMhfEnumerateQuest(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfEnumerateQuest)
 	var totalCount, returnedCount uint16
 	bf := byteframe.NewByteFrame()
 	bf.WriteUint16(0)
 
-	quests, err := s.server.eventRepo.GetEventQuests()
-	if err == nil {
-		currentTime := time.Now()
-		var updates []EventQuestUpdate
-
-		for i, eq := range quests {
-			// Use the Event Cycling system
-			if eq.ActiveDays > 0 {
-				cycleLength := (time.Duration(eq.ActiveDays) + time.Duration(eq.InactiveDays)) * 24 * time.Hour
-
-				// Count the number of full cycles elapsed since the last rotation.
-				extraCycles := int(currentTime.Sub(eq.StartTime) / cycleLength)
-
-				if extraCycles > 0 {
-					// Calculate the rotation time based on start time, active duration, and inactive duration.
-					rotationTime := eq.StartTime.Add(time.Duration(eq.ActiveDays+eq.InactiveDays) * 24 * time.Hour * time.Duration(extraCycles))
-					if currentTime.After(rotationTime) {
-						// Normalize rotationTime to 12PM JST to align with the in-game events update notification.
-						newRotationTime := time.Date(rotationTime.Year(), rotationTime.Month(), rotationTime.Day(), 12, 0, 0, 0, TimeAdjusted().Location())
-
-						updates = append(updates, EventQuestUpdate{ID: eq.ID, StartTime: newRotationTime})
-						quests[i].StartTime = newRotationTime // Set the new start time so the quest can be used/removed immediately.
-						eq = quests[i]
-					}
-				}
-
-				// Check if the quest is currently active
-				if currentTime.Before(eq.StartTime) || currentTime.After(eq.StartTime.Add(time.Duration(eq.ActiveDays)*24*time.Hour)) {
-					continue
-				}
-			}
-
-			data, err := makeEventQuest(s, eq)
-			if err != nil {
-				s.logger.Error("Failed to make event quest", zap.Error(err))
-				continue
-			} else {
-				if len(data) > questDataMaxLen || len(data) < questDataMinLen {
-					s.logger.Error("Invalid quest data length", zap.Int("len", len(data)))
-					continue
-				} else {
-					totalCount++
-					if totalCount > pkt.Offset && len(bf.Data()) < 60000 {
-						returnedCount++
-						bf.WriteBytes(data)
-						continue
-					}
-				}
-			}
+	for _, eq := range s.server.eventQuestScheduler.Live(time.Now()) {
+		data := s.server.eventQuestScheduler.Payload(s, eq)
+		if data == nil {
+			continue
 		}
-
-		if err := s.server.eventRepo.UpdateEventQuestStartTimes(updates); err != nil {
-			s.logger.Error("Failed to update event quest start times", zap.Error(err))
+		totalCount++
+		if totalCount > pkt.Offset && len(bf.Data()) < 60000 {
+			returnedCount++
+			bf.WriteBytes(data)
 		}
 	}
 
@@ -640,6 +640,8 @@ func TestTournamentQuestEntryHandler(t *testing.T) {
 	mockConn := &MockCryptConn{sentPackets: make([][]byte, 0)}
 	s := createTestSession(mockConn)
 	s.server.tournamentRepo = &mockTournamentRepo{}
+	s.server.logger = s.logger
+	ensureTournamentService(s.server)
 
 	pkt := &mhfpacket.MsgMhfEnterTournamentQuest{AckHandle: 1}
 
@@ -2,6 +2,7 @@ package channelserver
 
 import (
 	"erupe-ce/common/byteframe"
+	"erupe-ce/network/clientctx"
 	"erupe-ce/network/mhfpacket"
 )
 
@@ -118,34 +119,54 @@ func handleMsgSysLoadRegister(s *Session, p mhfpacket.MHFPacket) {
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
-func (s *Session) notifyRavi() {
-	sema := s.server.getRaviSemaphore()
-	if sema == nil {
-		return
-	}
+// raviNotification tells a client to reload all three Raviente registers.
+func raviNotification(ctx *clientctx.ClientContext) []byte {
 	var temp mhfpacket.MHFPacket
 	raviNotif := byteframe.NewByteFrame()
 	temp = &mhfpacket.MsgSysNotifyRegister{RegisterID: raviRegisterState}
 	raviNotif.WriteUint16(uint16(temp.Opcode()))
-	_ = temp.Build(raviNotif, s.clientContext)
+	_ = temp.Build(raviNotif, ctx)
 	temp = &mhfpacket.MsgSysNotifyRegister{RegisterID: raviRegisterSupport}
 	raviNotif.WriteUint16(uint16(temp.Opcode()))
-	_ = temp.Build(raviNotif, s.clientContext)
+	_ = temp.Build(raviNotif, ctx)
 	temp = &mhfpacket.MsgSysNotifyRegister{RegisterID: raviRegisterGeneral}
 	raviNotif.WriteUint16(uint16(temp.Opcode()))
-	_ = temp.Build(raviNotif, s.clientContext)
+	_ = temp.Build(raviNotif, ctx)
 	raviNotif.WriteUint16(0x0010) // End it.
+	return raviNotif.Data()
+}
+
+func (s *Session) notifyRavi() {
+	sema := s.server.getRaviSemaphore()
+	if sema == nil {
+		return
+	}
+	raviNotif := raviNotification(s.clientContext)
 	if s.server.erupeConfig.GameplayOptions.LowLatencyRaviente {
 		for session := range sema.clients {
-			session.QueueSendNonBlocking(raviNotif.Data())
+			session.QueueSendNonBlocking(raviNotif)
 		}
 	} else {
 		for session := range sema.clients {
 			if session.charID == s.charID {
-				session.QueueSendNonBlocking(raviNotif.Data())
+				session.QueueSendNonBlocking(raviNotif)
 			}
 		}
 	}
 }
 
+// notifyRaviAll tells every siege participant to reload the registers, for
+// changes made by the server rather than by a player.
+func (s *Server) notifyRaviAll() {
+	s.semaphoreLock.Lock()
+	defer s.semaphoreLock.Unlock()
+	sema := s.getRaviSemaphore()
+	if sema == nil {
+		return
+	}
+	for session := range sema.clients {
+		session.QueueSendNonBlocking(raviNotification(session.clientContext))
+	}
+}
+
 func handleMsgSysNotifyRegister(s *Session, p mhfpacket.MHFPacket) {} // stub: unimplemented
@@ -2,8 +2,12 @@ package channelserver
 
 import (
 	"erupe-ce/common/byteframe"
+	"erupe-ce/common/stringsupport"
 	"erupe-ce/network/mhfpacket"
+	"math"
 	"time"
+
+	"go.uber.org/zap"
 )
 
 // SeibattleTimetable represents a seibattle schedule entry.
@@ -64,33 +68,124 @@ type Seibattle struct {
 	CurResult        []SeibattleCurResult
 }
 
+// Seibattle windows and list sizes. The current result covers today and
+// the convention results the past week, one entry per day.
+const (
+	seibattleConventionDays = 7
+	seibattleOpponents      = 5
+	seibattleRankingSize    = 100
+)
+
+// seibattleGuild returns the guild a Seibattle request is about: the one it
+// names, or the character's own.
+func seibattleGuild(s *Session, guildID uint32) uint32 {
+	if guildID != 0 {
+		return guildID
+	}
+	member, err := s.server.guildRepo.GetCharacterMembership(s.charID)
+	if err != nil || member == nil || member.IsApplicant {
+		return 0
+	}
+	return member.GuildID
+}
+
+// clampUint16 caps a count at the range of a u16 field.
+func clampUint16(v uint32) uint16 {
+	return uint16(min(v, 0xFFFF))
+}
+
+// seibattleTimetable returns today's three eight-hour battle windows.
+func seibattleTimetable(today time.Time) []SeibattleTimetable {
+	return []SeibattleTimetable{
+		{today, today.Add(time.Hour * 8)},
+		{today.Add(time.Hour * 8), today.Add(time.Hour * 16)},
+		{today.Add(time.Hour * 16), today.Add(time.Hour * 24)},
+	}
+}
+
+// placeholderSeibattle returns the fixed sections Erupe sends unless
+// GameplayOptions.EnableSeibattleResults is set.
+func placeholderSeibattle() Seibattle {
+	return Seibattle{
+		Timetable:        seibattleTimetable(TimeMidnight()),
+		KeyScore:         []SeibattleKeyScore{{0, 0}},
+		Career:           []SeibattleCareer{{0, 0, 0}},
+		Opponent:         []SeibattleOpponent{{1, 1}},
+		ConventionResult: []SeibattleConventionResult{{0, 0, 0, 0, 0}},
+		CharScore:        []SeibattleCharScore{{0}},
+		CurResult:        []SeibattleCurResult{{0, 0, 0, 0}},
+	}
+}
+
+// loadSeibattle builds the Seibattle sections from the stored results. The
+// meaning of the sections' fields is not confirmed: the entry layouts are
+// the ones Erupe has always sent, filled with the guild's summed scores,
+// battle counts and placings. It is only used when
+// GameplayOptions.EnableSeibattleResults is set.
+func loadSeibattle(s *Session, guildID uint32) (Seibattle, error) {
+	today := TimeMidnight()
+	seibattle := Seibattle{Timetable: seibattleTimetable(today)}
+	charScore, err := s.server.seibattleRepo.GetCharacterScore(s.charID, today)
+	if err != nil {
+		return seibattle, err
+	}
+	seibattle.CharScore = []SeibattleCharScore{{charScore}}
+	if guildID == 0 {
+		return seibattle, nil
+	}
+
+	keys, err := s.server.seibattleRepo.GetKeyScores(guildID, today)
+	if err != nil {
+		return seibattle, err
+	}
+	for _, k := range keys {
+		seibattle.KeyScore = append(seibattle.KeyScore, SeibattleKeyScore{k.Key, int32(min(k.Score, math.MaxInt32))})
+	}
+
+	career, err := s.server.seibattleRepo.GetCareer(guildID)
+	if err != nil {
+		return seibattle, err
+	}
+	seibattle.Career = []SeibattleCareer{{clampUint16(career.Battles), clampUint16(career.Days), clampUint16(career.Members)}}
+
+	totals, err := s.server.seibattleRepo.GetGuildTotals(today, seibattleRankingSize)
+	if err != nil {
+		return seibattle, err
+	}
+	cur := SeibattleCurResult{}
+	for _, t := range totals {
+		if t.GuildID == guildID {
+			cur = SeibattleCurResult{t.Score, clampUint16(t.Place), clampUint16(t.Battles), clampUint16(t.Members)}
+			continue
+		}
+		if len(seibattle.Opponent) < seibattleOpponents {
+			seibattle.Opponent = append(seibattle.Opponent, SeibattleOpponent{int32(t.GuildID), int8(min(t.Place, math.MaxInt8))})
+		}
+	}
+	seibattle.CurResult = []SeibattleCurResult{cur}
+
+	since := today.AddDate(0, 0, 1-seibattleConventionDays)
+	days, err := s.server.seibattleRepo.GetDays(guildID, since)
+	if err != nil {
+		return seibattle, err
+	}
+	for _, d := range days {
+		seibattle.ConventionResult = append(seibattle.ConventionResult, SeibattleConventionResult{
+			uint32(since.AddDate(0, 0, d.Day).Unix()), clampUint16(d.Place), clampUint16(d.Battles), clampUint16(d.Members), 0,
+		})
+	}
+	return seibattle, nil
+}
+
 func handleMsgMhfGetSeibattle(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfGetSeibattle)
 	var data []*byteframe.ByteFrame
-	seibattle := Seibattle{
-		Timetable: []SeibattleTimetable{
-			{TimeMidnight(), TimeMidnight().Add(time.Hour * 8)},
-			{TimeMidnight().Add(time.Hour * 8), TimeMidnight().Add(time.Hour * 16)},
-			{TimeMidnight().Add(time.Hour * 16), TimeMidnight().Add(time.Hour * 24)},
-		},
-		KeyScore: []SeibattleKeyScore{
-			{0, 0},
-		},
-		Career: []SeibattleCareer{
-			{0, 0, 0},
-		},
-		Opponent: []SeibattleOpponent{
-			{1, 1},
-		},
-		ConventionResult: []SeibattleConventionResult{
-			{0, 0, 0, 0, 0},
-		},
-		CharScore: []SeibattleCharScore{
-			{0},
-		},
-		CurResult: []SeibattleCurResult{
-			{0, 0, 0, 0},
-		},
+	seibattle := placeholderSeibattle()
+	if s.server.erupeConfig.GameplayOptions.EnableSeibattleResults {
+		var err error
+		if seibattle, err = loadSeibattle(s, seibattleGuild(s, pkt.GuildID)); err != nil {
+			s.logger.Error("Failed to load seibattle results", zap.Error(err))
+		}
 	}
 
 	switch pkt.Type {
@@ -154,6 +249,23 @@ func handleMsgMhfGetSeibattle(s *Session, p mhfpacket.MHFPacket) {
 
 func handleMsgMhfPostSeibattle(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfPostSeibattle)
+	// Results are credited to the poster's guild; Unk0 is taken as the
+	// result key and Unk2 as its score, the rest is stored as sent.
+	if guildID := seibattleGuild(s, 0); guildID != 0 {
+		if err := s.server.seibattleRepo.AddResult(SeibattleResult{
+			GuildID: guildID,
+			CharID:  s.charID,
+			Key:     pkt.Unk0,
+			Score:   pkt.Unk2,
+			Unk1:    pkt.Unk1,
+			Unk3:    pkt.Unk3,
+			Unk4:    pkt.Unk4,
+			Unk5:    pkt.Unk5,
+			Unk6:    pkt.Unk6,
+		}); err != nil {
+			s.logger.Error("Failed to store seibattle result", zap.Error(err))
+		}
+	}
 	doAckSimpleSucceed(s, pkt.AckHandle, make([]byte, 4))
 }
 
@@ -167,31 +279,27 @@ func handleMsgMhfGetBreakSeibatuLevelReward(s *Session, p mhfpacket.MHFPacket) {
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
-// WeeklySeibatuRankingReward represents a weekly seibattle ranking reward.
-type WeeklySeibatuRankingReward struct {
-	Unk0 int32
-	Unk1 int32
-	Unk2 uint32
-	Unk3 int32
-	Unk4 int32
-	Unk5 int32
-}
+// weeklySeibatuConquestRewards is the Operation (Unk1) of a
+// GetWeeklySeibatuRankingReward request for the Conquest ranking rewards.
+const weeklySeibatuConquestRewards = 1
 
 func handleMsgMhfGetWeeklySeibatuRankingReward(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfGetWeeklySeibatuRankingReward)
+	// Operation 1 lists the Conquest ranking brackets, one entry per
+	// EarthRewards bracket: i32 unknown, i32 item ID, u32 quantity, i32 place
+	// from, i32 place to. The Pallone and Tower operations have no reward
+	// tables and get an empty list.
 	var data []*byteframe.ByteFrame
-	weeklySeibatuRankingRewards := []WeeklySeibatuRankingReward{
-		{0, 0, 0, 0, 0, 0},
-	}
-	for _, reward := range weeklySeibatuRankingRewards {
-		bf := byteframe.NewByteFrame()
-		bf.WriteInt32(reward.Unk0)
-		bf.WriteInt32(reward.Unk1)
-		bf.WriteUint32(reward.Unk2)
-		bf.WriteInt32(reward.Unk3)
-		bf.WriteInt32(reward.Unk4)
-		bf.WriteInt32(reward.Unk5)
-		data = append(data, bf)
+	if pkt.Unk1 == weeklySeibatuConquestRewards {
+		for _, reward := range s.server.erupeConfig.EarthRewards {
+			bf := byteframe.NewByteFrame()
+			bf.WriteInt32(0)
+			bf.WriteUint32(reward.ItemID)
+			bf.WriteUint32(reward.Quantity)
+			bf.WriteUint32(reward.PlaceFrom)
+			bf.WriteUint32(reward.PlaceTo)
+			data = append(data, bf)
+		}
 	}
 	doAckEarthSucceed(s, pkt.AckHandle, data)
 }
@@ -205,15 +313,56 @@ func handleMsgMhfGetFixedSeibatuRankingTable(s *Session, p mhfpacket.MHFPacket)
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
 
+// runConquest pays out finished Conquest phases until the server shuts down.
+func (s *Server) runConquest() {
+	s.runPeriodic(conquestTickInterval, func() {
+		s.conquestService.Tick(s.erupeConfig, TimeAdjusted(), TimeWeekStart())
+	})
+}
+
+// earthPhase returns the current Earth phase. If the schedule cannot be
+// read, the cycle is treated as disabled for this request.
+func earthPhase(s *Session) ConquestPhase {
+	phase, err := s.server.conquestService.Current(s.server.erupeConfig, TimeAdjusted(), TimeWeekStart())
+	if err != nil {
+		s.logger.Error("Failed to read earth phase", zap.Error(err))
+		return ConquestPhase{
+			EarthID: s.server.erupeConfig.EarthID,
+			Start:   TimeWeekStart(),
+			End:     TimeWeekNext(),
+		}
+	}
+	return phase
+}
+
+// earthMonster returns the conquest target monster in beat level slot i, or
+// 0 if the slot is not configured.
+func earthMonster(s *Session, i int) int32 {
+	if i < len(s.server.erupeConfig.EarthMonsters) {
+		return s.server.erupeConfig.EarthMonsters[i]
+	}
+	return 0
+}
+
 func handleMsgMhfReadBeatLevel(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfReadBeatLevel)
 
-	// This response is fixed and will never change on JP,
-	// but I've left it dynamic for possible other client differences.
+	levels, err := s.server.conquestService.Levels(earthPhase(s), s.charID)
+	if err != nil {
+		s.logger.Error("Failed to read beat levels", zap.Error(err))
+	}
+
+	// The requested IDs are fixed literals on JP; entry i reports the level
+	// of the monster in slot i of EarthMonsters, the order UpdateBeatLevel
+	// sends them in.
 	resp := byteframe.NewByteFrame()
-	for i := 0; i < int(pkt.ValidIDCount); i++ {
+	for i := 0; i < int(min(pkt.ValidIDCount, uint32(len(pkt.IDs)))); i++ {
+		level, ok := levels[earthMonster(s, i)]
+		if !ok {
+			level = 1
+		}
 		resp.WriteUint32(pkt.IDs[i])
-		resp.WriteUint32(1)
+		resp.WriteUint32(level)
 		resp.WriteUint32(1)
 		resp.WriteUint32(1)
 	}
@@ -223,10 +372,22 @@ func handleMsgMhfReadBeatLevel(s *Session, p mhfpacket.MHFPacket) {
 
 func handleMsgMhfReadLastWeekBeatRanking(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfReadLastWeekBeatRanking)
+
+	// The character's placing on the requested monster's leaderboard for
+	// the most recent finished Conquest phase.
+	var placing ConquestRankEntry
+	if earthID, ok := earthPhase(s).LastConquest(); ok {
+		placings, err := s.server.conquestService.Placings(earthID, s.charID)
+		if err != nil {
+			s.logger.Error("Failed to read last week's beat ranking", zap.Error(err))
+		}
+		placing = placings[pkt.Unk1]
+	}
+
 	bf := byteframe.NewByteFrame()
-	bf.WriteInt32(0)
-	bf.WriteInt32(0)
-	bf.WriteInt32(0)
+	bf.WriteInt32(pkt.Unk1)
+	bf.WriteUint32(placing.Place)
+	bf.WriteUint32(placing.Level)
 	bf.WriteInt32(0)
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
@@ -234,17 +395,36 @@ func handleMsgMhfReadLastWeekBeatRanking(s *Session, p mhfpacket.MHFPacket) {
 func handleMsgMhfUpdateBeatLevel(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfUpdateBeatLevel)
 
+	// Data2 carries the beat level of each EarthMonsters slot.
+	if err := s.server.conquestService.RecordLevels(earthPhase(s), s.charID,
+		s.server.erupeConfig.EarthMonsters, pkt.Data2); err != nil {
+		s.logger.Error("Failed to update beat levels", zap.Error(err))
+	}
+
 	doAckBufSucceed(s, pkt.AckHandle, []byte{0x00, 0x00, 0x00, 0x00})
 }
 
 func handleMsgMhfReadBeatLevelAllRanking(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfReadBeatLevelAllRanking)
+
+	// The field parsed as GuildID selects the monster whose leaderboard is shown.
+	ranking, err := s.server.conquestService.Ranking(earthPhase(s), pkt.GuildID)
+	if err != nil {
+		s.logger.Error("Failed to read beat level ranking", zap.Error(err))
+	}
+
 	bf := byteframe.NewByteFrame()
 	bf.WriteUint32(0)
 	bf.WriteInt32(0)
 	bf.WriteInt32(0)
 
-	for i := 0; i < 100; i++ {
+	for i := 0; i < conquestRankingSize; i++ {
+		if i < len(ranking) {
+			bf.WriteUint32(ranking[i].Place)
+			bf.WriteUint32(ranking[i].Level)
+			bf.WriteBytes(stringsupport.PaddedString(ranking[i].Name, 32, true))
+			continue
+		}
 		bf.WriteUint32(0)
 		bf.WriteUint32(0)
 		bf.WriteBytes(make([]byte, 32))
@@ -254,6 +434,28 @@ func handleMsgMhfReadBeatLevelAllRanking(s *Session, p mhfpacket.MHFPacket) {
 
 func handleMsgMhfReadBeatLevelMyRanking(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgMhfReadBeatLevelMyRanking)
+
+	phase := earthPhase(s)
+	var placings map[int32]ConquestRankEntry
+	if phase.Enabled {
+		var err error
+		placings, err = s.server.conquestService.Placings(phase.EarthID, s.charID)
+		if err != nil {
+			s.logger.Error("Failed to read own beat level ranking", zap.Error(err))
+		}
+	}
+
+	// One entry per requested ID, matched to EarthMonsters by slot as in
+	// ReadBeatLevel. Unranked slots report place 0.
 	bf := byteframe.NewByteFrame()
+	for i, id := range pkt.Unk2 {
+		if id == 0 {
+			continue
+		}
+		placing := placings[earthMonster(s, i)]
+		bf.WriteInt32(id)
+		bf.WriteUint32(placing.Place)
+		bf.WriteUint32(placing.Level)
+	}
 	doAckBufSucceed(s, pkt.AckHandle, bf.Data())
 }
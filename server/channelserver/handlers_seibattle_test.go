@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"testing"
 
+	cfg "erupe-ce/config"
 	"erupe-ce/network/mhfpacket"
 )
 
@@ -100,9 +101,10 @@ func TestHandleMsgMhfGetBreakSeibatuLevelReward_DataSize(t *testing.T) {
 func TestHandleMsgMhfGetWeeklySeibatuRankingReward_EarthFormat(t *testing.T) {
 	server := createMockServer()
 	server.erupeConfig.EarthID = 42
+	server.erupeConfig.EarthRewards = []cfg.ConquestReward{{PlaceFrom: 1, PlaceTo: 100, ItemType: 7, ItemID: 1234, Quantity: 3}}
 	session := createMockSession(1, server)
 
-	pkt := &mhfpacket.MsgMhfGetWeeklySeibatuRankingReward{AckHandle: 100}
+	pkt := &mhfpacket.MsgMhfGetWeeklySeibatuRankingReward{AckHandle: 100, Unk1: weeklySeibatuConquestRewards}
 	handleMsgMhfGetWeeklySeibatuRankingReward(session, pkt)
 
 	select {
@@ -114,13 +116,105 @@ func TestHandleMsgMhfGetWeeklySeibatuRankingReward_EarthFormat(t *testing.T) {
 		}
 		count := binary.BigEndian.Uint32(ackData[12:16])
 		if count != 1 {
-			t.Errorf("reward count = %d, want 1", count)
+			t.Fatalf("reward count = %d, want 1", count)
+		}
+		entry := ackData[16:]
+		if len(entry) != 20 || binary.BigEndian.Uint32(entry[4:8]) != 1234 ||
+			binary.BigEndian.Uint32(entry[8:12]) != 3 || binary.BigEndian.Uint32(entry[16:20]) != 100 {
+			t.Errorf("reward entry = %X, want item 1234 x3 for places 1-100", entry)
 		}
 	default:
 		t.Fatal("No response queued")
 	}
 }
 
+func TestHandleMsgMhfGetWeeklySeibatuRankingReward_OtherOperations(t *testing.T) {
+	server := createMockServer()
+	server.erupeConfig.EarthRewards = []cfg.ConquestReward{{PlaceFrom: 1, PlaceTo: 100, ItemID: 1234, Quantity: 3}}
+	session := createMockSession(1, server)
+
+	handleMsgMhfGetWeeklySeibatuRankingReward(session, &mhfpacket.MsgMhfGetWeeklySeibatuRankingReward{AckHandle: 100, Unk1: 5})
+
+	_, _, ackData := parseAckBufData(t, (<-session.sendPackets).data)
+	if count := binary.BigEndian.Uint32(ackData[12:16]); count != 0 {
+		t.Errorf("reward count = %d, want 0 for the Tower operation", count)
+	}
+}
+
+func TestHandleMsgMhfPostSeibattle_StoresResult(t *testing.T) {
+	server := createMockServer()
+	repo := &mockSeibattleRepo{}
+	server.seibattleRepo = repo
+	server.guildRepo = &mockGuildRepo{membership: &GuildMember{GuildID: 7, CharID: 1}}
+	session := createMockSession(1, server)
+
+	handleMsgMhfPostSeibattle(session, &mhfpacket.MsgMhfPostSeibattle{AckHandle: 1, Unk0: 2, Unk2: 450, Unk4: 9})
+
+	if ack := readAck(t, session); ack.ErrorCode != 0 {
+		t.Errorf("ErrorCode = %d, want 0", ack.ErrorCode)
+	}
+	if len(repo.added) != 1 {
+		t.Fatalf("stored %d results, want 1", len(repo.added))
+	}
+	if got := repo.added[0]; got.GuildID != 7 || got.CharID != 1 || got.Key != 2 || got.Score != 450 || got.Unk4 != 9 {
+		t.Errorf("stored %+v", got)
+	}
+}
+
+func TestHandleMsgMhfPostSeibattle_NoGuild(t *testing.T) {
+	server := createMockServer()
+	repo := &mockSeibattleRepo{}
+	server.seibattleRepo = repo
+	session := createMockSession(1, server)
+
+	handleMsgMhfPostSeibattle(session, &mhfpacket.MsgMhfPostSeibattle{AckHandle: 1, Unk2: 450})
+
+	<-session.sendPackets
+	if len(repo.added) != 0 {
+		t.Errorf("stored %+v without a guild", repo.added)
+	}
+}
+
+func TestHandleMsgMhfGetSeibattle_PlaceholderWhenDisabled(t *testing.T) {
+	server := createMockServer()
+	server.seibattleRepo = &mockSeibattleRepo{totals: []SeibattleGuildTotal{
+		{GuildID: 9, Place: 1, Score: 900, Battles: 4, Members: 2},
+		{GuildID: 7, Place: 2, Score: 600, Battles: 3, Members: 3},
+	}}
+	server.guildRepo = &mockGuildRepo{membership: &GuildMember{GuildID: 7, CharID: 1}}
+	session := createMockSession(1, server)
+
+	handleMsgMhfGetSeibattle(session, &mhfpacket.MsgMhfGetSeibattle{AckHandle: 1, Type: 8})
+	_, _, ackData := parseAckBufData(t, (<-session.sendPackets).data)
+	if cur := ackData[16:]; len(cur) != 10 || binary.BigEndian.Uint32(cur[:4]) != 0 {
+		t.Errorf("cur result = %X, want the zeroed placeholder", cur)
+	}
+}
+
+func TestHandleMsgMhfGetSeibattle_CurResultAndOpponents(t *testing.T) {
+	server := createMockServer()
+	server.erupeConfig.GameplayOptions.EnableSeibattleResults = true
+	server.seibattleRepo = &mockSeibattleRepo{totals: []SeibattleGuildTotal{
+		{GuildID: 9, Place: 1, Score: 900, Battles: 4, Members: 2},
+		{GuildID: 7, Place: 2, Score: 600, Battles: 3, Members: 3},
+	}}
+	server.guildRepo = &mockGuildRepo{membership: &GuildMember{GuildID: 7, CharID: 1}}
+	session := createMockSession(1, server)
+
+	handleMsgMhfGetSeibattle(session, &mhfpacket.MsgMhfGetSeibattle{AckHandle: 1, Type: 8})
+	_, _, ackData := parseAckBufData(t, (<-session.sendPackets).data)
+	cur := ackData[16:]
+	if len(cur) != 10 || binary.BigEndian.Uint32(cur[:4]) != 600 || binary.BigEndian.Uint16(cur[4:6]) != 2 {
+		t.Errorf("cur result = %X, want score 600 in place 2", cur)
+	}
+
+	handleMsgMhfGetSeibattle(session, &mhfpacket.MsgMhfGetSeibattle{AckHandle: 2, Type: 5})
+	_, _, ackData = parseAckBufData(t, (<-session.sendPackets).data)
+	if count := binary.BigEndian.Uint32(ackData[12:16]); count != 1 || binary.BigEndian.Uint32(ackData[16:20]) != 9 {
+		t.Errorf("opponents = %X, want guild 9 only", ackData[12:])
+	}
+}
+
 func TestHandleMsgMhfGetFixedSeibatuRankingTable_DataSize(t *testing.T) {
 	server := createMockServer()
 	session := createMockSession(1, server)
@@ -275,3 +369,100 @@ func TestHandleMsgMhfReadLastWeekBeatRanking_DataSize(t *testing.T) {
 		t.Fatal("No response queued")
 	}
 }
+
+func newConquestTestSession(t *testing.T, repo *mockConquestRepo, status int32) *Session {
+	t.Helper()
+	server := createMockServer()
+	server.erupeConfig = &cfg.Config{EarthID: 2, EarthStatus: status, EarthMonsters: []int32{116, 107, 2, 36}}
+	server.conquestRepo = repo
+	ensureConquestService(server)
+	return createMockSession(1, server)
+}
+
+func TestHandleMsgMhfUpdateBeatLevel_RecordsLevels(t *testing.T) {
+	repo := &mockConquestRepo{}
+	session := newConquestTestSession(t, repo, EarthStatusConquest)
+
+	data2 := make([]int32, 16)
+	data2[0], data2[1] = 40, 12
+	handleMsgMhfUpdateBeatLevel(session, &mhfpacket.MsgMhfUpdateBeatLevel{AckHandle: 1, Data1: make([]int32, 16), Data2: data2})
+	<-session.sendPackets
+
+	if repo.setEarthID != 2 || repo.setLevels[116] != 40 || repo.setLevels[107] != 12 || len(repo.setLevels) != 2 {
+		t.Errorf("stored earth %d levels %v", repo.setEarthID, repo.setLevels)
+	}
+}
+
+func TestHandleMsgMhfReadBeatLevel_StoredLevels(t *testing.T) {
+	repo := &mockConquestRepo{levels: map[int32]uint32{107: 250}}
+	session := newConquestTestSession(t, repo, EarthStatusConquest)
+
+	handleMsgMhfReadBeatLevel(session, &mhfpacket.MsgMhfReadBeatLevel{
+		AckHandle: 1, ValidIDCount: 2, IDs: [16]uint32{0x74, 0x6B},
+	})
+	_, _, ackData := parseAckBufData(t, (<-session.sendPackets).data)
+	if got := binary.BigEndian.Uint32(ackData[4:8]); got != 1 {
+		t.Errorf("slot 0 level = %d, want default 1", got)
+	}
+	if got := binary.BigEndian.Uint32(ackData[20:24]); got != 250 {
+		t.Errorf("slot 1 level = %d, want 250", got)
+	}
+}
+
+func TestHandleMsgMhfReadBeatLevelAllRanking_Entries(t *testing.T) {
+	repo := &mockConquestRepo{ranking: []ConquestRankEntry{
+		{MonsterID: 116, Place: 1, CharID: 5, Name: "Hunter", Level: 900},
+	}}
+	session := newConquestTestSession(t, repo, EarthStatusConquest)
+
+	handleMsgMhfReadBeatLevelAllRanking(session, &mhfpacket.MsgMhfReadBeatLevelAllRanking{AckHandle: 1, GuildID: 116})
+	_, _, ackData := parseAckBufData(t, (<-session.sendPackets).data)
+	if len(ackData) != 12+100*40 {
+		t.Fatalf("AckData len = %d", len(ackData))
+	}
+	entry := ackData[12:52]
+	if binary.BigEndian.Uint32(entry[0:4]) != 1 || binary.BigEndian.Uint32(entry[4:8]) != 900 {
+		t.Errorf("first entry = % x", entry[:8])
+	}
+	if string(entry[8:14]) != "Hunter" || entry[14] != 0 {
+		t.Errorf("first entry name = %q", entry[8:40])
+	}
+	if repo.rankingEarth != 2 {
+		t.Errorf("ranking read for earth %d, want 2", repo.rankingEarth)
+	}
+}
+
+func TestHandleMsgMhfReadBeatLevelMyRanking_Placings(t *testing.T) {
+	repo := &mockConquestRepo{placings: []ConquestRankEntry{{MonsterID: 107, Place: 3, CharID: 1, Level: 70}}}
+	session := newConquestTestSession(t, repo, EarthStatusConquest)
+
+	unk2 := make([]int32, 16)
+	unk2[0], unk2[1] = 0x74, 0x6B
+	handleMsgMhfReadBeatLevelMyRanking(session, &mhfpacket.MsgMhfReadBeatLevelMyRanking{AckHandle: 1, Unk2: unk2})
+	_, _, ackData := parseAckBufData(t, (<-session.sendPackets).data)
+	if len(ackData) != 24 {
+		t.Fatalf("AckData len = %d, want 24", len(ackData))
+	}
+	if place, level := binary.BigEndian.Uint32(ackData[16:20]), binary.BigEndian.Uint32(ackData[20:24]); place != 3 || level != 70 {
+		t.Errorf("slot 1 = place %d level %d, want 3 and 70", place, level)
+	}
+}
+
+func TestHandleMsgMhfReadLastWeekBeatRanking_Placing(t *testing.T) {
+	repo := &mockConquestRepo{placings: []ConquestRankEntry{{MonsterID: 116, Place: 8, CharID: 1, Level: 44}}}
+	// Seeded in the Pallone phase, so Earth 2's Conquest phase has finished.
+	session := newConquestTestSession(t, repo, EarthStatusPallone)
+
+	handleMsgMhfReadLastWeekBeatRanking(session, &mhfpacket.MsgMhfReadLastWeekBeatRanking{AckHandle: 1, Unk1: 116})
+	_, _, ackData := parseAckBufData(t, (<-session.sendPackets).data)
+	if len(ackData) != 16 {
+		t.Fatalf("AckData len = %d, want 16", len(ackData))
+	}
+	if m, place, level := binary.BigEndian.Uint32(ackData[0:4]), binary.BigEndian.Uint32(ackData[4:8]),
+		binary.BigEndian.Uint32(ackData[8:12]); m != 116 || place != 8 || level != 44 {
+		t.Errorf("response = monster %d place %d level %d", m, place, level)
+	}
+	if repo.placingsEarth != 2 {
+		t.Errorf("placings read for earth %d, want 2", repo.placingsEarth)
+	}
+}
@@ -23,22 +23,27 @@ func handleMsgSysCreateSemaphore(s *Session, p mhfpacket.MHFPacket) {
 }
 
 func destructEmptySemaphores(s *Session) {
+	var ended []*raviSiegeEnd
 	s.server.semaphoreLock.Lock()
 	for id, sema := range s.server.semaphore {
 		if len(sema.clients) == 0 {
 			delete(s.server.semaphore, id)
 			if strings.HasPrefix(id, "hs_l0") {
-				s.server.resetRaviente()
+				ended = append(ended, s.server.resetRaviente())
 			}
 			s.logger.Debug("Destructed semaphore", zap.String("sema.name", id))
 		}
 	}
 	s.server.semaphoreLock.Unlock()
+	for _, end := range ended {
+		s.server.endRaviSiege(end)
+	}
 }
 
 func handleMsgSysDeleteSemaphore(s *Session, p mhfpacket.MHFPacket) {
 	pkt := p.(*mhfpacket.MsgSysDeleteSemaphore)
 	destructEmptySemaphores(s)
+	var ended []*raviSiegeEnd
 	s.server.semaphoreLock.Lock()
 	for id, sema := range s.server.semaphore {
 		if sema.id == pkt.SemaphoreID {
@@ -50,13 +55,16 @@ func handleMsgSysDeleteSemaphore(s *Session, p mhfpacket.MHFPacket) {
 			if len(sema.clients) == 0 {
 				delete(s.server.semaphore, id)
 				if strings.HasPrefix(id, "hs_l0") {
-					s.server.resetRaviente()
+					ended = append(ended, s.server.resetRaviente())
 				}
 				s.logger.Debug("Destructed semaphore", zap.String("sema.name", id))
 			}
 		}
 	}
 	s.server.semaphoreLock.Unlock()
+	for _, end := range ended {
+		s.server.endRaviSiege(end)
+	}
 }
 
 func handleMsgSysCreateAcquireSemaphore(s *Session, p mhfpacket.MHFPacket) {
@@ -100,6 +108,9 @@ func handleMsgSysCreateAcquireSemaphore(s *Session, p mhfpacket.MHFPacket) {
 		s.Lock()
 		s.semaphore = newSemaphore
 		s.Unlock()
+		if strings.HasPrefix(SemaphoreID, "hs_l0") {
+			s.server.raviente.join(s.charID)
+		}
 		bf.WriteUint32(newSemaphore.id)
 	} else {
 		bf.WriteUint32(0)
@@ -343,6 +343,10 @@ func logoutPlayer(s *Session) {
 		return true
 	})
 
+	// Hand over held mutexes even if the player never entered a stage, so
+	// queued openers are not left waiting on a dead session.
+	releaseSessionMutexes(s)
+
 	// Update sign sessions and server player count
 	if s.server.db != nil {
 		if err := s.server.sessionRepo.ClearSession(s.token); err != nil {
@@ -453,15 +457,23 @@ func handleMsgSysRecordLog(s *Session, p mhfpacket.MHFPacket) {
 		bf := byteframe.NewByteFrameFromBytes(pkt.Data)
 		_, _ = bf.Seek(killLogHeaderSize, 0)
 		var val uint8
+		killed := false
 		for i := 0; i < killLogMonsterCount; i++ {
 			val = bf.ReadUint8()
 			if val > 0 && mhfmon.Monsters[i].Large {
+				killed = true
 				if err := s.server.guildRepo.InsertKillLog(s.charID, i, val, TimeAdjusted()); err != nil {
 					s.logger.Error("Failed to insert kill log", zap.Error(err))
 				}
 			}
 		}
+		// A fort quest that returns without a large monster kill counts as
+		// a failed defence.
+		if s.fortSortie != 0 {
+			s.server.fortAttackService.SortieResult(s.fortSortie, s.charID, killed, s.server.erupeConfig.FortAttack.SortieDamage)
+		}
 	}
+	s.fortSortie = 0
 	// remove a client returning to town from reserved slots to make sure the stage is hidden from board
 	delete(s.stage.reservedClientSlots, s.charID)
 	doAckSimpleSucceed(s, pkt.AckHandle, make([]byte, 4))
@@ -1111,9 +1111,6 @@ func TestEmptyHandlers_Concurrent(t *testing.T) {
 		handleMsgSysInfokyserver,
 		handleMsgMhfGetCaUniqueID,
 		handleMsgSysSetStatus,
-		handleMsgSysDuplicateObject,
-		handleMsgSysUpdateObjectBinary,
-		handleMsgSysCleanupObject,
 		handleMsgMhfShutClient,
 		handleMsgSysStageDestruct,
 	}
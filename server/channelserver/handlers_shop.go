@@ -1,6 +1,8 @@
 package channelserver
 
 import (
+	"time"
+
 	"erupe-ce/common/byteframe"
 	ps "erupe-ce/common/pascalstring"
 	cfg "erupe-ce/config"
@@ -25,6 +27,15 @@ type ShopItem struct {
 	RoadFatalis  uint16 `db:"road_fatalis"`
 }
 
+// maxItemShopRows caps the rows sent for one item shop (shop type 10) tab.
+// The client's item-shop tab renderer has a fixed-size internal buffer well
+// below the 512-row Limit it advertises: ~420 rows in one tab is known to
+// crash mhf.exe a couple seconds after entering the forge (see
+// Mezeporta/Erupe#190). 256 is a conservative, unbisected safety net, not a
+// confirmed-safe ceiling -- prefer trimming shop_items itself over relying on
+// this cap, since truncation silently hides rows from players.
+const maxItemShopRows = 256
+
 func writeShopItems(bf *byteframe.ByteFrame, items []ShopItem, mode cfg.Mode) {
 	bf.WriteUint16(uint16(len(items)))
 	bf.WriteUint16(uint16(len(items)))
@@ -234,14 +245,6 @@ func handleMsgMhfEnumerateShop(s *Session, p mhfpacket.MHFPacket) {
 		if len(items) > int(pkt.Limit) {
 			items = items[:pkt.Limit]
 		}
-		// The client's item-shop tab renderer has a fixed-size internal buffer
-		// well below the 512-row Limit it advertises: ~420 rows in one tab is
-		// known to crash mhf.exe a couple seconds after entering the forge
-		// (see Mezeporta/Erupe#190). 256 is a conservative, unbisected safety
-		// net, not a confirmed-safe ceiling -- prefer trimming shop_items
-		// itself over relying on this cap, since truncation silently hides
-		// rows from players.
-		const maxItemShopRows = 256
 		if len(items) > maxItemShopRows {
 			items = items[:maxItemShopRows]
 		}
@@ -269,12 +272,13 @@ func handleMsgMhfAcquireExchangeShop(s *Session, p mhfpacket.MHFPacket) {
 
 // FPointExchange represents a frontier point exchange entry.
 type FPointExchange struct {
-	ID       uint32 `db:"id"`
-	ItemType uint8  `db:"item_type"`
-	ItemID   uint16 `db:"item_id"`
-	Quantity uint16 `db:"quantity"`
-	FPoints  uint16 `db:"fpoints"`
-	Buyable  bool   `db:"buyable"`
+	ID        uint32     `db:"id" json:"id"`
+	ItemType  uint8      `db:"item_type" json:"itemType"`
+	ItemID    uint16     `db:"item_id" json:"itemId"`
+	Quantity  uint16     `db:"quantity" json:"quantity"`
+	FPoints   uint16     `db:"fpoints" json:"fpoints"`
+	Buyable   bool       `db:"buyable" json:"buyable"`
+	RetiredAt *time.Time `db:"retired_at" json:"retiredAt,omitempty"`
 }
 
 func handleMsgMhfExchangeFpoint2Item(s *Session, p mhfpacket.MHFPacket) {
@@ -34,6 +34,7 @@ func TestHandlerMsgMhfSexChanger(t *testing.T) {
 func TestHandlerMsgMhfEnterTournamentQuest(t *testing.T) {
 	server := createMockServer()
 	server.tournamentRepo = &mockTournamentRepo{}
+	ensureTournamentService(server)
 	session := createMockSession(1, server)
 
 	pkt := &mhfpacket.MsgMhfEnterTournamentQuest{AckHandle: 1}
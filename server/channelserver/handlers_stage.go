@@ -91,29 +91,32 @@ func doStageTransfer(s *Session, ackHandle uint32, stageID string) {
 		// Notify the client to duplicate the existing objects.
 		s.logger.Info("Sending existing stage objects", zap.String("session", s.Name))
 
-		// Lock stage to safely iterate over objects map
-		// We need to copy the objects list first to avoid holding the lock during packet building
+		// Snapshot each object's state as packets under the stage lock, then
+		// serialize them without holding it.
 		s.stage.RLock()
-		var objectList []*Object
+		var objectPackets []mhfpacket.MHFPacket
 		for _, obj := range s.stage.objects {
 			if obj.ownerCharID == s.charID {
 				continue
 			}
-			objectList = append(objectList, obj)
-		}
-		s.stage.RUnlock()
-
-		// Build packets for each object without holding the lock
-		var temp mhfpacket.MHFPacket
-		for _, obj := range objectList {
-			temp = &mhfpacket.MsgSysDuplicateObject{
+			objectPackets = append(objectPackets, &mhfpacket.MsgSysDuplicateObject{
 				ObjID:       obj.id,
 				X:           obj.x,
 				Y:           obj.y,
 				Z:           obj.z,
 				Unk0:        0,
 				OwnerCharID: obj.ownerCharID,
+			})
+			if obj.rotation != 0 {
+				objectPackets = append(objectPackets, &mhfpacket.MsgSysRotateObject{ObjID: obj.id, Rotation: obj.rotation})
+			}
+			if obj.hidden {
+				objectPackets = append(objectPackets, &mhfpacket.MsgSysHideObject{ObjID: obj.id})
 			}
+		}
+		s.stage.RUnlock()
+
+		for _, temp := range objectPackets {
 			newNotif.WriteUint16(uint16(temp.Opcode()))
 			_ = temp.Build(newNotif, s.clientContext)
 		}
@@ -152,18 +155,7 @@ func removeSessionFromStage(s *Session) {
 	delete(s.stage.clients, s)
 
 	// Delete old stage objects owned by the client.
-	// We must copy the objects to delete to avoid modifying the map while iterating
-	var objectsToDelete []*Object
-	for _, object := range s.stage.objects {
-		if object.ownerCharID == s.charID {
-			objectsToDelete = append(objectsToDelete, object)
-		}
-	}
-
-	// Delete from map while still holding lock
-	for _, object := range objectsToDelete {
-		delete(s.stage.objects, object.ownerCharID)
-	}
+	objectsToDelete := s.stage.removeObjectsOwnedBy(s.charID)
 
 	// CRITICAL FIX: Unlock BEFORE broadcasting to avoid deadlock
 	// BroadcastMHF also tries to lock the stage, so we must release our lock first
@@ -208,6 +200,12 @@ func handleMsgSysEnterStage(s *Session, p mhfpacket.MHFPacket) {
 		return
 	}
 
+	// Entering a quest stage is a sortie if the quest is part of a fort attack.
+	if len(pkt.StageID) > 4 && pkt.StageID[3:5] == "Qs" && !fortAttackSortie(s, s.questFile) {
+		doAckSimpleFail(s, pkt.AckHandle, []byte{0x00, 0x00, 0x00, 0x01})
+		return
+	}
+
 	// Push our current stage ID to the movement stack before entering another one.
 	if s.stage != nil {
 		s.stage.Lock()
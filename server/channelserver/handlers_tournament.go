@@ -56,6 +56,14 @@ type TournamentReward struct {
 	Unk2 uint16
 }
 
+// runTournaments pays out and rolls forward tournaments until the server
+// shuts down.
+func (s *Server) runTournaments() {
+	s.runPeriodic(tournamentTickInterval, func() {
+		s.tournamentService.Tick(TimeAdjusted())
+	})
+}
+
 // tournamentIsValid reports whether a tournament row has plausible timestamps.
 // A row with any non-positive timestamp is treated as malformed — emitting it
 // to the ZZ client (especially with state=3) is known to crash quest counters
@@ -123,7 +131,7 @@ func handleMsgMhfEnumerateRanking(s *Session, p mhfpacket.MHFPacket) {
 	bf.WriteUint8(state)
 	ps.Uint8(bf, tournament.Name, true)
 
-	subEvents, err := s.server.tournamentRepo.GetSubEvents()
+	subEvents, err := s.server.tournamentRepo.GetSubEvents(tournament.ID)
 	if err != nil {
 		s.logger.Error("Failed to get tournament sub-events", zap.Error(err))
 		subEvents = nil
@@ -308,12 +316,13 @@ func handleMsgMhfEnterTournamentQuest(s *Session, p mhfpacket.MHFPacket) {
 		zap.Uint32("questSlot", pkt.QuestSlot),
 		zap.Uint32("stageHandle", pkt.StageHandle),
 	)
-	if err := s.server.tournamentRepo.SubmitResult(
+	if err := s.server.tournamentService.Submit(
 		s.charID,
 		pkt.TournamentID,
 		pkt.Unk2,
 		pkt.QuestSlot,
 		pkt.StageHandle,
+		TimeAdjusted(),
 	); err != nil {
 		s.logger.Error("Failed to submit tournament result", zap.Error(err))
 	}
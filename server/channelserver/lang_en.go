@@ -47,6 +47,7 @@ func langEnglish() i18n {
 	i.commands.ravi.noCommand = "No Raviente command specified!"
 	i.commands.ravi.start.success = "The Great Slaying will begin in a moment"
 	i.commands.ravi.start.error = "The Great Slaying has already begun!"
+	i.commands.ravi.start.closed = "The Great Slaying can only begin during its scheduled hours"
 	i.commands.ravi.multiplier = "Raviente multiplier is currently %.2fx"
 	i.commands.ravi.res.success = "Sending resurrection support!"
 	i.commands.ravi.res.error = "Resurrection support has not been requested!"
@@ -47,6 +47,7 @@ func langSpanish() i18n {
 	i.commands.ravi.noCommand = "No se especificó ningún comando de Raviente"
 	i.commands.ravi.start.success = "La Gran Cacería comenzará en un momento"
 	i.commands.ravi.start.error = "¡La Gran Cacería ya ha comenzado!"
+	i.commands.ravi.start.closed = "La Gran Cacería solo puede comenzar en su horario programado"
 	i.commands.ravi.multiplier = "El multiplicador de Raviente es actualmente %.2fx"
 	i.commands.ravi.res.success = "¡Enviando apoyo de resurrección!"
 	i.commands.ravi.res.error = "¡El apoyo de resurrección no ha sido solicitado!"
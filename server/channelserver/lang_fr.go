@@ -47,6 +47,7 @@ func langFrench() i18n {
 	i.commands.ravi.noCommand = "Aucune commande Raviente spécifiée !"
 	i.commands.ravi.start.success = "La Grande Chasse va commencer dans un instant"
 	i.commands.ravi.start.error = "La Grande Chasse a déjà commencé !"
+	i.commands.ravi.start.closed = "La Grande Chasse ne peut commencer qu'aux horaires prévus"
 	i.commands.ravi.multiplier = "Le multiplicateur Raviente est actuellement de %.2fx"
 	i.commands.ravi.res.success = "Envoi du soutien de résurrection !"
 	i.commands.ravi.res.error = "Le soutien de résurrection n'a pas été demandé !"
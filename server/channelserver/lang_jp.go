@@ -47,6 +47,7 @@ func langJapanese() i18n {
 	i.commands.ravi.noCommand = "ラヴィコマンドが指定されていません"
 	i.commands.ravi.start.success = "大討伐を開始します"
 	i.commands.ravi.start.error = "大討伐は既に開催されています"
+	i.commands.ravi.start.closed = "大討伐は予定された時間帯にのみ開始できます"
 	i.commands.ravi.multiplier = "ラヴィダメージ倍率：ｘ%.2f"
 	i.commands.ravi.res.success = "復活支援を実行します"
 	i.commands.ravi.res.error = "復活支援は実行されませんでした"
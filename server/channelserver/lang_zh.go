@@ -47,6 +47,7 @@ func langChinese() i18n {
 	i.commands.ravi.noCommand = "未指定 Raviente 命令！"
 	i.commands.ravi.start.success = "大讨伐战即将开始"
 	i.commands.ravi.start.error = "大讨伐战已经开始！"
+	i.commands.ravi.start.closed = "大讨伐战只能在预定时间内开始"
 	i.commands.ravi.multiplier = "Raviente 倍率当前为 %.2fx"
 	i.commands.ravi.res.success = "正在发送复活支援！"
 	i.commands.ravi.res.error = "尚未请求复活支援！"
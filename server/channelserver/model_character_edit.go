@@ -0,0 +1,307 @@
+package channelserver
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	cfg "erupe-ce/config"
+)
+
+// ErrSaveUnreadable wraps failures to decompress or parse a stored savedata
+// blob.
+var ErrSaveUnreadable = errors.New("savedata unreadable")
+
+// saveFieldSizes gives the bytes read at each pointer by
+// updateStructWithSaveData; DecodeSave checks them against the blob length
+// before parsing.
+var saveFieldSizes = map[SavePointer]int{
+	pGender:      1,
+	pRP:          saveFieldRP,
+	pHouseTier:   saveFieldHouseTier,
+	pHouseData:   saveFieldHouseData,
+	pGalleryData: saveFieldGallery,
+	pToreData:    saveFieldTore,
+	pGardenData:  saveFieldGarden,
+	pPlaytime:    saveFieldPlaytime,
+	pWeaponType:  1,
+	pWeaponID:    saveFieldWeaponID,
+	pHR:          saveFieldHR,
+	pGRP:         saveFieldGRP,
+	pKQF:         saveFieldKQF,
+}
+
+// DecodeSave decompresses and parses a stored savedata blob with the layout
+// of mode. Unlike the login path it rejects blobs too short for the layout
+// rather than panicking on them; every error wraps ErrSaveUnreadable.
+func DecodeSave(charID uint32, mode cfg.Mode, comp []byte) (*CharacterSaveData, error) {
+	save := &CharacterSaveData{
+		CharID:   charID,
+		Mode:     mode,
+		Pointers: getPointers(mode),
+		compSave: comp,
+	}
+	if err := save.Decompress(); err != nil {
+		return nil, fmt.Errorf("%w: decompress: %w", ErrSaveUnreadable, err)
+	}
+	if len(save.decompSave) < saveFieldNameOffset+saveFieldNameLen {
+		return nil, fmt.Errorf("%w: too short (%d bytes)", ErrSaveUnreadable, len(save.decompSave))
+	}
+	for ptr, size := range saveFieldSizes {
+		if off, ok := save.Pointers[ptr]; ok && off+size > len(save.decompSave) {
+			return nil, fmt.Errorf("%w: too short for client mode %s (%d bytes)", ErrSaveUnreadable, mode.Name(), len(save.decompSave))
+		}
+	}
+	save.updateStructWithSaveData()
+	return save, nil
+}
+
+// SaveFields is the JSON view of every field the save layout maps for the
+// save's client mode. Byte sections are hex encoded. Fields the mode does not
+// map are omitted.
+type SaveFields struct {
+	Mode               string  `json:"mode"`
+	Size               int     `json:"size"`
+	Name               string  `json:"name"`
+	IsFemale           bool    `json:"isFemale"`
+	RP                 *uint16 `json:"rp,omitempty"`
+	HR                 *uint16 `json:"hr,omitempty"`
+	GRP                *uint32 `json:"grp,omitempty"`
+	GR                 *uint16 `json:"gr,omitempty"`
+	Playtime           *uint32 `json:"playtime,omitempty"`
+	WeaponType         *uint8  `json:"weaponType,omitempty"`
+	WeaponID           *uint16 `json:"weaponId,omitempty"`
+	Zenny              *uint32 `json:"zenny,omitempty"`
+	GZenny             *uint32 `json:"gzenny,omitempty"`
+	CP                 *uint32 `json:"cp,omitempty"`
+	KQF                string  `json:"kqf,omitempty"`
+	HouseTier          string  `json:"houseTier,omitempty"`
+	HouseData          string  `json:"houseData,omitempty"`
+	BookshelfData      string  `json:"bookshelfData,omitempty"`
+	GalleryData        string  `json:"galleryData,omitempty"`
+	ToreData           string  `json:"toreData,omitempty"`
+	GardenData         string  `json:"gardenData,omitempty"`
+	CurrentEquipOffset *int    `json:"currentEquipOffset,omitempty"`
+}
+
+// Fields returns the parsed fields of a save decoded with DecodeSave.
+func (save *CharacterSaveData) Fields() SaveFields {
+	f := SaveFields{
+		Mode:     save.Mode.Name(),
+		Size:     len(save.decompSave),
+		Name:     save.Name,
+		IsFemale: save.Gender,
+	}
+	if save.Mode < cfg.S6 {
+		return f
+	}
+	f.RP, f.HR, f.GR = &save.RP, &save.HR, &save.GR
+	f.Playtime, f.WeaponType, f.WeaponID = &save.Playtime, &save.WeaponType, &save.WeaponID
+	if save.Mode >= cfg.G1 {
+		grp := binary.LittleEndian.Uint32(save.decompSave[save.Pointers[pGRP]:])
+		f.GRP = &grp
+	}
+	if _, ok := save.mappedOffset(pZenny, saveFieldZenny); ok {
+		f.Zenny = &save.Zenny
+	}
+	if _, ok := save.mappedOffset(pGZenny, saveFieldGZenny); ok {
+		f.GZenny = &save.GZenny
+	}
+	if _, ok := save.mappedOffset(pCP, saveFieldCP); ok {
+		f.CP = &save.CP
+	}
+	if off, ok := save.Pointers[pCurrentEquip]; ok {
+		f.CurrentEquipOffset = &off
+	}
+	f.KQF = hex.EncodeToString(save.KQF)
+	f.HouseTier = hex.EncodeToString(save.HouseTier)
+	f.HouseData = hex.EncodeToString(save.HouseData)
+	f.BookshelfData = hex.EncodeToString(save.BookshelfData)
+	f.GalleryData = hex.EncodeToString(save.GalleryData)
+	f.ToreData = hex.EncodeToString(save.ToreData)
+	f.GardenData = hex.EncodeToString(save.GardenData)
+	return f
+}
+
+// mappedOffset returns the offset of ptr when the mode maps it and size bytes
+// fit in the blob, using the same guards as the zenny / gzenny / CP reads.
+func (save *CharacterSaveData) mappedOffset(ptr SavePointer, size int) (int, bool) {
+	off, ok := save.Pointers[ptr]
+	if !ok || off <= 0 || off+size > len(save.decompSave) {
+		return 0, false
+	}
+	return off, true
+}
+
+// saveIntField describes an editable integer field: where it lives, its
+// width in bytes, the accepted range and the first client mode that reads it.
+type saveIntField struct {
+	ptr      SavePointer
+	size     int
+	min, max uint64
+	minMode  cfg.Mode
+}
+
+// saveIntFields lists the integer fields accepted by SetField. GRP is capped
+// below the last bracket of grpToGR.
+var saveIntFields = map[string]saveIntField{
+	"rp":         {pRP, saveFieldRP, 0, math.MaxUint16, cfg.S6},
+	"hr":         {pHR, saveFieldHR, 1, 999, cfg.S6},
+	"grp":        {pGRP, saveFieldGRP, 0, 99999999, cfg.G1},
+	"playtime":   {pPlaytime, saveFieldPlaytime, 0, math.MaxUint32, cfg.S6},
+	"weaponType": {pWeaponType, 1, 0, 13, cfg.S6},
+	"weaponId":   {pWeaponID, saveFieldWeaponID, 0, math.MaxUint16, cfg.S6},
+	"zenny":      {pZenny, saveFieldZenny, 0, math.MaxUint32, cfg.S6},
+	"gzenny":     {pGZenny, saveFieldGZenny, 0, math.MaxUint32, cfg.S6},
+	"cp":         {pCP, saveFieldCP, 0, math.MaxUint32, cfg.S6},
+}
+
+// SaveFieldNames lists the field names accepted by SetField. "kqf.N" sets or
+// clears bit N (0-63) of the KQF flags.
+func SaveFieldNames() []string {
+	return []string{"isFemale", "rp", "hr", "grp", "playtime", "weaponType", "weaponId",
+		"zenny", "gzenny", "cp", "kqf", "kqf.N", "houseTier"}
+}
+
+// SetField validates value and writes it into the decompressed save at the
+// field's offset, then re-parses the struct fields. Names match the JSON keys
+// of SaveFields. The save is unchanged when an error is returned.
+func (save *CharacterSaveData) SetField(name, value string) error {
+	if f, ok := saveIntFields[name]; ok {
+		return save.setIntField(name, f, value)
+	}
+	switch {
+	case name == "isFemale":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("isFemale: %q is not a boolean", value)
+		}
+		save.decompSave[save.Pointers[pGender]] = 0
+		if b {
+			save.decompSave[save.Pointers[pGender]] = 1
+		}
+	case name == "kqf":
+		off, err := save.editableOffset(name, pKQF, saveFieldKQF, cfg.G10)
+		if err != nil {
+			return err
+		}
+		kqf, err := hex.DecodeString(value)
+		if err != nil || len(kqf) != saveFieldKQF {
+			return fmt.Errorf("kqf: want %d hex-encoded bytes", saveFieldKQF)
+		}
+		copy(save.decompSave[off:], kqf)
+	case strings.HasPrefix(name, "kqf."):
+		off, err := save.editableOffset("kqf", pKQF, saveFieldKQF, cfg.G10)
+		if err != nil {
+			return err
+		}
+		bit, err := strconv.Atoi(strings.TrimPrefix(name, "kqf."))
+		if err != nil || bit < 0 || bit >= saveFieldKQF*8 {
+			return fmt.Errorf("%s: flag index must be 0-%d", name, saveFieldKQF*8-1)
+		}
+		set, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a boolean", name, value)
+		}
+		mask := byte(1) << (bit % 8)
+		if set {
+			save.decompSave[off+bit/8] |= mask
+		} else {
+			save.decompSave[off+bit/8] &^= mask
+		}
+	case name == "houseTier":
+		off, err := save.editableOffset(name, pHouseTier, saveFieldHouseTier, cfg.S6)
+		if err != nil {
+			return err
+		}
+		tier, err := hex.DecodeString(value)
+		if err != nil || len(tier) != saveFieldHouseTier {
+			return fmt.Errorf("houseTier: want %d hex-encoded bytes", saveFieldHouseTier)
+		}
+		for _, b := range tier {
+			if b == 0xFF {
+				return errors.New("houseTier: 0xFF bytes are never valid")
+			}
+		}
+		copy(save.decompSave[off:], tier)
+	default:
+		return fmt.Errorf("unknown field %q (editable: %s)", name, strings.Join(SaveFieldNames(), ", "))
+	}
+	save.refresh()
+	return nil
+}
+
+func (save *CharacterSaveData) setIntField(name string, f saveIntField, value string) error {
+	off, err := save.editableOffset(name, f.ptr, f.size, f.minMode)
+	if err != nil {
+		return err
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || n < f.min || n > f.max {
+		return fmt.Errorf("%s: %q is outside %d-%d", name, value, f.min, f.max)
+	}
+	switch f.size {
+	case 1:
+		save.decompSave[off] = uint8(n)
+	case 2:
+		binary.LittleEndian.PutUint16(save.decompSave[off:], uint16(n))
+	case 4:
+		binary.LittleEndian.PutUint32(save.decompSave[off:], uint32(n))
+	}
+	save.refresh()
+	return nil
+}
+
+// editableOffset returns the offset of a field the save's mode both maps and
+// reads.
+func (save *CharacterSaveData) editableOffset(name string, ptr SavePointer, size int, minMode cfg.Mode) (int, error) {
+	off, ok := save.mappedOffset(ptr, size)
+	if !ok || save.Mode < minMode {
+		return 0, fmt.Errorf("%s is not mapped for client mode %s", name, save.Mode.Name())
+	}
+	return off, nil
+}
+
+// refresh re-parses the struct fields after a direct edit of decompSave. GR
+// is only derived when HR is 999, so it is cleared first.
+func (save *CharacterSaveData) refresh() {
+	save.GR = 0
+	save.updateStructWithSaveData()
+}
+
+// encode recompresses decompSave the way Save does for the save's mode.
+func (save *CharacterSaveData) encode() error {
+	if save.Mode >= cfg.G1 {
+		return save.Compress()
+	}
+	// Saves were not compressed
+	save.compSave = save.decompSave
+	return nil
+}
+
+// atomicParams builds the SaveCharacterDataAtomic parameters for the current
+// compSave, hashing decompSave.
+func (save *CharacterSaveData) atomicParams() SaveAtomicParams {
+	hash := sha256.Sum256(save.decompSave)
+	return SaveAtomicParams{
+		CharID:        save.CharID,
+		CompSave:      save.compSave,
+		Hash:          hash[:],
+		HR:            save.HR,
+		GR:            save.GR,
+		IsFemale:      save.Gender,
+		WeaponType:    save.WeaponType,
+		WeaponID:      save.WeaponID,
+		HouseTier:     save.HouseTier,
+		HouseData:     save.HouseData,
+		BookshelfData: save.BookshelfData,
+		GalleryData:   save.GalleryData,
+		ToreData:      save.ToreData,
+		GardenData:    save.GardenData,
+	}
+}
@@ -0,0 +1,164 @@
+package channelserver
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	cfg "erupe-ce/config"
+	"erupe-ce/server/channelserver/compression/nullcomp"
+)
+
+func decodeTestSave(t *testing.T, mode cfg.Mode) *CharacterSaveData {
+	t.Helper()
+	save, err := DecodeSave(1, mode, buildTestSave(t, "Hunter", 7, 1000))
+	if err != nil {
+		t.Fatalf("DecodeSave: %v", err)
+	}
+	return save
+}
+
+func TestDecodeSave_RejectsShortBlobs(t *testing.T) {
+	short, _ := nullcomp.Compress(make([]byte, 100000))
+	if _, err := DecodeSave(1, cfg.ZZ, short); !errors.Is(err, ErrSaveUnreadable) {
+		t.Errorf("err = %v, want ErrSaveUnreadable", err)
+	}
+	if _, err := DecodeSave(1, cfg.ZZ, []byte{1, 2}); !errors.Is(err, ErrSaveUnreadable) {
+		t.Errorf("tiny blob: err = %v, want ErrSaveUnreadable", err)
+	}
+}
+
+func TestSaveFields(t *testing.T) {
+	f := decodeTestSave(t, cfg.ZZ).Fields()
+	if f.Mode != "ZZ" || f.Name != "Hunter" || f.Size != 150000 {
+		t.Errorf("fields = %+v", f)
+	}
+	if f.HR == nil || *f.HR != 7 || f.Zenny == nil || *f.Zenny != 1000 {
+		t.Errorf("hr/zenny not reported: %+v", f)
+	}
+	if f.GRP == nil || f.CurrentEquipOffset == nil || len(f.KQF) != saveFieldKQF*2 {
+		t.Errorf("grp/currentEquip/kqf not reported: %+v", f)
+	}
+}
+
+func TestSaveFields_UnmappedFieldsOmitted(t *testing.T) {
+	f := decodeTestSave(t, cfg.Z2).Fields()
+	if f.Zenny != nil || f.GZenny != nil || f.CP != nil || f.CurrentEquipOffset != nil {
+		t.Errorf("Z2 has no zenny/gzenny/cp/current equip mapping: %+v", f)
+	}
+	if f.HR == nil {
+		t.Error("hr should be reported for Z2")
+	}
+}
+
+func TestSetField_Integers(t *testing.T) {
+	save := decodeTestSave(t, cfg.ZZ)
+
+	for _, e := range []SaveEdit{{"zenny", "123456"}, {"hr", "999"}, {"grp", "300000"}, {"weaponType", "13"}} {
+		if err := save.SetField(e.Field, e.Value); err != nil {
+			t.Fatalf("SetField(%s): %v", e.Field, err)
+		}
+	}
+	if save.Zenny != 123456 || save.HR != 999 || save.WeaponType != 13 {
+		t.Errorf("struct not refreshed: zenny %d hr %d weaponType %d", save.Zenny, save.HR, save.WeaponType)
+	}
+	if save.GR == 0 {
+		t.Error("GR not derived from GRP at HR 999")
+	}
+	if got := binary.LittleEndian.Uint32(save.decompSave[save.Pointers[pZenny]:]); got != 123456 {
+		t.Errorf("zenny bytes = %d, want 123456", got)
+	}
+
+	if err := save.SetField("hr", "500"); err != nil {
+		t.Fatal(err)
+	}
+	if save.GR != 0 {
+		t.Errorf("GR = %d after dropping below HR 999, want 0", save.GR)
+	}
+}
+
+func TestSetField_BoundsValidation(t *testing.T) {
+	save := decodeTestSave(t, cfg.ZZ)
+	before := append([]byte(nil), save.decompSave...)
+
+	for _, e := range []SaveEdit{
+		{"hr", "0"},
+		{"hr", "1000"},
+		{"rp", "65536"},
+		{"zenny", "-1"},
+		{"zenny", "lots"},
+		{"weaponType", "14"},
+		{"isFemale", "maybe"},
+		{"kqf", "00"},
+		{"kqf.64", "true"},
+		{"houseTier", "ffffffffff"},
+		{"name", "Other"},
+	} {
+		if err := save.SetField(e.Field, e.Value); err == nil {
+			t.Errorf("SetField(%s, %s) accepted", e.Field, e.Value)
+		}
+	}
+	if string(save.decompSave) != string(before) {
+		t.Error("rejected edits modified the save")
+	}
+}
+
+func TestSetField_UnmappedForMode(t *testing.T) {
+	save := decodeTestSave(t, cfg.Z2)
+	if err := save.SetField("zenny", "1"); err == nil {
+		t.Error("zenny edit accepted on Z2, which has no zenny offset")
+	}
+
+	save = decodeTestSave(t, cfg.G9)
+	if err := save.SetField("kqf.0", "true"); err == nil {
+		t.Error("kqf edit accepted before G10")
+	}
+}
+
+func TestSetField_KQF(t *testing.T) {
+	save := decodeTestSave(t, cfg.ZZ)
+
+	if err := save.SetField("kqf", "0100000000000080"); err != nil {
+		t.Fatal(err)
+	}
+	if save.KQF[0] != 0x01 || save.KQF[7] != 0x80 {
+		t.Errorf("kqf = %x", save.KQF)
+	}
+	if err := save.SetField("kqf.9", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := save.SetField("kqf.0", "false"); err != nil {
+		t.Fatal(err)
+	}
+	if save.KQF[0] != 0x00 || save.KQF[1] != 0x02 {
+		t.Errorf("kqf = %x, want bit 0 cleared and bit 9 set", save.KQF)
+	}
+}
+
+func TestSetField_IsFemale(t *testing.T) {
+	save := decodeTestSave(t, cfg.ZZ)
+	if err := save.SetField("isFemale", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if !save.Gender || save.decompSave[save.Pointers[pGender]] != 1 {
+		t.Error("gender not set")
+	}
+}
+
+func TestAtomicParams(t *testing.T) {
+	save := decodeTestSave(t, cfg.ZZ)
+	if err := save.SetField("hr", "50"); err != nil {
+		t.Fatal(err)
+	}
+	if err := save.encode(); err != nil {
+		t.Fatal(err)
+	}
+	params := save.atomicParams()
+	if params.CharID != 1 || params.HR != 50 || len(params.Hash) != 32 {
+		t.Errorf("params = CharID %d HR %d hash %d bytes", params.CharID, params.HR, len(params.Hash))
+	}
+	roundTrip, err := DecodeSave(1, cfg.ZZ, params.CompSave)
+	if err != nil || roundTrip.HR != 50 {
+		t.Errorf("re-decoded HR = %v, %v", roundTrip, err)
+	}
+}
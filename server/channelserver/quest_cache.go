@@ -2,6 +2,7 @@ package channelserver
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,6 +23,9 @@ type QuestCache struct {
 	data   map[questCacheKey][]byte
 	expiry map[questCacheKey]time.Time
 	ttl    time.Duration
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
 }
 
 // NewQuestCache creates a QuestCache with the given TTL in seconds.
@@ -37,6 +41,16 @@ func NewQuestCache(ttlSeconds int) *QuestCache {
 // Get returns cached quest data for the (questID, lang) variant if it exists
 // and has not expired.
 func (c *QuestCache) Get(questID int, lang string) ([]byte, bool) {
+	b, ok := c.get(questID, lang)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return b, ok
+}
+
+func (c *QuestCache) get(questID int, lang string) ([]byte, bool) {
 	if c.ttl <= 0 {
 		return nil, false
 	}
@@ -53,6 +67,11 @@ func (c *QuestCache) Get(questID int, lang string) ([]byte, bool) {
 	return b, true
 }
 
+// Stats returns the number of Get calls that hit and missed the cache.
+func (c *QuestCache) Stats() (hits, misses uint64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
 // Put stores quest data for the (questID, lang) variant with the configured TTL.
 func (c *QuestCache) Put(questID int, lang string, b []byte) {
 	k := questCacheKey{questID: questID, lang: lang}
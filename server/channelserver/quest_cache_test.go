@@ -95,3 +95,15 @@ func TestQuestCache_ConcurrentAccess(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestQuestCache_Stats(t *testing.T) {
+	c := NewQuestCache(60)
+	c.Put(1, "jp", []byte{0x01})
+	c.Get(1, "jp")
+	c.Get(1, "en")
+
+	hits, misses := c.Stats()
+	if hits != 1 || misses != 1 {
+		t.Errorf("Stats() = (%d, %d), want (1, 1)", hits, misses)
+	}
+}
@@ -3,34 +3,253 @@ package channelserver
 import (
 	"strings"
 	"sync"
+	"time"
 
 	"erupe-ce/common/byteframe"
 	ps "erupe-ce/common/pascalstring"
+	cfg "erupe-ce/config"
 	"erupe-ce/network/mhfpacket"
 
 	"go.uber.org/zap"
 )
 
 // Raviente holds shared state for the Raviente siege event.
+//
+// The state is saved and restored per server_id, so each channel still runs
+// its own siege.
+// TODO: share one siege between the channels of a world. That needs a single
+// raviente_sieges row per world, with each channel merging its register
+// deltas into it under a row lock at every sync.
 type Raviente struct {
 	sync.Mutex
 	id       uint16
 	register []uint32
 	state    []uint32
 	support  []uint32
+
+	// siegeID is the raviente_sieges row of the current siege, 0 until it
+	// is first saved.
+	siegeID uint32
+	// participants holds every character that joined the current siege;
+	// joined lists those not saved yet.
+	participants map[uint32]bool
+	joined       []uint32
+
+	// persist orders this channel's siege writes so a siege ended by
+	// resetRaviente is stored before the next sync touches the table. It
+	// is taken before the mutex and never while semaphoreLock is held.
+	persist sync.Mutex
+}
+
+// raviSiegeEnd is the final state of a siege reset by resetRaviente, kept
+// so it can be written once the caller has released semaphoreLock.
+type raviSiegeEnd struct {
+	siegeID      uint32
+	raviID       uint16
+	joined       []uint32
+	participants int
+	register     []uint32
+	state        []uint32
+	support      []uint32
+}
+
+// join records charID as a participant of the current siege.
+func (r *Raviente) join(charID uint32) {
+	r.Lock()
+	defer r.Unlock()
+	if r.participants == nil {
+		r.participants = make(map[uint32]bool)
+	}
+	if !r.participants[charID] {
+		r.participants[charID] = true
+		r.joined = append(r.joined, charID)
+	}
 }
 
-func (s *Server) resetRaviente() {
+// startLocked starts a waiting siege by copying register slot 3 into slot 1,
+// reporting false if it has already begun.
+func (r *Raviente) startLocked() bool {
+	if r.register[1] != 0 {
+		return false
+	}
+	r.register[1] = r.register[3]
+	return true
+}
+
+// resetRaviente starts a new siege once every Raviente semaphore is gone.
+// It is called with semaphoreLock held, so it only swaps the in-memory
+// state; the old siege is returned for endRaviSiege to write after the
+// lock is released, or nil if there is nothing to record.
+func (s *Server) resetRaviente() *raviSiegeEnd {
 	for _, semaphore := range s.semaphore {
 		if strings.HasPrefix(semaphore.name, "hs_l0") {
-			return
+			return nil
 		}
 	}
 	s.logger.Debug("All Raviente Semaphores empty, resetting")
-	s.raviente.id = s.raviente.id + 1
-	s.raviente.register = make([]uint32, 30)
-	s.raviente.state = make([]uint32, 30)
-	s.raviente.support = make([]uint32, 30)
+	r := s.raviente
+	r.Lock()
+	defer r.Unlock()
+	var end *raviSiegeEnd
+	if r.siegeID != 0 || len(r.joined) > 0 {
+		end = &raviSiegeEnd{
+			siegeID:      r.siegeID,
+			raviID:       r.id,
+			joined:       r.joined,
+			participants: len(r.participants),
+			register:     r.register,
+			state:        r.state,
+			support:      r.support,
+		}
+	}
+	r.id = r.id + 1
+	r.siegeID = 0
+	r.register = make([]uint32, raviRegisterSize)
+	r.state = make([]uint32, raviRegisterSize)
+	r.support = make([]uint32, raviRegisterSize)
+	r.participants = nil
+	r.joined = nil
+	return end
+}
+
+// endRaviSiege writes the final state and summary of a siege returned by
+// resetRaviente. It must not be called with semaphoreLock held.
+func (s *Server) endRaviSiege(end *raviSiegeEnd) {
+	if end == nil || s.ravienteRepo == nil {
+		return
+	}
+	s.raviente.persist.Lock()
+	defer s.raviente.persist.Unlock()
+	if end.siegeID == 0 {
+		id, err := s.ravienteRepo.BeginSiege(s.ID, end.raviID)
+		if err != nil {
+			s.logger.Error("Failed to record Raviente siege", zap.Error(err))
+			return
+		}
+		end.siegeID = id
+	}
+	if err := s.ravienteRepo.AddParticipants(end.siegeID, end.joined); err != nil {
+		s.logger.Error("Failed to record Raviente participants", zap.Error(err))
+	}
+	if err := s.ravienteRepo.EndSiege(end.siegeID, end.register, end.state, end.support); err != nil {
+		s.logger.Error("Failed to record Raviente siege result", zap.Error(err))
+		return
+	}
+	s.logger.Info("Raviente siege ended", zap.Uint32("siegeID", end.siegeID),
+		zap.Int("phase", raviPhase(end.state)), zap.Int("participants", end.participants))
+}
+
+// restoreRaviente loads the siege this channel had open when it last
+// stopped, so a restart does not lose its progress.
+func (s *Server) restoreRaviente() {
+	r := s.raviente
+	r.Lock()
+	defer r.Unlock()
+	siege, err := s.ravienteRepo.GetOpenSiege(s.ID)
+	if err != nil {
+		s.logger.Error("Failed to load Raviente siege", zap.Error(err))
+		return
+	}
+	if siege == nil {
+		// Carry on numbering from the last siege so clients never see an
+		// ID reused after a restart.
+		if last, err := s.ravienteRepo.LastRaviID(s.ID); err == nil && last > 0 {
+			r.id = last + 1
+		}
+		return
+	}
+	r.id = siege.RaviID
+	r.siegeID = siege.ID
+	r.register = siege.Register
+	r.state = siege.State
+	r.support = siege.Support
+	s.logger.Info("Restored Raviente siege",
+		zap.Uint32("siegeID", siege.ID), zap.Int("phase", siege.Phase()))
+}
+
+// syncRaviente saves the siege state every Raviente.SyncSeconds and applies
+// start requests from the API and scheduled windows.
+func (s *Server) syncRaviente() {
+	interval := time.Duration(s.erupeConfig.Raviente.SyncSeconds) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	last := time.Now()
+	s.runPeriodic(interval, func() {
+		now := time.Now()
+		s.flushRaviente(last, now)
+		last = now
+	})
+	// Save once more on shutdown so the last interval is not lost.
+	s.flushRaviente(last, time.Now())
+}
+
+// raviLoad returns the number of players in the siege and the damage
+// multiplier they get.
+func (s *Server) raviLoad() (int, float64) {
+	s.semaphoreLock.Lock()
+	defer s.semaphoreLock.Unlock()
+	sema := s.getRaviSemaphore()
+	if sema == nil {
+		return 0, 0
+	}
+	s.raviente.Lock()
+	defer s.raviente.Unlock()
+	return len(sema.clients), s.GetRaviMultiplier()
+}
+
+// flushRaviente saves the current siege, starting it first if an operator
+// asked for that through the API or a scheduled window opened after prev.
+func (s *Server) flushRaviente(prev, now time.Time) {
+	r := s.raviente
+	r.persist.Lock()
+	defer r.persist.Unlock()
+	players, multiplier := s.raviLoad()
+	r.Lock()
+	if !s.saveRaviSiegeLocked() {
+		r.Unlock()
+		return
+	}
+	start, err := s.ravienteRepo.TakeStartRequest(r.siegeID)
+	if err != nil {
+		s.logger.Error("Failed to read Raviente start request", zap.Error(err))
+	}
+	if RavienteWindowOpened(s.erupeConfig.Raviente.Windows, prev, now) && players > 0 {
+		start = true
+	}
+	started := start && r.startLocked()
+	if err := s.ravienteRepo.SaveSiege(r.siegeID, r.register, r.state, r.support, multiplier, players); err != nil {
+		s.logger.Error("Failed to save Raviente siege", zap.Error(err))
+	}
+	r.Unlock()
+	if started {
+		s.logger.Info("Raviente siege started by schedule or API")
+		s.notifyRaviAll()
+	}
+}
+
+// saveRaviSiegeLocked creates the current siege's row once someone has
+// joined and saves its new participants. It reports whether there is a
+// saved siege.
+func (s *Server) saveRaviSiegeLocked() bool {
+	r := s.raviente
+	if r.siegeID == 0 {
+		if len(r.joined) == 0 {
+			return false
+		}
+		id, err := s.ravienteRepo.BeginSiege(s.ID, r.id)
+		if err != nil {
+			s.logger.Error("Failed to record Raviente siege", zap.Error(err))
+			return false
+		}
+		r.siegeID = id
+	}
+	if err := s.ravienteRepo.AddParticipants(r.siegeID, r.joined); err != nil {
+		s.logger.Error("Failed to record Raviente participants", zap.Error(err))
+	} else {
+		r.joined = nil
+	}
+	return true
 }
 
 func (s *Server) GetRaviMultiplier() float64 {
@@ -42,7 +261,7 @@ func (s *Server) GetRaviMultiplier() float64 {
 		} else {
 			minPlayers = 4
 		}
-		if len(raviSema.clients) > minPlayers {
+		if len(raviSema.clients) == 0 || len(raviSema.clients) > minPlayers {
 			return 1
 		}
 		return float64(minPlayers / len(raviSema.clients))
@@ -117,3 +336,101 @@ func (s *Server) getRaviSemaphore() *Semaphore {
 	}
 	return nil
 }
+
+// raviWindow is a parsed cfg.RavienteWindow.
+type raviWindow struct {
+	days     map[time.Weekday]bool // nil for every day
+	start    time.Duration         // offset from midnight
+	duration time.Duration
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// parseRaviWindows returns the valid windows; malformed ones are dropped.
+func parseRaviWindows(windows []cfg.RavienteWindow) []raviWindow {
+	var parsed []raviWindow
+	for _, w := range windows {
+		clock, err := time.Parse("15:04", w.Start)
+		if err != nil || w.Minutes <= 0 {
+			continue
+		}
+		pw := raviWindow{
+			start:    time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute,
+			duration: time.Duration(w.Minutes) * time.Minute,
+		}
+		valid := true
+		for _, name := range w.Weekdays {
+			day, ok := weekdayNames[strings.ToLower(name)]
+			if !ok {
+				valid = false
+				break
+			}
+			if pw.days == nil {
+				pw.days = make(map[time.Weekday]bool)
+			}
+			pw.days[day] = true
+		}
+		if valid {
+			parsed = append(parsed, pw)
+		}
+	}
+	return parsed
+}
+
+// raviOpenings calls fn with each window occurrence that starts between a
+// day before from and a week after it, in no particular order.
+func raviOpenings(windows []raviWindow, from time.Time, fn func(start, end time.Time)) {
+	y, m, d := from.Date()
+	for _, w := range windows {
+		for offset := -1; offset <= 7; offset++ {
+			midnight := time.Date(y, m, d+offset, 0, 0, 0, 0, from.Location())
+			if w.days != nil && !w.days[midnight.Weekday()] {
+				continue
+			}
+			start := midnight.Add(w.start)
+			fn(start, start.Add(w.duration))
+		}
+	}
+}
+
+// RavienteWindowOpen reports whether a siege may be started at now. Without
+// any valid windows configured sieges may start at any time.
+func RavienteWindowOpen(windows []cfg.RavienteWindow, now time.Time) bool {
+	parsed := parseRaviWindows(windows)
+	if len(parsed) == 0 {
+		return true
+	}
+	open := false
+	raviOpenings(parsed, now, func(start, end time.Time) {
+		if !now.Before(start) && now.Before(end) {
+			open = true
+		}
+	})
+	return open
+}
+
+// RavienteWindowOpened reports whether a window opened after prev and no
+// later than now.
+func RavienteWindowOpened(windows []cfg.RavienteWindow, prev, now time.Time) bool {
+	opened := false
+	raviOpenings(parseRaviWindows(windows), now, func(start, _ time.Time) {
+		if start.After(prev) && !start.After(now) {
+			opened = true
+		}
+	})
+	return opened
+}
+
+// NextRavienteWindow returns the window that is open at now, or else the
+// next one to open. ok is false when no valid windows are configured.
+func NextRavienteWindow(windows []cfg.RavienteWindow, now time.Time) (start, end time.Time, ok bool) {
+	raviOpenings(parseRaviWindows(windows), now, func(s, e time.Time) {
+		if e.After(now) && (!ok || s.Before(start)) {
+			start, end, ok = s, e, true
+		}
+	})
+	return start, end, ok
+}
@@ -0,0 +1,242 @@
+package channelserver
+
+import (
+	"testing"
+	"time"
+
+	cfg "erupe-ce/config"
+	"erupe-ce/network/mhfpacket"
+)
+
+func newRaviSyncServer(repo *mockRavienteRepo) *Server {
+	s := createMockServer()
+	s.ID = 0x1010
+	s.semaphore = make(map[string]*Semaphore)
+	s.ravienteRepo = repo
+	s.raviente.id = 7
+	return s
+}
+
+func TestFlushRaviente_NothingBeforeAnyoneJoins(t *testing.T) {
+	repo := &mockRavienteRepo{}
+	s := newRaviSyncServer(repo)
+
+	now := time.Now()
+	s.flushRaviente(now.Add(-time.Minute), now)
+
+	if len(repo.began) != 0 || repo.saved != 0 {
+		t.Errorf("began=%v saved=%d, want no siege recorded", repo.began, repo.saved)
+	}
+}
+
+func TestFlushRaviente_RecordsSiegeAndParticipants(t *testing.T) {
+	repo := &mockRavienteRepo{}
+	s := newRaviSyncServer(repo)
+	addRaviSemaphore(s)
+	sess := createMockSession(42, s)
+	s.getRaviSemaphore().clients[sess] = 42
+	s.raviente.join(42)
+	s.raviente.join(42)
+
+	now := time.Now()
+	s.flushRaviente(now.Add(-time.Minute), now)
+
+	if len(repo.began) != 1 || repo.began[0] != 7 {
+		t.Fatalf("began = %v, want one siege with ravi ID 7", repo.began)
+	}
+	if len(repo.participants) != 1 || repo.participants[0] != 42 {
+		t.Errorf("participants = %v, want [42]", repo.participants)
+	}
+	if repo.saved != 1 || repo.savedPlayers != 1 {
+		t.Errorf("saved=%d players=%d, want 1 save with 1 player", repo.saved, repo.savedPlayers)
+	}
+
+	// A second flush reuses the row and adds nobody new.
+	s.flushRaviente(now, now.Add(time.Minute))
+	if len(repo.began) != 1 || len(repo.participants) != 1 || repo.saved != 2 {
+		t.Errorf("second flush: began=%v participants=%v saved=%d", repo.began, repo.participants, repo.saved)
+	}
+}
+
+func TestFlushRaviente_AppliesStartRequest(t *testing.T) {
+	repo := &mockRavienteRepo{startPending: true}
+	s := newRaviSyncServer(repo)
+	s.raviente.join(1)
+	s.raviente.register[3] = 300
+
+	now := time.Now()
+	s.flushRaviente(now.Add(-time.Minute), now)
+
+	if s.raviente.register[1] != 300 {
+		t.Errorf("register[1] = %d, want 300 after a start request", s.raviente.register[1])
+	}
+}
+
+func TestFlushRaviente_StartsWhenWindowOpens(t *testing.T) {
+	repo := &mockRavienteRepo{}
+	s := newRaviSyncServer(repo)
+	addRaviSemaphore(s)
+	s.getRaviSemaphore().clients[createMockSession(1, s)] = 1
+	s.raviente.join(1)
+	s.raviente.register[3] = 300
+
+	now := time.Now()
+	opening := now.Add(-30 * time.Second).Format("15:04")
+	s.erupeConfig.Raviente.Windows = []cfg.RavienteWindow{{Start: opening, Minutes: 60}}
+	openedAt, _, _ := NextRavienteWindow(s.erupeConfig.Raviente.Windows, now)
+
+	s.flushRaviente(openedAt.Add(-time.Second), openedAt)
+
+	if s.raviente.register[1] != 300 {
+		t.Errorf("register[1] = %d, want the siege started when the window opened", s.raviente.register[1])
+	}
+}
+
+func TestResetRaviente_EndsSiege(t *testing.T) {
+	repo := &mockRavienteRepo{}
+	s := newRaviSyncServer(repo)
+	s.raviente.join(5)
+	s.raviente.state[0] = 1000
+	s.raviente.state[1] = 200
+
+	s.endRaviSiege(s.resetRaviente())
+
+	if len(repo.ended) != 1 || repo.ended[0] != 1 {
+		t.Fatalf("ended = %v, want siege 1 ended", repo.ended)
+	}
+	if repo.endedState[0] != 1000 || repo.endedState[1] != 200 {
+		t.Errorf("ended with state %v, want the final state", repo.endedState[:2])
+	}
+	if s.raviente.siegeID != 0 || s.raviente.id != 8 || len(s.raviente.participants) != 0 {
+		t.Errorf("after reset siegeID=%d id=%d participants=%d", s.raviente.siegeID, s.raviente.id, len(s.raviente.participants))
+	}
+}
+
+func TestDeleteSemaphore_EndsSiegeOutsideSemaphoreLock(t *testing.T) {
+	repo := &mockRavienteRepo{}
+	s := newRaviSyncServer(repo)
+	sess := createMockSession(5, s)
+	sema := NewSemaphore(sess, "hs_l0u3B51", 32)
+	sema.clients[sess] = 5
+	s.semaphore[sema.name] = sema
+	s.raviente.join(5)
+	repo.onEnd = func() {
+		if !s.semaphoreLock.TryLock() {
+			t.Error("siege ended while semaphoreLock was held")
+			return
+		}
+		s.semaphoreLock.Unlock()
+	}
+
+	handleMsgSysDeleteSemaphore(sess, &mhfpacket.MsgSysDeleteSemaphore{SemaphoreID: sema.id})
+
+	if len(repo.ended) != 1 {
+		t.Errorf("ended = %v, want the siege ended once", repo.ended)
+	}
+}
+
+func TestRestoreRaviente(t *testing.T) {
+	register := make([]uint32, raviRegisterSize)
+	register[1] = 9
+	repo := &mockRavienteRepo{open: &RavienteSiege{
+		ID: 12, RaviID: 33, Register: register,
+		State: make([]uint32, raviRegisterSize), Support: make([]uint32, raviRegisterSize),
+	}}
+	s := newRaviSyncServer(repo)
+
+	s.restoreRaviente()
+
+	if s.raviente.siegeID != 12 || s.raviente.id != 33 || s.raviente.register[1] != 9 {
+		t.Errorf("restored siegeID=%d id=%d register[1]=%d", s.raviente.siegeID, s.raviente.id, s.raviente.register[1])
+	}
+}
+
+func TestRestoreRaviente_ContinuesNumbering(t *testing.T) {
+	repo := &mockRavienteRepo{lastRaviID: 40}
+	s := newRaviSyncServer(repo)
+
+	s.restoreRaviente()
+
+	if s.raviente.id != 41 || s.raviente.siegeID != 0 {
+		t.Errorf("id=%d siegeID=%d, want 41 and no open siege", s.raviente.id, s.raviente.siegeID)
+	}
+}
+
+func TestRavienteWindows(t *testing.T) {
+	// Saturday 2026-10-17
+	loc := time.UTC
+	sat := func(h, m int) time.Time { return time.Date(2026, 10, 17, h, m, 0, 0, loc) }
+	windows := []cfg.RavienteWindow{
+		{Weekdays: []string{"Saturday", "sunday"}, Start: "20:00", Minutes: 120},
+		{Start: "bogus", Minutes: 60},
+	}
+
+	tests := []struct {
+		at   time.Time
+		open bool
+	}{
+		{sat(19, 59), false},
+		{sat(20, 0), true},
+		{sat(21, 59), true},
+		{sat(22, 0), false},
+		{time.Date(2026, 10, 19, 20, 30, 0, 0, loc), false}, // Monday
+	}
+	for _, tt := range tests {
+		if got := RavienteWindowOpen(windows, tt.at); got != tt.open {
+			t.Errorf("RavienteWindowOpen(%v) = %v, want %v", tt.at, got, tt.open)
+		}
+	}
+
+	if !RavienteWindowOpened(windows, sat(19, 59), sat(20, 0)) {
+		t.Error("window opening at 20:00 not reported")
+	}
+	if RavienteWindowOpened(windows, sat(20, 0), sat(20, 1)) {
+		t.Error("window reported as opening twice")
+	}
+
+	start, end, ok := NextRavienteWindow(windows, sat(22, 30))
+	if !ok || !start.Equal(time.Date(2026, 10, 18, 20, 0, 0, 0, loc)) || !end.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("NextRavienteWindow = %v, %v, %v, want Sunday 20:00-22:00", start, end, ok)
+	}
+	if start, _, _ := NextRavienteWindow(windows, sat(21, 0)); !start.Equal(sat(20, 0)) {
+		t.Errorf("NextRavienteWindow during a window = %v, want the open one", start)
+	}
+
+	if !RavienteWindowOpen(nil, sat(3, 0)) {
+		t.Error("no windows should allow sieges at any time")
+	}
+	if _, _, ok := NextRavienteWindow(nil, sat(3, 0)); ok {
+		t.Error("NextRavienteWindow without windows reported one")
+	}
+}
+
+func TestRavienteSiegePhaseAndDamage(t *testing.T) {
+	siege := RavienteSiege{State: make([]uint32, raviRegisterSize), Register: make([]uint32, raviRegisterSize)}
+	if siege.Phase() != 0 || siege.Damage() != 0 || siege.Started() {
+		t.Errorf("fresh siege phase=%d damage=%d started=%v", siege.Phase(), siege.Damage(), siege.Started())
+	}
+	siege.State[0], siege.State[1], siege.State[2] = 100, 50, 25
+	siege.State[10] = 999 // not a phase slot
+	siege.Register[1] = 1
+	if siege.Phase() != 3 || siege.Damage() != 175 || !siege.Started() {
+		t.Errorf("phase=%d damage=%d started=%v, want 3, 175, true", siege.Phase(), siege.Damage(), siege.Started())
+	}
+}
+
+func TestParseChatCommand_Raviente_StartOutsideWindow(t *testing.T) {
+	setupCommandsMap(true)
+	s := createCommandSession(&mockUserRepoCommands{})
+	addRaviSemaphore(s.server)
+	s.server.raviente.register[3] = 100
+	closed := time.Now().Add(3 * time.Hour).Format("15:04")
+	s.server.erupeConfig.Raviente.Windows = []cfg.RavienteWindow{{Start: closed, Minutes: 30}}
+
+	parseChatCommand(s, "!ravi start")
+
+	if s.server.raviente.register[1] != 0 {
+		t.Errorf("register[1] = %d, want the siege left waiting outside its window", s.server.raviente.register[1])
+	}
+	if n := drainChatResponses(s); n != 1 {
+		t.Errorf("chat responses = %d, want 1", n)
+	}
+}
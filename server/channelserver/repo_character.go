@@ -1,6 +1,7 @@
 package channelserver
 
 import (
+	"bytes"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -336,6 +337,37 @@ func (r *CharacterRepository) SaveCharacterDataAtomic(params SaveAtomicParams) e
 	}
 	defer tx.Rollback() //nolint:errcheck // rollback is no-op after commit
 
+	if err := saveCharacterDataTx(tx, params); err != nil {
+		return err
+	}
+
+	// 3. Optional backup
+	if err := saveBackupTx(tx, params); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// saveBackupTx upserts params.BackupData into its slot when set.
+func saveBackupTx(tx *sqlx.Tx, params SaveAtomicParams) error {
+	if params.BackupData == nil {
+		return nil
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO savedata_backups (char_id, slot, savedata, saved_at)
+		 VALUES ($1, $2, $3, now())
+		 ON CONFLICT (char_id, slot) DO UPDATE SET savedata = $3, saved_at = now()`,
+		params.CharID, params.BackupSlot, params.BackupData,
+	); err != nil {
+		return fmt.Errorf("save backup: %w", err)
+	}
+	return nil
+}
+
+// saveCharacterDataTx writes the character data, hash and house data of
+// params inside tx. Shared by SaveCharacterDataAtomic and RestoreBackupAtomic.
+func saveCharacterDataTx(tx *sqlx.Tx, params SaveAtomicParams) error {
 	// 1. Save character data + hash
 	if _, err := tx.Exec(
 		`UPDATE characters SET savedata=$1, savedata_hash=$2, is_new_character=false, hr=$3, gr=$4, is_female=$5, weapon_type=$6, weapon_id=$7 WHERE id=$8`,
@@ -351,19 +383,110 @@ func (r *CharacterRepository) SaveCharacterDataAtomic(params SaveAtomicParams) e
 	); err != nil {
 		return fmt.Errorf("save house data: %w", err)
 	}
+	return nil
+}
 
-	// 3. Optional backup
-	if params.BackupData != nil {
-		if _, err := tx.Exec(
-			`INSERT INTO savedata_backups (char_id, slot, savedata, saved_at)
-			 VALUES ($1, $2, $3, now())
-			 ON CONFLICT (char_id, slot) DO UPDATE SET savedata = $3, saved_at = now()`,
-			params.CharID, params.BackupSlot, params.BackupData,
-		); err != nil {
-			return fmt.Errorf("save backup: %w", err)
-		}
+// ErrCharacterOnline is returned by RestoreBackupAtomic when the character
+// has a live session; its client would overwrite the restore on next save.
+var ErrCharacterOnline = errors.New("character is online")
+
+// ErrSaveChanged is returned by ReplaceSaveDataAtomic when the character's
+// savedata no longer matches what the caller read.
+var ErrSaveChanged = errors.New("savedata changed since it was read")
+
+// ErrBackupChanged is returned by RestoreBackupAtomic when the backup slot was
+// rewritten after the caller read it.
+var ErrBackupChanged = errors.New("backup slot changed since it was read")
+
+// lockOfflineCharacter locks the characters row and returns its savedata,
+// failing with ErrCharacterOnline if a sign session is attached to the
+// character.
+func lockOfflineCharacter(tx *sqlx.Tx, charID uint32) ([]byte, error) {
+	var current []byte
+	if err := tx.QueryRow(`SELECT savedata FROM characters WHERE id = $1 FOR UPDATE`, charID).Scan(&current); err != nil {
+		return nil, fmt.Errorf("lock character: %w", err)
 	}
+	var online bool
+	if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM sign_sessions WHERE char_id = $1)`, charID).Scan(&online); err != nil {
+		return nil, fmt.Errorf("check online: %w", err)
+	}
+	if online {
+		return nil, ErrCharacterOnline
+	}
+	return current, nil
+}
 
+// ReplaceSaveDataAtomic writes an edited save, as SaveCharacterDataAtomic
+// does, provided the character is offline (ErrCharacterOnline) and its stored
+// savedata still equals prev (ErrSaveChanged). params.BackupData, when set,
+// is written in the same transaction.
+func (r *CharacterRepository) ReplaceSaveDataAtomic(prev []byte, params SaveAtomicParams) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback is no-op after commit
+
+	current, err := lockOfflineCharacter(tx, params.CharID)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current, prev) {
+		return ErrSaveChanged
+	}
+	if err := saveCharacterDataTx(tx, params); err != nil {
+		return err
+	}
+	if err := saveBackupTx(tx, params); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RestoreBackupAtomic replaces the character's savedata with the backup in
+// slot, in one transaction. params carries the backup's blob and the fields
+// parsed from it, as for SaveCharacterDataAtomic; savedAt must be the slot's
+// saved_at as read by the caller. The save being replaced moves into the
+// restored slot, so restoring the same slot again undoes the restore.
+//
+// The restore refuses to run while the character has a sign session attached
+// to a channel (ErrCharacterOnline). The characters row is locked first so a
+// concurrent save cannot interleave.
+func (r *CharacterRepository) RestoreBackupAtomic(slot int, savedAt time.Time, params SaveAtomicParams) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback is no-op after commit
+
+	current, err := lockOfflineCharacter(tx, params.CharID)
+	if err != nil {
+		return err
+	}
+
+	var res sql.Result
+	if len(current) > 0 {
+		res, err = tx.Exec(
+			`UPDATE savedata_backups SET savedata = $1, saved_at = now()
+			 WHERE char_id = $2 AND slot = $3 AND saved_at = $4`,
+			current, params.CharID, slot, savedAt)
+	} else {
+		res, err = tx.Exec(
+			`DELETE FROM savedata_backups WHERE char_id = $1 AND slot = $2 AND saved_at = $3`,
+			params.CharID, slot, savedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("swap backup: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrBackupChanged
+	}
+
+	if err := saveCharacterDataTx(tx, params); err != nil {
+		return err
+	}
 	return tx.Commit()
 }
 
@@ -1,6 +1,7 @@
 package channelserver
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -701,3 +702,94 @@ func TestLoadSaveDataNotFound(t *testing.T) {
 		t.Fatal("Expected error for non-existent character")
 	}
 }
+
+func TestRestoreBackupAtomic(t *testing.T) {
+	repo, db, charID := setupCharRepo(t)
+
+	live := []byte("live save")
+	backup := []byte("backup save")
+	if err := repo.SaveColumn(charID, "savedata", live); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := repo.SaveBackup(charID, 1, backup); err != nil {
+		t.Fatalf("SaveBackup failed: %v", err)
+	}
+	backups, err := repo.LoadBackupsByRecency(charID)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("LoadBackupsByRecency = %v, %v", backups, err)
+	}
+
+	params := SaveAtomicParams{CharID: charID, CompSave: backup, Hash: make([]byte, 32), HR: 42}
+	if err := repo.RestoreBackupAtomic(1, backups[0].SavedAt, params); err != nil {
+		t.Fatalf("RestoreBackupAtomic failed: %v", err)
+	}
+
+	var savedata []byte
+	var hr int
+	if err := db.QueryRow("SELECT savedata, hr FROM characters WHERE id=$1", charID).Scan(&savedata, &hr); err != nil {
+		t.Fatalf("Verification query failed: %v", err)
+	}
+	if string(savedata) != string(backup) || hr != 42 {
+		t.Errorf("Expected restored savedata with hr 42, got %q hr %d", savedata, hr)
+	}
+	swapped, err := repo.LoadBackupsByRecency(charID)
+	if err != nil || len(swapped) != 1 || string(swapped[0].Data) != string(live) {
+		t.Fatalf("Expected the live save in slot 1, got %v, %v", swapped, err)
+	}
+
+	// The slot has been rewritten, so a restore using the old timestamp fails.
+	if err := repo.RestoreBackupAtomic(1, backups[0].SavedAt, params); !errors.Is(err, ErrBackupChanged) {
+		t.Errorf("Expected ErrBackupChanged, got: %v", err)
+	}
+}
+
+func TestRestoreBackupAtomicOnline(t *testing.T) {
+	repo, db, charID := setupCharRepo(t)
+
+	if err := repo.SaveBackup(charID, 0, []byte("backup")); err != nil {
+		t.Fatalf("SaveBackup failed: %v", err)
+	}
+	var userID uint32
+	if err := db.QueryRow("SELECT user_id FROM characters WHERE id=$1", charID).Scan(&userID); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	CreateTestSignSession(t, db, userID, "restore_token")
+	if _, err := db.Exec("UPDATE sign_sessions SET char_id=$1 WHERE token='restore_token'", charID); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	backups, _ := repo.LoadBackupsByRecency(charID)
+
+	err := repo.RestoreBackupAtomic(0, backups[0].SavedAt, SaveAtomicParams{CharID: charID, CompSave: []byte("backup")})
+	if !errors.Is(err, ErrCharacterOnline) {
+		t.Errorf("Expected ErrCharacterOnline, got: %v", err)
+	}
+}
+
+func TestReplaceSaveDataAtomic(t *testing.T) {
+	repo, db, charID := setupCharRepo(t)
+
+	prev := []byte("before edit")
+	if err := repo.SaveColumn(charID, "savedata", prev); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	params := SaveAtomicParams{CharID: charID, CompSave: []byte("after edit"), HR: 9, BackupSlot: 2, BackupData: prev}
+
+	if err := repo.ReplaceSaveDataAtomic([]byte("stale"), params); !errors.Is(err, ErrSaveChanged) {
+		t.Fatalf("Expected ErrSaveChanged, got: %v", err)
+	}
+	if err := repo.ReplaceSaveDataAtomic(prev, params); err != nil {
+		t.Fatalf("ReplaceSaveDataAtomic failed: %v", err)
+	}
+
+	var savedata []byte
+	if err := db.QueryRow("SELECT savedata FROM characters WHERE id=$1", charID).Scan(&savedata); err != nil {
+		t.Fatalf("Verification query failed: %v", err)
+	}
+	if string(savedata) != "after edit" {
+		t.Errorf("Expected edited savedata, got: %q", savedata)
+	}
+	backups, err := repo.LoadBackupsByRecency(charID)
+	if err != nil || len(backups) != 1 || backups[0].Slot != 2 || string(backups[0].Data) != string(prev) {
+		t.Errorf("Expected the unedited save in slot 2, got %v, %v", backups, err)
+	}
+}
@@ -0,0 +1,202 @@
+package channelserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ConquestRepository centralizes all database access for the
+// conquest_schedule, conquest_beat_levels and conquest_reward_payouts tables.
+type ConquestRepository struct {
+	db *sqlx.DB
+}
+
+// NewConquestRepository creates a new ConquestRepository.
+func NewConquestRepository(db *sqlx.DB) *ConquestRepository {
+	return &ConquestRepository{db: db}
+}
+
+// ConquestSchedule is the anchor the Earth phase rotation is computed from.
+type ConquestSchedule struct {
+	EarthID   int32     `db:"earth_id"`
+	Status    int32     `db:"status"`
+	StartTime time.Time `db:"start_time"`
+}
+
+// ConquestRankEntry is a character's place on one monster's beat level
+// leaderboard.
+type ConquestRankEntry struct {
+	MonsterID int32  `db:"monster_id"`
+	Place     uint32 `db:"place"`
+	CharID    uint32 `db:"character_id"`
+	Name      string `db:"name"`
+	Level     uint32 `db:"level"`
+}
+
+// ConquestRewardGrant is the distribution one leaderboard placing earns.
+type ConquestRewardGrant struct {
+	CharID      uint32
+	MonsterID   int32
+	Place       uint32
+	Description string
+	Items       []DistributionItem
+}
+
+// conquestPlacings ranks every non-deleted character's beat level per
+// monster for one Earth event. Ties go to whoever reached the level first.
+const conquestPlacings = `
+	SELECT b.monster_id, b.character_id, c.name, b.level,
+		ROW_NUMBER() OVER (PARTITION BY b.monster_id ORDER BY b.level DESC, b.updated_at, b.character_id) AS place
+	FROM conquest_beat_levels b
+	JOIN characters c ON c.id = b.character_id
+	WHERE b.earth_id = $1 AND NOT c.deleted`
+
+// EnsureSchedule returns the rotation anchor, storing the given one first if
+// none exists yet.
+func (r *ConquestRepository) EnsureSchedule(earthID, status int32, start time.Time) (ConquestSchedule, error) {
+	var sched ConquestSchedule
+	if _, err := r.db.Exec(`
+		INSERT INTO conquest_schedule (id, earth_id, status, start_time) VALUES (1, $1, $2, $3)
+		ON CONFLICT (id) DO NOTHING
+	`, earthID, status, start); err != nil {
+		return sched, fmt.Errorf("seed conquest schedule: %w", err)
+	}
+	err := r.db.Get(&sched, `SELECT earth_id, status, start_time FROM conquest_schedule WHERE id = 1`)
+	return sched, err
+}
+
+// GetLevels returns a character's beat levels for an Earth event keyed by
+// monster ID.
+func (r *ConquestRepository) GetLevels(charID uint32, earthID int32) (map[int32]uint32, error) {
+	rows, err := r.db.Query(`
+		SELECT monster_id, level FROM conquest_beat_levels WHERE character_id = $1 AND earth_id = $2
+	`, charID, earthID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	levels := make(map[int32]uint32)
+	for rows.Next() {
+		var monsterID int32
+		var level uint32
+		if err := rows.Scan(&monsterID, &level); err != nil {
+			return nil, err
+		}
+		levels[monsterID] = level
+	}
+	return levels, rows.Err()
+}
+
+// SetLevels stores a character's beat levels for an Earth event. A row's
+// updated_at only moves when its level changes, so re-sending an unchanged
+// level does not cost the character a tie-break.
+func (r *ConquestRepository) SetLevels(charID uint32, earthID int32, levels map[int32]uint32) error {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for monsterID, level := range levels {
+		if _, err := tx.Exec(`
+			INSERT INTO conquest_beat_levels (character_id, earth_id, monster_id, level)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (character_id, earth_id, monster_id) DO UPDATE
+			SET level = EXCLUDED.level, updated_at = now()
+			WHERE conquest_beat_levels.level <> EXCLUDED.level
+		`, charID, earthID, monsterID, level); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetRanking returns the top limit placings on a monster's leaderboard.
+func (r *ConquestRepository) GetRanking(earthID, monsterID int32, limit int) ([]ConquestRankEntry, error) {
+	var entries []ConquestRankEntry
+	err := r.db.Select(&entries, `
+		SELECT monster_id, character_id, name, level, place FROM (`+conquestPlacings+`) r
+		WHERE monster_id = $2
+		ORDER BY place
+		LIMIT $3
+	`, earthID, monsterID, limit)
+	return entries, err
+}
+
+// GetCharacterPlacings returns a character's placing on every leaderboard
+// they appear on for an Earth event.
+func (r *ConquestRepository) GetCharacterPlacings(charID uint32, earthID int32) ([]ConquestRankEntry, error) {
+	var entries []ConquestRankEntry
+	err := r.db.Select(&entries, `
+		SELECT monster_id, character_id, name, level, place FROM (`+conquestPlacings+`) r
+		WHERE character_id = $2
+		ORDER BY monster_id
+	`, earthID, charID)
+	return entries, err
+}
+
+// GetPlacings returns every placing on every leaderboard of an Earth event.
+func (r *ConquestRepository) GetPlacings(earthID int32) ([]ConquestRankEntry, error) {
+	var entries []ConquestRankEntry
+	err := r.db.Select(&entries, `
+		SELECT monster_id, character_id, name, level, place FROM (`+conquestPlacings+`) r
+		ORDER BY monster_id, place
+	`, earthID)
+	return entries, err
+}
+
+// IsRewardPaid reports whether an Earth event's ranking rewards have been
+// distributed.
+func (r *ConquestRepository) IsRewardPaid(earthID int32) (bool, error) {
+	var paid bool
+	err := r.db.QueryRow(`SELECT true FROM conquest_reward_payouts WHERE earth_id = $1`, earthID).Scan(&paid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return paid, err
+}
+
+// PayRewards creates one character-bound distribution per grant and marks
+// the Earth event as paid, all in one transaction. It returns false without
+// writing anything if the event was already paid.
+func (r *ConquestRepository) PayRewards(earthID int32, grants []ConquestRewardGrant, eventName string) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`
+		INSERT INTO conquest_reward_payouts (earth_id, recipients) VALUES ($1, $2)
+		ON CONFLICT (earth_id) DO NOTHING
+	`, earthID, len(grants))
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	for _, grant := range grants {
+		var distID uint32
+		if err := tx.QueryRow(`
+			INSERT INTO distribution (character_id, type, event_name, description, times_acceptable)
+			VALUES ($1, $2, $3, $4, 1) RETURNING id
+		`, grant.CharID, conquestDistributionType, eventName, grant.Description).Scan(&distID); err != nil {
+			return false, fmt.Errorf("insert distribution: %w", err)
+		}
+		for _, item := range grant.Items {
+			if _, err := tx.Exec(`
+				INSERT INTO distribution_items (distribution_id, item_type, item_id, quantity) VALUES ($1, $2, $3, $4)
+			`, distID, item.ItemType, item.ItemID, item.Quantity); err != nil {
+				return false, fmt.Errorf("insert distribution item: %w", err)
+			}
+		}
+	}
+	return true, tx.Commit()
+}
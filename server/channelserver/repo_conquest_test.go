@@ -0,0 +1,105 @@
+package channelserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func setupConquestRepo(t *testing.T) (*ConquestRepository, *sqlx.DB, uint32, uint32) {
+	t.Helper()
+	db := SetupTestDB(t)
+	userID := CreateTestUser(t, db, "conquest_test_user")
+	first := CreateTestCharacter(t, db, userID, "ConquestOne")
+	second := CreateTestCharacter(t, db, userID, "ConquestTwo")
+	repo := NewConquestRepository(db)
+	t.Cleanup(func() { TeardownTestDB(t, db) })
+	return repo, db, first, second
+}
+
+func TestRepoConquestEnsureScheduleKeepsFirstSeed(t *testing.T) {
+	repo, _, _, _ := setupConquestRepo(t)
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	if _, err := repo.EnsureSchedule(3, 1, start); err != nil {
+		t.Fatalf("EnsureSchedule failed: %v", err)
+	}
+	sched, err := repo.EnsureSchedule(9, 21, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("EnsureSchedule failed: %v", err)
+	}
+	if sched.EarthID != 3 || sched.Status != 1 || !sched.StartTime.Equal(start) {
+		t.Errorf("schedule = %+v, want the first seed", sched)
+	}
+}
+
+func TestRepoConquestLevelsAndRanking(t *testing.T) {
+	repo, _, first, second := setupConquestRepo(t)
+
+	if err := repo.SetLevels(first, 1, map[int32]uint32{116: 50, 107: 10}); err != nil {
+		t.Fatalf("SetLevels failed: %v", err)
+	}
+	if err := repo.SetLevels(second, 1, map[int32]uint32{116: 80}); err != nil {
+		t.Fatalf("SetLevels failed: %v", err)
+	}
+	// Another Earth event's levels do not leak into event 1.
+	if err := repo.SetLevels(first, 2, map[int32]uint32{116: 999}); err != nil {
+		t.Fatalf("SetLevels failed: %v", err)
+	}
+
+	levels, err := repo.GetLevels(first, 1)
+	if err != nil {
+		t.Fatalf("GetLevels failed: %v", err)
+	}
+	if levels[116] != 50 || levels[107] != 10 {
+		t.Errorf("levels = %v", levels)
+	}
+
+	ranking, err := repo.GetRanking(1, 116, 100)
+	if err != nil {
+		t.Fatalf("GetRanking failed: %v", err)
+	}
+	if len(ranking) != 2 || ranking[0].CharID != second || ranking[0].Place != 1 ||
+		ranking[1].Name != "ConquestOne" || ranking[1].Place != 2 {
+		t.Errorf("ranking = %+v", ranking)
+	}
+
+	placings, err := repo.GetCharacterPlacings(first, 1)
+	if err != nil {
+		t.Fatalf("GetCharacterPlacings failed: %v", err)
+	}
+	if len(placings) != 2 || placings[0].MonsterID != 107 || placings[0].Place != 1 || placings[1].Place != 2 {
+		t.Errorf("placings = %+v", placings)
+	}
+}
+
+func TestRepoConquestPayRewardsOnce(t *testing.T) {
+	repo, db, first, _ := setupConquestRepo(t)
+	grants := []ConquestRewardGrant{{
+		CharID: first, MonsterID: 116, Place: 1, Description: "first",
+		Items: []DistributionItem{{ItemType: 7, ItemID: 100, Quantity: 2}},
+	}}
+
+	ok, err := repo.PayRewards(4, grants, "Conquest War Reward")
+	if err != nil || !ok {
+		t.Fatalf("PayRewards = %v, %v; want true", ok, err)
+	}
+	if ok, err := repo.PayRewards(4, grants, "Conquest War Reward"); err != nil || ok {
+		t.Fatalf("second PayRewards = %v, %v; want false", ok, err)
+	}
+	if paid, err := repo.IsRewardPaid(4); err != nil || !paid {
+		t.Errorf("IsRewardPaid = %v, %v; want true", paid, err)
+	}
+
+	var dists, items int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM distribution WHERE character_id = $1`, first).Scan(&dists); err != nil {
+		t.Fatalf("count distributions: %v", err)
+	}
+	if err := db.QueryRow(`SELECT COUNT(*) FROM distribution_items WHERE item_id = 100 AND quantity = 2`).Scan(&items); err != nil {
+		t.Fatalf("count distribution items: %v", err)
+	}
+	if dists != 1 || items != 1 {
+		t.Errorf("distributions %d items %d, want 1 each", dists, items)
+	}
+}
@@ -0,0 +1,36 @@
+package channelserver
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DailyMissionRepository centralizes all database access for the
+// daily_mission_progress table.
+type DailyMissionRepository struct {
+	db *sqlx.DB
+}
+
+// NewDailyMissionRepository creates a new DailyMissionRepository.
+func NewDailyMissionRepository(db *sqlx.DB) *DailyMissionRepository {
+	return &DailyMissionRepository{db: db}
+}
+
+// DailyMissionProgress is a character's progress on one daily mission.
+type DailyMissionProgress struct {
+	MissionID uint32 `db:"mission_id"`
+	Progress  uint32 `db:"progress"`
+	Claimed   bool   `db:"claimed"`
+}
+
+// GetProgress returns the character's progress recorded for the given day.
+// Rows left over from earlier days are ignored, which is what resets the
+// board at midnight.
+func (r *DailyMissionRepository) GetProgress(charID uint32, day time.Time) ([]DailyMissionProgress, error) {
+	var progress []DailyMissionProgress
+	err := r.db.Select(&progress,
+		`SELECT mission_id, progress, claimed FROM daily_mission_progress
+		WHERE character_id=$1 AND day=$2 ORDER BY mission_id`, charID, day)
+	return progress, err
+}
@@ -0,0 +1,32 @@
+package channelserver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRepoDailyMissionGetProgress(t *testing.T) {
+	db := SetupTestDB(t)
+	t.Cleanup(func() { TeardownTestDB(t, db) })
+	userID := CreateTestUser(t, db, "daily_mission_test_user")
+	charID := CreateTestCharacter(t, db, userID, "DailyMissionChar")
+	repo := NewDailyMissionRepository(db)
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.FixedZone("UTC+9", 9*60*60))
+
+	if _, err := db.Exec(`INSERT INTO daily_mission_progress (character_id, mission_id, progress, day)
+		VALUES ($1, 1, 2, $2)`, charID, day); err != nil {
+		t.Fatalf("insert progress: %v", err)
+	}
+	progress, err := repo.GetProgress(charID, day)
+	if err != nil {
+		t.Fatalf("GetProgress failed: %v", err)
+	}
+	if len(progress) != 1 || progress[0].MissionID != 1 || progress[0].Progress != 2 || progress[0].Claimed {
+		t.Errorf("progress = %+v, want mission 1 at 2, unclaimed", progress)
+	}
+
+	// Progress from an earlier day is not returned.
+	if progress, _ := repo.GetProgress(charID, day.Add(24*time.Hour)); len(progress) != 0 {
+		t.Errorf("next day progress = %+v, want none", progress)
+	}
+}
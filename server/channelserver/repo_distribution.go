@@ -1,7 +1,14 @@
 package channelserver
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // DistributionRepository centralizes all database access for the distribution,
@@ -15,7 +22,8 @@ func NewDistributionRepository(db *sqlx.DB) *DistributionRepository {
 	return &DistributionRepository{db: db}
 }
 
-// List returns all distributions matching the given character and type.
+// List returns the distributions of the given type offered to a character:
+// those targeting everyone or the character whose time window is open.
 func (r *DistributionRepository) List(charID uint32, distType uint8) ([]Distribution, error) {
 	rows, err := r.db.Queryx(`
 		SELECT d.id, event_name, description, COALESCE(rights, 0) AS rights, COALESCE(selection, false) AS selection, times_acceptable,
@@ -28,7 +36,11 @@ func (r *DistributionRepository) List(charID uint32, distType uint8) ([]Distribu
 		) AS times_accepted,
 		COALESCE(deadline, TO_TIMESTAMP(0)) AS deadline
 		FROM distribution d
-		WHERE character_id = $1 AND type = $2 OR character_id IS NULL AND type = $2 ORDER BY id DESC
+		WHERE type = $2
+			AND (character_id = $1 OR $1 = ANY(character_ids) OR character_id IS NULL AND character_ids IS NULL)
+			AND (starts_at IS NULL OR starts_at <= now())
+			AND (deadline IS NULL OR deadline > now())
+		ORDER BY id DESC
 	`, charID, distType)
 	if err != nil {
 		return nil, err
@@ -77,3 +89,226 @@ func (r *DistributionRepository) GetDescription(distributionID uint32) (string,
 	err := r.db.QueryRow("SELECT description FROM distribution WHERE id = $1", distributionID).Scan(&desc)
 	return desc, err
 }
+
+// DistributionCampaign is a distribution as managed through the admin API,
+// with its items and claim counts. Zero level bounds are stored as NULL, so
+// they do not restrict the distribution.
+type DistributionCampaign struct {
+	ID              uint32             `json:"id"`
+	Type            uint8              `json:"type"`
+	EventName       string             `json:"eventName"`
+	Description     string             `json:"description"`
+	StartsAt        *time.Time         `json:"startsAt,omitempty"`
+	Deadline        *time.Time         `json:"deadline,omitempty"`
+	TimesAcceptable uint16             `json:"timesAcceptable"`
+	Rights          uint32             `json:"-"` // Course bitmask; set from Courses by DistributionService
+	Courses         []string           `json:"courses,omitempty"`
+	MinHR           int16              `json:"minHr,omitempty"`
+	MaxHR           int16              `json:"maxHr,omitempty"`
+	MinSR           int16              `json:"minSr,omitempty"`
+	MaxSR           int16              `json:"maxSr,omitempty"`
+	MinGR           int16              `json:"minGr,omitempty"`
+	MaxGR           int16              `json:"maxGr,omitempty"`
+	CharIDs         []uint32           `json:"charIds,omitempty"` // Empty offers the distribution to everyone
+	Selection       bool               `json:"selection,omitempty"`
+	Items           []DistributionItem `json:"items"`
+	Claims          int                `json:"claims"`    // Times the distribution was accepted
+	Claimants       int                `json:"claimants"` // Characters who accepted it
+}
+
+// DistributionEligibleChar is a character a distribution would be offered to.
+type DistributionEligibleChar struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+	HR   uint16 `json:"hr"`
+	GR   uint16 `json:"gr"`
+}
+
+const distributionCampaignColumns = `d.id, d.type, d.event_name, d.description, d.starts_at, d.deadline,
+	d.times_acceptable, COALESCE(d.rights, 0), COALESCE(d.min_hr, 0), COALESCE(d.max_hr, 0),
+	COALESCE(d.min_sr, 0), COALESCE(d.max_sr, 0), COALESCE(d.min_gr, 0), COALESCE(d.max_gr, 0),
+	d.character_id, d.character_ids, COALESCE(d.selection, false),
+	(SELECT COUNT(*) FROM distributions_accepted da WHERE da.distribution_id = d.id),
+	(SELECT COUNT(DISTINCT da.character_id) FROM distributions_accepted da WHERE da.distribution_id = d.id)`
+
+func scanDistributionCampaign(row rowScanner) (*DistributionCampaign, error) {
+	var c DistributionCampaign
+	var charID sql.NullInt64
+	var charIDs pq.Int64Array
+	if err := row.Scan(&c.ID, &c.Type, &c.EventName, &c.Description, &c.StartsAt, &c.Deadline,
+		&c.TimesAcceptable, &c.Rights, &c.MinHR, &c.MaxHR, &c.MinSR, &c.MaxSR, &c.MinGR, &c.MaxGR,
+		&charID, &charIDs, &c.Selection, &c.Claims, &c.Claimants); err != nil {
+		return nil, err
+	}
+	if charID.Valid {
+		c.CharIDs = append(c.CharIDs, uint32(charID.Int64))
+	}
+	for _, id := range charIDs {
+		c.CharIDs = append(c.CharIDs, uint32(id))
+	}
+	return &c, nil
+}
+
+// distributionCampaignArgs returns the column values stored for c, from
+// type to selection.
+func distributionCampaignArgs(c DistributionCampaign) []interface{} {
+	var charIDs pq.Int64Array
+	for _, id := range c.CharIDs {
+		charIDs = append(charIDs, int64(id))
+	}
+	return []interface{}{c.Type, c.EventName, c.Description, c.StartsAt, c.Deadline, c.TimesAcceptable,
+		c.Rights, c.MinHR, c.MaxHR, c.MinSR, c.MaxSR, c.MinGR, c.MaxGR, charIDs, c.Selection}
+}
+
+// ListCampaigns returns every distribution with its claim counts, newest
+// first. Items are not loaded.
+func (r *DistributionRepository) ListCampaigns() ([]DistributionCampaign, error) {
+	rows, err := r.db.Query(`SELECT ` + distributionCampaignColumns + ` FROM distribution d ORDER BY d.id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var list []DistributionCampaign
+	for rows.Next() {
+		c, err := scanDistributionCampaign(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *c)
+	}
+	return list, rows.Err()
+}
+
+// GetCampaign returns a distribution with its items and claim counts, or nil
+// if it does not exist.
+func (r *DistributionRepository) GetCampaign(id uint32) (*DistributionCampaign, error) {
+	c, err := scanDistributionCampaign(r.db.QueryRow(`SELECT `+distributionCampaignColumns+` FROM distribution d WHERE d.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.Items, err = r.GetItems(id); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// insertDistributionItems stores a distribution's items.
+func insertDistributionItems(tx *sqlx.Tx, id uint32, items []DistributionItem) error {
+	for _, item := range items {
+		if _, err := tx.Exec(`
+			INSERT INTO distribution_items (distribution_id, item_type, item_id, quantity) VALUES ($1, $2, $3, $4)
+		`, id, item.ItemType, item.ItemID, item.Quantity); err != nil {
+			return fmt.Errorf("insert distribution item: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateCampaign stores a distribution and its items, returning its ID.
+func (r *DistributionRepository) CreateCampaign(c DistributionCampaign) (uint32, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var id uint32
+	if err := tx.QueryRow(`
+		INSERT INTO distribution (type, event_name, description, starts_at, deadline, times_acceptable,
+			rights, min_hr, max_hr, min_sr, max_sr, min_gr, max_gr, character_ids, selection)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0), NULLIF($8, 0), NULLIF($9, 0), NULLIF($10, 0),
+			NULLIF($11, 0), NULLIF($12, 0), NULLIF($13, 0), NULLIF($14, '{}'::integer[]), $15)
+		RETURNING id`, distributionCampaignArgs(c)...).Scan(&id); err != nil {
+		return 0, err
+	}
+	if err := insertDistributionItems(tx, id, c.Items); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// UpdateCampaign replaces a distribution's settings and items, reporting
+// whether it exists. Claims already made are kept.
+func (r *DistributionRepository) UpdateCampaign(c DistributionCampaign) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	args := append(distributionCampaignArgs(c), c.ID)
+	res, err := tx.Exec(`
+		UPDATE distribution SET type = $1, event_name = $2, description = $3, starts_at = $4, deadline = $5,
+			times_acceptable = $6, rights = NULLIF($7, 0), min_hr = NULLIF($8, 0), max_hr = NULLIF($9, 0),
+			min_sr = NULLIF($10, 0), max_sr = NULLIF($11, 0), min_gr = NULLIF($12, 0), max_gr = NULLIF($13, 0),
+			character_id = NULL, character_ids = NULLIF($14, '{}'::integer[]), selection = $15
+		WHERE id = $16`, args...)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if _, err := tx.Exec(`DELETE FROM distribution_items WHERE distribution_id = $1`, c.ID); err != nil {
+		return false, err
+	}
+	if err := insertDistributionItems(tx, c.ID, c.Items); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// ExpireCampaign moves a distribution's deadline forward to at unless it
+// already ends earlier, reporting whether the distribution exists.
+func (r *DistributionRepository) ExpireCampaign(id uint32, at time.Time) (bool, error) {
+	res, err := r.db.Exec(`UPDATE distribution SET deadline = LEAST(COALESCE(deadline, $2), $2) WHERE id = $1`, id, at)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// distributionEligibleWhere matches the characters a distribution d is
+// offered to who can still accept it. SR bounds are not checked as the
+// characters table does not store SR, and course rights are checked against
+// the account's current courses.
+const distributionEligibleWhere = `c.deleted = false
+	AND (c.id = d.character_id OR c.id = ANY(d.character_ids) OR d.character_id IS NULL AND d.character_ids IS NULL)
+	AND (COALESCE(d.min_hr, -1) < 0 OR c.hr >= d.min_hr) AND (COALESCE(d.max_hr, -1) < 0 OR c.hr <= d.max_hr)
+	AND (COALESCE(d.min_gr, -1) < 0 OR c.gr >= d.min_gr) AND (COALESCE(d.max_gr, -1) < 0 OR c.gr <= d.max_gr)
+	AND (COALESCE(d.rights, 0) = 0 OR COALESCE(u.rights, 0) & d.rights <> 0)
+	AND (SELECT COUNT(*) FROM distributions_accepted da
+		WHERE da.distribution_id = d.id AND da.character_id = c.id) < d.times_acceptable`
+
+// Eligible returns how many characters a distribution is offered to who can
+// still accept it, and the first limit of them by character ID.
+func (r *DistributionRepository) Eligible(id uint32, limit int) (int, []DistributionEligibleChar, error) {
+	var count int
+	if err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM distribution d, characters c JOIN users u ON u.id = c.user_id
+		WHERE d.id = $1 AND `+distributionEligibleWhere, id).Scan(&count); err != nil {
+		return 0, nil, err
+	}
+	rows, err := r.db.Query(`
+		SELECT c.id, COALESCE(c.name, ''), COALESCE(c.hr, 0), COALESCE(c.gr, 0)
+		FROM distribution d, characters c JOIN users u ON u.id = c.user_id
+		WHERE d.id = $1 AND `+distributionEligibleWhere+`
+		ORDER BY c.id LIMIT $2`, id, limit)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var chars []DistributionEligibleChar
+	for rows.Next() {
+		var ch DistributionEligibleChar
+		if err := rows.Scan(&ch.ID, &ch.Name, &ch.HR, &ch.GR); err != nil {
+			return 0, nil, err
+		}
+		chars = append(chars, ch)
+	}
+	return count, chars, rows.Err()
+}
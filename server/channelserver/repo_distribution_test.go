@@ -2,6 +2,7 @@ package channelserver
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -144,3 +145,84 @@ func TestRepoDistributionFiltersByType(t *testing.T) {
 		t.Errorf("Expected 1 distribution of type 1, got: %d", len(dists))
 	}
 }
+
+func TestRepoDistributionCampaignLifecycle(t *testing.T) {
+	repo, db, charID := setupDistributionRepo(t)
+	other := CreateTestCharacter(t, db, CreateTestUser(t, db, "dist_other_user"), "DistOther")
+
+	id, err := repo.CreateCampaign(DistributionCampaign{
+		Type:            1,
+		EventName:       "Listed",
+		Description:     "~C05For the list",
+		TimesAcceptable: 2,
+		CharIDs:         []uint32{charID},
+		Items:           []DistributionItem{{ItemType: 7, ItemID: 100, Quantity: 5}},
+	})
+	if err != nil {
+		t.Fatalf("CreateCampaign failed: %v", err)
+	}
+	if dists, _ := repo.List(charID, 1); len(dists) != 1 || dists[0].MinHR != -1 {
+		t.Errorf("targeted character sees %+v, want the distribution without an HR bound", dists)
+	}
+	if dists, _ := repo.List(other, 1); len(dists) != 0 {
+		t.Errorf("other character sees %d distributions, want 0", len(dists))
+	}
+
+	count, chars, err := repo.Eligible(id, 10)
+	if err != nil || count != 1 || len(chars) != 1 || chars[0].ID != charID {
+		t.Fatalf("Eligible = %d, %+v, %v", count, chars, err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := repo.RecordAccepted(id, charID); err != nil {
+			t.Fatalf("RecordAccepted failed: %v", err)
+		}
+	}
+	if count, _, _ := repo.Eligible(id, 10); count != 0 {
+		t.Errorf("eligible after using every claim = %d, want 0", count)
+	}
+
+	c, err := repo.GetCampaign(id)
+	if err != nil || c == nil {
+		t.Fatalf("GetCampaign = %+v, %v", c, err)
+	}
+	if c.Claims != 2 || c.Claimants != 1 || len(c.Items) != 1 || len(c.CharIDs) != 1 {
+		t.Errorf("campaign = %+v", c)
+	}
+
+	c.CharIDs = nil
+	c.Items = []DistributionItem{{ItemType: 30, Quantity: 1}, {ItemType: 31, Quantity: 1}}
+	if ok, err := repo.UpdateCampaign(*c); err != nil || !ok {
+		t.Fatalf("UpdateCampaign = %v, %v", ok, err)
+	}
+	if dists, _ := repo.List(other, 1); len(dists) != 1 {
+		t.Errorf("untargeted distribution not offered to everyone: %d", len(dists))
+	}
+	if items, _ := repo.GetItems(id); len(items) != 2 {
+		t.Errorf("items after update = %d, want 2", len(items))
+	}
+
+	if ok, err := repo.ExpireCampaign(id, time.Now().Add(-time.Minute)); err != nil || !ok {
+		t.Fatalf("ExpireCampaign = %v, %v", ok, err)
+	}
+	if dists, _ := repo.List(other, 1); len(dists) != 0 {
+		t.Errorf("expired distribution still offered: %d", len(dists))
+	}
+	if list, err := repo.ListCampaigns(); err != nil || len(list) != 1 || list[0].Deadline == nil {
+		t.Errorf("ListCampaigns = %+v, %v", list, err)
+	}
+	if ok, _ := repo.UpdateCampaign(DistributionCampaign{ID: id + 100, EventName: "x", Description: "x"}); ok {
+		t.Error("UpdateCampaign reported a missing distribution as updated")
+	}
+}
+
+func TestRepoDistributionListHonoursStart(t *testing.T) {
+	repo, db, charID := setupDistributionRepo(t)
+
+	id := createDistribution(t, db, nil, 1, "Later", "Not yet")
+	if _, err := db.Exec(`UPDATE distribution SET starts_at = now() + interval '1 hour' WHERE id = $1`, id); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if dists, _ := repo.List(charID, 1); len(dists) != 0 {
+		t.Errorf("distribution offered before it starts: %d", len(dists))
+	}
+}
@@ -1,7 +1,11 @@
 package channelserver
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -224,3 +228,142 @@ func (r *DivaRepository) AddInterceptionPoints(characterID uint32, questFileID i
 		characterID, questFileID, points)
 	return err
 }
+
+// DivaRankEntry is a place on a Diva Defense point ranking. ID is a
+// character ID on the personal ranking and a guild ID on the guild ranking.
+type DivaRankEntry struct {
+	Rank   uint32 `db:"rank"`
+	ID     uint32 `db:"id"`
+	Name   string `db:"name"`
+	Points uint64 `db:"points"`
+}
+
+// divaPersonalPoints ranks every character's quest and bonus points in one
+// event. Ties go to whoever reached the total first.
+const divaPersonalPoints = `
+	SELECT ROW_NUMBER() OVER (ORDER BY p.quest_points + p.bonus_points DESC, p.updated_at, p.char_id) AS rank,
+		p.char_id AS id, c.name, p.quest_points + p.bonus_points AS points
+	FROM diva_points p
+	JOIN characters c ON c.id = p.char_id
+	WHERE p.event_id = $1 AND NOT c.deleted`
+
+// divaGuildPoints ranks every guild by its current members' summed points
+// in one event.
+const divaGuildPoints = `
+	SELECT ROW_NUMBER() OVER (ORDER BY SUM(p.quest_points + p.bonus_points) DESC, g.id) AS rank,
+		g.id, g.name, SUM(p.quest_points + p.bonus_points) AS points
+	FROM diva_points p
+	JOIN guild_characters gc ON gc.character_id = p.char_id
+	JOIN guilds g ON g.id = gc.guild_id
+	WHERE p.event_id = $1
+	GROUP BY g.id, g.name`
+
+// GetPersonalRanking returns the top limit characters by points in an event.
+func (r *DivaRepository) GetPersonalRanking(eventID uint32, limit int) ([]DivaRankEntry, error) {
+	var entries []DivaRankEntry
+	err := r.db.Select(&entries, `SELECT * FROM (`+divaPersonalPoints+`) r ORDER BY rank LIMIT $2`, eventID, limit)
+	return entries, err
+}
+
+// GetGuildRanking returns the top limit guilds by points in an event.
+func (r *DivaRepository) GetGuildRanking(eventID uint32, limit int) ([]DivaRankEntry, error) {
+	var entries []DivaRankEntry
+	err := r.db.Select(&entries, `SELECT * FROM (`+divaGuildPoints+`) r ORDER BY rank LIMIT $2`, eventID, limit)
+	return entries, err
+}
+
+// GetCharacterRank returns a character's place on an event's personal
+// ranking. The zero entry is returned if the character has no points.
+func (r *DivaRepository) GetCharacterRank(charID, eventID uint32) (DivaRankEntry, error) {
+	var entry DivaRankEntry
+	err := r.db.Get(&entry, `SELECT * FROM (`+divaPersonalPoints+`) r WHERE id = $2`, eventID, charID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DivaRankEntry{}, nil
+	}
+	return entry, err
+}
+
+// GetGuildRank returns a guild's place on an event's guild ranking. The
+// zero entry is returned if no member has points.
+func (r *DivaRepository) GetGuildRank(guildID, eventID uint32) (DivaRankEntry, error) {
+	var entry DivaRankEntry
+	err := r.db.Get(&entry, `SELECT * FROM (`+divaGuildPoints+`) r WHERE id = $2`, eventID, guildID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DivaRankEntry{}, nil
+	}
+	return entry, err
+}
+
+// DivaPresent is a row of diva_presents.
+type DivaPresent struct {
+	ID        uint32 `db:"id"`
+	List      string `db:"list"`
+	RankType  uint8  `db:"rank_type"`
+	RankFrom  uint16 `db:"rank_from"`
+	RankTo    uint16 `db:"rank_to"`
+	ItemType  uint8  `db:"item_type"`
+	ItemID    uint16 `db:"item_id"`
+	Quantity  uint16 `db:"quantity"`
+	PointsReq uint32 `db:"points_req"`
+	BeadType  uint8  `db:"bead_type"`
+}
+
+// GetPresents returns the presents of one list ('daily' or 'norma'), ordered
+// by rank bracket and point threshold.
+func (r *DivaRepository) GetPresents(list string) ([]DivaPresent, error) {
+	var presents []DivaPresent
+	err := r.db.Select(&presents, `
+		SELECT id, list, rank_type, rank_from, rank_to, item_type, item_id, quantity, points_req, bead_type
+		FROM diva_presents
+		WHERE list = $1
+		ORDER BY rank_type, rank_from, points_req, id`, list)
+	return presents, err
+}
+
+// ClaimPresents records that a character acquired the given presents for a
+// period and delivers the items of the ones not already claimed for it as a
+// character-bound distribution, all in one transaction. It returns the IDs
+// of the presents it claimed.
+func (r *DivaRepository) ClaimPresents(charID uint32, presents []DivaPresent, period time.Time, eventName string) ([]uint32, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var claimed []DivaPresent
+	for _, present := range presents {
+		res, err := tx.Exec(`
+			INSERT INTO diva_present_claims (character_id, present_id, period) VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING`, charID, present.ID, period)
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if n > 0 {
+			claimed = append(claimed, present)
+		}
+	}
+	if len(claimed) == 0 {
+		return nil, tx.Commit()
+	}
+
+	var distID uint32
+	if err := tx.QueryRow(`
+		INSERT INTO distribution (character_id, type, event_name, description, times_acceptable)
+		VALUES ($1, $2, $3, $4, 1) RETURNING id
+	`, charID, divaPresentDistributionType, eventName, eventName).Scan(&distID); err != nil {
+		return nil, fmt.Errorf("insert distribution: %w", err)
+	}
+	ids := make([]uint32, 0, len(claimed))
+	for _, present := range claimed {
+		if _, err := tx.Exec(`
+			INSERT INTO distribution_items (distribution_id, item_type, item_id, quantity) VALUES ($1, $2, $3, $4)
+		`, distID, present.ItemType, present.ItemID, present.Quantity); err != nil {
+			return nil, fmt.Errorf("insert distribution item: %w", err)
+		}
+		ids = append(ids, present.ID)
+	}
+	return ids, tx.Commit()
+}
@@ -2,6 +2,7 @@ package channelserver
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -111,3 +112,85 @@ func TestRepoDivaDeleteOnlyDivaEvents(t *testing.T) {
 		t.Errorf("Expected festa event to survive, got count=%d", count)
 	}
 }
+
+func TestRepoDivaRankings(t *testing.T) {
+	repo, db := setupDivaRepo(t)
+	userID := CreateTestUser(t, db, "diva_rank_user")
+	first := CreateTestCharacter(t, db, userID, "DivaOne")
+	second := CreateTestCharacter(t, db, userID, "DivaTwo")
+	guildID := CreateTestGuild(t, db, first, "DivaGuild")
+
+	if err := repo.AddPoints(first, 1, 100, 10); err != nil {
+		t.Fatalf("AddPoints failed: %v", err)
+	}
+	if err := repo.AddPoints(second, 1, 500, 0); err != nil {
+		t.Fatalf("AddPoints failed: %v", err)
+	}
+
+	ranking, err := repo.GetPersonalRanking(1, 100)
+	if err != nil {
+		t.Fatalf("GetPersonalRanking failed: %v", err)
+	}
+	if len(ranking) != 2 || ranking[0].ID != second || ranking[1].Name != "DivaOne" || ranking[1].Points != 110 {
+		t.Errorf("ranking = %+v", ranking)
+	}
+
+	rank, err := repo.GetCharacterRank(first, 1)
+	if err != nil || rank.Rank != 2 {
+		t.Errorf("GetCharacterRank = %+v, %v; want rank 2", rank, err)
+	}
+	if rank, err := repo.GetCharacterRank(first, 2); err != nil || rank.Rank != 0 {
+		t.Errorf("GetCharacterRank for another event = %+v, %v; want unranked", rank, err)
+	}
+
+	guild, err := repo.GetGuildRank(guildID, 1)
+	if err != nil || guild.Rank != 1 || guild.Name != "DivaGuild" || guild.Points != 110 {
+		t.Errorf("GetGuildRank = %+v, %v", guild, err)
+	}
+}
+
+func TestRepoDivaClaimPresents(t *testing.T) {
+	repo, db := setupDivaRepo(t)
+	userID := CreateTestUser(t, db, "diva_present_user")
+	charID := CreateTestCharacter(t, db, userID, "DivaPresent")
+
+	var presentID uint32
+	if err := db.QueryRow(`
+		INSERT INTO diva_presents (list, rank_type, rank_from, rank_to, item_type, item_id, quantity, points_req, bead_type)
+		VALUES ('daily', 0, 1, 10, 26, 1, 3, 0, 0) RETURNING id
+	`).Scan(&presentID); err != nil {
+		t.Fatalf("insert present: %v", err)
+	}
+
+	presents, err := repo.GetPresents("daily")
+	if err != nil {
+		t.Fatalf("GetPresents failed: %v", err)
+	}
+	found := false
+	for _, p := range presents {
+		found = found || p.ID == presentID
+	}
+	if !found {
+		t.Errorf("present %d missing from %+v", presentID, presents)
+	}
+
+	present := DivaPresent{ID: presentID, ItemType: 7, ItemID: 15, Quantity: 3}
+	today := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	claimed, err := repo.ClaimPresents(charID, []DivaPresent{present}, today, "Diva Defense Present")
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimPresents = %v, %v; want one claim", claimed, err)
+	}
+	var delivered int
+	if err := db.QueryRow(`
+		SELECT COUNT(*) FROM distribution d JOIN distribution_items di ON di.distribution_id = d.id
+		WHERE d.character_id = $1 AND di.item_id = 15 AND di.quantity = 3
+	`, charID).Scan(&delivered); err != nil || delivered != 1 {
+		t.Errorf("delivered items = %d, %v; want one", delivered, err)
+	}
+	if claimed, err := repo.ClaimPresents(charID, []DivaPresent{present}, today, "Diva Defense Present"); err != nil || len(claimed) != 0 {
+		t.Errorf("repeat ClaimPresents = %v, %v; want none", claimed, err)
+	}
+	if claimed, err := repo.ClaimPresents(charID, []DivaPresent{present}, today.AddDate(0, 0, 1), "Diva Defense Present"); err != nil || len(claimed) != 1 {
+		t.Errorf("next day ClaimPresents = %v, %v; want one claim", claimed, err)
+	}
+}
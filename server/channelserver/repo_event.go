@@ -18,6 +18,13 @@ type EventQuest struct {
 	StartTime    time.Time `db:"start_time"`
 	ActiveDays   int       `db:"active_days"`
 	InactiveDays int       `db:"inactive_days"`
+
+	// Scheduling rules applied by EventQuestScheduler.
+	Weekdays       uint8      `db:"weekdays"` // Bit n set = live on time.Weekday(n); 0 is every day
+	WeekendsOnly   bool       `db:"weekends_only"`
+	AvailableFrom  *time.Time `db:"available_from"`
+	AvailableUntil *time.Time `db:"available_until"`
+	ExclusiveGroup string     `db:"exclusive_group"`
 }
 
 // EventRepository centralizes all database access for event-related tables.
@@ -65,7 +72,10 @@ func (r *EventRepository) UpdateLoginBoost(charID uint32, weekReq uint8, expirat
 // GetEventQuests returns all event quest rows ordered by quest_id.
 func (r *EventRepository) GetEventQuests() ([]EventQuest, error) {
 	var result []EventQuest
-	err := r.db.Select(&result, "SELECT id, COALESCE(max_players, 4) AS max_players, quest_type, quest_id, COALESCE(mark, 0) AS mark, COALESCE(flags, -1) AS flags, start_time, COALESCE(active_days, 0) AS active_days, COALESCE(inactive_days, 0) AS inactive_days FROM event_quests ORDER BY quest_id")
+	err := r.db.Select(&result, `SELECT id, COALESCE(max_players, 4) AS max_players, quest_type, quest_id, COALESCE(mark, 0) AS mark,
+		COALESCE(flags, -1) AS flags, start_time, COALESCE(active_days, 0) AS active_days, COALESCE(inactive_days, 0) AS inactive_days,
+		COALESCE(weekdays, 0) AS weekdays, weekends_only, available_from, available_until, COALESCE(exclusive_group, '') AS exclusive_group
+		FROM event_quests ORDER BY quest_id`)
 	return result, err
 }
 
@@ -74,6 +74,34 @@ func TestGetEventQuestsReturnsRows(t *testing.T) {
 	}
 }
 
+func TestGetEventQuestsSchedulingRules(t *testing.T) {
+	repo, db := setupEventRepo(t)
+
+	now := time.Now().Truncate(time.Microsecond)
+	plain := insertEventQuest(t, db, 1, 100, now, 0, 0)
+	ruled := insertEventQuest(t, db, 1, 200, now, 0, 0)
+	until := now.Add(48 * time.Hour)
+	if _, err := db.Exec(`UPDATE event_quests SET weekdays = 65, weekends_only = true, available_from = $1,
+		available_until = $2, exclusive_group = 'weekly' WHERE id = $3`, now, until, ruled); err != nil {
+		t.Fatalf("Failed to set rules: %v", err)
+	}
+
+	quests, err := repo.GetEventQuests()
+	if err != nil || len(quests) != 2 {
+		t.Fatalf("GetEventQuests = %d quests, %v", len(quests), err)
+	}
+	if quests[0].ID != plain || quests[0].Weekdays != 0 || quests[0].AvailableFrom != nil || quests[0].ExclusiveGroup != "" {
+		t.Errorf("quest without rules = %+v", quests[0])
+	}
+	q := quests[1]
+	if q.Weekdays != 65 || !q.WeekendsOnly || q.ExclusiveGroup != "weekly" {
+		t.Errorf("quest with rules = %+v", q)
+	}
+	if q.AvailableUntil == nil || !q.AvailableUntil.Equal(until) {
+		t.Errorf("AvailableUntil = %v, want %v", q.AvailableUntil, until)
+	}
+}
+
 func TestGetEventQuestsOrderByQuestID(t *testing.T) {
 	repo, db := setupEventRepo(t)
 
@@ -3,12 +3,14 @@ package channelserver
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
 // FestaRepository centralizes all database access for festa-related tables
-// (events, festa_registrations, festa_submissions, festa_prizes, festa_prizes_accepted, festa_trials, guild_characters).
+// (events, festa_registrations, festa_submissions, festa_prizes, festa_prizes_accepted, festa_trials,
+// festa_history, festa_history_guilds, guild_characters).
 type FestaRepository struct {
 	db *sqlx.DB
 }
@@ -32,15 +34,53 @@ type FestaGuildRanking struct {
 	Souls     uint32
 }
 
+// FestaResult is a judged festival: its final team soul totals and winner.
+// ArchivedAt is set once the festival has closed out.
+type FestaResult struct {
+	ID         uint32        `db:"id" json:"id"`
+	EventID    uint32        `db:"event_id" json:"eventId"`
+	StartTime  int64         `db:"start_time" json:"startTime"`
+	BlueSouls  uint32        `db:"blue_souls" json:"blueSouls"`
+	RedSouls   uint32        `db:"red_souls" json:"redSouls"`
+	Winner     FestivalColor `db:"winner" json:"winner"`
+	JudgedAt   time.Time     `db:"judged_at" json:"judgedAt"`
+	ArchivedAt *time.Time    `db:"archived_at" json:"archivedAt,omitempty"`
+}
+
+// FestaHistoryGuild is a registered guild's archived result from a past festival.
+type FestaHistoryGuild struct {
+	GuildID   uint32        `db:"guild_id" json:"guildId"`
+	GuildName string        `db:"guild_name" json:"guildName"`
+	Team      FestivalColor `db:"team" json:"team"`
+	Souls     uint32        `db:"souls" json:"souls"`
+	Place     uint32        `db:"place" json:"place"`
+}
+
+// FestaPrize is a festa_prizes row as edited through the admin API.
+type FestaPrize struct {
+	ID       uint32 `db:"id" json:"id"`
+	Type     string `db:"type" json:"type"`
+	Tier     uint32 `db:"tier" json:"tier"`
+	SoulsReq uint32 `db:"souls_req" json:"soulsReq"`
+	ItemID   uint32 `db:"item_id" json:"itemId"`
+	NumItem  uint32 `db:"num_item" json:"numItem"`
+}
+
+const festaResultColumns = `id, event_id, EXTRACT(epoch FROM start_time)::bigint AS start_time,
+	blue_souls, red_souls, winner, judged_at, archived_at`
+
+// festaCleanupQueries clear the per-festival tables; trials and prizes are kept.
+var festaCleanupQueries = []string{
+	"DELETE FROM events WHERE event_type='festa'",
+	"DELETE FROM festa_registrations",
+	"DELETE FROM festa_submissions",
+	"DELETE FROM festa_prizes_accepted",
+	"UPDATE guild_characters SET trial_vote=NULL",
+}
+
 // CleanupAll removes all festa state: events, registrations, submissions, accepted prizes, and trial votes.
 func (r *FestaRepository) CleanupAll() error {
-	for _, q := range []string{
-		"DELETE FROM events WHERE event_type='festa'",
-		"DELETE FROM festa_registrations",
-		"DELETE FROM festa_submissions",
-		"DELETE FROM festa_prizes_accepted",
-		"UPDATE guild_characters SET trial_vote=NULL",
-	} {
+	for _, q := range festaCleanupQueries {
 		if _, err := r.db.Exec(q); err != nil {
 			return err
 		}
@@ -57,6 +97,22 @@ func (r *FestaRepository) InsertEvent(startTime uint32) error {
 	return err
 }
 
+// InsertEventIfNone creates a festa event with the given start time unless
+// one already exists, reporting whether it did.
+func (r *FestaRepository) InsertEventIfNone(startTime uint32) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO events (event_type, start_time)
+		SELECT 'festa', to_timestamp($1)::timestamp without time zone
+		WHERE NOT EXISTS (SELECT 1 FROM events WHERE event_type='festa')`,
+		startTime,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
 // GetFestaEvents returns all festa events (id and start_time as epoch).
 func (r *FestaRepository) GetFestaEvents() ([]FestaEvent, error) {
 	var events []FestaEvent
@@ -224,5 +280,200 @@ func (r *FestaRepository) ListPrizes(charID uint32, prizeType string) ([]Prize,
 	return prizes, nil
 }
 
-// ensure sql import is used
-var _ = sql.ErrNoRows
+// GetResultByEvent returns the judged result of a festa event, or nil if it
+// has not been judged.
+func (r *FestaRepository) GetResultByEvent(eventID uint32) (*FestaResult, error) {
+	var result FestaResult
+	err := r.db.Get(&result, `SELECT `+festaResultColumns+` FROM festa_history WHERE event_id = $1`, eventID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RecordResult stores a festa event's judged result unless one is already
+// stored, reporting whether it did.
+func (r *FestaRepository) RecordResult(result FestaResult) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO festa_history (event_id, start_time, blue_souls, red_souls, winner)
+		VALUES ($1, to_timestamp($2), $3, $4, $5)
+		ON CONFLICT (event_id) DO NOTHING`,
+		result.EventID, result.StartTime, result.BlueSouls, result.RedSouls, result.Winner,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// CloseOutEvent archives a festa event and clears all festival state in one
+// transaction: the result is stored (keeping an earlier judgement if there is
+// one), every registered guild's souls and placing are copied to
+// festa_history_guilds, and registrations, submissions, claimed prizes and
+// trial votes are removed. A nextStart other than zero schedules the next
+// festival. It reports false without changes if the event no longer exists,
+// so only one channel closes out a festival.
+func (r *FestaRepository) CloseOutEvent(result FestaResult, nextStart uint32) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`DELETE FROM events WHERE id = $1 AND event_type='festa'`, result.EventID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	var historyID uint32
+	if err := tx.QueryRow(`
+		INSERT INTO festa_history (event_id, start_time, blue_souls, red_souls, winner, archived_at)
+		VALUES ($1, to_timestamp($2), $3, $4, $5, now())
+		ON CONFLICT (event_id) DO UPDATE SET archived_at = now()
+		RETURNING id`,
+		result.EventID, result.StartTime, result.BlueSouls, result.RedSouls, result.Winner,
+	).Scan(&historyID); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO festa_history_guilds (history_id, guild_id, guild_name, team, souls, place)
+		SELECT $1, fr.guild_id, COALESCE(g.name, ''), fr.team, fr.souls,
+			RANK() OVER (ORDER BY fr.souls DESC)
+		FROM (
+			SELECT DISTINCT ON (reg.guild_id) reg.guild_id, reg.team,
+				COALESCE((SELECT SUM(fs.souls) FROM festa_submissions fs WHERE fs.guild_id = reg.guild_id), 0) AS souls
+			FROM festa_registrations reg
+			ORDER BY reg.guild_id
+		) fr
+		LEFT JOIN guilds g ON g.id = fr.guild_id`,
+		historyID,
+	); err != nil {
+		return false, err
+	}
+
+	for _, q := range festaCleanupQueries {
+		if _, err := tx.Exec(q); err != nil {
+			return false, err
+		}
+	}
+	if nextStart != 0 {
+		if _, err := tx.Exec(
+			"INSERT INTO events (event_type, start_time) VALUES ('festa', to_timestamp($1)::timestamp without time zone)",
+			nextStart,
+		); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit()
+}
+
+// ListHistory returns archived and judged festivals, latest first.
+func (r *FestaRepository) ListHistory(limit int) ([]FestaResult, error) {
+	var results []FestaResult
+	err := r.db.Select(&results, `SELECT `+festaResultColumns+` FROM festa_history
+		ORDER BY start_time DESC, id DESC LIMIT $1`, limit)
+	return results, err
+}
+
+// GetHistory returns a festival's stored result, or nil if it does not exist.
+func (r *FestaRepository) GetHistory(id uint32) (*FestaResult, error) {
+	var result FestaResult
+	err := r.db.Get(&result, `SELECT `+festaResultColumns+` FROM festa_history WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetHistoryGuilds returns a past festival's guild results by placing.
+func (r *FestaRepository) GetHistoryGuilds(historyID uint32) ([]FestaHistoryGuild, error) {
+	var guilds []FestaHistoryGuild
+	err := r.db.Select(&guilds, `
+		SELECT guild_id, guild_name, team, souls, place FROM festa_history_guilds
+		WHERE history_id = $1 ORDER BY place, guild_id`, historyID)
+	return guilds, err
+}
+
+// CreateTrial adds a festa trial and returns its ID.
+func (r *FestaRepository) CreateTrial(trial FestaTrial) (uint32, error) {
+	var id uint32
+	err := r.db.QueryRow(`
+		INSERT INTO festa_trials (objective, goal_id, times_req, locale_req, reward)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		trial.Objective, trial.GoalID, trial.TimesReq, trial.Locale, trial.Reward,
+	).Scan(&id)
+	return id, err
+}
+
+// DeleteTrial removes a festa trial and clears votes cast for it, reporting
+// whether the trial existed.
+func (r *FestaRepository) DeleteTrial(id uint32) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`UPDATE guild_characters SET trial_vote=NULL WHERE trial_vote=$1`, id); err != nil {
+		return false, err
+	}
+	res, err := tx.Exec(`DELETE FROM festa_trials WHERE id=$1`, id)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// ListAllPrizes returns every festa prize of both types.
+func (r *FestaRepository) ListAllPrizes() ([]FestaPrize, error) {
+	var prizes []FestaPrize
+	err := r.db.Select(&prizes, `SELECT id, type, tier, souls_req, item_id, num_item FROM festa_prizes
+		ORDER BY type, tier, souls_req, id`)
+	return prizes, err
+}
+
+// CreatePrize adds a festa prize and returns its ID.
+func (r *FestaRepository) CreatePrize(prize FestaPrize) (uint32, error) {
+	var id uint32
+	err := r.db.QueryRow(`
+		INSERT INTO festa_prizes (type, tier, souls_req, item_id, num_item)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		prize.Type, prize.Tier, prize.SoulsReq, prize.ItemID, prize.NumItem,
+	).Scan(&id)
+	return id, err
+}
+
+// DeletePrize removes a festa prize and its claim records, reporting whether
+// the prize existed.
+func (r *FestaRepository) DeletePrize(id uint32) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM festa_prizes_accepted WHERE prize_id=$1`, id); err != nil {
+		return false, err
+	}
+	res, err := tx.Exec(`DELETE FROM festa_prizes WHERE id=$1`, id)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	return true, tx.Commit()
+}
@@ -259,3 +259,95 @@ func TestRepoFestaGetTeamSoulsWithSubmissions(t *testing.T) {
 		t.Errorf("Expected red souls=30, got: %d", redSouls)
 	}
 }
+
+func TestRepoFestaCloseOutEvent(t *testing.T) {
+	repo, _, charID, guildID := setupFestaRepo(t)
+
+	startTime := uint32(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC).Unix())
+	if ok, err := repo.InsertEventIfNone(startTime); err != nil || !ok {
+		t.Fatalf("InsertEventIfNone = %v, %v", ok, err)
+	}
+	if ok, err := repo.InsertEventIfNone(startTime + 1); err != nil || ok {
+		t.Errorf("second InsertEventIfNone = %v, %v, want false", ok, err)
+	}
+	events, _ := repo.GetFestaEvents()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got: %d", len(events))
+	}
+	if err := repo.RegisterGuild(guildID, "red"); err != nil {
+		t.Fatalf("RegisterGuild failed: %v", err)
+	}
+	if err := repo.SubmitSouls(charID, guildID, []uint16{40, 2}); err != nil {
+		t.Fatalf("SubmitSouls failed: %v", err)
+	}
+
+	result := FestaResult{EventID: events[0].ID, StartTime: int64(startTime), RedSouls: 42, Winner: FestivalColorRed}
+	if ok, err := repo.RecordResult(result); err != nil || !ok {
+		t.Fatalf("RecordResult = %v, %v", ok, err)
+	}
+	judged, err := repo.GetResultByEvent(events[0].ID)
+	if err != nil || judged == nil || judged.Winner != FestivalColorRed || judged.ArchivedAt != nil {
+		t.Fatalf("GetResultByEvent = %+v, %v", judged, err)
+	}
+
+	next := startTime + 40*secsPerDay
+	if ok, err := repo.CloseOutEvent(result, next); err != nil || !ok {
+		t.Fatalf("CloseOutEvent = %v, %v", ok, err)
+	}
+	if ok, err := repo.CloseOutEvent(result, next); err != nil || ok {
+		t.Errorf("second CloseOutEvent = %v, %v, want false", ok, err)
+	}
+
+	events, _ = repo.GetFestaEvents()
+	if len(events) != 1 || events[0].StartTime != next {
+		t.Errorf("events after close-out = %+v, want the next festival", events)
+	}
+	if souls, _ := repo.GetTeamSouls("red"); souls != 0 {
+		t.Errorf("red souls after close-out = %d, want 0", souls)
+	}
+	history, err := repo.ListHistory(10)
+	if err != nil || len(history) != 1 || history[0].ArchivedAt == nil || history[0].StartTime != int64(startTime) {
+		t.Fatalf("ListHistory = %+v, %v", history, err)
+	}
+	guilds, err := repo.GetHistoryGuilds(history[0].ID)
+	if err != nil || len(guilds) != 1 || guilds[0].GuildName != "FestaGuild" || guilds[0].Souls != 42 || guilds[0].Place != 1 {
+		t.Errorf("GetHistoryGuilds = %+v, %v", guilds, err)
+	}
+}
+
+func TestRepoFestaTrialAndPrizeAdmin(t *testing.T) {
+	repo, db, charID, _ := setupFestaRepo(t)
+
+	trialID, err := repo.CreateTrial(FestaTrial{Objective: 1, GoalID: 5, TimesReq: 3, Reward: 10})
+	if err != nil {
+		t.Fatalf("CreateTrial failed: %v", err)
+	}
+	if err := repo.VoteTrial(charID, trialID); err != nil {
+		t.Fatalf("VoteTrial failed: %v", err)
+	}
+	if ok, err := repo.DeleteTrial(trialID); err != nil || !ok {
+		t.Fatalf("DeleteTrial = %v, %v", ok, err)
+	}
+	var votes int
+	if err := db.QueryRow("SELECT COUNT(*) FROM guild_characters WHERE trial_vote IS NOT NULL").Scan(&votes); err != nil || votes != 0 {
+		t.Errorf("votes after delete = %d, %v, want 0", votes, err)
+	}
+	if ok, err := repo.DeleteTrial(trialID); err != nil || ok {
+		t.Errorf("second DeleteTrial = %v, %v, want false", ok, err)
+	}
+
+	prizeID, err := repo.CreatePrize(FestaPrize{Type: "personal", Tier: 1, SoulsReq: 100, ItemID: 7011, NumItem: 2})
+	if err != nil {
+		t.Fatalf("CreatePrize failed: %v", err)
+	}
+	if err := repo.ClaimPrize(prizeID, charID); err != nil {
+		t.Fatalf("ClaimPrize failed: %v", err)
+	}
+	prizes, err := repo.ListAllPrizes()
+	if err != nil || len(prizes) != 1 || prizes[0].Type != "personal" || prizes[0].NumItem != 2 {
+		t.Fatalf("ListAllPrizes = %+v, %v", prizes, err)
+	}
+	if ok, err := repo.DeletePrize(prizeID); err != nil || !ok {
+		t.Errorf("DeletePrize = %v, %v", ok, err)
+	}
+}
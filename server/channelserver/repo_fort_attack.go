@@ -0,0 +1,184 @@
+package channelserver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// FortAttackRepository centralizes all database access for the
+// fort_attack_events and fort_attack_participants tables.
+type FortAttackRepository struct {
+	db *sqlx.DB
+}
+
+// NewFortAttackRepository creates a new FortAttackRepository.
+func NewFortAttackRepository(db *sqlx.DB) *FortAttackRepository {
+	return &FortAttackRepository{db: db}
+}
+
+// FortAttack is a scheduled Interceptor's Base fort attack event.
+type FortAttack struct {
+	ID            uint32        `db:"id"`
+	ScheduleIndex int           `db:"schedule_index"` // Position in FortAttack.Events
+	EventType     uint16        `db:"event_type"`
+	StartsAt      time.Time     `db:"starts_at"`
+	EndsAt        time.Time     `db:"ends_at"`
+	QuestFileIDs  pq.Int64Array `db:"quest_file_ids"`
+	MinHR         uint16        `db:"min_hr"`
+	MaxSorties    int           `db:"max_sorties"`
+	Durability    int           `db:"durability"`
+	MaxDurability int           `db:"max_durability"`
+	EndedAt       *time.Time    `db:"ended_at"`
+}
+
+// hasQuest reports whether questID is one of the event's fort quests.
+func (f *FortAttack) hasQuest(questID uint16) bool {
+	for _, id := range f.QuestFileIDs {
+		if id == int64(questID) {
+			return true
+		}
+	}
+	return false
+}
+
+// FortAttackParticipant is a character's sortie count for a fort attack.
+type FortAttackParticipant struct {
+	CharID  uint32 `db:"character_id"`
+	Sorties int    `db:"sorties"`
+	Clears  int    `db:"clears"`
+}
+
+// FortAttackGrant is the participation reward paid to one character.
+type FortAttackGrant struct {
+	CharID      uint32
+	Description string
+	Items       []DistributionItem
+}
+
+const fortAttackColumns = `id, schedule_index, event_type, starts_at, ends_at, quest_file_ids,
+	min_hr, max_sorties, durability, max_durability, ended_at`
+
+// ScheduleEvent stores a fort attack unless the same schedule entry already
+// has one at that start time, reporting whether it did.
+func (r *FortAttackRepository) ScheduleEvent(event FortAttack) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO fort_attack_events (schedule_index, event_type, starts_at, ends_at, quest_file_ids,
+			min_hr, max_sorties, durability, max_durability)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (schedule_index, starts_at) DO NOTHING`,
+		event.ScheduleIndex, event.EventType, event.StartsAt, event.EndsAt, event.QuestFileIDs,
+		event.MinHR, event.MaxSorties, event.MaxDurability,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListOpen returns the fort attacks that have not been ended and start
+// before until, earliest first.
+func (r *FortAttackRepository) ListOpen(until time.Time) ([]FortAttack, error) {
+	var events []FortAttack
+	err := r.db.Select(&events, `SELECT `+fortAttackColumns+` FROM fort_attack_events
+		WHERE ended_at IS NULL AND starts_at < $1
+		ORDER BY starts_at, id`, until)
+	return events, err
+}
+
+// RecordSortie counts a sortie by a character, reporting false without
+// counting it when the character already took maxSorties (0 is unlimited).
+func (r *FortAttackRepository) RecordSortie(eventID, charID uint32, maxSorties int) (bool, error) {
+	res, err := r.db.Exec(`
+		INSERT INTO fort_attack_participants AS p (event_id, character_id, sorties)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (event_id, character_id) DO UPDATE SET sorties = p.sorties + 1
+		WHERE $3 = 0 OR p.sorties < $3`,
+		eventID, charID, maxSorties,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// RecordSortieResult records how a sortie ended. A clear is credited to the
+// character; otherwise the fort loses damage durability, and the event is
+// ended early once it reaches zero. It returns the remaining durability, or 0
+// if the event has already ended.
+func (r *FortAttackRepository) RecordSortieResult(eventID, charID uint32, cleared bool, damage int) (int, error) {
+	var durability int
+	if cleared {
+		if _, err := r.db.Exec(`UPDATE fort_attack_participants SET clears = clears + 1
+			WHERE event_id = $1 AND character_id = $2`, eventID, charID); err != nil {
+			return 0, err
+		}
+		err := r.db.QueryRow(`SELECT durability FROM fort_attack_events WHERE id = $1`, eventID).Scan(&durability)
+		return durability, err
+	}
+	err := r.db.QueryRow(`
+		UPDATE fort_attack_events SET
+			durability = GREATEST(durability - $2, 0),
+			ends_at = CASE WHEN durability - $2 <= 0 THEN LEAST(ends_at, now()) ELSE ends_at END
+		WHERE id = $1 AND ended_at IS NULL
+		RETURNING durability`,
+		eventID, damage,
+	).Scan(&durability)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The event ended while the sortie was underway.
+		return 0, nil
+	}
+	return durability, err
+}
+
+// GetParticipants returns everyone who took a sortie on a fort attack.
+func (r *FortAttackRepository) GetParticipants(eventID uint32) ([]FortAttackParticipant, error) {
+	var participants []FortAttackParticipant
+	err := r.db.Select(&participants, `SELECT character_id, sorties, clears FROM fort_attack_participants
+		WHERE event_id = $1 ORDER BY character_id`, eventID)
+	return participants, err
+}
+
+// EndEvent marks a fort attack ended and inserts its participation rewards
+// as distributions in one transaction. It reports false without paying if
+// the event was already ended, so only one channel pays out.
+func (r *FortAttackRepository) EndEvent(eventID uint32, grants []FortAttackGrant, eventName string) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`UPDATE fort_attack_events SET ended_at = now() WHERE id = $1 AND ended_at IS NULL`, eventID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	for _, grant := range grants {
+		var distID uint32
+		if err := tx.QueryRow(`
+			INSERT INTO distribution (character_id, type, event_name, description, times_acceptable)
+			VALUES ($1, $2, $3, $4, 1) RETURNING id
+		`, grant.CharID, fortAttackDistributionType, eventName, grant.Description).Scan(&distID); err != nil {
+			return false, fmt.Errorf("insert distribution: %w", err)
+		}
+		for _, item := range grant.Items {
+			if _, err := tx.Exec(`
+				INSERT INTO distribution_items (distribution_id, item_type, item_id, quantity) VALUES ($1, $2, $3, $4)
+			`, distID, item.ItemType, item.ItemID, item.Quantity); err != nil {
+				return false, fmt.Errorf("insert distribution item: %w", err)
+			}
+		}
+	}
+	return true, tx.Commit()
+}
@@ -0,0 +1,93 @@
+package channelserver
+
+import (
+	"testing"
+	"time"
+)
+
+func setupFortAttackRepo(t *testing.T) (*FortAttackRepository, uint32, uint32) {
+	t.Helper()
+	db := SetupTestDB(t)
+	userID := CreateTestUser(t, db, "fort_test_user")
+	first := CreateTestCharacter(t, db, userID, "FortOne")
+	second := CreateTestCharacter(t, db, userID, "FortTwo")
+	repo := NewFortAttackRepository(db)
+	t.Cleanup(func() { TeardownTestDB(t, db) })
+	return repo, first, second
+}
+
+func TestRepoFortAttackLifecycle(t *testing.T) {
+	repo, first, second := setupFortAttackRepo(t)
+	start := time.Now().Add(-time.Minute).Truncate(time.Second)
+	event := FortAttack{
+		ScheduleIndex: 0,
+		EventType:     2,
+		StartsAt:      start,
+		EndsAt:        start.Add(time.Hour),
+		QuestFileIDs:  []int64{20001, 20004},
+		MinHR:         100,
+		MaxSorties:    2,
+		MaxDurability: 30,
+	}
+
+	if ok, err := repo.ScheduleEvent(event); err != nil || !ok {
+		t.Fatalf("ScheduleEvent = %v, %v", ok, err)
+	}
+	if ok, err := repo.ScheduleEvent(event); err != nil || ok {
+		t.Errorf("duplicate ScheduleEvent = %v, %v, want false", ok, err)
+	}
+
+	open, err := repo.ListOpen(time.Now())
+	if err != nil || len(open) != 1 {
+		t.Fatalf("ListOpen = %+v, %v", open, err)
+	}
+	id := open[0].ID
+	if open[0].Durability != 30 || !open[0].hasQuest(20004) || open[0].MinHR != 100 {
+		t.Errorf("event = %+v", open[0])
+	}
+
+	for i := 0; i < 2; i++ {
+		if ok, err := repo.RecordSortie(id, first, 2); err != nil || !ok {
+			t.Fatalf("RecordSortie %d = %v, %v", i, ok, err)
+		}
+	}
+	if ok, err := repo.RecordSortie(id, first, 2); err != nil || ok {
+		t.Errorf("third RecordSortie = %v, %v, want false", ok, err)
+	}
+	if ok, err := repo.RecordSortie(id, second, 2); err != nil || !ok {
+		t.Fatalf("RecordSortie second = %v, %v", ok, err)
+	}
+
+	if durability, err := repo.RecordSortieResult(id, first, true, 20); err != nil || durability != 30 {
+		t.Errorf("clear: durability = %d, %v", durability, err)
+	}
+	if durability, err := repo.RecordSortieResult(id, second, false, 20); err != nil || durability != 10 {
+		t.Errorf("failure: durability = %d, %v", durability, err)
+	}
+	if durability, err := repo.RecordSortieResult(id, second, false, 20); err != nil || durability != 0 {
+		t.Errorf("fall: durability = %d, %v", durability, err)
+	}
+
+	participants, err := repo.GetParticipants(id)
+	if err != nil || len(participants) != 2 {
+		t.Fatalf("GetParticipants = %+v, %v", participants, err)
+	}
+	if participants[0].CharID != first || participants[0].Sorties != 2 || participants[0].Clears != 1 {
+		t.Errorf("first participant = %+v", participants[0])
+	}
+
+	grants := []FortAttackGrant{{CharID: first, Description: "~C05test", Items: []DistributionItem{{ItemType: 7, ItemID: 100, Quantity: 1}}}}
+	if ok, err := repo.EndEvent(id, grants, fortAttackRewardEventName); err != nil || !ok {
+		t.Fatalf("EndEvent = %v, %v", ok, err)
+	}
+	if ok, err := repo.EndEvent(id, grants, fortAttackRewardEventName); err != nil || ok {
+		t.Errorf("second EndEvent = %v, %v, want false", ok, err)
+	}
+	if open, _ := repo.ListOpen(time.Now().Add(time.Hour)); len(open) != 0 {
+		t.Errorf("ended event still open: %+v", open)
+	}
+	var paid int
+	if err := repo.db.QueryRow(`SELECT COUNT(*) FROM distribution WHERE character_id = $1`, first).Scan(&paid); err != nil || paid != 1 {
+		t.Errorf("distributions = %d, %v, want 1", paid, err)
+	}
+}
@@ -1,8 +1,12 @@
 package channelserver
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -10,7 +14,8 @@ import (
 )
 
 // GachaRepository centralizes all database access for gacha-related tables
-// (gacha_shop, gacha_entries, gacha_items, gacha_stepup, gacha_box).
+// (gacha_shop, gacha_entries, gacha_items, gacha_stepup, gacha_box,
+// gacha_history).
 type GachaRepository struct {
 	db     *sqlx.DB
 	logger *zap.Logger
@@ -258,3 +263,370 @@ func (r *GachaRepository) GetWeightDivisor(gachaID uint32) (float64, error) {
 	).Scan(&divisor)
 	return divisor, err
 }
+
+// Catalogue methods
+
+// GachaCatalogue is a gacha with its entries and their items, as managed
+// through the admin API.
+type GachaCatalogue struct {
+	Gacha
+	Entries []GachaCatalogueEntry `json:"entries"`
+}
+
+// GachaCatalogueEntry is a gacha entry with its items. Roll entries
+// (entry_type below 100) define a roll's cost and, for step-up gachas, the
+// items it guarantees; reward entries (entry_type 100) make up the pool.
+type GachaCatalogueEntry struct {
+	GachaEntry
+	Items []GachaItem `json:"items"`
+}
+
+// GetShop returns a gacha shop definition, or nil if it does not exist.
+func (r *GachaRepository) GetShop(id uint32) (*Gacha, error) {
+	var g Gacha
+	err := r.db.QueryRowx(
+		`SELECT id, COALESCE(min_gr, 0) AS min_gr, COALESCE(min_hr, 0) AS min_hr, COALESCE(name, '') AS name,
+		COALESCE(url_banner, '') AS url_banner, COALESCE(url_feature, '') AS url_feature,
+		COALESCE(url_thumbnail, '') AS url_thumbnail, COALESCE(wide, false) AS wide,
+		COALESCE(recommended, false) AS recommended, COALESCE(gacha_type, 0) AS gacha_type,
+		COALESCE(hidden, false) AS hidden
+		FROM gacha_shop WHERE id = $1`,
+		id,
+	).StructScan(&g)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// CreateShop inserts a gacha shop definition and returns its ID.
+func (r *GachaRepository) CreateShop(g Gacha) (uint32, error) {
+	var id uint32
+	err := r.db.QueryRow(
+		`INSERT INTO gacha_shop (min_gr, min_hr, name, url_banner, url_feature, url_thumbnail, wide, recommended, gacha_type, hidden)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`,
+		g.MinGR, g.MinHR, g.Name, g.URLBanner, g.URLFeature, g.URLThumbnail, g.Wide, g.Recommended, g.GachaType, g.Hidden,
+	).Scan(&id)
+	return id, err
+}
+
+// UpdateShop replaces a gacha shop definition. It reports false if the gacha
+// does not exist.
+func (r *GachaRepository) UpdateShop(g Gacha) (bool, error) {
+	res, err := r.db.Exec(
+		`UPDATE gacha_shop SET min_gr = $2, min_hr = $3, name = $4, url_banner = $5, url_feature = $6,
+		url_thumbnail = $7, wide = $8, recommended = $9, gacha_type = $10, hidden = $11
+		WHERE id = $1`,
+		g.ID, g.MinGR, g.MinHR, g.Name, g.URLBanner, g.URLFeature, g.URLThumbnail, g.Wide, g.Recommended, g.GachaType, g.Hidden,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// DeleteShop removes a gacha with its entries, items and every character's
+// step-up and box progress on it. It reports false if the gacha does not
+// exist.
+func (r *GachaRepository) DeleteShop(id uint32) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, q := range []string{
+		`DELETE FROM gacha_items WHERE entry_id IN (SELECT id FROM gacha_entries WHERE gacha_id = $1)`,
+		`DELETE FROM gacha_entries WHERE gacha_id = $1`,
+		`DELETE FROM gacha_box WHERE gacha_id = $1`,
+		`DELETE FROM gacha_stepup WHERE gacha_id = $1`,
+	} {
+		if _, err := tx.Exec(q, id); err != nil {
+			return false, err
+		}
+	}
+	res, err := tx.Exec(`DELETE FROM gacha_shop WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// ListCatalogueEntries returns every entry of a gacha with its items, roll
+// entries first.
+func (r *GachaRepository) ListCatalogueEntries(gachaID uint32) ([]GachaCatalogueEntry, error) {
+	rows, err := r.db.Queryx(
+		`SELECT COALESCE(entry_type, 0) AS entry_type, id, COALESCE(item_type, 0) AS item_type,
+		COALESCE(item_number, 0) AS item_number, COALESCE(item_quantity, 0) AS item_quantity,
+		COALESCE(weight, 0) AS weight, COALESCE(rarity, 0) AS rarity, COALESCE(rolls, 0) AS rolls,
+		COALESCE(frontier_points, 0) AS frontier_points, COALESCE(daily_limit, 0) AS daily_limit,
+		COALESCE(name, '') AS name
+		FROM gacha_entries WHERE gacha_id = $1 ORDER BY entry_type, id`,
+		gachaID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var entries []GachaCatalogueEntry
+	index := make(map[uint32]int)
+	for rows.Next() {
+		var e GachaCatalogueEntry
+		if err := rows.StructScan(&e.GachaEntry); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		e.Items = []GachaItem{}
+		index[e.ID] = len(entries)
+		entries = append(entries, e)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.Queryx(
+		`SELECT gi.entry_id, gi.item_type, gi.item_id, gi.quantity FROM gacha_items gi
+		JOIN gacha_entries e ON e.id = gi.entry_id
+		WHERE e.gacha_id = $1 ORDER BY gi.id`,
+		gachaID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var entryID uint32
+		var item GachaItem
+		if err := rows.Scan(&entryID, &item.ItemType, &item.ItemID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		if i, ok := index[entryID]; ok {
+			entries[i].Items = append(entries[i].Items, item)
+		}
+	}
+	return entries, rows.Err()
+}
+
+// CreateEntry inserts a gacha entry with its items and returns its ID.
+func (r *GachaRepository) CreateEntry(gachaID uint32, e GachaCatalogueEntry) (uint32, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var id uint32
+	err = tx.QueryRow(
+		`INSERT INTO gacha_entries (gacha_id, entry_type, item_type, item_number, item_quantity, weight, rarity, rolls, frontier_points, daily_limit, name)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id`,
+		gachaID, e.EntryType, e.ItemType, e.ItemNumber, e.ItemQuantity, int64(e.Weight), e.Rarity, e.Rolls,
+		e.FrontierPoints, e.DailyLimit, e.Name,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	if err := insertGachaItems(tx, id, e.Items); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// UpdateEntry replaces a gacha entry and its items. It reports false if the
+// entry does not exist on the given gacha.
+func (r *GachaRepository) UpdateEntry(gachaID uint32, e GachaCatalogueEntry) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(
+		`UPDATE gacha_entries SET entry_type = $3, item_type = $4, item_number = $5, item_quantity = $6, weight = $7,
+		rarity = $8, rolls = $9, frontier_points = $10, daily_limit = $11, name = $12
+		WHERE id = $1 AND gacha_id = $2`,
+		e.ID, gachaID, e.EntryType, e.ItemType, e.ItemNumber, e.ItemQuantity, int64(e.Weight), e.Rarity, e.Rolls,
+		e.FrontierPoints, e.DailyLimit, e.Name,
+	)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if _, err := tx.Exec(`DELETE FROM gacha_items WHERE entry_id = $1`, e.ID); err != nil {
+		return false, err
+	}
+	if err := insertGachaItems(tx, e.ID, e.Items); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// DeleteEntry removes a gacha entry with its items and any box draws of it.
+// It reports false if the entry does not exist on the given gacha.
+func (r *GachaRepository) DeleteEntry(gachaID, entryID uint32) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`DELETE FROM gacha_entries WHERE id = $1 AND gacha_id = $2`, entryID, gachaID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if _, err := tx.Exec(`DELETE FROM gacha_items WHERE entry_id = $1`, entryID); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(`DELETE FROM gacha_box WHERE gacha_id = $1 AND entry_id = $2`, gachaID, entryID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// insertGachaItems adds items to a gacha entry.
+func insertGachaItems(tx *sqlx.Tx, entryID uint32, items []GachaItem) error {
+	for _, item := range items {
+		if _, err := tx.Exec(
+			`INSERT INTO gacha_items (entry_id, item_type, item_id, quantity) VALUES ($1, $2, $3, $4)`,
+			entryID, item.ItemType, item.ItemID, item.Quantity,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Play history methods
+
+// Kinds of gacha play recorded in the ledger.
+const (
+	GachaPlayNormal = "normal"
+	GachaPlayStepup = "stepup"
+	GachaPlayBox    = "box"
+	GachaPlayFree   = "free"
+)
+
+// Balances the server charges for a roll. Rolls costing anything else are
+// paid for by the client and recorded without a currency.
+const (
+	GachaCurrencyNetcafePoints  = "netcafe_points"
+	GachaCurrencyTrialCoins     = "trial_coins"
+	GachaCurrencyPremiumCoins   = "premium_coins"
+	GachaCurrencyFrontierPoints = "frontier_points"
+)
+
+// GachaPlay is one roll in the gacha play ledger.
+type GachaPlay struct {
+	ID        uint64          `json:"id"`
+	UserID    uint32          `json:"userId"`
+	CharID    uint32          `json:"charId"`
+	GachaID   uint32          `json:"gachaId"`
+	GachaName string          `json:"gachaName"` // Shop name when the roll was made
+	Kind      string          `json:"kind"`
+	RollType  uint8           `json:"rollType"` // Roll entry type, the step for step-up gachas
+	CostType  uint8           `json:"costType"` // Item type of the roll's cost
+	Cost      uint32          `json:"cost"`
+	Currency  string          `json:"currency"` // Balance the server charged, empty if none
+	Items     []GachaPlayItem `json:"items"`
+	PlayedAt  time.Time       `json:"playedAt"`
+}
+
+// GachaPlayItem is an item won by a roll.
+type GachaPlayItem struct {
+	ItemType   uint8  `json:"itemType"`
+	ItemID     uint16 `json:"itemId"`
+	Quantity   uint16 `json:"quantity"`
+	Rarity     uint8  `json:"rarity"`
+	Guaranteed bool   `json:"guaranteed,omitempty"` // Awarded by a step-up roll rather than drawn
+}
+
+// GachaPlayFilter narrows a ledger query. Zero fields match every play.
+type GachaPlayFilter struct {
+	UserID   uint32
+	CharID   uint32
+	GachaID  uint32
+	Since    *time.Time
+	Until    *time.Time
+	BeforeID uint64 // Only plays older than this one, for paging
+	Limit    int
+}
+
+// InsertPlay records a roll in the ledger. The shop name is copied so the
+// record stays readable after the shop is renamed or deleted.
+func (r *GachaRepository) InsertPlay(p GachaPlay) error {
+	items, err := json.Marshal(p.Items)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(`
+		INSERT INTO gacha_history (user_id, character_id, gacha_id, gacha_name, kind, roll_type, cost_type, cost, currency, items)
+		VALUES ($1, $2, $3, COALESCE((SELECT name FROM gacha_shop WHERE id = $3), ''), $4, $5, $6, $7, $8, $9)`,
+		p.UserID, p.CharID, p.GachaID, p.Kind, p.RollType, p.CostType, p.Cost, p.Currency, items,
+	)
+	return err
+}
+
+// ListPlays returns ledger entries matching f, newest first.
+func (r *GachaRepository) ListPlays(f GachaPlayFilter) ([]GachaPlay, error) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.UserID > 0 {
+		add("user_id = $%d", f.UserID)
+	}
+	if f.CharID > 0 {
+		add("character_id = $%d", f.CharID)
+	}
+	if f.GachaID > 0 {
+		add("gacha_id = $%d", f.GachaID)
+	}
+	if f.Since != nil {
+		add("played_at >= $%d", *f.Since)
+	}
+	if f.Until != nil {
+		add("played_at < $%d", *f.Until)
+	}
+	if f.BeforeID > 0 {
+		add("id < $%d", f.BeforeID)
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, f.Limit)
+	rows, err := r.db.Query(`
+		SELECT id, user_id, character_id, gacha_id, gacha_name, kind, roll_type, cost_type, cost, currency, items, played_at
+		FROM gacha_history `+where+fmt.Sprintf(` ORDER BY id DESC LIMIT $%d`, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var plays []GachaPlay
+	for rows.Next() {
+		var p GachaPlay
+		var items []byte
+		if err := rows.Scan(&p.ID, &p.UserID, &p.CharID, &p.GachaID, &p.GachaName, &p.Kind, &p.RollType,
+			&p.CostType, &p.Cost, &p.Currency, &items, &p.PlayedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(items, &p.Items); err != nil {
+			return nil, fmt.Errorf("decode items: %w", err)
+		}
+		plays = append(plays, p)
+	}
+	return plays, rows.Err()
+}
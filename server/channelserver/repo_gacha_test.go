@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -377,3 +378,122 @@ func TestRepoGachaBoxIsolation(t *testing.T) {
 		t.Errorf("Expected empty box for char2, got: %d entries", len(ids))
 	}
 }
+
+func TestRepoGachaCatalogueLifecycle(t *testing.T) {
+	repo, db, charID := setupGachaRepo(t)
+
+	id, err := repo.CreateShop(Gacha{Name: "Catalogue", GachaType: 4, MinHR: 10, Hidden: true})
+	if err != nil {
+		t.Fatalf("CreateShop failed: %v", err)
+	}
+	g, err := repo.GetShop(id)
+	if err != nil || g == nil || g.Name != "Catalogue" || g.GachaType != 4 || !g.Hidden {
+		t.Fatalf("GetShop = %+v, %v", g, err)
+	}
+	g.Name = "Renamed"
+	if ok, err := repo.UpdateShop(*g); err != nil || !ok {
+		t.Fatalf("UpdateShop = %v, %v", ok, err)
+	}
+
+	rollID, err := repo.CreateEntry(id, GachaCatalogueEntry{GachaEntry: GachaEntry{EntryType: 0, ItemType: 19, ItemNumber: 5, Rolls: 1}})
+	if err != nil {
+		t.Fatalf("CreateEntry failed: %v", err)
+	}
+	rewardID, err := repo.CreateEntry(id, GachaCatalogueEntry{
+		GachaEntry: GachaEntry{EntryType: 100, Weight: 30, Rarity: 2},
+		Items:      []GachaItem{{ItemType: 7, ItemID: 10, Quantity: 1}, {ItemType: 7, ItemID: 11, Quantity: 3}},
+	})
+	if err != nil {
+		t.Fatalf("CreateEntry failed: %v", err)
+	}
+	entries, err := repo.ListCatalogueEntries(id)
+	if err != nil {
+		t.Fatalf("ListCatalogueEntries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != rollID || len(entries[0].Items) != 0 || len(entries[1].Items) != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+
+	reward := entries[1]
+	reward.Weight = 40
+	reward.Items = reward.Items[:1]
+	if ok, err := repo.UpdateEntry(id, reward); err != nil || !ok {
+		t.Fatalf("UpdateEntry = %v, %v", ok, err)
+	}
+	if ok, _ := repo.UpdateEntry(id+1, reward); ok {
+		t.Error("UpdateEntry changed an entry of another gacha")
+	}
+	pool, _ := repo.GetRewardPool(id)
+	if len(pool) != 1 || pool[0].Weight != 40 {
+		t.Errorf("reward pool = %+v", pool)
+	}
+
+	if err := repo.InsertBoxEntry(id, rewardID, charID); err != nil {
+		t.Fatalf("InsertBoxEntry failed: %v", err)
+	}
+	if ok, err := repo.DeleteEntry(id, rewardID); err != nil || !ok {
+		t.Fatalf("DeleteEntry = %v, %v", ok, err)
+	}
+	if ids, _ := repo.GetBoxEntryIDs(id, charID); len(ids) != 0 {
+		t.Errorf("box draws of a deleted entry remain: %v", ids)
+	}
+
+	if ok, err := repo.DeleteShop(id); err != nil || !ok {
+		t.Fatalf("DeleteShop = %v, %v", ok, err)
+	}
+	if g, _ := repo.GetShop(id); g != nil {
+		t.Error("gacha still exists after DeleteShop")
+	}
+	var n int
+	_ = db.QueryRow(`SELECT COUNT(*) FROM gacha_entries WHERE gacha_id = $1`, id).Scan(&n)
+	if n != 0 {
+		t.Errorf("%d entries remain after DeleteShop", n)
+	}
+}
+
+func TestRepoGachaPlayHistory(t *testing.T) {
+	repo, _, charID := setupGachaRepo(t)
+
+	shopID, err := repo.CreateShop(Gacha{Name: "Ledger"})
+	if err != nil {
+		t.Fatalf("CreateShop failed: %v", err)
+	}
+	for i := uint8(0); i < 3; i++ {
+		if err := repo.InsertPlay(GachaPlay{
+			UserID: 90001, CharID: charID, GachaID: shopID, Kind: GachaPlayNormal, RollType: i,
+			CostType: 19, Cost: 5, Currency: GachaCurrencyTrialCoins,
+			Items: []GachaPlayItem{{ItemType: 7, ItemID: 500, Quantity: 2, Rarity: 3}},
+		}); err != nil {
+			t.Fatalf("InsertPlay failed: %v", err)
+		}
+	}
+	if err := repo.InsertPlay(GachaPlay{UserID: 90001, CharID: charID, GachaID: shopID + 1, Kind: GachaPlayFree, Items: []GachaPlayItem{}}); err != nil {
+		t.Fatalf("InsertPlay failed: %v", err)
+	}
+
+	plays, err := repo.ListPlays(GachaPlayFilter{CharID: charID, GachaID: shopID, Limit: 10})
+	if err != nil {
+		t.Fatalf("ListPlays failed: %v", err)
+	}
+	if len(plays) != 3 || plays[0].RollType != 2 || plays[0].GachaName != "Ledger" || plays[0].Currency != GachaCurrencyTrialCoins {
+		t.Fatalf("plays = %+v", plays)
+	}
+	if len(plays[0].Items) != 1 || plays[0].Items[0].ItemID != 500 {
+		t.Errorf("items = %+v", plays[0].Items)
+	}
+
+	page, err := repo.ListPlays(GachaPlayFilter{CharID: charID, BeforeID: plays[0].ID, Limit: 1})
+	if err != nil || len(page) != 1 || page[0].ID != plays[1].ID {
+		t.Errorf("page = %+v, %v; want play %d", page, err, plays[1].ID)
+	}
+
+	// The ledger outlives the shop.
+	if _, err := repo.DeleteShop(shopID); err != nil {
+		t.Fatalf("DeleteShop failed: %v", err)
+	}
+	future := time.Now().Add(time.Hour)
+	all, err := repo.ListPlays(GachaPlayFilter{UserID: 90001, Until: &future, Limit: 10})
+	if err != nil || len(all) != 4 {
+		t.Errorf("after delete = %d plays, %v; want 4", len(all), err)
+	}
+}
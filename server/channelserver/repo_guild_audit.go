@@ -0,0 +1,103 @@
+package channelserver
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// insertGuildAudit records entry in guild_audit as part of tx.
+func insertGuildAudit(tx *sqlx.Tx, entry GuildAuditEntry) error {
+	_, err := tx.Exec(`
+		INSERT INTO guild_audit (guild_id, actor_source, actor_id, action, old_value, new_value)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, entry.GuildID, entry.ActorSource, entry.ActorID, entry.Action, entry.OldValue, entry.NewValue)
+	return err
+}
+
+// auditedUpdate runs update and records entry in one transaction.
+func (r *GuildRepository) auditedUpdate(entry GuildAuditEntry, update func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := update(tx); err != nil {
+		return err
+	}
+	if err := insertGuildAudit(tx, entry); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AdminSetRankRP sets a guild's rank RP and records the change.
+func (r *GuildRepository) AdminSetRankRP(guildID, rp uint32, entry GuildAuditEntry) error {
+	return r.auditedUpdate(entry, func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(`UPDATE guilds SET rank_rp = $1 WHERE id = $2`, rp, guildID)
+		return err
+	})
+}
+
+// AdminRename renames a guild and records the change.
+func (r *GuildRepository) AdminRename(guildID uint32, name string, entry GuildAuditEntry) error {
+	return r.auditedUpdate(entry, func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(`UPDATE guilds SET name = $1 WHERE id = $2`, name, guildID)
+		return err
+	})
+}
+
+// AdminTransferLeader makes newLeaderID the guild leader and records the
+// change. The new leader takes order index 1 and the previous leader takes
+// the new leader's old index, as on a voluntary resignation.
+func (r *GuildRepository) AdminTransferLeader(guildID, oldLeaderID, newLeaderID uint32, entry GuildAuditEntry) error {
+	return r.auditedUpdate(entry, func(tx *sqlx.Tx) error {
+		if _, err := tx.Exec(`
+			UPDATE guild_characters SET order_index = (
+				SELECT order_index FROM guild_characters WHERE character_id = $2
+			) WHERE character_id = $1 AND guild_id = $3
+		`, oldLeaderID, newLeaderID, guildID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE guild_characters SET order_index = 1 WHERE character_id = $1 AND guild_id = $2`,
+			newLeaderID, guildID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE guilds SET leader_id = $1 WHERE id = $2`, newLeaderID, guildID)
+		return err
+	})
+}
+
+// AdminUpdateGuildcard sets a guild's mottos and comment and records the change.
+func (r *GuildRepository) AdminUpdateGuildcard(guildID uint32, mainMotto, subMotto uint8, comment string, entry GuildAuditEntry) error {
+	return r.auditedUpdate(entry, func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(`UPDATE guilds SET main_motto = $1, sub_motto = $2, comment = $3 WHERE id = $4`,
+			mainMotto, subMotto, comment, guildID)
+		return err
+	})
+}
+
+// ListAudit returns up to limit guild_audit entries for a guild, newest first.
+func (r *GuildRepository) ListAudit(guildID uint32, limit int) ([]*GuildAuditEntry, error) {
+	rows, err := r.db.Queryx(`
+		SELECT id, guild_id, actor_source, actor_id, action, old_value, new_value, created_at
+		FROM guild_audit
+		WHERE guild_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`, guildID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	entries := make([]*GuildAuditEntry, 0)
+	for rows.Next() {
+		entry := &GuildAuditEntry{}
+		if err := rows.StructScan(entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -1530,3 +1530,57 @@ func TestCreateInviteWithMail(t *testing.T) {
 		t.Errorf("Expected 1 mail row, got %d", mailCount)
 	}
 }
+
+func TestAdminGuildUpdatesAreAudited(t *testing.T) {
+	repo, db, guildID, leaderID := setupGuildRepo(t)
+
+	user2 := CreateTestUser(t, db, "admin_guild_member")
+	char2 := CreateTestCharacter(t, db, user2, "NewLeader")
+	if err := repo.AddMember(guildID, char2); err != nil {
+		t.Fatalf("AddMember failed: %v", err)
+	}
+	if _, err := db.Exec("UPDATE guild_characters SET order_index = 5 WHERE character_id = $1", char2); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := func(action string) GuildAuditEntry {
+		return GuildAuditEntry{GuildID: guildID, ActorSource: GuildAuditSourceAPI, ActorID: 1, Action: action, NewValue: action}
+	}
+	if err := repo.AdminSetRankRP(guildID, 504, entry(GuildAuditRankRP)); err != nil {
+		t.Fatalf("AdminSetRankRP failed: %v", err)
+	}
+	if err := repo.AdminRename(guildID, "Renamed", entry(GuildAuditName)); err != nil {
+		t.Fatalf("AdminRename failed: %v", err)
+	}
+	if err := repo.AdminUpdateGuildcard(guildID, 3, 4, "Card", entry(GuildAuditGuildcard)); err != nil {
+		t.Fatalf("AdminUpdateGuildcard failed: %v", err)
+	}
+	if err := repo.AdminTransferLeader(guildID, leaderID, char2, entry(GuildAuditLeader)); err != nil {
+		t.Fatalf("AdminTransferLeader failed: %v", err)
+	}
+
+	guild, err := repo.GetByID(guildID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if guild.RankRP != 504 || guild.Name != "Renamed" || guild.MainMotto != 3 || guild.SubMotto != 4 ||
+		guild.Comment != "Card" || guild.LeaderCharID != char2 {
+		t.Errorf("guild = %+v", guild)
+	}
+	newLeader, _ := repo.GetCharacterMembership(char2)
+	oldLeader, _ := repo.GetCharacterMembership(leaderID)
+	if newLeader.OrderIndex != 1 || oldLeader.OrderIndex != 5 {
+		t.Errorf("order indices: new leader %d, old leader %d", newLeader.OrderIndex, oldLeader.OrderIndex)
+	}
+
+	entries, err := repo.ListAudit(guildID, 10)
+	if err != nil {
+		t.Fatalf("ListAudit failed: %v", err)
+	}
+	if len(entries) != 4 || entries[0].Action != GuildAuditLeader || entries[3].Action != GuildAuditRankRP {
+		t.Errorf("audit entries = %+v", entries)
+	}
+	if entries, _ := repo.ListAudit(guildID, 1); len(entries) != 1 {
+		t.Errorf("limit ignored: %d entries", len(entries))
+	}
+}
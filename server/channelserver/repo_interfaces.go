@@ -51,6 +51,13 @@ type CharacterRepo interface {
 	// LoadBackupsByRecency returns all backup slots for a character ordered
 	// most-recent first. Returns an empty slice if no backups exist.
 	LoadBackupsByRecency(charID uint32) ([]SavedataBackup, error)
+	// RestoreBackupAtomic replaces the character's savedata with a backup
+	// slot, swapping the replaced save into that slot. Fails with
+	// ErrCharacterOnline while the character is logged in.
+	RestoreBackupAtomic(slot int, savedAt time.Time, params SaveAtomicParams) error
+	// ReplaceSaveDataAtomic writes an edited save if the character is offline
+	// and its savedata still equals prev.
+	ReplaceSaveDataAtomic(prev []byte, params SaveAtomicParams) error
 }
 
 // GuildRepo defines the contract for guild data access.
@@ -128,6 +135,11 @@ type GuildRepo interface {
 	AddWeeklyBonusUsers(guildID uint32, numUsers uint8) error
 	FindOrCreateReturnGuild(returnType uint8, nameTemplate string) (uint32, error)
 	AddMember(guildID, charID uint32) error
+	AdminSetRankRP(guildID, rp uint32, entry GuildAuditEntry) error
+	AdminRename(guildID uint32, name string, entry GuildAuditEntry) error
+	AdminTransferLeader(guildID, oldLeaderID, newLeaderID uint32, entry GuildAuditEntry) error
+	AdminUpdateGuildcard(guildID uint32, mainMotto, subMotto uint8, comment string, entry GuildAuditEntry) error
+	ListAudit(guildID uint32, limit int) ([]*GuildAuditEntry, error)
 }
 
 // UserRepo defines the contract for user account data access.
@@ -186,6 +198,16 @@ type GachaRepo interface {
 	GetShopType(shopID uint32) (int, error)
 	GetAllEntries(gachaID uint32) ([]GachaEntry, error)
 	GetWeightDivisor(gachaID uint32) (float64, error)
+	GetShop(id uint32) (*Gacha, error)
+	CreateShop(g Gacha) (uint32, error)
+	UpdateShop(g Gacha) (bool, error)
+	DeleteShop(id uint32) (bool, error)
+	ListCatalogueEntries(gachaID uint32) ([]GachaCatalogueEntry, error)
+	CreateEntry(gachaID uint32, e GachaCatalogueEntry) (uint32, error)
+	UpdateEntry(gachaID uint32, e GachaCatalogueEntry) (bool, error)
+	DeleteEntry(gachaID, entryID uint32) (bool, error)
+	InsertPlay(p GachaPlay) error
+	ListPlays(f GachaPlayFilter) ([]GachaPlay, error)
 }
 
 // HouseRepo defines the contract for house/housing data access.
@@ -225,6 +247,18 @@ type FestaRepo interface {
 	SubmitSouls(charID, guildID uint32, souls []uint16) error
 	ClaimPrize(prizeID uint32, charID uint32) error
 	ListPrizes(charID uint32, prizeType string) ([]Prize, error)
+	InsertEventIfNone(startTime uint32) (bool, error)
+	GetResultByEvent(eventID uint32) (*FestaResult, error)
+	RecordResult(result FestaResult) (bool, error)
+	CloseOutEvent(result FestaResult, nextStart uint32) (bool, error)
+	ListHistory(limit int) ([]FestaResult, error)
+	GetHistory(id uint32) (*FestaResult, error)
+	GetHistoryGuilds(historyID uint32) ([]FestaHistoryGuild, error)
+	CreateTrial(trial FestaTrial) (uint32, error)
+	DeleteTrial(id uint32) (bool, error)
+	ListAllPrizes() ([]FestaPrize, error)
+	CreatePrize(prize FestaPrize) (uint32, error)
+	DeletePrize(id uint32) (bool, error)
 }
 
 // TowerRepo defines the contract for tower/tenrouirai data access.
@@ -259,6 +293,47 @@ type CaravanRepo interface {
 	GetGuildRanking() ([]CaravanGuildRankEntry, error)
 }
 
+// DailyMissionRepo defines the contract for per-character daily mission progress.
+type DailyMissionRepo interface {
+	GetProgress(charID uint32, day time.Time) ([]DailyMissionProgress, error)
+}
+
+// ConquestRepo defines the contract for Conquest War schedule, beat level and
+// ranking reward data access.
+type ConquestRepo interface {
+	EnsureSchedule(earthID, status int32, start time.Time) (ConquestSchedule, error)
+	GetLevels(charID uint32, earthID int32) (map[int32]uint32, error)
+	SetLevels(charID uint32, earthID int32, levels map[int32]uint32) error
+	GetRanking(earthID, monsterID int32, limit int) ([]ConquestRankEntry, error)
+	GetCharacterPlacings(charID uint32, earthID int32) ([]ConquestRankEntry, error)
+	GetPlacings(earthID int32) ([]ConquestRankEntry, error)
+	IsRewardPaid(earthID int32) (bool, error)
+	PayRewards(earthID int32, grants []ConquestRewardGrant, eventName string) (bool, error)
+}
+
+// SeibattleRepo defines the contract for Seibattle guild battle result data
+// access.
+type SeibattleRepo interface {
+	AddResult(res SeibattleResult) error
+	GetGuildTotals(since time.Time, limit int) ([]SeibattleGuildTotal, error)
+	GetKeyScores(guildID uint32, since time.Time) ([]SeibattleKeyTotal, error)
+	GetCareer(guildID uint32) (SeibattleRecord, error)
+	GetDays(guildID uint32, since time.Time) ([]SeibattleDay, error)
+	GetCharacterScore(charID uint32, since time.Time) (uint32, error)
+}
+
+// RavienteRepo defines the contract for Raviente siege state and summary
+// data access.
+type RavienteRepo interface {
+	GetOpenSiege(serverID uint16) (*RavienteSiege, error)
+	LastRaviID(serverID uint16) (uint16, error)
+	BeginSiege(serverID, raviID uint16) (uint32, error)
+	SaveSiege(id uint32, register, state, support []uint32, multiplier float64, players int) error
+	AddParticipants(id uint32, charIDs []uint32) error
+	TakeStartRequest(id uint32) (bool, error)
+	EndSiege(id uint32, register, state, support []uint32) error
+}
+
 // MailRepo defines the contract for in-game mail data access.
 type MailRepo interface {
 	SendMail(senderID, recipientID uint32, subject, body string, itemID, itemAmount uint16, isGuildInvite, isSystemMessage bool) error
@@ -289,6 +364,13 @@ type DistributionRepo interface {
 	GetItems(distributionID uint32) ([]DistributionItem, error)
 	RecordAccepted(distributionID, charID uint32) error
 	GetDescription(distributionID uint32) (string, error)
+
+	ListCampaigns() ([]DistributionCampaign, error)
+	GetCampaign(id uint32) (*DistributionCampaign, error)
+	CreateCampaign(c DistributionCampaign) (uint32, error)
+	UpdateCampaign(c DistributionCampaign) (bool, error)
+	ExpireCampaign(id uint32, at time.Time) (bool, error)
+	Eligible(id uint32, limit int) (int, []DistributionEligibleChar, error)
 }
 
 // SessionRepo defines the contract for session/login token data access.
@@ -325,6 +407,21 @@ type ShopRepo interface {
 	RecordPurchase(charID, shopItemID, quantity uint32) error
 	GetFpointItem(tradeID uint32) (quantity, fpoints int, err error)
 	GetFpointExchangeList() ([]FPointExchange, error)
+	ListCatalogue(f ShopItemFilter) ([]ShopCatalogueItem, error)
+	GetCatalogueItem(id uint32) (*ShopCatalogueItem, error)
+	CreateCatalogueItem(item ShopCatalogueItem) (uint32, error)
+	UpdateCatalogueItem(item ShopCatalogueItem, repriced bool) (bool, error)
+	SetCatalogueItemRetired(id uint32, retired bool) (bool, error)
+	ListPriceChanges(shopItemID uint32) ([]ShopPriceChange, error)
+	CreatePriceChange(c ShopPriceChange) (uint32, error)
+	DeletePriceChange(shopItemID, changeID uint32) (bool, error)
+	ListFpointCatalogue(includeRetired bool) ([]FPointExchange, error)
+	GetFpointCatalogueItem(id uint32) (*FPointExchange, error)
+	CreateFpointItem(item FPointExchange) (uint32, error)
+	UpdateFpointItem(item FPointExchange) (bool, error)
+	SetFpointItemRetired(id uint32, retired bool) (bool, error)
+	TopShopItems(f ShopReportFilter) ([]ShopItemSales, error)
+	ShopCapHits(f ShopReportFilter) ([]ShopCapHit, error)
 }
 
 // CafeRepo defines the contract for cafe bonus data access.
@@ -378,6 +475,16 @@ type DivaRepo interface {
 	GetPersonalPrizes() ([]DivaPrize, error)
 	GetGuildPrizes() ([]DivaPrize, error)
 
+	// Point rankings
+	GetPersonalRanking(eventID uint32, limit int) ([]DivaRankEntry, error)
+	GetGuildRanking(eventID uint32, limit int) ([]DivaRankEntry, error)
+	GetCharacterRank(charID, eventID uint32) (DivaRankEntry, error)
+	GetGuildRank(guildID, eventID uint32) (DivaRankEntry, error)
+
+	// Daily and norma presents
+	GetPresents(list string) ([]DivaPresent, error)
+	ClaimPresents(charID uint32, presents []DivaPresent, period time.Time, eventName string) ([]uint32, error)
+
 	// Interception points (guild_characters.interception_points JSON)
 	GetCharacterInterceptionPoints(characterID uint32) (map[string]int, error)
 	AddInterceptionPoints(characterID uint32, questFileID int, points int) error
@@ -403,33 +510,38 @@ type MercenaryRepo interface {
 	GetGuildAirou(guildID uint32) ([][]byte, error)
 }
 
-// Tournament represents a tournament schedule entry.
+// Tournament represents a tournament schedule entry. A non-zero CycleDays
+// schedules a copy that many days after StartTime once the tournament ends.
 type Tournament struct {
-	ID         uint32 `db:"id"`
-	Name       string `db:"name"`
-	StartTime  int64  `db:"start_time"`
-	EntryEnd   int64  `db:"entry_end"`
-	RankingEnd int64  `db:"ranking_end"`
-	RewardEnd  int64  `db:"reward_end"`
+	ID         uint32     `db:"id" json:"id"`
+	Name       string     `db:"name" json:"name"`
+	StartTime  int64      `db:"start_time" json:"startTime"`
+	EntryEnd   int64      `db:"entry_end" json:"entryEnd"`
+	RankingEnd int64      `db:"ranking_end" json:"rankingEnd"`
+	RewardEnd  int64      `db:"reward_end" json:"rewardEnd"`
+	CycleDays  int        `db:"cycle_days" json:"cycleDays"`
+	CycledFrom *uint32    `db:"cycled_from" json:"cycledFrom,omitempty"`
+	PaidAt     *time.Time `db:"paid_at" json:"paidAt,omitempty"`
 }
 
 // TournamentCup represents a competition category within a tournament.
 type TournamentCup struct {
-	ID          uint32 `db:"id"`
-	CupGroup    int16  `db:"cup_group"`
-	CupType     int16  `db:"cup_type"`
-	Unk         int16  `db:"unk"`
-	Name        string `db:"name"`
-	Description string `db:"description"`
+	ID          uint32 `db:"id" json:"id"`
+	CupGroup    int16  `db:"cup_group" json:"cupGroup"`
+	CupType     int16  `db:"cup_type" json:"cupType"`
+	Unk         int16  `db:"unk" json:"unk"`
+	Name        string `db:"name" json:"name"`
+	Description string `db:"description" json:"description"`
 }
 
 // TournamentSubEvent represents a specific hunt/fish target within a cup group.
 type TournamentSubEvent struct {
-	ID           uint32 `db:"id"`
-	CupGroup     int16  `db:"cup_group"`
-	EventSubType int16  `db:"event_sub_type"`
-	QuestFileID  uint32 `db:"quest_file_id"`
-	Name         string `db:"name"`
+	ID                       uint32 `db:"id" json:"id"`
+	CupGroup                 int16  `db:"cup_group" json:"cupGroup"`
+	EventSubType             int16  `db:"event_sub_type" json:"eventSubType"`
+	QuestFileID              uint32 `db:"quest_file_id" json:"questFileId"`
+	Name                     string `db:"name" json:"name"`
+	MinSubmitIntervalSeconds int    `db:"min_submit_interval_seconds" json:"minSubmitIntervalSeconds"`
 }
 
 // TournamentRankEntry is a single entry in a leaderboard.
@@ -454,9 +566,44 @@ type TournamentEntry struct {
 type TournamentRepo interface {
 	GetActive(now int64) (*Tournament, error)
 	GetCups(tournamentID uint32) ([]TournamentCup, error)
-	GetSubEvents() ([]TournamentSubEvent, error)
+	GetSubEvents(tournamentID uint32) ([]TournamentSubEvent, error)
 	Register(charID, tournamentID uint32) (entryID uint32, err error)
 	GetEntry(charID, tournamentID uint32) (*TournamentEntry, error)
-	SubmitResult(charID, tournamentID, eventID, questSlot, stageHandle uint32) error
+	SubmitResult(result TournamentResult) error
+	LastSubmission(charID, tournamentID, eventID uint32) (*time.Time, error)
 	GetLeaderboard(eventID uint32) ([]TournamentRankEntry, error)
+
+	GetTournament(id uint32) (*Tournament, error)
+	ListTournaments() ([]Tournament, error)
+	CreateTournament(detail TournamentDetail) (uint32, error)
+	UpdateTournament(detail TournamentDetail) (bool, error)
+	DeleteTournament(id uint32) (bool, error)
+	GetOwnSubEvents(tournamentID uint32) ([]TournamentSubEvent, error)
+	GetPrizes(tournamentID uint32) ([]TournamentPrize, error)
+	GetResults(tournamentID uint32, status string) ([]TournamentResult, error)
+	SetResultStatus(tournamentID, resultID uint32, status string) (bool, error)
+	GetPlacings(tournamentID uint32) ([]TournamentPlacing, error)
+	PayPrizes(tournamentID uint32, grants []TournamentPrizeGrant, eventName string) (bool, error)
+	ListUnpaid(now int64) ([]Tournament, error)
+	ListCycleDue(now int64) ([]Tournament, error)
+	CreateNextCycle(prev Tournament, next Tournament) (bool, error)
+}
+
+// FortAttackRepo defines the contract for Interceptor's Base fort attack data access.
+type FortAttackRepo interface {
+	ScheduleEvent(event FortAttack) (bool, error)
+	ListOpen(until time.Time) ([]FortAttack, error)
+	RecordSortie(eventID, charID uint32, maxSorties int) (bool, error)
+	RecordSortieResult(eventID, charID uint32, cleared bool, damage int) (int, error)
+	GetParticipants(eventID uint32) ([]FortAttackParticipant, error)
+	EndEvent(eventID uint32, grants []FortAttackGrant, eventName string) (bool, error)
+}
+
+// MailBroadcastRepo defines the contract for admin mail broadcast data access.
+type MailBroadcastRepo interface {
+	CountRecipients(filter MailBroadcastFilter) (int, error)
+	Create(b MailBroadcast) (*MailBroadcast, bool, error)
+	Get(id uint32) (*MailBroadcast, error)
+	List(limit int) ([]MailBroadcast, error)
+	SendBatch(id uint32, mails []mailBroadcastMail, limit int) ([]uint32, bool, error)
 }
@@ -0,0 +1,273 @@
+package channelserver
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// MailBroadcastRepository centralizes all database access for the
+// mail_broadcasts table.
+type MailBroadcastRepository struct {
+	db *sqlx.DB
+}
+
+// NewMailBroadcastRepository creates a new MailBroadcastRepository.
+func NewMailBroadcastRepository(db *sqlx.DB) *MailBroadcastRepository {
+	return &MailBroadcastRepository{db: db}
+}
+
+// MailBroadcastFilter selects the characters a broadcast is sent to. Every
+// criterion that is set must match; All must be set to send to everyone.
+type MailBroadcastFilter struct {
+	All         bool       `json:"all,omitempty"`
+	MinHR       uint16     `json:"minHr,omitempty"`
+	MaxHR       uint16     `json:"maxHr,omitempty"` // 0 means no upper bound
+	MinGR       uint16     `json:"minGr,omitempty"`
+	MaxGR       uint16     `json:"maxGr,omitempty"` // 0 means no upper bound
+	LoginSince  *time.Time `json:"loginSince,omitempty"`
+	LoginBefore *time.Time `json:"loginBefore,omitempty"`
+	GuildID     uint32     `json:"guildId,omitempty"`
+	CharIDs     []uint32   `json:"charIds,omitempty"`
+}
+
+// empty reports whether no criterion is set.
+func (f MailBroadcastFilter) empty() bool {
+	return f.MinHR == 0 && f.MaxHR == 0 && f.MinGR == 0 && f.MaxGR == 0 &&
+		f.LoginSince == nil && f.LoginBefore == nil && f.GuildID == 0 && len(f.CharIDs) == 0
+}
+
+// MailBroadcastItem is one item attached to a broadcast.
+type MailBroadcastItem struct {
+	ItemID   uint16 `json:"itemId"`
+	Quantity uint16 `json:"quantity"`
+}
+
+// MailBroadcast is an admin mail sent to every character matching a filter.
+type MailBroadcast struct {
+	ID             uint32              `json:"id"`
+	IdempotencyKey string              `json:"idempotencyKey"`
+	SenderID       uint32              `json:"senderId"`
+	SenderName     string              `json:"senderName,omitempty"`
+	Subject        string              `json:"subject"`
+	Body           string              `json:"body"`
+	Filter         MailBroadcastFilter `json:"filter"`
+	Items          []MailBroadcastItem `json:"items"`
+	Total          int                 `json:"total"`      // Characters matching the filter when it was created
+	Sent           int                 `json:"sent"`       // Characters mailed so far
+	LastCharID     uint32              `json:"lastCharId"` // Batch cursor
+	CreatedBy      uint32              `json:"createdBy,omitempty"`
+	CreatedAt      time.Time           `json:"createdAt"`
+	FinishedAt     *time.Time          `json:"finishedAt,omitempty"`
+}
+
+// mailBroadcastMail is one mail a broadcast delivers to each recipient.
+type mailBroadcastMail struct {
+	Subject  string
+	ItemID   uint16
+	Quantity uint16
+}
+
+const mailBroadcastColumns = `b.id, b.idempotency_key, b.sender_id, COALESCE(c.name, ''), b.subject, b.body,
+	b.filter, b.items, b.total, b.sent, b.last_char_id, COALESCE(b.created_by, 0), b.created_at, b.finished_at`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMailBroadcast(row rowScanner) (*MailBroadcast, error) {
+	var b MailBroadcast
+	var filter, items []byte
+	if err := row.Scan(&b.ID, &b.IdempotencyKey, &b.SenderID, &b.SenderName, &b.Subject, &b.Body,
+		&filter, &items, &b.Total, &b.Sent, &b.LastCharID, &b.CreatedBy, &b.CreatedAt, &b.FinishedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filter, &b.Filter); err != nil {
+		return nil, fmt.Errorf("decode filter: %w", err)
+	}
+	if err := json.Unmarshal(items, &b.Items); err != nil {
+		return nil, fmt.Errorf("decode items: %w", err)
+	}
+	return &b, nil
+}
+
+// mailBroadcastWhere returns the SQL condition matching filter against the
+// characters table aliased as c, appending its arguments to args.
+func mailBroadcastWhere(filter MailBroadcastFilter, args []interface{}) (string, []interface{}) {
+	conds := []string{"c.deleted = false"}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.MinHR > 0 {
+		add("c.hr >= $%d", filter.MinHR)
+	}
+	if filter.MaxHR > 0 {
+		add("c.hr <= $%d", filter.MaxHR)
+	}
+	if filter.MinGR > 0 {
+		add("c.gr >= $%d", filter.MinGR)
+	}
+	if filter.MaxGR > 0 {
+		add("c.gr <= $%d", filter.MaxGR)
+	}
+	if filter.LoginSince != nil {
+		add("c.last_login >= $%d", filter.LoginSince.Unix())
+	}
+	if filter.LoginBefore != nil {
+		add("c.last_login < $%d", filter.LoginBefore.Unix())
+	}
+	if filter.GuildID > 0 {
+		add("EXISTS (SELECT 1 FROM guild_characters gc WHERE gc.character_id = c.id AND gc.guild_id = $%d)", filter.GuildID)
+	}
+	if len(filter.CharIDs) > 0 {
+		ids := make(pq.Int64Array, len(filter.CharIDs))
+		for i, id := range filter.CharIDs {
+			ids[i] = int64(id)
+		}
+		add("c.id = ANY($%d)", ids)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// CountRecipients returns the number of characters matching filter.
+func (r *MailBroadcastRepository) CountRecipients(filter MailBroadcastFilter) (int, error) {
+	where, args := mailBroadcastWhere(filter, nil)
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM characters c WHERE `+where, args...).Scan(&n)
+	return n, err
+}
+
+// Create stores a broadcast unless one with the same idempotency key exists.
+// It returns the stored broadcast and whether it was created.
+func (r *MailBroadcastRepository) Create(b MailBroadcast) (*MailBroadcast, bool, error) {
+	filter, err := json.Marshal(b.Filter)
+	if err != nil {
+		return nil, false, err
+	}
+	items, err := json.Marshal(b.Items)
+	if err != nil {
+		return nil, false, err
+	}
+	var id uint32
+	err = r.db.QueryRow(`
+		INSERT INTO mail_broadcasts (idempotency_key, sender_id, subject, body, filter, items, total, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, 0))
+		ON CONFLICT (idempotency_key) DO NOTHING RETURNING id`,
+		b.IdempotencyKey, b.SenderID, b.Subject, b.Body, filter, items, b.Total, b.CreatedBy,
+	).Scan(&id)
+	created := true
+	if errors.Is(err, sql.ErrNoRows) {
+		created = false
+	} else if err != nil {
+		return nil, false, err
+	}
+	stored, err := scanMailBroadcast(r.db.QueryRow(`SELECT `+mailBroadcastColumns+`
+		FROM mail_broadcasts b LEFT JOIN characters c ON c.id = b.sender_id
+		WHERE b.idempotency_key = $1`, b.IdempotencyKey))
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, created, nil
+}
+
+// Get returns a broadcast, or nil if it does not exist.
+func (r *MailBroadcastRepository) Get(id uint32) (*MailBroadcast, error) {
+	b, err := scanMailBroadcast(r.db.QueryRow(`SELECT `+mailBroadcastColumns+`
+		FROM mail_broadcasts b LEFT JOIN characters c ON c.id = b.sender_id
+		WHERE b.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// List returns the most recent broadcasts, newest first.
+func (r *MailBroadcastRepository) List(limit int) ([]MailBroadcast, error) {
+	rows, err := r.db.Query(`SELECT `+mailBroadcastColumns+`
+		FROM mail_broadcasts b LEFT JOIN characters c ON c.id = b.sender_id
+		ORDER BY b.id DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	var list []MailBroadcast
+	for rows.Next() {
+		b, err := scanMailBroadcast(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *b)
+	}
+	return list, rows.Err()
+}
+
+// SendBatch mails the next batch of up to limit recipients after the
+// broadcast's cursor and advances the cursor, all in one transaction. The
+// broadcast row is locked for the duration, so concurrent runners on other
+// channel servers never send the same batch twice. An empty batch marks the
+// broadcast finished. It returns the recipients mailed and whether the
+// broadcast is finished.
+func (r *MailBroadcastRepository) SendBatch(id uint32, mails []mailBroadcastMail, limit int) ([]uint32, bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	b, err := scanMailBroadcast(tx.QueryRow(`SELECT `+mailBroadcastColumns+`
+		FROM mail_broadcasts b LEFT JOIN characters c ON c.id = b.sender_id
+		WHERE b.id = $1 FOR UPDATE OF b`, id))
+	if err != nil {
+		return nil, false, err
+	}
+	if b.FinishedAt != nil {
+		return nil, true, nil
+	}
+
+	where, args := mailBroadcastWhere(b.Filter, []interface{}{b.LastCharID, limit})
+	rows, err := tx.Query(`SELECT c.id FROM characters c WHERE c.id > $1 AND `+where+` ORDER BY c.id LIMIT $2`, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	var recipients []uint32
+	for rows.Next() {
+		var cid uint32
+		if err := rows.Scan(&cid); err != nil {
+			_ = rows.Close()
+			return nil, false, err
+		}
+		recipients = append(recipients, cid)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if len(recipients) == 0 {
+		if _, err := tx.Exec(`UPDATE mail_broadcasts SET finished_at = now() WHERE id = $1`, id); err != nil {
+			return nil, false, err
+		}
+		return nil, true, tx.Commit()
+	}
+	for _, cid := range recipients {
+		for _, m := range mails {
+			if _, err := tx.Exec(mailInsertQuery, b.SenderID, cid, m.Subject, b.Body, m.ItemID, m.Quantity, false, true); err != nil {
+				return nil, false, fmt.Errorf("send mail to char %d: %w", cid, err)
+			}
+		}
+	}
+	if _, err := tx.Exec(`UPDATE mail_broadcasts SET last_char_id = $2, sent = sent + $3 WHERE id = $1`,
+		id, recipients[len(recipients)-1], len(recipients)); err != nil {
+		return nil, false, err
+	}
+	return recipients, false, tx.Commit()
+}
@@ -0,0 +1,62 @@
+package channelserver
+
+import (
+	"testing"
+)
+
+func TestRepoMailBroadcastLifecycle(t *testing.T) {
+	db := SetupTestDB(t)
+	t.Cleanup(func() { TeardownTestDB(t, db) })
+	userID := CreateTestUser(t, db, "broadcast_test_user")
+	sender := CreateTestCharacter(t, db, userID, "Sender")
+	first := CreateTestCharacter(t, db, userID, "First")
+	second := CreateTestCharacter(t, db, userID, "Second")
+	repo := NewMailBroadcastRepository(db)
+
+	filter := MailBroadcastFilter{CharIDs: []uint32{first, second}}
+	if n, err := repo.CountRecipients(filter); err != nil || n != 2 {
+		t.Fatalf("CountRecipients = %d, %v", n, err)
+	}
+	b := MailBroadcast{
+		IdempotencyKey: "repo-test",
+		SenderID:       sender,
+		Subject:        "Compensation",
+		Filter:         filter,
+		Items:          []MailBroadcastItem{{ItemID: 100, Quantity: 2}},
+		Total:          2,
+	}
+	stored, created, err := repo.Create(b)
+	if err != nil || !created {
+		t.Fatalf("Create = %v, %v", created, err)
+	}
+	if stored.SenderName != "Sender" || len(stored.Filter.CharIDs) != 2 || stored.Items[0].Quantity != 2 {
+		t.Errorf("stored = %+v", stored)
+	}
+	if again, created, err := repo.Create(b); err != nil || created || again.ID != stored.ID {
+		t.Errorf("duplicate Create = %+v, %v, %v", again, created, err)
+	}
+
+	mails := []mailBroadcastMail{{Subject: "Compensation", ItemID: 100, Quantity: 2}}
+	recipients, done, err := repo.SendBatch(stored.ID, mails, 1)
+	if err != nil || done || len(recipients) != 1 || recipients[0] != first {
+		t.Fatalf("first batch = %v, %v, %v", recipients, done, err)
+	}
+	if recipients, done, err = repo.SendBatch(stored.ID, mails, 1); err != nil || len(recipients) != 1 || recipients[0] != second {
+		t.Fatalf("second batch = %v, %v, %v", recipients, done, err)
+	}
+	if recipients, done, err = repo.SendBatch(stored.ID, mails, 1); err != nil || !done || len(recipients) != 0 {
+		t.Fatalf("final batch = %v, %v, %v", recipients, done, err)
+	}
+
+	got, err := repo.Get(stored.ID)
+	if err != nil || got.Sent != 2 || got.LastCharID != second || got.FinishedAt == nil {
+		t.Errorf("Get = %+v, %v", got, err)
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM mail WHERE sender_id = $1 AND attached_item = 100`, sender).Scan(&count); err != nil || count != 2 {
+		t.Errorf("mails = %d, %v, want 2", count, err)
+	}
+	if list, err := repo.List(10); err != nil || len(list) != 1 {
+		t.Errorf("List = %+v, %v", list, err)
+	}
+}
@@ -141,6 +141,15 @@ type mockCharacterRepo struct {
 	etcDailyQuests uint32
 	etcPromoPoints uint32
 	etcPointsErr   error
+
+	// Backup history mock fields
+	backups       []SavedataBackup
+	restoreErr    error
+	restoreSlot   int
+	restoreParams *SaveAtomicParams
+	replaceErr    error
+	replacePrev   []byte
+	replaceParams *SaveAtomicParams
 }
 
 func newMockCharacterRepo() *mockCharacterRepo {
@@ -249,8 +258,27 @@ func (m *mockCharacterRepo) LoadSaveDataWithHash(_ uint32) (uint32, []byte, bool
 	return m.loadSaveDataID, m.loadSaveDataData, m.loadSaveDataNew, m.loadSaveDataName, m.loadSaveDataHash, m.loadSaveDataErr
 }
 func (m *mockCharacterRepo) LoadBackupsByRecency(_ uint32) ([]SavedataBackup, error) {
+	if m.backups != nil {
+		return m.backups, nil
+	}
 	return []SavedataBackup{}, nil
 }
+func (m *mockCharacterRepo) ReplaceSaveDataAtomic(prev []byte, params SaveAtomicParams) error {
+	if m.replaceErr != nil {
+		return m.replaceErr
+	}
+	m.replacePrev = prev
+	m.replaceParams = &params
+	return nil
+}
+func (m *mockCharacterRepo) RestoreBackupAtomic(slot int, _ time.Time, params SaveAtomicParams) error {
+	if m.restoreErr != nil {
+		return m.restoreErr
+	}
+	m.restoreSlot = slot
+	m.restoreParams = &params
+	return nil
+}
 
 // --- mockGoocooRepo ---
 
@@ -377,6 +405,11 @@ type mockGuildRepo struct {
 	membership  *GuildMember
 	application *GuildApplication
 	posts       []*MessageBoardPost
+
+	// Admin
+	adminErr     error
+	auditEntries []GuildAuditEntry
+	transferArgs []uint32
 }
 
 func (m *mockGuildRepo) GetByID(guildID uint32) (*Guild, error) {
@@ -619,6 +652,44 @@ func (m *mockGuildRepo) FindOrCreateReturnGuild(_ uint8, _ string) (uint32, erro
 }
 func (m *mockGuildRepo) AddMember(_, _ uint32) error { return nil }
 
+func (m *mockGuildRepo) adminUpdate(entry GuildAuditEntry, update func()) error {
+	if m.adminErr != nil {
+		return m.adminErr
+	}
+	update()
+	m.auditEntries = append(m.auditEntries, entry)
+	return nil
+}
+
+func (m *mockGuildRepo) AdminSetRankRP(_, rp uint32, entry GuildAuditEntry) error {
+	return m.adminUpdate(entry, func() { m.guild.RankRP = rp })
+}
+
+func (m *mockGuildRepo) AdminRename(_ uint32, name string, entry GuildAuditEntry) error {
+	return m.adminUpdate(entry, func() { m.guild.Name = name })
+}
+
+func (m *mockGuildRepo) AdminTransferLeader(_, oldLeaderID, newLeaderID uint32, entry GuildAuditEntry) error {
+	return m.adminUpdate(entry, func() {
+		m.transferArgs = []uint32{oldLeaderID, newLeaderID}
+		m.guild.LeaderCharID = newLeaderID
+	})
+}
+
+func (m *mockGuildRepo) AdminUpdateGuildcard(_ uint32, mainMotto, subMotto uint8, comment string, entry GuildAuditEntry) error {
+	return m.adminUpdate(entry, func() {
+		m.guild.MainMotto, m.guild.SubMotto, m.guild.Comment = mainMotto, subMotto, comment
+	})
+}
+
+func (m *mockGuildRepo) ListAudit(_ uint32, _ int) ([]*GuildAuditEntry, error) {
+	entries := make([]*GuildAuditEntry, 0, len(m.auditEntries))
+	for i := len(m.auditEntries) - 1; i >= 0; i-- {
+		entries = append(entries, &m.auditEntries[i])
+	}
+	return entries, nil
+}
+
 // --- mockUserRepoForItems ---
 
 type mockUserRepoForItems struct {
@@ -856,6 +927,15 @@ type mockGachaRepo struct {
 	allEntries    []GachaEntry
 	allEntriesErr error
 	weightDivisor float64
+
+	// Catalogue
+	shop      *Gacha
+	catalogue []GachaCatalogueEntry
+	deleted   bool
+
+	// Play history
+	plays         []GachaPlay
+	insertPlayErr error
 }
 
 func (m *mockGachaRepo) GetEntryForTransaction(_ uint32, _ uint8) (uint8, uint16, int, error) {
@@ -910,6 +990,85 @@ func (m *mockGachaRepo) GetAllEntries(_ uint32) ([]GachaEntry, error) {
 	return m.allEntries, m.allEntriesErr
 }
 func (m *mockGachaRepo) GetWeightDivisor(_ uint32) (float64, error) { return m.weightDivisor, nil }
+func (m *mockGachaRepo) GetShop(id uint32) (*Gacha, error) {
+	if m.shop == nil || m.shop.ID != id {
+		return nil, nil
+	}
+	g := *m.shop
+	return &g, nil
+}
+func (m *mockGachaRepo) CreateShop(g Gacha) (uint32, error) {
+	g.ID = 1
+	m.shop = &g
+	return g.ID, nil
+}
+func (m *mockGachaRepo) UpdateShop(g Gacha) (bool, error) {
+	if m.shop == nil || m.shop.ID != g.ID {
+		return false, nil
+	}
+	m.shop = &g
+	return true, nil
+}
+func (m *mockGachaRepo) DeleteShop(id uint32) (bool, error) {
+	if m.shop == nil || m.shop.ID != id {
+		return false, nil
+	}
+	m.shop, m.catalogue, m.deleted = nil, nil, true
+	return true, nil
+}
+func (m *mockGachaRepo) ListCatalogueEntries(_ uint32) ([]GachaCatalogueEntry, error) {
+	return append([]GachaCatalogueEntry(nil), m.catalogue...), nil
+}
+func (m *mockGachaRepo) CreateEntry(_ uint32, e GachaCatalogueEntry) (uint32, error) {
+	e.ID = 1
+	for _, c := range m.catalogue {
+		e.ID = max(e.ID, c.ID+1)
+	}
+	m.catalogue = append(m.catalogue, e)
+	return e.ID, nil
+}
+func (m *mockGachaRepo) UpdateEntry(_ uint32, e GachaCatalogueEntry) (bool, error) {
+	for i := range m.catalogue {
+		if m.catalogue[i].ID == e.ID {
+			m.catalogue[i] = e
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockGachaRepo) DeleteEntry(_ uint32, entryID uint32) (bool, error) {
+	for i := range m.catalogue {
+		if m.catalogue[i].ID == entryID {
+			m.catalogue = append(m.catalogue[:i], m.catalogue[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockGachaRepo) InsertPlay(p GachaPlay) error {
+	if m.insertPlayErr != nil {
+		return m.insertPlayErr
+	}
+	p.ID = uint64(len(m.plays) + 1)
+	p.PlayedAt = time.Now()
+	m.plays = append(m.plays, p)
+	return nil
+}
+
+func (m *mockGachaRepo) ListPlays(f GachaPlayFilter) ([]GachaPlay, error) {
+	var out []GachaPlay
+	for i := len(m.plays) - 1; i >= 0 && len(out) < f.Limit; i-- {
+		p := m.plays[i]
+		if (f.UserID > 0 && p.UserID != f.UserID) || (f.CharID > 0 && p.CharID != f.CharID) ||
+			(f.GachaID > 0 && p.GachaID != f.GachaID) || (f.BeforeID > 0 && p.ID >= f.BeforeID) ||
+			(f.Since != nil && p.PlayedAt.Before(*f.Since)) || (f.Until != nil && !p.PlayedAt.Before(*f.Until)) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
 
 // --- mockShopRepo ---
 
@@ -922,6 +1081,17 @@ type mockShopRepo struct {
 	fpointValue     int
 	fpointItemErr   error
 	fpointExchanges []FPointExchange
+
+	// Catalogue
+	catalogue       []ShopCatalogueItem
+	priceChanges    []ShopPriceChange
+	fpointCatalogue []FPointExchange
+	repriced        bool
+
+	// Reports
+	sales        []ShopItemSales
+	capHits      []ShopCapHit
+	reportFilter ShopReportFilter
 }
 
 type shopPurchaseRecord struct {
@@ -941,6 +1111,135 @@ func (m *mockShopRepo) GetFpointItem(_ uint32) (int, int, error) {
 func (m *mockShopRepo) GetFpointExchangeList() ([]FPointExchange, error) {
 	return m.fpointExchanges, nil
 }
+func (m *mockShopRepo) ListCatalogue(f ShopItemFilter) ([]ShopCatalogueItem, error) {
+	var out []ShopCatalogueItem
+	for _, item := range m.catalogue {
+		if (f.ShopType > 0 && item.ShopType != f.ShopType) || (f.ShopID != nil && item.ShopID != *f.ShopID) ||
+			(!f.IncludeRetired && item.RetiredAt != nil) {
+			continue
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+func (m *mockShopRepo) GetCatalogueItem(id uint32) (*ShopCatalogueItem, error) {
+	for _, item := range m.catalogue {
+		if item.ID == id {
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockShopRepo) CreateCatalogueItem(item ShopCatalogueItem) (uint32, error) {
+	item.ID = 1
+	for _, c := range m.catalogue {
+		item.ID = max(item.ID, c.ID+1)
+	}
+	m.catalogue = append(m.catalogue, item)
+	return item.ID, nil
+}
+func (m *mockShopRepo) UpdateCatalogueItem(item ShopCatalogueItem, repriced bool) (bool, error) {
+	for i := range m.catalogue {
+		if m.catalogue[i].ID == item.ID {
+			if !repriced {
+				item.Cost = m.catalogue[i].Cost
+			}
+			item.RetiredAt = m.catalogue[i].RetiredAt
+			m.catalogue[i], m.repriced = item, repriced
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockShopRepo) SetCatalogueItemRetired(id uint32, retired bool) (bool, error) {
+	for i := range m.catalogue {
+		if m.catalogue[i].ID == id {
+			m.catalogue[i].RetiredAt = nil
+			if retired {
+				at := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+				m.catalogue[i].RetiredAt = &at
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockShopRepo) ListPriceChanges(shopItemID uint32) ([]ShopPriceChange, error) {
+	var out []ShopPriceChange
+	for _, c := range m.priceChanges {
+		if c.ShopItemID == shopItemID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+func (m *mockShopRepo) CreatePriceChange(c ShopPriceChange) (uint32, error) {
+	c.ID = uint32(len(m.priceChanges) + 1)
+	m.priceChanges = append(m.priceChanges, c)
+	return c.ID, nil
+}
+func (m *mockShopRepo) DeletePriceChange(shopItemID, changeID uint32) (bool, error) {
+	for i, c := range m.priceChanges {
+		if c.ID == changeID && c.ShopItemID == shopItemID {
+			m.priceChanges = append(m.priceChanges[:i], m.priceChanges[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockShopRepo) ListFpointCatalogue(includeRetired bool) ([]FPointExchange, error) {
+	var out []FPointExchange
+	for _, item := range m.fpointCatalogue {
+		if includeRetired || item.RetiredAt == nil {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+func (m *mockShopRepo) GetFpointCatalogueItem(id uint32) (*FPointExchange, error) {
+	for _, item := range m.fpointCatalogue {
+		if item.ID == id {
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockShopRepo) CreateFpointItem(item FPointExchange) (uint32, error) {
+	item.ID = uint32(len(m.fpointCatalogue) + 1)
+	m.fpointCatalogue = append(m.fpointCatalogue, item)
+	return item.ID, nil
+}
+func (m *mockShopRepo) UpdateFpointItem(item FPointExchange) (bool, error) {
+	for i := range m.fpointCatalogue {
+		if m.fpointCatalogue[i].ID == item.ID {
+			item.RetiredAt = m.fpointCatalogue[i].RetiredAt
+			m.fpointCatalogue[i] = item
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockShopRepo) SetFpointItemRetired(id uint32, retired bool) (bool, error) {
+	for i := range m.fpointCatalogue {
+		if m.fpointCatalogue[i].ID == id {
+			m.fpointCatalogue[i].RetiredAt = nil
+			if retired {
+				at := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+				m.fpointCatalogue[i].RetiredAt = &at
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockShopRepo) TopShopItems(f ShopReportFilter) ([]ShopItemSales, error) {
+	m.reportFilter = f
+	return m.sales, nil
+}
+func (m *mockShopRepo) ShopCapHits(f ShopReportFilter) ([]ShopCapHit, error) {
+	m.reportFilter = f
+	return m.capHits, nil
+}
 
 // --- mockUserRepoGacha (UserRepo with configurable gacha fields) ---
 
@@ -1075,6 +1374,106 @@ func (m *mockCaravanRepo) GetGuildRanking() ([]CaravanGuildRankEntry, error) {
 	return m.guildRank, m.guildRankErr
 }
 
+// --- mockDailyMissionRepo ---
+
+type mockDailyMissionRepo struct {
+	progress    []DailyMissionProgress
+	progressErr error
+}
+
+func (m *mockDailyMissionRepo) GetProgress(_ uint32, _ time.Time) ([]DailyMissionProgress, error) {
+	return m.progress, m.progressErr
+}
+
+// --- mockSeibattleRepo ---
+
+type mockSeibattleRepo struct {
+	totals    []SeibattleGuildTotal
+	keyScores []SeibattleKeyTotal
+	career    SeibattleRecord
+	days      []SeibattleDay
+	charScore uint32
+
+	added []SeibattleResult
+}
+
+func (m *mockSeibattleRepo) AddResult(res SeibattleResult) error {
+	m.added = append(m.added, res)
+	return nil
+}
+func (m *mockSeibattleRepo) GetGuildTotals(_ time.Time, _ int) ([]SeibattleGuildTotal, error) {
+	return m.totals, nil
+}
+func (m *mockSeibattleRepo) GetKeyScores(_ uint32, _ time.Time) ([]SeibattleKeyTotal, error) {
+	return m.keyScores, nil
+}
+func (m *mockSeibattleRepo) GetCareer(_ uint32) (SeibattleRecord, error) { return m.career, nil }
+func (m *mockSeibattleRepo) GetDays(_ uint32, _ time.Time) ([]SeibattleDay, error) {
+	return m.days, nil
+}
+func (m *mockSeibattleRepo) GetCharacterScore(_ uint32, _ time.Time) (uint32, error) {
+	return m.charScore, nil
+}
+
+// --- mockConquestRepo ---
+
+type mockConquestRepo struct {
+	schedule    ConquestSchedule
+	scheduleErr error
+	levels      map[int32]uint32
+	setErr      error
+	ranking     []ConquestRankEntry
+	placings    []ConquestRankEntry
+	rewardPaid  bool
+	payErr      error
+
+	seeded        bool
+	setEarthID    int32
+	setLevels     map[int32]uint32
+	rankingEarth  int32
+	placingsEarth int32
+	paidEarth     int32
+	grants        []ConquestRewardGrant
+	payCalls      int
+}
+
+func (m *mockConquestRepo) EnsureSchedule(earthID, status int32, start time.Time) (ConquestSchedule, error) {
+	if !m.seeded && m.schedule.StartTime.IsZero() {
+		m.schedule = ConquestSchedule{EarthID: earthID, Status: status, StartTime: start}
+	}
+	m.seeded = true
+	return m.schedule, m.scheduleErr
+}
+func (m *mockConquestRepo) GetLevels(_ uint32, _ int32) (map[int32]uint32, error) {
+	return m.levels, nil
+}
+func (m *mockConquestRepo) SetLevels(_ uint32, earthID int32, levels map[int32]uint32) error {
+	m.setEarthID, m.setLevels = earthID, levels
+	return m.setErr
+}
+func (m *mockConquestRepo) GetRanking(earthID, _ int32, _ int) ([]ConquestRankEntry, error) {
+	m.rankingEarth = earthID
+	return m.ranking, nil
+}
+func (m *mockConquestRepo) GetCharacterPlacings(_ uint32, earthID int32) ([]ConquestRankEntry, error) {
+	m.placingsEarth = earthID
+	return m.placings, nil
+}
+func (m *mockConquestRepo) GetPlacings(_ int32) ([]ConquestRankEntry, error) {
+	return m.placings, nil
+}
+func (m *mockConquestRepo) IsRewardPaid(_ int32) (bool, error) {
+	return m.rewardPaid, nil
+}
+func (m *mockConquestRepo) PayRewards(earthID int32, grants []ConquestRewardGrant, _ string) (bool, error) {
+	m.payCalls++
+	if m.payErr != nil {
+		return false, m.payErr
+	}
+	m.paidEarth, m.grants, m.rewardPaid = earthID, grants, true
+	return true, nil
+}
+
 // --- mockFestaRepo ---
 
 type mockFestaRepo struct {
@@ -1100,6 +1499,18 @@ type mockFestaRepo struct {
 	insertedStart  uint32
 	submitErr      error
 	submittedSouls []uint16
+
+	result         *FestaResult
+	recorded       *FestaResult
+	closedOut      *FestaResult
+	closeOutOK     bool
+	nextStart      uint32
+	insertedIfNone uint32
+	history        []FestaResult
+	historyGuilds  []FestaHistoryGuild
+	createdTrial   *FestaTrial
+	deleteOK       bool
+	createdPrize   *FestaPrize
 }
 
 func (m *mockFestaRepo) CleanupAll() error {
@@ -1133,6 +1544,47 @@ func (m *mockFestaRepo) ClaimPrize(_ uint32, _ uint32) error { return nil }
 func (m *mockFestaRepo) ListPrizes(_ uint32, _ string) ([]Prize, error) {
 	return m.prizes, m.prizesErr
 }
+func (m *mockFestaRepo) InsertEventIfNone(start uint32) (bool, error) {
+	m.insertedIfNone = start
+	return true, m.insertErr
+}
+func (m *mockFestaRepo) GetResultByEvent(_ uint32) (*FestaResult, error) { return m.result, nil }
+func (m *mockFestaRepo) RecordResult(result FestaResult) (bool, error) {
+	m.recorded = &result
+	return true, nil
+}
+func (m *mockFestaRepo) CloseOutEvent(result FestaResult, nextStart uint32) (bool, error) {
+	m.closedOut, m.nextStart = &result, nextStart
+	return m.closeOutOK, nil
+}
+func (m *mockFestaRepo) ListHistory(limit int) ([]FestaResult, error) {
+	if len(m.history) > limit {
+		return m.history[:limit], nil
+	}
+	return m.history, nil
+}
+func (m *mockFestaRepo) GetHistory(id uint32) (*FestaResult, error) {
+	for i := range m.history {
+		if m.history[i].ID == id {
+			return &m.history[i], nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockFestaRepo) GetHistoryGuilds(_ uint32) ([]FestaHistoryGuild, error) {
+	return m.historyGuilds, nil
+}
+func (m *mockFestaRepo) CreateTrial(trial FestaTrial) (uint32, error) {
+	m.createdTrial = &trial
+	return 12, nil
+}
+func (m *mockFestaRepo) DeleteTrial(_ uint32) (bool, error)   { return m.deleteOK, nil }
+func (m *mockFestaRepo) ListAllPrizes() ([]FestaPrize, error) { return nil, m.prizesErr }
+func (m *mockFestaRepo) CreatePrize(prize FestaPrize) (uint32, error) {
+	m.createdPrize = &prize
+	return 7, nil
+}
+func (m *mockFestaRepo) DeletePrize(_ uint32) (bool, error) { return m.deleteOK, nil }
 
 // --- mockRengokuRepo ---
 
@@ -1157,6 +1609,15 @@ type mockDivaRepo struct {
 	addErr   error
 	getErr   error
 	totalErr error
+
+	personalRanking []DivaRankEntry
+	guildRanking    []DivaRankEntry
+	charRank        DivaRankEntry
+	guildRank       DivaRankEntry
+	presents        map[string][]DivaPresent
+	claimed         map[uint32]bool // present IDs already claimed
+	delivered       []DivaPresent   // presents whose items were delivered
+	claimPeriod     time.Time
 }
 
 func (m *mockDivaRepo) DeleteEvents() error             { return nil }
@@ -1218,6 +1679,30 @@ func (m *mockDivaRepo) GetCharacterInterceptionPoints(_ uint32) (map[string]int,
 	return map[string]int{}, nil
 }
 func (m *mockDivaRepo) AddInterceptionPoints(_ uint32, _ int, _ int) error { return nil }
+func (m *mockDivaRepo) GetPersonalRanking(_ uint32, _ int) ([]DivaRankEntry, error) {
+	return m.personalRanking, nil
+}
+func (m *mockDivaRepo) GetGuildRanking(_ uint32, _ int) ([]DivaRankEntry, error) {
+	return m.guildRanking, nil
+}
+func (m *mockDivaRepo) GetCharacterRank(_, _ uint32) (DivaRankEntry, error) { return m.charRank, nil }
+func (m *mockDivaRepo) GetGuildRank(_, _ uint32) (DivaRankEntry, error)     { return m.guildRank, nil }
+func (m *mockDivaRepo) GetPresents(list string) ([]DivaPresent, error)      { return m.presents[list], nil }
+func (m *mockDivaRepo) ClaimPresents(_ uint32, presents []DivaPresent, period time.Time, _ string) ([]uint32, error) {
+	if m.claimed == nil {
+		m.claimed = make(map[uint32]bool)
+	}
+	var claimed []uint32
+	for _, present := range presents {
+		if !m.claimed[present.ID] {
+			m.claimed[present.ID] = true
+			m.delivered = append(m.delivered, present)
+			claimed = append(claimed, present.ID)
+		}
+	}
+	m.claimPeriod = period
+	return claimed, nil
+}
 
 // --- mockEventRepo ---
 
@@ -1228,6 +1713,7 @@ type mockEventRepo struct {
 	loginBoostErr error
 	eventQuests   []EventQuest
 	eventQuestErr error
+	questUpdates  []EventQuestUpdate
 }
 
 func (m *mockEventRepo) GetFeatureWeapon(_ time.Time) (activeFeature, error) {
@@ -1240,9 +1726,12 @@ func (m *mockEventRepo) GetLoginBoosts(_ uint32) ([]loginBoost, error) {
 func (m *mockEventRepo) InsertLoginBoost(_ uint32, _ uint8, _, _ time.Time) error { return nil }
 func (m *mockEventRepo) UpdateLoginBoost(_ uint32, _ uint8, _, _ time.Time) error { return nil }
 func (m *mockEventRepo) GetEventQuests() ([]EventQuest, error) {
-	return m.eventQuests, m.eventQuestErr
+	return append([]EventQuest(nil), m.eventQuests...), m.eventQuestErr
+}
+func (m *mockEventRepo) UpdateEventQuestStartTimes(updates []EventQuestUpdate) error {
+	m.questUpdates = append(m.questUpdates, updates...)
+	return nil
 }
-func (m *mockEventRepo) UpdateEventQuestStartTimes(_ []EventQuestUpdate) error { return nil }
 
 // --- mockMiscRepo ---
 
@@ -1317,13 +1806,25 @@ type mockTournamentRepo struct {
 	registerErr error
 	entry       *TournamentEntry
 	entryErr    error
+
+	tournaments    map[uint32]*Tournament
+	prizes         []TournamentPrize
+	results        []TournamentResult
+	lastSubmission *time.Time
+	placings       []TournamentPlacing
+	created        []TournamentDetail
+	updated        []TournamentDetail
+	paid           map[uint32][]TournamentPrizeGrant
+	cycled         []Tournament
+	unpaid         []Tournament
+	cycleDue       []Tournament
 }
 
 func (m *mockTournamentRepo) GetActive(_ int64) (*Tournament, error) {
 	return m.active, m.activeErr
 }
 func (m *mockTournamentRepo) GetCups(_ uint32) ([]TournamentCup, error) { return m.cups, nil }
-func (m *mockTournamentRepo) GetSubEvents() ([]TournamentSubEvent, error) {
+func (m *mockTournamentRepo) GetSubEvents(_ uint32) ([]TournamentSubEvent, error) {
 	return m.subEvents, nil
 }
 func (m *mockTournamentRepo) Register(_, _ uint32) (uint32, error) {
@@ -1332,7 +1833,306 @@ func (m *mockTournamentRepo) Register(_, _ uint32) (uint32, error) {
 func (m *mockTournamentRepo) GetEntry(_, _ uint32) (*TournamentEntry, error) {
 	return m.entry, m.entryErr
 }
-func (m *mockTournamentRepo) SubmitResult(_, _, _, _, _ uint32) error { return nil }
+func (m *mockTournamentRepo) SubmitResult(result TournamentResult) error {
+	m.results = append(m.results, result)
+	return nil
+}
+func (m *mockTournamentRepo) LastSubmission(_, _, _ uint32) (*time.Time, error) {
+	return m.lastSubmission, nil
+}
 func (m *mockTournamentRepo) GetLeaderboard(_ uint32) ([]TournamentRankEntry, error) {
 	return m.ranks, nil
 }
+func (m *mockTournamentRepo) GetTournament(id uint32) (*Tournament, error) {
+	return m.tournaments[id], nil
+}
+func (m *mockTournamentRepo) ListTournaments() ([]Tournament, error) {
+	var list []Tournament
+	for _, t := range m.tournaments {
+		list = append(list, *t)
+	}
+	return list, nil
+}
+func (m *mockTournamentRepo) CreateTournament(detail TournamentDetail) (uint32, error) {
+	if m.tournaments == nil {
+		m.tournaments = make(map[uint32]*Tournament)
+	}
+	detail.ID = uint32(len(m.tournaments) + 1)
+	t := detail.Tournament
+	m.tournaments[detail.ID] = &t
+	m.created = append(m.created, detail)
+	return detail.ID, nil
+}
+func (m *mockTournamentRepo) UpdateTournament(detail TournamentDetail) (bool, error) {
+	if m.tournaments[detail.ID] == nil {
+		return false, nil
+	}
+	t := detail.Tournament
+	m.tournaments[detail.ID] = &t
+	m.updated = append(m.updated, detail)
+	return true, nil
+}
+func (m *mockTournamentRepo) DeleteTournament(id uint32) (bool, error) {
+	if m.tournaments[id] == nil {
+		return false, nil
+	}
+	delete(m.tournaments, id)
+	return true, nil
+}
+func (m *mockTournamentRepo) GetOwnSubEvents(_ uint32) ([]TournamentSubEvent, error) {
+	return m.subEvents, nil
+}
+func (m *mockTournamentRepo) GetPrizes(_ uint32) ([]TournamentPrize, error) { return m.prizes, nil }
+func (m *mockTournamentRepo) GetResults(_ uint32, status string) ([]TournamentResult, error) {
+	var results []TournamentResult
+	for _, r := range m.results {
+		if status == "" || r.Status == status {
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+func (m *mockTournamentRepo) SetResultStatus(_, resultID uint32, status string) (bool, error) {
+	for i := range m.results {
+		if m.results[i].ID == resultID {
+			m.results[i].Status = status
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (m *mockTournamentRepo) GetPlacings(_ uint32) ([]TournamentPlacing, error) {
+	return m.placings, nil
+}
+func (m *mockTournamentRepo) PayPrizes(id uint32, grants []TournamentPrizeGrant, _ string) (bool, error) {
+	if m.paid == nil {
+		m.paid = make(map[uint32][]TournamentPrizeGrant)
+	}
+	if _, ok := m.paid[id]; ok {
+		return false, nil
+	}
+	m.paid[id] = grants
+	return true, nil
+}
+func (m *mockTournamentRepo) ListUnpaid(_ int64) ([]Tournament, error) { return m.unpaid, nil }
+func (m *mockTournamentRepo) ListCycleDue(_ int64) ([]Tournament, error) {
+	return m.cycleDue, nil
+}
+func (m *mockTournamentRepo) CreateNextCycle(_ Tournament, next Tournament) (bool, error) {
+	m.cycled = append(m.cycled, next)
+	return true, nil
+}
+
+// --- mockRavienteRepo ---
+
+type mockRavienteRepo struct {
+	open         *RavienteSiege
+	lastRaviID   uint16
+	nextID       uint32
+	began        []uint16
+	participants []uint32
+	startPending bool
+	saved        int
+	savedPlayers int
+	ended        []uint32
+	endedState   []uint32
+	onEnd        func()
+	err          error
+}
+
+func (m *mockRavienteRepo) GetOpenSiege(uint16) (*RavienteSiege, error) { return m.open, m.err }
+func (m *mockRavienteRepo) LastRaviID(uint16) (uint16, error)           { return m.lastRaviID, m.err }
+func (m *mockRavienteRepo) BeginSiege(_, raviID uint16) (uint32, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.nextID++
+	m.began = append(m.began, raviID)
+	return m.nextID, nil
+}
+func (m *mockRavienteRepo) SaveSiege(_ uint32, _, _, _ []uint32, _ float64, players int) error {
+	m.saved++
+	m.savedPlayers = players
+	return m.err
+}
+func (m *mockRavienteRepo) AddParticipants(_ uint32, charIDs []uint32) error {
+	m.participants = append(m.participants, charIDs...)
+	return m.err
+}
+func (m *mockRavienteRepo) TakeStartRequest(uint32) (bool, error) {
+	pending := m.startPending
+	m.startPending = false
+	return pending, m.err
+}
+func (m *mockRavienteRepo) EndSiege(id uint32, _, state, _ []uint32) error {
+	if m.onEnd != nil {
+		m.onEnd()
+	}
+	m.ended = append(m.ended, id)
+	m.endedState = append([]uint32(nil), state...)
+	return m.err
+}
+
+// --- mockFortAttackRepo ---
+
+type mockFortAttackRepo struct {
+	events    []FortAttack
+	sorties   map[uint32]int // charID -> sorties on any event
+	cleared   []uint32
+	damaged   []int
+	remaining int
+	ended     map[uint32][]FortAttackGrant
+	err       error
+}
+
+func (m *mockFortAttackRepo) ScheduleEvent(event FortAttack) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	for _, e := range m.events {
+		if e.ScheduleIndex == event.ScheduleIndex && e.StartsAt.Equal(event.StartsAt) {
+			return false, nil
+		}
+	}
+	event.ID = uint32(len(m.events) + 1)
+	event.Durability = event.MaxDurability
+	m.events = append(m.events, event)
+	return true, nil
+}
+
+func (m *mockFortAttackRepo) ListOpen(until time.Time) ([]FortAttack, error) {
+	var open []FortAttack
+	for _, e := range m.events {
+		if e.EndedAt == nil && e.StartsAt.Before(until) {
+			open = append(open, e)
+		}
+	}
+	return open, m.err
+}
+
+func (m *mockFortAttackRepo) RecordSortie(_, charID uint32, maxSorties int) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if m.sorties == nil {
+		m.sorties = make(map[uint32]int)
+	}
+	if maxSorties > 0 && m.sorties[charID] >= maxSorties {
+		return false, nil
+	}
+	m.sorties[charID]++
+	return true, nil
+}
+
+func (m *mockFortAttackRepo) RecordSortieResult(_, charID uint32, cleared bool, damage int) (int, error) {
+	if cleared {
+		m.cleared = append(m.cleared, charID)
+	} else {
+		m.damaged = append(m.damaged, damage)
+	}
+	return m.remaining, m.err
+}
+
+func (m *mockFortAttackRepo) GetParticipants(_ uint32) ([]FortAttackParticipant, error) {
+	var participants []FortAttackParticipant
+	for charID, n := range m.sorties {
+		participants = append(participants, FortAttackParticipant{CharID: charID, Sorties: n})
+	}
+	return participants, m.err
+}
+
+func (m *mockFortAttackRepo) EndEvent(eventID uint32, grants []FortAttackGrant, _ string) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if m.ended == nil {
+		m.ended = make(map[uint32][]FortAttackGrant)
+	}
+	if _, ok := m.ended[eventID]; ok {
+		return false, nil
+	}
+	m.ended[eventID] = grants
+	for i := range m.events {
+		if m.events[i].ID == eventID {
+			now := time.Now()
+			m.events[i].EndedAt = &now
+		}
+	}
+	return true, nil
+}
+
+// --- mockMailBroadcastRepo ---
+
+// mockMailBroadcastRepo sends to recipients in order, ignoring the filter.
+type mockMailBroadcastRepo struct {
+	broadcasts []MailBroadcast
+	recipients []uint32
+	mailed     map[uint32][]mailBroadcastMail
+	batches    int
+	failAfter  int // Fail SendBatch once this many batches have been sent, if > 0
+}
+
+func (m *mockMailBroadcastRepo) CountRecipients(filter MailBroadcastFilter) (int, error) {
+	return len(m.recipients), nil
+}
+
+func (m *mockMailBroadcastRepo) Create(b MailBroadcast) (*MailBroadcast, bool, error) {
+	for i := range m.broadcasts {
+		if m.broadcasts[i].IdempotencyKey == b.IdempotencyKey {
+			stored := m.broadcasts[i]
+			return &stored, false, nil
+		}
+	}
+	b.ID = uint32(len(m.broadcasts) + 1)
+	m.broadcasts = append(m.broadcasts, b)
+	return &b, true, nil
+}
+
+func (m *mockMailBroadcastRepo) Get(id uint32) (*MailBroadcast, error) {
+	for i := range m.broadcasts {
+		if m.broadcasts[i].ID == id {
+			b := m.broadcasts[i]
+			return &b, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockMailBroadcastRepo) List(limit int) ([]MailBroadcast, error) {
+	var list []MailBroadcast
+	for i := len(m.broadcasts) - 1; i >= 0 && len(list) < limit; i-- {
+		list = append(list, m.broadcasts[i])
+	}
+	return list, nil
+}
+
+func (m *mockMailBroadcastRepo) SendBatch(id uint32, mails []mailBroadcastMail, limit int) ([]uint32, bool, error) {
+	if m.failAfter > 0 && m.batches >= m.failAfter {
+		return nil, false, errNotFound
+	}
+	b := &m.broadcasts[id-1]
+	if b.FinishedAt != nil {
+		return nil, true, nil
+	}
+	var batch []uint32
+	for _, cid := range m.recipients {
+		if cid > b.LastCharID && len(batch) < limit {
+			batch = append(batch, cid)
+		}
+	}
+	if len(batch) == 0 {
+		now := time.Now()
+		b.FinishedAt = &now
+		return nil, true, nil
+	}
+	if m.mailed == nil {
+		m.mailed = make(map[uint32][]mailBroadcastMail)
+	}
+	for _, cid := range batch {
+		m.mailed[cid] = append(m.mailed[cid], mails...)
+	}
+	b.LastCharID = batch[len(batch)-1]
+	b.Sent += len(batch)
+	m.batches++
+	return batch, false, nil
+}
@@ -0,0 +1,294 @@
+package channelserver
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"erupe-ce/common/byteframe"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// RavienteRepository centralizes all database access for the
+// raviente_sieges and raviente_participants tables.
+type RavienteRepository struct {
+	db *sqlx.DB
+}
+
+// NewRavienteRepository creates a new RavienteRepository.
+func NewRavienteRepository(db *sqlx.DB) *RavienteRepository {
+	return &RavienteRepository{db: db}
+}
+
+// raviPhases is the number of leading state register slots that accumulate
+// the damage dealt in each phase of the siege.
+const raviPhases = 5
+
+// RavienteSiege is a saved Raviente siege. Summary is nil until it ends.
+type RavienteSiege struct {
+	ID         uint32
+	ServerID   uint16
+	RaviID     uint16
+	Register   []uint32
+	State      []uint32
+	Support    []uint32
+	Multiplier float64
+	Players    int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	EndedAt    *time.Time
+	Summary    *RavienteSummary
+}
+
+// RavienteSummary is the outcome of a finished siege.
+type RavienteSummary struct {
+	PhaseReached int
+	Damage       uint64
+	Participants int
+}
+
+// RavienteParticipant is a character that joined a siege.
+type RavienteParticipant struct {
+	CharID   uint32    `db:"character_id"`
+	Name     string    `db:"name"`
+	JoinedAt time.Time `db:"joined_at"`
+}
+
+// Phase returns the furthest phase (1-5) with damage recorded, or 0 if the
+// siege has not been damaged yet.
+func (r RavienteSiege) Phase() int {
+	return raviPhase(r.State)
+}
+
+// Damage returns the total damage dealt across all phases.
+func (r RavienteSiege) Damage() uint64 {
+	return raviDamage(r.State)
+}
+
+// Started reports whether the siege has been started; register slot 1 holds
+// the start value once it has.
+func (r RavienteSiege) Started() bool {
+	return len(r.Register) > 1 && r.Register[1] != 0
+}
+
+func raviPhase(state []uint32) int {
+	for i := min(raviPhases, len(state)) - 1; i >= 0; i-- {
+		if state[i] != 0 {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func raviDamage(state []uint32) uint64 {
+	var total uint64
+	for i := 0; i < min(raviPhases, len(state)); i++ {
+		total += uint64(state[i])
+	}
+	return total
+}
+
+// encodeRaviRegister stores register values as big-endian uint32s.
+func encodeRaviRegister(values []uint32) []byte {
+	bf := byteframe.NewByteFrame()
+	for _, v := range values {
+		bf.WriteUint32(v)
+	}
+	return bf.Data()
+}
+
+// decodeRaviRegister reads a stored register into a slice of n values,
+// zero-filling any values the blob does not cover.
+func decodeRaviRegister(data []byte, n int) []uint32 {
+	values := make([]uint32, n)
+	bf := byteframe.NewByteFrameFromBytes(data)
+	for i := 0; i < n && i < len(data)/4; i++ {
+		values[i] = bf.ReadUint32()
+	}
+	return values
+}
+
+type raviSiegeRow struct {
+	ID           uint32        `db:"id"`
+	ServerID     uint16        `db:"server_id"`
+	RaviID       uint16        `db:"ravi_id"`
+	Register     []byte        `db:"register"`
+	State        []byte        `db:"state"`
+	Support      []byte        `db:"support"`
+	Multiplier   float64       `db:"multiplier"`
+	Players      int           `db:"players"`
+	CreatedAt    time.Time     `db:"created_at"`
+	UpdatedAt    time.Time     `db:"updated_at"`
+	EndedAt      sql.NullTime  `db:"ended_at"`
+	PhaseReached sql.NullInt64 `db:"phase_reached"`
+	Damage       sql.NullInt64 `db:"damage"`
+	Participants sql.NullInt64 `db:"participants"`
+}
+
+const raviSiegeColumns = `id, server_id, ravi_id, register, state, support, multiplier, players,
+	created_at, updated_at, ended_at, phase_reached, damage, participants`
+
+func (row raviSiegeRow) siege() RavienteSiege {
+	siege := RavienteSiege{
+		ID:         row.ID,
+		ServerID:   row.ServerID,
+		RaviID:     row.RaviID,
+		Register:   decodeRaviRegister(row.Register, raviRegisterSize),
+		State:      decodeRaviRegister(row.State, raviRegisterSize),
+		Support:    decodeRaviRegister(row.Support, raviRegisterSize),
+		Multiplier: row.Multiplier,
+		Players:    row.Players,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+	}
+	if row.EndedAt.Valid {
+		ended := row.EndedAt.Time
+		siege.EndedAt = &ended
+		siege.Summary = &RavienteSummary{
+			PhaseReached: int(row.PhaseReached.Int64),
+			Damage:       uint64(row.Damage.Int64),
+			Participants: int(row.Participants.Int64),
+		}
+	}
+	return siege
+}
+
+func (r *RavienteRepository) selectSieges(query string, args ...interface{}) ([]RavienteSiege, error) {
+	var rows []raviSiegeRow
+	if err := r.db.Select(&rows, `SELECT `+raviSiegeColumns+` FROM raviente_sieges `+query, args...); err != nil {
+		return nil, err
+	}
+	sieges := make([]RavienteSiege, len(rows))
+	for i, row := range rows {
+		sieges[i] = row.siege()
+	}
+	return sieges, nil
+}
+
+// GetSiege returns a siege by ID, or nil if it does not exist.
+func (r *RavienteRepository) GetSiege(id uint32) (*RavienteSiege, error) {
+	sieges, err := r.selectSieges(`WHERE id = $1`, id)
+	if err != nil || len(sieges) == 0 {
+		return nil, err
+	}
+	return &sieges[0], nil
+}
+
+// GetOpenSiege returns the siege a channel has not ended yet, or nil.
+func (r *RavienteRepository) GetOpenSiege(serverID uint16) (*RavienteSiege, error) {
+	sieges, err := r.selectSieges(`WHERE server_id = $1 AND ended_at IS NULL`, serverID)
+	if err != nil || len(sieges) == 0 {
+		return nil, err
+	}
+	return &sieges[0], nil
+}
+
+// ListOpenSieges returns every channel's open siege.
+func (r *RavienteRepository) ListOpenSieges() ([]RavienteSiege, error) {
+	return r.selectSieges(`WHERE ended_at IS NULL ORDER BY server_id`)
+}
+
+// ListEndedSieges returns the most recently ended sieges, newest first.
+func (r *RavienteRepository) ListEndedSieges(limit int) ([]RavienteSiege, error) {
+	return r.selectSieges(`WHERE ended_at IS NOT NULL ORDER BY ended_at DESC, id DESC LIMIT $1`, limit)
+}
+
+// LastRaviID returns the client-facing ID of a channel's latest siege, or 0.
+func (r *RavienteRepository) LastRaviID(serverID uint16) (uint16, error) {
+	var id uint16
+	err := r.db.QueryRow(`
+		SELECT COALESCE((SELECT ravi_id FROM raviente_sieges WHERE server_id = $1 ORDER BY id DESC LIMIT 1), 0)
+	`, serverID).Scan(&id)
+	return id, err
+}
+
+// BeginSiege records a new open siege for a channel and returns its ID.
+func (r *RavienteRepository) BeginSiege(serverID, raviID uint16) (uint32, error) {
+	var id uint32
+	err := r.db.QueryRow(`
+		INSERT INTO raviente_sieges (server_id, ravi_id, register, state, support)
+		VALUES ($1, $2, $3, $3, $3) RETURNING id
+	`, serverID, raviID, encodeRaviRegister(make([]uint32, raviRegisterSize))).Scan(&id)
+	return id, err
+}
+
+// SaveSiege stores the current registers and load of an open siege.
+func (r *RavienteRepository) SaveSiege(id uint32, register, state, support []uint32, multiplier float64, players int) error {
+	_, err := r.db.Exec(`
+		UPDATE raviente_sieges
+		SET register = $2, state = $3, support = $4, multiplier = $5, players = $6, updated_at = now()
+		WHERE id = $1 AND ended_at IS NULL
+	`, id, encodeRaviRegister(register), encodeRaviRegister(state), encodeRaviRegister(support), multiplier, players)
+	return err
+}
+
+// AddParticipants records characters as having joined a siege.
+func (r *RavienteRepository) AddParticipants(id uint32, charIDs []uint32) error {
+	if len(charIDs) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(charIDs))
+	for i, c := range charIDs {
+		ids[i] = int64(c)
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO raviente_participants (siege_id, character_id)
+		SELECT $1, c FROM unnest($2::int[]) AS c
+		WHERE EXISTS (SELECT 1 FROM characters WHERE id = c)
+		ON CONFLICT DO NOTHING
+	`, id, pq.Array(ids))
+	return err
+}
+
+// GetParticipants returns the characters that joined a siege, in join order.
+func (r *RavienteRepository) GetParticipants(id uint32) ([]RavienteParticipant, error) {
+	var participants []RavienteParticipant
+	err := r.db.Select(&participants, `
+		SELECT p.character_id, c.name, p.joined_at
+		FROM raviente_participants p
+		JOIN characters c ON c.id = p.character_id
+		WHERE p.siege_id = $1
+		ORDER BY p.joined_at, p.character_id
+	`, id)
+	return participants, err
+}
+
+// RequestStart asks the channel running an open siege to start it at its
+// next sync. It reports false if no open siege has that ID.
+func (r *RavienteRepository) RequestStart(id uint32) (bool, error) {
+	res, err := r.db.Exec(`UPDATE raviente_sieges SET start_requested = true WHERE id = $1 AND ended_at IS NULL`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// TakeStartRequest clears and returns a siege's pending start request.
+func (r *RavienteRepository) TakeStartRequest(id uint32) (bool, error) {
+	var requested bool
+	err := r.db.QueryRow(`
+		UPDATE raviente_sieges SET start_requested = false
+		WHERE id = $1 AND start_requested RETURNING true
+	`, id).Scan(&requested)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return requested, err
+}
+
+// EndSiege saves a siege's final registers and writes its summary. The
+// participant count is taken from raviente_participants.
+func (r *RavienteRepository) EndSiege(id uint32, register, state, support []uint32) error {
+	_, err := r.db.Exec(`
+		UPDATE raviente_sieges
+		SET register = $2, state = $3, support = $4, players = 0, start_requested = false,
+			updated_at = now(), ended_at = now(), phase_reached = $5, damage = $6,
+			participants = (SELECT COUNT(*) FROM raviente_participants WHERE siege_id = $1)
+		WHERE id = $1 AND ended_at IS NULL
+	`, id, encodeRaviRegister(register), encodeRaviRegister(state), encodeRaviRegister(support),
+		raviPhase(state), int64(raviDamage(state)))
+	return err
+}
@@ -0,0 +1,87 @@
+package channelserver
+
+import (
+	"testing"
+)
+
+func setupRavienteRepo(t *testing.T) (*RavienteRepository, uint32, uint32) {
+	t.Helper()
+	db := SetupTestDB(t)
+	userID := CreateTestUser(t, db, "ravi_test_user")
+	first := CreateTestCharacter(t, db, userID, "RaviOne")
+	second := CreateTestCharacter(t, db, userID, "RaviTwo")
+	repo := NewRavienteRepository(db)
+	t.Cleanup(func() { TeardownTestDB(t, db) })
+	return repo, first, second
+}
+
+func TestRepoRavienteSiegeLifecycle(t *testing.T) {
+	repo, first, second := setupRavienteRepo(t)
+
+	if open, err := repo.GetOpenSiege(0x1010); err != nil || open != nil {
+		t.Fatalf("GetOpenSiege before any siege = %v, %v", open, err)
+	}
+	id, err := repo.BeginSiege(0x1010, 4)
+	if err != nil {
+		t.Fatalf("BeginSiege failed: %v", err)
+	}
+	if _, err := repo.BeginSiege(0x1010, 5); err == nil {
+		t.Error("second open siege on one channel was allowed")
+	}
+	if err := repo.AddParticipants(id, []uint32{first, second, first, 999999}); err != nil {
+		t.Fatalf("AddParticipants failed: %v", err)
+	}
+
+	state := make([]uint32, raviRegisterSize)
+	state[0], state[1] = 5000, 1200
+	register := make([]uint32, raviRegisterSize)
+	register[1] = 3
+	if err := repo.SaveSiege(id, register, state, make([]uint32, raviRegisterSize), 2, 2); err != nil {
+		t.Fatalf("SaveSiege failed: %v", err)
+	}
+	open, err := repo.GetOpenSiege(0x1010)
+	if err != nil || open == nil {
+		t.Fatalf("GetOpenSiege = %v, %v", open, err)
+	}
+	if open.ID != id || open.RaviID != 4 || open.State[0] != 5000 || open.Register[1] != 3 || open.Players != 2 || open.Multiplier != 2 {
+		t.Errorf("open siege = %+v", open)
+	}
+
+	if ok, _ := repo.TakeStartRequest(id); ok {
+		t.Error("start request pending before one was made")
+	}
+	if ok, err := repo.RequestStart(id); err != nil || !ok {
+		t.Fatalf("RequestStart = %v, %v", ok, err)
+	}
+	if ok, _ := repo.TakeStartRequest(id); !ok {
+		t.Error("start request not returned")
+	}
+	if ok, _ := repo.TakeStartRequest(id); ok {
+		t.Error("start request returned twice")
+	}
+
+	if err := repo.EndSiege(id, register, state, make([]uint32, raviRegisterSize)); err != nil {
+		t.Fatalf("EndSiege failed: %v", err)
+	}
+	if ok, _ := repo.RequestStart(id); ok {
+		t.Error("start request accepted for an ended siege")
+	}
+	ended, err := repo.ListEndedSieges(10)
+	if err != nil || len(ended) != 1 {
+		t.Fatalf("ListEndedSieges = %v, %v", ended, err)
+	}
+	want := RavienteSummary{PhaseReached: 2, Damage: 6200, Participants: 2}
+	if ended[0].Summary == nil || *ended[0].Summary != want || ended[0].EndedAt == nil {
+		t.Errorf("summary = %+v, want %+v", ended[0].Summary, want)
+	}
+	participants, err := repo.GetParticipants(id)
+	if err != nil || len(participants) != 2 {
+		t.Errorf("GetParticipants = %v, %v", participants, err)
+	}
+	if last, err := repo.LastRaviID(0x1010); err != nil || last != 4 {
+		t.Errorf("LastRaviID = %d, %v, want 4", last, err)
+	}
+	if open, _ := repo.ListOpenSieges(); len(open) != 0 {
+		t.Errorf("open sieges after end = %d", len(open))
+	}
+}
@@ -0,0 +1,142 @@
+package channelserver
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SeibattleRepository centralizes all database access for the
+// seibattle_results table.
+type SeibattleRepository struct {
+	db *sqlx.DB
+}
+
+// NewSeibattleRepository creates a new SeibattleRepository.
+func NewSeibattleRepository(db *sqlx.DB) *SeibattleRepository {
+	return &SeibattleRepository{db: db}
+}
+
+// SeibattleResult is one result posted by MSG_MHF_POST_SEIBATTLE. Key and
+// Score are the packet's Unk0 and Unk2; the other fields are stored raw.
+type SeibattleResult struct {
+	GuildID uint32
+	CharID  uint32
+	Key     uint8
+	Score   uint32
+	Unk1    uint8
+	Unk3    uint8
+	Unk4    uint16
+	Unk5    uint16
+	Unk6    uint8
+}
+
+// SeibattleGuildTotal is a guild's summed Seibattle results over a window.
+type SeibattleGuildTotal struct {
+	GuildID uint32 `db:"guild_id"`
+	Place   uint32 `db:"place"`
+	Score   uint32 `db:"score"`
+	Battles uint32 `db:"battles"`
+	Members uint32 `db:"members"`
+}
+
+// SeibattleKeyTotal is a guild's summed score for one result key.
+type SeibattleKeyTotal struct {
+	Key   uint8  `db:"key"`
+	Score uint32 `db:"score"`
+}
+
+// SeibattleRecord is a guild's all-time Seibattle record.
+type SeibattleRecord struct {
+	Battles uint32 `db:"battles"`
+	Days    uint32 `db:"days"`
+	Members uint32 `db:"members"`
+}
+
+// SeibattleDay is a guild's placing on one day of a window.
+type SeibattleDay struct {
+	Day int `db:"day"`
+	SeibattleGuildTotal
+}
+
+// AddResult stores a posted Seibattle result.
+func (r *SeibattleRepository) AddResult(res SeibattleResult) error {
+	_, err := r.db.Exec(`
+		INSERT INTO seibattle_results (guild_id, character_id, key, score, unk1, unk3, unk4, unk5, unk6)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, res.GuildID, res.CharID, res.Key, res.Score, res.Unk1, res.Unk3, res.Unk4, res.Unk5, res.Unk6)
+	return err
+}
+
+// GetGuildTotals ranks every guild by its score since the given time, best
+// first.
+func (r *SeibattleRepository) GetGuildTotals(since time.Time, limit int) ([]SeibattleGuildTotal, error) {
+	var totals []SeibattleGuildTotal
+	err := r.db.Select(&totals, `
+		SELECT guild_id, score, battles, members,
+			RANK() OVER (ORDER BY score DESC) AS place
+		FROM (
+			SELECT guild_id, SUM(score) AS score, COUNT(*) AS battles,
+				COUNT(DISTINCT character_id) AS members
+			FROM seibattle_results
+			WHERE posted_at >= $1
+			GROUP BY guild_id
+		) t
+		ORDER BY score DESC, guild_id
+		LIMIT $2`, since, limit)
+	return totals, err
+}
+
+// GetKeyScores returns a guild's score per result key since the given time.
+func (r *SeibattleRepository) GetKeyScores(guildID uint32, since time.Time) ([]SeibattleKeyTotal, error) {
+	var scores []SeibattleKeyTotal
+	err := r.db.Select(&scores, `
+		SELECT key, SUM(score) AS score
+		FROM seibattle_results
+		WHERE guild_id = $1 AND posted_at >= $2
+		GROUP BY key
+		ORDER BY key`, guildID, since)
+	return scores, err
+}
+
+// GetCareer returns a guild's all-time Seibattle record.
+func (r *SeibattleRepository) GetCareer(guildID uint32) (SeibattleRecord, error) {
+	var career SeibattleRecord
+	err := r.db.Get(&career, `
+		SELECT COUNT(*) AS battles,
+			COUNT(DISTINCT date_trunc('day', posted_at)) AS days,
+			COUNT(DISTINCT character_id) AS members
+		FROM seibattle_results
+		WHERE guild_id = $1`, guildID)
+	return career, err
+}
+
+// GetDays returns a guild's placing on each day since the given time that it
+// posted a result. Day 0 starts at since.
+func (r *SeibattleRepository) GetDays(guildID uint32, since time.Time) ([]SeibattleDay, error) {
+	var days []SeibattleDay
+	err := r.db.Select(&days, `
+		WITH daily AS (
+			SELECT guild_id, FLOOR(EXTRACT(EPOCH FROM posted_at - $2::timestamptz) / 86400)::int AS day,
+				SUM(score) AS score, COUNT(*) AS battles, COUNT(DISTINCT character_id) AS members
+			FROM seibattle_results
+			WHERE posted_at >= $2
+			GROUP BY 1, 2
+		)
+		SELECT day, guild_id, score, battles, members, place
+		FROM (
+			SELECT *, RANK() OVER (PARTITION BY day ORDER BY score DESC) AS place FROM daily
+		) ranked
+		WHERE guild_id = $1
+		ORDER BY day`, guildID, since)
+	return days, err
+}
+
+// GetCharacterScore returns a character's summed score since the given time.
+func (r *SeibattleRepository) GetCharacterScore(charID uint32, since time.Time) (uint32, error) {
+	var score uint32
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(score), 0) FROM seibattle_results
+		WHERE character_id = $1 AND posted_at >= $2`, charID, since).Scan(&score)
+	return score, err
+}
@@ -0,0 +1,59 @@
+package channelserver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRepoSeibattleResults(t *testing.T) {
+	db := SetupTestDB(t)
+	t.Cleanup(func() { TeardownTestDB(t, db) })
+	repo := NewSeibattleRepository(db)
+
+	userID := CreateTestUser(t, db, "seibattle_user")
+	first := CreateTestCharacter(t, db, userID, "SeiOne")
+	second := CreateTestCharacter(t, db, userID, "SeiTwo")
+	guildA := CreateTestGuild(t, db, first, "SeiGuildA")
+	guildB := CreateTestGuild(t, db, second, "SeiGuildB")
+
+	for _, res := range []SeibattleResult{
+		{GuildID: guildA, CharID: first, Key: 1, Score: 300},
+		{GuildID: guildA, CharID: first, Key: 2, Score: 200},
+		{GuildID: guildB, CharID: second, Key: 1, Score: 400},
+	} {
+		if err := repo.AddResult(res); err != nil {
+			t.Fatalf("AddResult: %v", err)
+		}
+	}
+	since := time.Now().Add(-time.Hour)
+
+	totals, err := repo.GetGuildTotals(since, 10)
+	if err != nil {
+		t.Fatalf("GetGuildTotals: %v", err)
+	}
+	if len(totals) != 2 || totals[0].GuildID != guildA || totals[0].Score != 500 || totals[0].Place != 1 || totals[0].Battles != 2 {
+		t.Errorf("totals = %+v, want guild A first with 500 over 2 battles", totals)
+	}
+
+	keys, err := repo.GetKeyScores(guildA, since)
+	if err != nil || len(keys) != 2 || keys[0].Key != 1 || keys[0].Score != 300 {
+		t.Errorf("GetKeyScores = %+v, %v", keys, err)
+	}
+
+	career, err := repo.GetCareer(guildA)
+	if err != nil || career.Battles != 2 || career.Days != 1 || career.Members != 1 {
+		t.Errorf("GetCareer = %+v, %v", career, err)
+	}
+
+	days, err := repo.GetDays(guildB, since)
+	if err != nil || len(days) != 1 || days[0].Day != 0 || days[0].Place != 2 {
+		t.Errorf("GetDays = %+v, %v; want place 2 on day 0", days, err)
+	}
+
+	if score, err := repo.GetCharacterScore(first, since); err != nil || score != 500 {
+		t.Errorf("GetCharacterScore = %d, %v; want 500", score, err)
+	}
+	if score, err := repo.GetCharacterScore(first, time.Now().Add(time.Hour)); err != nil || score != 0 {
+		t.Errorf("GetCharacterScore after the window = %d, %v; want 0", score, err)
+	}
+}
@@ -1,6 +1,13 @@
 package channelserver
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 )
 
@@ -14,12 +21,19 @@ func NewShopRepository(db *sqlx.DB) *ShopRepository {
 	return &ShopRepository{db: db}
 }
 
-// GetShopItems returns shop items with per-character purchase counts.
+// shopItemCost is the price a shop_items row (aliased si) sells at: the
+// latest scheduled price change that has taken effect, or its own cost.
+const shopItemCost = `COALESCE((SELECT pc.cost FROM shop_price_changes pc
+	WHERE pc.shop_item_id = si.id AND pc.effective_at <= now()
+	ORDER BY pc.effective_at DESC, pc.id DESC LIMIT 1), si.cost)`
+
+// GetShopItems returns the items on sale in a shop with per-character
+// purchase counts. Retired items are left out.
 func (r *ShopRepository) GetShopItems(shopType uint8, shopID uint32, charID uint32) ([]ShopItem, error) {
 	var result []ShopItem
-	err := r.db.Select(&result, `SELECT id, item_id, cost, quantity, min_hr, min_sr, min_gr, store_level, max_quantity,
+	err := r.db.Select(&result, `SELECT id, item_id, `+shopItemCost+` AS cost, quantity, min_hr, min_sr, min_gr, store_level, max_quantity,
        		COALESCE((SELECT bought FROM shop_items_bought WHERE shop_item_id=si.id AND character_id=$3), 0) as used_quantity,
-       		road_floors, road_fatalis FROM shop_items si WHERE shop_type=$1 AND shop_id=$2
+       		road_floors, road_fatalis FROM shop_items si WHERE shop_type=$1 AND shop_id=$2 AND retired_at IS NULL
        		`, shopType, shopID, charID)
 	return result, err
 }
@@ -33,15 +47,368 @@ func (r *ShopRepository) RecordPurchase(charID, shopItemID, quantity uint32) err
 	return err
 }
 
-// GetFpointItem returns the quantity and fpoints cost for a frontier point item.
+// GetFpointItem returns the quantity and fpoints cost for a frontier point
+// item. Retired items are treated as missing.
 func (r *ShopRepository) GetFpointItem(tradeID uint32) (quantity, fpoints int, err error) {
-	err = r.db.QueryRow("SELECT quantity, fpoints FROM fpoint_items WHERE id=$1", tradeID).Scan(&quantity, &fpoints)
+	err = r.db.QueryRow("SELECT quantity, fpoints FROM fpoint_items WHERE id=$1 AND retired_at IS NULL", tradeID).Scan(&quantity, &fpoints)
 	return
 }
 
-// GetFpointExchangeList returns all frontier point exchange items ordered by buyable status.
+// GetFpointExchangeList returns all frontier point exchange items that are
+// not retired, ordered by buyable status.
 func (r *ShopRepository) GetFpointExchangeList() ([]FPointExchange, error) {
 	var result []FPointExchange
-	err := r.db.Select(&result, `SELECT id, item_type, item_id, quantity, fpoints, buyable FROM fpoint_items ORDER BY buyable DESC`)
+	err := r.db.Select(&result, `SELECT id, item_type, item_id, quantity, fpoints, buyable FROM fpoint_items
+		WHERE retired_at IS NULL ORDER BY buyable DESC`)
 	return result, err
 }
+
+// Catalogue methods
+
+// ShopCatalogueItem is a shop_items row as managed through the admin API.
+// Cost is the current price, including any scheduled change that has taken
+// effect.
+type ShopCatalogueItem struct {
+	ID           uint32            `db:"id" json:"id"`
+	ShopType     uint8             `db:"shop_type" json:"shopType"`
+	ShopID       uint32            `db:"shop_id" json:"shopId"`
+	ItemID       uint16            `db:"item_id" json:"itemId"`
+	Cost         uint32            `db:"cost" json:"cost"`
+	Quantity     uint16            `db:"quantity" json:"quantity"`
+	MinHR        uint16            `db:"min_hr" json:"minHr"`
+	MinSR        uint16            `db:"min_sr" json:"minSr"`
+	MinGR        uint16            `db:"min_gr" json:"minGr"`
+	StoreLevel   uint16            `db:"store_level" json:"storeLevel"`
+	MaxQuantity  uint16            `db:"max_quantity" json:"maxQuantity"` // Per-character purchase cap, 0 for none
+	RoadFloors   uint16            `db:"road_floors" json:"roadFloors"`
+	RoadFatalis  uint16            `db:"road_fatalis" json:"roadFatalis"`
+	RetiredAt    *time.Time        `db:"retired_at" json:"retiredAt,omitempty"`
+	PriceChanges []ShopPriceChange `db:"-" json:"priceChanges,omitempty"`
+}
+
+// ShopPriceChange sets a shop item's cost from EffectiveAt onwards.
+type ShopPriceChange struct {
+	ID          uint32    `db:"id" json:"id"`
+	ShopItemID  uint32    `db:"shop_item_id" json:"shopItemId"`
+	Cost        uint32    `db:"cost" json:"cost"`
+	EffectiveAt time.Time `db:"effective_at" json:"effectiveAt"`
+}
+
+// ShopItemFilter selects catalogue items. A nil ShopID matches every shop of
+// the type, and a zero ShopType every type.
+type ShopItemFilter struct {
+	ShopType       uint8
+	ShopID         *uint32
+	IncludeRetired bool
+}
+
+// shopCatalogueColumns selects a shop_items row (aliased si) into a
+// ShopCatalogueItem.
+const shopCatalogueColumns = `si.id, COALESCE(si.shop_type, 0) AS shop_type, COALESCE(si.shop_id, 0) AS shop_id,
+	COALESCE(si.item_id, 0) AS item_id, COALESCE(` + shopItemCost + `, 0) AS cost,
+	COALESCE(si.quantity, 0) AS quantity, COALESCE(si.min_hr, 0) AS min_hr, COALESCE(si.min_sr, 0) AS min_sr,
+	COALESCE(si.min_gr, 0) AS min_gr, COALESCE(si.store_level, 0) AS store_level,
+	COALESCE(si.max_quantity, 0) AS max_quantity, COALESCE(si.road_floors, 0) AS road_floors,
+	COALESCE(si.road_fatalis, 0) AS road_fatalis, si.retired_at`
+
+// ListCatalogue returns the shop items matching f, by shop and ID.
+func (r *ShopRepository) ListCatalogue(f ShopItemFilter) ([]ShopCatalogueItem, error) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.ShopType > 0 {
+		add("si.shop_type = $%d", f.ShopType)
+	}
+	if f.ShopID != nil {
+		add("si.shop_id = $%d", *f.ShopID)
+	}
+	if !f.IncludeRetired {
+		conds = append(conds, "si.retired_at IS NULL")
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+	var items []ShopCatalogueItem
+	err := r.db.Select(&items, `SELECT `+shopCatalogueColumns+` FROM shop_items si `+where+`
+		ORDER BY si.shop_type, si.shop_id, si.id`, args...)
+	return items, err
+}
+
+// GetCatalogueItem returns a shop item, or nil if it does not exist.
+func (r *ShopRepository) GetCatalogueItem(id uint32) (*ShopCatalogueItem, error) {
+	var item ShopCatalogueItem
+	err := r.db.QueryRowx(`SELECT `+shopCatalogueColumns+` FROM shop_items si WHERE si.id = $1`, id).StructScan(&item)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CreateCatalogueItem inserts a shop item and returns its ID.
+func (r *ShopRepository) CreateCatalogueItem(item ShopCatalogueItem) (uint32, error) {
+	var id uint32
+	err := r.db.QueryRow(
+		`INSERT INTO shop_items (shop_type, shop_id, item_id, cost, quantity, min_hr, min_sr, min_gr, store_level,
+		max_quantity, road_floors, road_fatalis)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`,
+		item.ShopType, item.ShopID, item.ItemID, item.Cost, item.Quantity, item.MinHR, item.MinSR, item.MinGR,
+		item.StoreLevel, item.MaxQuantity, item.RoadFloors, item.RoadFatalis,
+	).Scan(&id)
+	return id, err
+}
+
+// UpdateCatalogueItem replaces a shop item's listing. When repriced is set
+// its cost is replaced too, along with any scheduled price change that has
+// already taken effect; pending changes are kept. It reports false if the
+// item does not exist.
+func (r *ShopRepository) UpdateCatalogueItem(item ShopCatalogueItem, repriced bool) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(
+		`UPDATE shop_items SET shop_type = $2, shop_id = $3, item_id = $4, quantity = $5, min_hr = $6, min_sr = $7,
+		min_gr = $8, store_level = $9, max_quantity = $10, road_floors = $11, road_fatalis = $12
+		WHERE id = $1`,
+		item.ID, item.ShopType, item.ShopID, item.ItemID, item.Quantity, item.MinHR, item.MinSR,
+		item.MinGR, item.StoreLevel, item.MaxQuantity, item.RoadFloors, item.RoadFatalis,
+	)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if repriced {
+		if _, err := tx.Exec(`UPDATE shop_items SET cost = $2 WHERE id = $1`, item.ID, item.Cost); err != nil {
+			return false, err
+		}
+		if _, err := tx.Exec(
+			`DELETE FROM shop_price_changes WHERE shop_item_id = $1 AND effective_at <= now()`, item.ID,
+		); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit()
+}
+
+// SetCatalogueItemRetired retires a shop item, hiding it from the in-game
+// shop, or puts it back on sale. It reports false if the item does not exist.
+func (r *ShopRepository) SetCatalogueItemRetired(id uint32, retired bool) (bool, error) {
+	res, err := r.db.Exec(
+		`UPDATE shop_items SET retired_at = CASE WHEN $2 THEN COALESCE(retired_at, now()) END WHERE id = $1`,
+		id, retired,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ListPriceChanges returns a shop item's scheduled price changes, earliest
+// first, including those that have taken effect.
+func (r *ShopRepository) ListPriceChanges(shopItemID uint32) ([]ShopPriceChange, error) {
+	var changes []ShopPriceChange
+	err := r.db.Select(&changes,
+		`SELECT id, shop_item_id, cost, effective_at FROM shop_price_changes
+		WHERE shop_item_id = $1 ORDER BY effective_at, id`,
+		shopItemID,
+	)
+	return changes, err
+}
+
+// CreatePriceChange schedules a shop item price change and returns its ID.
+func (r *ShopRepository) CreatePriceChange(c ShopPriceChange) (uint32, error) {
+	var id uint32
+	err := r.db.QueryRow(
+		`INSERT INTO shop_price_changes (shop_item_id, cost, effective_at) VALUES ($1, $2, $3) RETURNING id`,
+		c.ShopItemID, c.Cost, c.EffectiveAt,
+	).Scan(&id)
+	return id, err
+}
+
+// DeletePriceChange removes a scheduled price change from a shop item. It
+// reports false if the change does not exist on the item.
+func (r *ShopRepository) DeletePriceChange(shopItemID, changeID uint32) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM shop_price_changes WHERE id = $1 AND shop_item_id = $2`, changeID, shopItemID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// fpointCatalogueColumns selects an fpoint_items row into an FPointExchange.
+const fpointCatalogueColumns = `id, item_type, item_id, quantity, fpoints, buyable, retired_at`
+
+// ListFpointCatalogue returns the Frontier Point exchange items by ID.
+func (r *ShopRepository) ListFpointCatalogue(includeRetired bool) ([]FPointExchange, error) {
+	where := "WHERE retired_at IS NULL"
+	if includeRetired {
+		where = ""
+	}
+	var items []FPointExchange
+	err := r.db.Select(&items, `SELECT `+fpointCatalogueColumns+` FROM fpoint_items `+where+` ORDER BY id`)
+	return items, err
+}
+
+// GetFpointCatalogueItem returns a Frontier Point exchange item, or nil if it
+// does not exist.
+func (r *ShopRepository) GetFpointCatalogueItem(id uint32) (*FPointExchange, error) {
+	var item FPointExchange
+	err := r.db.QueryRowx(`SELECT `+fpointCatalogueColumns+` FROM fpoint_items WHERE id = $1`, id).StructScan(&item)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CreateFpointItem inserts a Frontier Point exchange item and returns its ID.
+func (r *ShopRepository) CreateFpointItem(item FPointExchange) (uint32, error) {
+	var id uint32
+	err := r.db.QueryRow(
+		`INSERT INTO fpoint_items (item_type, item_id, quantity, fpoints, buyable) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		item.ItemType, item.ItemID, item.Quantity, item.FPoints, item.Buyable,
+	).Scan(&id)
+	return id, err
+}
+
+// UpdateFpointItem replaces a Frontier Point exchange item. It reports false
+// if the item does not exist.
+func (r *ShopRepository) UpdateFpointItem(item FPointExchange) (bool, error) {
+	res, err := r.db.Exec(
+		`UPDATE fpoint_items SET item_type = $2, item_id = $3, quantity = $4, fpoints = $5, buyable = $6 WHERE id = $1`,
+		item.ID, item.ItemType, item.ItemID, item.Quantity, item.FPoints, item.Buyable,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// SetFpointItemRetired retires a Frontier Point exchange item or puts it
+// back in the exchange. It reports false if the item does not exist.
+func (r *ShopRepository) SetFpointItemRetired(id uint32, retired bool) (bool, error) {
+	res, err := r.db.Exec(
+		`UPDATE fpoint_items SET retired_at = CASE WHEN $2 THEN COALESCE(retired_at, now()) END WHERE id = $1`,
+		id, retired,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Purchase report methods
+
+// ShopItemSales totals the purchases recorded against a shop item.
+type ShopItemSales struct {
+	ShopItemID   uint32 `db:"shop_item_id" json:"shopItemId"`
+	ShopType     uint8  `db:"shop_type" json:"shopType"`
+	ShopID       uint32 `db:"shop_id" json:"shopId"`
+	ItemID       uint16 `db:"item_id" json:"itemId"`
+	Cost         uint32 `db:"cost" json:"cost"`
+	MaxQuantity  uint16 `db:"max_quantity" json:"maxQuantity"`
+	Retired      bool   `db:"retired" json:"retired"`
+	Bought       int64  `db:"bought" json:"bought"`              // Purchases, counted the way maxQuantity caps them
+	Buyers       int    `db:"buyers" json:"buyers"`              // Characters with at least one purchase
+	CappedBuyers int    `db:"capped_buyers" json:"cappedBuyers"` // Characters who have reached maxQuantity
+}
+
+// ShopCapHit is a character that has bought a capped shop item as many times
+// as its maxQuantity allows.
+type ShopCapHit struct {
+	CharID      uint32 `db:"character_id" json:"charId"`
+	CharName    string `db:"name" json:"charName"`
+	ShopItemID  uint32 `db:"shop_item_id" json:"shopItemId"`
+	ShopType    uint8  `db:"shop_type" json:"shopType"`
+	ShopID      uint32 `db:"shop_id" json:"shopId"`
+	ItemID      uint16 `db:"item_id" json:"itemId"`
+	MaxQuantity uint16 `db:"max_quantity" json:"maxQuantity"`
+	Bought      int64  `db:"bought" json:"bought"`
+}
+
+// ShopReportFilter narrows a purchase report. Purchase counts are running
+// totals per character with no timestamps, so reports cannot be limited to a
+// period.
+type ShopReportFilter struct {
+	ShopType uint8
+	ShopID   *uint32
+	CharID   uint32 // Cap hits only
+	Limit    int
+}
+
+// TopShopItems returns the shop items with the most purchases, retired ones
+// included.
+func (r *ShopRepository) TopShopItems(f ShopReportFilter) ([]ShopItemSales, error) {
+	conds := []string{"b.bought > 0"}
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.ShopType > 0 {
+		add("si.shop_type = $%d", f.ShopType)
+	}
+	if f.ShopID != nil {
+		add("si.shop_id = $%d", *f.ShopID)
+	}
+	args = append(args, f.Limit)
+	var sales []ShopItemSales
+	err := r.db.Select(&sales, `
+		SELECT si.id AS shop_item_id, COALESCE(si.shop_type, 0) AS shop_type, COALESCE(si.shop_id, 0) AS shop_id,
+		COALESCE(si.item_id, 0) AS item_id, COALESCE(`+shopItemCost+`, 0) AS cost,
+		COALESCE(si.max_quantity, 0) AS max_quantity, si.retired_at IS NOT NULL AS retired,
+		SUM(b.bought) AS bought, COUNT(*) AS buyers,
+		COUNT(*) FILTER (WHERE si.max_quantity > 0 AND b.bought >= si.max_quantity) AS capped_buyers
+		FROM shop_items_bought b JOIN shop_items si ON si.id = b.shop_item_id
+		WHERE `+strings.Join(conds, " AND ")+`
+		GROUP BY si.id`+fmt.Sprintf(` ORDER BY bought DESC, si.id LIMIT $%d`, len(args)), args...)
+	return sales, err
+}
+
+// ShopCapHits returns the characters that have reached a shop item's
+// per-character cap, by item and character.
+func (r *ShopRepository) ShopCapHits(f ShopReportFilter) ([]ShopCapHit, error) {
+	conds := []string{"si.max_quantity > 0", "b.bought >= si.max_quantity"}
+	var args []interface{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.ShopType > 0 {
+		add("si.shop_type = $%d", f.ShopType)
+	}
+	if f.ShopID != nil {
+		add("si.shop_id = $%d", *f.ShopID)
+	}
+	if f.CharID > 0 {
+		add("b.character_id = $%d", f.CharID)
+	}
+	args = append(args, f.Limit)
+	var hits []ShopCapHit
+	err := r.db.Select(&hits, `
+		SELECT b.character_id, COALESCE(c.name, '') AS name, si.id AS shop_item_id,
+		COALESCE(si.shop_type, 0) AS shop_type, COALESCE(si.shop_id, 0) AS shop_id,
+		COALESCE(si.item_id, 0) AS item_id, si.max_quantity, b.bought
+		FROM shop_items_bought b JOIN shop_items si ON si.id = b.shop_item_id
+		LEFT JOIN characters c ON c.id = b.character_id
+		WHERE `+strings.Join(conds, " AND ")+
+		fmt.Sprintf(` ORDER BY si.id, b.character_id LIMIT $%d`, len(args)), args...)
+	return hits, err
+}
@@ -2,6 +2,7 @@ package channelserver
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -139,3 +140,94 @@ func TestRepoShopGetFpointExchangeListEmpty(t *testing.T) {
 		t.Errorf("Expected 0 exchange items, got: %d", len(exchanges))
 	}
 }
+
+func TestRepoShopCatalogueRetireAndPriceChanges(t *testing.T) {
+	repo, _, charID := setupShopRepo(t)
+
+	id, err := repo.CreateCatalogueItem(ShopCatalogueItem{ShopType: 10, ShopID: 2, ItemID: 500, Cost: 100, Quantity: 1, MaxQuantity: 3})
+	if err != nil {
+		t.Fatalf("CreateCatalogueItem failed: %v", err)
+	}
+	now := time.Now()
+	for _, c := range []ShopPriceChange{
+		{ShopItemID: id, Cost: 80, EffectiveAt: now.Add(-time.Hour)},
+		{ShopItemID: id, Cost: 60, EffectiveAt: now.Add(time.Hour)},
+	} {
+		if _, err := repo.CreatePriceChange(c); err != nil {
+			t.Fatalf("CreatePriceChange failed: %v", err)
+		}
+	}
+
+	items, err := repo.GetShopItems(10, 2, charID)
+	if err != nil {
+		t.Fatalf("GetShopItems failed: %v", err)
+	}
+	if len(items) != 1 || items[0].Cost != 80 {
+		t.Fatalf("Expected the applied price change (80), got: %+v", items)
+	}
+
+	item, _ := repo.GetCatalogueItem(id)
+	item.Cost = 120
+	if ok, err := repo.UpdateCatalogueItem(*item, true); err != nil || !ok {
+		t.Fatalf("UpdateCatalogueItem failed: %v", err)
+	}
+	changes, err := repo.ListPriceChanges(id)
+	if err != nil {
+		t.Fatalf("ListPriceChanges failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Cost != 60 {
+		t.Errorf("Expected only the pending change to survive a reprice, got: %+v", changes)
+	}
+	if item, _ = repo.GetCatalogueItem(id); item.Cost != 120 {
+		t.Errorf("Expected cost=120, got: %d", item.Cost)
+	}
+
+	if ok, err := repo.SetCatalogueItemRetired(id, true); err != nil || !ok {
+		t.Fatalf("SetCatalogueItemRetired failed: %v", err)
+	}
+	if items, _ = repo.GetShopItems(10, 2, charID); len(items) != 0 {
+		t.Errorf("Expected retired item to be hidden, got: %+v", items)
+	}
+	listed, err := repo.ListCatalogue(ShopItemFilter{ShopType: 10, IncludeRetired: true})
+	if err != nil {
+		t.Fatalf("ListCatalogue failed: %v", err)
+	}
+	if len(listed) != 1 || listed[0].RetiredAt == nil {
+		t.Errorf("Expected the retired item with retiredAt, got: %+v", listed)
+	}
+}
+
+func TestRepoShopPurchaseReports(t *testing.T) {
+	repo, _, charID := setupShopRepo(t)
+
+	capped, err := repo.CreateCatalogueItem(ShopCatalogueItem{ShopType: 10, ShopID: 2, ItemID: 500, Cost: 100, Quantity: 1, MaxQuantity: 3})
+	if err != nil {
+		t.Fatalf("CreateCatalogueItem failed: %v", err)
+	}
+	open, err := repo.CreateCatalogueItem(ShopCatalogueItem{ShopType: 10, ShopID: 2, ItemID: 501, Cost: 10, Quantity: 1})
+	if err != nil {
+		t.Fatalf("CreateCatalogueItem failed: %v", err)
+	}
+	if err := repo.RecordPurchase(charID, capped, 3); err != nil {
+		t.Fatalf("RecordPurchase failed: %v", err)
+	}
+	if err := repo.RecordPurchase(charID, open, 7); err != nil {
+		t.Fatalf("RecordPurchase failed: %v", err)
+	}
+
+	sales, err := repo.TopShopItems(ShopReportFilter{ShopType: 10, Limit: 10})
+	if err != nil {
+		t.Fatalf("TopShopItems failed: %v", err)
+	}
+	if len(sales) != 2 || sales[0].ShopItemID != open || sales[0].Bought != 7 || sales[1].CappedBuyers != 1 {
+		t.Errorf("Unexpected sales: %+v", sales)
+	}
+
+	hits, err := repo.ShopCapHits(ShopReportFilter{CharID: charID, Limit: 10})
+	if err != nil {
+		t.Fatalf("ShopCapHits failed: %v", err)
+	}
+	if len(hits) != 1 || hits[0].ShopItemID != capped || hits[0].CharName != "ShopChar" {
+		t.Errorf("Unexpected cap hits: %+v", hits)
+	}
+}
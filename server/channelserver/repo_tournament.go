@@ -1,12 +1,92 @@
 package channelserver
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// Tournament result review states.
+const (
+	TournamentResultOK       = "ok"
+	TournamentResultFlagged  = "flagged"
+	TournamentResultVerified = "verified"
+	TournamentResultRejected = "rejected"
+)
+
+// tournamentSoulsTrialType is the festa_submissions trial type tournament
+// souls are credited under. No Festa trial uses it, so the souls count toward
+// team totals without affecting trial rankings.
+const tournamentSoulsTrialType = -1
+
+// tournamentColumns are the tournaments columns scanned into a Tournament.
+const tournamentColumns = `id, name, start_time, entry_end, ranking_end, reward_end, cycle_days, cycled_from, paid_at`
+
+// TournamentPrize is a prize bracket: placings PlaceFrom to PlaceTo in any
+// sub-event of CupGroup earn the item, and their guild earns Souls.
+type TournamentPrize struct {
+	ID        uint32 `db:"id" json:"id"`
+	CupGroup  int16  `db:"cup_group" json:"cupGroup"`
+	PlaceFrom uint32 `db:"place_from" json:"placeFrom"`
+	PlaceTo   uint32 `db:"place_to" json:"placeTo"`
+	ItemType  uint8  `db:"item_type" json:"itemType"`
+	ItemID    uint32 `db:"item_id" json:"itemId"`
+	Quantity  uint32 `db:"quantity" json:"quantity"`
+	Souls     uint32 `db:"souls" json:"souls"`
+}
+
+// TournamentDetail is a tournament with its cups, own sub-events and prize
+// brackets. A tournament without sub-events of its own uses the shared ones.
+type TournamentDetail struct {
+	Tournament
+	Cups      []TournamentCup      `json:"cups"`
+	SubEvents []TournamentSubEvent `json:"subEvents"`
+	Prizes    []TournamentPrize    `json:"prizes"`
+}
+
+// TournamentResult is one submitted tournament run.
+type TournamentResult struct {
+	ID           uint32    `db:"id" json:"id"`
+	CharID       uint32    `db:"char_id" json:"charId"`
+	CharName     string    `db:"char_name" json:"charName"`
+	TournamentID uint32    `db:"tournament_id" json:"tournamentId"`
+	EventID      uint32    `db:"event_id" json:"eventId"`
+	QuestSlot    uint32    `db:"quest_slot" json:"questSlot"`
+	StageHandle  uint32    `db:"stage_handle" json:"stageHandle"`
+	SubmittedAt  time.Time `db:"submitted_at" json:"submittedAt"`
+	Status       string    `db:"status" json:"status"`
+	FlagReason   string    `db:"flag_reason" json:"flagReason,omitempty"`
+}
+
+// TournamentPlacing is a character's place in one sub-event, counting only
+// their first ok or verified result.
+type TournamentPlacing struct {
+	EventID   uint32 `db:"event_id"`
+	EventName string `db:"event_name"`
+	CupGroup  int16  `db:"cup_group"`
+	CharID    uint32 `db:"char_id"`
+	CharName  string `db:"char_name"`
+	GuildID   uint32 `db:"guild_id"`
+	Place     uint32 `db:"place"`
+}
+
+// TournamentPrizeGrant is what one placing earns: items sent as a
+// distribution and souls credited to the guild.
+type TournamentPrizeGrant struct {
+	CharID      uint32
+	GuildID     uint32
+	EventID     uint32
+	Place       uint32
+	Description string
+	Items       []DistributionItem
+	Souls       uint32
+}
+
 // TournamentRepository centralizes all database access for tournament tables.
 type TournamentRepository struct {
 	db *sqlx.DB
@@ -25,7 +105,7 @@ func NewTournamentRepository(db *sqlx.DB) *TournamentRepository {
 func (r *TournamentRepository) GetActive(now int64) (*Tournament, error) {
 	var t Tournament
 	err := r.db.QueryRowx(
-		`SELECT id, name, start_time, entry_end, ranking_end, reward_end
+		`SELECT `+tournamentColumns+`
 		 FROM tournaments
 		 WHERE start_time > 0 AND entry_end > 0
 		   AND ranking_end > 0 AND reward_end > 0
@@ -56,13 +136,18 @@ func (r *TournamentRepository) GetCups(tournamentID uint32) ([]TournamentCup, er
 	return cups, err
 }
 
-// GetSubEvents returns all sub-events ordered by cup group and event sub type.
-func (r *TournamentRepository) GetSubEvents() ([]TournamentSubEvent, error) {
+// GetSubEvents returns the sub-events of a tournament ordered by cup group
+// and event sub type: its own if it has any, otherwise the shared ones.
+func (r *TournamentRepository) GetSubEvents(tournamentID uint32) ([]TournamentSubEvent, error) {
 	var events []TournamentSubEvent
 	err := r.db.Select(&events,
-		`SELECT id, cup_group, event_sub_type, quest_file_id, name
+		`SELECT id, cup_group, event_sub_type, quest_file_id, name, min_submit_interval_seconds
 		 FROM tournament_sub_events
+		 WHERE tournament_id = $1
+		    OR (tournament_id IS NULL
+		        AND NOT EXISTS (SELECT 1 FROM tournament_sub_events WHERE tournament_id = $1))
 		 ORDER BY cup_group, event_sub_type`,
+		tournamentID,
 	)
 	return events, err
 }
@@ -108,12 +193,14 @@ func (r *TournamentRepository) GetEntry(charID, tournamentID uint32) (*Tournamen
 	return &e, nil
 }
 
-// SubmitResult records a completed tournament run for a character.
-func (r *TournamentRepository) SubmitResult(charID, tournamentID, eventID, questSlot, stageHandle uint32) error {
+// SubmitResult records a tournament run for a character.
+func (r *TournamentRepository) SubmitResult(result TournamentResult) error {
 	_, err := r.db.Exec(
-		`INSERT INTO tournament_results (char_id, tournament_id, event_id, quest_slot, stage_handle)
-		 VALUES ($1, $2, $3, $4, $5)`,
-		charID, tournamentID, eventID, questSlot, stageHandle,
+		`INSERT INTO tournament_results
+		     (char_id, tournament_id, event_id, quest_slot, stage_handle, submitted_at, status, flag_reason)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		result.CharID, result.TournamentID, result.EventID, result.QuestSlot, result.StageHandle,
+		result.SubmittedAt, result.Status, result.FlagReason,
 	)
 	if err != nil {
 		return fmt.Errorf("insert tournament result: %w", err)
@@ -121,9 +208,27 @@ func (r *TournamentRepository) SubmitResult(charID, tournamentID, eventID, quest
 	return nil
 }
 
+// LastSubmission returns when a character last submitted a run of an event,
+// or nil if they never have.
+func (r *TournamentRepository) LastSubmission(charID, tournamentID, eventID uint32) (*time.Time, error) {
+	var last sql.NullTime
+	err := r.db.QueryRow(
+		`SELECT MAX(submitted_at) FROM tournament_results
+		 WHERE char_id = $1 AND tournament_id = $2 AND event_id = $3`,
+		charID, tournamentID, eventID,
+	).Scan(&last)
+	if err != nil {
+		return nil, fmt.Errorf("get last tournament submission: %w", err)
+	}
+	if !last.Valid {
+		return nil, nil
+	}
+	return &last.Time, nil
+}
+
 // GetLeaderboard returns the ranked leaderboard for an event ID.
-// Rank is assigned by submission order (first submitted = rank 1).
-// Returns at most 100 entries.
+// Rank is assigned by submission order (first submitted = rank 1) and
+// rejected results are left out. Returns at most 100 entries.
 func (r *TournamentRepository) GetLeaderboard(eventID uint32) ([]TournamentRankEntry, error) {
 	type row struct {
 		CharID    uint32 `db:"char_id"`
@@ -148,7 +253,7 @@ func (r *TournamentRepository) GetLeaderboard(eventID uint32) ([]TournamentRankE
 		JOIN characters c ON c.id = r.char_id
 		LEFT JOIN guild_characters gc ON gc.character_id = r.char_id
 		LEFT JOIN guilds g ON g.id = gc.guild_id
-		WHERE r.event_id = $1
+		WHERE r.event_id = $1 AND r.status <> 'rejected'
 		ORDER BY r.submitted_at ASC
 		LIMIT 100`,
 		eventID,
@@ -170,3 +275,350 @@ func (r *TournamentRepository) GetLeaderboard(eventID uint32) ([]TournamentRankE
 	}
 	return entries, nil
 }
+
+// GetTournament returns a tournament by ID, or nil if it does not exist.
+func (r *TournamentRepository) GetTournament(id uint32) (*Tournament, error) {
+	var t Tournament
+	err := r.db.QueryRowx(`SELECT `+tournamentColumns+` FROM tournaments WHERE id = $1`, id).StructScan(&t)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get tournament: %w", err)
+	}
+	return &t, nil
+}
+
+// ListTournaments returns every tournament, latest start first.
+func (r *TournamentRepository) ListTournaments() ([]Tournament, error) {
+	var tournaments []Tournament
+	err := r.db.Select(&tournaments, `SELECT `+tournamentColumns+` FROM tournaments ORDER BY start_time DESC, id DESC`)
+	return tournaments, err
+}
+
+// CreateTournament stores a tournament with its cups, sub-events and prizes
+// in one transaction and returns its ID.
+func (r *TournamentRepository) CreateTournament(detail TournamentDetail) (uint32, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var id uint32
+	t := detail.Tournament
+	if err := tx.QueryRow(
+		`INSERT INTO tournaments (name, start_time, entry_end, ranking_end, reward_end, cycle_days)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		t.Name, t.StartTime, t.EntryEnd, t.RankingEnd, t.RewardEnd, t.CycleDays,
+	).Scan(&id); err != nil {
+		return 0, fmt.Errorf("insert tournament: %w", err)
+	}
+	if err := replaceTournamentChildren(tx, id, detail); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// UpdateTournament replaces a tournament's schedule, cups, sub-events and
+// prizes in one transaction. Sub-events are matched by ID so results keep
+// pointing at them. It returns false if the tournament does not exist.
+func (r *TournamentRepository) UpdateTournament(detail TournamentDetail) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	t := detail.Tournament
+	res, err := tx.Exec(
+		`UPDATE tournaments
+		 SET name = $2, start_time = $3, entry_end = $4, ranking_end = $5, reward_end = $6, cycle_days = $7
+		 WHERE id = $1`,
+		t.ID, t.Name, t.StartTime, t.EntryEnd, t.RankingEnd, t.RewardEnd, t.CycleDays,
+	)
+	if err != nil {
+		return false, fmt.Errorf("update tournament: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if err := replaceTournamentChildren(tx, t.ID, detail); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// replaceTournamentChildren rewrites the cups and prizes of a tournament and
+// upserts its sub-events, deleting any that are no longer listed.
+func replaceTournamentChildren(tx *sqlx.Tx, id uint32, detail TournamentDetail) error {
+	if _, err := tx.Exec(`DELETE FROM tournament_cups WHERE tournament_id = $1`, id); err != nil {
+		return fmt.Errorf("delete tournament cups: %w", err)
+	}
+	for _, cup := range detail.Cups {
+		if _, err := tx.Exec(
+			`INSERT INTO tournament_cups (tournament_id, cup_group, cup_type, unk, name, description)
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			id, cup.CupGroup, cup.CupType, cup.Unk, cup.Name, cup.Description,
+		); err != nil {
+			return fmt.Errorf("insert tournament cup: %w", err)
+		}
+	}
+
+	kept := make([]int64, 0, len(detail.SubEvents))
+	for _, se := range detail.SubEvents {
+		if se.ID != 0 {
+			res, err := tx.Exec(
+				`UPDATE tournament_sub_events
+				 SET cup_group = $3, event_sub_type = $4, quest_file_id = $5, name = $6, min_submit_interval_seconds = $7
+				 WHERE id = $1 AND tournament_id = $2`,
+				se.ID, id, se.CupGroup, se.EventSubType, se.QuestFileID, se.Name, se.MinSubmitIntervalSeconds,
+			)
+			if err != nil {
+				return fmt.Errorf("update tournament sub-event: %w", err)
+			}
+			if n, _ := res.RowsAffected(); n == 1 {
+				kept = append(kept, int64(se.ID))
+				continue
+			}
+		}
+		var seID int64
+		if err := tx.QueryRow(
+			`INSERT INTO tournament_sub_events (tournament_id, cup_group, event_sub_type, quest_file_id, name, min_submit_interval_seconds)
+			 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			id, se.CupGroup, se.EventSubType, se.QuestFileID, se.Name, se.MinSubmitIntervalSeconds,
+		).Scan(&seID); err != nil {
+			return fmt.Errorf("insert tournament sub-event: %w", err)
+		}
+		kept = append(kept, seID)
+	}
+	if _, err := tx.Exec(
+		`DELETE FROM tournament_sub_events WHERE tournament_id = $1 AND NOT (id = ANY($2))`,
+		id, pq.Array(kept),
+	); err != nil {
+		return fmt.Errorf("delete tournament sub-events: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM tournament_prizes WHERE tournament_id = $1`, id); err != nil {
+		return fmt.Errorf("delete tournament prizes: %w", err)
+	}
+	for _, p := range detail.Prizes {
+		if _, err := tx.Exec(
+			`INSERT INTO tournament_prizes (tournament_id, cup_group, place_from, place_to, item_type, item_id, quantity, souls)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			id, p.CupGroup, p.PlaceFrom, p.PlaceTo, p.ItemType, p.ItemID, p.Quantity, p.Souls,
+		); err != nil {
+			return fmt.Errorf("insert tournament prize: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteTournament deletes a tournament along with its cups, sub-events,
+// entries, results and prizes. It returns false if it did not exist.
+func (r *TournamentRepository) DeleteTournament(id uint32) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM tournaments WHERE id = $1`, id)
+	if err != nil {
+		return false, fmt.Errorf("delete tournament: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetOwnSubEvents returns only the sub-events that belong to a tournament.
+func (r *TournamentRepository) GetOwnSubEvents(tournamentID uint32) ([]TournamentSubEvent, error) {
+	var events []TournamentSubEvent
+	err := r.db.Select(&events,
+		`SELECT id, cup_group, event_sub_type, quest_file_id, name, min_submit_interval_seconds
+		 FROM tournament_sub_events
+		 WHERE tournament_id = $1
+		 ORDER BY cup_group, event_sub_type`,
+		tournamentID,
+	)
+	return events, err
+}
+
+// GetPrizes returns a tournament's prize brackets.
+func (r *TournamentRepository) GetPrizes(tournamentID uint32) ([]TournamentPrize, error) {
+	var prizes []TournamentPrize
+	err := r.db.Select(&prizes,
+		`SELECT id, cup_group, place_from, place_to, item_type, item_id, quantity, souls
+		 FROM tournament_prizes
+		 WHERE tournament_id = $1
+		 ORDER BY cup_group, place_from, id`,
+		tournamentID,
+	)
+	return prizes, err
+}
+
+// GetResults returns a tournament's submitted runs in submission order,
+// optionally only those with the given status.
+func (r *TournamentRepository) GetResults(tournamentID uint32, status string) ([]TournamentResult, error) {
+	var results []TournamentResult
+	err := r.db.Select(&results,
+		`SELECT r.id, r.char_id, COALESCE(c.name, '') AS char_name, r.tournament_id, r.event_id,
+		        r.quest_slot, r.stage_handle, r.submitted_at, r.status, r.flag_reason
+		 FROM tournament_results r
+		 LEFT JOIN characters c ON c.id = r.char_id
+		 WHERE r.tournament_id = $1 AND ($2 = '' OR r.status = $2)
+		 ORDER BY r.submitted_at, r.id`,
+		tournamentID, status,
+	)
+	return results, err
+}
+
+// SetResultStatus sets the review status of one of a tournament's results.
+// It returns false if the result does not exist.
+func (r *TournamentRepository) SetResultStatus(tournamentID, resultID uint32, status string) (bool, error) {
+	res, err := r.db.Exec(
+		`UPDATE tournament_results SET status = $3 WHERE id = $2 AND tournament_id = $1`,
+		tournamentID, resultID, status,
+	)
+	if err != nil {
+		return false, fmt.Errorf("update tournament result: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// GetPlacings ranks the characters in each of a tournament's sub-events by
+// their first ok or verified result, in submission order like
+// GetLeaderboard.
+func (r *TournamentRepository) GetPlacings(tournamentID uint32) ([]TournamentPlacing, error) {
+	var placings []TournamentPlacing
+	err := r.db.Select(&placings, `
+		WITH firsts AS (
+		    SELECT DISTINCT ON (event_id, char_id) event_id, char_id, submitted_at
+		    FROM tournament_results
+		    WHERE tournament_id = $1 AND status IN ('ok', 'verified')
+		    ORDER BY event_id, char_id, submitted_at, id
+		)
+		SELECT f.event_id, COALESCE(se.name, '') AS event_name, COALESCE(se.cup_group, 0) AS cup_group, f.char_id, c.name AS char_name,
+		       COALESCE(gc.guild_id, 0) AS guild_id,
+		       ROW_NUMBER() OVER (PARTITION BY f.event_id ORDER BY f.submitted_at, f.char_id) AS place
+		FROM firsts f
+		JOIN characters c ON c.id = f.char_id
+		LEFT JOIN tournament_sub_events se ON se.id = f.event_id
+		LEFT JOIN guild_characters gc ON gc.character_id = f.char_id
+		ORDER BY f.event_id, place`,
+		tournamentID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get tournament placings: %w", err)
+	}
+	return placings, nil
+}
+
+// PayPrizes creates one character-bound distribution per grant with items,
+// credits the grants' souls to their guilds and marks the tournament paid,
+// all in one transaction. It returns false without writing anything if the
+// tournament was already paid.
+func (r *TournamentRepository) PayPrizes(tournamentID uint32, grants []TournamentPrizeGrant, eventName string) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.Exec(`UPDATE tournaments SET paid_at = now() WHERE id = $1 AND paid_at IS NULL`, tournamentID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	for _, grant := range grants {
+		if len(grant.Items) > 0 {
+			var distID uint32
+			if err := tx.QueryRow(`
+				INSERT INTO distribution (character_id, type, event_name, description, times_acceptable)
+				VALUES ($1, $2, $3, $4, 1) RETURNING id
+			`, grant.CharID, tournamentDistributionType, eventName, grant.Description).Scan(&distID); err != nil {
+				return false, fmt.Errorf("insert distribution: %w", err)
+			}
+			for _, item := range grant.Items {
+				if _, err := tx.Exec(`
+					INSERT INTO distribution_items (distribution_id, item_type, item_id, quantity) VALUES ($1, $2, $3, $4)
+				`, distID, item.ItemType, item.ItemID, item.Quantity); err != nil {
+					return false, fmt.Errorf("insert distribution item: %w", err)
+				}
+			}
+		}
+		if grant.Souls > 0 && grant.GuildID != 0 {
+			if _, err := tx.Exec(`
+				INSERT INTO festa_submissions (character_id, guild_id, trial_type, souls, "timestamp")
+				VALUES ($1, $2, $3, $4, now())
+			`, grant.CharID, grant.GuildID, tournamentSoulsTrialType, grant.Souls); err != nil {
+				return false, fmt.Errorf("insert festa souls: %w", err)
+			}
+		}
+	}
+	return true, tx.Commit()
+}
+
+// ListUnpaid returns the tournaments whose reward phase ended by now and
+// whose prizes have not been paid.
+func (r *TournamentRepository) ListUnpaid(now int64) ([]Tournament, error) {
+	var tournaments []Tournament
+	err := r.db.Select(&tournaments,
+		`SELECT `+tournamentColumns+` FROM tournaments
+		 WHERE paid_at IS NULL AND reward_end > 0 AND reward_end <= $1
+		 ORDER BY reward_end`,
+		now,
+	)
+	return tournaments, err
+}
+
+// ListCycleDue returns the recurring tournaments that ended by now and have
+// not yet been rolled forward.
+func (r *TournamentRepository) ListCycleDue(now int64) ([]Tournament, error) {
+	var tournaments []Tournament
+	err := r.db.Select(&tournaments,
+		`SELECT `+tournamentColumns+` FROM tournaments t
+		 WHERE cycle_days > 0 AND reward_end > 0 AND reward_end <= $1
+		   AND NOT EXISTS (SELECT 1 FROM tournaments n WHERE n.cycled_from = t.id)
+		 ORDER BY reward_end`,
+		now,
+	)
+	return tournaments, err
+}
+
+// CreateNextCycle stores next as the copy of prev, along with prev's cups,
+// own sub-events and prizes. It returns false without writing anything if
+// prev has already been rolled forward.
+func (r *TournamentRepository) CreateNextCycle(prev Tournament, next Tournament) (bool, error) {
+	tx, err := r.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var id uint32
+	err = tx.QueryRow(
+		`INSERT INTO tournaments (name, start_time, entry_end, ranking_end, reward_end, cycle_days, cycled_from)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (cycled_from) DO NOTHING
+		 RETURNING id`,
+		next.Name, next.StartTime, next.EntryEnd, next.RankingEnd, next.RewardEnd, next.CycleDays, prev.ID,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("insert tournament cycle: %w", err)
+	}
+	for _, q := range []string{
+		`INSERT INTO tournament_cups (tournament_id, cup_group, cup_type, unk, name, description)
+		 SELECT $2, cup_group, cup_type, unk, name, description FROM tournament_cups WHERE tournament_id = $1 ORDER BY id`,
+		`INSERT INTO tournament_sub_events (tournament_id, cup_group, event_sub_type, quest_file_id, name, min_submit_interval_seconds)
+		 SELECT $2, cup_group, event_sub_type, quest_file_id, name, min_submit_interval_seconds FROM tournament_sub_events WHERE tournament_id = $1 ORDER BY id`,
+		`INSERT INTO tournament_prizes (tournament_id, cup_group, place_from, place_to, item_type, item_id, quantity, souls)
+		 SELECT $2, cup_group, place_from, place_to, item_type, item_id, quantity, souls FROM tournament_prizes WHERE tournament_id = $1 ORDER BY id`,
+	} {
+		if _, err := tx.Exec(q, prev.ID, id); err != nil {
+			return false, fmt.Errorf("copy tournament cycle: %w", err)
+		}
+	}
+	return true, tx.Commit()
+}
@@ -0,0 +1,172 @@
+package channelserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func setupTournamentRepo(t *testing.T) (*TournamentRepository, *sqlx.DB, uint32, uint32) {
+	t.Helper()
+	db := SetupTestDB(t)
+	userID := CreateTestUser(t, db, "tournament_test_user")
+	first := CreateTestCharacter(t, db, userID, "HunterOne")
+	second := CreateTestCharacter(t, db, userID, "HunterTwo")
+	repo := NewTournamentRepository(db)
+	t.Cleanup(func() { TeardownTestDB(t, db) })
+	return repo, db, first, second
+}
+
+func testTournamentDetail() TournamentDetail {
+	start := time.Date(2026, 3, 6, 14, 0, 0, 0, time.UTC).Unix()
+	return TournamentDetail{
+		Tournament: Tournament{
+			Name:       "Tournament #151",
+			StartTime:  start,
+			EntryEnd:   start + tournamentEntryLength,
+			RankingEnd: start + tournamentEntryLength + tournamentRankingLength,
+			RewardEnd:  start + tournamentEntryLength + tournamentRankingLength + tournamentRewardLength,
+			CycleDays:  21,
+		},
+		Cups:      []TournamentCup{{CupGroup: 16, CupType: 7, Name: "Speed Hunt"}},
+		SubEvents: []TournamentSubEvent{{CupGroup: 16, EventSubType: 0, QuestFileID: 60691, Name: "Brachydios", MinSubmitIntervalSeconds: 120}},
+		Prizes:    []TournamentPrize{{CupGroup: 16, PlaceFrom: 1, PlaceTo: 1, ItemType: 7, ItemID: 100, Quantity: 1}},
+	}
+}
+
+func TestRepoTournamentCreateAndUpdate(t *testing.T) {
+	repo, _, _, _ := setupTournamentRepo(t)
+
+	id, err := repo.CreateTournament(testTournamentDetail())
+	if err != nil {
+		t.Fatalf("CreateTournament failed: %v", err)
+	}
+	events, err := repo.GetSubEvents(id)
+	if err != nil || len(events) != 1 || events[0].MinSubmitIntervalSeconds != 120 {
+		t.Fatalf("GetSubEvents = %+v, %v", events, err)
+	}
+
+	detail := testTournamentDetail()
+	detail.ID = id
+	detail.Name = "Tournament #152"
+	detail.SubEvents[0].ID = events[0].ID
+	detail.SubEvents[0].Name = "Brachydios (G)"
+	detail.SubEvents = append(detail.SubEvents, TournamentSubEvent{CupGroup: 17, EventSubType: -1, Name: "Guild Hunt"})
+	if ok, err := repo.UpdateTournament(detail); err != nil || !ok {
+		t.Fatalf("UpdateTournament = %v, %v", ok, err)
+	}
+	events, err = repo.GetOwnSubEvents(id)
+	if err != nil || len(events) != 2 {
+		t.Fatalf("GetOwnSubEvents = %+v, %v", events, err)
+	}
+	if events[0].ID != detail.SubEvents[0].ID || events[0].Name != "Brachydios (G)" {
+		t.Errorf("sub-event = %+v, want the original ID renamed", events[0])
+	}
+	got, err := repo.GetTournament(id)
+	if err != nil || got == nil || got.Name != "Tournament #152" || got.CycleDays != 21 {
+		t.Errorf("GetTournament = %+v, %v", got, err)
+	}
+
+	if ok, err := repo.UpdateTournament(TournamentDetail{Tournament: Tournament{ID: id + 100, Name: "x", StartTime: 1, EntryEnd: 2, RankingEnd: 3, RewardEnd: 4}}); err != nil || ok {
+		t.Errorf("UpdateTournament(missing) = %v, %v, want false", ok, err)
+	}
+}
+
+func TestRepoTournamentResultsAndPayout(t *testing.T) {
+	repo, db, first, second := setupTournamentRepo(t)
+	id, err := repo.CreateTournament(testTournamentDetail())
+	if err != nil {
+		t.Fatalf("CreateTournament failed: %v", err)
+	}
+	events, _ := repo.GetSubEvents(id)
+	eventID := events[0].ID
+	submitted := time.Unix(testTournamentDetail().StartTime+3600, 0)
+
+	for _, r := range []TournamentResult{
+		{CharID: first, Status: TournamentResultFlagged, FlagReason: TournamentFlagSubmissionRate},
+		{CharID: second, Status: TournamentResultOK},
+		{CharID: first, Status: TournamentResultOK},
+	} {
+		r.TournamentID, r.EventID = id, eventID
+		r.SubmittedAt = submitted
+		submitted = submitted.Add(time.Minute)
+		if err := repo.SubmitResult(r); err != nil {
+			t.Fatalf("SubmitResult failed: %v", err)
+		}
+	}
+
+	flagged, err := repo.GetResults(id, TournamentResultFlagged)
+	if err != nil || len(flagged) != 1 || flagged[0].CharName != "HunterOne" {
+		t.Fatalf("GetResults(flagged) = %+v, %v", flagged, err)
+	}
+	last, err := repo.LastSubmission(first, id, eventID)
+	if err != nil || last == nil || !last.Equal(submitted.Add(-time.Minute)) {
+		t.Errorf("LastSubmission = %v, %v", last, err)
+	}
+
+	// The flagged run does not count, so the second character places first.
+	placings, err := repo.GetPlacings(id)
+	if err != nil || len(placings) != 2 || placings[0].CharID != second || placings[0].Place != 1 {
+		t.Fatalf("GetPlacings = %+v, %v", placings, err)
+	}
+
+	if ok, err := repo.SetResultStatus(id, flagged[0].ID, TournamentResultVerified); err != nil || !ok {
+		t.Fatalf("SetResultStatus = %v, %v", ok, err)
+	}
+	placings, _ = repo.GetPlacings(id)
+	if len(placings) != 2 || placings[0].CharID != first {
+		t.Errorf("after verify placings = %+v, want the first character first", placings)
+	}
+
+	grants := []TournamentPrizeGrant{{CharID: first, Description: "prize", Items: []DistributionItem{{ItemType: 7, ItemID: 100, Quantity: 1}}}}
+	if ok, err := repo.PayPrizes(id, grants, tournamentPrizeEventName); err != nil || !ok {
+		t.Fatalf("PayPrizes = %v, %v", ok, err)
+	}
+	if ok, err := repo.PayPrizes(id, grants, tournamentPrizeEventName); err != nil || ok {
+		t.Errorf("second PayPrizes = %v, %v, want false", ok, err)
+	}
+	var dists int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM distribution WHERE character_id = $1`, first).Scan(&dists); err != nil || dists != 1 {
+		t.Errorf("distributions = %d, %v, want 1", dists, err)
+	}
+}
+
+func TestRepoTournamentCycle(t *testing.T) {
+	repo, _, _, _ := setupTournamentRepo(t)
+	id, err := repo.CreateTournament(testTournamentDetail())
+	if err != nil {
+		t.Fatalf("CreateTournament failed: %v", err)
+	}
+	prev, _ := repo.GetTournament(id)
+
+	due, err := repo.ListCycleDue(prev.RewardEnd)
+	if err != nil || len(due) != 1 {
+		t.Fatalf("ListCycleDue = %+v, %v", due, err)
+	}
+	next := nextTournamentCycle(*prev, prev.RewardEnd)
+	if ok, err := repo.CreateNextCycle(*prev, next); err != nil || !ok {
+		t.Fatalf("CreateNextCycle = %v, %v", ok, err)
+	}
+	if ok, err := repo.CreateNextCycle(*prev, next); err != nil || ok {
+		t.Errorf("second CreateNextCycle = %v, %v, want false", ok, err)
+	}
+	if due, _ := repo.ListCycleDue(prev.RewardEnd); len(due) != 0 {
+		t.Errorf("ListCycleDue after roll = %+v, want none", due)
+	}
+
+	list, err := repo.ListTournaments()
+	if err != nil || len(list) != 2 || list[0].CycledFrom == nil || *list[0].CycledFrom != id {
+		t.Fatalf("ListTournaments = %+v, %v", list, err)
+	}
+	cups, _ := repo.GetCups(list[0].ID)
+	prizes, _ := repo.GetPrizes(list[0].ID)
+	events, _ := repo.GetOwnSubEvents(list[0].ID)
+	if len(cups) != 1 || len(prizes) != 1 || len(events) != 1 {
+		t.Errorf("copied %d cups, %d prizes, %d sub-events, want 1 each", len(cups), len(prizes), len(events))
+	}
+
+	if ok, err := repo.DeleteTournament(id); err != nil || !ok {
+		t.Errorf("DeleteTournament = %v, %v", ok, err)
+	}
+}
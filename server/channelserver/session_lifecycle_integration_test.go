@@ -566,6 +566,7 @@ func createTestServerWithDB(t *testing.T, db *sqlx.DB) *Server {
 		userBinary: NewUserBinaryStore(),
 		minidata:   NewMinidataStore(),
 		semaphore:  make(map[string]*Semaphore),
+		mutexes:    make(map[string]*Mutex),
 		erupeConfig: &cfg.Config{
 			RealClientMode: cfg.ZZ,
 		},
@@ -0,0 +1,273 @@
+package channelserver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+// Earth phase status IDs, in rotation order. Each phase lasts one week and
+// the Earth ID advances when the rotation returns to Conquest.
+const (
+	EarthStatusConquest int32 = 1
+	EarthStatusPallone  int32 = 11
+	EarthStatusTower    int32 = 21
+)
+
+var earthPhases = [...]int32{EarthStatusConquest, EarthStatusPallone, EarthStatusTower}
+
+const (
+	earthPhaseLength = 7 * 24 * time.Hour
+
+	// conquestTickInterval is how often a channel checks for a finished
+	// Conquest phase to pay out.
+	conquestTickInterval = time.Minute
+
+	conquestMaxLevel    = 9999
+	conquestRankingSize = 100
+
+	// conquestDistributionType is the distribution type ranking rewards are
+	// sent as, the same gift box the bundled distribution seeds use.
+	conquestDistributionType = 1
+	conquestRewardEventName  = "Conquest War Reward"
+)
+
+// ConquestPhase is the Earth phase active at a point in time.
+type ConquestPhase struct {
+	Enabled bool
+	EarthID int32
+	Status  int32
+	Start   time.Time
+	End     time.Time
+
+	// rotation is the number of phases since the Conquest phase of the
+	// schedule anchor's Earth ID.
+	rotation int
+}
+
+// Hunting reports whether beat levels are being recorded.
+func (p ConquestPhase) Hunting() bool {
+	return p.Enabled && p.Status == EarthStatusConquest
+}
+
+// LastConquest returns the Earth ID of the most recent finished Conquest
+// phase, or false if none has finished since the anchor.
+func (p ConquestPhase) LastConquest() (int32, bool) {
+	switch {
+	case !p.Enabled || p.rotation == 0:
+		return 0, false
+	case p.Status == EarthStatusConquest:
+		return p.EarthID - 1, true
+	default:
+		return p.EarthID, true
+	}
+}
+
+// earthPhaseIndex maps a configured EarthStatus onto its place in the
+// rotation. The reward-week variants 2 and 12 start their phase's week.
+func earthPhaseIndex(status int32) int {
+	switch status {
+	case 11, 12:
+		return 1
+	case 21:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// ConquestService encapsulates the Conquest War Earth cycle: phase rotation,
+// beat level storage, rankings and weekly ranking reward payouts.
+type ConquestService struct {
+	conquestRepo ConquestRepo
+	logger       *zap.Logger
+
+	mu       sync.Mutex
+	schedule *ConquestSchedule
+
+	// payMu guards paid and serialises payouts. It is separate from mu so a
+	// payout never blocks Current.
+	payMu sync.Mutex
+	paid  map[int32]bool
+}
+
+// NewConquestService creates a new ConquestService.
+func NewConquestService(cr ConquestRepo, log *zap.Logger) *ConquestService {
+	return &ConquestService{
+		conquestRepo: cr,
+		logger:       log,
+		paid:         make(map[int32]bool),
+	}
+}
+
+// Current returns the Earth phase active at now. The rotation is anchored on
+// the stored schedule, which is seeded from c.EarthID and c.EarthStatus at
+// weekStart the first time the cycle runs. An EarthStatus of 0 disables the
+// cycle and reports the configured values for the current week unchanged.
+func (svc *ConquestService) Current(c *cfg.Config, now, weekStart time.Time) (ConquestPhase, error) {
+	if c.EarthStatus == 0 {
+		return ConquestPhase{
+			EarthID: c.EarthID,
+			Start:   weekStart,
+			End:     weekStart.Add(earthPhaseLength),
+		}, nil
+	}
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if svc.schedule == nil {
+		sched, err := svc.conquestRepo.EnsureSchedule(c.EarthID, c.EarthStatus, weekStart)
+		if err != nil {
+			return ConquestPhase{}, fmt.Errorf("conquest schedule: %w", err)
+		}
+		svc.schedule = &sched
+	}
+
+	weeks := 0
+	if now.After(svc.schedule.StartTime) {
+		weeks = int(now.Sub(svc.schedule.StartTime) / earthPhaseLength)
+	}
+	rotation := earthPhaseIndex(svc.schedule.Status) + weeks
+	start := svc.schedule.StartTime.Add(time.Duration(weeks) * earthPhaseLength)
+	phase := ConquestPhase{
+		Enabled:  true,
+		EarthID:  svc.schedule.EarthID + int32(rotation/len(earthPhases)),
+		Status:   earthPhases[rotation%len(earthPhases)],
+		Start:    start,
+		End:      start.Add(earthPhaseLength),
+		rotation: rotation,
+	}
+	return phase, nil
+}
+
+// Tick pays out the ranking rewards of the most recent finished Conquest
+// phase, if no channel has yet. It is driven by the channel server's
+// conquest loop, never by packet handlers.
+func (svc *ConquestService) Tick(c *cfg.Config, now, weekStart time.Time) {
+	phase, err := svc.Current(c, now, weekStart)
+	if err != nil {
+		svc.logger.Error("Failed to read earth phase", zap.Error(err))
+		return
+	}
+	if earthID, ok := phase.LastConquest(); ok {
+		svc.payRewards(earthID, c.EarthRewards)
+	}
+}
+
+// payRewards distributes an Earth event's ranking rewards if no channel has
+// yet. Failures are logged and retried on the next tick.
+func (svc *ConquestService) payRewards(earthID int32, rewards []cfg.ConquestReward) {
+	svc.payMu.Lock()
+	defer svc.payMu.Unlock()
+	if svc.paid[earthID] {
+		return
+	}
+	paid, err := svc.conquestRepo.IsRewardPaid(earthID)
+	if err != nil {
+		svc.logger.Error("Failed to check conquest reward payout", zap.Error(err), zap.Int32("earthID", earthID))
+		return
+	}
+	if paid {
+		svc.paid[earthID] = true
+		return
+	}
+
+	placings, err := svc.conquestRepo.GetPlacings(earthID)
+	if err != nil {
+		svc.logger.Error("Failed to read conquest rankings", zap.Error(err), zap.Int32("earthID", earthID))
+		return
+	}
+	grants := conquestRewardGrants(placings, rewards)
+	ok, err := svc.conquestRepo.PayRewards(earthID, grants, conquestRewardEventName)
+	if err != nil {
+		svc.logger.Error("Failed to pay conquest rewards", zap.Error(err), zap.Int32("earthID", earthID))
+		return
+	}
+	svc.paid[earthID] = true
+	if ok {
+		svc.logger.Info("Paid conquest ranking rewards",
+			zap.Int32("earthID", earthID), zap.Int("recipients", len(grants)))
+	}
+}
+
+// conquestRewardGrants matches each placing against the reward brackets.
+// Placings outside every bracket earn nothing.
+func conquestRewardGrants(placings []ConquestRankEntry, rewards []cfg.ConquestReward) []ConquestRewardGrant {
+	var grants []ConquestRewardGrant
+	for _, p := range placings {
+		var items []DistributionItem
+		for _, r := range rewards {
+			if p.Place >= r.PlaceFrom && p.Place <= r.PlaceTo {
+				items = append(items, DistributionItem{ItemType: r.ItemType, ItemID: r.ItemID, Quantity: r.Quantity})
+			}
+		}
+		if len(items) == 0 {
+			continue
+		}
+		grants = append(grants, ConquestRewardGrant{
+			CharID:      p.CharID,
+			MonsterID:   p.MonsterID,
+			Place:       p.Place,
+			Description: fmt.Sprintf("~C05Conquest War ranking reward: you placed #%d at level %d.", p.Place, p.Level),
+			Items:       items,
+		})
+	}
+	return grants
+}
+
+// RecordLevels stores the beat levels a client reports, one per slot of
+// monsters. Levels are only recorded during the Conquest phase; slots with
+// no configured monster or no level are skipped and levels are capped at
+// 9999.
+func (svc *ConquestService) RecordLevels(phase ConquestPhase, charID uint32, monsters []int32, levels []int32) error {
+	if !phase.Hunting() {
+		return nil
+	}
+	stored := make(map[int32]uint32)
+	for i, monsterID := range monsters {
+		if i >= len(levels) || monsterID == 0 || levels[i] <= 0 {
+			continue
+		}
+		stored[monsterID] = uint32(min(levels[i], conquestMaxLevel))
+	}
+	if len(stored) == 0 {
+		return nil
+	}
+	return svc.conquestRepo.SetLevels(charID, phase.EarthID, stored)
+}
+
+// Levels returns a character's beat levels for the phase's Earth event,
+// keyed by monster ID.
+func (svc *ConquestService) Levels(phase ConquestPhase, charID uint32) (map[int32]uint32, error) {
+	if !phase.Enabled {
+		return map[int32]uint32{}, nil
+	}
+	return svc.conquestRepo.GetLevels(charID, phase.EarthID)
+}
+
+// Ranking returns the top 100 of a monster's leaderboard for the phase's
+// Earth event.
+func (svc *ConquestService) Ranking(phase ConquestPhase, monsterID int32) ([]ConquestRankEntry, error) {
+	if !phase.Enabled {
+		return nil, nil
+	}
+	return svc.conquestRepo.GetRanking(phase.EarthID, monsterID, conquestRankingSize)
+}
+
+// Placings returns a character's placings for the given Earth event, keyed
+// by monster ID.
+func (svc *ConquestService) Placings(earthID int32, charID uint32) (map[int32]ConquestRankEntry, error) {
+	entries, err := svc.conquestRepo.GetCharacterPlacings(charID, earthID)
+	if err != nil {
+		return nil, err
+	}
+	placings := make(map[int32]ConquestRankEntry, len(entries))
+	for _, e := range entries {
+		placings[e.MonsterID] = e
+	}
+	return placings, nil
+}
@@ -0,0 +1,198 @@
+package channelserver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+func newTestConquestService(mock *mockConquestRepo) *ConquestService {
+	logger, _ := zap.NewDevelopment()
+	return NewConquestService(mock, logger)
+}
+
+var conquestAnchor = time.Date(2026, 1, 5, 0, 0, 0, 0, time.FixedZone("UTC+9", 9*60*60))
+
+func TestConquestService_Current_Disabled(t *testing.T) {
+	mock := &mockConquestRepo{}
+	svc := newTestConquestService(mock)
+
+	phase, err := svc.Current(&cfg.Config{EarthID: 7}, conquestAnchor.Add(time.Hour), conquestAnchor)
+	if err != nil {
+		t.Fatalf("Current: %v", err)
+	}
+	if phase.Enabled || phase.EarthID != 7 || phase.Status != 0 || !phase.Start.Equal(conquestAnchor) {
+		t.Errorf("phase = %+v, want disabled earth 7 from the week start", phase)
+	}
+	if mock.seeded {
+		t.Error("disabled cycle touched the schedule")
+	}
+}
+
+func TestConquestService_Current_Rotation(t *testing.T) {
+	tests := []struct {
+		weeks   int
+		earthID int32
+		status  int32
+	}{
+		{0, 10, EarthStatusConquest},
+		{1, 10, EarthStatusPallone},
+		{2, 10, EarthStatusTower},
+		{3, 11, EarthStatusConquest},
+		{7, 12, EarthStatusPallone},
+	}
+	for _, tt := range tests {
+		mock := &mockConquestRepo{}
+		svc := newTestConquestService(mock)
+		c := &cfg.Config{EarthID: 10, EarthStatus: 1}
+
+		// Seed at the anchor, then ask again tt.weeks later.
+		if _, err := svc.Current(c, conquestAnchor, conquestAnchor); err != nil {
+			t.Fatalf("Current: %v", err)
+		}
+		now := conquestAnchor.Add(time.Duration(tt.weeks)*earthPhaseLength + time.Hour)
+		phase, err := svc.Current(c, now, conquestAnchor)
+		if err != nil {
+			t.Fatalf("Current: %v", err)
+		}
+		if phase.EarthID != tt.earthID || phase.Status != tt.status {
+			t.Errorf("week %d: earth %d status %d, want earth %d status %d",
+				tt.weeks, phase.EarthID, phase.Status, tt.earthID, tt.status)
+		}
+		wantStart := conquestAnchor.Add(time.Duration(tt.weeks) * earthPhaseLength)
+		if !phase.Start.Equal(wantStart) || !phase.End.Equal(wantStart.Add(earthPhaseLength)) {
+			t.Errorf("week %d: window %v-%v", tt.weeks, phase.Start, phase.End)
+		}
+	}
+}
+
+func TestConquestService_Current_SeedPhase(t *testing.T) {
+	mock := &mockConquestRepo{}
+	svc := newTestConquestService(mock)
+
+	// Seeded in the Tower week, the next week starts the next Earth ID.
+	c := &cfg.Config{EarthID: 4, EarthStatus: EarthStatusTower}
+	phase, err := svc.Current(c, conquestAnchor.Add(earthPhaseLength+time.Hour), conquestAnchor)
+	if err != nil {
+		t.Fatalf("Current: %v", err)
+	}
+	if phase.EarthID != 5 || phase.Status != EarthStatusConquest {
+		t.Errorf("phase = earth %d status %d, want earth 5 Conquest", phase.EarthID, phase.Status)
+	}
+}
+
+func TestConquestService_Current_ScheduleError(t *testing.T) {
+	mock := &mockConquestRepo{scheduleErr: errors.New("db down")}
+	svc := newTestConquestService(mock)
+
+	if _, err := svc.Current(&cfg.Config{EarthStatus: 1}, conquestAnchor, conquestAnchor); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestConquestService_PaysRewardsOnce(t *testing.T) {
+	mock := &mockConquestRepo{placings: []ConquestRankEntry{
+		{MonsterID: 116, Place: 1, CharID: 1, Level: 300},
+		{MonsterID: 116, Place: 2, CharID: 2, Level: 200},
+		{MonsterID: 116, Place: 150, CharID: 3, Level: 5},
+		{MonsterID: 107, Place: 1, CharID: 2, Level: 90},
+	}}
+	svc := newTestConquestService(mock)
+	c := &cfg.Config{EarthID: 10, EarthStatus: 1, EarthRewards: []cfg.ConquestReward{
+		{PlaceFrom: 1, PlaceTo: 1, ItemType: 7, ItemID: 100, Quantity: 3},
+		{PlaceFrom: 1, PlaceTo: 100, ItemType: 7, ItemID: 200, Quantity: 1},
+	}}
+
+	// Still hunting: nothing is paid, and reading the phase never pays.
+	svc.Tick(c, conquestAnchor.Add(time.Hour), conquestAnchor)
+	if mock.payCalls != 0 {
+		t.Fatalf("paid during the Conquest phase")
+	}
+
+	pallone := conquestAnchor.Add(earthPhaseLength + time.Hour)
+	if _, err := svc.Current(c, pallone, conquestAnchor); err != nil {
+		t.Fatalf("Current: %v", err)
+	}
+	if mock.payCalls != 0 {
+		t.Fatalf("Current paid rewards")
+	}
+	svc.Tick(c, pallone, conquestAnchor)
+	if mock.payCalls != 1 || mock.paidEarth != 10 {
+		t.Fatalf("pay calls %d for earth %d, want 1 for earth 10", mock.payCalls, mock.paidEarth)
+	}
+	if len(mock.grants) != 3 {
+		t.Fatalf("grants = %+v, want 3", mock.grants)
+	}
+	if first := mock.grants[0]; first.CharID != 1 || len(first.Items) != 2 {
+		t.Errorf("first place grant = %+v, want both brackets", first)
+	}
+	if second := mock.grants[1]; second.CharID != 2 || len(second.Items) != 1 || second.Items[0].ItemID != 200 {
+		t.Errorf("second place grant = %+v, want the top-100 bracket", second)
+	}
+
+	svc.Tick(c, pallone.Add(time.Hour), conquestAnchor)
+	if mock.payCalls != 1 {
+		t.Errorf("pay calls = %d after a second tick, want 1", mock.payCalls)
+	}
+}
+
+func TestConquestService_PayRetriedAfterFailure(t *testing.T) {
+	mock := &mockConquestRepo{payErr: errors.New("db down")}
+	svc := newTestConquestService(mock)
+	c := &cfg.Config{EarthID: 10, EarthStatus: EarthStatusPallone}
+	now := conquestAnchor.Add(time.Hour)
+
+	svc.Tick(c, now, conquestAnchor)
+	mock.payErr = nil
+	svc.Tick(c, now, conquestAnchor)
+	if mock.payCalls != 2 || !mock.rewardPaid {
+		t.Errorf("pay calls = %d, paid %v; want a successful retry", mock.payCalls, mock.rewardPaid)
+	}
+}
+
+func TestConquestService_RecordLevels(t *testing.T) {
+	mock := &mockConquestRepo{}
+	svc := newTestConquestService(mock)
+	hunting := ConquestPhase{Enabled: true, EarthID: 3, Status: EarthStatusConquest}
+	monsters := []int32{116, 107, 0, 36}
+
+	if err := svc.RecordLevels(hunting, 1, monsters, []int32{50, 20000, 9, 0}); err != nil {
+		t.Fatalf("RecordLevels: %v", err)
+	}
+	if mock.setEarthID != 3 || len(mock.setLevels) != 2 ||
+		mock.setLevels[116] != 50 || mock.setLevels[107] != conquestMaxLevel {
+		t.Errorf("stored earth %d levels %v, want 116=50 and 107 capped", mock.setEarthID, mock.setLevels)
+	}
+
+	mock.setLevels = nil
+	tower := ConquestPhase{Enabled: true, EarthID: 3, Status: EarthStatusTower}
+	if err := svc.RecordLevels(tower, 1, monsters, []int32{60}); err != nil {
+		t.Fatalf("RecordLevels: %v", err)
+	}
+	if mock.setLevels != nil {
+		t.Error("levels recorded outside the Conquest phase")
+	}
+}
+
+func TestConquestPhase_LastConquest(t *testing.T) {
+	tests := []struct {
+		phase  ConquestPhase
+		want   int32
+		wantOK bool
+	}{
+		{ConquestPhase{}, 0, false},
+		{ConquestPhase{Enabled: true, EarthID: 5, Status: EarthStatusConquest}, 0, false},
+		{ConquestPhase{Enabled: true, EarthID: 5, Status: EarthStatusPallone, rotation: 1}, 5, true},
+		{ConquestPhase{Enabled: true, EarthID: 6, Status: EarthStatusConquest, rotation: 3}, 5, true},
+	}
+	for _, tt := range tests {
+		got, ok := tt.phase.LastConquest()
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%+v: got %d, %v; want %d, %v", tt.phase, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package channelserver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"erupe-ce/common/mhfcourse"
+
+	"go.uber.org/zap"
+)
+
+const (
+	distributionMaxNameLen        = 100 // Characters; the name is sent with a uint8 length prefix
+	distributionMaxDescriptionLen = 1000
+	distributionEligibleLimit     = 100
+	distributionEligibleMaxLimit  = 1000
+)
+
+var (
+	// ErrDistributionNotFound is returned when a distribution does not exist.
+	ErrDistributionNotFound = errors.New("distribution not found")
+	// ErrInvalidDistribution is returned when a distribution fails validation.
+	ErrInvalidDistribution = errors.New("invalid distribution")
+)
+
+// DistributionEligibility previews who a distribution is offered to.
+type DistributionEligibility struct {
+	Count      int                        `json:"count"`      // Characters who can still accept it
+	Characters []DistributionEligibleChar `json:"characters"` // The first of them by character ID
+}
+
+// DistributionService manages item distributions for the admin API and the
+// liveops tool.
+type DistributionService struct {
+	distRepo DistributionRepo
+	logger   *zap.Logger
+}
+
+// NewDistributionService creates a new DistributionService.
+func NewDistributionService(dr DistributionRepo, log *zap.Logger) *DistributionService {
+	return &DistributionService{
+		distRepo: dr,
+		logger:   log,
+	}
+}
+
+// List returns every distribution with its claim counts, newest first.
+func (svc *DistributionService) List() ([]DistributionCampaign, error) {
+	list, err := svc.distRepo.ListCampaigns()
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Courses = distributionCourses(list[i].Rights)
+	}
+	return list, nil
+}
+
+// Get returns a distribution with its items and claim counts.
+func (svc *DistributionService) Get(id uint32) (*DistributionCampaign, error) {
+	c, err := svc.distRepo.GetCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, fmt.Errorf("%w: %d", ErrDistributionNotFound, id)
+	}
+	c.Courses = distributionCourses(c.Rights)
+	return c, nil
+}
+
+// Create validates and stores a distribution.
+func (svc *DistributionService) Create(c DistributionCampaign) (*DistributionCampaign, error) {
+	if err := prepareDistribution(&c); err != nil {
+		return nil, err
+	}
+	id, err := svc.distRepo.CreateCampaign(c)
+	if err != nil {
+		return nil, err
+	}
+	svc.logger.Info("Distribution created", zap.Uint32("distributionID", id), zap.String("name", c.EventName))
+	return svc.Get(id)
+}
+
+// Update validates and replaces a distribution's settings and items.
+func (svc *DistributionService) Update(id uint32, c DistributionCampaign) (*DistributionCampaign, error) {
+	if err := prepareDistribution(&c); err != nil {
+		return nil, err
+	}
+	c.ID = id
+	ok, err := svc.distRepo.UpdateCampaign(c)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrDistributionNotFound, id)
+	}
+	return svc.Get(id)
+}
+
+// Expire ends a distribution at the given time, or leaves it alone if its
+// deadline is already earlier.
+func (svc *DistributionService) Expire(id uint32, at time.Time) (*DistributionCampaign, error) {
+	ok, err := svc.distRepo.ExpireCampaign(id, at)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrDistributionNotFound, id)
+	}
+	return svc.Get(id)
+}
+
+// Eligible previews which characters a distribution is offered to and can
+// still accept it, listing up to limit of them.
+func (svc *DistributionService) Eligible(id uint32, limit int) (*DistributionEligibility, error) {
+	if limit <= 0 {
+		limit = distributionEligibleLimit
+	}
+	if limit > distributionEligibleMaxLimit {
+		limit = distributionEligibleMaxLimit
+	}
+	if _, err := svc.Get(id); err != nil {
+		return nil, err
+	}
+	count, chars, err := svc.distRepo.Eligible(id, limit)
+	if err != nil {
+		return nil, err
+	}
+	if chars == nil {
+		chars = []DistributionEligibleChar{}
+	}
+	return &DistributionEligibility{Count: count, Characters: chars}, nil
+}
+
+// prepareDistribution validates c and sets its course rights.
+func prepareDistribution(c *DistributionCampaign) error {
+	name := utf8.RuneCountInString(c.EventName)
+	switch {
+	case name == 0 || name > distributionMaxNameLen:
+		return fmt.Errorf("%w: event name must be 1-%d characters", ErrInvalidDistribution, distributionMaxNameLen)
+	case c.Description == "" || utf8.RuneCountInString(c.Description) > distributionMaxDescriptionLen:
+		return fmt.Errorf("%w: description must be 1-%d characters", ErrInvalidDistribution, distributionMaxDescriptionLen)
+	case len(c.Items) == 0:
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidDistribution)
+	case c.StartsAt != nil && c.Deadline != nil && !c.StartsAt.Before(*c.Deadline):
+		return fmt.Errorf("%w: startsAt must be before deadline", ErrInvalidDistribution)
+	}
+	for _, r := range [][2]int16{{c.MinHR, c.MaxHR}, {c.MinSR, c.MaxSR}, {c.MinGR, c.MaxGR}} {
+		if r[0] < 0 || r[1] < 0 || (r[1] > 0 && r[0] > r[1]) {
+			return fmt.Errorf("%w: level range %d-%d", ErrInvalidDistribution, r[0], r[1])
+		}
+	}
+	for _, item := range c.Items {
+		if item.Quantity == 0 {
+			return fmt.Errorf("%w: item quantities must be positive", ErrInvalidDistribution)
+		}
+	}
+	if c.TimesAcceptable == 0 {
+		c.TimesAcceptable = 1
+	}
+	c.Rights = 0
+	for _, name := range c.Courses {
+		course, ok := mhfcourse.CourseByName(name)
+		if !ok {
+			return fmt.Errorf("%w: unknown course %q", ErrInvalidDistribution, name)
+		}
+		c.Rights |= course.Value()
+	}
+	return nil
+}
+
+// distributionCourses returns the primary name of every named course in
+// rights.
+func distributionCourses(rights uint32) []string {
+	var names []string
+	for _, c := range mhfcourse.Courses() {
+		if rights&c.Value() == 0 {
+			continue
+		}
+		if aliases := c.Aliases(); len(aliases) > 0 {
+			names = append(names, aliases[0])
+		}
+	}
+	return names
+}
@@ -0,0 +1,116 @@
+package channelserver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newTestDistributionCampaign() DistributionCampaign {
+	return DistributionCampaign{
+		Type:        1,
+		EventName:   "Login Bonus",
+		Description: "~C05Thanks for playing!",
+		Courses:     []string{"HunterLife", "EX"},
+		MinHR:       100,
+		CharIDs:     []uint32{1, 2},
+		Items:       []DistributionItem{{ItemType: 7, ItemID: 100, Quantity: 5}},
+	}
+}
+
+func TestDistributionService_Create(t *testing.T) {
+	repo := &mockDistRepo{}
+	svc := NewDistributionService(repo, zap.NewNop())
+
+	c, err := svc.Create(newTestDistributionCampaign())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ID != 1 || c.TimesAcceptable != 1 {
+		t.Errorf("created = %+v, want ID 1 accepted once", c)
+	}
+	if c.Rights != 1<<2|1<<3 {
+		t.Errorf("rights = %b, want HunterLife and Extra", c.Rights)
+	}
+	if len(c.Courses) != 2 || c.Courses[0] != "HunterLife" || c.Courses[1] != "Extra" {
+		t.Errorf("courses = %v", c.Courses)
+	}
+}
+
+func TestPrepareDistribution_Invalid(t *testing.T) {
+	start := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+	tests := []struct {
+		name   string
+		modify func(c *DistributionCampaign)
+	}{
+		{"no name", func(c *DistributionCampaign) { c.EventName = "" }},
+		{"no description", func(c *DistributionCampaign) { c.Description = "" }},
+		{"no items", func(c *DistributionCampaign) { c.Items = nil }},
+		{"zero quantity", func(c *DistributionCampaign) { c.Items[0].Quantity = 0 }},
+		{"inverted window", func(c *DistributionCampaign) { c.StartsAt, c.Deadline = &start, &end }},
+		{"inverted HR range", func(c *DistributionCampaign) { c.MaxHR = 50 }},
+		{"negative GR", func(c *DistributionCampaign) { c.MinGR = -1 }},
+		{"unknown course", func(c *DistributionCampaign) { c.Courses = []string{"Gold"} }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestDistributionCampaign()
+			tc.modify(&c)
+			if err := prepareDistribution(&c); !errors.Is(err, ErrInvalidDistribution) {
+				t.Errorf("err = %v, want ErrInvalidDistribution", err)
+			}
+		})
+	}
+}
+
+func TestDistributionService_UpdateAndExpire(t *testing.T) {
+	repo := &mockDistRepo{}
+	svc := NewDistributionService(repo, zap.NewNop())
+	created, _ := svc.Create(newTestDistributionCampaign())
+
+	edit := newTestDistributionCampaign()
+	edit.EventName = "Weekend Bonus"
+	edit.TimesAcceptable = 3
+	updated, err := svc.Update(created.ID, edit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.EventName != "Weekend Bonus" || updated.TimesAcceptable != 3 {
+		t.Errorf("updated = %+v", updated)
+	}
+	if _, err := svc.Update(99, edit); !errors.Is(err, ErrDistributionNotFound) {
+		t.Errorf("update missing: err = %v, want ErrDistributionNotFound", err)
+	}
+
+	at := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	expired, err := svc.Expire(created.ID, at)
+	if err != nil || expired.Deadline == nil || !expired.Deadline.Equal(at) {
+		t.Fatalf("Expire = %+v, %v", expired, err)
+	}
+	if again, _ := svc.Expire(created.ID, at.Add(time.Hour)); !again.Deadline.Equal(at) {
+		t.Errorf("later expiry moved the deadline to %v", again.Deadline)
+	}
+	if _, err := svc.Expire(99, at); !errors.Is(err, ErrDistributionNotFound) {
+		t.Errorf("expire missing: err = %v, want ErrDistributionNotFound", err)
+	}
+}
+
+func TestDistributionService_Eligible(t *testing.T) {
+	repo := &mockDistRepo{eligible: []DistributionEligibleChar{{ID: 1}, {ID: 2}, {ID: 3}}}
+	svc := NewDistributionService(repo, zap.NewNop())
+	created, _ := svc.Create(newTestDistributionCampaign())
+
+	preview, err := svc.Eligible(created.ID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Count != 3 || len(preview.Characters) != 2 {
+		t.Errorf("preview = %+v, want a count of 3 and 2 characters", preview)
+	}
+	if _, err := svc.Eligible(99, 0); !errors.Is(err, ErrDistributionNotFound) {
+		t.Errorf("err = %v, want ErrDistributionNotFound", err)
+	}
+}
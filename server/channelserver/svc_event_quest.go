@@ -0,0 +1,295 @@
+package channelserver
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// eventQuestTickInterval is how often the scheduler checks whether the live
+// set is due to be recomputed.
+const eventQuestTickInterval = time.Minute
+
+// eventQuestRotationHour is the hour cycling quests rotate at, aligned with
+// the in-game events update notification (12:00 JST).
+const eventQuestRotationHour = 12
+
+// eventQuestWeekends is the Weekdays mask for Saturday and Sunday.
+const eventQuestWeekends = 1<<time.Saturday | 1<<time.Sunday
+
+// EventQuestLive is an event quest that is live at a given time, as reported
+// by the preview API.
+type EventQuestLive struct {
+	ID             uint32     `json:"id"`
+	QuestID        int        `json:"questId"`
+	QuestType      uint8      `json:"questType"`
+	MaxPlayers     uint8      `json:"maxPlayers"`
+	ExclusiveGroup string     `json:"exclusiveGroup,omitempty"`
+	LiveUntil      *time.Time `json:"liveUntil,omitempty"` // End of the current cycle or date range, if any
+}
+
+// eventQuestPayloadKey identifies a compiled makeEventQuest payload. Quest
+// strings are localised, so payloads are cached per language.
+type eventQuestPayloadKey struct {
+	id   uint32
+	lang string
+}
+
+// EventQuestScheduler works out which event quests are live and caches their
+// compiled payloads. The live set only changes at rotations and rule
+// boundaries, so it is computed once per boundary rather than on every
+// MsgMhfEnumerateQuest.
+type EventQuestScheduler struct {
+	eventRepo EventRepo
+	logger    *zap.Logger
+
+	mu       sync.Mutex
+	loaded   bool
+	live     []EventQuest
+	next     time.Time // When the live set must be recomputed
+	payloads map[eventQuestPayloadKey][]byte
+}
+
+// NewEventQuestScheduler creates a new EventQuestScheduler.
+func NewEventQuestScheduler(er EventRepo, log *zap.Logger) *EventQuestScheduler {
+	return &EventQuestScheduler{
+		eventRepo: er,
+		logger:    log,
+		payloads:  make(map[eventQuestPayloadKey][]byte),
+	}
+}
+
+// Tick recomputes the live set if it has never been computed or a boundary
+// has passed.
+func (sch *EventQuestScheduler) Tick(now time.Time) {
+	sch.mu.Lock()
+	due := !sch.loaded || !now.Before(sch.next)
+	sch.mu.Unlock()
+	if due {
+		sch.Refresh(now)
+	}
+}
+
+// Refresh reloads the event quests, stores rotated cycle start times and
+// recomputes the live set, dropping the cached payloads.
+func (sch *EventQuestScheduler) Refresh(now time.Time) {
+	quests, err := sch.eventRepo.GetEventQuests()
+	if err != nil {
+		sch.logger.Error("Failed to load event quests", zap.Error(err))
+		return
+	}
+
+	var updates []EventQuestUpdate
+	for i, eq := range quests {
+		if eq.ActiveDays <= 0 {
+			continue
+		}
+		if start := eventQuestCycleStart(eq, now); !start.Equal(eq.StartTime) {
+			updates = append(updates, EventQuestUpdate{ID: eq.ID, StartTime: start})
+			quests[i].StartTime = start
+		}
+	}
+	if err := sch.eventRepo.UpdateEventQuestStartTimes(updates); err != nil {
+		sch.logger.Error("Failed to update event quest start times", zap.Error(err))
+	}
+
+	live := eventQuestsLiveAt(quests, now)
+	next := eventQuestNextBoundary(quests, now)
+
+	sch.mu.Lock()
+	sch.loaded = true
+	sch.live = live
+	sch.next = next
+	sch.payloads = make(map[eventQuestPayloadKey][]byte)
+	sch.mu.Unlock()
+
+	sch.logger.Debug("Event quests scheduled", zap.Int("live", len(live)), zap.Time("next", next))
+}
+
+// Live returns the event quests live at now, in quest ID order.
+func (sch *EventQuestScheduler) Live(now time.Time) []EventQuest {
+	sch.Tick(now)
+	sch.mu.Lock()
+	defer sch.mu.Unlock()
+	return append([]EventQuest(nil), sch.live...)
+}
+
+// Payload returns the makeEventQuest payload for eq, compiling and caching it
+// on first use. It returns nil if the quest cannot be served; the failure is
+// cached too, so it is only logged once per rotation. Nothing is cached while
+// QuestCacheExpiry disables the quest cache, so edited quest files show up
+// straight away.
+func (sch *EventQuestScheduler) Payload(s *Session, eq EventQuest) []byte {
+	key := eventQuestPayloadKey{eq.ID, s.Lang()}
+	caching := s.server.erupeConfig.QuestCacheExpiry > 0
+	sch.mu.Lock()
+	data, ok := sch.payloads[key]
+	sch.mu.Unlock()
+	if ok && caching {
+		if data != nil && eq.QuestType == QuestTypeSpecialTool {
+			// Whether a special tool quest is unlocked depends on the
+			// character's campaign stamps.
+			data = append([]byte(nil), data...)
+			data[eventQuestUnlockedOffset] = boolByte(eventQuestUnlocked(s, eq))
+		}
+		return data
+	}
+
+	data, err := makeEventQuest(s, eq)
+	if err != nil {
+		s.logger.Error("Failed to make event quest", zap.Error(err))
+		data = nil
+	} else if len(data) > questDataMaxLen || len(data) < questDataMinLen {
+		s.logger.Error("Invalid quest data length", zap.Int("len", len(data)), zap.Int("questID", eq.QuestID))
+		data = nil
+	}
+	if caching {
+		sch.mu.Lock()
+		sch.payloads[key] = data
+		sch.mu.Unlock()
+	}
+	return data
+}
+
+// Preview returns the event quests that will be live at the given time. It
+// does not change any stored state.
+func (sch *EventQuestScheduler) Preview(at time.Time) ([]EventQuestLive, error) {
+	quests, err := sch.eventRepo.GetEventQuests()
+	if err != nil {
+		return nil, err
+	}
+	for i, eq := range quests {
+		if eq.ActiveDays > 0 {
+			quests[i].StartTime = eventQuestCycleStart(eq, at)
+		}
+	}
+	live := eventQuestsLiveAt(quests, at)
+	preview := make([]EventQuestLive, 0, len(live))
+	for _, eq := range live {
+		preview = append(preview, EventQuestLive{
+			ID:             eq.ID,
+			QuestID:        eq.QuestID,
+			QuestType:      eq.QuestType,
+			MaxPlayers:     eq.MaxPlayers,
+			ExclusiveGroup: eq.ExclusiveGroup,
+			LiveUntil:      eventQuestLiveUntil(eq),
+		})
+	}
+	return preview, nil
+}
+
+// eventQuestCycleStart returns the start of the cycle eq is in at now. Start
+// times are moved forward by whole cycles and normalised to 12:00.
+func eventQuestCycleStart(eq EventQuest, now time.Time) time.Time {
+	cycle := time.Duration(eq.ActiveDays+eq.InactiveDays) * 24 * time.Hour
+	extraCycles := int(now.Sub(eq.StartTime) / cycle)
+	if extraCycles <= 0 {
+		return eq.StartTime
+	}
+	rotation := eq.StartTime.Add(cycle * time.Duration(extraCycles))
+	return time.Date(rotation.Year(), rotation.Month(), rotation.Day(), eventQuestRotationHour, 0, 0, 0, TimeAdjusted().Location())
+}
+
+// eventQuestEligible reports whether eq's cycle and rules allow it at now,
+// before exclusive groups are resolved. eq.StartTime must already be the
+// start of the current cycle.
+func eventQuestEligible(eq EventQuest, now time.Time) bool {
+	if eq.ActiveDays > 0 {
+		if now.Before(eq.StartTime) || now.After(eq.StartTime.Add(time.Duration(eq.ActiveDays)*24*time.Hour)) {
+			return false
+		}
+	}
+	if eq.AvailableFrom != nil && now.Before(*eq.AvailableFrom) {
+		return false
+	}
+	if eq.AvailableUntil != nil && !now.Before(*eq.AvailableUntil) {
+		return false
+	}
+	day := uint8(1) << now.In(TimeAdjusted().Location()).Weekday()
+	if eq.Weekdays != 0 && eq.Weekdays&day == 0 {
+		return false
+	}
+	if eq.WeekendsOnly && eventQuestWeekends&day == 0 {
+		return false
+	}
+	return true
+}
+
+// eventQuestsLiveAt returns the quests live at now. Of the eligible quests
+// sharing an exclusive group, one is live per day, rotating in ID order.
+func eventQuestsLiveAt(quests []EventQuest, now time.Time) []EventQuest {
+	var live []EventQuest
+	groups := make(map[string][]EventQuest)
+	for _, eq := range quests {
+		if !eventQuestEligible(eq, now) {
+			continue
+		}
+		if eq.ExclusiveGroup == "" {
+			live = append(live, eq)
+		} else {
+			groups[eq.ExclusiveGroup] = append(groups[eq.ExclusiveGroup], eq)
+		}
+	}
+	local := now.In(TimeAdjusted().Location())
+	day := int(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+	for _, members := range groups {
+		sort.Slice(members, func(i, j int) bool { return members[i].ID < members[j].ID })
+		live = append(live, members[day%len(members)])
+	}
+	sort.SliceStable(live, func(i, j int) bool { return live[i].QuestID < live[j].QuestID })
+	return live
+}
+
+// eventQuestNextBoundary returns the next time the live set may change:
+// the next midnight or rotation hour, or an earlier rule boundary.
+func eventQuestNextBoundary(quests []EventQuest, now time.Time) time.Time {
+	local := now.In(TimeAdjusted().Location())
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	next := midnight.Add(24 * time.Hour)
+	if rotation := midnight.Add(eventQuestRotationHour * time.Hour); rotation.After(now) {
+		next = rotation
+	}
+	consider := func(t time.Time) {
+		if t.After(now) && t.Before(next) {
+			next = t
+		}
+	}
+	for _, eq := range quests {
+		if eq.ActiveDays > 0 {
+			consider(eq.StartTime)
+			consider(eq.StartTime.Add(time.Duration(eq.ActiveDays) * 24 * time.Hour))
+		}
+		if eq.AvailableFrom != nil {
+			consider(*eq.AvailableFrom)
+		}
+		if eq.AvailableUntil != nil {
+			consider(*eq.AvailableUntil)
+		}
+	}
+	return next
+}
+
+// eventQuestLiveUntil returns when eq stops being live through its cycle or
+// date range, or nil if neither ends it.
+func eventQuestLiveUntil(eq EventQuest) *time.Time {
+	var until *time.Time
+	if eq.ActiveDays > 0 {
+		end := eq.StartTime.Add(time.Duration(eq.ActiveDays) * 24 * time.Hour)
+		until = &end
+	}
+	if eq.AvailableUntil != nil && (until == nil || eq.AvailableUntil.Before(*until)) {
+		end := *eq.AvailableUntil
+		until = &end
+	}
+	return until
+}
+
+// boolByte returns 1 for true and 0 for false.
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,185 @@
+package channelserver
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var jst = time.FixedZone("UTC+9", 9*60*60)
+
+func TestEventQuestCycleStart(t *testing.T) {
+	eq := EventQuest{
+		StartTime:    time.Date(2026, 10, 1, 12, 0, 0, 0, jst),
+		ActiveDays:   3,
+		InactiveDays: 4,
+	}
+
+	start := eventQuestCycleStart(eq, time.Date(2026, 10, 16, 15, 0, 0, 0, jst))
+	if want := time.Date(2026, 10, 15, 12, 0, 0, 0, jst); !start.Equal(want) {
+		t.Fatalf("cycle start = %v, want %v", start, want)
+	}
+	eq.StartTime = start
+	if !eventQuestEligible(eq, time.Date(2026, 10, 16, 15, 0, 0, 0, jst)) {
+		t.Error("quest not live during its active days")
+	}
+	if eventQuestEligible(eq, time.Date(2026, 10, 19, 15, 0, 0, 0, jst)) {
+		t.Error("quest live during its inactive days")
+	}
+
+	before := EventQuest{StartTime: time.Date(2026, 11, 1, 12, 0, 0, 0, jst), ActiveDays: 1}
+	if start := eventQuestCycleStart(before, time.Date(2026, 10, 16, 0, 0, 0, 0, jst)); !start.Equal(before.StartTime) {
+		t.Errorf("quest that has not started was rotated to %v", start)
+	}
+}
+
+func TestEventQuestsLiveAt_Rules(t *testing.T) {
+	from := time.Date(2026, 10, 10, 0, 0, 0, 0, jst)
+	until := time.Date(2026, 10, 20, 0, 0, 0, 0, jst)
+	quests := []EventQuest{
+		{ID: 1, QuestID: 100},
+		{ID: 2, QuestID: 200, Weekdays: 1 << time.Saturday},
+		{ID: 3, QuestID: 300, Weekdays: 1<<time.Monday | 1<<time.Tuesday},
+		{ID: 4, QuestID: 400, WeekendsOnly: true},
+		{ID: 5, QuestID: 500, AvailableFrom: &from, AvailableUntil: &until},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want []int
+	}{
+		{"saturday in range", time.Date(2026, 10, 17, 9, 0, 0, 0, jst), []int{100, 200, 400, 500}},
+		{"monday in range", time.Date(2026, 10, 19, 9, 0, 0, 0, jst), []int{100, 300, 500}},
+		{"after range", time.Date(2026, 10, 20, 9, 0, 0, 0, jst), []int{100, 300}},
+		{"before range", time.Date(2026, 10, 8, 9, 0, 0, 0, jst), []int{100}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			live := eventQuestsLiveAt(quests, tc.at)
+			var got []int
+			for _, eq := range live {
+				got = append(got, eq.QuestID)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("live = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("live = %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEventQuestsLiveAt_ExclusiveGroup(t *testing.T) {
+	quests := []EventQuest{
+		{ID: 1, QuestID: 100, ExclusiveGroup: "hunt"},
+		{ID: 2, QuestID: 200, ExclusiveGroup: "hunt"},
+		{ID: 3, QuestID: 300, ExclusiveGroup: "hunt", Weekdays: 1 << time.Sunday},
+		{ID: 4, QuestID: 400},
+	}
+
+	seen := make(map[int]bool)
+	for day := 0; day < 6; day++ {
+		// 2026-10-12 is a Monday, so the Sunday-only member never qualifies.
+		at := time.Date(2026, 10, 12+day, 13, 0, 0, 0, jst)
+		live := eventQuestsLiveAt(quests, at)
+		if len(live) != 2 {
+			t.Fatalf("day %d: live = %+v, want one group member and the ungrouped quest", day, live)
+		}
+		for _, eq := range live {
+			if eq.ExclusiveGroup == "hunt" {
+				seen[eq.QuestID] = true
+			}
+		}
+	}
+	if !seen[100] || !seen[200] || seen[300] {
+		t.Errorf("group members live over the week = %v, want 100 and 200", seen)
+	}
+}
+
+func TestEventQuestNextBoundary(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, jst)
+	if next := eventQuestNextBoundary(nil, now); !next.Equal(time.Date(2026, 10, 17, 12, 0, 0, 0, jst)) {
+		t.Errorf("next = %v, want today's rotation", next)
+	}
+	if next := eventQuestNextBoundary(nil, now.Add(4*time.Hour)); !next.Equal(time.Date(2026, 10, 18, 0, 0, 0, 0, jst)) {
+		t.Errorf("next = %v, want midnight", next)
+	}
+	until := time.Date(2026, 10, 17, 10, 30, 0, 0, jst)
+	if next := eventQuestNextBoundary([]EventQuest{{AvailableUntil: &until}}, now); !next.Equal(until) {
+		t.Errorf("next = %v, want the end of the date range", next)
+	}
+}
+
+func TestEventQuestScheduler_RefreshStoresRotations(t *testing.T) {
+	repo := &mockEventRepo{eventQuests: []EventQuest{
+		{ID: 1, QuestID: 100, StartTime: time.Date(2026, 10, 1, 12, 0, 0, 0, jst), ActiveDays: 1, InactiveDays: 1},
+		{ID: 2, QuestID: 200, StartTime: time.Date(2026, 10, 2, 12, 0, 0, 0, jst), ActiveDays: 1, InactiveDays: 1},
+	}}
+	sch := NewEventQuestScheduler(repo, zap.NewNop())
+	now := time.Date(2026, 10, 17, 13, 0, 0, 0, jst)
+
+	live := sch.Live(now)
+	if len(live) != 1 || live[0].ID != 1 {
+		t.Fatalf("live = %+v, want quest 1", live)
+	}
+	if len(repo.questUpdates) != 2 {
+		t.Fatalf("updates = %+v, want both quests rotated", repo.questUpdates)
+	}
+
+	// A second request before the next boundary uses the computed set.
+	repo.eventQuests = nil
+	if live := sch.Live(now.Add(time.Hour)); len(live) != 1 {
+		t.Errorf("live set recomputed before the boundary: %+v", live)
+	}
+	if live := sch.Live(time.Date(2026, 10, 18, 0, 0, 0, 0, jst)); len(live) != 0 {
+		t.Errorf("live set not recomputed at the boundary: %+v", live)
+	}
+}
+
+func TestEventQuestScheduler_Preview(t *testing.T) {
+	repo := &mockEventRepo{eventQuests: []EventQuest{
+		{ID: 1, QuestID: 100, MaxPlayers: 4, StartTime: time.Date(2026, 10, 1, 12, 0, 0, 0, jst), ActiveDays: 1, InactiveDays: 1},
+		{ID: 2, QuestID: 200, WeekendsOnly: true},
+	}}
+	sch := NewEventQuestScheduler(repo, zap.NewNop())
+
+	live, err := sch.Preview(time.Date(2026, 10, 24, 13, 0, 0, 0, jst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(live) != 1 || live[0].QuestID != 200 || live[0].LiveUntil != nil {
+		t.Fatalf("Saturday preview = %+v, want only the weekend quest", live)
+	}
+	live, _ = sch.Preview(time.Date(2026, 10, 27, 13, 0, 0, 0, jst))
+	if len(live) != 1 || live[0].QuestID != 100 || live[0].LiveUntil == nil {
+		t.Fatalf("Tuesday preview = %+v, want the cycling quest", live)
+	}
+	if !live[0].LiveUntil.Equal(time.Date(2026, 10, 28, 12, 0, 0, 0, jst)) {
+		t.Errorf("LiveUntil = %v", live[0].LiveUntil)
+	}
+	if len(repo.questUpdates) != 0 {
+		t.Errorf("preview stored rotations: %+v", repo.questUpdates)
+	}
+}
+
+func TestEventQuestScheduler_PayloadCachesFailures(t *testing.T) {
+	server := createMockServer()
+	server.erupeConfig.BinPath = t.TempDir()
+	server.erupeConfig.QuestCacheExpiry = 60
+	server.questCache = NewQuestCache(0)
+	session := createMockSession(1, server)
+	sch := NewEventQuestScheduler(&mockEventRepo{}, zap.NewNop())
+	eq := EventQuest{ID: 9, QuestID: 99999}
+
+	if data := sch.Payload(session, eq); data != nil {
+		t.Fatalf("payload for a missing quest file = %x", data)
+	}
+	if _, ok := sch.payloads[eventQuestPayloadKey{9, session.Lang()}]; !ok {
+		t.Error("failed payload was not cached")
+	}
+}
@@ -1,11 +1,63 @@
 package channelserver
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"go.uber.org/zap"
 )
 
+// FestaPhase is a stage of the Mezeporta Festival cycle.
+type FestaPhase string
+
+const (
+	FestaPhaseNone         FestaPhase = "none"         // No festival scheduled
+	FestaPhaseScheduled    FestaPhase = "scheduled"    // Registration has not opened yet
+	FestaPhaseRegistration FestaPhase = "registration" // Guilds join a team
+	FestaPhaseSoulRace     FestaPhase = "soul_race"    // Souls are charged to the teams
+	FestaPhaseIntermediate FestaPhase = "intermediate" // Race judged, results announced
+	FestaPhaseFinal        FestaPhase = "final"        // Final prizes can be claimed
+	FestaPhaseEnded        FestaPhase = "ended"        // Prize period over, waiting to close out
+)
+
+const (
+	festaTickInterval   = time.Minute
+	festaHistoryMaxRows = 100
+)
+
+var (
+	// ErrInvalidFesta is returned when a trial or prize fails validation.
+	ErrInvalidFesta = errors.New("invalid festa entry")
+	// ErrFestaHistoryNotFound is returned for an unknown festival history ID.
+	ErrFestaHistoryNotFound = errors.New("festa history not found")
+	// ErrFestaTrialNotFound is returned for an unknown trial ID.
+	ErrFestaTrialNotFound = errors.New("festa trial not found")
+	// ErrFestaPrizeNotFound is returned for an unknown prize ID.
+	ErrFestaPrizeNotFound = errors.New("festa prize not found")
+)
+
+// FestaStatus describes the current festival for the admin API. The
+// timestamps are zero when no festival is scheduled.
+type FestaStatus struct {
+	EventID           uint32        `json:"eventId"`
+	Phase             FestaPhase    `json:"phase"`
+	RegistrationStart uint32        `json:"registrationStart"`
+	SoulRaceStart     uint32        `json:"soulRaceStart"`
+	SoulRaceEnd       uint32        `json:"soulRaceEnd"`
+	FinalStart        uint32        `json:"finalStart"`
+	End               uint32        `json:"end"`
+	BlueSouls         uint32        `json:"blueSouls"`
+	RedSouls          uint32        `json:"redSouls"`
+	Leader            FestivalColor `json:"leader"`
+}
+
+// FestaHistoryDetail is a past festival with its guild results.
+type FestaHistoryDetail struct {
+	FestaResult
+	Guilds []FestaHistoryGuild `json:"guilds"`
+}
+
 // FestaService encapsulates festa business logic, sitting between handlers and repos.
 type FestaService struct {
 	festaRepo FestaRepo
@@ -29,6 +81,15 @@ func (svc *FestaService) EnsureActiveEvent(currentStart uint32, now time.Time, n
 		return currentStart, nil
 	}
 
+	// Keep the expired festival's results before its state is cleared.
+	if currentStart != 0 {
+		if events, err := svc.festaRepo.GetFestaEvents(); err == nil {
+			for _, e := range events {
+				svc.closeOut(e, 0)
+			}
+		}
+	}
+
 	if err := svc.festaRepo.CleanupAll(); err != nil {
 		svc.logger.Error("Failed to cleanup festa", zap.Error(err))
 		return 0, err
@@ -58,3 +119,291 @@ func (svc *FestaService) SubmitSouls(charID, guildID uint32, souls []uint16) err
 	}
 	return svc.festaRepo.SubmitSouls(charID, guildID, souls)
 }
+
+// festaTimestamps returns the phase boundaries of a festival starting at
+// start: registration, soul race, race end, final prizes and end.
+func festaTimestamps(start uint32) []uint32 {
+	timestamps := make([]uint32, 5)
+	timestamps[0] = start
+	timestamps[1] = timestamps[0] + secsPerWeek
+	timestamps[2] = timestamps[1] + secsPerWeek
+	timestamps[3] = timestamps[2] + festaVotingDuration
+	timestamps[4] = timestamps[3] + festaRewardDuration
+	return timestamps
+}
+
+// festaPhaseAt returns the phase of a festival starting at start.
+func festaPhaseAt(start uint32, now int64) FestaPhase {
+	if start == 0 {
+		return FestaPhaseNone
+	}
+	timestamps := festaTimestamps(start)
+	switch {
+	case now < int64(timestamps[0]):
+		return FestaPhaseScheduled
+	case now < int64(timestamps[1]):
+		return FestaPhaseRegistration
+	case now < int64(timestamps[2]):
+		return FestaPhaseSoulRace
+	case now < int64(timestamps[3]):
+		return FestaPhaseIntermediate
+	case now < int64(timestamps[4]):
+		return FestaPhaseFinal
+	}
+	return FestaPhaseEnded
+}
+
+// festaWinner returns the team with more souls, or none on a tie.
+func festaWinner(blue, red uint32) FestivalColor {
+	switch {
+	case blue > red:
+		return FestivalColorBlue
+	case red > blue:
+		return FestivalColorRed
+	}
+	return FestivalColorNone
+}
+
+// nextFestaStart returns when the festival after one starting at start
+// should open registration: the first midnight at least restDays after its
+// prize period ends, and never earlier than the next midnight after now.
+func nextFestaStart(start uint32, restDays int, now time.Time) uint32 {
+	end := time.Unix(int64(festaTimestamps(start)[4])+int64(restDays)*int64(secsPerDay), 0).In(now.Location())
+	next := festaMidnightAfter(end)
+	if earliest := festaMidnightAfter(now); next.Before(earliest) {
+		next = earliest
+	}
+	return uint32(next.Unix())
+}
+
+// festaMidnightAfter returns the first midnight at or after t, in t's location.
+func festaMidnightAfter(t time.Time) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if midnight.Before(t) {
+		midnight = midnight.Add(24 * time.Hour)
+	}
+	return midnight
+}
+
+// currentFestaEvent returns the latest scheduled festa event, if any.
+func currentFestaEvent(events []FestaEvent) (FestaEvent, bool) {
+	var current FestaEvent
+	for _, e := range events {
+		if e.StartTime >= current.StartTime {
+			current = e
+		}
+	}
+	return current, len(events) > 0
+}
+
+// Tick advances the festival cycle: it schedules a festival when none
+// exists, judges the soul race once it ends, and closes the festival out
+// after the prize period, scheduling the next one restDays later. Each step
+// is guarded in the database, so every channel may tick.
+func (svc *FestaService) Tick(now time.Time, restDays int) {
+	events, err := svc.festaRepo.GetFestaEvents()
+	if err != nil {
+		svc.logger.Error("Failed to load festa schedule", zap.Error(err))
+		return
+	}
+	event, ok := currentFestaEvent(events)
+	if !ok {
+		start := uint32(festaMidnightAfter(now).Unix())
+		inserted, err := svc.festaRepo.InsertEventIfNone(start)
+		if err != nil {
+			svc.logger.Error("Failed to schedule festa", zap.Error(err))
+		} else if inserted {
+			svc.logger.Info("Festa scheduled", zap.Uint32("start", start))
+		}
+		return
+	}
+
+	switch festaPhaseAt(event.StartTime, now.Unix()) {
+	case FestaPhaseIntermediate, FestaPhaseFinal:
+		svc.judge(event)
+	case FestaPhaseEnded:
+		svc.closeOut(event, nextFestaStart(event.StartTime, restDays, now))
+	}
+}
+
+// tally reads the team soul totals for a festa event.
+func (svc *FestaService) tally(event FestaEvent) (FestaResult, error) {
+	result := FestaResult{EventID: event.ID, StartTime: int64(event.StartTime)}
+	var err error
+	if result.BlueSouls, err = svc.festaRepo.GetTeamSouls(string(FestivalColorBlue)); err != nil {
+		return result, err
+	}
+	if result.RedSouls, err = svc.festaRepo.GetTeamSouls(string(FestivalColorRed)); err != nil {
+		return result, err
+	}
+	result.Winner = festaWinner(result.BlueSouls, result.RedSouls)
+	return result, nil
+}
+
+// judge records the soul race result of a festa event once.
+func (svc *FestaService) judge(event FestaEvent) {
+	existing, err := svc.festaRepo.GetResultByEvent(event.ID)
+	if err != nil || existing != nil {
+		if err != nil {
+			svc.logger.Error("Failed to load festa result", zap.Error(err))
+		}
+		return
+	}
+	result, err := svc.tally(event)
+	if err != nil {
+		svc.logger.Error("Failed to tally festa souls", zap.Error(err))
+		return
+	}
+	recorded, err := svc.festaRepo.RecordResult(result)
+	if err != nil {
+		svc.logger.Error("Failed to record festa result", zap.Error(err))
+		return
+	}
+	if recorded {
+		svc.logger.Info("Festa soul race judged", zap.Uint32("eventID", event.ID),
+			zap.String("winner", string(result.Winner)),
+			zap.Uint32("blueSouls", result.BlueSouls), zap.Uint32("redSouls", result.RedSouls))
+	}
+}
+
+// closeOut archives a festa event and clears its state, scheduling the next
+// festival at nextStart unless it is zero.
+func (svc *FestaService) closeOut(event FestaEvent, nextStart uint32) {
+	result, err := svc.tally(event)
+	if err != nil {
+		svc.logger.Error("Failed to tally festa souls", zap.Error(err))
+		return
+	}
+	closed, err := svc.festaRepo.CloseOutEvent(result, nextStart)
+	if err != nil {
+		svc.logger.Error("Failed to close out festa", zap.Error(err), zap.Uint32("eventID", event.ID))
+		return
+	}
+	if closed {
+		svc.logger.Info("Festa closed out", zap.Uint32("eventID", event.ID),
+			zap.String("winner", string(result.Winner)), zap.Uint32("nextStart", nextStart))
+	}
+}
+
+// Status returns the current festival's phase, schedule and soul totals.
+func (svc *FestaService) Status(now time.Time) (*FestaStatus, error) {
+	events, err := svc.festaRepo.GetFestaEvents()
+	if err != nil {
+		return nil, err
+	}
+	status := &FestaStatus{Phase: FestaPhaseNone, Leader: FestivalColorNone}
+	event, ok := currentFestaEvent(events)
+	if !ok {
+		return status, nil
+	}
+	result, err := svc.tally(event)
+	if err != nil {
+		return nil, err
+	}
+	timestamps := festaTimestamps(event.StartTime)
+	status.EventID = event.ID
+	status.Phase = festaPhaseAt(event.StartTime, now.Unix())
+	status.RegistrationStart = timestamps[0]
+	status.SoulRaceStart = timestamps[1]
+	status.SoulRaceEnd = timestamps[2]
+	status.FinalStart = timestamps[3]
+	status.End = timestamps[4]
+	status.BlueSouls = result.BlueSouls
+	status.RedSouls = result.RedSouls
+	status.Leader = result.Winner
+	return status, nil
+}
+
+// History returns up to limit past festivals, latest first.
+func (svc *FestaService) History(limit int) ([]FestaResult, error) {
+	if limit <= 0 || limit > festaHistoryMaxRows {
+		limit = festaHistoryMaxRows
+	}
+	return svc.festaRepo.ListHistory(limit)
+}
+
+// HistoryDetail returns a past festival with its guild results.
+func (svc *FestaService) HistoryDetail(id uint32) (*FestaHistoryDetail, error) {
+	result, err := svc.festaRepo.GetHistory(id)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, ErrFestaHistoryNotFound
+	}
+	guilds, err := svc.festaRepo.GetHistoryGuilds(id)
+	if err != nil {
+		return nil, err
+	}
+	if guilds == nil {
+		guilds = []FestaHistoryGuild{}
+	}
+	return &FestaHistoryDetail{FestaResult: *result, Guilds: guilds}, nil
+}
+
+// Trials returns every festa trial with its current monopoly.
+func (svc *FestaService) Trials() ([]FestaTrial, error) {
+	return svc.festaRepo.GetTrialsWithMonopoly()
+}
+
+// AddTrial validates and stores a festa trial.
+func (svc *FestaService) AddTrial(trial FestaTrial) (*FestaTrial, error) {
+	if trial.Objective == 0 {
+		return nil, fmt.Errorf("%w: objective is required", ErrInvalidFesta)
+	}
+	if trial.TimesReq == 0 {
+		return nil, fmt.Errorf("%w: timesReq must be positive", ErrInvalidFesta)
+	}
+	id, err := svc.festaRepo.CreateTrial(trial)
+	if err != nil {
+		return nil, err
+	}
+	trial.ID = id
+	trial.Monopoly = FestivalColorNone
+	return &trial, nil
+}
+
+// RemoveTrial deletes a festa trial and the votes cast for it.
+func (svc *FestaService) RemoveTrial(id uint32) error {
+	ok, err := svc.festaRepo.DeleteTrial(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrFestaTrialNotFound
+	}
+	return nil
+}
+
+// Prizes returns every personal and guild festa prize.
+func (svc *FestaService) Prizes() ([]FestaPrize, error) {
+	return svc.festaRepo.ListAllPrizes()
+}
+
+// AddPrize validates and stores a festa prize.
+func (svc *FestaService) AddPrize(prize FestaPrize) (*FestaPrize, error) {
+	if prize.Type != "personal" && prize.Type != "guild" {
+		return nil, fmt.Errorf("%w: type must be personal or guild", ErrInvalidFesta)
+	}
+	if prize.ItemID == 0 || prize.NumItem == 0 {
+		return nil, fmt.Errorf("%w: itemId and numItem are required", ErrInvalidFesta)
+	}
+	id, err := svc.festaRepo.CreatePrize(prize)
+	if err != nil {
+		return nil, err
+	}
+	prize.ID = id
+	return &prize, nil
+}
+
+// RemovePrize deletes a festa prize and its claim records.
+func (svc *FestaService) RemovePrize(id uint32) error {
+	ok, err := svc.festaRepo.DeletePrize(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrFestaPrizeNotFound
+	}
+	return nil
+}
@@ -136,3 +136,184 @@ func TestFestaService_SubmitSouls_RepoError(t *testing.T) {
 		t.Fatal("expected error from repo failure")
 	}
 }
+
+// --- Scheduler tests ---
+
+func TestFestaPhaseAt(t *testing.T) {
+	start := uint32(1000000)
+	ts := festaTimestamps(start)
+	tests := []struct {
+		now  int64
+		want FestaPhase
+	}{
+		{int64(start) - 1, FestaPhaseScheduled},
+		{int64(start), FestaPhaseRegistration},
+		{int64(ts[1]), FestaPhaseSoulRace},
+		{int64(ts[2]), FestaPhaseIntermediate},
+		{int64(ts[3]), FestaPhaseFinal},
+		{int64(ts[4]), FestaPhaseEnded},
+	}
+	for _, tt := range tests {
+		if got := festaPhaseAt(start, tt.now); got != tt.want {
+			t.Errorf("festaPhaseAt(%d) = %s, want %s", tt.now, got, tt.want)
+		}
+	}
+	if got := festaPhaseAt(0, 5); got != FestaPhaseNone {
+		t.Errorf("festaPhaseAt(no event) = %s, want none", got)
+	}
+}
+
+func TestFestaWinner(t *testing.T) {
+	if festaWinner(10, 5) != FestivalColorBlue || festaWinner(5, 10) != FestivalColorRed || festaWinner(7, 7) != FestivalColorNone {
+		t.Error("festaWinner picked the wrong team")
+	}
+}
+
+func TestNextFestaStart(t *testing.T) {
+	jst := time.FixedZone("UTC+9", 9*60*60)
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, jst)
+	end := time.Unix(int64(festaTimestamps(uint32(start.Unix()))[4]), 0).In(jst)
+
+	next := time.Unix(int64(nextFestaStart(uint32(start.Unix()), 6, end)), 0).In(jst)
+	if next.Hour() != 0 || next.Minute() != 0 || next.Before(end.AddDate(0, 0, 6)) || next.After(end.AddDate(0, 0, 7)) {
+		t.Errorf("next start = %v, want the first midnight 6 days after %v", next, end)
+	}
+
+	// A server that was down past the rest period opens at the next midnight.
+	late := end.AddDate(0, 1, 0)
+	next = time.Unix(int64(nextFestaStart(uint32(start.Unix()), 6, late)), 0).In(jst)
+	if !next.After(late) || next.Sub(late) > 24*time.Hour {
+		t.Errorf("late next start = %v, want the midnight after %v", next, late)
+	}
+}
+
+func TestFestaService_Tick_SchedulesWhenNone(t *testing.T) {
+	mock := &mockFestaRepo{}
+	svc := newTestFestaService(mock)
+
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	svc.Tick(now, 6)
+	if want := uint32(time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC).Unix()); mock.insertedIfNone != want {
+		t.Errorf("scheduled start = %d, want %d", mock.insertedIfNone, want)
+	}
+}
+
+func TestFestaService_Tick_JudgesSoulRace(t *testing.T) {
+	start := uint32(1000000)
+	mock := &mockFestaRepo{events: []FestaEvent{{ID: 4, StartTime: start}}, teamSouls: 50}
+	svc := newTestFestaService(mock)
+
+	svc.Tick(time.Unix(int64(festaTimestamps(start)[2])+60, 0), 6)
+	if mock.recorded == nil || mock.recorded.EventID != 4 || mock.recorded.Winner != FestivalColorNone {
+		t.Fatalf("recorded = %+v, want a tied result for event 4", mock.recorded)
+	}
+
+	mock.recorded = nil
+	mock.result = &FestaResult{EventID: 4}
+	svc.Tick(time.Unix(int64(festaTimestamps(start)[3])+60, 0), 6)
+	if mock.recorded != nil {
+		t.Error("an already judged festival should not be judged again")
+	}
+
+	svc.Tick(time.Unix(int64(start)+60, 0), 6)
+	if mock.closedOut != nil {
+		t.Error("a festival in registration should not be closed out")
+	}
+}
+
+func TestFestaService_Tick_ClosesOut(t *testing.T) {
+	start := uint32(1000000)
+	mock := &mockFestaRepo{events: []FestaEvent{{ID: 4, StartTime: start}}, closeOutOK: true}
+	svc := newTestFestaService(mock)
+
+	now := time.Unix(int64(festaTimestamps(start)[4])+60, 0)
+	svc.Tick(now, 6)
+	if mock.closedOut == nil || mock.closedOut.EventID != 4 {
+		t.Fatalf("closedOut = %+v, want event 4", mock.closedOut)
+	}
+	if mock.nextStart != nextFestaStart(start, 6, now) {
+		t.Errorf("next start = %d, want %d", mock.nextStart, nextFestaStart(start, 6, now))
+	}
+}
+
+func TestFestaService_EnsureActiveEvent_ArchivesExpired(t *testing.T) {
+	mock := &mockFestaRepo{events: []FestaEvent{{ID: 2, StartTime: 1}}, closeOutOK: true}
+	svc := newTestFestaService(mock)
+
+	now := time.Unix(10000000, 0)
+	if _, err := svc.EnsureActiveEvent(1, now, now.Add(24*time.Hour)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mock.closedOut == nil || mock.closedOut.EventID != 2 || mock.nextStart != 0 {
+		t.Errorf("closedOut = %+v next %d, want event 2 archived without scheduling", mock.closedOut, mock.nextStart)
+	}
+}
+
+// --- Admin tests ---
+
+func TestFestaService_Status(t *testing.T) {
+	start := uint32(1000000)
+	mock := &mockFestaRepo{events: []FestaEvent{{ID: 1, StartTime: start - 100}, {ID: 4, StartTime: start}}, teamSouls: 30}
+	svc := newTestFestaService(mock)
+
+	status, err := svc.Status(time.Unix(int64(festaTimestamps(start)[1])+10, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.EventID != 4 || status.Phase != FestaPhaseSoulRace || status.BlueSouls != 30 || status.End != festaTimestamps(start)[4] {
+		t.Errorf("status = %+v", status)
+	}
+
+	status, _ = newTestFestaService(&mockFestaRepo{}).Status(time.Now())
+	if status.Phase != FestaPhaseNone {
+		t.Errorf("phase with no event = %s, want none", status.Phase)
+	}
+}
+
+func TestFestaService_HistoryDetail(t *testing.T) {
+	mock := &mockFestaRepo{
+		history:       []FestaResult{{ID: 3, EventID: 9, Winner: FestivalColorRed}},
+		historyGuilds: []FestaHistoryGuild{{GuildID: 1, Place: 1}},
+	}
+	svc := newTestFestaService(mock)
+
+	detail, err := svc.HistoryDetail(3)
+	if err != nil || detail.Winner != FestivalColorRed || len(detail.Guilds) != 1 {
+		t.Errorf("HistoryDetail = %+v, %v", detail, err)
+	}
+	if _, err := svc.HistoryDetail(8); !errors.Is(err, ErrFestaHistoryNotFound) {
+		t.Errorf("missing history err = %v, want ErrFestaHistoryNotFound", err)
+	}
+}
+
+func TestFestaService_AddTrialAndPrize(t *testing.T) {
+	mock := &mockFestaRepo{}
+	svc := newTestFestaService(mock)
+
+	trial, err := svc.AddTrial(FestaTrial{Objective: 1, GoalID: 5, TimesReq: 3, Reward: 10})
+	if err != nil || trial.ID != 12 || trial.Monopoly != FestivalColorNone {
+		t.Errorf("AddTrial = %+v, %v", trial, err)
+	}
+	if _, err := svc.AddTrial(FestaTrial{Objective: 1}); !errors.Is(err, ErrInvalidFesta) {
+		t.Errorf("trial without timesReq err = %v, want ErrInvalidFesta", err)
+	}
+
+	prize, err := svc.AddPrize(FestaPrize{Type: "guild", Tier: 1, SoulsReq: 100, ItemID: 7011, NumItem: 2})
+	if err != nil || prize.ID != 7 || mock.createdPrize.SoulsReq != 100 {
+		t.Errorf("AddPrize = %+v, %v", prize, err)
+	}
+	if _, err := svc.AddPrize(FestaPrize{Type: "team", ItemID: 1, NumItem: 1}); !errors.Is(err, ErrInvalidFesta) {
+		t.Errorf("bad prize type err = %v, want ErrInvalidFesta", err)
+	}
+}
+
+func TestFestaService_RemoveNotFound(t *testing.T) {
+	svc := newTestFestaService(&mockFestaRepo{})
+
+	if err := svc.RemoveTrial(4); !errors.Is(err, ErrFestaTrialNotFound) {
+		t.Errorf("RemoveTrial err = %v, want ErrFestaTrialNotFound", err)
+	}
+	if err := svc.RemovePrize(4); !errors.Is(err, ErrFestaPrizeNotFound) {
+		t.Errorf("RemovePrize err = %v, want ErrFestaPrizeNotFound", err)
+	}
+}
@@ -0,0 +1,200 @@
+package channelserver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const (
+	fortAttackTickInterval      = time.Minute
+	fortAttackLookahead         = 24 * time.Hour // How far ahead events are scheduled and announced
+	fortAttackDefaultDurability = 100
+	fortAttackDistributionType  = 1
+	fortAttackRewardEventName   = "Interceptor's Base Defense"
+)
+
+// ErrFortAttackRestricted is returned when a character may not take a sortie
+// on a fort attack quest.
+var ErrFortAttackRestricted = errors.New("fort attack sortie restricted")
+
+// FortAttackService schedules fort attack events, applies their sortie
+// restrictions and pays participation rewards.
+type FortAttackService struct {
+	fortAttackRepo FortAttackRepo
+	logger         *zap.Logger
+}
+
+// NewFortAttackService creates a new FortAttackService.
+func NewFortAttackService(fr FortAttackRepo, log *zap.Logger) *FortAttackService {
+	return &FortAttackService{
+		fortAttackRepo: fr,
+		logger:         log,
+	}
+}
+
+// Tick schedules the configured events starting within the lookahead and
+// ends the events that are over, paying their rewards.
+func (svc *FortAttackService) Tick(now time.Time, opts cfg.FortAttackOptions) {
+	svc.schedule(now, opts)
+
+	events, err := svc.fortAttackRepo.ListOpen(now)
+	if err != nil {
+		svc.logger.Error("Failed to list fort attacks", zap.Error(err))
+		return
+	}
+	for _, event := range events {
+		if !event.EndsAt.After(now) {
+			svc.end(event, opts.Rewards)
+		}
+	}
+}
+
+// schedule stores the occurrences of each configured event that have not
+// ended and start within the lookahead.
+func (svc *FortAttackService) schedule(now time.Time, opts cfg.FortAttackOptions) {
+	durability := opts.Durability
+	if durability <= 0 {
+		durability = fortAttackDefaultDurability
+	}
+	for i, ev := range opts.Events {
+		windows := parseRaviWindows([]cfg.RavienteWindow{{Weekdays: ev.Weekdays, Start: ev.Start, Minutes: ev.Minutes}})
+		if len(windows) == 0 {
+			continue
+		}
+		quests := make(pq.Int64Array, len(ev.QuestFileIDs))
+		for j, id := range ev.QuestFileIDs {
+			quests[j] = int64(id)
+		}
+		raviOpenings(windows, now, func(start, end time.Time) {
+			if !end.After(now) || !start.Before(now.Add(fortAttackLookahead)) {
+				return
+			}
+			scheduled, err := svc.fortAttackRepo.ScheduleEvent(FortAttack{
+				ScheduleIndex: i,
+				EventType:     ev.EventType,
+				StartsAt:      start,
+				EndsAt:        end,
+				QuestFileIDs:  quests,
+				MinHR:         ev.MinHR,
+				MaxSorties:    ev.MaxSorties,
+				MaxDurability: durability,
+			})
+			if err != nil {
+				svc.logger.Error("Failed to schedule fort attack", zap.Error(err))
+			} else if scheduled {
+				svc.logger.Info("Fort attack scheduled", zap.Int("schedule", i), zap.Time("start", start))
+			}
+		})
+	}
+}
+
+// end pays an event's participation rewards and marks it ended.
+func (svc *FortAttackService) end(event FortAttack, rewards []cfg.FortAttackReward) {
+	participants, err := svc.fortAttackRepo.GetParticipants(event.ID)
+	if err != nil {
+		svc.logger.Error("Failed to load fort attack participants", zap.Error(err), zap.Uint32("eventID", event.ID))
+		return
+	}
+	grants := fortAttackGrants(event, participants, rewards)
+	ended, err := svc.fortAttackRepo.EndEvent(event.ID, grants, fortAttackRewardEventName)
+	if err != nil {
+		svc.logger.Error("Failed to end fort attack", zap.Error(err), zap.Uint32("eventID", event.ID))
+		return
+	}
+	if ended {
+		svc.logger.Info("Fort attack ended", zap.Uint32("eventID", event.ID),
+			zap.Int("durability", event.Durability), zap.Int("participants", len(participants)),
+			zap.Int("rewarded", len(grants)))
+	}
+}
+
+// fortAttackGrants returns the rewards each participant earned.
+func fortAttackGrants(event FortAttack, participants []FortAttackParticipant, rewards []cfg.FortAttackReward) []FortAttackGrant {
+	held := event.Durability > 0
+	description := "~C05Interceptor's Base reward: the fort held."
+	if !held {
+		description = "~C05Interceptor's Base reward: the fort fell."
+	}
+	var grants []FortAttackGrant
+	for _, p := range participants {
+		var items []DistributionItem
+		for _, reward := range rewards {
+			if p.Sorties < reward.MinSorties || (reward.HeldOnly && !held) || reward.Quantity == 0 {
+				continue
+			}
+			items = append(items, DistributionItem{ItemType: reward.ItemType, ItemID: reward.ItemID, Quantity: reward.Quantity})
+		}
+		if len(items) > 0 {
+			grants = append(grants, FortAttackGrant{CharID: p.CharID, Description: description, Items: items})
+		}
+	}
+	return grants
+}
+
+// Announced returns the events EnumerateEvent lists: those running at now
+// and those starting within the lookahead.
+func (svc *FortAttackService) Announced(now time.Time) ([]FortAttack, error) {
+	events, err := svc.fortAttackRepo.ListOpen(now.Add(fortAttackLookahead))
+	if err != nil {
+		return nil, err
+	}
+	announced := events[:0]
+	for _, event := range events {
+		if event.EndsAt.After(now) {
+			announced = append(announced, event)
+		}
+	}
+	return announced, nil
+}
+
+// ActiveFor returns the running event that includes questID, or nil.
+func (svc *FortAttackService) ActiveFor(now time.Time, questID uint16) (*FortAttack, error) {
+	events, err := svc.fortAttackRepo.ListOpen(now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		if events[i].EndsAt.After(now) && events[i].hasQuest(questID) {
+			return &events[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Sortie applies an event's restrictions and counts a sortie by the
+// character.
+func (svc *FortAttackService) Sortie(event *FortAttack, charID uint32, hr int) error {
+	if event.MinHR > 0 && hr < int(event.MinHR) {
+		return fmt.Errorf("%w: HR %d is below %d", ErrFortAttackRestricted, hr, event.MinHR)
+	}
+	ok, err := svc.fortAttackRepo.RecordSortie(event.ID, charID, event.MaxSorties)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: all %d sorties used", ErrFortAttackRestricted, event.MaxSorties)
+	}
+	return nil
+}
+
+// SortieResult records the outcome of a character's sortie. A failed sortie
+// costs the fort damage durability.
+func (svc *FortAttackService) SortieResult(eventID, charID uint32, cleared bool, damage int) {
+	if !cleared && damage <= 0 {
+		return
+	}
+	durability, err := svc.fortAttackRepo.RecordSortieResult(eventID, charID, cleared, damage)
+	if err != nil {
+		svc.logger.Error("Failed to record fort attack sortie", zap.Error(err), zap.Uint32("eventID", eventID))
+		return
+	}
+	if !cleared && durability == 0 {
+		svc.logger.Info("Fort has fallen", zap.Uint32("eventID", eventID))
+	}
+}
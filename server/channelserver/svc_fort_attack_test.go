@@ -0,0 +1,153 @@
+package channelserver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+func newFortAttackOpts() cfg.FortAttackOptions {
+	return cfg.FortAttackOptions{
+		Enabled:      true,
+		Durability:   80,
+		SortieDamage: 10,
+		Events: []cfg.FortAttackEvent{
+			{EventType: 2, Start: "12:00", Minutes: 60, QuestFileIDs: []uint16{20001, 20004}, MinHR: 100, MaxSorties: 3},
+		},
+		Rewards: []cfg.FortAttackReward{
+			{MinSorties: 1, ItemType: 7, ItemID: 100, Quantity: 1},
+			{MinSorties: 2, ItemType: 7, ItemID: 101, Quantity: 2},
+			{MinSorties: 1, HeldOnly: true, ItemType: 7, ItemID: 102, Quantity: 5},
+		},
+	}
+}
+
+func TestFortAttackService_TickSchedules(t *testing.T) {
+	repo := &mockFortAttackRepo{}
+	svc := NewFortAttackService(repo, zap.NewNop())
+	now := time.Date(2026, 10, 17, 12, 30, 0, 0, time.UTC)
+
+	svc.Tick(now, newFortAttackOpts())
+	svc.Tick(now.Add(time.Minute), newFortAttackOpts())
+
+	// Today's window is running and tomorrow's starts within the lookahead.
+	if len(repo.events) != 2 {
+		t.Fatalf("scheduled %d events, want 2", len(repo.events))
+	}
+	first := repo.events[0]
+	if !first.StartsAt.Equal(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)) || !first.EndsAt.Equal(first.StartsAt.Add(time.Hour)) {
+		t.Errorf("first event window = %v - %v", first.StartsAt, first.EndsAt)
+	}
+	if first.EventType != 2 || first.MaxDurability != 80 || first.MinHR != 100 || len(first.QuestFileIDs) != 2 {
+		t.Errorf("first event = %+v", first)
+	}
+}
+
+func TestFortAttackService_TickSkipsInvalidWindows(t *testing.T) {
+	repo := &mockFortAttackRepo{}
+	svc := NewFortAttackService(repo, zap.NewNop())
+	opts := newFortAttackOpts()
+	opts.Events = []cfg.FortAttackEvent{
+		{EventType: 1, Start: "25:00", Minutes: 60},
+		{EventType: 1, Start: "12:00", Minutes: 0},
+		{EventType: 1, Weekdays: []string{"funday"}, Start: "12:00", Minutes: 60},
+	}
+
+	svc.Tick(time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC), opts)
+	if len(repo.events) != 0 {
+		t.Errorf("scheduled %d events from invalid windows", len(repo.events))
+	}
+}
+
+func TestFortAttackService_TickEndsAndRewards(t *testing.T) {
+	now := time.Date(2026, 10, 17, 14, 0, 0, 0, time.UTC)
+	repo := &mockFortAttackRepo{
+		events: []FortAttack{
+			{ID: 1, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour), Durability: 40},
+		},
+		sorties: map[uint32]int{10: 1, 11: 2},
+	}
+	svc := NewFortAttackService(repo, zap.NewNop())
+	opts := newFortAttackOpts()
+	opts.Events = nil
+
+	svc.Tick(now, opts)
+	svc.Tick(now.Add(time.Minute), opts)
+
+	grants, ok := repo.ended[1]
+	if !ok {
+		t.Fatal("event was not ended")
+	}
+	if len(grants) != 2 {
+		t.Fatalf("grants = %+v, want 2", grants)
+	}
+	items := map[uint32]int{}
+	for _, g := range grants {
+		items[g.CharID] = len(g.Items)
+	}
+	if items[10] != 2 || items[11] != 3 {
+		t.Errorf("items per character = %v, want 10:2 11:3", items)
+	}
+}
+
+func TestFortAttackGrants_FortFell(t *testing.T) {
+	event := FortAttack{Durability: 0}
+	participants := []FortAttackParticipant{{CharID: 1, Sorties: 1}}
+
+	grants := fortAttackGrants(event, participants, newFortAttackOpts().Rewards)
+	if len(grants) != 1 || len(grants[0].Items) != 1 || grants[0].Items[0].ItemID != 100 {
+		t.Errorf("grants = %+v, want only the unconditional reward", grants)
+	}
+}
+
+func TestFortAttackService_Announced(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	repo := &mockFortAttackRepo{events: []FortAttack{
+		{ID: 1, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)},
+		{ID: 2, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{ID: 3, StartsAt: now.Add(23 * time.Hour), EndsAt: now.Add(24 * time.Hour)},
+		{ID: 4, StartsAt: now.Add(25 * time.Hour), EndsAt: now.Add(26 * time.Hour)},
+	}}
+	svc := NewFortAttackService(repo, zap.NewNop())
+
+	events, err := svc.Announced(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].ID != 2 || events[1].ID != 3 {
+		t.Errorf("announced = %+v, want events 2 and 3", events)
+	}
+}
+
+func TestFortAttackService_Sortie(t *testing.T) {
+	repo := &mockFortAttackRepo{}
+	svc := NewFortAttackService(repo, zap.NewNop())
+	event := &FortAttack{ID: 1, MinHR: 100, MaxSorties: 1}
+
+	if err := svc.Sortie(event, 5, 99); !errors.Is(err, ErrFortAttackRestricted) {
+		t.Errorf("low HR: err = %v, want ErrFortAttackRestricted", err)
+	}
+	if err := svc.Sortie(event, 5, 100); err != nil {
+		t.Fatalf("first sortie: %v", err)
+	}
+	if err := svc.Sortie(event, 5, 100); !errors.Is(err, ErrFortAttackRestricted) {
+		t.Errorf("second sortie: err = %v, want ErrFortAttackRestricted", err)
+	}
+}
+
+func TestFortAttackService_SortieResult(t *testing.T) {
+	repo := &mockFortAttackRepo{}
+	svc := NewFortAttackService(repo, zap.NewNop())
+
+	svc.SortieResult(1, 5, true, 10)
+	svc.SortieResult(1, 5, false, 10)
+	svc.SortieResult(1, 5, false, 0)
+
+	if len(repo.cleared) != 1 || len(repo.damaged) != 1 || repo.damaged[0] != 10 {
+		t.Errorf("cleared = %v, damaged = %v", repo.cleared, repo.damaged)
+	}
+}
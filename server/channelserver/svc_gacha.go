@@ -3,7 +3,9 @@ package channelserver
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"math/rand"
+	"sort"
 	"time"
 
 	"erupe-ce/common/byteframe"
@@ -11,6 +13,17 @@ import (
 	"go.uber.org/zap"
 )
 
+// Gacha types as listed in the gacha shop. The client plays step-up gachas
+// with MsgMhfPlayStepupGacha and box gachas (type 4 and up) with
+// MsgMhfPlayBoxGacha; everything else is a normal gacha.
+const (
+	gachaTypeStepup = 1
+	gachaTypeBox    = 4
+)
+
+// ErrGachaNotPlayable is returned when a gacha has nothing to draw.
+var ErrGachaNotPlayable = errors.New("gacha has no valid reward entries")
+
 // GachaService encapsulates business logic for the gacha lottery system.
 type GachaService struct {
 	gachaRepo        GachaRepo
@@ -55,23 +68,34 @@ type StepupStatus struct {
 	Step uint8
 }
 
+// gachaCost is what a roll was charged.
+type gachaCost struct {
+	Rolls    int
+	ItemType uint8
+	Amount   uint16
+	Currency string // One of the GachaCurrency values, empty if the client pays
+}
+
 // transact processes the cost for a gacha roll, deducting the appropriate currency.
-func (svc *GachaService) transact(userID, charID, gachaID uint32, rollID uint8) (int, error) {
+func (svc *GachaService) transact(userID, charID, gachaID uint32, rollID uint8) (gachaCost, error) {
 	itemType, itemNumber, rolls, err := svc.gachaRepo.GetEntryForTransaction(gachaID, rollID)
 	if err != nil {
-		return 0, err
+		return gachaCost{}, err
 	}
+	cost := gachaCost{Rolls: rolls, ItemType: itemType, Amount: itemNumber}
 	switch itemType {
 	case 17:
 		svc.deductNetcafePoints(charID, int(itemNumber))
+		cost.Currency = GachaCurrencyNetcafePoints
 	case 19, 20:
-		svc.spendGachaCoin(userID, itemNumber)
+		cost.Currency = svc.spendGachaCoin(userID, itemNumber)
 	case 21:
 		if err := svc.userRepo.DeductFrontierPoints(userID, uint32(itemNumber)); err != nil {
 			svc.logger.Error("Failed to deduct frontier points for gacha", zap.Error(err))
 		}
+		cost.Currency = GachaCurrencyFrontierPoints
 	}
-	return rolls, nil
+	return cost, nil
 }
 
 // deductNetcafePoints removes netcafe points from a character's save data.
@@ -87,18 +111,52 @@ func (svc *GachaService) deductNetcafePoints(charID uint32, amount int) {
 	}
 }
 
-// spendGachaCoin deducts gacha coins, preferring trial coins over premium.
-func (svc *GachaService) spendGachaCoin(userID uint32, quantity uint16) {
+// spendGachaCoin deducts gacha coins, preferring trial coins over premium,
+// and returns the balance it charged.
+func (svc *GachaService) spendGachaCoin(userID uint32, quantity uint16) string {
 	gt, _ := svc.userRepo.GetTrialCoins(userID)
 	if quantity <= gt {
 		if err := svc.userRepo.DeductTrialCoins(userID, uint32(quantity)); err != nil {
 			svc.logger.Error("Failed to deduct gacha trial coins", zap.Error(err))
 		}
-	} else {
-		if err := svc.userRepo.DeductPremiumCoins(userID, uint32(quantity)); err != nil {
-			svc.logger.Error("Failed to deduct gacha premium coins", zap.Error(err))
-		}
+		return GachaCurrencyTrialCoins
 	}
+	if err := svc.userRepo.DeductPremiumCoins(userID, uint32(quantity)); err != nil {
+		svc.logger.Error("Failed to deduct gacha premium coins", zap.Error(err))
+	}
+	return GachaCurrencyPremiumCoins
+}
+
+// recordPlay writes a roll to the play ledger. A failure is logged rather
+// than returned, as the roll has already been charged and awarded.
+func (svc *GachaService) recordPlay(userID, charID, gachaID uint32, kind string, rollType uint8, cost gachaCost, items []GachaPlayItem) {
+	if items == nil {
+		items = []GachaPlayItem{}
+	}
+	err := svc.gachaRepo.InsertPlay(GachaPlay{
+		UserID:   userID,
+		CharID:   charID,
+		GachaID:  gachaID,
+		Kind:     kind,
+		RollType: rollType,
+		CostType: cost.ItemType,
+		Cost:     uint32(cost.Amount),
+		Currency: cost.Currency,
+		Items:    items,
+	})
+	if err != nil {
+		svc.logger.Error("Failed to record gacha play", zap.Error(err),
+			zap.Uint32("charID", charID), zap.Uint32("gachaID", gachaID), zap.String("kind", kind))
+	}
+}
+
+// rewardsToPlayItems converts rewards to ledger items.
+func rewardsToPlayItems(rewards []GachaReward, guaranteed bool) []GachaPlayItem {
+	items := make([]GachaPlayItem, len(rewards))
+	for i, r := range rewards {
+		items[i] = GachaPlayItem{ItemType: r.ItemType, ItemID: r.ItemID, Quantity: r.Quantity, Rarity: r.Rarity, Guaranteed: guaranteed}
+	}
+	return items
 }
 
 // resolveRewards selects random entries and resolves them into rewards.
@@ -172,14 +230,15 @@ func (svc *GachaService) PlayNormalGacha(userID, charID, gachaID uint32, rollTyp
 		return nil, err
 	}
 	if len(entries) == 0 {
-		return nil, errors.New("gacha has no valid reward entries")
+		return nil, ErrGachaNotPlayable
 	}
-	rolls, err := svc.transact(userID, charID, gachaID, rollType)
+	cost, err := svc.transact(userID, charID, gachaID, rollType)
 	if err != nil {
 		return nil, err
 	}
-	rewards := svc.resolveRewards(entries, rolls, false)
+	rewards := svc.resolveRewards(entries, cost.Rolls, false)
 	svc.saveGachaItems(charID, rewardsToItems(rewards))
+	svc.recordPlay(userID, charID, gachaID, GachaPlayNormal, rollType, cost, rewardsToPlayItems(rewards, false))
 	return &GachaPlayResult{Rewards: rewards}, nil
 }
 
@@ -192,9 +251,9 @@ func (svc *GachaService) PlayStepupGacha(userID, charID, gachaID uint32, rollTyp
 		return nil, err
 	}
 	if len(entries) == 0 {
-		return nil, errors.New("gacha has no valid reward entries")
+		return nil, ErrGachaNotPlayable
 	}
-	rolls, err := svc.transact(userID, charID, gachaID, rollType)
+	cost, err := svc.transact(userID, charID, gachaID, rollType)
 	if err != nil {
 		return nil, err
 	}
@@ -209,7 +268,7 @@ func (svc *GachaService) PlayStepupGacha(userID, charID, gachaID uint32, rollTyp
 	}
 
 	guaranteedItems, _ := svc.gachaRepo.GetGuaranteedItems(rollType, gachaID)
-	randomRewards := svc.resolveRewards(entries, rolls, false)
+	randomRewards := svc.resolveRewards(entries, cost.Rolls, false)
 
 	var guaranteedRewards []GachaReward
 	for _, item := range guaranteedItems {
@@ -223,6 +282,8 @@ func (svc *GachaService) PlayStepupGacha(userID, charID, gachaID uint32, rollTyp
 
 	svc.saveGachaItems(charID, rewardsToItems(randomRewards))
 	svc.saveGachaItems(charID, rewardsToItems(guaranteedRewards))
+	svc.recordPlay(userID, charID, gachaID, GachaPlayStepup, rollType, cost,
+		append(rewardsToPlayItems(guaranteedRewards, true), rewardsToPlayItems(randomRewards, false)...))
 	return &StepupPlayResult{
 		RandomRewards:     randomRewards,
 		GuaranteedRewards: guaranteedRewards,
@@ -238,15 +299,16 @@ func (svc *GachaService) PlayBoxGacha(userID, charID, gachaID uint32, rollType u
 		return nil, err
 	}
 	if len(entries) == 0 {
-		return nil, errors.New("gacha has no valid reward entries")
+		return nil, ErrGachaNotPlayable
 	}
-	rolls, err := svc.transact(userID, charID, gachaID, rollType)
+	cost, err := svc.transact(userID, charID, gachaID, rollType)
 	if err != nil {
 		return nil, err
 	}
-	rewardEntries, err := getRandomEntries(entries, rolls, true)
+	rewardEntries, err := getRandomEntries(entries, cost.Rolls, true)
 	if err != nil {
 		svc.logger.Warn("Failed to select box gacha entries", zap.Error(err))
+		svc.recordPlay(userID, charID, gachaID, GachaPlayBox, rollType, cost, nil)
 		return &GachaPlayResult{}, nil
 	}
 	var rewards []GachaReward
@@ -270,9 +332,141 @@ func (svc *GachaService) PlayBoxGacha(userID, charID, gachaID uint32, rollType u
 		}
 	}
 	svc.saveGachaItems(charID, rewardsToItems(rewards))
+	svc.recordPlay(userID, charID, gachaID, GachaPlayBox, rollType, cost, rewardsToPlayItems(rewards, false))
 	return &GachaPlayResult{Rewards: rewards}, nil
 }
 
+// PlayFreeGacha records a free roll. Erupe neither charges nor awards
+// anything for free rolls, so the record has no cost or items.
+func (svc *GachaService) PlayFreeGacha(userID, charID, gachaID uint32, gachaType uint8) {
+	svc.recordPlay(userID, charID, gachaID, GachaPlayFree, gachaType, gachaCost{}, nil)
+}
+
+// GachaSimulation is the outcome of a series of simulated plays.
+type GachaSimulation struct {
+	GachaID  uint32                `json:"gachaId"`
+	RollType uint8                 `json:"rollType"`
+	Plays    int                   `json:"plays"`
+	Seed     int64                 `json:"seed"`
+	Draws    int                   `json:"draws"` // Entries drawn from the reward pool
+	Entries  []GachaSimulatedEntry `json:"entries"`
+	Items    []GachaSimulatedItem  `json:"items"`
+}
+
+// GachaSimulatedEntry counts how often a reward entry was drawn.
+type GachaSimulatedEntry struct {
+	EntryID   uint32  `json:"entryId"`
+	Rarity    uint8   `json:"rarity"`
+	Hits      int     `json:"hits"`
+	Frequency float64 `json:"frequency"` // Hits per draw
+}
+
+// GachaSimulatedItem totals an item awarded during a simulation.
+type GachaSimulatedItem struct {
+	ItemType   uint8  `json:"itemType"`
+	ItemID     uint16 `json:"itemId"`
+	Awarded    int    `json:"awarded"`              // Times the item was awarded
+	Quantity   int    `json:"quantity"`             // Total quantity awarded
+	Guaranteed bool   `json:"guaranteed,omitempty"` // Awarded by a step-up roll rather than drawn
+}
+
+// Simulate plays a gacha plays times with the given roll type without
+// charging, rewarding or recording anything. Entries are drawn exactly as
+// PlayNormalGacha, PlayStepupGacha and PlayBoxGacha draw them, from a source
+// seeded with seed, so the same catalogue and seed always give the same
+// result.
+func (svc *GachaService) Simulate(gachaID uint32, rollType uint8, plays int, seed int64) (*GachaSimulation, error) {
+	gachaType, err := svc.gachaRepo.GetShopType(gachaID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := svc.gachaRepo.GetRewardPool(gachaID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrGachaNotPlayable
+	}
+	_, _, rolls, err := svc.gachaRepo.GetEntryForTransaction(gachaID, rollType)
+	if err != nil {
+		return nil, err
+	}
+	isBox := gachaType >= gachaTypeBox
+	var guaranteed []GachaItem
+	if gachaType == gachaTypeStepup {
+		if guaranteed, err = svc.gachaRepo.GetGuaranteedItems(rollType, gachaID); err != nil {
+			return nil, err
+		}
+	}
+	entryItems := make(map[uint32][]GachaItem, len(entries))
+	for _, e := range entries {
+		if entryItems[e.ID], err = svc.gachaRepo.GetItemsForEntry(e.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	type itemKey struct {
+		itemType   uint8
+		itemID     uint16
+		guaranteed bool
+	}
+	hits := make(map[uint32]int, len(entries))
+	items := make(map[itemKey]*GachaSimulatedItem)
+	award := func(item GachaItem, guaranteed bool) {
+		k := itemKey{item.ItemType, item.ItemID, guaranteed}
+		if items[k] == nil {
+			items[k] = &GachaSimulatedItem{ItemType: item.ItemType, ItemID: item.ItemID, Guaranteed: guaranteed}
+		}
+		items[k].Awarded++
+		items[k].Quantity += int(item.Quantity)
+	}
+
+	sim := &GachaSimulation{GachaID: gachaID, RollType: rollType, Plays: plays, Seed: seed}
+	rng := rand.New(rand.NewSource(seed))
+	pool := make([]GachaEntry, len(entries))
+	for i := 0; i < plays; i++ {
+		// Box draws remove entries from the slice they are given.
+		copy(pool, entries)
+		drawn, err := drawGachaEntries(rng, pool, rolls, isBox)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrGachaNotPlayable, err)
+		}
+		sim.Draws += len(drawn)
+		for _, e := range drawn {
+			hits[e.ID]++
+			for _, item := range entryItems[e.ID] {
+				award(item, false)
+			}
+		}
+		for _, item := range guaranteed {
+			award(item, true)
+		}
+	}
+
+	for _, e := range entries {
+		se := GachaSimulatedEntry{EntryID: e.ID, Rarity: e.Rarity, Hits: hits[e.ID]}
+		if sim.Draws > 0 {
+			se.Frequency = float64(se.Hits) / float64(sim.Draws)
+		}
+		sim.Entries = append(sim.Entries, se)
+	}
+	sim.Items = make([]GachaSimulatedItem, 0, len(items))
+	for _, item := range items {
+		sim.Items = append(sim.Items, *item)
+	}
+	sort.Slice(sim.Items, func(i, j int) bool {
+		a, b := sim.Items[i], sim.Items[j]
+		if a.Guaranteed != b.Guaranteed {
+			return b.Guaranteed
+		}
+		if a.ItemType != b.ItemType {
+			return a.ItemType < b.ItemType
+		}
+		return a.ItemID < b.ItemID
+	})
+	return sim, nil
+}
+
 // GetStepupStatus returns the current stepup step for a character, resetting
 // stale progress based on the noon boundary. The now parameter enables
 // deterministic testing.
@@ -317,10 +511,28 @@ func (svc *GachaService) ResetBox(gachaID, charID uint32) error {
 	return svc.gachaRepo.DeleteBoxEntries(gachaID, charID)
 }
 
+// gachaRand is a source of gacha draws.
+type gachaRand interface {
+	Float64() float64
+	Intn(n int) int
+}
+
+// globalGachaRand draws from the shared math/rand source, which is safe for
+// concurrent sessions.
+type globalGachaRand struct{}
+
+func (globalGachaRand) Float64() float64 { return rand.Float64() }
+func (globalGachaRand) Intn(n int) int   { return rand.Intn(n) }
+
 // getRandomEntries selects random gacha entries. In non-box mode, entries are
 // chosen with weighted probability (with replacement). In box mode, entries are
 // chosen uniformly without replacement.
 func getRandomEntries(entries []GachaEntry, rolls int, isBox bool) ([]GachaEntry, error) {
+	return drawGachaEntries(globalGachaRand{}, entries, rolls, isBox)
+}
+
+// drawGachaEntries is getRandomEntries with an explicit source.
+func drawGachaEntries(rng gachaRand, entries []GachaEntry, rolls int, isBox bool) ([]GachaEntry, error) {
 	if len(entries) == 0 {
 		return nil, errors.New("no gacha entries available")
 	}
@@ -338,7 +550,7 @@ func getRandomEntries(entries []GachaEntry, rolls int, isBox bool) ([]GachaEntry
 	}
 	for rolls != len(chosen) {
 		if !isBox {
-			result := rand.Float64() * totalWeight
+			result := rng.Float64() * totalWeight
 			for _, entry := range entries {
 				result -= entry.Weight
 				if result < 0 {
@@ -347,7 +559,7 @@ func getRandomEntries(entries []GachaEntry, rolls int, isBox bool) ([]GachaEntry
 				}
 			}
 		} else {
-			result := rand.Intn(len(entries))
+			result := rng.Intn(len(entries))
 			chosen = append(chosen, entries[result])
 			entries[result] = entries[len(entries)-1]
 			entries = entries[:len(entries)-1]
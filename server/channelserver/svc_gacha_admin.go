@@ -0,0 +1,446 @@
+package channelserver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+const (
+	gachaRewardEntryType    = 100 // Entries below this are roll costs and step-up steps
+	gachaMaxNameLen         = 100 // Characters; names are sent with a uint8 length prefix
+	gachaMaxURLLen          = 255 // Bytes; URLs are sent with a uint8 length prefix
+	gachaMaxEntryItems      = 255
+	gachaRateScale          = 100000 // Rates are sent to the client in 1/100000ths
+	gachaSimulationMaxPlays = 100000
+)
+
+var (
+	// ErrGachaNotFound is returned when a gacha does not exist.
+	ErrGachaNotFound = errors.New("gacha not found")
+	// ErrGachaEntryNotFound is returned when an entry does not exist on a gacha.
+	ErrGachaEntryNotFound = errors.New("gacha entry not found")
+	// ErrInvalidGacha is returned when a gacha, entry or simulation request
+	// fails validation.
+	ErrInvalidGacha = errors.New("invalid gacha")
+)
+
+// GachaRates discloses the real chance of drawing each reward entry and item.
+type GachaRates struct {
+	GachaID    uint32            `json:"gachaId"`
+	Box        bool              `json:"box"`        // Entries are drawn uniformly, without replacement within a play
+	PoolWeight float64           `json:"poolWeight"` // Total weight of the entries that can be drawn
+	Entries    []GachaEntryRate  `json:"entries"`
+	Items      []GachaItemRate   `json:"items"`
+	Rarities   []GachaRarityRate `json:"rarities"`
+	Warnings   []string          `json:"warnings"`
+}
+
+// GachaEntryRate is the chance of drawing a reward entry.
+type GachaEntryRate struct {
+	EntryID       uint32      `json:"entryId"`
+	Rarity        uint8       `json:"rarity"`
+	Weight        float64     `json:"weight"`
+	Probability   float64     `json:"probability"`             // Chance per draw, 0-1
+	DisplayedRate *float64    `json:"displayedRate,omitempty"` // Chance the client is told, 0-1; not sent for box gachas
+	Items         []GachaItem `json:"items"`
+}
+
+// GachaItemRate is the chance that a draw awards an item.
+type GachaItemRate struct {
+	ItemType         uint8   `json:"itemType"`
+	ItemID           uint16  `json:"itemId"`
+	Probability      float64 `json:"probability"`      // Chance per draw, 0-1
+	ExpectedQuantity float64 `json:"expectedQuantity"` // Mean quantity per draw
+}
+
+// GachaRarityRate is the chance of drawing an entry of a rarity.
+type GachaRarityRate struct {
+	Rarity      uint8   `json:"rarity"`
+	Probability float64 `json:"probability"`
+}
+
+// GachaSimulationRequest configures a simulated series of plays.
+type GachaSimulationRequest struct {
+	RollType uint8 `json:"rollType"` // Entry type of the roll, or the step for step-up gachas
+	Plays    int   `json:"plays"`
+	Seed     int64 `json:"seed"`
+}
+
+// GachaAdminService manages gacha catalogues for the admin API and the
+// liveops tool.
+type GachaAdminService struct {
+	gachaRepo GachaRepo
+	gacha     *GachaService
+	logger    *zap.Logger
+}
+
+// NewGachaAdminService creates a new GachaAdminService.
+func NewGachaAdminService(gr GachaRepo, log *zap.Logger) *GachaAdminService {
+	return &GachaAdminService{
+		gachaRepo: gr,
+		// Simulations only read the catalogue, so the play service needs no
+		// user or character repositories.
+		gacha:  NewGachaService(gr, nil, nil, log, 0),
+		logger: log,
+	}
+}
+
+// List returns every gacha shop definition by ID.
+func (svc *GachaAdminService) List() ([]Gacha, error) {
+	gachas, err := svc.gachaRepo.ListShop()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(gachas, func(i, j int) bool { return gachas[i].ID < gachas[j].ID })
+	return gachas, nil
+}
+
+// Get returns a gacha with its entries and their items.
+func (svc *GachaAdminService) Get(id uint32) (*GachaCatalogue, error) {
+	g, err := svc.gachaRepo.GetShop(id)
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, fmt.Errorf("%w: %d", ErrGachaNotFound, id)
+	}
+	entries, err := svc.gachaRepo.ListCatalogueEntries(id)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []GachaCatalogueEntry{}
+	}
+	return &GachaCatalogue{Gacha: *g, Entries: entries}, nil
+}
+
+// Create validates and stores a gacha shop definition.
+func (svc *GachaAdminService) Create(g Gacha) (*GachaCatalogue, error) {
+	if err := validateGacha(g); err != nil {
+		return nil, err
+	}
+	id, err := svc.gachaRepo.CreateShop(g)
+	if err != nil {
+		return nil, err
+	}
+	svc.logger.Info("Gacha created", zap.Uint32("gachaID", id), zap.String("name", g.Name))
+	return svc.Get(id)
+}
+
+// Update validates and replaces a gacha shop definition.
+func (svc *GachaAdminService) Update(id uint32, g Gacha) (*GachaCatalogue, error) {
+	if err := validateGacha(g); err != nil {
+		return nil, err
+	}
+	g.ID = id
+	ok, err := svc.gachaRepo.UpdateShop(g)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrGachaNotFound, id)
+	}
+	return svc.Get(id)
+}
+
+// Delete removes a gacha with its entries and every character's progress on
+// it.
+func (svc *GachaAdminService) Delete(id uint32) error {
+	ok, err := svc.gachaRepo.DeleteShop(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrGachaNotFound, id)
+	}
+	svc.logger.Info("Gacha deleted", zap.Uint32("gachaID", id))
+	return nil
+}
+
+// CreateEntry validates and adds an entry with its items to a gacha.
+func (svc *GachaAdminService) CreateEntry(gachaID uint32, e GachaCatalogueEntry) (*GachaCatalogueEntry, error) {
+	c, err := svc.Get(gachaID)
+	if err != nil {
+		return nil, err
+	}
+	e.ID = 0
+	if err := validateGachaEntry(c, e); err != nil {
+		return nil, err
+	}
+	id, err := svc.gachaRepo.CreateEntry(gachaID, e)
+	if err != nil {
+		return nil, err
+	}
+	return svc.getEntry(gachaID, id)
+}
+
+// UpdateEntry validates and replaces an entry and its items.
+func (svc *GachaAdminService) UpdateEntry(gachaID, entryID uint32, e GachaCatalogueEntry) (*GachaCatalogueEntry, error) {
+	c, err := svc.Get(gachaID)
+	if err != nil {
+		return nil, err
+	}
+	e.ID = entryID
+	if err := validateGachaEntry(c, e); err != nil {
+		return nil, err
+	}
+	ok, err := svc.gachaRepo.UpdateEntry(gachaID, e)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrGachaEntryNotFound, entryID)
+	}
+	return svc.getEntry(gachaID, entryID)
+}
+
+// DeleteEntry removes an entry and its items from a gacha.
+func (svc *GachaAdminService) DeleteEntry(gachaID, entryID uint32) error {
+	ok, err := svc.gachaRepo.DeleteEntry(gachaID, entryID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrGachaEntryNotFound, entryID)
+	}
+	return nil
+}
+
+// getEntry returns one entry of a gacha's catalogue.
+func (svc *GachaAdminService) getEntry(gachaID, entryID uint32) (*GachaCatalogueEntry, error) {
+	c, err := svc.Get(gachaID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range c.Entries {
+		if c.Entries[i].ID == entryID {
+			return &c.Entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", ErrGachaEntryNotFound, entryID)
+}
+
+// Rates computes the real chance of drawing each reward entry and item of a
+// gacha, and lists catalogue problems that break plays or make the rates the
+// client displays differ from the real ones.
+func (svc *GachaAdminService) Rates(id uint32) (*GachaRates, error) {
+	c, err := svc.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return computeGachaRates(c), nil
+}
+
+// Simulate runs a seeded series of plays through GachaService.
+func (svc *GachaAdminService) Simulate(id uint32, req GachaSimulationRequest) (*GachaSimulation, error) {
+	if req.Plays < 1 || req.Plays > gachaSimulationMaxPlays {
+		return nil, fmt.Errorf("%w: plays must be 1-%d", ErrInvalidGacha, gachaSimulationMaxPlays)
+	}
+	c, err := svc.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	hasRoll := false
+	for _, e := range c.Entries {
+		if e.EntryType == req.RollType && e.EntryType < gachaRewardEntryType {
+			hasRoll = true
+			break
+		}
+	}
+	if !hasRoll {
+		return nil, fmt.Errorf("%w: no roll entry of type %d", ErrInvalidGacha, req.RollType)
+	}
+	sim, err := svc.gacha.Simulate(id, req.RollType, req.Plays, req.Seed)
+	if errors.Is(err, ErrGachaNotPlayable) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGacha, err)
+	}
+	return sim, err
+}
+
+// validateGacha checks a gacha shop definition.
+func validateGacha(g Gacha) error {
+	if n := utf8.RuneCountInString(g.Name); n == 0 || n > gachaMaxNameLen {
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidGacha, gachaMaxNameLen)
+	}
+	for _, url := range []string{g.URLBanner, g.URLFeature, g.URLThumbnail} {
+		if len(url) > gachaMaxURLLen {
+			return fmt.Errorf("%w: URLs must be at most %d bytes", ErrInvalidGacha, gachaMaxURLLen)
+		}
+	}
+	return nil
+}
+
+// validateGachaEntry checks an entry against the gacha it belongs to. Roll
+// entry types must be unique: the cost, roll count and guaranteed items of a
+// roll are looked up by type.
+func validateGachaEntry(c *GachaCatalogue, e GachaCatalogueEntry) error {
+	switch {
+	case e.EntryType > gachaRewardEntryType:
+		return fmt.Errorf("%w: entryType must be 0-%d for rolls or %d for rewards", ErrInvalidGacha,
+			gachaRewardEntryType-1, gachaRewardEntryType)
+	case e.Weight < 0 || e.Weight > math.MaxInt32 || e.Weight != math.Trunc(e.Weight):
+		return fmt.Errorf("%w: weight must be a whole number from 0 to %d", ErrInvalidGacha, math.MaxInt32)
+	case len(e.Items) > gachaMaxEntryItems:
+		return fmt.Errorf("%w: at most %d items per entry", ErrInvalidGacha, gachaMaxEntryItems)
+	case utf8.RuneCountInString(e.Name) > gachaMaxNameLen:
+		return fmt.Errorf("%w: name must be at most %d characters", ErrInvalidGacha, gachaMaxNameLen)
+	}
+	if e.EntryType == gachaRewardEntryType {
+		return nil
+	}
+	for _, other := range c.Entries {
+		if other.EntryType == e.EntryType && other.ID != e.ID {
+			return fmt.Errorf("%w: entry %d already defines roll type %d", ErrInvalidGacha, other.ID, e.EntryType)
+		}
+	}
+	return nil
+}
+
+// computeGachaRates derives draw chances from a catalogue the way
+// GachaService draws: only reward entries with items are in the pool, drawn
+// by weight, or uniformly for box gachas. Displayed rates are what the shop
+// handler sends, which divides by the weight of every entry.
+func computeGachaRates(c *GachaCatalogue) *GachaRates {
+	rates := &GachaRates{
+		GachaID:  c.ID,
+		Box:      c.GachaType >= gachaTypeBox,
+		Entries:  []GachaEntryRate{},
+		Items:    []GachaItemRate{},
+		Rarities: []GachaRarityRate{},
+		Warnings: gachaCatalogueWarnings(c),
+	}
+	var allWeight float64
+	pool := 0
+	for _, e := range c.Entries {
+		allWeight += e.Weight
+		if e.EntryType == gachaRewardEntryType && len(e.Items) > 0 {
+			rates.PoolWeight += e.Weight
+			pool++
+		}
+	}
+	divisor := allWeight / gachaRateScale
+
+	type itemKey struct {
+		itemType uint8
+		itemID   uint16
+	}
+	items := make(map[itemKey]*GachaItemRate)
+	rarities := make(map[uint8]float64)
+	for _, e := range c.Entries {
+		if e.EntryType != gachaRewardEntryType {
+			continue
+		}
+		r := GachaEntryRate{EntryID: e.ID, Rarity: e.Rarity, Weight: e.Weight, Items: e.Items}
+		switch {
+		case len(e.Items) == 0:
+		case rates.Box:
+			r.Probability = 1 / float64(pool)
+		case rates.PoolWeight > 0:
+			r.Probability = e.Weight / rates.PoolWeight
+		}
+		if !rates.Box && divisor > 0 {
+			sent := e.Weight / divisor
+			// The rate field is a uint16, so larger shares wrap around.
+			shown := float64(uint16(int64(sent))) / gachaRateScale
+			r.DisplayedRate = &shown
+			switch {
+			case sent > math.MaxUint16:
+				rates.Warnings = append(rates.Warnings, fmt.Sprintf(
+					"reward entry %d has %.3f%% of the total weight, more than the 65.535%% the client can display",
+					e.ID, 100*e.Weight/allWeight))
+			case math.Abs(shown-r.Probability) > 1.0/gachaRateScale:
+				rates.Warnings = append(rates.Warnings, fmt.Sprintf(
+					"reward entry %d is displayed at %.3f%% but drawn at %.3f%%", e.ID, 100*shown, 100*r.Probability))
+			}
+		}
+		rates.Entries = append(rates.Entries, r)
+		if r.Probability == 0 {
+			continue
+		}
+		rarities[e.Rarity] += r.Probability
+		seen := make(map[itemKey]bool)
+		for _, item := range e.Items {
+			k := itemKey{item.ItemType, item.ItemID}
+			if items[k] == nil {
+				items[k] = &GachaItemRate{ItemType: item.ItemType, ItemID: item.ItemID}
+			}
+			if !seen[k] {
+				items[k].Probability += r.Probability
+				seen[k] = true
+			}
+			items[k].ExpectedQuantity += r.Probability * float64(item.Quantity)
+		}
+	}
+
+	for _, item := range items {
+		rates.Items = append(rates.Items, *item)
+	}
+	sort.Slice(rates.Items, func(i, j int) bool {
+		a, b := rates.Items[i], rates.Items[j]
+		if a.ItemType != b.ItemType {
+			return a.ItemType < b.ItemType
+		}
+		return a.ItemID < b.ItemID
+	})
+	for rarity, p := range rarities {
+		rates.Rarities = append(rates.Rarities, GachaRarityRate{Rarity: rarity, Probability: p})
+	}
+	sort.Slice(rates.Rarities, func(i, j int) bool { return rates.Rarities[i].Rarity < rates.Rarities[j].Rarity })
+	return rates
+}
+
+// gachaCatalogueWarnings lists catalogue problems that stop a gacha from
+// being played or make entries behave differently from how they read.
+func gachaCatalogueWarnings(c *GachaCatalogue) []string {
+	warnings := []string{}
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	box := c.GachaType >= gachaTypeBox
+	stepup := c.GachaType == gachaTypeStepup
+	rolls, pool := 0, 0
+	var poolWeight float64
+	for _, e := range c.Entries {
+		if e.EntryType < gachaRewardEntryType {
+			rolls++
+			switch {
+			case stepup && e.Rolls == 0 && len(e.Items) == 0:
+				warn("step %d has no rolls and no guaranteed items, so it awards nothing", e.EntryType)
+			case !stepup && e.Rolls == 0:
+				warn("roll entry %d has no rolls, so it awards nothing", e.ID)
+			case !stepup && len(e.Items) > 0:
+				warn("roll entry %d has items, but only step-up gachas award a roll's items", e.ID)
+			}
+			if !box && e.Weight > 0 {
+				warn("roll entry %d has a weight of %.0f, which lowers every rate the client displays", e.ID, e.Weight)
+			}
+			continue
+		}
+		if e.ItemType != 0 || e.ItemNumber != 0 || e.ItemQuantity != 0 {
+			warn("reward entry %d sets itemType, itemNumber or itemQuantity; G10+ clients need these to be 0 and read its items instead", e.ID)
+		}
+		if len(e.Items) == 0 {
+			warn("reward entry %d has no items; the client lists it but it is never drawn", e.ID)
+			continue
+		}
+		pool++
+		poolWeight += e.Weight
+		if !box && e.Weight == 0 {
+			warn("reward entry %d has no weight and is never drawn", e.ID)
+		}
+	}
+	switch {
+	case rolls == 0:
+		warn("no roll entries (entryType 0-%d), so the gacha cannot be played", gachaRewardEntryType-1)
+	case pool == 0:
+		warn("no reward entries with items, so every play fails")
+	case !box && poolWeight == 0:
+		warn("reward entries have no weight, so every play fails")
+	}
+	return warnings
+}
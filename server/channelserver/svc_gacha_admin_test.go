@@ -0,0 +1,236 @@
+package channelserver
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// newTestGachaCatalogue builds a normal gacha with one roll and three
+// rewards weighted 50/30/20.
+func newTestGachaCatalogue(t *testing.T) (*GachaAdminService, *mockGachaRepo, uint32) {
+	t.Helper()
+	repo := &mockGachaRepo{}
+	svc := NewGachaAdminService(repo, zap.NewNop())
+	g, err := svc.Create(Gacha{Name: "Premium", GachaType: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := []GachaCatalogueEntry{
+		{GachaEntry: GachaEntry{EntryType: 0, ItemType: 19, ItemNumber: 1, Rolls: 1}},
+		{GachaEntry: GachaEntry{EntryType: 100, Weight: 50, Rarity: 1}, Items: []GachaItem{{ItemType: 7, ItemID: 1, Quantity: 2}}},
+		{GachaEntry: GachaEntry{EntryType: 100, Weight: 30, Rarity: 2}, Items: []GachaItem{{ItemType: 7, ItemID: 1, Quantity: 5}}},
+		{GachaEntry: GachaEntry{EntryType: 100, Weight: 20, Rarity: 3}, Items: []GachaItem{{ItemType: 7, ItemID: 2, Quantity: 1}}},
+	}
+	for _, e := range entries {
+		if _, err := svc.CreateEntry(g.ID, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return svc, repo, g.ID
+}
+
+func TestGachaAdminService_CRUD(t *testing.T) {
+	svc, repo, id := newTestGachaCatalogue(t)
+
+	c, err := svc.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "Premium" || len(c.Entries) != 4 {
+		t.Fatalf("catalogue = %+v", c)
+	}
+
+	edit := c.Entries[1]
+	edit.Weight = 60
+	edit.Items = append(edit.Items, GachaItem{ItemType: 7, ItemID: 9, Quantity: 1})
+	updated, err := svc.UpdateEntry(id, edit.ID, edit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Weight != 60 || len(updated.Items) != 2 {
+		t.Errorf("updated entry = %+v", updated)
+	}
+	if _, err := svc.UpdateEntry(id, 99, edit); !errors.Is(err, ErrGachaEntryNotFound) {
+		t.Errorf("update missing entry: err = %v, want ErrGachaEntryNotFound", err)
+	}
+
+	if err := svc.DeleteEntry(id, edit.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteEntry(id, edit.ID); !errors.Is(err, ErrGachaEntryNotFound) {
+		t.Errorf("delete twice: err = %v, want ErrGachaEntryNotFound", err)
+	}
+
+	if _, err := svc.Update(id, Gacha{Name: "Premium II", GachaType: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Delete(id); err != nil || !repo.deleted {
+		t.Fatalf("Delete = %v, deleted = %v", err, repo.deleted)
+	}
+	if _, err := svc.Get(id); !errors.Is(err, ErrGachaNotFound) {
+		t.Errorf("get deleted: err = %v, want ErrGachaNotFound", err)
+	}
+}
+
+func TestGachaAdminService_Invalid(t *testing.T) {
+	svc, _, id := newTestGachaCatalogue(t)
+
+	if _, err := svc.Create(Gacha{}); !errors.Is(err, ErrInvalidGacha) {
+		t.Errorf("no name: err = %v, want ErrInvalidGacha", err)
+	}
+	if _, err := svc.Create(Gacha{Name: "x", URLBanner: strings.Repeat("a", 256)}); !errors.Is(err, ErrInvalidGacha) {
+		t.Errorf("long URL: err = %v, want ErrInvalidGacha", err)
+	}
+
+	entries := []struct {
+		name  string
+		entry GachaCatalogueEntry
+	}{
+		{"unknown entry type", GachaCatalogueEntry{GachaEntry: GachaEntry{EntryType: 101}}},
+		{"fractional weight", GachaCatalogueEntry{GachaEntry: GachaEntry{EntryType: 100, Weight: 1.5}}},
+		{"negative weight", GachaCatalogueEntry{GachaEntry: GachaEntry{EntryType: 100, Weight: -1}}},
+		{"duplicate roll type", GachaCatalogueEntry{GachaEntry: GachaEntry{EntryType: 0, Rolls: 11}}},
+		{"too many items", GachaCatalogueEntry{GachaEntry: GachaEntry{EntryType: 100}, Items: make([]GachaItem, 256)}},
+	}
+	for _, tc := range entries {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := svc.CreateEntry(id, tc.entry); !errors.Is(err, ErrInvalidGacha) {
+				t.Errorf("err = %v, want ErrInvalidGacha", err)
+			}
+		})
+	}
+	if _, err := svc.CreateEntry(99, entries[0].entry); !errors.Is(err, ErrGachaNotFound) {
+		t.Errorf("missing gacha: err = %v, want ErrGachaNotFound", err)
+	}
+}
+
+func TestGachaAdminService_Rates(t *testing.T) {
+	svc, _, id := newTestGachaCatalogue(t)
+
+	rates, err := svc.Rates(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates.PoolWeight != 100 || len(rates.Entries) != 3 {
+		t.Fatalf("rates = %+v", rates)
+	}
+	want := []float64{0.5, 0.3, 0.2}
+	for i, r := range rates.Entries {
+		if !approxRate(r.Probability, want[i]) || r.DisplayedRate == nil || !approxRate(*r.DisplayedRate, want[i]) {
+			t.Errorf("entry %d: probability %v displayed %v, want %v", r.EntryID, r.Probability, r.DisplayedRate, want[i])
+		}
+	}
+	// Item 1 is in the 50 and 30 entries.
+	if len(rates.Items) != 2 || !approxRate(rates.Items[0].Probability, 0.8) || !approxRate(rates.Items[0].ExpectedQuantity, 0.5*2+0.3*5) {
+		t.Errorf("items = %+v", rates.Items)
+	}
+	if len(rates.Rarities) != 3 || !approxRate(rates.Rarities[2].Probability, 0.2) {
+		t.Errorf("rarities = %+v", rates.Rarities)
+	}
+	if len(rates.Warnings) != 0 {
+		t.Errorf("warnings = %v, want none", rates.Warnings)
+	}
+}
+
+func TestGachaAdminService_RateWarnings(t *testing.T) {
+	svc, _, id := newTestGachaCatalogue(t)
+	// An entry without items is listed and counted by the client's rate
+	// display, but never drawn.
+	if _, err := svc.CreateEntry(id, GachaCatalogueEntry{GachaEntry: GachaEntry{EntryType: 100, Weight: 100}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rates, err := svc.Rates(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approxRate(rates.Entries[0].Probability, 0.5) || !approxRate(*rates.Entries[0].DisplayedRate, 0.25) {
+		t.Errorf("entry = %+v, want drawn at 50%% but displayed at 25%%", rates.Entries[0])
+	}
+	joined := strings.Join(rates.Warnings, "\n")
+	for _, want := range []string{"has no items", "displayed at 25.000% but drawn at 50.000%", "displayed at 50.000% but drawn at 0.000%"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("warnings missing %q:\n%s", want, joined)
+		}
+	}
+}
+
+func TestComputeGachaRates_DisplayOverflow(t *testing.T) {
+	c := &GachaCatalogue{Entries: []GachaCatalogueEntry{
+		{GachaEntry: GachaEntry{ID: 1, Rolls: 1}},
+		{GachaEntry: GachaEntry{ID: 2, EntryType: 100, Weight: 70}, Items: []GachaItem{{ItemID: 1}}},
+		{GachaEntry: GachaEntry{ID: 3, EntryType: 100, Weight: 30}, Items: []GachaItem{{ItemID: 2}}},
+	}}
+	rates := computeGachaRates(c)
+	// 70000 does not fit the client's uint16 rate field and wraps to 4464.
+	if !approxRate(*rates.Entries[0].DisplayedRate, 0.04464) {
+		t.Errorf("displayed rate = %v, want the wrapped 0.04464", *rates.Entries[0].DisplayedRate)
+	}
+	if len(rates.Warnings) != 1 || !strings.Contains(rates.Warnings[0], "more than the 65.535%") {
+		t.Errorf("warnings = %v", rates.Warnings)
+	}
+}
+
+func TestGachaCatalogueWarnings(t *testing.T) {
+	reward := GachaCatalogueEntry{GachaEntry: GachaEntry{ID: 2, EntryType: 100, Weight: 10}, Items: []GachaItem{{ItemID: 1}}}
+	tests := []struct {
+		name    string
+		catalog GachaCatalogue
+		want    string
+	}{
+		{"no rolls", GachaCatalogue{Entries: []GachaCatalogueEntry{reward}}, "cannot be played"},
+		{"no pool", GachaCatalogue{Entries: []GachaCatalogueEntry{{GachaEntry: GachaEntry{ID: 1, Rolls: 1}}}}, "every play fails"},
+		{"empty step", GachaCatalogue{Gacha: Gacha{GachaType: gachaTypeStepup}, Entries: []GachaCatalogueEntry{
+			{GachaEntry: GachaEntry{ID: 1, EntryType: 2}}, reward}}, "step 2 has no rolls"},
+		{"normal roll items", GachaCatalogue{Entries: []GachaCatalogueEntry{
+			{GachaEntry: GachaEntry{ID: 1, Rolls: 1}, Items: []GachaItem{{ItemID: 5}}}, reward}}, "only step-up gachas"},
+		{"legacy reward fields", GachaCatalogue{Entries: []GachaCatalogueEntry{
+			{GachaEntry: GachaEntry{ID: 1, Rolls: 1}},
+			{GachaEntry: GachaEntry{ID: 2, EntryType: 100, Weight: 10, ItemType: 7, ItemNumber: 1}, Items: []GachaItem{{ItemID: 1}}}}}, "G10+"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := strings.Join(gachaCatalogueWarnings(&tc.catalog), "\n")
+			if !strings.Contains(got, tc.want) {
+				t.Errorf("warnings = %q, want one containing %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGachaAdminService_Simulate(t *testing.T) {
+	svc, repo, id := newTestGachaCatalogue(t)
+	repo.txRolls = 1
+	repo.rewardPool = []GachaEntry{{ID: 2, Weight: 50}, {ID: 3, Weight: 30}, {ID: 4, Weight: 20}}
+
+	sim, err := svc.Simulate(id, GachaSimulationRequest{RollType: 0, Plays: 100, Seed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sim.Plays != 100 || sim.Draws != 100 || sim.Seed != 7 {
+		t.Errorf("simulation = %+v", sim)
+	}
+
+	invalid := []GachaSimulationRequest{
+		{Plays: 0},
+		{Plays: gachaSimulationMaxPlays + 1},
+		{RollType: 5, Plays: 1},
+	}
+	for _, req := range invalid {
+		if _, err := svc.Simulate(id, req); !errors.Is(err, ErrInvalidGacha) {
+			t.Errorf("Simulate(%+v): err = %v, want ErrInvalidGacha", req, err)
+		}
+	}
+	repo.rewardPool = nil
+	if _, err := svc.Simulate(id, GachaSimulationRequest{Plays: 1}); !errors.Is(err, ErrInvalidGacha) {
+		t.Errorf("unplayable: err = %v, want ErrInvalidGacha", err)
+	}
+}
+
+func approxRate(got, want float64) bool {
+	return math.Abs(got-want) < 1e-9
+}
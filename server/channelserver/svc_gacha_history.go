@@ -0,0 +1,52 @@
+package channelserver
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Gacha play ledger query limits.
+const (
+	gachaHistoryDefaultLimit = 50
+	gachaHistoryMaxLimit     = 500
+)
+
+// ErrInvalidGachaHistory is returned for a malformed ledger query.
+var ErrInvalidGachaHistory = errors.New("invalid gacha history query")
+
+// GachaHistoryService reads the gacha play ledger for players, the admin API
+// and the liveops tool. GachaService writes it.
+type GachaHistoryService struct {
+	gachaRepo GachaRepo
+	logger    *zap.Logger
+}
+
+// NewGachaHistoryService creates a new GachaHistoryService.
+func NewGachaHistoryService(gr GachaRepo, log *zap.Logger) *GachaHistoryService {
+	return &GachaHistoryService{gachaRepo: gr, logger: log}
+}
+
+// History returns the plays matching f, newest first. A zero limit returns
+// the default number of plays; larger limits are capped.
+func (svc *GachaHistoryService) History(f GachaPlayFilter) ([]GachaPlay, error) {
+	if f.Limit < 0 {
+		return nil, fmt.Errorf("%w: limit must not be negative", ErrInvalidGachaHistory)
+	}
+	if f.Since != nil && f.Until != nil && !f.Since.Before(*f.Until) {
+		return nil, fmt.Errorf("%w: since must be before until", ErrInvalidGachaHistory)
+	}
+	if f.Limit == 0 {
+		f.Limit = gachaHistoryDefaultLimit
+	}
+	f.Limit = min(f.Limit, gachaHistoryMaxLimit)
+	plays, err := svc.gachaRepo.ListPlays(f)
+	if err != nil {
+		return nil, err
+	}
+	if plays == nil {
+		plays = []GachaPlay{}
+	}
+	return plays, nil
+}
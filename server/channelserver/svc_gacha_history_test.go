@@ -0,0 +1,115 @@
+package channelserver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestGachaService_RecordsPlays(t *testing.T) {
+	gr := &mockGachaRepo{
+		txItemType:   19,
+		txItemNumber: 5,
+		txRolls:      1,
+		rewardPool:   []GachaEntry{{ID: 10, Weight: 100, Rarity: 3}},
+		entryItems:   map[uint32][]GachaItem{10: {{ItemType: 7, ItemID: 500, Quantity: 2}}},
+		guaranteedItems: []GachaItem{
+			{ItemType: 7, ItemID: 700, Quantity: 1},
+		},
+	}
+	ur := &mockUserRepoGacha{trialCoins: 3}
+	svc := newTestGachaService(gr, ur, newMockCharacterRepo())
+
+	if _, err := svc.PlayNormalGacha(2, 20, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.PlayStepupGacha(2, 20, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.PlayBoxGacha(2, 20, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	svc.PlayFreeGacha(2, 20, 1, 3)
+
+	if len(gr.plays) != 4 {
+		t.Fatalf("recorded %d plays, want 4", len(gr.plays))
+	}
+	normal := gr.plays[0]
+	// 5 coins is more than the 3 trial coins, so premium coins are charged.
+	if normal.Kind != GachaPlayNormal || normal.UserID != 2 || normal.CharID != 20 || normal.CostType != 19 ||
+		normal.Cost != 5 || normal.Currency != GachaCurrencyPremiumCoins {
+		t.Errorf("normal play = %+v", normal)
+	}
+	if len(normal.Items) != 1 || normal.Items[0] != (GachaPlayItem{ItemType: 7, ItemID: 500, Quantity: 2, Rarity: 3}) {
+		t.Errorf("normal items = %+v", normal.Items)
+	}
+	stepup := gr.plays[1]
+	if stepup.Kind != GachaPlayStepup || len(stepup.Items) != 2 || !stepup.Items[0].Guaranteed || stepup.Items[1].Guaranteed {
+		t.Errorf("stepup play = %+v", stepup)
+	}
+	if gr.plays[2].Kind != GachaPlayBox || len(gr.plays[2].Items) != 1 {
+		t.Errorf("box play = %+v", gr.plays[2])
+	}
+	free := gr.plays[3]
+	if free.Kind != GachaPlayFree || free.RollType != 3 || free.Cost != 0 || free.Currency != "" || free.Items == nil {
+		t.Errorf("free play = %+v", free)
+	}
+}
+
+func TestGachaService_RecordFailureKeepsRoll(t *testing.T) {
+	gr := &mockGachaRepo{
+		txRolls:       1,
+		rewardPool:    []GachaEntry{{ID: 10, Weight: 100}},
+		entryItems:    map[uint32][]GachaItem{10: {{ItemType: 7, ItemID: 500, Quantity: 1}}},
+		insertPlayErr: errors.New("ledger down"),
+	}
+	svc := newTestGachaService(gr, &mockUserRepoGacha{}, newMockCharacterRepo())
+
+	result, err := svc.PlayNormalGacha(1, 1, 1, 0)
+	if err != nil || len(result.Rewards) != 1 {
+		t.Errorf("PlayNormalGacha = %+v, %v; want the reward despite the ledger error", result, err)
+	}
+}
+
+func TestGachaHistoryService_History(t *testing.T) {
+	gr := &mockGachaRepo{}
+	for i := 0; i < 60; i++ {
+		_ = gr.InsertPlay(GachaPlay{UserID: 1, CharID: uint32(10 + i%2), GachaID: 3, Kind: GachaPlayNormal})
+	}
+	svc := NewGachaHistoryService(gr, zap.NewNop())
+
+	plays, err := svc.History(GachaPlayFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plays) != gachaHistoryDefaultLimit || plays[0].ID != 60 {
+		t.Errorf("got %d plays starting at %d, want %d starting at 60", len(plays), plays[0].ID, gachaHistoryDefaultLimit)
+	}
+
+	plays, err = svc.History(GachaPlayFilter{CharID: 11, BeforeID: 10, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plays) != 2 || plays[0].ID != 8 || plays[1].ID != 6 {
+		t.Errorf("page = %+v, want plays 8 and 6", plays)
+	}
+
+	plays, err = svc.History(GachaPlayFilter{GachaID: 99})
+	if err != nil || plays == nil || len(plays) != 0 {
+		t.Errorf("no matches = %v, %v; want an empty slice", plays, err)
+	}
+
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	invalid := []GachaPlayFilter{
+		{Limit: -1},
+		{Since: &now, Until: &earlier},
+	}
+	for _, f := range invalid {
+		if _, err := svc.History(f); !errors.Is(err, ErrInvalidGachaHistory) {
+			t.Errorf("History(%+v): err = %v, want ErrInvalidGachaHistory", f, err)
+		}
+	}
+}
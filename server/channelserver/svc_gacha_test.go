@@ -296,12 +296,15 @@ func TestGachaService_Transact_NetcafeCoins(t *testing.T) {
 	}
 	svc := newTestGachaService(gr, &mockUserRepoGacha{}, cr)
 
-	rolls, err := svc.transact(1, 1, 1, 0)
+	cost, err := svc.transact(1, 1, 1, 0)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
-	if rolls != 1 {
-		t.Errorf("Rolls = %d, want 1", rolls)
+	if cost.Rolls != 1 {
+		t.Errorf("Rolls = %d, want 1", cost.Rolls)
+	}
+	if cost.Currency != GachaCurrencyNetcafePoints || cost.Amount != 100 {
+		t.Errorf("cost = %+v, want 100 netcafe points", cost)
 	}
 	// Netcafe points should have been reduced
 	if cr.ints["netcafe_points"] != 4900 {
@@ -313,14 +316,96 @@ func TestGachaService_SpendGachaCoin_TrialFirst(t *testing.T) {
 	ur := &mockUserRepoGacha{trialCoins: 100}
 	svc := newTestGachaService(&mockGachaRepo{}, ur, newMockCharacterRepo())
 
-	svc.spendGachaCoin(1, 50)
 	// Should have used trial coins, not premium
+	if got := svc.spendGachaCoin(1, 50); got != GachaCurrencyTrialCoins {
+		t.Errorf("charged %q, want %q", got, GachaCurrencyTrialCoins)
+	}
 }
 
 func TestGachaService_SpendGachaCoin_PremiumFallback(t *testing.T) {
 	ur := &mockUserRepoGacha{trialCoins: 10}
 	svc := newTestGachaService(&mockGachaRepo{}, ur, newMockCharacterRepo())
 
-	svc.spendGachaCoin(1, 50)
 	// Should have used premium coins since trial < quantity
+	if got := svc.spendGachaCoin(1, 50); got != GachaCurrencyPremiumCoins {
+		t.Errorf("charged %q, want %q", got, GachaCurrencyPremiumCoins)
+	}
+}
+
+func newSimulationGachaRepo(gachaType int) *mockGachaRepo {
+	return &mockGachaRepo{
+		shopType:   gachaType,
+		txRolls:    10,
+		rewardPool: []GachaEntry{{ID: 1, Weight: 90, Rarity: 1}, {ID: 2, Weight: 10, Rarity: 3}},
+		entryItems: map[uint32][]GachaItem{
+			1: {{ItemType: 7, ItemID: 100, Quantity: 2}},
+			2: {{ItemType: 7, ItemID: 200, Quantity: 1}},
+		},
+		guaranteedItems: []GachaItem{{ItemType: 7, ItemID: 300, Quantity: 1}},
+	}
+}
+
+func TestGachaService_SimulateDeterministic(t *testing.T) {
+	svc := newTestGachaService(newSimulationGachaRepo(0), nil, nil)
+
+	first, err := svc.Simulate(1, 0, 1000, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := svc.Simulate(1, 0, 1000, 42)
+	if first.Draws != 10000 {
+		t.Fatalf("draws = %d, want 10000", first.Draws)
+	}
+	for i := range first.Entries {
+		if first.Entries[i] != second.Entries[i] {
+			t.Fatalf("same seed gave %+v and %+v", first.Entries[i], second.Entries[i])
+		}
+	}
+	if f := first.Entries[1].Frequency; f < 0.08 || f > 0.12 {
+		t.Errorf("entry 2 frequency = %v, want about 0.1", f)
+	}
+	if len(first.Items) != 2 || first.Items[0].Quantity != 2*first.Entries[0].Hits {
+		t.Errorf("items = %+v", first.Items)
+	}
+
+	other, _ := svc.Simulate(1, 0, 1000, 43)
+	if other.Entries[0].Hits == first.Entries[0].Hits && other.Entries[1].Hits == first.Entries[1].Hits {
+		t.Error("different seeds gave identical results")
+	}
+}
+
+func TestGachaService_SimulateStepupAndBox(t *testing.T) {
+	stepup := newTestGachaService(newSimulationGachaRepo(gachaTypeStepup), nil, nil)
+	sim, err := stepup.Simulate(1, 0, 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := sim.Items[len(sim.Items)-1]
+	if !last.Guaranteed || last.ItemID != 300 || last.Awarded != 5 {
+		t.Errorf("guaranteed item = %+v, want item 300 awarded every play", last)
+	}
+
+	box := newTestGachaService(newSimulationGachaRepo(gachaTypeBox), nil, nil)
+	sim, err = box.Simulate(1, 0, 50, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A box play draws each entry at most once, so 10 rolls from 2 entries
+	// draw both every time.
+	if sim.Draws != 100 || sim.Entries[0].Hits != 50 || sim.Entries[1].Hits != 50 {
+		t.Errorf("box simulation = %+v", sim)
+	}
+}
+
+func TestGachaService_SimulateNotPlayable(t *testing.T) {
+	gr := newSimulationGachaRepo(0)
+	gr.rewardPool = []GachaEntry{{ID: 1}}
+	svc := newTestGachaService(gr, nil, nil)
+	if _, err := svc.Simulate(1, 0, 1, 1); !errors.Is(err, ErrGachaNotPlayable) {
+		t.Errorf("zero weight: err = %v, want ErrGachaNotPlayable", err)
+	}
+	gr.rewardPool = nil
+	if _, err := svc.Simulate(1, 0, 1, 1); !errors.Is(err, ErrGachaNotPlayable) {
+		t.Errorf("empty pool: err = %v, want ErrGachaNotPlayable", err)
+	}
 }
@@ -0,0 +1,200 @@
+package channelserver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"erupe-ce/common/stringsupport"
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+// Guild audit actions recorded by the GuildService admin methods.
+const (
+	GuildAuditRank      = "rank"
+	GuildAuditRankRP    = "rank_rp"
+	GuildAuditName      = "name"
+	GuildAuditLeader    = "leader"
+	GuildAuditGuildcard = "guildcard"
+)
+
+// GuildAuditSourceAPI marks administrative guild changes made through the
+// admin API.
+const GuildAuditSourceAPI = "api"
+
+// Limits of the guilds table columns edited by the admin methods.
+const (
+	guildNameMaxLength    = 24
+	guildCommentMaxLength = 255
+)
+
+// ErrGuildNotFound is returned when the guild being administered does not exist.
+var ErrGuildNotFound = errors.New("guild not found")
+
+// ErrInvalidGuildRank is returned when a forced rank does not exist for the client mode.
+var ErrInvalidGuildRank = errors.New("invalid guild rank")
+
+// ErrInvalidGuildEdit is returned when a new guild name or comment is rejected.
+var ErrInvalidGuildEdit = errors.New("invalid guild edit")
+
+// ErrNotGuildMember is returned when a new leader is not a member of the guild.
+var ErrNotGuildMember = errors.New("character is not a member of the guild")
+
+// GuildAuditActor identifies the operator behind an administrative guild
+// change: a user ID for GuildAuditSourceAPI.
+type GuildAuditActor struct {
+	Source string
+	ID     uint32
+}
+
+// GuildCard is the public guild information edited by UpdateGuildcard.
+type GuildCard struct {
+	MainMotto uint8  `json:"mainMotto"`
+	SubMotto  uint8  `json:"subMotto"`
+	Comment   string `json:"comment"`
+}
+
+func (c GuildCard) String() string {
+	return fmt.Sprintf("motto %d/%d comment %q", c.MainMotto, c.SubMotto, c.Comment)
+}
+
+func (svc *GuildService) adminGuild(guildID uint32) (*Guild, error) {
+	guild, err := svc.guildRepo.GetByID(guildID)
+	if err != nil {
+		return nil, fmt.Errorf("guild lookup: %w", err)
+	}
+	if guild == nil {
+		return nil, ErrGuildNotFound
+	}
+	return guild, nil
+}
+
+func newGuildAuditEntry(actor GuildAuditActor, guildID uint32, action, oldValue, newValue string) GuildAuditEntry {
+	return GuildAuditEntry{
+		GuildID:     guildID,
+		ActorSource: actor.Source,
+		ActorID:     actor.ID,
+		Action:      action,
+		OldValue:    oldValue,
+		NewValue:    newValue,
+	}
+}
+
+func (svc *GuildService) logAdminChange(entry GuildAuditEntry) {
+	svc.logger.Info("Guild changed by operator",
+		zap.Uint32("guildID", entry.GuildID), zap.String("action", entry.Action),
+		zap.String("source", entry.ActorSource), zap.Uint32("actorID", entry.ActorID),
+		zap.String("old", entry.OldValue), zap.String("new", entry.NewValue))
+}
+
+// ForceRank sets a guild's rank RP to the minimum of the given rank under
+// mode's thresholds.
+func (svc *GuildService) ForceRank(actor GuildAuditActor, guildID uint32, rank uint16, mode cfg.Mode) (*Guild, error) {
+	rp, ok := GuildRankRP(rank, mode)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d is above the client's maximum of %d", ErrInvalidGuildRank, rank, maxGuildRank(mode))
+	}
+	guild, err := svc.adminGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	entry := newGuildAuditEntry(actor, guildID, GuildAuditRank,
+		fmt.Sprintf("%d (%d RP)", guild.Rank(mode), guild.RankRP), fmt.Sprintf("%d (%d RP)", rank, rp))
+	if err := svc.guildRepo.AdminSetRankRP(guildID, rp, entry); err != nil {
+		return nil, fmt.Errorf("set rank RP: %w", err)
+	}
+	svc.logAdminChange(entry)
+	return svc.adminGuild(guildID)
+}
+
+// SetRankRP sets a guild's rank RP directly.
+func (svc *GuildService) SetRankRP(actor GuildAuditActor, guildID, rp uint32) (*Guild, error) {
+	guild, err := svc.adminGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	entry := newGuildAuditEntry(actor, guildID, GuildAuditRankRP,
+		fmt.Sprint(guild.RankRP), fmt.Sprint(rp))
+	if err := svc.guildRepo.AdminSetRankRP(guildID, rp, entry); err != nil {
+		return nil, fmt.Errorf("set rank RP: %w", err)
+	}
+	svc.logAdminChange(entry)
+	return svc.adminGuild(guildID)
+}
+
+// Rename changes a guild's name. The name is trimmed and must fit the
+// guilds.name column and be representable in Shift-JIS.
+func (svc *GuildService) Rename(actor GuildAuditActor, guildID uint32, name string) (*Guild, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return nil, fmt.Errorf("%w: name is empty", ErrInvalidGuildEdit)
+	case utf8.RuneCountInString(name) > guildNameMaxLength:
+		return nil, fmt.Errorf("%w: name is longer than %d characters", ErrInvalidGuildEdit, guildNameMaxLength)
+	case stringsupport.SJISToUTF8Lossy(stringsupport.UTF8ToSJIS(name)) != name:
+		return nil, fmt.Errorf("%w: name contains characters the client cannot display", ErrInvalidGuildEdit)
+	}
+	guild, err := svc.adminGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	entry := newGuildAuditEntry(actor, guildID, GuildAuditName, guild.Name, name)
+	if err := svc.guildRepo.AdminRename(guildID, name, entry); err != nil {
+		return nil, fmt.Errorf("rename guild: %w", err)
+	}
+	svc.logAdminChange(entry)
+	return svc.adminGuild(guildID)
+}
+
+// TransferLeader makes charID the guild's leader. The character must be a
+// member of the guild; applicants are rejected with ErrNotGuildMember.
+// Transferring to the current leader changes nothing and records no entry.
+func (svc *GuildService) TransferLeader(actor GuildAuditActor, guildID, charID uint32) (*Guild, error) {
+	guild, err := svc.adminGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	if guild.LeaderCharID == charID {
+		return guild, nil
+	}
+	member, err := svc.guildRepo.GetCharacterMembership(charID)
+	if err != nil {
+		return nil, fmt.Errorf("membership lookup: %w", err)
+	}
+	if member == nil || member.GuildID != guildID || member.IsApplicant {
+		return nil, ErrNotGuildMember
+	}
+	entry := newGuildAuditEntry(actor, guildID, GuildAuditLeader,
+		fmt.Sprintf("%d %s", guild.LeaderCharID, guild.LeaderName), fmt.Sprintf("%d %s", charID, member.Name))
+	if err := svc.guildRepo.AdminTransferLeader(guildID, guild.LeaderCharID, charID, entry); err != nil {
+		return nil, fmt.Errorf("transfer leadership: %w", err)
+	}
+	svc.logAdminChange(entry)
+	return svc.adminGuild(guildID)
+}
+
+// UpdateGuildcard replaces a guild's mottos and comment.
+func (svc *GuildService) UpdateGuildcard(actor GuildAuditActor, guildID uint32, card GuildCard) (*Guild, error) {
+	if utf8.RuneCountInString(card.Comment) > guildCommentMaxLength {
+		return nil, fmt.Errorf("%w: comment is longer than %d characters", ErrInvalidGuildEdit, guildCommentMaxLength)
+	}
+	guild, err := svc.adminGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	old := GuildCard{MainMotto: guild.MainMotto, SubMotto: guild.SubMotto, Comment: guild.Comment}
+	entry := newGuildAuditEntry(actor, guildID, GuildAuditGuildcard, old.String(), card.String())
+	if err := svc.guildRepo.AdminUpdateGuildcard(guildID, card.MainMotto, card.SubMotto, card.Comment, entry); err != nil {
+		return nil, fmt.Errorf("update guild card: %w", err)
+	}
+	svc.logAdminChange(entry)
+	return svc.adminGuild(guildID)
+}
+
+// AuditLog returns up to limit administrative changes to a guild, newest first.
+func (svc *GuildService) AuditLog(guildID uint32, limit int) ([]*GuildAuditEntry, error) {
+	return svc.guildRepo.ListAudit(guildID, limit)
+}
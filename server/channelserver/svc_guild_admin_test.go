@@ -0,0 +1,174 @@
+package channelserver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cfg "erupe-ce/config"
+)
+
+var testGuildAuditActor = GuildAuditActor{Source: GuildAuditSourceAPI, ID: 1}
+
+func newGuildAdminMock() *mockGuildRepo {
+	return &mockGuildRepo{
+		guild: &Guild{ID: 10, Name: "Old", RankRP: 50, GuildLeader: GuildLeader{LeaderCharID: 1, LeaderName: "Leader"}},
+	}
+}
+
+func TestGuildRankRP(t *testing.T) {
+	for _, mode := range []cfg.Mode{cfg.S6, cfg.F5, cfg.G32, cfg.Z2, cfg.ZZ} {
+		for rank := uint16(0); rank <= maxGuildRank(mode); rank++ {
+			rp, ok := GuildRankRP(rank, mode)
+			if !ok {
+				t.Fatalf("mode %d: rank %d rejected", mode, rank)
+			}
+			if got := (&Guild{RankRP: rp}).Rank(mode); got != rank {
+				t.Errorf("mode %d: rank %d -> %d RP -> rank %d", mode, rank, rp, got)
+			}
+		}
+		if _, ok := GuildRankRP(maxGuildRank(mode)+1, mode); ok {
+			t.Errorf("mode %d: rank above maximum accepted", mode)
+		}
+	}
+}
+
+func TestGuildService_ForceRank(t *testing.T) {
+	mock := newGuildAdminMock()
+	svc := newTestGuildService(mock, &mockMailRepo{})
+
+	guild, err := svc.ForceRank(testGuildAuditActor, 10, 10, cfg.ZZ)
+	if err != nil {
+		t.Fatalf("ForceRank: %v", err)
+	}
+	if guild.RankRP != 504 || guild.Rank(cfg.ZZ) != 10 {
+		t.Errorf("rank RP = %d, rank %d", guild.RankRP, guild.Rank(cfg.ZZ))
+	}
+	want := GuildAuditEntry{GuildID: 10, ActorSource: GuildAuditSourceAPI, ActorID: 1,
+		Action: GuildAuditRank, OldValue: "2 (50 RP)", NewValue: "10 (504 RP)"}
+	if len(mock.auditEntries) != 1 || mock.auditEntries[0] != want {
+		t.Errorf("audit = %+v, want %+v", mock.auditEntries, want)
+	}
+
+	if _, err := svc.ForceRank(testGuildAuditActor, 10, 13, cfg.S6); !errors.Is(err, ErrInvalidGuildRank) {
+		t.Errorf("rank 13 on S6: err = %v, want ErrInvalidGuildRank", err)
+	}
+}
+
+func TestGuildService_SetRankRP(t *testing.T) {
+	mock := newGuildAdminMock()
+	svc := newTestGuildService(mock, &mockMailRepo{})
+
+	if _, err := svc.SetRankRP(testGuildAuditActor, 10, 777); err != nil {
+		t.Fatal(err)
+	}
+	if mock.guild.RankRP != 777 || mock.auditEntries[0].OldValue != "50" || mock.auditEntries[0].NewValue != "777" {
+		t.Errorf("guild %+v audit %+v", mock.guild, mock.auditEntries)
+	}
+}
+
+func TestGuildService_Rename(t *testing.T) {
+	mock := newGuildAdminMock()
+	svc := newTestGuildService(mock, &mockMailRepo{})
+
+	guild, err := svc.Rename(testGuildAuditActor, 10, "  New Name ")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if guild.Name != "New Name" || mock.auditEntries[0].OldValue != "Old" {
+		t.Errorf("name %q audit %+v", guild.Name, mock.auditEntries)
+	}
+
+	for _, name := range []string{"", "   ", strings.Repeat("a", 25), "Guild 🙂"} {
+		if _, err := svc.Rename(testGuildAuditActor, 10, name); !errors.Is(err, ErrInvalidGuildEdit) {
+			t.Errorf("Rename(%q): err = %v, want ErrInvalidGuildEdit", name, err)
+		}
+	}
+	if len(mock.auditEntries) != 1 {
+		t.Errorf("rejected renames were audited: %+v", mock.auditEntries)
+	}
+}
+
+func TestGuildService_TransferLeader(t *testing.T) {
+	mock := newGuildAdminMock()
+	mock.membership = &GuildMember{GuildID: 10, CharID: 2, Name: "Member"}
+	svc := newTestGuildService(mock, &mockMailRepo{})
+
+	guild, err := svc.TransferLeader(testGuildAuditActor, 10, 2)
+	if err != nil {
+		t.Fatalf("TransferLeader: %v", err)
+	}
+	if guild.LeaderCharID != 2 || len(mock.transferArgs) != 2 || mock.transferArgs[0] != 1 {
+		t.Errorf("leader %d transfer args %v", guild.LeaderCharID, mock.transferArgs)
+	}
+	if e := mock.auditEntries[0]; e.OldValue != "1 Leader" || e.NewValue != "2 Member" {
+		t.Errorf("audit = %+v", e)
+	}
+
+	// Already the leader: nothing to do.
+	if _, err := svc.TransferLeader(testGuildAuditActor, 10, 2); err != nil || len(mock.auditEntries) != 1 {
+		t.Errorf("no-op transfer: err %v, %d audit entries", err, len(mock.auditEntries))
+	}
+}
+
+func TestGuildService_TransferLeader_NotMember(t *testing.T) {
+	tests := []struct {
+		name       string
+		membership *GuildMember
+	}{
+		{"no guild", nil},
+		{"other guild", &GuildMember{GuildID: 11, CharID: 2}},
+		{"applicant", &GuildMember{GuildID: 10, CharID: 2, IsApplicant: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newGuildAdminMock()
+			mock.membership = tt.membership
+			svc := newTestGuildService(mock, &mockMailRepo{})
+
+			if _, err := svc.TransferLeader(testGuildAuditActor, 10, 2); !errors.Is(err, ErrNotGuildMember) {
+				t.Errorf("err = %v, want ErrNotGuildMember", err)
+			}
+			if mock.guild.LeaderCharID != 1 {
+				t.Error("leader changed")
+			}
+		})
+	}
+}
+
+func TestGuildService_UpdateGuildcard(t *testing.T) {
+	mock := newGuildAdminMock()
+	svc := newTestGuildService(mock, &mockMailRepo{})
+
+	guild, err := svc.UpdateGuildcard(testGuildAuditActor, 10, GuildCard{MainMotto: 1, SubMotto: 2, Comment: "Hello"})
+	if err != nil {
+		t.Fatalf("UpdateGuildcard: %v", err)
+	}
+	if guild.MainMotto != 1 || guild.SubMotto != 2 || guild.Comment != "Hello" {
+		t.Errorf("guild = %+v", guild)
+	}
+	if e := mock.auditEntries[0]; e.Action != GuildAuditGuildcard || !strings.Contains(e.NewValue, `"Hello"`) {
+		t.Errorf("audit = %+v", e)
+	}
+
+	long := GuildCard{Comment: strings.Repeat("a", 256)}
+	if _, err := svc.UpdateGuildcard(testGuildAuditActor, 10, long); !errors.Is(err, ErrInvalidGuildEdit) {
+		t.Errorf("long comment: err = %v, want ErrInvalidGuildEdit", err)
+	}
+}
+
+func TestGuildService_AdminErrors(t *testing.T) {
+	mock := newGuildAdminMock()
+	svc := newTestGuildService(mock, &mockMailRepo{})
+
+	if _, err := svc.SetRankRP(testGuildAuditActor, 99, 1); err == nil {
+		t.Error("missing guild accepted")
+	}
+	mock.adminErr = errors.New("db down")
+	if _, err := svc.Rename(testGuildAuditActor, 10, "New"); err == nil {
+		t.Error("repo error swallowed")
+	}
+	if mock.guild.Name != "Old" {
+		t.Error("guild renamed despite the repo error")
+	}
+}
@@ -0,0 +1,198 @@
+package channelserver
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+const (
+	mailBroadcastBatchSize    = 200
+	mailBroadcastMaxItems     = 10
+	mailBroadcastMaxKeyLen    = 64
+	mailBroadcastDefaultLimit = 20
+)
+
+var (
+	// ErrMailBroadcastNotFound is returned when a broadcast does not exist.
+	ErrMailBroadcastNotFound = errors.New("mail broadcast not found")
+	// ErrInvalidMailBroadcast is returned when a broadcast request fails
+	// validation.
+	ErrInvalidMailBroadcast = errors.New("invalid mail broadcast")
+)
+
+// MailNotifier delivers the new mail popup to a character if they are
+// online. ChannelRegistry satisfies it.
+type MailNotifier interface {
+	NotifyMailToCharID(charID uint32, sender *Session, mail *Mail)
+}
+
+// MailBroadcastService sends admin mail to every character matching a
+// filter, in resumable batches.
+type MailBroadcastService struct {
+	broadcastRepo MailBroadcastRepo
+	logger        *zap.Logger
+
+	mu       sync.Mutex
+	notifier MailNotifier
+}
+
+// NewMailBroadcastService creates a new MailBroadcastService.
+func NewMailBroadcastService(br MailBroadcastRepo, log *zap.Logger) *MailBroadcastService {
+	return &MailBroadcastService{
+		broadcastRepo: br,
+		logger:        log,
+	}
+}
+
+// SetNotifier sets where new mail popups are sent. Without one, recipients
+// see the mail the next time they open their mailbox.
+func (svc *MailBroadcastService) SetNotifier(n MailNotifier) {
+	svc.mu.Lock()
+	svc.notifier = n
+	svc.mu.Unlock()
+}
+
+// Create validates and stores a broadcast. If a broadcast with the same
+// idempotency key exists it is returned instead, with created false.
+func (svc *MailBroadcastService) Create(b MailBroadcast) (*MailBroadcast, bool, error) {
+	if err := validateMailBroadcast(b); err != nil {
+		return nil, false, err
+	}
+	total, err := svc.broadcastRepo.CountRecipients(b.Filter)
+	if err != nil {
+		return nil, false, err
+	}
+	b.Total = total
+	stored, created, err := svc.broadcastRepo.Create(b)
+	if err != nil {
+		return nil, false, err
+	}
+	if created {
+		svc.logger.Info("Mail broadcast created", zap.Uint32("broadcastID", stored.ID),
+			zap.String("key", stored.IdempotencyKey), zap.Int("recipients", total))
+	}
+	return stored, created, nil
+}
+
+// validateMailBroadcast checks a broadcast request.
+func validateMailBroadcast(b MailBroadcast) error {
+	switch {
+	case b.IdempotencyKey == "" || len(b.IdempotencyKey) > mailBroadcastMaxKeyLen:
+		return fmt.Errorf("%w: idempotency key must be 1-%d characters", ErrInvalidMailBroadcast, mailBroadcastMaxKeyLen)
+	case b.SenderID == 0:
+		return fmt.Errorf("%w: sender character is required", ErrInvalidMailBroadcast)
+	case b.Subject == "":
+		return fmt.Errorf("%w: subject is required", ErrInvalidMailBroadcast)
+	case len(b.Items) > mailBroadcastMaxItems:
+		return fmt.Errorf("%w: at most %d items", ErrInvalidMailBroadcast, mailBroadcastMaxItems)
+	}
+	for _, item := range b.Items {
+		if item.ItemID == 0 || item.Quantity == 0 {
+			return fmt.Errorf("%w: items need an ID and a quantity", ErrInvalidMailBroadcast)
+		}
+	}
+	f := b.Filter
+	switch {
+	case !f.All && f.empty():
+		return fmt.Errorf("%w: filter is empty; set all to mail every character", ErrInvalidMailBroadcast)
+	case f.MaxHR > 0 && f.MinHR > f.MaxHR:
+		return fmt.Errorf("%w: minHr is above maxHr", ErrInvalidMailBroadcast)
+	case f.MaxGR > 0 && f.MinGR > f.MaxGR:
+		return fmt.Errorf("%w: minGr is above maxGr", ErrInvalidMailBroadcast)
+	case f.LoginSince != nil && f.LoginBefore != nil && !f.LoginSince.Before(*f.LoginBefore):
+		return fmt.Errorf("%w: loginSince must be before loginBefore", ErrInvalidMailBroadcast)
+	}
+	return nil
+}
+
+// mailBroadcastMails returns the mails each recipient receives. A mail holds
+// a single attachment, so a broadcast with several items sends one mail per
+// item, numbered in the subject.
+func mailBroadcastMails(b *MailBroadcast) []mailBroadcastMail {
+	if len(b.Items) == 0 {
+		return []mailBroadcastMail{{Subject: b.Subject}}
+	}
+	mails := make([]mailBroadcastMail, len(b.Items))
+	for i, item := range b.Items {
+		subject := b.Subject
+		if len(b.Items) > 1 {
+			subject = fmt.Sprintf("%s (%d/%d)", b.Subject, i+1, len(b.Items))
+		}
+		mails[i] = mailBroadcastMail{Subject: subject, ItemID: item.ItemID, Quantity: item.Quantity}
+	}
+	return mails
+}
+
+// Get returns a broadcast.
+func (svc *MailBroadcastService) Get(id uint32) (*MailBroadcast, error) {
+	b, err := svc.broadcastRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, fmt.Errorf("%w: %d", ErrMailBroadcastNotFound, id)
+	}
+	return b, nil
+}
+
+// List returns the most recent broadcasts, newest first.
+func (svc *MailBroadcastService) List(limit int) ([]MailBroadcast, error) {
+	if limit <= 0 {
+		limit = mailBroadcastDefaultLimit
+	}
+	return svc.broadcastRepo.List(limit)
+}
+
+// Send mails the broadcast's remaining recipients batch by batch, notifying
+// those who are online, and returns the broadcast once it has finished. It
+// picks up from the stored cursor, so calling it again after a failure or
+// restart resumes the broadcast, and calling it on a finished broadcast does
+// nothing.
+func (svc *MailBroadcastService) Send(id uint32) (*MailBroadcast, error) {
+	b, err := svc.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if b.FinishedAt != nil {
+		return b, nil
+	}
+	mails := mailBroadcastMails(b)
+	for {
+		recipients, done, err := svc.broadcastRepo.SendBatch(id, mails, mailBroadcastBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("send broadcast %d batch: %w", id, err)
+		}
+		svc.notify(b, mails[len(mails)-1], recipients)
+		if done {
+			break
+		}
+	}
+	b, err = svc.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	svc.logger.Info("Mail broadcast finished", zap.Uint32("broadcastID", id), zap.Int("sent", b.Sent))
+	return b, nil
+}
+
+// notify sends the new mail popup to the batch's online recipients.
+func (svc *MailBroadcastService) notify(b *MailBroadcast, last mailBroadcastMail, recipients []uint32) {
+	svc.mu.Lock()
+	notifier := svc.notifier
+	svc.mu.Unlock()
+	if notifier == nil {
+		return
+	}
+	for _, cid := range recipients {
+		notifier.NotifyMailToCharID(cid, nil, &Mail{
+			SenderID:        b.SenderID,
+			SenderName:      b.SenderName,
+			RecipientID:     cid,
+			Subject:         last.Subject,
+			IsSystemMessage: true,
+		})
+	}
+}
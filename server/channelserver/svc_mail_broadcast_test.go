@@ -0,0 +1,179 @@
+package channelserver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type recordingMailNotifier struct {
+	notified []*Mail
+}
+
+func (n *recordingMailNotifier) NotifyMailToCharID(charID uint32, sender *Session, mail *Mail) {
+	n.notified = append(n.notified, mail)
+}
+
+func newTestMailBroadcast() MailBroadcast {
+	return MailBroadcast{
+		IdempotencyKey: "maint-2026-10-17",
+		SenderID:       1,
+		SenderName:     "Admin",
+		Subject:        "Maintenance compensation",
+		Body:           "Sorry for the downtime.",
+		Filter:         MailBroadcastFilter{All: true},
+		Items:          []MailBroadcastItem{{ItemID: 100, Quantity: 5}, {ItemID: 200, Quantity: 1}},
+	}
+}
+
+func TestValidateMailBroadcast(t *testing.T) {
+	since := time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		modify func(b *MailBroadcast)
+	}{
+		{"missing key", func(b *MailBroadcast) { b.IdempotencyKey = "" }},
+		{"missing sender", func(b *MailBroadcast) { b.SenderID = 0 }},
+		{"missing subject", func(b *MailBroadcast) { b.Subject = "" }},
+		{"empty filter", func(b *MailBroadcast) { b.Filter = MailBroadcastFilter{} }},
+		{"zero quantity", func(b *MailBroadcast) { b.Items[0].Quantity = 0 }},
+		{"too many items", func(b *MailBroadcast) { b.Items = make([]MailBroadcastItem, mailBroadcastMaxItems+1) }},
+		{"inverted HR range", func(b *MailBroadcast) { b.Filter = MailBroadcastFilter{MinHR: 100, MaxHR: 50} }},
+		{"inverted login window", func(b *MailBroadcast) {
+			before := since.Add(-time.Hour)
+			b.Filter = MailBroadcastFilter{LoginSince: &since, LoginBefore: &before}
+		}},
+	}
+	if err := validateMailBroadcast(newTestMailBroadcast()); err != nil {
+		t.Fatalf("valid broadcast rejected: %v", err)
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := newTestMailBroadcast()
+			tc.modify(&b)
+			if err := validateMailBroadcast(b); !errors.Is(err, ErrInvalidMailBroadcast) {
+				t.Errorf("err = %v, want ErrInvalidMailBroadcast", err)
+			}
+		})
+	}
+}
+
+func TestMailBroadcastMails(t *testing.T) {
+	b := newTestMailBroadcast()
+	mails := mailBroadcastMails(&b)
+	if len(mails) != 2 || mails[0].Subject != "Maintenance compensation (1/2)" || mails[1].ItemID != 200 {
+		t.Errorf("mails = %+v", mails)
+	}
+	b.Items = nil
+	if mails := mailBroadcastMails(&b); len(mails) != 1 || mails[0].Subject != b.Subject || mails[0].ItemID != 0 {
+		t.Errorf("mails without items = %+v", mails)
+	}
+}
+
+func TestMailBroadcastService_CreateIsIdempotent(t *testing.T) {
+	repo := &mockMailBroadcastRepo{recipients: []uint32{1, 2, 3}}
+	svc := NewMailBroadcastService(repo, zap.NewNop())
+
+	first, created, err := svc.Create(newTestMailBroadcast())
+	if err != nil || !created {
+		t.Fatalf("Create = %v, %v", created, err)
+	}
+	if first.Total != 3 {
+		t.Errorf("total = %d, want 3", first.Total)
+	}
+	second, created, err := svc.Create(newTestMailBroadcast())
+	if err != nil || created || second.ID != first.ID {
+		t.Errorf("repeated Create = %+v, %v, %v, want the existing broadcast", second, created, err)
+	}
+}
+
+func TestMailBroadcastService_SendBatchesAndNotifies(t *testing.T) {
+	recipients := make([]uint32, mailBroadcastBatchSize+5)
+	for i := range recipients {
+		recipients[i] = uint32(i + 1)
+	}
+	repo := &mockMailBroadcastRepo{recipients: recipients}
+	svc := NewMailBroadcastService(repo, zap.NewNop())
+	notifier := &recordingMailNotifier{}
+	svc.SetNotifier(notifier)
+	b, _, _ := svc.Create(newTestMailBroadcast())
+
+	done, err := svc.Send(b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.FinishedAt == nil || done.Sent != len(recipients) {
+		t.Errorf("broadcast = %+v, want finished with every recipient sent", done)
+	}
+	if repo.batches != 2 {
+		t.Errorf("batches = %d, want 2", repo.batches)
+	}
+	if len(repo.mailed[1]) != 2 || repo.mailed[1][1].ItemID != 200 {
+		t.Errorf("mails to char 1 = %+v, want one per item", repo.mailed[1])
+	}
+	if len(notifier.notified) != len(recipients) || notifier.notified[0].SenderName != "Admin" {
+		t.Errorf("notified %d recipients, want %d", len(notifier.notified), len(recipients))
+	}
+}
+
+func TestMailBroadcastService_SendResumes(t *testing.T) {
+	recipients := make([]uint32, 2*mailBroadcastBatchSize+1)
+	for i := range recipients {
+		recipients[i] = uint32(i + 1)
+	}
+	repo := &mockMailBroadcastRepo{recipients: recipients, failAfter: 1}
+	svc := NewMailBroadcastService(repo, zap.NewNop())
+	b, _, _ := svc.Create(newTestMailBroadcast())
+
+	if _, err := svc.Send(b.ID); err == nil {
+		t.Fatal("Send succeeded despite a failing batch")
+	}
+	if got, _ := svc.Get(b.ID); got.Sent != mailBroadcastBatchSize || got.FinishedAt != nil {
+		t.Fatalf("after failure = %+v, want one batch sent", got)
+	}
+
+	repo.failAfter = 0
+	done, err := svc.Send(b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.Sent != len(recipients) {
+		t.Errorf("sent = %d, want %d", done.Sent, len(recipients))
+	}
+	for cid, mails := range repo.mailed {
+		if len(mails) != 2 {
+			t.Fatalf("char %d received %d mails, want 2", cid, len(mails))
+		}
+	}
+	if _, err := svc.Send(b.ID); err != nil || repo.batches != 3 {
+		t.Errorf("Send on a finished broadcast = %v, batches = %d", err, repo.batches)
+	}
+}
+
+func TestMailBroadcastService_GetNotFound(t *testing.T) {
+	svc := NewMailBroadcastService(&mockMailBroadcastRepo{}, zap.NewNop())
+	if _, err := svc.Get(9); !errors.Is(err, ErrMailBroadcastNotFound) {
+		t.Errorf("err = %v, want ErrMailBroadcastNotFound", err)
+	}
+	if _, err := svc.Send(9); !errors.Is(err, ErrMailBroadcastNotFound) {
+		t.Errorf("Send err = %v, want ErrMailBroadcastNotFound", err)
+	}
+}
+
+func TestMailBroadcastWhere(t *testing.T) {
+	since := time.Unix(1760000000, 0)
+	where, args := mailBroadcastWhere(MailBroadcastFilter{MinHR: 100, GuildID: 7, CharIDs: []uint32{1, 2}, LoginSince: &since}, []interface{}{uint32(0)})
+	want := "c.deleted = false AND c.hr >= $2 AND c.last_login >= $3 AND " +
+		"EXISTS (SELECT 1 FROM guild_characters gc WHERE gc.character_id = c.id AND gc.guild_id = $4) AND c.id = ANY($5)"
+	if where != want {
+		t.Errorf("where = %q\nwant %q", where, want)
+	}
+	if len(args) != 5 || args[2] != since.Unix() {
+		t.Errorf("args = %v", args)
+	}
+	if where, _ := mailBroadcastWhere(MailBroadcastFilter{All: true}, nil); where != "c.deleted = false" {
+		t.Errorf("all: where = %q", where)
+	}
+}
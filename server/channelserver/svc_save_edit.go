@@ -0,0 +1,133 @@
+package channelserver
+
+import (
+	"errors"
+	"fmt"
+
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+// SaveEdit is one field assignment for SaveEditService.Edit. Field takes the
+// names listed by SaveFieldNames.
+type SaveEdit struct {
+	Field string
+	Value string
+}
+
+// SaveEditService inspects and edits a character's stored savedata through
+// the SavePointer layout, for support tooling.
+type SaveEditService struct {
+	charRepo CharacterRepo
+	mode     cfg.Mode
+	logger   *zap.Logger
+}
+
+// NewSaveEditService creates a SaveEditService parsing saves with the layout
+// of the given client mode.
+func NewSaveEditService(cr CharacterRepo, mode cfg.Mode, log *zap.Logger) *SaveEditService {
+	return &SaveEditService{
+		charRepo: cr,
+		mode:     mode,
+		logger:   log,
+	}
+}
+
+func (svc *SaveEditService) load(charID uint32) ([]byte, *CharacterSaveData, error) {
+	_, comp, _, _, _, err := svc.charRepo.LoadSaveDataWithHash(charID)
+	if err != nil {
+		return nil, nil, err
+	}
+	save, err := DecodeSave(charID, svc.mode, comp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return comp, save, nil
+}
+
+// Inspect returns every mapped field of the character's live save.
+func (svc *SaveEditService) Inspect(charID uint32) (SaveFields, error) {
+	_, save, err := svc.load(charID)
+	if err != nil {
+		return SaveFields{}, err
+	}
+	return save.Fields(), nil
+}
+
+// Preview applies edits to a copy of the live save and returns the result
+// without writing it.
+func (svc *SaveEditService) Preview(charID uint32, edits []SaveEdit) (SaveFields, error) {
+	_, save, err := svc.load(charID)
+	if err != nil {
+		return SaveFields{}, err
+	}
+	if err := applySaveEdits(save, edits); err != nil {
+		return SaveFields{}, err
+	}
+	return save.Fields(), nil
+}
+
+// Edit applies edits to the live save and writes it back through the atomic
+// save path. Every edit is validated before anything is written. The save
+// being replaced goes into a backup slot, so SaveHistoryService.RestoreBackup
+// can undo the edit. Fails with ErrCharacterOnline while the character is
+// logged in and ErrSaveChanged if the game saved in the meantime.
+func (svc *SaveEditService) Edit(charID uint32, edits []SaveEdit) (SaveFields, error) {
+	prev, save, err := svc.load(charID)
+	if err != nil {
+		return SaveFields{}, err
+	}
+	if err := applySaveEdits(save, edits); err != nil {
+		return SaveFields{}, err
+	}
+	if err := save.encode(); err != nil {
+		return SaveFields{}, fmt.Errorf("compress savedata: %w", err)
+	}
+	params := save.atomicParams()
+
+	backups, err := svc.charRepo.LoadBackupsByRecency(charID)
+	if err != nil {
+		return SaveFields{}, err
+	}
+	params.BackupSlot = nextEditBackupSlot(backups)
+	params.BackupData = prev
+
+	if err := svc.charRepo.ReplaceSaveDataAtomic(prev, params); err != nil {
+		return SaveFields{}, err
+	}
+	fields := make([]string, 0, len(edits))
+	for _, e := range edits {
+		fields = append(fields, e.Field+"="+e.Value)
+	}
+	svc.logger.Info("Savedata edited",
+		zap.Uint32("charID", charID), zap.Strings("edits", fields), zap.Int("backupSlot", params.BackupSlot))
+	return save.Fields(), nil
+}
+
+func applySaveEdits(save *CharacterSaveData, edits []SaveEdit) error {
+	if len(edits) == 0 {
+		return errors.New("no edits given")
+	}
+	for _, e := range edits {
+		if err := save.SetField(e.Field, e.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextEditBackupSlot returns the first unused backup slot, or the least
+// recently written one when all are in use.
+func nextEditBackupSlot(backups []SavedataBackup) int {
+	used := make(map[int]bool, len(backups))
+	for _, b := range backups {
+		used[b.Slot] = true
+	}
+	for slot := 0; slot < saveBackupSlots; slot++ {
+		if !used[slot] {
+			return slot
+		}
+	}
+	return backups[len(backups)-1].Slot
+}
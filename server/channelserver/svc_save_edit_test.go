@@ -0,0 +1,116 @@
+package channelserver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+func newTestSaveEditService(mock *mockCharacterRepo) *SaveEditService {
+	logger, _ := zap.NewDevelopment()
+	return NewSaveEditService(mock, cfg.ZZ, logger)
+}
+
+func TestSaveEditService_Inspect(t *testing.T) {
+	mock := newSaveHistoryMock(t)
+	svc := newTestSaveEditService(mock)
+
+	f, err := svc.Inspect(1)
+	if err != nil {
+		t.Fatalf("Inspect: %v", err)
+	}
+	if f.Name != "Hunter" || *f.Zenny != 1000 {
+		t.Errorf("fields = %+v", f)
+	}
+}
+
+func TestSaveEditService_Preview(t *testing.T) {
+	mock := newSaveHistoryMock(t)
+	svc := newTestSaveEditService(mock)
+
+	f, err := svc.Preview(1, []SaveEdit{{"zenny", "5"}})
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	if *f.Zenny != 5 {
+		t.Errorf("zenny = %d, want 5", *f.Zenny)
+	}
+	if mock.replaceParams != nil {
+		t.Error("Preview wrote the save")
+	}
+}
+
+func TestSaveEditService_Edit(t *testing.T) {
+	mock := newSaveHistoryMock(t)
+	prev := mock.loadSaveDataData
+	svc := newTestSaveEditService(mock)
+
+	f, err := svc.Edit(1, []SaveEdit{{"zenny", "5000"}, {"hr", "100"}})
+	if err != nil {
+		t.Fatalf("Edit: %v", err)
+	}
+	if *f.Zenny != 5000 || *f.HR != 100 {
+		t.Errorf("fields = %+v", f)
+	}
+	p := mock.replaceParams
+	if p == nil {
+		t.Fatal("ReplaceSaveDataAtomic not called")
+	}
+	if string(mock.replacePrev) != string(prev) || string(p.BackupData) != string(prev) {
+		t.Error("previous save not passed as prev and backup")
+	}
+	// Slots 2 and 0 are in use, so slot 1 is free.
+	if p.BackupSlot != 1 {
+		t.Errorf("backup slot = %d, want 1", p.BackupSlot)
+	}
+	if p.HR != 100 || len(p.Hash) != 32 {
+		t.Errorf("params HR %d hash %d bytes", p.HR, len(p.Hash))
+	}
+	saved, err := DecodeSave(1, cfg.ZZ, p.CompSave)
+	if err != nil || saved.Zenny != 5000 {
+		t.Errorf("written save zenny = %v, %v", saved, err)
+	}
+}
+
+func TestSaveEditService_Edit_InvalidEditWritesNothing(t *testing.T) {
+	mock := newSaveHistoryMock(t)
+	svc := newTestSaveEditService(mock)
+
+	if _, err := svc.Edit(1, []SaveEdit{{"zenny", "5"}, {"hr", "5000"}}); err == nil {
+		t.Fatal("expected an error for hr=5000")
+	}
+	if _, err := svc.Edit(1, nil); err == nil {
+		t.Fatal("expected an error for no edits")
+	}
+	if mock.replaceParams != nil {
+		t.Error("save written despite an invalid edit")
+	}
+}
+
+func TestSaveEditService_Edit_Online(t *testing.T) {
+	mock := newSaveHistoryMock(t)
+	mock.replaceErr = ErrCharacterOnline
+	svc := newTestSaveEditService(mock)
+
+	if _, err := svc.Edit(1, []SaveEdit{{"zenny", "5"}}); !errors.Is(err, ErrCharacterOnline) {
+		t.Errorf("err = %v, want ErrCharacterOnline", err)
+	}
+}
+
+func TestNextEditBackupSlot(t *testing.T) {
+	full := []SavedataBackup{
+		{Slot: 1, SavedAt: time.Unix(3000, 0)},
+		{Slot: 0, SavedAt: time.Unix(2000, 0)},
+		{Slot: 2, SavedAt: time.Unix(1000, 0)},
+	}
+	if got := nextEditBackupSlot(full); got != 2 {
+		t.Errorf("all slots used: got %d, want least recent 2", got)
+	}
+	if got := nextEditBackupSlot(nil); got != 0 {
+		t.Errorf("no backups: got %d, want 0", got)
+	}
+}
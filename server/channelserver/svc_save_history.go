@@ -0,0 +1,254 @@
+package channelserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+// CurrentSaveSlot selects the character's live savedata instead of a backup
+// slot in DiffBackups.
+const CurrentSaveSlot = -1
+
+// ErrBackupNotFound is returned when the requested backup slot is empty.
+var ErrBackupNotFound = errors.New("backup slot not found")
+
+// SaveSummary holds the headline fields parsed from a savedata blob.
+type SaveSummary struct {
+	Name       string `json:"name"`
+	IsFemale   bool   `json:"isFemale"`
+	HR         uint16 `json:"hr"`
+	GR         uint16 `json:"gr"`
+	RP         uint16 `json:"rp"`
+	Playtime   uint32 `json:"playtime"`
+	WeaponType uint8  `json:"weaponType"`
+	WeaponID   uint16 `json:"weaponId"`
+	Zenny      uint32 `json:"zenny"`
+	GZenny     uint32 `json:"gzenny"`
+	CP         uint32 `json:"cp"`
+}
+
+// SaveBackupInfo describes one savedata snapshot. Slot is CurrentSaveSlot for
+// the live save, whose SavedAt is zero. A snapshot that cannot be parsed has a
+// nil Summary and the reason in Error.
+type SaveBackupInfo struct {
+	Slot    int          `json:"slot"`
+	SavedAt time.Time    `json:"savedAt"`
+	Size    int          `json:"size"`
+	Summary *SaveSummary `json:"summary,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// SaveHistory is a character's live save followed by its backups, most
+// recent first.
+type SaveHistory struct {
+	Current SaveBackupInfo   `json:"current"`
+	Backups []SaveBackupInfo `json:"backups"`
+}
+
+// SaveFieldDiff is one changed field between two snapshots. Scalar fields
+// carry Old and New; byte sections (and "savedata", the whole decompressed
+// blob) carry the number of differing bytes instead.
+type SaveFieldDiff struct {
+	Field        string      `json:"field"`
+	Old          interface{} `json:"old,omitempty"`
+	New          interface{} `json:"new,omitempty"`
+	ChangedBytes int         `json:"changedBytes,omitempty"`
+}
+
+// SaveHistoryService lists, compares and restores the rotating savedata
+// backups written by the save handler.
+type SaveHistoryService struct {
+	charRepo CharacterRepo
+	mode     cfg.Mode
+	logger   *zap.Logger
+}
+
+// NewSaveHistoryService creates a SaveHistoryService parsing saves with the
+// layout of the given client mode.
+func NewSaveHistoryService(cr CharacterRepo, mode cfg.Mode, log *zap.Logger) *SaveHistoryService {
+	return &SaveHistoryService{
+		charRepo: cr,
+		mode:     mode,
+		logger:   log,
+	}
+}
+
+// parseSnapshot decodes a stored blob with the service's client mode.
+func (svc *SaveHistoryService) parseSnapshot(charID uint32, comp []byte) (*CharacterSaveData, error) {
+	return DecodeSave(charID, svc.mode, comp)
+}
+
+func summarize(save *CharacterSaveData) *SaveSummary {
+	return &SaveSummary{
+		Name:       save.Name,
+		IsFemale:   save.Gender,
+		HR:         save.HR,
+		GR:         save.GR,
+		RP:         save.RP,
+		Playtime:   save.Playtime,
+		WeaponType: save.WeaponType,
+		WeaponID:   save.WeaponID,
+		Zenny:      save.Zenny,
+		GZenny:     save.GZenny,
+		CP:         save.CP,
+	}
+}
+
+func (svc *SaveHistoryService) describe(charID uint32, slot int, savedAt time.Time, comp []byte) SaveBackupInfo {
+	info := SaveBackupInfo{Slot: slot, SavedAt: savedAt, Size: len(comp)}
+	if len(comp) == 0 {
+		info.Error = "no savedata"
+		return info
+	}
+	save, err := svc.parseSnapshot(charID, comp)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Summary = summarize(save)
+	return info
+}
+
+// History returns the character's live save and every backup slot, most
+// recent first.
+func (svc *SaveHistoryService) History(charID uint32) (SaveHistory, error) {
+	var history SaveHistory
+	_, current, _, _, _, err := svc.charRepo.LoadSaveDataWithHash(charID)
+	if err != nil {
+		return history, err
+	}
+	history.Current = svc.describe(charID, CurrentSaveSlot, time.Time{}, current)
+
+	backups, err := svc.charRepo.LoadBackupsByRecency(charID)
+	if err != nil {
+		return history, err
+	}
+	history.Backups = make([]SaveBackupInfo, 0, len(backups))
+	for _, b := range backups {
+		history.Backups = append(history.Backups, svc.describe(charID, b.Slot, b.SavedAt, b.Data))
+	}
+	return history, nil
+}
+
+// loadSnapshot returns the raw blob for slot, or the live save for
+// CurrentSaveSlot, along with the backup's timestamp.
+func (svc *SaveHistoryService) loadSnapshot(charID uint32, slot int) ([]byte, time.Time, error) {
+	if slot == CurrentSaveSlot {
+		_, data, _, _, _, err := svc.charRepo.LoadSaveDataWithHash(charID)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if len(data) == 0 {
+			return nil, time.Time{}, ErrBackupNotFound
+		}
+		return data, time.Time{}, nil
+	}
+	backups, err := svc.charRepo.LoadBackupsByRecency(charID)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	for _, b := range backups {
+		if b.Slot == slot {
+			return b.Data, b.SavedAt, nil
+		}
+	}
+	return nil, time.Time{}, ErrBackupNotFound
+}
+
+// DiffBackups compares two snapshots field by field. Either slot may be
+// CurrentSaveSlot. Only changed fields are returned.
+func (svc *SaveHistoryService) DiffBackups(charID uint32, from, to int) ([]SaveFieldDiff, error) {
+	var saves [2]*CharacterSaveData
+	for i, slot := range []int{from, to} {
+		data, _, err := svc.loadSnapshot(charID, slot)
+		if err != nil {
+			return nil, err
+		}
+		if saves[i], err = svc.parseSnapshot(charID, data); err != nil {
+			return nil, fmt.Errorf("slot %d: %w", slot, err)
+		}
+	}
+	return diffSaves(saves[0], saves[1]), nil
+}
+
+func diffSaves(a, b *CharacterSaveData) []SaveFieldDiff {
+	diffs := make([]SaveFieldDiff, 0)
+	scalar := func(field string, before, after interface{}) {
+		if before != after {
+			diffs = append(diffs, SaveFieldDiff{Field: field, Old: before, New: after})
+		}
+	}
+	section := func(field string, before, after []byte) {
+		if n := changedBytes(before, after); n > 0 {
+			diffs = append(diffs, SaveFieldDiff{Field: field, ChangedBytes: n})
+		}
+	}
+	scalar("name", a.Name, b.Name)
+	scalar("isFemale", a.Gender, b.Gender)
+	scalar("hr", a.HR, b.HR)
+	scalar("gr", a.GR, b.GR)
+	scalar("rp", a.RP, b.RP)
+	scalar("playtime", a.Playtime, b.Playtime)
+	scalar("weaponType", a.WeaponType, b.WeaponType)
+	scalar("weaponId", a.WeaponID, b.WeaponID)
+	scalar("zenny", a.Zenny, b.Zenny)
+	scalar("gzenny", a.GZenny, b.GZenny)
+	scalar("cp", a.CP, b.CP)
+	section("houseTier", a.HouseTier, b.HouseTier)
+	section("houseData", a.HouseData, b.HouseData)
+	section("bookshelfData", a.BookshelfData, b.BookshelfData)
+	section("galleryData", a.GalleryData, b.GalleryData)
+	section("toreData", a.ToreData, b.ToreData)
+	section("gardenData", a.GardenData, b.GardenData)
+	section("kqf", a.KQF, b.KQF)
+	section("savedata", a.decompSave, b.decompSave)
+	return diffs
+}
+
+// changedBytes counts differing positions, treating bytes past the end of
+// the shorter slice as changed.
+func changedBytes(a, b []byte) int {
+	if bytes.Equal(a, b) {
+		return 0
+	}
+	n := max(len(a), len(b)) - min(len(a), len(b))
+	for i := 0; i < min(len(a), len(b)); i++ {
+		if a[i] != b[i] {
+			n++
+		}
+	}
+	return n
+}
+
+// RestoreBackup makes the backup in slot the character's live save. The save
+// it replaces is swapped into the same slot, so restoring the slot a second
+// time undoes the restore. Backups that do not parse are refused, and the
+// repository refuses with ErrCharacterOnline while the character is logged
+// in. Returns a description of the save now live.
+func (svc *SaveHistoryService) RestoreBackup(charID uint32, slot int) (SaveBackupInfo, error) {
+	if slot == CurrentSaveSlot {
+		return SaveBackupInfo{}, ErrBackupNotFound
+	}
+	data, savedAt, err := svc.loadSnapshot(charID, slot)
+	if err != nil {
+		return SaveBackupInfo{}, err
+	}
+	save, err := svc.parseSnapshot(charID, data)
+	if err != nil {
+		return SaveBackupInfo{}, fmt.Errorf("slot %d: %w", slot, err)
+	}
+
+	params := save.atomicParams()
+	if err := svc.charRepo.RestoreBackupAtomic(slot, savedAt, params); err != nil {
+		return SaveBackupInfo{}, err
+	}
+	svc.logger.Info("Savedata restored from backup",
+		zap.Uint32("charID", charID), zap.Int("slot", slot), zap.Time("backupSavedAt", savedAt))
+	return SaveBackupInfo{Slot: CurrentSaveSlot, Size: len(data), Summary: summarize(save)}, nil
+}
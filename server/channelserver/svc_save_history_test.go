@@ -0,0 +1,183 @@
+package channelserver
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	cfg "erupe-ce/config"
+	"erupe-ce/server/channelserver/compression/nullcomp"
+
+	"go.uber.org/zap"
+)
+
+// buildTestSave returns a compressed ZZ-layout save with the given HR and
+// zenny written at their pointers.
+func buildTestSave(t *testing.T, name string, hr uint16, zenny uint32) []byte {
+	t.Helper()
+	ptrs := getPointers(cfg.ZZ)
+	raw := make([]byte, 150000)
+	copy(raw[saveFieldNameOffset:], append([]byte(name), 0x00))
+	binary.LittleEndian.PutUint16(raw[ptrs[pHR]:], hr)
+	binary.LittleEndian.PutUint32(raw[ptrs[pZenny]:], zenny)
+	comp, err := nullcomp.Compress(raw)
+	if err != nil {
+		t.Fatalf("compress: %v", err)
+	}
+	return comp
+}
+
+func newTestSaveHistoryService(mock *mockCharacterRepo) *SaveHistoryService {
+	logger, _ := zap.NewDevelopment()
+	return NewSaveHistoryService(mock, cfg.ZZ, logger)
+}
+
+func newSaveHistoryMock(t *testing.T) *mockCharacterRepo {
+	t.Helper()
+	mock := newMockCharacterRepo()
+	mock.loadSaveDataData = buildTestSave(t, "Hunter", 7, 1000)
+	mock.backups = []SavedataBackup{
+		{Slot: 2, Data: buildTestSave(t, "Hunter", 6, 500), SavedAt: time.Unix(2000, 0)},
+		{Slot: 0, Data: []byte("not a save"), SavedAt: time.Unix(1000, 0)},
+	}
+	return mock
+}
+
+func TestSaveHistoryService_History(t *testing.T) {
+	svc := newTestSaveHistoryService(newSaveHistoryMock(t))
+
+	history, err := svc.History(1)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if history.Current.Slot != CurrentSaveSlot || history.Current.Summary == nil {
+		t.Fatalf("current = %+v", history.Current)
+	}
+	if got := history.Current.Summary; got.Name != "Hunter" || got.HR != 7 || got.Zenny != 1000 {
+		t.Errorf("current summary = %+v", got)
+	}
+	if len(history.Backups) != 2 {
+		t.Fatalf("got %d backups, want 2", len(history.Backups))
+	}
+	if b := history.Backups[0]; b.Slot != 2 || b.Summary == nil || b.Summary.HR != 6 {
+		t.Errorf("backup 0 = %+v", b)
+	}
+	if b := history.Backups[1]; b.Summary != nil || b.Error == "" {
+		t.Errorf("corrupt backup should carry an error, got %+v", b)
+	}
+}
+
+func TestSaveHistoryService_DiffBackups(t *testing.T) {
+	svc := newTestSaveHistoryService(newSaveHistoryMock(t))
+
+	diffs, err := svc.DiffBackups(1, 2, CurrentSaveSlot)
+	if err != nil {
+		t.Fatalf("DiffBackups: %v", err)
+	}
+	byField := make(map[string]SaveFieldDiff)
+	for _, d := range diffs {
+		byField[d.Field] = d
+	}
+	if d, ok := byField["hr"]; !ok || d.Old != uint16(6) || d.New != uint16(7) {
+		t.Errorf("hr diff = %+v", d)
+	}
+	if d, ok := byField["zenny"]; !ok || d.Old != uint32(500) || d.New != uint32(1000) {
+		t.Errorf("zenny diff = %+v", d)
+	}
+	if d, ok := byField["savedata"]; !ok || d.ChangedBytes == 0 {
+		t.Errorf("savedata diff = %+v", d)
+	}
+	if _, ok := byField["name"]; ok {
+		t.Error("unchanged name reported as a diff")
+	}
+}
+
+func TestSaveHistoryService_DiffBackups_Errors(t *testing.T) {
+	svc := newTestSaveHistoryService(newSaveHistoryMock(t))
+
+	if _, err := svc.DiffBackups(1, 1, CurrentSaveSlot); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("empty slot: err = %v, want ErrBackupNotFound", err)
+	}
+	if _, err := svc.DiffBackups(1, 0, CurrentSaveSlot); !errors.Is(err, ErrSaveUnreadable) {
+		t.Errorf("corrupt slot: err = %v, want ErrSaveUnreadable", err)
+	}
+}
+
+func TestSaveHistoryService_DiffBackups_ShortSave(t *testing.T) {
+	mock := newSaveHistoryMock(t)
+	short, _ := nullcomp.Compress(make([]byte, 1000))
+	mock.backups[0].Data = short
+	svc := newTestSaveHistoryService(mock)
+
+	if _, err := svc.DiffBackups(1, 2, CurrentSaveSlot); !errors.Is(err, ErrSaveUnreadable) {
+		t.Errorf("err = %v, want ErrSaveUnreadable", err)
+	}
+}
+
+func TestSaveHistoryService_RestoreBackup(t *testing.T) {
+	mock := newSaveHistoryMock(t)
+	svc := newTestSaveHistoryService(mock)
+
+	info, err := svc.RestoreBackup(1, 2)
+	if err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if info.Summary == nil || info.Summary.HR != 6 {
+		t.Errorf("restored summary = %+v", info.Summary)
+	}
+	if mock.restoreSlot != 2 || mock.restoreParams == nil {
+		t.Fatalf("RestoreBackupAtomic not called with slot 2")
+	}
+	p := mock.restoreParams
+	if p.CharID != 1 || p.HR != 6 || len(p.Hash) != 32 || len(p.HouseData) != saveFieldHouseData {
+		t.Errorf("params = CharID %d HR %d hash %d bytes house %d bytes",
+			p.CharID, p.HR, len(p.Hash), len(p.HouseData))
+	}
+}
+
+func TestSaveHistoryService_RestoreBackup_Refusals(t *testing.T) {
+	t.Run("corrupt_backup", func(t *testing.T) {
+		mock := newSaveHistoryMock(t)
+		svc := newTestSaveHistoryService(mock)
+		if _, err := svc.RestoreBackup(1, 0); !errors.Is(err, ErrSaveUnreadable) {
+			t.Errorf("err = %v, want ErrSaveUnreadable", err)
+		}
+		if mock.restoreParams != nil {
+			t.Error("corrupt backup was restored")
+		}
+	})
+
+	t.Run("online", func(t *testing.T) {
+		mock := newSaveHistoryMock(t)
+		mock.restoreErr = ErrCharacterOnline
+		svc := newTestSaveHistoryService(mock)
+		if _, err := svc.RestoreBackup(1, 2); !errors.Is(err, ErrCharacterOnline) {
+			t.Errorf("err = %v, want ErrCharacterOnline", err)
+		}
+	})
+
+	t.Run("current_slot", func(t *testing.T) {
+		svc := newTestSaveHistoryService(newSaveHistoryMock(t))
+		if _, err := svc.RestoreBackup(1, CurrentSaveSlot); !errors.Is(err, ErrBackupNotFound) {
+			t.Errorf("err = %v, want ErrBackupNotFound", err)
+		}
+	})
+}
+
+func TestChangedBytes(t *testing.T) {
+	tests := []struct {
+		a, b []byte
+		want int
+	}{
+		{[]byte{1, 2, 3}, []byte{1, 2, 3}, 0},
+		{[]byte{1, 2, 3}, []byte{1, 0, 3}, 1},
+		{[]byte{1, 2}, []byte{1, 2, 3, 4}, 2},
+		{nil, []byte{1}, 1},
+	}
+	for _, tt := range tests {
+		if got := changedBytes(tt.a, tt.b); got != tt.want {
+			t.Errorf("changedBytes(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
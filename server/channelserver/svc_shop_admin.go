@@ -0,0 +1,434 @@
+package channelserver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+const (
+	shopTypeFirstListed    = 3  // Shop types 1 and 2 are gachas, managed through GachaAdminService
+	shopTypeItemShop       = 10 // The item shop, whose tabs are capped at maxItemShopRows
+	shopReportDefaultLimit = 50
+	shopReportMaxLimit     = 500
+	fpointMaxItemsZ2       = math.MaxUint8 // Clients up to Z2 are sent a uint8 exchange count
+)
+
+var (
+	// ErrShopItemNotFound is returned when a shop item does not exist.
+	ErrShopItemNotFound = errors.New("shop item not found")
+	// ErrShopPriceChangeNotFound is returned when a price change does not
+	// exist on a shop item.
+	ErrShopPriceChangeNotFound = errors.New("shop price change not found")
+	// ErrFpointItemNotFound is returned when a Frontier Point exchange item
+	// does not exist.
+	ErrFpointItemNotFound = errors.New("frontier point item not found")
+	// ErrInvalidShopItem is returned when a shop item, Frontier Point item or
+	// price change fails validation.
+	ErrInvalidShopItem = errors.New("invalid shop item")
+	// ErrInvalidShopReport is returned for a malformed purchase report query.
+	ErrInvalidShopReport = errors.New("invalid shop report query")
+)
+
+// ShopAdminService manages the shop and Frontier Point exchange catalogues
+// for the admin API and the liveops tool, and reports on purchases. Items are
+// checked against what writeShopItems sends to the configured client mode.
+type ShopAdminService struct {
+	shopRepo ShopRepo
+	mode     cfg.Mode
+	logger   *zap.Logger
+}
+
+// NewShopAdminService creates a new ShopAdminService.
+func NewShopAdminService(sr ShopRepo, mode cfg.Mode, log *zap.Logger) *ShopAdminService {
+	return &ShopAdminService{shopRepo: sr, mode: mode, logger: log}
+}
+
+// List returns the shop items matching f.
+func (svc *ShopAdminService) List(f ShopItemFilter) ([]ShopCatalogueItem, error) {
+	items, err := svc.shopRepo.ListCatalogue(f)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []ShopCatalogueItem{}
+	}
+	return items, nil
+}
+
+// Get returns a shop item with its scheduled price changes.
+func (svc *ShopAdminService) Get(id uint32) (*ShopCatalogueItem, error) {
+	item, err := svc.shopRepo.GetCatalogueItem(id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("%w: %d", ErrShopItemNotFound, id)
+	}
+	item.PriceChanges, err = svc.shopRepo.ListPriceChanges(id)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// Create validates and puts a new item on sale.
+func (svc *ShopAdminService) Create(item ShopCatalogueItem) (*ShopCatalogueItem, error) {
+	item.ID = 0
+	if err := validateShopItem(item, svc.mode); err != nil {
+		return nil, err
+	}
+	if err := svc.checkItemShopRoom(item); err != nil {
+		return nil, err
+	}
+	id, err := svc.shopRepo.CreateCatalogueItem(item)
+	if err != nil {
+		return nil, err
+	}
+	svc.logger.Info("Shop item created", zap.Uint32("shopItemID", id),
+		zap.Uint8("shopType", item.ShopType), zap.Uint32("shopID", item.ShopID), zap.Uint16("itemID", item.ItemID))
+	return svc.Get(id)
+}
+
+// Update validates and replaces a shop item's listing. A cost that differs
+// from the current price replaces it straight away, together with any
+// scheduled change that has already taken effect; pending changes still
+// apply when due. Purchase counts are kept.
+func (svc *ShopAdminService) Update(id uint32, item ShopCatalogueItem) (*ShopCatalogueItem, error) {
+	current, err := svc.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	item.ID = id
+	if err := validateShopItem(item, svc.mode); err != nil {
+		return nil, err
+	}
+	if current.RetiredAt == nil {
+		if err := svc.checkItemShopRoom(item); err != nil {
+			return nil, err
+		}
+	}
+	ok, err := svc.shopRepo.UpdateCatalogueItem(item, item.Cost != current.Cost)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrShopItemNotFound, id)
+	}
+	return svc.Get(id)
+}
+
+// Retire takes a shop item off sale. The row and its purchase counts are
+// kept, so restoring it brings back every character's progress towards its
+// cap.
+func (svc *ShopAdminService) Retire(id uint32) (*ShopCatalogueItem, error) {
+	ok, err := svc.shopRepo.SetCatalogueItemRetired(id, true)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrShopItemNotFound, id)
+	}
+	svc.logger.Info("Shop item retired", zap.Uint32("shopItemID", id))
+	return svc.Get(id)
+}
+
+// Restore puts a retired shop item back on sale.
+func (svc *ShopAdminService) Restore(id uint32) (*ShopCatalogueItem, error) {
+	item, err := svc.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if item.RetiredAt == nil {
+		return item, nil
+	}
+	if err := validateShopItem(*item, svc.mode); err != nil {
+		return nil, err
+	}
+	if err := svc.checkItemShopRoom(*item); err != nil {
+		return nil, err
+	}
+	if _, err := svc.shopRepo.SetCatalogueItemRetired(id, false); err != nil {
+		return nil, err
+	}
+	svc.logger.Info("Shop item restored", zap.Uint32("shopItemID", id))
+	return svc.Get(id)
+}
+
+// SchedulePrice schedules a new cost for a shop item from c.EffectiveAt,
+// which must be after now.
+func (svc *ShopAdminService) SchedulePrice(id uint32, c ShopPriceChange, now time.Time) (*ShopCatalogueItem, error) {
+	item, err := svc.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case item.RetiredAt != nil:
+		return nil, fmt.Errorf("%w: shop item %d is retired", ErrInvalidShopItem, id)
+	case c.Cost > math.MaxInt32:
+		return nil, fmt.Errorf("%w: cost must be at most %d", ErrInvalidShopItem, math.MaxInt32)
+	case !c.EffectiveAt.After(now):
+		return nil, fmt.Errorf("%w: effectiveAt must be in the future", ErrInvalidShopItem)
+	}
+	c.ShopItemID = id
+	changeID, err := svc.shopRepo.CreatePriceChange(c)
+	if err != nil {
+		return nil, err
+	}
+	svc.logger.Info("Shop price change scheduled", zap.Uint32("shopItemID", id),
+		zap.Uint32("changeID", changeID), zap.Uint32("cost", c.Cost), zap.Time("effectiveAt", c.EffectiveAt))
+	return svc.Get(id)
+}
+
+// CancelPriceChange removes a scheduled price change that has not taken
+// effect yet.
+func (svc *ShopAdminService) CancelPriceChange(id, changeID uint32, now time.Time) (*ShopCatalogueItem, error) {
+	item, err := svc.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	var change *ShopPriceChange
+	for i := range item.PriceChanges {
+		if item.PriceChanges[i].ID == changeID {
+			change = &item.PriceChanges[i]
+			break
+		}
+	}
+	if change == nil {
+		return nil, fmt.Errorf("%w: %d", ErrShopPriceChangeNotFound, changeID)
+	}
+	if !change.EffectiveAt.After(now) {
+		return nil, fmt.Errorf("%w: price change %d has already taken effect", ErrInvalidShopItem, changeID)
+	}
+	ok, err := svc.shopRepo.DeletePriceChange(id, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrShopPriceChangeNotFound, changeID)
+	}
+	return svc.Get(id)
+}
+
+// checkItemShopRoom rejects putting an item on sale in an item shop tab that
+// already lists as many items as the handler sends, since the extra rows
+// would be silently cut off.
+func (svc *ShopAdminService) checkItemShopRoom(item ShopCatalogueItem) error {
+	if item.ShopType != shopTypeItemShop {
+		return nil
+	}
+	shopID := item.ShopID
+	listed, err := svc.shopRepo.ListCatalogue(ShopItemFilter{ShopType: item.ShopType, ShopID: &shopID})
+	if err != nil {
+		return err
+	}
+	others := 0
+	for _, l := range listed {
+		if l.ID != item.ID {
+			others++
+		}
+	}
+	if others >= maxItemShopRows {
+		return fmt.Errorf("%w: item shop %d already lists %d items, the most the client is sent",
+			ErrInvalidShopItem, item.ShopID, others)
+	}
+	return nil
+}
+
+// validateShopItem checks a shop item against the fields and widths
+// writeShopItems sends to clients of mode. Fields a mode is not sent are
+// rejected rather than stored, since they would silently do nothing.
+func validateShopItem(item ShopCatalogueItem, mode cfg.Mode) error {
+	switch {
+	case item.ShopType < shopTypeFirstListed || item.ShopType > shopTypeItemShop:
+		return fmt.Errorf("%w: shopType must be %d-%d; types 1 and 2 are gachas", ErrInvalidShopItem,
+			shopTypeFirstListed, shopTypeItemShop)
+	case item.ItemID == 0:
+		return fmt.Errorf("%w: itemId is required", ErrInvalidShopItem)
+	case item.Quantity == 0:
+		return fmt.Errorf("%w: quantity must be at least 1", ErrInvalidShopItem)
+	case item.Cost > math.MaxInt32:
+		return fmt.Errorf("%w: cost must be at most %d", ErrInvalidShopItem, math.MaxInt32)
+	case item.StoreLevel > math.MaxUint8:
+		return fmt.Errorf("%w: storeLevel must be at most %d", ErrInvalidShopItem, math.MaxUint8)
+	case mode < cfg.Z2 && item.MinGR > 0:
+		return fmt.Errorf("%w: minGr is not sent to %s clients", ErrInvalidShopItem, mode.Name())
+	case mode < cfg.Z2 && item.MaxQuantity > 0:
+		return fmt.Errorf("%w: maxQuantity is not sent to %s clients, so purchases would not be capped",
+			ErrInvalidShopItem, mode.Name())
+	case mode < cfg.Z1 && (item.RoadFloors > 0 || item.RoadFatalis > 0):
+		return fmt.Errorf("%w: roadFloors and roadFatalis are not sent to %s clients", ErrInvalidShopItem, mode.Name())
+	case mode == cfg.Z1 && (item.RoadFloors > math.MaxUint8 || item.RoadFatalis > math.MaxUint8):
+		return fmt.Errorf("%w: roadFloors and roadFatalis must be at most %d for Z1 clients",
+			ErrInvalidShopItem, math.MaxUint8)
+	}
+	return nil
+}
+
+// ListFpoint returns the Frontier Point exchange items.
+func (svc *ShopAdminService) ListFpoint(includeRetired bool) ([]FPointExchange, error) {
+	items, err := svc.shopRepo.ListFpointCatalogue(includeRetired)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []FPointExchange{}
+	}
+	return items, nil
+}
+
+// GetFpoint returns a Frontier Point exchange item.
+func (svc *ShopAdminService) GetFpoint(id uint32) (*FPointExchange, error) {
+	item, err := svc.shopRepo.GetFpointCatalogueItem(id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("%w: %d", ErrFpointItemNotFound, id)
+	}
+	return item, nil
+}
+
+// CreateFpoint validates and adds a Frontier Point exchange item.
+func (svc *ShopAdminService) CreateFpoint(item FPointExchange) (*FPointExchange, error) {
+	item.ID = 0
+	if err := validateFpointItem(item); err != nil {
+		return nil, err
+	}
+	if err := svc.checkFpointRoom(); err != nil {
+		return nil, err
+	}
+	id, err := svc.shopRepo.CreateFpointItem(item)
+	if err != nil {
+		return nil, err
+	}
+	svc.logger.Info("Frontier Point item created", zap.Uint32("fpointItemID", id), zap.Uint16("itemID", item.ItemID))
+	return svc.GetFpoint(id)
+}
+
+// UpdateFpoint validates and replaces a Frontier Point exchange item.
+func (svc *ShopAdminService) UpdateFpoint(id uint32, item FPointExchange) (*FPointExchange, error) {
+	item.ID = id
+	if err := validateFpointItem(item); err != nil {
+		return nil, err
+	}
+	ok, err := svc.shopRepo.UpdateFpointItem(item)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrFpointItemNotFound, id)
+	}
+	return svc.GetFpoint(id)
+}
+
+// RetireFpoint removes a Frontier Point exchange item from the exchange and
+// refuses trades against it.
+func (svc *ShopAdminService) RetireFpoint(id uint32) (*FPointExchange, error) {
+	ok, err := svc.shopRepo.SetFpointItemRetired(id, true)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrFpointItemNotFound, id)
+	}
+	svc.logger.Info("Frontier Point item retired", zap.Uint32("fpointItemID", id))
+	return svc.GetFpoint(id)
+}
+
+// RestoreFpoint puts a retired Frontier Point exchange item back.
+func (svc *ShopAdminService) RestoreFpoint(id uint32) (*FPointExchange, error) {
+	item, err := svc.GetFpoint(id)
+	if err != nil {
+		return nil, err
+	}
+	if item.RetiredAt == nil {
+		return item, nil
+	}
+	if err := svc.checkFpointRoom(); err != nil {
+		return nil, err
+	}
+	if _, err := svc.shopRepo.SetFpointItemRetired(id, false); err != nil {
+		return nil, err
+	}
+	svc.logger.Info("Frontier Point item restored", zap.Uint32("fpointItemID", id))
+	return svc.GetFpoint(id)
+}
+
+// checkFpointRoom rejects growing the exchange past the count clients up to
+// Z2 can be sent.
+func (svc *ShopAdminService) checkFpointRoom() error {
+	if svc.mode > cfg.Z2 {
+		return nil
+	}
+	listed, err := svc.shopRepo.ListFpointCatalogue(false)
+	if err != nil {
+		return err
+	}
+	if len(listed) >= fpointMaxItemsZ2 {
+		return fmt.Errorf("%w: the exchange already lists %d items, the most %s clients are sent",
+			ErrInvalidShopItem, len(listed), svc.mode.Name())
+	}
+	return nil
+}
+
+// validateFpointItem checks a Frontier Point exchange item. Trading items in
+// divides by the quantity, so it must not be zero.
+func validateFpointItem(item FPointExchange) error {
+	switch {
+	case item.ItemID == 0:
+		return fmt.Errorf("%w: itemId is required", ErrInvalidShopItem)
+	case item.Quantity == 0:
+		return fmt.Errorf("%w: quantity must be at least 1", ErrInvalidShopItem)
+	}
+	return nil
+}
+
+// TopItems returns the shop items with the most recorded purchases. A zero
+// limit returns the default number of items; larger limits are capped.
+func (svc *ShopAdminService) TopItems(f ShopReportFilter) ([]ShopItemSales, error) {
+	if err := normalizeShopReportFilter(&f); err != nil {
+		return nil, err
+	}
+	sales, err := svc.shopRepo.TopShopItems(f)
+	if err != nil {
+		return nil, err
+	}
+	if sales == nil {
+		sales = []ShopItemSales{}
+	}
+	return sales, nil
+}
+
+// CapHits returns the characters that have bought a capped item as many
+// times as it allows.
+func (svc *ShopAdminService) CapHits(f ShopReportFilter) ([]ShopCapHit, error) {
+	if err := normalizeShopReportFilter(&f); err != nil {
+		return nil, err
+	}
+	hits, err := svc.shopRepo.ShopCapHits(f)
+	if err != nil {
+		return nil, err
+	}
+	if hits == nil {
+		hits = []ShopCapHit{}
+	}
+	return hits, nil
+}
+
+// normalizeShopReportFilter applies the default and maximum report limits.
+func normalizeShopReportFilter(f *ShopReportFilter) error {
+	if f.Limit < 0 {
+		return fmt.Errorf("%w: limit must not be negative", ErrInvalidShopReport)
+	}
+	if f.Limit == 0 {
+		f.Limit = shopReportDefaultLimit
+	}
+	f.Limit = min(f.Limit, shopReportMaxLimit)
+	return nil
+}
@@ -0,0 +1,252 @@
+package channelserver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	cfg "erupe-ce/config"
+
+	"go.uber.org/zap"
+)
+
+func newTestShopAdmin(mode cfg.Mode) (*ShopAdminService, *mockShopRepo) {
+	repo := &mockShopRepo{}
+	return NewShopAdminService(repo, mode, zap.NewNop()), repo
+}
+
+func TestShopAdminService_CRUD(t *testing.T) {
+	svc, repo := newTestShopAdmin(cfg.ZZ)
+
+	item, err := svc.Create(ShopCatalogueItem{ShopType: 10, ShopID: 2, ItemID: 500, Cost: 100, Quantity: 1, MaxQuantity: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID == 0 || item.Cost != 100 {
+		t.Fatalf("created = %+v", item)
+	}
+
+	edit := *item
+	edit.MinHR = 30
+	if _, err := svc.Update(item.ID, edit); err != nil {
+		t.Fatal(err)
+	}
+	if repo.repriced {
+		t.Error("unchanged cost should not reprice the item")
+	}
+	edit.Cost = 80
+	updated, err := svc.Update(item.ID, edit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !repo.repriced || updated.Cost != 80 || updated.MinHR != 30 {
+		t.Errorf("updated = %+v, repriced = %v", updated, repo.repriced)
+	}
+	if _, err := svc.Update(99, edit); !errors.Is(err, ErrShopItemNotFound) {
+		t.Errorf("update missing item: err = %v, want ErrShopItemNotFound", err)
+	}
+
+	retired, err := svc.Retire(item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retired.RetiredAt == nil {
+		t.Error("retired item has no retiredAt")
+	}
+	if listed, _ := svc.List(ShopItemFilter{ShopType: 10}); len(listed) != 0 {
+		t.Errorf("retired item is still listed: %+v", listed)
+	}
+	if listed, _ := svc.List(ShopItemFilter{ShopType: 10, IncludeRetired: true}); len(listed) != 1 {
+		t.Errorf("includeRetired listed %d items, want 1", len(listed))
+	}
+	restored, err := svc.Restore(item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.RetiredAt != nil {
+		t.Error("restored item is still retired")
+	}
+	if _, err := svc.Retire(99); !errors.Is(err, ErrShopItemNotFound) {
+		t.Errorf("retire missing item: err = %v, want ErrShopItemNotFound", err)
+	}
+}
+
+func TestShopAdminService_ValidatesForClientMode(t *testing.T) {
+	valid := ShopCatalogueItem{ShopType: 5, ShopID: 1, ItemID: 500, Cost: 10, Quantity: 1}
+	tests := []struct {
+		name string
+		mode cfg.Mode
+		edit func(*ShopCatalogueItem)
+		want string // Substring of the error, empty if valid
+	}{
+		{"valid ZZ", cfg.ZZ, func(i *ShopCatalogueItem) { i.MinGR, i.MaxQuantity, i.RoadFloors = 1, 5, 300 }, ""},
+		{"gacha shop type", cfg.ZZ, func(i *ShopCatalogueItem) { i.ShopType = 2 }, "shopType"},
+		{"no item", cfg.ZZ, func(i *ShopCatalogueItem) { i.ItemID = 0 }, "itemId"},
+		{"no quantity", cfg.ZZ, func(i *ShopCatalogueItem) { i.Quantity = 0 }, "quantity"},
+		{"cost overflows column", cfg.ZZ, func(i *ShopCatalogueItem) { i.Cost = 1 << 31 }, "cost"},
+		{"store level is a byte", cfg.ZZ, func(i *ShopCatalogueItem) { i.StoreLevel = 256 }, "storeLevel"},
+		{"min GR before Z2", cfg.Z1, func(i *ShopCatalogueItem) { i.MinGR = 1 }, "minGr"},
+		{"cap before Z2", cfg.G10, func(i *ShopCatalogueItem) { i.MaxQuantity = 5 }, "maxQuantity"},
+		{"road fields before Z1", cfg.G10, func(i *ShopCatalogueItem) { i.RoadFloors = 1 }, "road"},
+		{"road fields are bytes on Z1", cfg.Z1, func(i *ShopCatalogueItem) { i.RoadFatalis = 256 }, "road"},
+		{"road fields fit on Z1", cfg.Z1, func(i *ShopCatalogueItem) { i.RoadFloors = 255 }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestShopAdmin(tt.mode)
+			item := valid
+			tt.edit(&item)
+			_, err := svc.Create(item)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidShopItem) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want ErrInvalidShopItem mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestShopAdminService_ItemShopRowCap(t *testing.T) {
+	svc, repo := newTestShopAdmin(cfg.ZZ)
+	for i := 0; i < maxItemShopRows; i++ {
+		repo.catalogue = append(repo.catalogue, ShopCatalogueItem{
+			ID: uint32(i + 1), ShopType: 10, ShopID: 3, ItemID: 1, Quantity: 1,
+		})
+	}
+
+	_, err := svc.Create(ShopCatalogueItem{ShopType: 10, ShopID: 3, ItemID: 2, Quantity: 1})
+	if !errors.Is(err, ErrInvalidShopItem) {
+		t.Errorf("full tab: err = %v, want ErrInvalidShopItem", err)
+	}
+	if _, err := svc.Create(ShopCatalogueItem{ShopType: 10, ShopID: 4, ItemID: 2, Quantity: 1}); err != nil {
+		t.Errorf("other tab: err = %v", err)
+	}
+
+	// Editing an item already in the full tab is fine.
+	edit := repo.catalogue[0]
+	edit.Cost = 5
+	if _, err := svc.Update(edit.ID, edit); err != nil {
+		t.Errorf("edit in full tab: err = %v", err)
+	}
+	if _, err := svc.Retire(edit.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Create(ShopCatalogueItem{ShopType: 10, ShopID: 3, ItemID: 2, Quantity: 1}); err != nil {
+		t.Fatalf("after retiring: err = %v", err)
+	}
+	if _, err := svc.Restore(edit.ID); !errors.Is(err, ErrInvalidShopItem) {
+		t.Errorf("restore into full tab: err = %v, want ErrInvalidShopItem", err)
+	}
+}
+
+func TestShopAdminService_PriceSchedule(t *testing.T) {
+	svc, _ := newTestShopAdmin(cfg.ZZ)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	item, err := svc.Create(ShopCatalogueItem{ShopType: 4, ShopID: 0, ItemID: 500, Cost: 100, Quantity: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.SchedulePrice(item.ID, ShopPriceChange{Cost: 50, EffectiveAt: now}, now); !errors.Is(err, ErrInvalidShopItem) {
+		t.Errorf("past change: err = %v, want ErrInvalidShopItem", err)
+	}
+	scheduled, err := svc.SchedulePrice(item.ID, ShopPriceChange{Cost: 50, EffectiveAt: now.Add(time.Hour)}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scheduled.PriceChanges) != 1 || scheduled.PriceChanges[0].ShopItemID != item.ID {
+		t.Fatalf("price changes = %+v", scheduled.PriceChanges)
+	}
+	changeID := scheduled.PriceChanges[0].ID
+
+	if _, err := svc.CancelPriceChange(item.ID, changeID, now.Add(2*time.Hour)); !errors.Is(err, ErrInvalidShopItem) {
+		t.Errorf("cancel applied change: err = %v, want ErrInvalidShopItem", err)
+	}
+	if _, err := svc.CancelPriceChange(item.ID, 99, now); !errors.Is(err, ErrShopPriceChangeNotFound) {
+		t.Errorf("cancel missing change: err = %v, want ErrShopPriceChangeNotFound", err)
+	}
+	cancelled, err := svc.CancelPriceChange(item.ID, changeID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cancelled.PriceChanges) != 0 {
+		t.Errorf("price changes after cancel = %+v", cancelled.PriceChanges)
+	}
+
+	if _, err := svc.Retire(item.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SchedulePrice(item.ID, ShopPriceChange{Cost: 50, EffectiveAt: now.Add(time.Hour)}, now); !errors.Is(err, ErrInvalidShopItem) {
+		t.Errorf("retired item: err = %v, want ErrInvalidShopItem", err)
+	}
+}
+
+func TestShopAdminService_Fpoint(t *testing.T) {
+	svc, repo := newTestShopAdmin(cfg.Z2)
+
+	if _, err := svc.CreateFpoint(FPointExchange{ItemType: 7, ItemID: 8895, Quantity: 0, FPoints: 500}); !errors.Is(err, ErrInvalidShopItem) {
+		t.Errorf("zero quantity: err = %v, want ErrInvalidShopItem", err)
+	}
+	item, err := svc.CreateFpoint(FPointExchange{ItemType: 7, ItemID: 8895, Quantity: 1, FPoints: 500, Buyable: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item.FPoints = 400
+	if updated, err := svc.UpdateFpoint(item.ID, *item); err != nil || updated.FPoints != 400 {
+		t.Errorf("update = %+v, %v", updated, err)
+	}
+	if _, err := svc.RetireFpoint(item.ID); err != nil {
+		t.Fatal(err)
+	}
+	if listed, _ := svc.ListFpoint(false); len(listed) != 0 {
+		t.Errorf("retired item is still listed: %+v", listed)
+	}
+	if _, err := svc.UpdateFpoint(99, *item); !errors.Is(err, ErrFpointItemNotFound) {
+		t.Errorf("update missing item: err = %v, want ErrFpointItemNotFound", err)
+	}
+
+	// Clients up to Z2 are sent a byte-wide count.
+	for len(repo.fpointCatalogue) < fpointMaxItemsZ2+1 {
+		repo.fpointCatalogue = append(repo.fpointCatalogue, FPointExchange{ID: uint32(len(repo.fpointCatalogue) + 1)})
+	}
+	if _, err := svc.CreateFpoint(FPointExchange{ItemType: 7, ItemID: 1, Quantity: 1}); !errors.Is(err, ErrInvalidShopItem) {
+		t.Errorf("full exchange: err = %v, want ErrInvalidShopItem", err)
+	}
+	if _, err := svc.RestoreFpoint(item.ID); !errors.Is(err, ErrInvalidShopItem) {
+		t.Errorf("restore into full exchange: err = %v, want ErrInvalidShopItem", err)
+	}
+	zz := NewShopAdminService(repo, cfg.ZZ, zap.NewNop())
+	if _, err := zz.RestoreFpoint(item.ID); err != nil {
+		t.Errorf("ZZ restore: err = %v", err)
+	}
+}
+
+func TestShopAdminService_Reports(t *testing.T) {
+	svc, repo := newTestShopAdmin(cfg.ZZ)
+	repo.sales = []ShopItemSales{{ShopItemID: 1, Bought: 40, Buyers: 8, CappedBuyers: 2}}
+
+	sales, err := svc.TopItems(ShopReportFilter{ShopType: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sales) != 1 || repo.reportFilter.Limit != shopReportDefaultLimit {
+		t.Errorf("sales = %+v, filter = %+v", sales, repo.reportFilter)
+	}
+
+	hits, err := svc.CapHits(ShopReportFilter{CharID: 20, Limit: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hits == nil || repo.reportFilter.Limit != shopReportMaxLimit || repo.reportFilter.CharID != 20 {
+		t.Errorf("hits = %v, filter = %+v", hits, repo.reportFilter)
+	}
+
+	if _, err := svc.TopItems(ShopReportFilter{Limit: -1}); !errors.Is(err, ErrInvalidShopReport) {
+		t.Errorf("negative limit: err = %v, want ErrInvalidShopReport", err)
+	}
+}
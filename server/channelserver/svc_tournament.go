@@ -0,0 +1,409 @@
+package channelserver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Default phase lengths, the retail cadence also used by
+	// TournamentDefaults.sql: 3 days of entry, ~8.9 days of ranking and
+	// 7 days of rewards.
+	tournamentEntryLength   = 259200
+	tournamentRankingLength = 766800
+	tournamentRewardLength  = 604800
+
+	tournamentNameMaxLength = 64
+	tournamentMaxCups       = 255
+
+	// tournamentTickInterval is how often channels look for tournaments to
+	// pay out or roll forward.
+	tournamentTickInterval = time.Minute
+
+	// tournamentDistributionType is the distribution type prizes are sent
+	// as, the same gift box Conquest War rewards use.
+	tournamentDistributionType = 1
+	tournamentPrizeEventName   = "Hunting Tournament Prize"
+)
+
+// Reasons a tournament result is flagged for review.
+const (
+	TournamentFlagOutsideEntry   = "outside_entry_window"
+	TournamentFlagNotRegistered  = "not_registered"
+	TournamentFlagUnknownEvent   = "unknown_event"
+	TournamentFlagSubmissionRate = "submitted_too_soon"
+)
+
+// ErrInvalidTournament is returned when a tournament schedule, cup, sub-event
+// or prize is rejected.
+var ErrInvalidTournament = errors.New("invalid tournament")
+
+// ErrTournamentNotFound is returned when a tournament does not exist.
+var ErrTournamentNotFound = errors.New("tournament not found")
+
+// ErrTournamentResultNotFound is returned when a tournament result does not
+// exist.
+var ErrTournamentResultNotFound = errors.New("tournament result not found")
+
+// TournamentService encapsulates tournament administration: scheduling,
+// result checks and review, rolling recurring tournaments forward and paying
+// out prizes once the reward phase ends.
+type TournamentService struct {
+	tournamentRepo TournamentRepo
+	logger         *zap.Logger
+}
+
+// NewTournamentService creates a new TournamentService.
+func NewTournamentService(tr TournamentRepo, log *zap.Logger) *TournamentService {
+	return &TournamentService{tournamentRepo: tr, logger: log}
+}
+
+// Submit records a tournament run. Runs outside the entry window, by
+// characters who did not register, for events the tournament does not hold,
+// or sooner after the character's previous submission for the event than
+// its minimum submission interval, are recorded as flagged for review. The
+// actual quest clear time is not known, so the interval between submissions
+// is the only rate limit on runs.
+func (svc *TournamentService) Submit(charID, tournamentID, eventID, questSlot, stageHandle uint32, now time.Time) error {
+	t, err := svc.tournamentRepo.GetTournament(tournamentID)
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return fmt.Errorf("%w: %d", ErrTournamentNotFound, tournamentID)
+	}
+	reasons, err := svc.check(t, charID, eventID, now)
+	if err != nil {
+		return err
+	}
+	result := TournamentResult{
+		CharID:       charID,
+		TournamentID: tournamentID,
+		EventID:      eventID,
+		QuestSlot:    questSlot,
+		StageHandle:  stageHandle,
+		SubmittedAt:  now,
+		Status:       TournamentResultOK,
+	}
+	if len(reasons) > 0 {
+		result.Status = TournamentResultFlagged
+		result.FlagReason = strings.Join(reasons, "; ")
+	}
+	return svc.tournamentRepo.SubmitResult(result)
+}
+
+// check returns the reasons a run should be flagged, if any.
+func (svc *TournamentService) check(t *Tournament, charID, eventID uint32, now time.Time) ([]string, error) {
+	var reasons []string
+	if unix := now.Unix(); unix < t.StartTime || unix > t.EntryEnd {
+		reasons = append(reasons, TournamentFlagOutsideEntry)
+	}
+	entry, err := svc.tournamentRepo.GetEntry(charID, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		reasons = append(reasons, TournamentFlagNotRegistered)
+	}
+
+	events, err := svc.tournamentRepo.GetSubEvents(t.ID)
+	if err != nil {
+		return nil, err
+	}
+	var event *TournamentSubEvent
+	for i := range events {
+		if events[i].ID == eventID {
+			event = &events[i]
+			break
+		}
+	}
+	switch {
+	case event == nil:
+		reasons = append(reasons, TournamentFlagUnknownEvent)
+	case event.MinSubmitIntervalSeconds > 0:
+		last, err := svc.tournamentRepo.LastSubmission(charID, t.ID, eventID)
+		if err != nil {
+			return nil, err
+		}
+		minimum := time.Duration(event.MinSubmitIntervalSeconds) * time.Second
+		if last != nil && now.Sub(*last) < minimum {
+			reasons = append(reasons, fmt.Sprintf("%s: %ds after the previous run, minimum %ds",
+				TournamentFlagSubmissionRate, int(now.Sub(*last).Seconds()), event.MinSubmitIntervalSeconds))
+		}
+	}
+	return reasons, nil
+}
+
+// List returns every tournament, latest start first.
+func (svc *TournamentService) List() ([]Tournament, error) {
+	return svc.tournamentRepo.ListTournaments()
+}
+
+// Get returns a tournament with its cups, own sub-events and prizes.
+func (svc *TournamentService) Get(id uint32) (*TournamentDetail, error) {
+	t, err := svc.tournamentRepo.GetTournament(id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTournamentNotFound
+	}
+	detail := &TournamentDetail{Tournament: *t}
+	if detail.Cups, err = svc.tournamentRepo.GetCups(id); err != nil {
+		return nil, err
+	}
+	if detail.SubEvents, err = svc.tournamentRepo.GetOwnSubEvents(id); err != nil {
+		return nil, err
+	}
+	if detail.Prizes, err = svc.tournamentRepo.GetPrizes(id); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// Create schedules a new tournament. Phase ends left at zero default to the
+// retail lengths after the previous phase.
+func (svc *TournamentService) Create(detail TournamentDetail) (*TournamentDetail, error) {
+	if err := normalizeTournament(&detail); err != nil {
+		return nil, err
+	}
+	id, err := svc.tournamentRepo.CreateTournament(detail)
+	if err != nil {
+		return nil, err
+	}
+	return svc.Get(id)
+}
+
+// Update replaces a tournament's schedule, cups, sub-events and prizes.
+func (svc *TournamentService) Update(id uint32, detail TournamentDetail) (*TournamentDetail, error) {
+	detail.ID = id
+	if err := normalizeTournament(&detail); err != nil {
+		return nil, err
+	}
+	ok, err := svc.tournamentRepo.UpdateTournament(detail)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTournamentNotFound
+	}
+	return svc.Get(id)
+}
+
+// Delete removes a tournament and everything recorded for it.
+func (svc *TournamentService) Delete(id uint32) error {
+	ok, err := svc.tournamentRepo.DeleteTournament(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTournamentNotFound
+	}
+	return nil
+}
+
+// Results returns a tournament's runs, optionally only those with the given
+// status.
+func (svc *TournamentService) Results(id uint32, status string) ([]TournamentResult, error) {
+	switch status {
+	case "", TournamentResultOK, TournamentResultFlagged, TournamentResultVerified, TournamentResultRejected:
+	default:
+		return nil, fmt.Errorf("%w: unknown result status %q", ErrInvalidTournament, status)
+	}
+	t, err := svc.tournamentRepo.GetTournament(id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTournamentNotFound
+	}
+	return svc.tournamentRepo.GetResults(id, status)
+}
+
+// Review marks one of a tournament's results verified, so it can earn a
+// prize, or rejected, removing it from the leaderboard.
+func (svc *TournamentService) Review(id, resultID uint32, status string) error {
+	if status != TournamentResultVerified && status != TournamentResultRejected {
+		return fmt.Errorf("%w: status must be %q or %q", ErrInvalidTournament, TournamentResultVerified, TournamentResultRejected)
+	}
+	ok, err := svc.tournamentRepo.SetResultStatus(id, resultID, status)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTournamentResultNotFound
+	}
+	return nil
+}
+
+// Tick pays out every tournament whose reward phase has ended and rolls
+// recurring ones forward. Both are recorded in the database, so any number
+// of channels can tick without paying or copying a tournament twice.
+// Failures are logged and retried on the next tick.
+func (svc *TournamentService) Tick(now time.Time) {
+	unpaid, err := svc.tournamentRepo.ListUnpaid(now.Unix())
+	if err != nil {
+		svc.logger.Error("Failed to list unpaid tournaments", zap.Error(err))
+	}
+	for _, t := range unpaid {
+		svc.payPrizes(t)
+	}
+
+	due, err := svc.tournamentRepo.ListCycleDue(now.Unix())
+	if err != nil {
+		svc.logger.Error("Failed to list recurring tournaments", zap.Error(err))
+	}
+	for _, t := range due {
+		next := nextTournamentCycle(t, now.Unix())
+		ok, err := svc.tournamentRepo.CreateNextCycle(t, next)
+		if err != nil {
+			svc.logger.Error("Failed to roll tournament forward", zap.Error(err), zap.Uint32("tournamentID", t.ID))
+			continue
+		}
+		if ok {
+			svc.logger.Info("Scheduled next tournament cycle",
+				zap.Uint32("tournamentID", t.ID), zap.Int64("startTime", next.StartTime))
+		}
+	}
+}
+
+// payPrizes pays a tournament's prizes if no channel has yet.
+func (svc *TournamentService) payPrizes(t Tournament) {
+	prizes, err := svc.tournamentRepo.GetPrizes(t.ID)
+	if err != nil {
+		svc.logger.Error("Failed to read tournament prizes", zap.Error(err), zap.Uint32("tournamentID", t.ID))
+		return
+	}
+	var grants []TournamentPrizeGrant
+	if len(prizes) > 0 {
+		placings, err := svc.tournamentRepo.GetPlacings(t.ID)
+		if err != nil {
+			svc.logger.Error("Failed to read tournament placings", zap.Error(err), zap.Uint32("tournamentID", t.ID))
+			return
+		}
+		grants = tournamentPrizeGrants(t, placings, prizes)
+	}
+	ok, err := svc.tournamentRepo.PayPrizes(t.ID, grants, tournamentPrizeEventName)
+	if err != nil {
+		svc.logger.Error("Failed to pay tournament prizes", zap.Error(err), zap.Uint32("tournamentID", t.ID))
+		return
+	}
+	if ok {
+		svc.logger.Info("Paid tournament prizes",
+			zap.Uint32("tournamentID", t.ID), zap.Int("recipients", len(grants)))
+	}
+}
+
+// tournamentPrizeGrants matches each placing against the prize brackets of
+// its sub-event's cup group. Souls go to the placing's guild, once per guild
+// and sub-event, so a guild is credited for its best placing only.
+func tournamentPrizeGrants(t Tournament, placings []TournamentPlacing, prizes []TournamentPrize) []TournamentPrizeGrant {
+	type guildEvent struct{ guildID, eventID uint32 }
+	credited := make(map[guildEvent]bool)
+
+	var grants []TournamentPrizeGrant
+	for _, p := range placings {
+		grant := TournamentPrizeGrant{CharID: p.CharID, GuildID: p.GuildID, EventID: p.EventID, Place: p.Place}
+		for _, prize := range prizes {
+			if prize.CupGroup != p.CupGroup || p.Place < prize.PlaceFrom || p.Place > prize.PlaceTo {
+				continue
+			}
+			if prize.Quantity > 0 {
+				grant.Items = append(grant.Items, DistributionItem{ItemType: prize.ItemType, ItemID: prize.ItemID, Quantity: prize.Quantity})
+			}
+			grant.Souls += prize.Souls
+		}
+		key := guildEvent{p.GuildID, p.EventID}
+		if p.GuildID == 0 || credited[key] {
+			grant.Souls = 0
+		} else if grant.Souls > 0 {
+			credited[key] = true
+		}
+		if len(grant.Items) == 0 && grant.Souls == 0 {
+			continue
+		}
+		grant.Description = fmt.Sprintf("~C05%s: you placed #%d in %s.", t.Name, p.Place, p.EventName)
+		grants = append(grants, grant)
+	}
+	return grants
+}
+
+// nextTournamentCycle returns the copy of a recurring tournament that starts
+// a whole number of cycles after it, skipping any cycle that would already
+// have ended by now.
+func nextTournamentCycle(t Tournament, now int64) Tournament {
+	cycle := int64(t.CycleDays) * 24 * 60 * 60
+	cycles := int64(1)
+	if now >= t.RewardEnd {
+		cycles = (now-t.RewardEnd)/cycle + 1
+	}
+	shift := cycles * cycle
+	return Tournament{
+		Name:       t.Name,
+		StartTime:  t.StartTime + shift,
+		EntryEnd:   t.EntryEnd + shift,
+		RankingEnd: t.RankingEnd + shift,
+		RewardEnd:  t.RewardEnd + shift,
+		CycleDays:  t.CycleDays,
+	}
+}
+
+// normalizeTournament fills in default phase ends and validates a
+// tournament before it is stored.
+func normalizeTournament(d *TournamentDetail) error {
+	if d.Name == "" || utf8.RuneCountInString(d.Name) > tournamentNameMaxLength {
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidTournament, tournamentNameMaxLength)
+	}
+	if d.StartTime <= 0 {
+		return fmt.Errorf("%w: startTime is required", ErrInvalidTournament)
+	}
+	if d.EntryEnd == 0 {
+		d.EntryEnd = d.StartTime + tournamentEntryLength
+	}
+	if d.RankingEnd == 0 {
+		d.RankingEnd = d.EntryEnd + tournamentRankingLength
+	}
+	if d.RewardEnd == 0 {
+		d.RewardEnd = d.RankingEnd + tournamentRewardLength
+	}
+	if d.StartTime >= d.EntryEnd || d.EntryEnd >= d.RankingEnd || d.RankingEnd >= d.RewardEnd {
+		return fmt.Errorf("%w: phases must satisfy startTime < entryEnd < rankingEnd < rewardEnd", ErrInvalidTournament)
+	}
+	if d.CycleDays < 0 {
+		return fmt.Errorf("%w: cycleDays must not be negative", ErrInvalidTournament)
+	}
+	if d.CycleDays > 0 && int64(d.CycleDays)*24*60*60 < d.RewardEnd-d.StartTime {
+		return fmt.Errorf("%w: a %d day cycle is shorter than the tournament", ErrInvalidTournament, d.CycleDays)
+	}
+
+	if len(d.Cups) > tournamentMaxCups {
+		return fmt.Errorf("%w: at most %d cups", ErrInvalidTournament, tournamentMaxCups)
+	}
+	for _, cup := range d.Cups {
+		if cup.Name == "" || utf8.RuneCountInString(cup.Name) > tournamentNameMaxLength {
+			return fmt.Errorf("%w: cup names must be 1-%d characters", ErrInvalidTournament, tournamentNameMaxLength)
+		}
+	}
+	for _, se := range d.SubEvents {
+		if se.Name == "" || utf8.RuneCountInString(se.Name) > tournamentNameMaxLength {
+			return fmt.Errorf("%w: sub-event names must be 1-%d characters", ErrInvalidTournament, tournamentNameMaxLength)
+		}
+		if se.MinSubmitIntervalSeconds < 0 {
+			return fmt.Errorf("%w: minSubmitIntervalSeconds must not be negative", ErrInvalidTournament)
+		}
+	}
+	for _, p := range d.Prizes {
+		if p.PlaceFrom < 1 || p.PlaceTo < p.PlaceFrom {
+			return fmt.Errorf("%w: prize places must satisfy 1 <= placeFrom <= placeTo", ErrInvalidTournament)
+		}
+		if p.Quantity == 0 && p.Souls == 0 {
+			return fmt.Errorf("%w: prizes must grant a quantity of an item or souls", ErrInvalidTournament)
+		}
+	}
+	return nil
+}
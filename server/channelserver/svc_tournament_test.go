@@ -0,0 +1,256 @@
+package channelserver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newTestTournamentService(mock *mockTournamentRepo) *TournamentService {
+	logger, _ := zap.NewDevelopment()
+	return NewTournamentService(mock, logger)
+}
+
+var tournamentStart = time.Date(2026, 3, 6, 14, 0, 0, 0, time.UTC)
+
+func newTestTournament() *Tournament {
+	start := tournamentStart.Unix()
+	return &Tournament{
+		ID:         1,
+		Name:       "Tournament #151",
+		StartTime:  start,
+		EntryEnd:   start + tournamentEntryLength,
+		RankingEnd: start + tournamentEntryLength + tournamentRankingLength,
+		RewardEnd:  start + tournamentEntryLength + tournamentRankingLength + tournamentRewardLength,
+	}
+}
+
+func TestTournamentService_Submit(t *testing.T) {
+	inEntry := tournamentStart.Add(time.Hour)
+	last := inEntry.Add(-30 * time.Second)
+	tests := []struct {
+		name    string
+		now     time.Time
+		entry   *TournamentEntry
+		eventID uint32
+		last    *time.Time
+		reasons []string
+	}{
+		{"ok", inEntry, &TournamentEntry{ID: 1}, 10, nil, nil},
+		{"slow enough", inEntry, &TournamentEntry{ID: 1}, 10, &tournamentStart, nil},
+		{"after entry end", tournamentStart.Add(4 * 24 * time.Hour), &TournamentEntry{ID: 1}, 10, nil,
+			[]string{TournamentFlagOutsideEntry}},
+		{"not registered", inEntry, nil, 10, nil, []string{TournamentFlagNotRegistered}},
+		{"unknown event", inEntry, &TournamentEntry{ID: 1}, 99, nil, []string{TournamentFlagUnknownEvent}},
+		{"too fast", inEntry, &TournamentEntry{ID: 1}, 10, &last, []string{TournamentFlagSubmissionRate}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTournamentRepo{
+				tournaments:    map[uint32]*Tournament{1: newTestTournament()},
+				entry:          tt.entry,
+				subEvents:      []TournamentSubEvent{{ID: 10, CupGroup: 16, Name: "Brachydios", MinSubmitIntervalSeconds: 120}},
+				lastSubmission: tt.last,
+			}
+			svc := newTestTournamentService(mock)
+			if err := svc.Submit(5, 1, tt.eventID, 2, 3, tt.now); err != nil {
+				t.Fatalf("Submit: %v", err)
+			}
+			if len(mock.results) != 1 {
+				t.Fatalf("results = %d, want 1", len(mock.results))
+			}
+			r := mock.results[0]
+			if r.CharID != 5 || r.EventID != tt.eventID || r.QuestSlot != 2 || r.StageHandle != 3 || !r.SubmittedAt.Equal(tt.now) {
+				t.Errorf("result = %+v", r)
+			}
+			if len(tt.reasons) == 0 {
+				if r.Status != TournamentResultOK || r.FlagReason != "" {
+					t.Errorf("status = %q (%q), want ok", r.Status, r.FlagReason)
+				}
+				return
+			}
+			if r.Status != TournamentResultFlagged {
+				t.Errorf("status = %q, want flagged", r.Status)
+			}
+			for _, reason := range tt.reasons {
+				if !strings.Contains(r.FlagReason, reason) {
+					t.Errorf("flag reason %q does not mention %q", r.FlagReason, reason)
+				}
+			}
+		})
+	}
+}
+
+func TestTournamentService_SubmitUnknownTournament(t *testing.T) {
+	mock := &mockTournamentRepo{}
+	svc := newTestTournamentService(mock)
+	if err := svc.Submit(5, 1, 10, 0, 0, tournamentStart); !errors.Is(err, ErrTournamentNotFound) {
+		t.Errorf("err = %v, want ErrTournamentNotFound", err)
+	}
+	if len(mock.results) != 0 {
+		t.Error("result recorded for a tournament that does not exist")
+	}
+}
+
+func TestTournamentService_CreateDefaults(t *testing.T) {
+	mock := &mockTournamentRepo{}
+	svc := newTestTournamentService(mock)
+	start := tournamentStart.Unix()
+
+	detail, err := svc.Create(TournamentDetail{
+		Tournament: Tournament{Name: "Tournament #151", StartTime: start, CycleDays: 21},
+		Cups:       []TournamentCup{{CupGroup: 16, CupType: 7, Name: "Speed Hunt"}},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if detail.EntryEnd != start+259200 || detail.RankingEnd != start+259200+766800 || detail.RewardEnd != start+259200+766800+604800 {
+		t.Errorf("phases = %d/%d/%d, want the retail defaults", detail.EntryEnd, detail.RankingEnd, detail.RewardEnd)
+	}
+	if len(mock.created) != 1 || len(mock.created[0].Cups) != 1 {
+		t.Errorf("created = %+v", mock.created)
+	}
+}
+
+func TestTournamentService_CreateInvalid(t *testing.T) {
+	start := tournamentStart.Unix()
+	tests := []struct {
+		name   string
+		detail TournamentDetail
+	}{
+		{"no name", TournamentDetail{Tournament: Tournament{StartTime: start}}},
+		{"no start", TournamentDetail{Tournament: Tournament{Name: "T"}}},
+		{"phases out of order", TournamentDetail{Tournament: Tournament{Name: "T", StartTime: start, EntryEnd: start - 1}}},
+		{"cycle shorter than tournament", TournamentDetail{Tournament: Tournament{Name: "T", StartTime: start, CycleDays: 7}}},
+		{"unnamed cup", TournamentDetail{Tournament: Tournament{Name: "T", StartTime: start}, Cups: []TournamentCup{{}}}},
+		{"bad prize places", TournamentDetail{Tournament: Tournament{Name: "T", StartTime: start},
+			Prizes: []TournamentPrize{{PlaceFrom: 3, PlaceTo: 1, Quantity: 1}}}},
+		{"empty prize", TournamentDetail{Tournament: Tournament{Name: "T", StartTime: start},
+			Prizes: []TournamentPrize{{PlaceFrom: 1, PlaceTo: 1}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockTournamentRepo{}
+			svc := newTestTournamentService(mock)
+			if _, err := svc.Create(tt.detail); !errors.Is(err, ErrInvalidTournament) {
+				t.Errorf("err = %v, want ErrInvalidTournament", err)
+			}
+			if len(mock.created) != 0 {
+				t.Error("invalid tournament was stored")
+			}
+		})
+	}
+}
+
+func TestTournamentService_UpdateNotFound(t *testing.T) {
+	svc := newTestTournamentService(&mockTournamentRepo{})
+	_, err := svc.Update(9, TournamentDetail{Tournament: Tournament{Name: "T", StartTime: tournamentStart.Unix()}})
+	if !errors.Is(err, ErrTournamentNotFound) {
+		t.Errorf("err = %v, want ErrTournamentNotFound", err)
+	}
+}
+
+func TestTournamentService_Review(t *testing.T) {
+	mock := &mockTournamentRepo{results: []TournamentResult{{ID: 4, Status: TournamentResultFlagged}}}
+	svc := newTestTournamentService(mock)
+
+	if err := svc.Review(1, 4, TournamentResultOK); !errors.Is(err, ErrInvalidTournament) {
+		t.Errorf("review to ok: err = %v, want ErrInvalidTournament", err)
+	}
+	if err := svc.Review(1, 5, TournamentResultVerified); !errors.Is(err, ErrTournamentResultNotFound) {
+		t.Errorf("unknown result: err = %v, want ErrTournamentResultNotFound", err)
+	}
+	if err := svc.Review(1, 4, TournamentResultRejected); err != nil {
+		t.Fatalf("Review: %v", err)
+	}
+	if mock.results[0].Status != TournamentResultRejected {
+		t.Errorf("status = %q, want rejected", mock.results[0].Status)
+	}
+}
+
+func TestTournamentPrizeGrants(t *testing.T) {
+	placings := []TournamentPlacing{
+		{EventID: 1, EventName: "Brachydios", CupGroup: 16, CharID: 10, GuildID: 0, Place: 1},
+		{EventID: 1, EventName: "Brachydios", CupGroup: 16, CharID: 11, GuildID: 7, Place: 2},
+		{EventID: 1, EventName: "Brachydios", CupGroup: 16, CharID: 12, GuildID: 7, Place: 3},
+		{EventID: 1, EventName: "Brachydios", CupGroup: 16, CharID: 13, GuildID: 8, Place: 4},
+		{EventID: 2, EventName: "Guild Hunt", CupGroup: 17, CharID: 11, GuildID: 7, Place: 1},
+	}
+	prizes := []TournamentPrize{
+		{CupGroup: 16, PlaceFrom: 1, PlaceTo: 1, ItemType: 7, ItemID: 100, Quantity: 1},
+		{CupGroup: 16, PlaceFrom: 1, PlaceTo: 3, ItemType: 7, ItemID: 200, Quantity: 5, Souls: 50},
+		{CupGroup: 17, PlaceFrom: 1, PlaceTo: 10, Souls: 5000},
+	}
+	grants := tournamentPrizeGrants(*newTestTournament(), placings, prizes)
+
+	byChar := make(map[[2]uint32]TournamentPrizeGrant)
+	for _, g := range grants {
+		byChar[[2]uint32{g.EventID, g.CharID}] = g
+	}
+	if len(grants) != 4 {
+		t.Fatalf("grants = %d, want 4 (4th place earns nothing)", len(grants))
+	}
+	if g := byChar[[2]uint32{1, 10}]; len(g.Items) != 2 || g.Souls != 0 {
+		t.Errorf("1st place = %+v, want both items and no souls without a guild", g)
+	}
+	if g := byChar[[2]uint32{1, 11}]; len(g.Items) != 1 || g.Souls != 50 {
+		t.Errorf("2nd place = %+v, want one item and 50 souls", g)
+	}
+	if g := byChar[[2]uint32{1, 12}]; len(g.Items) != 1 || g.Souls != 0 {
+		t.Errorf("3rd place = %+v, want one item and no souls (guild already credited)", g)
+	}
+	if g := byChar[[2]uint32{2, 11}]; len(g.Items) != 0 || g.Souls != 5000 {
+		t.Errorf("guild cup = %+v, want 5000 souls", g)
+	}
+	if g := byChar[[2]uint32{1, 10}]; !strings.Contains(g.Description, "#1") || !strings.Contains(g.Description, "Brachydios") {
+		t.Errorf("description = %q", g.Description)
+	}
+}
+
+func TestNextTournamentCycle(t *testing.T) {
+	prev := *newTestTournament()
+	prev.CycleDays = 21
+	cycle := int64(21 * 24 * 60 * 60)
+
+	next := nextTournamentCycle(prev, prev.RewardEnd)
+	if next.StartTime != prev.StartTime+cycle || next.RewardEnd != prev.RewardEnd+cycle || next.CycleDays != 21 {
+		t.Errorf("next = %+v, want one cycle later", next)
+	}
+
+	// Two whole cycles missed while the server was down: the copy starts in
+	// the cycle that has not ended yet.
+	late := nextTournamentCycle(prev, prev.RewardEnd+2*cycle+1)
+	if late.StartTime != prev.StartTime+3*cycle {
+		t.Errorf("late start = %d, want %d", late.StartTime, prev.StartTime+3*cycle)
+	}
+}
+
+func TestTournamentService_Tick(t *testing.T) {
+	ended := *newTestTournament()
+	ended.CycleDays = 21
+	mock := &mockTournamentRepo{
+		unpaid:   []Tournament{ended},
+		cycleDue: []Tournament{ended},
+		prizes:   []TournamentPrize{{CupGroup: 16, PlaceFrom: 1, PlaceTo: 1, ItemType: 7, ItemID: 1, Quantity: 1}},
+		placings: []TournamentPlacing{{EventID: 1, CupGroup: 16, CharID: 10, Place: 1}},
+	}
+	svc := newTestTournamentService(mock)
+	now := time.Unix(ended.RewardEnd+60, 0)
+
+	svc.Tick(now)
+	if grants, ok := mock.paid[ended.ID]; !ok || len(grants) != 1 || grants[0].CharID != 10 {
+		t.Errorf("paid = %+v, want one grant for character 10", mock.paid)
+	}
+	if len(mock.cycled) != 1 || mock.cycled[0].StartTime != ended.StartTime+21*24*60*60 {
+		t.Errorf("cycled = %+v, want the next cycle", mock.cycled)
+	}
+
+	// A second tick (or another channel) does not pay twice.
+	svc.Tick(now)
+	if len(mock.paid) != 1 {
+		t.Errorf("paid %d tournaments, want 1", len(mock.paid))
+	}
+}
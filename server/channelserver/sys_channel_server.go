@@ -32,6 +32,7 @@ type Config struct {
 	ErupeConfig *cfg.Config
 	Name        string
 	Enable      bool
+	Metrics     *Metrics // Optional; nil disables instrumentation.
 }
 
 // Server is a MHF channel server.
@@ -40,6 +41,7 @@ type Config struct {
 //  1. Server.Mutex          – protects sessions map
 //  2. Stage.RWMutex         – protects per-stage state (clients, objects)
 //  3. Server.semaphoreLock  – protects semaphore map
+//  4. Server.mutexLock      – protects mutexes map and every Mutex in it
 //
 // Note: Server.stages is a StageMap (sync.Map-backed), so it requires no
 // external lock for reads or writes.
@@ -48,49 +50,58 @@ type Config struct {
 // own locks internally and may be acquired at any point.
 type Server struct {
 	sync.Mutex
-	Registry           ChannelRegistry
-	ID                 uint16
-	GlobalID           string
-	IP                 string
-	Port               uint16
-	logger             *zap.Logger
-	db                 *sqlx.DB
-	charRepo           CharacterRepo
-	guildRepo          GuildRepo
-	userRepo           UserRepo
-	gachaRepo          GachaRepo
-	houseRepo          HouseRepo
-	festaRepo          FestaRepo
-	towerRepo          TowerRepo
-	rengokuRepo        RengokuRepo
-	mailRepo           MailRepo
-	stampRepo          StampRepo
-	distRepo           DistributionRepo
-	sessionRepo        SessionRepo
-	eventRepo          EventRepo
-	achievementRepo    AchievementRepo
-	shopRepo           ShopRepo
-	cafeRepo           CafeRepo
-	goocooRepo         GoocooRepo
-	divaRepo           DivaRepo
-	miscRepo           MiscRepo
-	scenarioRepo       ScenarioRepo
-	mercenaryRepo      MercenaryRepo
-	tournamentRepo     TournamentRepo
-	caravanRepo        CaravanRepo
-	mailService        *MailService
-	guildService       *GuildService
-	achievementService *AchievementService
-	gachaService       *GachaService
-	towerService       *TowerService
-	festaService       *FestaService
-	erupeConfig        *cfg.Config
-	acceptConns        chan net.Conn
-	deleteConns        chan net.Conn
-	sessions           map[net.Conn]*Session
-	listener           net.Listener // Listener that is created when Server.Start is called.
-	isShuttingDown     bool
-	done               chan struct{} // Closed on Shutdown to wake background goroutines.
+	Registry            ChannelRegistry
+	ID                  uint16
+	GlobalID            string
+	IP                  string
+	Port                uint16
+	logger              *zap.Logger
+	db                  *sqlx.DB
+	charRepo            CharacterRepo
+	guildRepo           GuildRepo
+	userRepo            UserRepo
+	gachaRepo           GachaRepo
+	houseRepo           HouseRepo
+	festaRepo           FestaRepo
+	towerRepo           TowerRepo
+	rengokuRepo         RengokuRepo
+	mailRepo            MailRepo
+	stampRepo           StampRepo
+	distRepo            DistributionRepo
+	sessionRepo         SessionRepo
+	eventRepo           EventRepo
+	achievementRepo     AchievementRepo
+	shopRepo            ShopRepo
+	cafeRepo            CafeRepo
+	goocooRepo          GoocooRepo
+	divaRepo            DivaRepo
+	miscRepo            MiscRepo
+	scenarioRepo        ScenarioRepo
+	mercenaryRepo       MercenaryRepo
+	tournamentRepo      TournamentRepo
+	caravanRepo         CaravanRepo
+	dailyMissionRepo    DailyMissionRepo
+	conquestRepo        ConquestRepo
+	seibattleRepo       SeibattleRepo
+	ravienteRepo        RavienteRepo
+	fortAttackRepo      FortAttackRepo
+	mailService         *MailService
+	guildService        *GuildService
+	achievementService  *AchievementService
+	gachaService        *GachaService
+	towerService        *TowerService
+	festaService        *FestaService
+	conquestService     *ConquestService
+	tournamentService   *TournamentService
+	fortAttackService   *FortAttackService
+	eventQuestScheduler *EventQuestScheduler
+	erupeConfig         *cfg.Config
+	acceptConns         chan net.Conn
+	deleteConns         chan net.Conn
+	sessions            map[net.Conn]*Session
+	listener            net.Listener // Listener that is created when Server.Start is called.
+	isShuttingDown      bool
+	done                chan struct{} // Closed on Shutdown to wake background goroutines.
 
 	stages StageMap
 
@@ -109,6 +120,12 @@ type Server struct {
 	semaphore      map[string]*Semaphore
 	semaphoreIndex uint32
 
+	// Mutex
+	mutexLock sync.Mutex
+	mutexes   map[string]*Mutex
+	// Last issued mutex ID; IDs start at 1 so 0 can signal failure.
+	mutexIndex uint32
+
 	// Discord chat integration
 	discordBot *discordbot.DiscordBot
 
@@ -118,8 +135,12 @@ type Server struct {
 
 	questCache *QuestCache
 
+	metrics *Metrics
+
 	rengokuBin []byte // Cached rengoku_data.bin (ECD-encrypted, served to clients as-is)
 
+	dailyMissions []DailyMission // Loaded from daily_missions.json
+
 	handlerTable map[network.PacketID]handlerFunc
 }
 
@@ -138,13 +159,15 @@ func NewServer(config *Config) *Server {
 		minidata:       NewMinidataStore(),
 		semaphore:      make(map[string]*Semaphore),
 		semaphoreIndex: 7,
+		mutexes:        make(map[string]*Mutex),
 		discordBot:     config.DiscordBot,
+		metrics:        config.Metrics,
 		name:           config.Name,
 		raviente: &Raviente{
 			id:       1,
-			register: make([]uint32, 30),
-			state:    make([]uint32, 30),
-			support:  make([]uint32, 30),
+			register: make([]uint32, raviRegisterSize),
+			state:    make([]uint32, raviRegisterSize),
+			support:  make([]uint32, raviRegisterSize),
 		},
 		questCache:   NewQuestCache(config.ErupeConfig.QuestCacheExpiry),
 		handlerTable: buildHandlerTable(),
@@ -173,6 +196,15 @@ func NewServer(config *Config) *Server {
 	s.mercenaryRepo = NewMercenaryRepository(config.DB)
 	s.tournamentRepo = NewTournamentRepository(config.DB)
 	s.caravanRepo = NewCaravanRepository(config.DB)
+	s.dailyMissionRepo = NewDailyMissionRepository(config.DB)
+	s.conquestRepo = NewConquestRepository(config.DB)
+	s.seibattleRepo = NewSeibattleRepository(config.DB)
+	s.fortAttackRepo = NewFortAttackRepository(config.DB)
+	// Siege state is saved from a background loop, so it is only wired up
+	// with a database.
+	if config.DB != nil {
+		s.ravienteRepo = NewRavienteRepository(config.DB)
+	}
 
 	s.mailService = NewMailService(s.mailRepo, s.guildRepo, s.logger)
 	s.guildService = NewGuildService(s.guildRepo, s.mailService, s.charRepo, s.logger)
@@ -180,6 +212,10 @@ func NewServer(config *Config) *Server {
 	s.gachaService = NewGachaService(s.gachaRepo, s.userRepo, s.charRepo, s.logger, config.ErupeConfig.GameplayOptions.MaximumNP)
 	s.towerService = NewTowerService(s.towerRepo, s.logger)
 	s.festaService = NewFestaService(s.festaRepo, s.logger)
+	s.conquestService = NewConquestService(s.conquestRepo, s.logger)
+	s.tournamentService = NewTournamentService(s.tournamentRepo, s.logger)
+	s.fortAttackService = NewFortAttackService(s.fortAttackRepo, s.logger)
+	s.eventQuestScheduler = NewEventQuestScheduler(s.eventRepo, s.logger)
 
 	// Mezeporta
 	s.stages.Store("sl1Ns200p0a0u0", NewStage("sl1Ns200p0a0u0"))
@@ -203,6 +239,7 @@ func NewServer(config *Config) *Server {
 	s.stages.Store("sl1Ns462p0a0u0", NewStage("sl1Ns462p0a0u0"))
 
 	s.rengokuBin = loadRengokuBinary(config.ErupeConfig.BinPath, s.logger)
+	s.dailyMissions = loadDailyMissions(config.ErupeConfig.BinPath, s.logger)
 
 	s.i18n = getLangStrings(s)
 
@@ -219,9 +256,28 @@ func (s *Server) Start() error {
 
 	initCommands(s.erupeConfig.Commands, s.logger)
 
+	s.metrics.registerServer(s)
+
 	go s.acceptClients()
 	go s.manageSessions()
 	go s.invalidateSessions()
+	if s.ravienteRepo != nil {
+		s.restoreRaviente()
+		go s.syncRaviente()
+	}
+	if s.db != nil {
+		go s.runTournaments()
+		go s.runEventQuests()
+		if s.erupeConfig.Festa.Enabled {
+			go s.runFesta()
+		}
+		if s.erupeConfig.FortAttack.Enabled {
+			go s.runFortAttacks()
+		}
+		if s.erupeConfig.EarthStatus != 0 {
+			go s.runConquest()
+		}
+	}
 
 	// Start the discord bot for chat integration.
 	if s.erupeConfig.Discord.Enabled && s.discordBot != nil {
@@ -406,6 +462,22 @@ func (s *Server) invalidateSessions() {
 	}
 }
 
+// runPeriodic calls fn straight away and then every interval until the
+// server shuts down.
+func (s *Server) runPeriodic(interval time.Duration, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	fn()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			fn()
+		}
+	}
+}
+
 // BroadcastMHF queues a MHFPacket to be sent to all sessions.
 func (s *Server) BroadcastMHF(pkt mhfpacket.MHFPacket, ignoredSession *Session) {
 	// Broadcast the data.
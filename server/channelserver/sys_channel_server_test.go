@@ -63,6 +63,7 @@ func createTestServer() *Server {
 		logger:     logger,
 		sessions:   make(map[net.Conn]*Session),
 		semaphore:  make(map[string]*Semaphore),
+		mutexes:    make(map[string]*Mutex),
 		questCache: NewQuestCache(0),
 		erupeConfig: &cfg.Config{
 			DebugOptions: cfg.DebugOptions{
@@ -806,3 +807,36 @@ func TestLoadRengokuBinary_BadMagic(t *testing.T) {
 		t.Errorf("Expected nil for bad magic, got %d bytes", len(result))
 	}
 }
+
+// TestRunPeriodic verifies that runPeriodic ticks straight away, keeps
+// ticking and stops once the server is shut down.
+func TestRunPeriodic(t *testing.T) {
+	server := createTestServer()
+	server.done = make(chan struct{})
+
+	ticks := make(chan struct{}, 8)
+	stopped := make(chan struct{})
+	go func() {
+		server.runPeriodic(time.Millisecond, func() {
+			select {
+			case ticks <- struct{}{}:
+			default:
+			}
+		})
+		close(stopped)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-ticks:
+		case <-time.After(time.Second):
+			t.Fatalf("tick %d did not happen", i+1)
+		}
+	}
+	close(server.done)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("runPeriodic did not stop after shutdown")
+	}
+}
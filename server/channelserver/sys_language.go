@@ -71,6 +71,7 @@ type i18n struct {
 			start     struct {
 				success string
 				error   string
+				closed  string
 			}
 			multiplier string
 			res        struct {
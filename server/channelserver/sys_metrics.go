@@ -0,0 +1,104 @@
+package channelserver
+
+import (
+	"fmt"
+	"time"
+
+	"erupe-ce/common/metrics"
+	"erupe-ce/network"
+)
+
+// Metrics holds the channel server collectors. One Metrics is shared by every
+// channel server in the process; each series carries a server_id label
+// matching the servers table. A nil *Metrics records nothing, which is what
+// tests and tools that build a Server directly get.
+type Metrics struct {
+	sessions         *metrics.Gauge
+	packetsIn        *metrics.Counter
+	packetsOut       *metrics.Counter
+	handlerLatency   *metrics.Histogram
+	saveDuration     *metrics.Histogram
+	saveFailures     *metrics.Counter
+	questCacheHits   *metrics.Counter
+	questCacheMisses *metrics.Counter
+}
+
+// NewMetrics registers the channel server collectors on reg.
+func NewMetrics(reg *metrics.Registry) *Metrics {
+	return &Metrics{
+		sessions: reg.NewGauge("erupe_channel_sessions",
+			"Connected sessions.", "server_id"),
+		packetsIn: reg.NewCounter("erupe_channel_packets_received_total",
+			"Packets received from clients.", "server_id", "opcode"),
+		packetsOut: reg.NewCounter("erupe_channel_packets_sent_total",
+			"Packets queued for clients.", "server_id", "opcode"),
+		handlerLatency: reg.NewHistogram("erupe_channel_handler_duration_seconds",
+			"Time from receiving a packet to its handler returning.", nil, "server_id", "opcode"),
+		saveDuration: reg.NewHistogram("erupe_channel_savedata_duration_seconds",
+			"Time spent handling MSG_MHF_SAVEDATA.", nil, "server_id"),
+		saveFailures: reg.NewCounter("erupe_channel_savedata_failures_total",
+			"MSG_MHF_SAVEDATA requests that did not persist the save.", "server_id"),
+		questCacheHits: reg.NewCounter("erupe_channel_quest_cache_hits_total",
+			"Quest file lookups served from the cache.", "server_id"),
+		questCacheMisses: reg.NewCounter("erupe_channel_quest_cache_misses_total",
+			"Quest file lookups that missed the cache.", "server_id"),
+	}
+}
+
+func serverIDLabel(s *Server) string {
+	return fmt.Sprint(s.ID)
+}
+
+// registerServer exposes the scrape-time series of s: its session count and
+// quest cache statistics.
+func (m *Metrics) registerServer(s *Server) {
+	if m == nil {
+		return
+	}
+	id := serverIDLabel(s)
+	m.sessions.Func(func() float64 {
+		s.Lock()
+		defer s.Unlock()
+		return float64(len(s.sessions))
+	}, id)
+	m.questCacheHits.Func(func() float64 {
+		hits, _ := s.questCache.Stats()
+		return float64(hits)
+	}, id)
+	m.questCacheMisses.Func(func() float64 {
+		_, misses := s.questCache.Stats()
+		return float64(misses)
+	}, id)
+}
+
+func (m *Metrics) packetReceived(s *Server, opcode network.PacketID) {
+	if m == nil {
+		return
+	}
+	m.packetsIn.Inc(serverIDLabel(s), opcode.String())
+}
+
+func (m *Metrics) packetSent(s *Server, opcode network.PacketID) {
+	if m == nil {
+		return
+	}
+	m.packetsOut.Inc(serverIDLabel(s), opcode.String())
+}
+
+func (m *Metrics) handlerDone(s *Server, opcode network.PacketID, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.handlerLatency.Observe(elapsed.Seconds(), serverIDLabel(s), opcode.String())
+}
+
+func (m *Metrics) saveDone(s *Server, elapsed time.Duration, saved bool) {
+	if m == nil {
+		return
+	}
+	id := serverIDLabel(s)
+	m.saveDuration.Observe(elapsed.Seconds(), id)
+	if !saved {
+		m.saveFailures.Inc(id)
+	}
+}
@@ -0,0 +1,118 @@
+package channelserver
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"erupe-ce/common/byteframe"
+	"erupe-ce/common/metrics"
+	"erupe-ce/network"
+	"erupe-ce/network/mhfpacket"
+)
+
+func newMetricsTestServer(t *testing.T) (*Server, *metrics.Registry) {
+	t.Helper()
+	reg := metrics.NewRegistry()
+	s := createMockServer()
+	s.ID = 4112
+	s.questCache = NewQuestCache(60)
+	s.metrics = NewMetrics(reg)
+	return s, reg
+}
+
+func scrape(t *testing.T, reg *metrics.Registry) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := reg.WriteText(&sb); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	return sb.String()
+}
+
+func TestMetrics_NilIsNoop(t *testing.T) {
+	var m *Metrics
+	s := createMockServer()
+	m.registerServer(s)
+	m.packetReceived(s, network.MSG_SYS_PING)
+	m.packetSent(s, network.MSG_SYS_ACK)
+	m.handlerDone(s, network.MSG_SYS_PING, time.Millisecond)
+	m.saveDone(s, time.Millisecond, false)
+}
+
+func TestMetrics_PacketCountersAndHandlerLatency(t *testing.T) {
+	server, reg := newMetricsTestServer(t)
+	session := createMockSession(1, server)
+	session.ackStart = make(map[uint32]time.Time)
+
+	bf := byteframe.NewByteFrame()
+	bf.WriteUint16(uint16(network.MSG_SYS_PING))
+	bf.WriteUint32(0xCAFE)
+	session.handlePacketGroup(bf.Data())
+
+	out := scrape(t, reg)
+	for _, want := range []string{
+		`erupe_channel_packets_received_total{server_id="4112",opcode="MSG_SYS_PING"} 1`,
+		`erupe_channel_packets_sent_total{server_id="4112",opcode="MSG_SYS_ACK"} 1`,
+		`erupe_channel_handler_duration_seconds_count{server_id="4112",opcode="MSG_SYS_PING"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetrics_DroppedPacketsNotCounted(t *testing.T) {
+	server, reg := newMetricsTestServer(t)
+	session := createMockSession(1, server)
+	session.sendPackets = make(chan packet, 1)
+
+	session.QueueSendNonBlocking([]byte{0x00, 0x12, 0x00})
+	session.QueueSendNonBlocking([]byte{0x00, 0x12, 0x00}) // queue full, dropped
+
+	if out := scrape(t, reg); !strings.Contains(out, `erupe_channel_packets_sent_total{server_id="4112",opcode="MSG_SYS_ACK"} 1`) {
+		t.Errorf("expected exactly one sent packet:\n%s", out)
+	}
+}
+
+func TestMetrics_SavedataFailure(t *testing.T) {
+	server, reg := newMetricsTestServer(t)
+	session := createMockSession(1, server)
+
+	handleMsgMhfSavedata(session, &mhfpacket.MsgMhfSavedata{
+		AckHandle:      1,
+		RawDataPayload: make([]byte, saveDataMaxCompressedPayload+1),
+	})
+
+	out := scrape(t, reg)
+	if !strings.Contains(out, `erupe_channel_savedata_failures_total{server_id="4112"} 1`) {
+		t.Errorf("failure not counted:\n%s", out)
+	}
+	if !strings.Contains(out, `erupe_channel_savedata_duration_seconds_count{server_id="4112"} 1`) {
+		t.Errorf("duration not observed:\n%s", out)
+	}
+}
+
+func TestMetrics_RegisterServer(t *testing.T) {
+	server, reg := newMetricsTestServer(t)
+	server.metrics.registerServer(server)
+
+	conn := &mockConn{}
+	server.sessions[net.Conn(conn)] = createMockSession(1, server)
+	server.questCache.Put(1, "en", []byte{1})
+	server.questCache.Get(1, "en")
+	server.questCache.Get(2, "en")
+	server.questCache.Get(3, "en")
+
+	out := scrape(t, reg)
+	for _, want := range []string{
+		`erupe_channel_sessions{server_id="4112"} 1`,
+		`erupe_channel_quest_cache_hits_total{server_id="4112"} 1`,
+		`erupe_channel_quest_cache_misses_total{server_id="4112"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
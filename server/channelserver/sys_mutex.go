@@ -0,0 +1,155 @@
+package channelserver
+
+import (
+	"erupe-ce/common/byteframe"
+	"erupe-ce/network/mhfpacket"
+)
+
+// Mutex is a named, server-wide lock clients use to serialise access to
+// shared state (e.g. a guild's hall or a house's decorations). Unlike a
+// Semaphore it has at most one owner; further openers queue in FIFO order.
+//
+// Mutex has no lock of its own: every field is guarded by
+// Server.mutexLock.
+type Mutex struct {
+	// Mutex name string
+	name string
+
+	id uint32
+
+	// Session currently holding the mutex, or nil if it is free.
+	owner *Session
+
+	// Map of session -> charID.
+	// These are clients that created or opened the Mutex.
+	members map[*Session]uint32
+
+	// Sessions blocked in OpenMutex, in arrival order.
+	waiters []mutexWaiter
+}
+
+// mutexWaiter is a queued OpenMutex whose ack is deferred until the
+// session is granted ownership.
+type mutexWaiter struct {
+	session   *Session
+	ackHandle uint32
+}
+
+// mutexAck is an ack produced while Server.mutexLock was held, sent once
+// the lock has been released.
+type mutexAck struct {
+	session   *Session
+	ackHandle uint32
+	id        uint32 // 0 acks a failure
+}
+
+// BroadcastMHF queues a MHFPacket to be sent to all sessions in the Mutex
+func (m *Mutex) BroadcastMHF(pkt mhfpacket.MHFPacket, ignoredSession *Session) {
+	for session := range m.members {
+		if session == ignoredSession {
+			continue
+		}
+		bf := byteframe.NewByteFrame()
+		bf.WriteUint16(uint16(pkt.Opcode()))
+		_ = pkt.Build(bf, session.clientContext)
+		session.QueueSendNonBlocking(bf.Data())
+	}
+}
+
+// createMutex returns the named mutex, creating it if needed, and registers
+// ses as a member. The caller must hold Server.mutexLock.
+func (s *Server) createMutex(ses *Session, name string) *Mutex {
+	m, exists := s.mutexes[name]
+	if !exists {
+		s.mutexIndex++
+		m = &Mutex{
+			name:    name,
+			id:      s.mutexIndex,
+			members: make(map[*Session]uint32),
+		}
+		s.mutexes[name] = m
+	}
+	m.members[ses] = ses.charID
+	return m
+}
+
+// open tries to take ownership of m for s. It reports whether s owns
+// the mutex on return; if not, s has been queued and will be acked when
+// ownership is handed over. The caller must hold Server.mutexLock.
+func (m *Mutex) open(s *Session, ackHandle uint32) bool {
+	m.members[s] = s.charID
+	if m.owner == nil || m.owner == s {
+		m.owner = s
+		return true
+	}
+	m.waiters = append(m.waiters, mutexWaiter{session: s, ackHandle: ackHandle})
+	return false
+}
+
+// release hands ownership to the next waiter, if any, and returns the ack
+// granting it. Other members are notified of the new owner with an
+// unsolicited MsgSysOpenMutex (ack handle 0). The caller must hold
+// Server.mutexLock.
+func (m *Mutex) release() []mutexAck {
+	m.owner = nil
+	if len(m.waiters) == 0 {
+		return nil
+	}
+	next := m.waiters[0]
+	m.waiters = m.waiters[1:]
+	m.owner = next.session
+	m.BroadcastMHF(&mhfpacket.MsgSysOpenMutex{MutexName: m.name}, next.session)
+	return []mutexAck{{session: next.session, ackHandle: next.ackHandle, id: m.id}}
+}
+
+// removeWaiter drops any queued OpenMutex from s, returning its ack handle
+// and whether one was found. The caller must hold Server.mutexLock.
+func (m *Mutex) removeWaiter(s *Session) (uint32, bool) {
+	for i, w := range m.waiters {
+		if w.session == s {
+			m.waiters = append(m.waiters[:i], m.waiters[i+1:]...)
+			return w.ackHandle, true
+		}
+	}
+	return 0, false
+}
+
+// sendMutexAcks delivers acks collected under Server.mutexLock. Acks for
+// other sessions are queued without blocking so one stalled client cannot
+// wedge the session releasing the mutex.
+func sendMutexAcks(s *Session, acks []mutexAck) {
+	for _, a := range acks {
+		ack := &mhfpacket.MsgSysAck{AckHandle: a.ackHandle, AckData: make([]byte, 4)}
+		if a.id == 0 {
+			ack.ErrorCode = 1
+		} else {
+			bf := byteframe.NewByteFrame()
+			bf.WriteUint32(a.id)
+			ack.AckData = bf.Data()
+		}
+		if a.session == s {
+			a.session.QueueSendMHF(ack)
+		} else {
+			a.session.QueueSendMHFNonBlocking(ack)
+		}
+	}
+}
+
+// releaseSessionMutexes removes s from every mutex on logout, handing over
+// any mutex it owns and deleting mutexes left without members.
+func releaseSessionMutexes(s *Session) {
+	var acks []mutexAck
+	s.server.mutexLock.Lock()
+	for name, m := range s.server.mutexes {
+		delete(m.members, s)
+		m.removeWaiter(s)
+		if m.owner == s {
+			acks = append(acks, m.release()...)
+		}
+		if len(m.members) == 0 && m.owner == nil {
+			delete(s.server.mutexes, name)
+		}
+	}
+	s.server.mutexLock.Unlock()
+	sendMutexAcks(s, acks)
+}
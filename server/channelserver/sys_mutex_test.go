@@ -0,0 +1,124 @@
+package channelserver
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"testing"
+
+	"erupe-ce/network/mhfpacket"
+)
+
+func TestReleaseSessionMutexes(t *testing.T) {
+	server := createMockServer()
+	a := createMockSession(1, server)
+	b := createMockSession(2, server)
+
+	handleMsgSysCreateOpenMutex(a, &mhfpacket.MsgSysCreateOpenMutex{AckHandle: 1, MutexName: "held"})
+	expectMutexAck(t, a, 1, true)
+	handleMsgSysOpenMutex(b, &mhfpacket.MsgSysOpenMutex{AckHandle: 2, MutexName: "held"})
+	handleMsgSysCreateMutex(a, &mhfpacket.MsgSysCreateMutex{AckHandle: 3, MutexName: "solo"})
+	expectMutexAck(t, a, 3, true)
+
+	releaseSessionMutexes(a)
+
+	expectMutexAck(t, b, 2, true)
+	if m := server.mutexes["held"]; m == nil || m.owner != b {
+		t.Fatal("held mutex should pass to the waiting session")
+	}
+	if _, exists := server.mutexes["solo"]; exists {
+		t.Error("mutex without members should be deleted")
+	}
+
+	releaseSessionMutexes(b)
+	if len(server.mutexes) != 0 {
+		t.Errorf("mutexes = %d, want 0 after every member left", len(server.mutexes))
+	}
+}
+
+func TestReleaseSessionMutexes_DropsQueuedOpen(t *testing.T) {
+	server := createMockServer()
+	a := createMockSession(1, server)
+	b := createMockSession(2, server)
+
+	handleMsgSysCreateOpenMutex(a, &mhfpacket.MsgSysCreateOpenMutex{AckHandle: 1, MutexName: "m"})
+	expectMutexAck(t, a, 1, true)
+	handleMsgSysOpenMutex(b, &mhfpacket.MsgSysOpenMutex{AckHandle: 2, MutexName: "m"})
+
+	releaseSessionMutexes(b)
+
+	handleMsgSysCloseMutex(a, &mhfpacket.MsgSysCloseMutex{AckHandle: 3, MutexName: "m"})
+	expectMutexAck(t, a, 3, true)
+	expectNoPacket(t, b)
+	if m := server.mutexes["m"]; m.owner != nil {
+		t.Error("mutex should be free once the only waiter logged out")
+	}
+}
+
+func TestLogoutPlayerReleasesMutexes(t *testing.T) {
+	server := createMockServer()
+	// charID 0 skips the character-save path, which needs a database.
+	a := createMockSession(0, server)
+	a.rawConn = &mockConn{}
+	b := createMockSession(2, server)
+
+	handleMsgSysCreateOpenMutex(a, &mhfpacket.MsgSysCreateOpenMutex{AckHandle: 1, MutexName: "m"})
+	expectMutexAck(t, a, 1, true)
+	handleMsgSysOpenMutex(b, &mhfpacket.MsgSysOpenMutex{AckHandle: 2, MutexName: "m"})
+
+	logoutPlayer(a)
+
+	expectMutexAck(t, b, 2, true)
+}
+
+// TestMutexConcurrentOpenClose hammers one mutex from many sessions. Run
+// with -race; every open must eventually be granted exactly once.
+func TestMutexConcurrentOpenClose(t *testing.T) {
+	server := createMockServer()
+	const sessions, rounds = 8, 25
+
+	handleMsgSysCreateMutex(createMockSession(0, server), &mhfpacket.MsgSysCreateMutex{MutexName: "m"})
+
+	var wg sync.WaitGroup
+	for i := 1; i <= sessions; i++ {
+		s := createMockSession(uint32(i), server)
+		s.sendPackets = make(chan packet, 2048)
+		wg.Add(1)
+		go func(s *Session) {
+			defer wg.Done()
+			for r := uint32(1); r <= rounds; r++ {
+				handleMsgSysOpenMutex(s, &mhfpacket.MsgSysOpenMutex{AckHandle: r, MutexName: "m"})
+				if err := waitMutexAck(s, r); err != nil {
+					t.Error(err)
+					return
+				}
+				handleMsgSysCloseMutex(s, &mhfpacket.MsgSysCloseMutex{AckHandle: 0, MutexName: "m"})
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	server.mutexLock.Lock()
+	defer server.mutexLock.Unlock()
+	if m := server.mutexes["m"]; m.owner != nil || len(m.waiters) != 0 {
+		t.Errorf("owner = %v, waiters = %d; want a free mutex", m.owner, len(m.waiters))
+	}
+}
+
+// waitMutexAck blocks until session receives a successful ack for
+// ackHandle, skipping close acks and ownership notifications.
+func waitMutexAck(session *Session, ackHandle uint32) error {
+	for p := range session.sendPackets {
+		if len(p.data) < 8 {
+			continue
+		}
+		if binary.BigEndian.Uint32(p.data[2:]) != ackHandle {
+			continue
+		}
+		if p.data[7] != 0 {
+			return fmt.Errorf("charID %d: open %d failed", session.charID, ackHandle)
+		}
+		return nil
+	}
+	return fmt.Errorf("charID %d: send queue closed", session.charID)
+}
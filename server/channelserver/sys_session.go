@@ -57,6 +57,8 @@ type Session struct {
 	token            string
 	kqf              []byte
 	kqfOverride      bool
+	questFile        string // Quest file last loaded through MsgSysGetFile
+	fortSortie       uint32 // Fort attack event of the quest being played, 0 when none
 
 	playtime     uint32
 	playtimeTime time.Time
@@ -180,7 +182,9 @@ func (s *Session) Start() {
 // QueueSend queues a packet (raw []byte) to be sent.
 func (s *Session) QueueSend(data []byte) {
 	if len(data) >= 2 {
-		s.logMessage(binary.BigEndian.Uint16(data[0:2]), data, "Server", s.Name)
+		opcode := binary.BigEndian.Uint16(data[0:2])
+		s.logMessage(opcode, data, "Server", s.Name)
+		s.server.metrics.packetSent(s.server, network.PacketID(opcode))
 	}
 	s.sendPackets <- packet{data, true}
 }
@@ -190,7 +194,9 @@ func (s *Session) QueueSendNonBlocking(data []byte) {
 	select {
 	case s.sendPackets <- packet{data, true}:
 		if len(data) >= 2 {
-			s.logMessage(binary.BigEndian.Uint16(data[0:2]), data, "Server", s.Name)
+			opcode := binary.BigEndian.Uint16(data[0:2])
+			s.logMessage(opcode, data, "Server", s.Name)
+			s.server.metrics.packetSent(s.server, network.PacketID(opcode))
 		}
 	default:
 		s.logger.Warn("Packet queue too full, dropping!")
@@ -296,11 +302,13 @@ func (s *Session) handlePacketGroup(pktGroup []byte) {
 	s.lastPacket = time.Now()
 	bf := byteframe.NewByteFrameFromBytes(pktGroup)
 	opcodeUint16 := bf.ReadUint16()
+	start := time.Now()
 	if len(bf.Data()) >= 6 {
-		s.ackStart[bf.ReadUint32()] = time.Now()
+		s.ackStart[bf.ReadUint32()] = start
 		_, _ = bf.Seek(2, io.SeekStart)
 	}
 	opcode := network.PacketID(opcodeUint16)
+	s.server.metrics.packetReceived(s.server, opcode)
 
 	// This shouldn't be needed, but it's better to recover and let the connection die than to panic the server.
 	defer func() {
@@ -350,6 +358,7 @@ func (s *Session) handlePacketGroup(pktGroup []byte) {
 		return
 	}
 	handler(s, mhfPkt)
+	s.server.metrics.handlerDone(s.server, opcode, time.Since(start))
 	// If there is more data on the stream that the .Parse method didn't read, then read another packet off it.
 	remainingData := bf.DataFromCurrent()
 	if len(remainingData) >= 2 {
@@ -65,6 +65,8 @@ type Object struct {
 	ownerCharID uint32
 	x, y, z     float32
 	rotation    float32
+	hidden      bool
+	binary      []byte // Last payload set with MSG_SYS_SET_OBJECT_BINARY.
 }
 
 // stageBinaryKey is a struct used as a map key for identifying a stage binary part.
@@ -80,7 +82,7 @@ type Stage struct {
 	// Stage ID string
 	id string
 
-	// Objects
+	// Map of object ID -> Object.
 	objects     map[uint32]*Object
 	objectIndex uint8
 
@@ -136,3 +138,26 @@ func (s *Stage) BroadcastMHF(pkt mhfpacket.MHFPacket, ignoredSession *Session) {
 		session.QueueSendNonBlocking(bf.Data())
 	}
 }
+
+// ownedObject returns the object with the given ID if charID owns it. The
+// caller must hold the stage lock.
+func (s *Stage) ownedObject(charID, objID uint32) *Object {
+	if object, ok := s.objects[objID]; ok && object.ownerCharID == charID {
+		return object
+	}
+	return nil
+}
+
+// removeObjectsOwnedBy deletes every object owned by charID and returns
+// them so the caller can broadcast the deletions once the stage lock is
+// released. The caller must hold the stage lock.
+func (s *Stage) removeObjectsOwnedBy(charID uint32) []*Object {
+	var removed []*Object
+	for id, object := range s.objects {
+		if object.ownerCharID == charID {
+			removed = append(removed, object)
+			delete(s.objects, id)
+		}
+	}
+	return removed
+}
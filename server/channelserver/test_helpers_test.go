@@ -42,17 +42,23 @@ func createMockServer() *Server {
 		erupeConfig: &cfg.Config{},
 		// stages is a StageMap (zero value is ready to use)
 		sessions:     make(map[net.Conn]*Session),
+		mutexes:      make(map[string]*Mutex),
 		handlerTable: buildHandlerTable(),
 		raviente: &Raviente{
 			register: make([]uint32, 30),
 			state:    make([]uint32, 30),
 			support:  make([]uint32, 30),
 		},
-		// divaRepo and tournamentRepo defaults prevent nil-deref in handler tests
-		// that don't need specific repo behaviour. Tests that need controlled data override them.
-		divaRepo:       &mockDivaRepo{},
-		tournamentRepo: &mockTournamentRepo{},
+		// divaRepo, tournamentRepo, seibattleRepo, guildRepo, conquestService and tournamentService defaults
+		// prevent nil-deref in handler tests that don't need specific repo behaviour. Tests that
+		// need controlled data override them.
+		divaRepo:        &mockDivaRepo{},
+		tournamentRepo:  &mockTournamentRepo{},
+		seibattleRepo:   &mockSeibattleRepo{},
+		guildRepo:       &mockGuildRepo{},
+		conquestService: NewConquestService(&mockConquestRepo{}, logger),
 	}
+	s.tournamentService = NewTournamentService(s.tournamentRepo, logger)
 	s.i18n = getLangStrings(s)
 	s.Registry = NewLocalChannelRegistry([]*Server{s})
 	// GuildService is wired lazily by tests that set repos then call ensureGuildService.
@@ -92,6 +98,21 @@ func ensureFestaService(s *Server) {
 	s.festaService = NewFestaService(s.festaRepo, s.logger)
 }
 
+// ensureConquestService wires the ConquestService from the server's current repos.
+func ensureConquestService(s *Server) {
+	s.conquestService = NewConquestService(s.conquestRepo, s.logger)
+}
+
+// ensureTournamentService wires the TournamentService from the server's current repos.
+func ensureTournamentService(s *Server) {
+	s.tournamentService = NewTournamentService(s.tournamentRepo, s.logger)
+}
+
+// ensureFortAttackService wires the FortAttackService from the server's current repos.
+func ensureFortAttackService(s *Server) {
+	s.fortAttackService = NewFortAttackService(s.fortAttackRepo, s.logger)
+}
+
 // createMockSession creates a minimal Session for testing.
 // Imported from v9.2.x-stable and adapted for main.
 func createMockSession(charID uint32, server *Server) *Session {
@@ -30,3 +30,19 @@ INSERT INTO diva_prizes (type, points_req, item_type, item_id, quantity, gr, rep
     ('guild',    70000000, 26, 0, 5, false, false),
     ('guild',   100000000, 26, 0, 5, false, false)
 ON CONFLICT DO NOTHING;
+
+-- Diva Defense default presents.
+-- Daily: personal rank brackets (rank_type=0), claimable once per day.
+-- Norma: personal point thresholds, claimable once per event.
+-- item_type=7 is a consumable item; the item IDs are ones the Diva shop
+-- (DivaShops.sql) sells. AcquireUdItem claims presents by item ID, so every
+-- present needs a real one.
+INSERT INTO diva_presents (list, rank_type, rank_from, rank_to, item_type, item_id, quantity, points_req) VALUES
+    ('daily', 0,    1,   10, 7, 15, 3,      0),
+    ('daily', 0,   11,  100, 7, 15, 2,      0),
+    ('daily', 0,  101, 1000, 7, 15, 1,      0),
+    ('norma', 0,    0,    0, 7,  1, 5,  10000),
+    ('norma', 0,    0,    0, 7,  6, 3,  50000),
+    ('norma', 0,    0,    0, 7,  9, 3, 100000),
+    ('norma', 0,    0,    0, 7, 14, 5, 500000)
+ON CONFLICT DO NOTHING;
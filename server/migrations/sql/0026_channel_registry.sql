@@ -0,0 +1,37 @@
+-- Shared state for the multi-process ChannelRegistry (Channel.Registry.Backend
+-- = "postgres"). Each Erupe process publishes a snapshot of its own sessions
+-- and stages here on a fixed interval and heartbeats in registry_instances;
+-- rows from instances whose heartbeat has gone stale are ignored by readers
+-- and reaped by the next publisher. The tables are UNLOGGED because their
+-- content is rebuilt from live server state after a crash.
+CREATE UNLOGGED TABLE IF NOT EXISTS registry_instances (
+    instance_id TEXT PRIMARY KEY,
+    heartbeat   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS registry_sessions (
+    instance_id  TEXT NOT NULL,
+    char_id      INTEGER NOT NULL,
+    name         TEXT NOT NULL DEFAULT '',
+    stage_id     TEXT NOT NULL DEFAULT '',
+    server_ip    TEXT NOT NULL,
+    server_port  INTEGER NOT NULL,
+    user_binary3 BYTEA,
+    PRIMARY KEY (instance_id, char_id)
+);
+
+CREATE UNLOGGED TABLE IF NOT EXISTS registry_stages (
+    instance_id    TEXT NOT NULL,
+    global_id      TEXT NOT NULL,
+    stage_id       TEXT NOT NULL,
+    server_ip      TEXT NOT NULL,
+    server_port    INTEGER NOT NULL,
+    client_count   INTEGER NOT NULL DEFAULT 0,
+    reserved       INTEGER NOT NULL DEFAULT 0,
+    quest_reserved INTEGER NOT NULL DEFAULT 0,
+    max_players    INTEGER NOT NULL DEFAULT 0,
+    bin_data0      BYTEA,
+    bin_data1      BYTEA,
+    bin_data3      BYTEA,
+    PRIMARY KEY (instance_id, server_port, stage_id)
+);
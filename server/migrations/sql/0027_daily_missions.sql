@@ -0,0 +1,12 @@
+-- Per-character daily mission progress. Mission definitions live in
+-- bin/daily_missions.json; one row is kept per character and mission, and
+-- `day` (JST midnight of the day the progress belongs to) lets the server
+-- treat rows from earlier days as reset without a cleanup job.
+CREATE TABLE IF NOT EXISTS daily_mission_progress (
+    character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+    mission_id   INTEGER NOT NULL,
+    progress     INTEGER NOT NULL DEFAULT 0,
+    claimed      BOOLEAN NOT NULL DEFAULT false,
+    day          TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (character_id, mission_id)
+);
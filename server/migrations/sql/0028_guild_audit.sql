@@ -0,0 +1,16 @@
+-- Administrative guild changes made by operators in game or through the
+-- admin API. Rows outlive the guild so a disbanded guild's history stays
+-- readable; actor_source is 'channel' (actor_id is a character ID) or 'api'
+-- (actor_id is a user ID).
+CREATE TABLE IF NOT EXISTS guild_audit (
+    id           SERIAL PRIMARY KEY,
+    guild_id     INTEGER NOT NULL,
+    actor_source TEXT NOT NULL,
+    actor_id     INTEGER NOT NULL,
+    action       TEXT NOT NULL,
+    old_value    TEXT NOT NULL DEFAULT '',
+    new_value    TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS guild_audit_guild_id_idx ON guild_audit (guild_id, created_at);
@@ -0,0 +1,32 @@
+-- Conquest War (Earth) state. conquest_schedule holds the single anchor row
+-- the three-week Conquest/Pallone/Tower rotation is computed from; it is
+-- seeded from EarthID/EarthStatus the first time the cycle runs, and
+-- deleting it reseeds from the config.
+CREATE TABLE IF NOT EXISTS conquest_schedule (
+    id         INTEGER PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    earth_id   INTEGER NOT NULL,
+    status     INTEGER NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Beat level per character and target monster for each Earth event. Rows of
+-- finished events are kept so earlier rankings stay readable.
+CREATE TABLE IF NOT EXISTS conquest_beat_levels (
+    character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+    earth_id     INTEGER NOT NULL,
+    monster_id   INTEGER NOT NULL,
+    level        INTEGER NOT NULL,
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (character_id, earth_id, monster_id)
+);
+
+CREATE INDEX IF NOT EXISTS conquest_beat_levels_ranking_idx
+    ON conquest_beat_levels (earth_id, monster_id, level DESC, updated_at);
+
+-- One row per Earth event whose ranking rewards have been distributed, so
+-- rewards are paid once however many channels notice the week rolling over.
+CREATE TABLE IF NOT EXISTS conquest_reward_payouts (
+    earth_id   INTEGER PRIMARY KEY,
+    recipients INTEGER NOT NULL DEFAULT 0,
+    paid_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
@@ -0,0 +1,28 @@
+-- Diva Defense present tables served by MSG_MHF_GET_UD_DAILY_PRESENT_LIST
+-- ('daily') and MSG_MHF_GET_UD_NORMA_PRESENT_LIST ('norma'). rank_type 0
+-- ranks by personal points and 1 by guild points; a rank_from of 0 means
+-- no rank requirement. points_req and bead_type only apply to norma rows.
+CREATE TABLE IF NOT EXISTS diva_presents (
+    id         SERIAL PRIMARY KEY,
+    list       VARCHAR(10) NOT NULL CHECK (list IN ('daily', 'norma')),
+    rank_type  INTEGER NOT NULL DEFAULT 0,
+    rank_from  INTEGER NOT NULL DEFAULT 0,
+    rank_to    INTEGER NOT NULL DEFAULT 0,
+    item_type  INTEGER NOT NULL,
+    item_id    INTEGER NOT NULL DEFAULT 0,
+    quantity   INTEGER NOT NULL,
+    points_req INTEGER NOT NULL DEFAULT 0,
+    bead_type  INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (list, rank_type, rank_from, rank_to, item_type, item_id, points_req)
+);
+
+-- Presents a character has acquired. period is JST midnight of the claim
+-- day for daily presents and the diva event's start for norma presents, so
+-- daily presents can be claimed again the next day.
+CREATE TABLE IF NOT EXISTS diva_present_claims (
+    character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+    present_id   INTEGER NOT NULL REFERENCES diva_presents(id) ON DELETE CASCADE,
+    period       TIMESTAMP WITH TIME ZONE NOT NULL,
+    claimed_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (character_id, present_id, period)
+);
@@ -0,0 +1,20 @@
+-- Failed password logins, shared by the sign server and the API. key is
+-- 'user:<lowercased username>' or 'ip:<address>'; failures starts over once
+-- last_failure is older than LoginProtection.ResetSeconds.
+CREATE TABLE IF NOT EXISTS login_failures (
+    key          TEXT PRIMARY KEY,
+    failures     INTEGER NOT NULL DEFAULT 0,
+    last_failure TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+-- Accounts created by AutoCreateAccount, counted per IP address for the
+-- LoginProtection.AutoCreatePerIPDaily cap.
+CREATE TABLE IF NOT EXISTS account_creations (
+    id         SERIAL PRIMARY KEY,
+    ip         TEXT NOT NULL,
+    user_id    INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS account_creations_ip_idx ON account_creations (ip, created_at);
@@ -0,0 +1,35 @@
+-- Raviente siege state. Each channel keeps at most one open siege (ended_at
+-- IS NULL); its register, state and support arrays are saved periodically so
+-- the siege survives restarts, and the API reads them from here.
+CREATE TABLE IF NOT EXISTS raviente_sieges (
+    id              SERIAL PRIMARY KEY,
+    server_id       INTEGER NOT NULL,
+    ravi_id         INTEGER NOT NULL,
+    register        BYTEA NOT NULL DEFAULT ''::bytea,
+    state           BYTEA NOT NULL DEFAULT ''::bytea,
+    support         BYTEA NOT NULL DEFAULT ''::bytea,
+    multiplier      REAL NOT NULL DEFAULT 0,
+    players         INTEGER NOT NULL DEFAULT 0,
+    start_requested BOOLEAN NOT NULL DEFAULT false,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    ended_at        TIMESTAMP WITH TIME ZONE,
+    -- Post-siege summary, written when the siege ends.
+    phase_reached   INTEGER,
+    damage          BIGINT,
+    participants    INTEGER
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS raviente_sieges_open_idx
+    ON raviente_sieges (server_id) WHERE ended_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS raviente_sieges_ended_idx
+    ON raviente_sieges (ended_at DESC);
+
+-- Every character that joined a siege, for reward summaries.
+CREATE TABLE IF NOT EXISTS raviente_participants (
+    siege_id     INTEGER NOT NULL REFERENCES raviente_sieges(id) ON DELETE CASCADE,
+    character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+    joined_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (siege_id, character_id)
+);
@@ -0,0 +1,43 @@
+-- Tournament administration: recurring schedules, per-tournament sub-events,
+-- result review and prize payouts.
+
+-- cycle_days > 0 re-schedules the tournament that many days after its start
+-- once it ends; cycled_from links each copy to the tournament it was rolled
+-- forward from, so only one copy is ever created however many channels
+-- notice the end. paid_at is set once the prizes have been paid.
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS cycle_days INTEGER NOT NULL DEFAULT 0 CHECK (cycle_days >= 0);
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS cycled_from INTEGER UNIQUE REFERENCES tournaments(id) ON DELETE SET NULL;
+ALTER TABLE tournaments ADD COLUMN IF NOT EXISTS paid_at TIMESTAMP WITH TIME ZONE;
+
+-- Sub-events with a tournament_id belong to that tournament only; a
+-- tournament without any of its own uses the shared rows (tournament_id
+-- NULL), which is what the bundled seed inserts. A run submitted sooner than
+-- min_clear_seconds after the same character's previous run of the event
+-- is flagged for review; 0 disables the check.
+ALTER TABLE tournament_sub_events ADD COLUMN IF NOT EXISTS tournament_id INTEGER REFERENCES tournaments(id) ON DELETE CASCADE;
+ALTER TABLE tournament_sub_events ADD COLUMN IF NOT EXISTS min_clear_seconds INTEGER NOT NULL DEFAULT 0 CHECK (min_clear_seconds >= 0);
+
+-- Results are 'ok' when recorded normally and 'flagged' when a check failed.
+-- Operators move flagged results to 'verified' or 'rejected'. Rejected
+-- results leave the leaderboard; only ok and verified results earn prizes.
+ALTER TABLE tournament_results ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'ok'
+    CHECK (status IN ('ok', 'flagged', 'verified', 'rejected'));
+ALTER TABLE tournament_results ADD COLUMN IF NOT EXISTS flag_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS tournament_results_tournament_idx
+    ON tournament_results (tournament_id, event_id, submitted_at);
+
+-- Prize brackets: every placing from place_from to place_to in a sub-event of
+-- cup_group gets the item as a distribution, and the placing's guild is
+-- credited souls toward the Mezeporta Festival (once per guild and event).
+CREATE TABLE IF NOT EXISTS tournament_prizes (
+    id            SERIAL PRIMARY KEY,
+    tournament_id INTEGER NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    cup_group     SMALLINT NOT NULL,
+    place_from    INTEGER NOT NULL CHECK (place_from >= 1),
+    place_to      INTEGER NOT NULL CHECK (place_to >= place_from),
+    item_type     SMALLINT NOT NULL DEFAULT 0,
+    item_id       INTEGER NOT NULL DEFAULT 0,
+    quantity      INTEGER NOT NULL DEFAULT 0,
+    souls         INTEGER NOT NULL DEFAULT 0 CHECK (souls >= 0)
+);
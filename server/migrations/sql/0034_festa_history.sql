@@ -0,0 +1,27 @@
+-- Mezeporta Festival history. A row is written when the soul race is judged
+-- and completed (archived_at set) when the festival closes out and its
+-- registrations, submissions and claimed prizes are cleared.
+CREATE TABLE IF NOT EXISTS festa_history (
+    id          SERIAL PRIMARY KEY,
+    event_id    INTEGER NOT NULL UNIQUE,
+    start_time  TIMESTAMP WITH TIME ZONE NOT NULL,
+    blue_souls  BIGINT NOT NULL DEFAULT 0,
+    red_souls   BIGINT NOT NULL DEFAULT 0,
+    winner      festival_color NOT NULL DEFAULT 'none',
+    judged_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    archived_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS festa_history_start_idx
+    ON festa_history (start_time DESC);
+
+-- Every registered guild's final souls and placing, copied at close-out.
+CREATE TABLE IF NOT EXISTS festa_history_guilds (
+    history_id INTEGER NOT NULL REFERENCES festa_history(id) ON DELETE CASCADE,
+    guild_id   INTEGER NOT NULL,
+    guild_name TEXT NOT NULL DEFAULT '',
+    team       festival_color NOT NULL,
+    souls      BIGINT NOT NULL DEFAULT 0,
+    place      INTEGER NOT NULL,
+    PRIMARY KEY (history_id, guild_id)
+);
@@ -0,0 +1,31 @@
+-- Interceptor's Base fort attack events. Rows are scheduled from the
+-- FortAttack config; the event type, quests and restrictions are copied so a
+-- config change does not alter an event already announced. Durability falls
+-- with failed sorties and the event ends early when it reaches zero.
+CREATE TABLE IF NOT EXISTS fort_attack_events (
+    id             SERIAL PRIMARY KEY,
+    schedule_index INTEGER NOT NULL,
+    event_type     INTEGER NOT NULL,
+    starts_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at        TIMESTAMP WITH TIME ZONE NOT NULL,
+    quest_file_ids INTEGER[] NOT NULL DEFAULT '{}',
+    min_hr         INTEGER NOT NULL DEFAULT 0,
+    max_sorties    INTEGER NOT NULL DEFAULT 0,
+    durability     INTEGER NOT NULL,
+    max_durability INTEGER NOT NULL,
+    ended_at       TIMESTAMP WITH TIME ZONE,
+    UNIQUE (schedule_index, starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS fort_attack_events_open_idx
+    ON fort_attack_events (ends_at) WHERE ended_at IS NULL;
+
+-- Sorties each character took on an event's quests, for restrictions and
+-- participation rewards.
+CREATE TABLE IF NOT EXISTS fort_attack_participants (
+    event_id     INTEGER NOT NULL REFERENCES fort_attack_events(id) ON DELETE CASCADE,
+    character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+    sorties      INTEGER NOT NULL DEFAULT 0,
+    clears       INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (event_id, character_id)
+);
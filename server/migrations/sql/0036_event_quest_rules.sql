@@ -0,0 +1,12 @@
+-- Scheduling rules for event quests, evaluated by the channel server's
+-- EventQuestScheduler on top of the active_days/inactive_days cycle.
+--   weekdays:        bitmask of days the quest is live on (bit 0 = Sunday); 0 or NULL is every day
+--   weekends_only:   only live on Saturday and Sunday
+--   available_from / available_until: optional date range
+--   exclusive_group: only one live quest per group at a time; the group
+--                    rotates daily through its eligible quests
+ALTER TABLE public.event_quests ADD COLUMN IF NOT EXISTS weekdays INTEGER;
+ALTER TABLE public.event_quests ADD COLUMN IF NOT EXISTS weekends_only BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE public.event_quests ADD COLUMN IF NOT EXISTS available_from TIMESTAMP WITH TIME ZONE;
+ALTER TABLE public.event_quests ADD COLUMN IF NOT EXISTS available_until TIMESTAMP WITH TIME ZONE;
+ALTER TABLE public.event_quests ADD COLUMN IF NOT EXISTS exclusive_group TEXT;
@@ -0,0 +1,20 @@
+-- Admin mail broadcasts. A broadcast mails its subject, body and attachments
+-- to every character matching its filter, in batches ordered by character ID.
+-- last_char_id is the batch cursor, so an interrupted broadcast resumes where
+-- it stopped, and the idempotency key stops a retried request from creating a
+-- second broadcast.
+CREATE TABLE IF NOT EXISTS mail_broadcasts (
+    id              SERIAL PRIMARY KEY,
+    idempotency_key TEXT NOT NULL UNIQUE,
+    sender_id       INTEGER NOT NULL REFERENCES characters(id),
+    subject         TEXT NOT NULL,
+    body            TEXT NOT NULL DEFAULT '',
+    filter          JSONB NOT NULL DEFAULT '{}',
+    items           JSONB NOT NULL DEFAULT '[]',
+    total           INTEGER NOT NULL DEFAULT 0,
+    sent            INTEGER NOT NULL DEFAULT 0,
+    last_char_id    INTEGER NOT NULL DEFAULT 0,
+    created_by      INTEGER,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    finished_at     TIMESTAMP WITH TIME ZONE
+);
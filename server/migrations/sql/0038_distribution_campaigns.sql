@@ -0,0 +1,9 @@
+-- Admin-managed distributions. starts_at opens a distribution's time window,
+-- which deadline already closes, and character_ids targets a list of
+-- characters where character_id could only target one. Distributions with
+-- neither are offered to everyone, as before.
+ALTER TABLE distribution ADD COLUMN IF NOT EXISTS starts_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE distribution ADD COLUMN IF NOT EXISTS character_ids INTEGER[];
+
+CREATE INDEX IF NOT EXISTS distributions_accepted_distribution_idx
+    ON distributions_accepted (distribution_id, character_id);
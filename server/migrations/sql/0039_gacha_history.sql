@@ -0,0 +1,24 @@
+-- Gacha play ledger. GachaService writes one row per normal, step-up, box or
+-- free roll: the roll's cost, where it was charged and the items won. Rows
+-- are kept when a gacha shop is deleted so refunds can still be looked into.
+CREATE TABLE IF NOT EXISTS gacha_history (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      INTEGER NOT NULL,
+    character_id INTEGER NOT NULL,
+    gacha_id     INTEGER NOT NULL,
+    gacha_name   TEXT NOT NULL DEFAULT '',
+    kind         TEXT NOT NULL,
+    roll_type    SMALLINT NOT NULL DEFAULT 0,
+    cost_type    SMALLINT NOT NULL DEFAULT 0,
+    cost         INTEGER NOT NULL DEFAULT 0,
+    currency     TEXT NOT NULL DEFAULT '',
+    items        JSONB NOT NULL DEFAULT '[]',
+    played_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS gacha_history_character_idx
+    ON gacha_history (character_id, id DESC);
+CREATE INDEX IF NOT EXISTS gacha_history_user_idx
+    ON gacha_history (user_id, id DESC);
+CREATE INDEX IF NOT EXISTS gacha_history_gacha_idx
+    ON gacha_history (gacha_id, id DESC);
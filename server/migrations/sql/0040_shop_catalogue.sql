@@ -0,0 +1,20 @@
+-- Shop catalogue editing. Items are retired instead of deleted so the
+-- purchase counts in shop_items_bought, which are keyed by shop_items.id and
+-- drive the per-character caps, stay attached to the row they were made on.
+ALTER TABLE public.shop_items
+    ADD COLUMN IF NOT EXISTS retired_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE public.fpoint_items
+    ADD COLUMN IF NOT EXISTS retired_at TIMESTAMP WITH TIME ZONE;
+
+-- Scheduled shop prices. Once effective_at has passed, the latest change
+-- replaces shop_items.cost for every read; nothing has to run at that time.
+CREATE TABLE IF NOT EXISTS shop_price_changes (
+    id           SERIAL PRIMARY KEY,
+    shop_item_id INTEGER NOT NULL REFERENCES public.shop_items (id) ON DELETE CASCADE,
+    cost         INTEGER NOT NULL CHECK (cost >= 0),
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS shop_price_changes_item_idx
+    ON shop_price_changes (shop_item_id, effective_at DESC);
@@ -0,0 +1,20 @@
+-- Seibattle (guild battle) results posted by MSG_MHF_POST_SEIBATTLE. Only
+-- the key (Unk0) and the u32 score (Unk2) are interpreted; the remaining
+-- fields are kept raw until captures identify them. GetSeibattle ranks
+-- guilds by their summed score.
+CREATE TABLE IF NOT EXISTS seibattle_results (
+    id           SERIAL PRIMARY KEY,
+    guild_id     INTEGER NOT NULL REFERENCES guilds(id) ON DELETE CASCADE,
+    character_id INTEGER NOT NULL REFERENCES characters(id) ON DELETE CASCADE,
+    key          SMALLINT NOT NULL,
+    score        BIGINT NOT NULL,
+    unk1         SMALLINT NOT NULL DEFAULT 0,
+    unk3         SMALLINT NOT NULL DEFAULT 0,
+    unk4         INTEGER NOT NULL DEFAULT 0,
+    unk5         INTEGER NOT NULL DEFAULT 0,
+    unk6         SMALLINT NOT NULL DEFAULT 0,
+    posted_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS seibattle_results_guild_idx ON seibattle_results (guild_id, posted_at);
+CREATE INDEX IF NOT EXISTS seibattle_results_posted_idx ON seibattle_results (posted_at);
@@ -0,0 +1,12 @@
+-- Records which login backend owns each account. Accounts created locally
+-- (registration, AutoCreateAccount) are 'local'; the webhook and ldap
+-- backends tag the accounts they provision on first login and only ever
+-- adopt their own, so an external login cannot take over a local account
+-- with the same username.
+--
+-- Accounts an external backend provisioned before this migration are
+-- marked 'local' here. Move them to their backend by hand, for example:
+--   UPDATE users SET auth_backend = 'ldap' WHERE username IN (...);
+
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS auth_backend TEXT NOT NULL DEFAULT 'local';
@@ -0,0 +1,15 @@
+-- min_clear_seconds never measured a clear time: the quest clear time is
+-- not known, so the check compares a submission with the character's
+-- previous one. Rename the column and the flag reason to say so.
+DO $$ BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'tournament_sub_events' AND column_name = 'min_clear_seconds'
+    ) THEN
+        ALTER TABLE tournament_sub_events RENAME COLUMN min_clear_seconds TO min_submit_interval_seconds;
+    END IF;
+END $$;
+
+UPDATE tournament_results
+SET flag_reason = replace(flag_reason, 'impossible_clear_time', 'submitted_too_soon')
+WHERE flag_reason LIKE '%impossible_clear_time%';
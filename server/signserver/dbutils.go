@@ -1,10 +1,11 @@
 package signserver
 
 import (
-	"database/sql"
+	"context"
 	"errors"
 	"erupe-ce/common/mhfcourse"
 	"erupe-ce/common/token"
+	"erupe-ce/server/auth"
 	"time"
 
 	"go.uber.org/zap"
@@ -162,26 +163,50 @@ func (s *Server) validateToken(tok string, tokenID uint32) bool {
 	return valid
 }
 
-func (s *Server) validateLogin(user string, pass string) (uint32, RespID) {
-	uid, passDB, err := s.userRepo.GetCredentials(user)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			s.logger.Info("User not found", zap.String("User", user))
-			if s.erupeConfig.AutoCreateAccount {
-				uid, err = s.registerDBAccount(user, pass)
-				if err == nil {
-					return uid, SIGN_SUCCESS
-				}
-				return 0, SIGN_EABORT
+// validateLogin checks a username and password from ip. Locked out
+// usernames get SIGN_ESUSPEND and locked out addresses SIGN_EILLEGAL without
+// the password being checked; failures count towards those lockouts.
+func (s *Server) validateLogin(user string, pass string, ip string) (uint32, RespID) {
+	if lock := s.loginGuard.Check(user, ip); lock.Locked() {
+		s.logger.Info("Login refused during lockout",
+			zap.String("User", user), zap.String("IP", ip), zap.Time("Until", lock.Until))
+		if lock.Scope == auth.ScopeIP {
+			return 0, SIGN_EILLEGAL
+		}
+		return 0, SIGN_ESUSPEND
+	}
+
+	uid, err := s.loginAuthenticator().Authenticate(context.Background(), user, pass)
+	switch {
+	case errors.Is(err, auth.ErrUnknownUser):
+		s.logger.Info("User not found", zap.String("User", user))
+		// Only the local backend owns its accounts; external backends
+		// provision theirs on a successful bind instead.
+		if s.erupeConfig.AutoCreateAccount && auth.IsLocal(s.loginAuthenticator()) {
+			if !s.loginGuard.AllowAccountCreation(ip) {
+				s.logger.Info("Account auto-creation limit reached", zap.String("User", user), zap.String("IP", ip))
+				return 0, SIGN_EAUTH
 			}
-			return 0, SIGN_EAUTH
+			uid, err = s.registerDBAccount(user, pass)
+			if err == nil {
+				s.loginGuard.AccountCreated(ip, uid)
+				return uid, SIGN_SUCCESS
+			}
+			return 0, SIGN_EABORT
 		}
-		return 0, SIGN_EABORT
-	}
-
-	if bcrypt.CompareHashAndPassword([]byte(passDB), []byte(pass)) != nil {
+		s.loginGuard.Failure(user, ip)
+		return 0, SIGN_EAUTH
+	case errors.Is(err, auth.ErrBadPassword):
+		s.loginGuard.Failure(user, ip)
 		return 0, SIGN_EPASS
+	case errors.Is(err, auth.ErrForeignAccount):
+		s.logger.Warn("External login matches an account it does not own", zap.String("User", user))
+		return 0, SIGN_EAUTH
+	case err != nil:
+		s.logger.Error("Authentication backend failed", zap.String("User", user), zap.Error(err))
+		return 0, SIGN_EABORT
 	}
+	s.loginGuard.Success(user)
 
 	bans, err := s.userRepo.CountPermanentBans(uid)
 	if err == nil && bans > 0 {
@@ -193,3 +218,21 @@ func (s *Server) validateLogin(user string, pass string) (uint32, RespID) {
 	}
 	return uid, SIGN_SUCCESS
 }
+
+// signCredentials adapts SignUserRepo to auth.CredentialStore.
+type signCredentials struct {
+	repo SignUserRepo
+}
+
+func (c signCredentials) GetCredentials(_ context.Context, username string) (uint32, string, error) {
+	return c.repo.GetCredentials(username)
+}
+
+// loginAuthenticator returns the configured Authenticator, defaulting to the local users
+// table.
+func (s *Server) loginAuthenticator() auth.Authenticator {
+	if s.authenticator == nil {
+		return auth.NewLocalAuthenticator(signCredentials{s.userRepo})
+	}
+	return s.authenticator
+}
@@ -2,10 +2,12 @@ package signserver
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
 	cfg "erupe-ce/config"
+	"erupe-ce/server/auth"
 
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
@@ -802,7 +804,7 @@ func TestValidateLoginSuccess(t *testing.T) {
 
 	// Note: bcrypt verification will fail with this test hash since it's not a real hash of "password123"
 	// The important thing is testing the flow, not actual bcrypt verification
-	_, resp := server.validateLogin("testuser", "password123")
+	_, resp := server.validateLogin("testuser", "password123", "127.0.0.1")
 	// This will return SIGN_EPASS since the hash doesn't match, which is expected behavior
 	if resp == SIGN_EABORT {
 		t.Error("validateLogin() should not abort for valid credentials lookup")
@@ -820,7 +822,7 @@ func TestValidateLoginUserNotFound(t *testing.T) {
 		userRepo:    userRepo,
 	}
 
-	_, resp := server.validateLogin("unknown", "password")
+	_, resp := server.validateLogin("unknown", "password", "127.0.0.1")
 	if resp != SIGN_EAUTH {
 		t.Errorf("validateLogin() for unknown user = %d, want SIGN_EAUTH(%d)", resp, SIGN_EAUTH)
 	}
@@ -840,7 +842,7 @@ func TestValidateLoginAutoCreate(t *testing.T) {
 		userRepo: userRepo,
 	}
 
-	uid, resp := server.validateLogin("newuser", "password")
+	uid, resp := server.validateLogin("newuser", "password", "127.0.0.1")
 	if resp != SIGN_SUCCESS {
 		t.Errorf("validateLogin() with auto-create = %d, want SIGN_SUCCESS(%d)", resp, SIGN_SUCCESS)
 	}
@@ -860,7 +862,7 @@ func TestValidateLoginDBError(t *testing.T) {
 		userRepo:    userRepo,
 	}
 
-	_, resp := server.validateLogin("testuser", "password")
+	_, resp := server.validateLogin("testuser", "password", "127.0.0.1")
 	if resp != SIGN_EABORT {
 		t.Errorf("validateLogin() on DB error = %d, want SIGN_EABORT(%d)", resp, SIGN_EABORT)
 	}
@@ -1019,7 +1021,7 @@ func TestValidateLogin_CorrectPassword(t *testing.T) {
 		userRepo:    userRepo,
 	}
 
-	uid, resp := server.validateLogin("testuser", password)
+	uid, resp := server.validateLogin("testuser", password, "127.0.0.1")
 	if resp != SIGN_SUCCESS {
 		t.Errorf("validateLogin() correct password = %d, want SIGN_SUCCESS(%d)", resp, SIGN_SUCCESS)
 	}
@@ -1047,7 +1049,7 @@ func TestValidateLogin_PermanentBan(t *testing.T) {
 		userRepo:    userRepo,
 	}
 
-	uid, resp := server.validateLogin("banned", password)
+	uid, resp := server.validateLogin("banned", password, "127.0.0.1")
 	if resp != SIGN_EELIMINATE {
 		t.Errorf("validateLogin() permanent ban = %d, want SIGN_EELIMINATE(%d)", resp, SIGN_EELIMINATE)
 	}
@@ -1075,7 +1077,7 @@ func TestValidateLogin_ActiveBan(t *testing.T) {
 		userRepo:    userRepo,
 	}
 
-	_, resp := server.validateLogin("suspended", password)
+	_, resp := server.validateLogin("suspended", password, "127.0.0.1")
 	if resp != SIGN_ESUSPEND {
 		t.Errorf("validateLogin() active ban = %d, want SIGN_ESUSPEND(%d)", resp, SIGN_ESUSPEND)
 	}
@@ -1095,7 +1097,7 @@ func TestValidateLogin_AutoCreateError(t *testing.T) {
 		userRepo: userRepo,
 	}
 
-	_, resp := server.validateLogin("newuser", "password")
+	_, resp := server.validateLogin("newuser", "password", "127.0.0.1")
 	if resp != SIGN_EABORT {
 		t.Errorf("validateLogin() auto-create error = %d, want SIGN_EABORT(%d)", resp, SIGN_EABORT)
 	}
@@ -1118,3 +1120,112 @@ func TestGetGuildmatesForCharactersError(t *testing.T) {
 		t.Errorf("getGuildmatesForCharacters() on error = %d, want 0", len(guildmates))
 	}
 }
+
+var testLoginProtection = cfg.LoginProtectionOptions{
+	Enabled:              true,
+	MaxFailures:          3,
+	MaxFailuresPerIP:     10,
+	LockoutSeconds:       60,
+	MaxLockoutSeconds:    600,
+	ResetSeconds:         600,
+	AutoCreatePerIPDaily: 1,
+}
+
+func TestValidateLogin_LockoutAfterFailures(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("right"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal("failed to hash password:", err)
+	}
+	server := &Server{
+		logger:      zap.NewNop(),
+		erupeConfig: &cfg.Config{},
+		userRepo:    &mockSignUserRepo{credUID: 1, credPassword: string(hash)},
+		loginGuard:  auth.NewGuard(newMockLoginStore(), testLoginProtection, zap.NewNop()),
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, resp := server.validateLogin("testuser", "wrong", "10.0.0.1"); resp != SIGN_EPASS {
+			t.Fatalf("failure %d = %d, want SIGN_EPASS(%d)", i+1, resp, SIGN_EPASS)
+		}
+	}
+	// The right password is refused while the username is locked out.
+	if _, resp := server.validateLogin("testuser", "right", "10.0.0.2"); resp != SIGN_ESUSPEND {
+		t.Errorf("login during lockout = %d, want SIGN_ESUSPEND(%d)", resp, SIGN_ESUSPEND)
+	}
+}
+
+func TestValidateLogin_IPLockout(t *testing.T) {
+	opts := testLoginProtection
+	opts.MaxFailuresPerIP = 2
+	server := &Server{
+		logger:      zap.NewNop(),
+		erupeConfig: &cfg.Config{},
+		userRepo:    &mockSignUserRepo{credErr: sql.ErrNoRows},
+		loginGuard:  auth.NewGuard(newMockLoginStore(), opts, zap.NewNop()),
+	}
+
+	_, _ = server.validateLogin("first", "x", "10.0.0.1")
+	_, _ = server.validateLogin("second", "x", "10.0.0.1")
+	if _, resp := server.validateLogin("third", "x", "10.0.0.1"); resp != SIGN_EILLEGAL {
+		t.Errorf("login from a locked out address = %d, want SIGN_EILLEGAL(%d)", resp, SIGN_EILLEGAL)
+	}
+	if _, resp := server.validateLogin("third", "x", "10.0.0.2"); resp != SIGN_EAUTH {
+		t.Errorf("login from another address = %d, want SIGN_EAUTH(%d)", resp, SIGN_EAUTH)
+	}
+}
+
+func TestValidateLogin_AutoCreateCap(t *testing.T) {
+	userRepo := &mockSignUserRepo{credErr: sql.ErrNoRows, registerUID: 42}
+	server := &Server{
+		logger:      zap.NewNop(),
+		erupeConfig: &cfg.Config{AutoCreateAccount: true},
+		userRepo:    userRepo,
+		loginGuard:  auth.NewGuard(newMockLoginStore(), testLoginProtection, zap.NewNop()),
+	}
+
+	if _, resp := server.validateLogin("first", "pw", "10.0.0.1"); resp != SIGN_SUCCESS {
+		t.Fatalf("first auto-create = %d, want SIGN_SUCCESS(%d)", resp, SIGN_SUCCESS)
+	}
+	userRepo.registered = false
+	if _, resp := server.validateLogin("second", "pw", "10.0.0.1"); resp != SIGN_EAUTH {
+		t.Errorf("auto-create over the cap = %d, want SIGN_EAUTH(%d)", resp, SIGN_EAUTH)
+	}
+	if userRepo.registered {
+		t.Error("account registered over the cap")
+	}
+}
+
+func TestValidateLogin_ExternalAuthenticator(t *testing.T) {
+	tests := []struct {
+		name     string
+		authErr  error
+		wantResp RespID
+	}{
+		{"accepted", nil, SIGN_SUCCESS},
+		{"wrong password", auth.ErrBadPassword, SIGN_EPASS},
+		{"unknown user is not auto-created", auth.ErrUnknownUser, SIGN_EAUTH},
+		{"account owned by another backend", auth.ErrForeignAccount, SIGN_EAUTH},
+		{"backend down", errors.New("connection refused"), SIGN_EABORT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &mockSignUserRepo{registerUID: 42}
+			server := &Server{
+				logger:        zap.NewNop(),
+				erupeConfig:   &cfg.Config{AutoCreateAccount: true},
+				userRepo:      userRepo,
+				authenticator: &mockAuthenticator{uid: 9, err: tt.authErr},
+			}
+			uid, resp := server.validateLogin("forumuser", "pw", "127.0.0.1")
+			if resp != tt.wantResp {
+				t.Errorf("validateLogin() = %d, want %d", resp, tt.wantResp)
+			}
+			if tt.authErr == nil && uid != 9 {
+				t.Errorf("uid = %d, want 9", uid)
+			}
+			if userRepo.registered {
+				t.Error("external login registered a local account")
+			}
+		})
+	}
+}
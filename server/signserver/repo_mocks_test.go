@@ -1,6 +1,7 @@
 package signserver
 
 import (
+	"context"
 	"errors"
 	"time"
 )
@@ -252,3 +253,49 @@ func (m *mockSignSessionRepo) Validate(token string, tokenID uint32) (bool, erro
 func (m *mockSignSessionRepo) GetPSNIDByToken(token string) (string, error) {
 	return m.psnIDByToken, m.psnIDByTokenErr
 }
+
+// --- mockLoginStore ---
+
+// mockLoginStore is an in-memory auth.LoginStore.
+type mockLoginStore struct {
+	failures  map[string]int
+	locked    map[string]time.Time
+	creations map[string]int
+}
+
+func newMockLoginStore() *mockLoginStore {
+	return &mockLoginStore{failures: map[string]int{}, locked: map[string]time.Time{}, creations: map[string]int{}}
+}
+
+func (m *mockLoginStore) LockedUntil(key string) (time.Time, error) { return m.locked[key], nil }
+func (m *mockLoginStore) AddFailure(key string, _, _ time.Time) (int, error) {
+	m.failures[key]++
+	return m.failures[key], nil
+}
+func (m *mockLoginStore) SetLockedUntil(key string, until time.Time) error {
+	m.locked[key] = until
+	return nil
+}
+func (m *mockLoginStore) ClearFailures(key string) error {
+	delete(m.failures, key)
+	return nil
+}
+func (m *mockLoginStore) CountAccountCreations(ip string, _ time.Time) (int, error) {
+	return m.creations[ip], nil
+}
+func (m *mockLoginStore) AddAccountCreation(ip string, _ uint32, _ time.Time) error {
+	m.creations[ip]++
+	return nil
+}
+
+// --- mockAuthenticator ---
+
+// mockAuthenticator is an auth.Authenticator returning a fixed result.
+type mockAuthenticator struct {
+	uid uint32
+	err error
+}
+
+func (m *mockAuthenticator) Authenticate(context.Context, string, string) (uint32, error) {
+	return m.uid, m.err
+}
@@ -10,6 +10,7 @@ import (
 
 	"erupe-ce/common/byteframe"
 	"erupe-ce/network"
+	"erupe-ce/server/auth"
 
 	"go.uber.org/zap"
 )
@@ -97,7 +98,7 @@ func (s *Session) authenticate(username string, password string) {
 		newCharaReq = true
 	}
 	bf := byteframe.NewByteFrame()
-	uid, resp := s.server.validateLogin(username, password)
+	uid, resp := s.server.validateLogin(username, password, s.remoteIP())
 	switch resp {
 	case SIGN_SUCCESS:
 		if newCharaReq {
@@ -159,7 +160,7 @@ func (s *Session) handlePSNLink(bf *byteframe.ByteFrame) {
 	credStr := stringsupport.SJISToUTF8Lossy(bf.ReadNullTerminatedBytes())
 	credentials := strings.Split(credStr, "\n")
 	tok := string(bf.ReadNullTerminatedBytes())
-	uid, resp := s.server.validateLogin(credentials[0], credentials[1])
+	uid, resp := s.server.validateLogin(credentials[0], credentials[1], s.remoteIP())
 	if resp == SIGN_SUCCESS && uid > 0 {
 		psn, err := s.server.sessionRepo.GetPSNIDByToken(tok)
 		if err != nil {
@@ -202,6 +203,14 @@ func (s *Session) handleDSGN(bf *byteframe.ByteFrame) {
 	s.authenticate(user, pass)
 }
 
+// remoteIP returns the client's address for login protection.
+func (s *Session) remoteIP() string {
+	if s.rawConn == nil {
+		return ""
+	}
+	return auth.RemoteIP(s.rawConn.RemoteAddr().String())
+}
+
 func (s *Session) sendCode(id RespID) {
 	_ = s.cryptConn.SendPacket([]byte{byte(id)})
 }
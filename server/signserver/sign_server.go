@@ -8,6 +8,7 @@ import (
 
 	cfg "erupe-ce/config"
 	"erupe-ce/network"
+	"erupe-ce/server/auth"
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 )
@@ -27,6 +28,8 @@ type Server struct {
 	userRepo       SignUserRepo
 	charRepo       SignCharacterRepo
 	sessionRepo    SignSessionRepo
+	loginGuard     *auth.Guard
+	authenticator  auth.Authenticator
 	listener       net.Listener
 	isShuttingDown bool
 }
@@ -41,6 +44,12 @@ func NewServer(config *Config) *Server {
 		s.userRepo = NewSignUserRepository(config.DB)
 		s.charRepo = NewSignCharacterRepository(config.DB)
 		s.sessionRepo = NewSignSessionRepository(config.DB)
+		s.loginGuard = auth.NewGuard(auth.NewLoginRepository(config.DB), config.ErupeConfig.LoginProtection, config.Logger)
+		var err error
+		s.authenticator, err = auth.New(config.ErupeConfig.Authentication, signCredentials{s.userRepo}, auth.NewUserRepository(config.DB))
+		if err != nil {
+			config.Logger.Error("Invalid authentication config, refusing all logins", zap.Error(err))
+		}
 	}
 	return s
 }